@@ -1888,9 +1888,13 @@ func RegisterFilterAPI(stack *node.Node, backend ethapi.Backend, ethcfg *ethconf
 	filterSystem := filters.NewFilterSystem(backend, filters.Config{
 		LogCacheSize: ethcfg.FilterLogCacheSize,
 	})
+	filterAPI := filters.NewFilterAPI(filterSystem, false) // libevm
 	stack.RegisterAPIs([]rpc.API{{
 		Namespace: "eth",
-		Service:   filters.NewFilterAPI(filterSystem, false),
+		Service:   filterAPI, // libevm
+	}, {
+		Namespace: filters.SubscriptionStatusNamespace,         // libevm
+		Service:   filters.NewSubscriptionStatusAPI(filterAPI), // libevm
 	}})
 	return filterSystem
 }
@@ -2155,6 +2159,10 @@ func MakeTrieDatabase(ctx *cli.Context, disk ethdb.Database, preimage bool, read
 	if err != nil {
 		Fatalf("%v", err)
 	}
+	if ctor, ok := triedb.RegisteredScheme(scheme); ok { // libevm
+		config.DBOverride = ctor
+		return triedb.NewDatabase(disk, config)
+	}
 	if scheme == rawdb.HashScheme {
 		// Read-only mode is not implemented in hash mode,
 		// ignore the parameter silently. TODO(rjl493456442)
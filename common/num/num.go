@@ -0,0 +1,159 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package num provides checked conversions between, and overflow-aware
+// arithmetic on, [big.Int], [uint256.Int], and uint64 values, as commonly mixed
+// when computing fees, balances, and gas.
+package num
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/holiman/uint256"
+)
+
+// U256FromBig converts `b` to a [uint256.Int], reporting whether it is out of
+// range. Unlike [uint256.FromBig], negative values are reported as overflowing
+// instead of being silently converted to their two's complement, and a nil `b`
+// is treated as zero.
+func U256FromBig(b *big.Int) (_ *uint256.Int, overflow bool) {
+	if b == nil {
+		return new(uint256.Int), false
+	}
+	if b.Sign() < 0 {
+		return new(uint256.Int), true
+	}
+	return uint256.FromBig(b)
+}
+
+// MustU256FromBig is equivalent to [U256FromBig] but panics on overflow.
+func MustU256FromBig(b *big.Int) *uint256.Int {
+	u, overflow := U256FromBig(b)
+	if overflow {
+		panic(fmt.Sprintf("%v out of uint256 range", b))
+	}
+	return u
+}
+
+// Uint64FromBig converts `b` to a uint64, reporting whether it is out of range.
+// A nil `b` is treated as zero.
+func Uint64FromBig(b *big.Int) (_ uint64, overflow bool) {
+	if b == nil {
+		return 0, false
+	}
+	if !b.IsUint64() {
+		return 0, true
+	}
+	return b.Uint64(), false
+}
+
+// MustFitUint64 is equivalent to [Uint64FromBig] but panics on overflow.
+func MustFitUint64(b *big.Int) uint64 {
+	u, overflow := Uint64FromBig(b)
+	if overflow {
+		panic(fmt.Sprintf("%v out of uint64 range", b))
+	}
+	return u
+}
+
+// Uint64FromU256 converts `u` to a uint64, reporting whether it is out of
+// range. A nil `u` is treated as zero.
+func Uint64FromU256(u *uint256.Int) (_ uint64, overflow bool) {
+	if u == nil {
+		return 0, false
+	}
+	if !u.IsUint64() {
+		return 0, true
+	}
+	return u.Uint64(), false
+}
+
+// SaturatingAdd returns x+y, or [math.MaxUint64] on overflow.
+func SaturatingAdd(x, y uint64) uint64 {
+	sum, carry := bits.Add64(x, y, 0)
+	if carry != 0 {
+		return ^uint64(0)
+	}
+	return sum
+}
+
+// SaturatingSub returns x-y, or 0 on underflow.
+func SaturatingSub(x, y uint64) uint64 {
+	if y > x {
+		return 0
+	}
+	return x - y
+}
+
+// SaturatingMul returns x*y, or [math.MaxUint64] on overflow.
+func SaturatingMul(x, y uint64) uint64 {
+	hi, lo := bits.Mul64(x, y)
+	if hi != 0 {
+		return ^uint64(0)
+	}
+	return lo
+}
+
+// AddU256 returns x+y as a new [uint256.Int], reporting overflow.
+func AddU256(x, y *uint256.Int) (*uint256.Int, bool) {
+	return new(uint256.Int).AddOverflow(x, y)
+}
+
+// SubU256 returns x-y as a new [uint256.Int], reporting underflow.
+func SubU256(x, y *uint256.Int) (*uint256.Int, bool) {
+	return new(uint256.Int).SubOverflow(x, y)
+}
+
+// MulU256 returns x*y as a new [uint256.Int], reporting overflow.
+func MulU256(x, y *uint256.Int) (*uint256.Int, bool) {
+	return new(uint256.Int).MulOverflow(x, y)
+}
+
+// MulDiv returns ⌊x*y/d⌋, computed without intermediate overflow. It reports
+// overflow if `d` is zero or the result doesn't fit in a uint64.
+func MulDiv(x, y, d uint64) (_ uint64, overflow bool) {
+	if d == 0 {
+		return 0, true
+	}
+	hi, lo := bits.Mul64(x, y)
+	if hi >= d {
+		return 0, true
+	}
+	quo, _ := bits.Div64(hi, lo, d)
+	return quo, false
+}
+
+// MulDivU256 is the [uint256.Int] equivalent of [MulDiv], returning a new
+// value.
+func MulDivU256(x, y, d *uint256.Int) (*uint256.Int, bool) {
+	if d.IsZero() {
+		return new(uint256.Int), true
+	}
+	return new(uint256.Int).MulDivOverflow(x, y, d)
+}
+
+// Percent returns ⌊x*pct/100⌋; see [MulDiv] re overflow.
+func Percent(x, pct uint64) (uint64, bool) {
+	return MulDiv(x, pct, 100)
+}
+
+// PercentU256 is the [uint256.Int] equivalent of [Percent], returning a new
+// value.
+func PercentU256(x *uint256.Int, pct uint64) (*uint256.Int, bool) {
+	return MulDivU256(x, uint256.NewInt(pct), uint256.NewInt(100))
+}
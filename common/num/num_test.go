@@ -0,0 +1,170 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package num
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversions(t *testing.T) {
+	maxU256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	tests := []struct {
+		name           string
+		in             *big.Int
+		wantU256       *uint256.Int
+		wantU256Over   bool
+		wantUint64     uint64
+		wantUint64Over bool
+	}{
+		{
+			name:     "nil",
+			wantU256: new(uint256.Int),
+		},
+		{
+			name:       "small",
+			in:         big.NewInt(42),
+			wantU256:   uint256.NewInt(42),
+			wantUint64: 42,
+		},
+		{
+			name:       "max_uint64",
+			in:         new(big.Int).SetUint64(math.MaxUint64),
+			wantU256:   uint256.NewInt(math.MaxUint64),
+			wantUint64: math.MaxUint64,
+		},
+		{
+			name:           "max_uint256",
+			in:             maxU256,
+			wantU256:       new(uint256.Int).SetAllOne(),
+			wantUint64Over: true,
+		},
+		{
+			name:           "over_uint256",
+			in:             new(big.Int).Add(maxU256, big.NewInt(1)),
+			wantU256Over:   true,
+			wantUint64Over: true,
+		},
+		{
+			name:           "negative",
+			in:             big.NewInt(-1),
+			wantU256:       new(uint256.Int),
+			wantU256Over:   true,
+			wantUint64Over: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, over := U256FromBig(tt.in)
+			require.Equal(t, tt.wantU256Over, over, "U256FromBig() overflow")
+			if !over {
+				assert.Equal(t, tt.wantU256, u, "U256FromBig()")
+				assert.Equal(t, u, MustU256FromBig(tt.in), "MustU256FromBig()")
+
+				got, over := Uint64FromU256(u)
+				assert.Equal(t, tt.wantUint64Over, over, "Uint64FromU256() overflow")
+				assert.Equal(t, tt.wantUint64, got, "Uint64FromU256()")
+			} else {
+				assert.Panics(t, func() { MustU256FromBig(tt.in) }, "MustU256FromBig()")
+			}
+
+			got, over := Uint64FromBig(tt.in)
+			require.Equal(t, tt.wantUint64Over, over, "Uint64FromBig() overflow")
+			if !over {
+				assert.Equal(t, tt.wantUint64, got, "Uint64FromBig()")
+				assert.Equal(t, got, MustFitUint64(tt.in), "MustFitUint64()")
+			} else {
+				assert.Panics(t, func() { MustFitUint64(tt.in) }, "MustFitUint64()")
+			}
+		})
+	}
+}
+
+func TestSaturating(t *testing.T) {
+	const maxU = math.MaxUint64
+
+	assert.Equal(t, uint64(3), SaturatingAdd(1, 2))
+	assert.Equal(t, uint64(maxU), SaturatingAdd(maxU, 1))
+	assert.Equal(t, uint64(1), SaturatingSub(3, 2))
+	assert.Equal(t, uint64(0), SaturatingSub(2, 3))
+	assert.Equal(t, uint64(6), SaturatingMul(2, 3))
+	assert.Equal(t, uint64(maxU), SaturatingMul(maxU/2+1, 2))
+}
+
+func TestMulDiv(t *testing.T) {
+	const maxU = math.MaxUint64
+
+	tests := []struct {
+		x, y, d  uint64
+		want     uint64
+		overflow bool
+	}{
+		{x: 10, y: 3, d: 4, want: 7},
+		{x: maxU, y: maxU, d: maxU, want: maxU},
+		{x: maxU, y: 2, d: 4, want: maxU / 2},
+		{x: maxU, y: 2, d: 1, overflow: true},
+		{x: 1, y: 1, d: 0, overflow: true},
+	}
+
+	for _, tt := range tests {
+		got, overflow := MulDiv(tt.x, tt.y, tt.d)
+		assert.Equalf(t, tt.overflow, overflow, "MulDiv(%d, %d, %d) overflow", tt.x, tt.y, tt.d)
+		assert.Equalf(t, tt.want, got, "MulDiv(%d, %d, %d)", tt.x, tt.y, tt.d)
+
+		gotU, overflowU := MulDivU256(uint256.NewInt(tt.x), uint256.NewInt(tt.y), uint256.NewInt(tt.d))
+		if tt.d == 0 {
+			assert.Truef(t, overflowU, "MulDivU256(%d, %d, %d) overflow", tt.x, tt.y, tt.d)
+			continue
+		}
+		// The uint256 equivalent only overflows beyond 256 bits.
+		assert.Falsef(t, overflowU, "MulDivU256(%d, %d, %d) overflow", tt.x, tt.y, tt.d)
+		if !tt.overflow {
+			assert.Equalf(t, uint256.NewInt(tt.want), gotU, "MulDivU256(%d, %d, %d)", tt.x, tt.y, tt.d)
+		}
+	}
+
+	got, overflow := Percent(250, 40)
+	assert.False(t, overflow, "Percent() overflow")
+	assert.Equal(t, uint64(100), got, "Percent()")
+
+	gotU, overflow := PercentU256(uint256.NewInt(250), 40)
+	assert.False(t, overflow, "PercentU256() overflow")
+	assert.Equal(t, uint256.NewInt(100), gotU, "PercentU256()")
+}
+
+func TestU256Arithmetic(t *testing.T) {
+	maxU := new(uint256.Int).SetAllOne()
+	one := uint256.NewInt(1)
+
+	sum, overflow := AddU256(one, one)
+	assert.False(t, overflow, "AddU256(1, 1) overflow")
+	assert.Equal(t, uint256.NewInt(2), sum, "AddU256(1, 1)")
+	_, overflow = AddU256(maxU, one)
+	assert.True(t, overflow, "AddU256(max, 1) overflow")
+
+	_, overflow = SubU256(new(uint256.Int), one)
+	assert.True(t, overflow, "SubU256(0, 1) overflow")
+	_, overflow = MulU256(maxU, uint256.NewInt(2))
+	assert.True(t, overflow, "MulU256(max, 2) overflow")
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+)
+
+// Errors returned by the two-phase block lifecycle; see
+// [BlockChain.VerifyAndStage].
+var (
+	ErrBlockNotStaged       = errors.New("block not staged")
+	ErrStagedParentUnknown  = errors.New("parent neither last accepted nor staged")
+	ErrAcceptParentNotFinal = errors.New("parent of accepted block is not the last accepted block")
+)
+
+// blockLifecycle tracks blocks that have been verified and staged but neither
+// accepted nor rejected.
+type blockLifecycle struct {
+	mu     sync.Mutex
+	staged map[common.Hash]*types.Block
+}
+
+// VerifyAndStage verifies and executes the block, persisting it along with its
+// receipts and post-execution state, but without making it canonical. This
+// mirrors the Verify step of Snow consensus, after which exactly one of
+// [BlockChain.Accept] or [BlockChain.Reject] is expected to be called.
+//
+// The parent of the block MUST be either the current head, which is the last
+// accepted block, or another staged block. Staging an already-staged block is
+// a no-op.
+//
+// The lifecycle methods SHOULD NOT be mixed with other means of modifying the
+// canonical chain, such as [BlockChain.InsertChain].
+func (bc *BlockChain) VerifyAndStage(block *types.Block) error {
+	l := &bc.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	hash := block.Hash()
+	if _, ok := l.staged[hash]; ok {
+		return nil
+	}
+	if parent := block.ParentHash(); parent != bc.CurrentBlock().Hash() {
+		if _, ok := l.staged[parent]; !ok {
+			return fmt.Errorf("%w: block %d (%v) with parent %v", ErrStagedParentUnknown, block.NumberU64(), hash, parent)
+		}
+	}
+	if err := bc.InsertBlockWithoutSetHead(block); err != nil {
+		return err
+	}
+	if l.staged == nil {
+		l.staged = make(map[common.Hash]*types.Block)
+	}
+	l.staged[hash] = block
+	return nil
+}
+
+// Accept makes the staged block canonical, writing transaction indices and
+// emitting the same events as any other new head. Its parent MUST be the last
+// accepted block; i.e. blocks are accepted in order.
+//
+// Staged blocks that conflict with the accepted one, such as its siblings, are
+// not implicitly rejected, and can no longer be accepted.
+func (bc *BlockChain) Accept(hash common.Hash) error {
+	l := &bc.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	block, ok := l.staged[hash]
+	if !ok {
+		return fmt.Errorf("%w: accepting %v", ErrBlockNotStaged, hash)
+	}
+	if head := bc.CurrentBlock().Hash(); block.ParentHash() != head {
+		return fmt.Errorf("%w: block %v has parent %v; last accepted %v", ErrAcceptParentNotFinal, hash, block.ParentHash(), head)
+	}
+	if _, err := bc.SetCanonical(block); err != nil {
+		return err
+	}
+	delete(l.staged, hash)
+	return nil
+}
+
+// Reject discards the staged block, along with all of its staged descendants,
+// which can no longer be accepted. The block, its receipts, and its state
+// remain in the database as a non-canonical side chain, subject to the usual
+// state-retention limits, exactly as for any other side-chain block.
+func (bc *BlockChain) Reject(hash common.Hash) error {
+	l := &bc.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.staged[hash]; !ok {
+		return fmt.Errorf("%w: rejecting %v", ErrBlockNotStaged, hash)
+	}
+	queue := []common.Hash{hash}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		delete(l.staged, h)
+		for child, b := range l.staged {
+			if b.ParentHash() == h {
+				queue = append(queue, child)
+			}
+		}
+	}
+	return nil
+}
+
+// IsStaged reports whether the block was staged by [BlockChain.VerifyAndStage]
+// and has been neither accepted nor rejected.
+func (bc *BlockChain) IsStaged(hash common.Hash) bool {
+	l := &bc.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.staged[hash]
+	return ok
+}
+
+// SetFreezeBoundary allows the consensus engine to drive migration of blocks
+// from the key-value store to the ancient store, such that blocks are frozen
+// once final instead of after [params.FullImmutabilityThreshold] blocks. All
+// canonical blocks numbered up to and including `number` MAY thereafter be
+// frozen by the background freezer. See [rawdb.SetFreezeBoundary].
+//
+// It is an error for `number` to exceed the current head, which is the last
+// accepted block if using [BlockChain.Accept], or to be lower than a
+// previously set boundary, including one set before a restart. Staged blocks
+// are never above the boundary and are therefore unaffected. The transaction
+// indexer reads from both stores, so transaction lookups are unaffected.
+func (bc *BlockChain) SetFreezeBoundary(number uint64) error {
+	if head := bc.CurrentBlock().Number.Uint64(); number > head {
+		return fmt.Errorf("freeze boundary %d beyond head block %d", number, head)
+	}
+	return rawdb.SetFreezeBoundary(bc.db, number)
+}
+
+// loadRegisteredSnapshotTree constructs the [state.SnapshotTree] registered
+// with [state.RegisterSnapshotTreeConstructor], if any, which then replaces
+// the standard snapshot tree.
+func (bc *BlockChain) loadRegisteredSnapshotTree() error {
+	ctor, ok := state.RegisteredSnapshotTreeConstructor()
+	if !ok {
+		return nil
+	}
+	snaps, err := ctor(bc.db, bc.triedb, bc.CurrentBlock().Root)
+	if err != nil {
+		return fmt.Errorf("constructing registered state.SnapshotTree: %v", err)
+	}
+	bc.altSnaps = snaps
+	return nil
+}
+
+// snapshotTree returns the [state.SnapshotTree] to be used when opening state,
+// which may be nil.
+func (bc *BlockChain) snapshotTree() state.SnapshotTree {
+	if bc.altSnaps != nil {
+		return bc.altSnaps
+	}
+	return bc.snaps
+}
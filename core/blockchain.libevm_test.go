@@ -0,0 +1,309 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/state/snapshot"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/stateconf"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/triedb"
+)
+
+func TestBlockLifecycle(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	gspec := &Genesis{
+		Config:  params.TestChainConfig,
+		Alloc:   types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	signer := types.LatestSigner(gspec.Config)
+	fork := func(coinbase common.Address, n int) []*types.Block {
+		_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), n, func(i int, b *BlockGen) {
+			b.SetCoinbase(coinbase)
+			b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+				Nonce:    b.TxNonce(addr),
+				To:       &coinbase,
+				Gas:      params.TxGas,
+				GasPrice: b.header.BaseFee,
+			}))
+		})
+		return blocks
+	}
+	a := fork(common.Address{'a'}, 3)
+	b := fork(common.Address{'b'}, 2)
+	orphan := fork(common.Address{'c'}, 2)[1]
+
+	db := rawdb.NewMemoryDatabase()
+	bc, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err, "NewBlockChain()")
+	defer bc.Stop()
+	genesis := bc.CurrentBlock().Hash()
+
+	heads := make(chan ChainHeadEvent, 10)
+	sub := bc.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	for _, blk := range []*types.Block{a[0], b[0], a[1], b[1], a[2]} {
+		require.NoErrorf(t, bc.VerifyAndStage(blk), "VerifyAndStage(block %d)", blk.NumberU64())
+		assert.True(t, bc.IsStaged(blk.Hash()), "IsStaged() after VerifyAndStage()")
+		assert.True(t, bc.HasState(blk.Root()), "HasState() of staged block")
+		assert.NotNil(t, bc.GetReceiptsByHash(blk.Hash()), "GetReceiptsByHash() of staged block")
+	}
+	require.NoError(t, bc.VerifyAndStage(a[0]), "VerifyAndStage() of already-staged block")
+	require.ErrorIs(t, bc.VerifyAndStage(orphan), ErrStagedParentUnknown, "VerifyAndStage() with unknown parent")
+	assert.Equal(t, genesis, bc.CurrentBlock().Hash(), "head unchanged by staging")
+
+	require.ErrorIs(t, bc.Accept(a[1].Hash()), ErrAcceptParentNotFinal, "Accept() out of order")
+	require.ErrorIs(t, bc.Accept(orphan.Hash()), ErrBlockNotStaged, "Accept() of unstaged block")
+
+	require.NoError(t, bc.Accept(a[0].Hash()), "Accept()")
+	assert.Equal(t, a[0].Hash(), bc.CurrentBlock().Hash(), "head after Accept()")
+	assert.Equal(t, a[0].Hash(), rawdb.ReadCanonicalHash(db, 1), "canonical hash after Accept()")
+	assert.NotNil(t, rawdb.ReadTxLookupEntry(db, a[0].Transactions()[0].Hash()), "tx index after Accept()")
+	assert.Nil(t, rawdb.ReadTxLookupEntry(db, b[0].Transactions()[0].Hash()), "tx index of conflicting block")
+	select {
+	case ev := <-heads:
+		assert.Equal(t, a[0].Hash(), ev.Block.Hash(), "ChainHeadEvent after Accept()")
+	default:
+		t.Error("no ChainHeadEvent after Accept()")
+	}
+
+	require.ErrorIs(t, bc.Accept(b[0].Hash()), ErrAcceptParentNotFinal, "Accept() of conflicting block")
+	require.NoError(t, bc.Reject(b[0].Hash()), "Reject()")
+	for _, blk := range b {
+		assert.Falsef(t, bc.IsStaged(blk.Hash()), "IsStaged(block %d) after Reject() of ancestor", blk.NumberU64())
+	}
+	require.ErrorIs(t, bc.Reject(b[1].Hash()), ErrBlockNotStaged, "Reject() of already-rejected descendant")
+
+	for _, blk := range a[1:] {
+		require.NoErrorf(t, bc.Accept(blk.Hash()), "Accept(block %d)", blk.NumberU64())
+		assert.False(t, bc.IsStaged(blk.Hash()), "IsStaged() after Accept()")
+	}
+	assert.Equal(t, a[2].Hash(), bc.CurrentBlock().Hash(), "final head")
+}
+
+// recordingSnapshotTree is a [state.SnapshotTree] that records all updates but
+// serves no state, causing the [state.StateDB] to fall back to the tries.
+type recordingSnapshotTree struct {
+	roots   map[common.Hash]bool
+	updates [][2]common.Hash // (parent, root) pairs
+}
+
+type uncoveredSnapshot common.Hash
+
+func (s uncoveredSnapshot) Root() common.Hash { return common.Hash(s) }
+func (uncoveredSnapshot) Account(common.Hash) (*types.SlimAccount, error) {
+	return nil, snapshot.ErrNotCoveredYet
+}
+func (uncoveredSnapshot) AccountRLP(common.Hash) ([]byte, error) {
+	return nil, snapshot.ErrNotCoveredYet
+}
+func (uncoveredSnapshot) Storage(common.Hash, common.Hash) ([]byte, error) {
+	return nil, snapshot.ErrNotCoveredYet
+}
+
+func (t *recordingSnapshotTree) Cap(common.Hash, int) error { return nil }
+
+func (t *recordingSnapshotTree) Snapshot(root common.Hash) snapshot.Snapshot {
+	if !t.roots[root] {
+		return nil
+	}
+	return uncoveredSnapshot(root)
+}
+
+func (t *recordingSnapshotTree) StorageIterator(common.Hash, common.Hash, common.Hash) (snapshot.StorageIterator, error) {
+	return nil, snapshot.ErrNotCoveredYet
+}
+
+func (t *recordingSnapshotTree) Update(root, parent common.Hash, _ map[common.Hash]struct{}, _ map[common.Hash][]byte, _ map[common.Hash]map[common.Hash][]byte, _ ...stateconf.SnapshotUpdateOption) error {
+	t.roots[root] = true
+	t.updates = append(t.updates, [2]common.Hash{parent, root})
+	return nil
+}
+
+func TestRegisteredSnapshotTree(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	gspec := &Genesis{
+		Config:  params.TestChainConfig,
+		Alloc:   types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	signer := types.LatestSigner(gspec.Config)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 3, func(i int, b *BlockGen) {
+		b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    b.TxNonce(addr),
+			To:       &common.Address{byte(i + 1)},
+			Value:    big.NewInt(1),
+			Gas:      params.TxGas,
+			GasPrice: b.BaseFee(),
+		}))
+	})
+
+	tree := &recordingSnapshotTree{roots: make(map[common.Hash]bool)}
+	state.TestOnlyClearRegisteredSnapshotTreeConstructor()
+	t.Cleanup(state.TestOnlyClearRegisteredSnapshotTreeConstructor)
+	state.RegisterSnapshotTreeConstructor(func(_ ethdb.Database, _ *triedb.Database, head common.Hash) (state.SnapshotTree, error) {
+		tree.roots[head] = true
+		return tree, nil
+	})
+
+	bc, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err, "NewBlockChain()")
+	defer bc.Stop()
+	assert.Nil(t, bc.Snapshots(), "standard snapshot tree")
+
+	_, err = bc.InsertChain(blocks)
+	require.NoError(t, err, "InsertChain()")
+
+	var want [][2]common.Hash
+	parent := bc.Genesis().Root()
+	for _, b := range blocks {
+		want = append(want, [2]common.Hash{parent, b.Root()})
+		parent = b.Root()
+	}
+	assert.Equal(t, want, tree.updates, "(parent, root) pairs passed to registered tree's Update()")
+}
+
+func TestSetFreezeBoundary(t *testing.T) {
+	const numBlocks = 16
+	gspec := &Genesis{
+		Config:  params.TestChainConfig,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), numBlocks, nil)
+
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+	require.NoError(t, err, "rawdb.NewDatabaseWithFreezer()")
+	defer db.Close()
+	bc, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err, "NewBlockChain()")
+	defer bc.Stop()
+	_, err = bc.InsertChain(blocks)
+	require.NoError(t, err, "InsertChain()")
+
+	type freezer interface {
+		Freeze(threshold uint64) error
+		Ancients() (uint64, error)
+	}
+	freeze := func(t *testing.T) uint64 {
+		t.Helper()
+		// The default threshold would otherwise result in nothing being frozen.
+		require.NoError(t, db.(freezer).Freeze(params.FullImmutabilityThreshold), "Freeze()")
+		n, err := db.(freezer).Ancients()
+		require.NoError(t, err, "Ancients()")
+		return n
+	}
+	require.Zero(t, freeze(t), "ancients without freeze boundary")
+
+	steps := []struct {
+		boundary     uint64
+		wantErr      bool
+		wantAncients uint64
+	}{
+		{boundary: 10, wantAncients: 11},
+		{boundary: 5, wantErr: true, wantAncients: 11},
+		{boundary: numBlocks + 1, wantErr: true, wantAncients: 11},
+		{boundary: numBlocks, wantAncients: numBlocks + 1},
+	}
+	for _, s := range steps {
+		err := bc.SetFreezeBoundary(s.boundary)
+		if s.wantErr {
+			assert.Errorf(t, err, "SetFreezeBoundary(%d)", s.boundary)
+		} else {
+			require.NoErrorf(t, err, "SetFreezeBoundary(%d)", s.boundary)
+		}
+		assert.Equalf(t, s.wantAncients, freeze(t), "ancients after SetFreezeBoundary(%d)", s.boundary)
+	}
+
+	got, ok := rawdb.FreezeBoundary(db)
+	assert.True(t, ok, "rawdb.FreezeBoundary() ok")
+	assert.Equal(t, uint64(numBlocks), got, "rawdb.FreezeBoundary()")
+	for _, b := range blocks {
+		assert.NotNilf(t, bc.GetBlockByNumber(b.NumberU64()), "GetBlockByNumber(%d) after freezing", b.NumberU64())
+	}
+
+	assert.Error(t, rawdb.SetFreezeBoundary(rawdb.NewMemoryDatabase(), 0), "rawdb.SetFreezeBoundary() without freezer")
+}
+
+func TestFreezeBoundaryPersistence(t *testing.T) {
+	const numBlocks = 16
+	gspec := &Genesis{
+		Config:  params.TestChainConfig,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), numBlocks, nil)
+
+	opts := rawdb.OpenOptions{
+		Type:              "leveldb",
+		Directory:         t.TempDir(),
+		AncientsDirectory: t.TempDir(),
+	}
+	open := func(t *testing.T) (ethdb.Database, *BlockChain) {
+		t.Helper()
+		db, err := rawdb.Open(opts)
+		require.NoError(t, err, "rawdb.Open()")
+		bc, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+		require.NoError(t, err, "NewBlockChain()")
+		return db, bc
+	}
+
+	const boundary = 10
+	db, bc := open(t)
+	_, err := bc.InsertChain(blocks)
+	require.NoError(t, err, "InsertChain()")
+	require.NoErrorf(t, bc.SetFreezeBoundary(boundary), "SetFreezeBoundary(%d)", boundary)
+	bc.Stop()
+	require.NoError(t, db.Close(), "Close()")
+
+	db, bc = open(t)
+	defer db.Close()
+	defer bc.Stop()
+
+	got, ok := rawdb.FreezeBoundary(db)
+	require.True(t, ok, "rawdb.FreezeBoundary() ok after reopening")
+	assert.Equal(t, uint64(boundary), got, "rawdb.FreezeBoundary() after reopening")
+	assert.Errorf(t, bc.SetFreezeBoundary(boundary-1), "SetFreezeBoundary(%d) after reopening", boundary-1)
+
+	type freezer interface {
+		Freeze(threshold uint64) error
+		Ancients() (uint64, error)
+	}
+	require.NoError(t, db.(freezer).Freeze(params.FullImmutabilityThreshold), "Freeze()")
+	n, err := db.(freezer).Ancients()
+	require.NoError(t, err, "Ancients()")
+	assert.Equal(t, uint64(boundary+1), n, "ancients after reopening")
+}
@@ -265,6 +265,11 @@ func (bc *BlockChain) GetAncestor(hash common.Hash, number, ancestor uint64, max
 // A null will be returned in the transaction is not found and background
 // transaction indexing is already finished. The transaction is not existent
 // from the node's perspective.
+//
+// libevm: if indexing is still in progress and the index doesn't cover the
+// entire chain then the error is a [rawdb.TxIndexRangeError]. The same error is
+// returned after indexing is finished iff enabled via
+// [BlockChain.SetTxLookupRangeErrors].
 func (bc *BlockChain) GetTransactionLookup(hash common.Hash) (*rawdb.LegacyTxLookupEntry, *types.Transaction, error) {
 	// Short circuit if the txlookup already in the cache, retrieve otherwise
 	if item, exist := bc.txLookupCache.Get(hash); exist {
@@ -279,8 +284,18 @@ func (bc *BlockChain) GetTransactionLookup(hash common.Hash) (*rawdb.LegacyTxLoo
 		// The transaction indexing is not finished yet, returning an
 		// error to explicitly indicate it.
 		if !progress.Done() {
+			// libevm: report the range that is already indexed, if any.
+			if err := bc.txIndexRangeError(); err != nil {
+				return nil, nil, err
+			}
 			return nil, nil, errors.New("transaction indexing still in progress")
 		}
+		// libevm: opt-in reporting of the range that is indexed.
+		if bc.txRangeErrs.Load() {
+			if err := bc.txIndexRangeError(); err != nil {
+				return nil, nil, err
+			}
+		}
 		// The transaction is already indexed, the transaction is either
 		// not existent or not in the range of index, returning null.
 		return nil, nil, nil
@@ -353,7 +368,7 @@ func (bc *BlockChain) State() (*state.StateDB, error) {
 
 // StateAt returns a new mutable state based on a particular point in time.
 func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
-	return state.New(root, bc.stateCache, bc.snaps)
+	return state.New(root, bc.stateCache, bc.snapshotTree()) // libevm
 }
 
 // Config retrieves the chain's fork configuration.
@@ -0,0 +1,42 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import "github.com/ava-labs/libevm/core/types"
+
+// processBeforeBlock calls [ProcessBeforeBlock] unless it has already been
+// called for the block. It is called lazily, before the first transaction, so
+// that it follows any call to [BlockGen.SetParentBeaconRoot].
+func (b *BlockGen) processBeforeBlock() {
+	if b.beforeBlock {
+		return
+	}
+	b.beforeBlock = true
+	ProcessBeforeBlock(b.statedb, types.NewBlockWithHeader(b.header))
+}
+
+// processAfterBlock calls [ProcessAfterBlock], first calling
+// [ProcessBeforeBlock] if the block has no transactions.
+func (b *BlockGen) processAfterBlock() {
+	b.processBeforeBlock()
+	block := types.NewBlockWithHeader(b.header).WithBody(types.Body{
+		Transactions: b.txs,
+		Uncles:       b.uncles,
+		Withdrawals:  b.withdrawals,
+	})
+	ProcessAfterBlock(b.statedb, block, b.receipts)
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/log"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A BlockResult is the outcome of processing a block, in a form that MAY be
+// recorded, e.g. as JSON, for later use as a [ConformanceReference].
+type BlockResult struct {
+	Receipts types.Receipts `json:"receipts"`
+	GasUsed  uint64         `json:"gasUsed"`
+	Root     common.Hash    `json:"root"`
+	// Err is empty i.f.f. processing succeeded, in which case all other
+	// fields are populated.
+	Err string `json:"error,omitempty"`
+}
+
+// ProcessBlockResult processes the block with `p` and returns the result,
+// including the post-state root.
+func ProcessBlockResult(config *params.ChainConfig, p Processor, block *types.Block, statedb *state.StateDB, cfg vm.Config) *BlockResult {
+	receipts, _, gasUsed, err := p.Process(block, statedb, cfg)
+	return newBlockResult(config, block, statedb, receipts, gasUsed, err)
+}
+
+func newBlockResult(config *params.ChainConfig, block *types.Block, statedb *state.StateDB, receipts types.Receipts, gasUsed uint64, err error) *BlockResult {
+	if err != nil {
+		return &BlockResult{Err: err.Error()}
+	}
+	return &BlockResult{
+		Receipts: receipts,
+		GasUsed:  gasUsed,
+		Root:     statedb.IntermediateRoot(config.IsEIP158(block.Number())),
+	}
+}
+
+// A ConformanceReference provides the expected result of processing a block.
+type ConformanceReference interface {
+	// BlockResult receives the block and a copy of the state before the block
+	// is processed, which it MAY modify.
+	BlockResult(block *types.Block, parent *state.StateDB, cfg vm.Config) (*BlockResult, error)
+}
+
+// ProcessorReference is a [ConformanceReference] that processes blocks with
+// its own, independently configured, [Processor].
+type ProcessorReference struct {
+	Config    *params.ChainConfig
+	Processor Processor
+}
+
+// BlockResult implements the [ConformanceReference] interface.
+func (r ProcessorReference) BlockResult(block *types.Block, parent *state.StateDB, cfg vm.Config) (*BlockResult, error) {
+	return ProcessBlockResult(r.Config, r.Processor, block, parent, cfg), nil
+}
+
+// RecordedResults is a [ConformanceReference] of results keyed by block hash,
+// e.g. as recorded by another node with [ProcessBlockResult].
+type RecordedResults map[common.Hash]*BlockResult
+
+// ErrNoRecordedResult is returned by [RecordedResults.BlockResult] if there is
+// no result for a block.
+var ErrNoRecordedResult = errors.New("no recorded result for block")
+
+// BlockResult implements the [ConformanceReference] interface.
+func (r RecordedResults) BlockResult(block *types.Block, _ *state.StateDB, _ vm.Config) (*BlockResult, error) {
+	res, ok := r[block.Hash()]
+	if !ok {
+		return nil, fmt.Errorf("%w %d (%v)", ErrNoRecordedResult, block.NumberU64(), block.Hash())
+	}
+	return res, nil
+}
+
+// A Divergence is a single difference between the reference and actual
+// results of processing a block.
+type Divergence struct {
+	Field     string // e.g. "root" or "receipts[1].logs[0].data"
+	Reference string
+	Actual    string
+}
+
+// A DivergenceReport describes all differences between the reference and
+// actual results of processing a block. It implements the error interface.
+type DivergenceReport struct {
+	Number      uint64
+	Hash        common.Hash
+	Divergences []Divergence
+}
+
+func (r *DivergenceReport) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "block %d (%v) diverged from reference:", r.Number, r.Hash)
+	for _, d := range r.Divergences {
+		fmt.Fprintf(&b, " %s: want %s, got %s;", d.Field, d.Reference, d.Actual)
+	}
+	return strings.TrimSuffix(b.String(), ";")
+}
+
+// CompareBlockResults returns all differences between the reference and
+// actual results. Only the error is compared if either result has one.
+func CompareBlockResults(reference, actual *BlockResult) []Divergence {
+	var divs []Divergence
+	diff := func(field string, want, got any) {
+		w, g := fmt.Sprint(want), fmt.Sprint(got)
+		if w != g {
+			divs = append(divs, Divergence{Field: field, Reference: w, Actual: g})
+		}
+	}
+
+	diff("error", reference.Err, actual.Err)
+	if reference.Err != "" || actual.Err != "" {
+		return divs
+	}
+	diff("root", reference.Root, actual.Root)
+	diff("gasUsed", reference.GasUsed, actual.GasUsed)
+	diff("len(receipts)", len(reference.Receipts), len(actual.Receipts))
+
+	for i := range min(len(reference.Receipts), len(actual.Receipts)) {
+		want, got := reference.Receipts[i], actual.Receipts[i]
+		field := func(f string) string { return fmt.Sprintf("receipts[%d].%s", i, f) }
+
+		diff(field("txHash"), want.TxHash, got.TxHash)
+		diff(field("type"), want.Type, got.Type)
+		diff(field("status"), want.Status, got.Status)
+		diff(field("postState"), common.Bytes2Hex(want.PostState), common.Bytes2Hex(got.PostState))
+		diff(field("cumulativeGasUsed"), want.CumulativeGasUsed, got.CumulativeGasUsed)
+		diff(field("gasUsed"), want.GasUsed, got.GasUsed)
+		diff(field("contractAddress"), want.ContractAddress, got.ContractAddress)
+		if !bytes.Equal(want.Bloom[:], got.Bloom[:]) {
+			divs = append(divs, Divergence{Field: field("logsBloom"), Reference: common.Bytes2Hex(want.Bloom[:]), Actual: common.Bytes2Hex(got.Bloom[:])})
+		}
+		diff(field("len(logs)"), len(want.Logs), len(got.Logs))
+
+		for j := range min(len(want.Logs), len(got.Logs)) {
+			wl, gl := want.Logs[j], got.Logs[j]
+			logField := func(f string) string { return field(fmt.Sprintf("logs[%d].%s", j, f)) }
+			diff(logField("address"), wl.Address, gl.Address)
+			diff(logField("topics"), wl.Topics, gl.Topics)
+			diff(logField("data"), common.Bytes2Hex(wl.Data), common.Bytes2Hex(gl.Data))
+		}
+	}
+	return divs
+}
+
+// A ConformanceProcessor is a [Processor] that additionally checks the result
+// of every block against a [ConformanceReference], for detecting consensus
+// drift; e.g. that introduced by hooks. See [NewConformanceProcessor].
+type ConformanceProcessor struct {
+	config       *params.ChainConfig
+	primary      Processor
+	reference    ConformanceReference
+	onDivergence func(*DivergenceReport) error
+}
+
+var _ Processor = (*ConformanceProcessor)(nil)
+
+// NewConformanceProcessor returns a [Processor] that propagates all calls to
+// `primary`, comparing each result against `reference`. Upon divergence,
+// `onDivergence` is called with a report and, if it returns a non-nil error,
+// processing of the block fails with said error. Returning the report itself
+// results in strict conformance, e.g. for CI, while logging it and returning
+// nil allows for shadow validation.
+//
+// The reference is run without any [vm.Config.Tracer]. Failure to obtain a
+// reference result is logged but doesn't otherwise affect processing.
+func NewConformanceProcessor(config *params.ChainConfig, primary Processor, reference ConformanceReference, onDivergence func(*DivergenceReport) error) *ConformanceProcessor {
+	return &ConformanceProcessor{
+		config:       config,
+		primary:      primary,
+		reference:    reference,
+		onDivergence: onDivergence,
+	}
+}
+
+// Process implements the [Processor] interface. The post-state root of
+// `statedb` is computed if processing succeeds.
+func (p *ConformanceProcessor) Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error) {
+	parent := statedb.Copy()
+	receipts, logs, gasUsed, err := p.primary.Process(block, statedb, cfg)
+	actual := newBlockResult(p.config, block, statedb, receipts, gasUsed, err)
+
+	refCfg := cfg
+	refCfg.Tracer = nil
+	reference, refErr := p.reference.BlockResult(block, parent, refCfg)
+	if refErr != nil {
+		log.Warn("Conformance reference unavailable", "number", block.NumberU64(), "hash", block.Hash(), "err", refErr)
+		return receipts, logs, gasUsed, err
+	}
+
+	divs := CompareBlockResults(reference, actual)
+	if len(divs) == 0 || p.onDivergence == nil {
+		return receipts, logs, gasUsed, err
+	}
+	report := &DivergenceReport{
+		Number:      block.NumberU64(),
+		Hash:        block.Hash(),
+		Divergences: divs,
+	}
+	if dErr := p.onDivergence(report); dErr != nil && err == nil {
+		return nil, nil, 0, dErr
+	}
+	return receipts, logs, gasUsed, err
+}
+
+// EnableConformanceChecking wraps the [BlockChain]'s [Processor] with a
+// [ConformanceProcessor]; see [NewConformanceProcessor] for the arguments.
+// It MUST be called before block import starts.
+func (bc *BlockChain) EnableConformanceChecking(reference ConformanceReference, onDivergence func(*DivergenceReport) error) {
+	bc.processor = NewConformanceProcessor(bc.chainConfig, bc.processor, reference, onDivergence)
+}
@@ -0,0 +1,222 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core_test
+
+import (
+	"maps"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/params"
+)
+
+// driftingProcessor credits an address after every block, modelling a hook
+// that introduces consensus drift.
+type driftingProcessor struct {
+	core.Processor
+}
+
+func (p driftingProcessor) Process(block *types.Block, sdb *state.StateDB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error) {
+	receipts, logs, gasUsed, err := p.Processor.Process(block, sdb, cfg)
+	sdb.AddBalance(common.Address{'d', 'r', 'i', 'f', 't'}, uint256.NewInt(1))
+	return receipts, logs, gasUsed, err
+}
+
+func TestConformanceProcessor(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	gspec := &core.Genesis{
+		Config:  params.TestChainConfig,
+		Alloc:   types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	signer := types.LatestSigner(gspec.Config)
+	_, blocks, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 3, func(_ int, b *core.BlockGen) {
+		b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    b.TxNonce(addr),
+			To:       &common.Address{},
+			Gas:      params.TxGas,
+			GasPrice: b.BaseFee(),
+		}))
+	})
+
+	newChain := func(t *testing.T) *core.BlockChain {
+		t.Helper()
+		bc, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+		require.NoError(t, err, "NewBlockChain()")
+		t.Cleanup(bc.Stop)
+		return bc
+	}
+
+	recorded := make(core.RecordedResults)
+	for i, b := range blocks {
+		recorded[b.Hash()] = &core.BlockResult{
+			Receipts: receipts[i],
+			GasUsed:  b.GasUsed(),
+			Root:     b.Root(),
+		}
+	}
+	tampered := maps.Clone(recorded)
+	{
+		r := *recorded[blocks[1].Hash()]
+		r.GasUsed++
+		tampered[blocks[1].Hash()] = &r
+	}
+
+	tests := []struct {
+		name      string
+		reference func(*core.BlockChain) core.ConformanceReference
+		strict    bool
+		wantErr   bool
+		// wantFields are the divergent fields of each reported block, by number.
+		wantFields map[uint64][]string
+	}{
+		{
+			name: "same_processor",
+			reference: func(bc *core.BlockChain) core.ConformanceReference {
+				return core.ProcessorReference{
+					Config:    gspec.Config,
+					Processor: core.NewStateProcessor(gspec.Config, bc, bc.Engine()),
+				}
+			},
+			strict: true,
+		},
+		{
+			name: "matching_recording",
+			reference: func(*core.BlockChain) core.ConformanceReference {
+				return recorded
+			},
+			strict: true,
+		},
+		{
+			name: "drifting_processor_strict",
+			reference: func(bc *core.BlockChain) core.ConformanceReference {
+				return core.ProcessorReference{
+					Config:    gspec.Config,
+					Processor: driftingProcessor{core.NewStateProcessor(gspec.Config, bc, bc.Engine())},
+				}
+			},
+			strict:     true,
+			wantErr:    true,
+			wantFields: map[uint64][]string{1: {"root"}},
+		},
+		{
+			name: "tampered_recording_shadow",
+			reference: func(*core.BlockChain) core.ConformanceReference {
+				return tampered
+			},
+			wantFields: map[uint64][]string{2: {"gasUsed"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := newChain(t)
+
+			gotFields := make(map[uint64][]string)
+			bc.EnableConformanceChecking(tt.reference(bc), func(r *core.DivergenceReport) error {
+				for _, d := range r.Divergences {
+					gotFields[r.Number] = append(gotFields[r.Number], d.Field)
+				}
+				if tt.strict {
+					return r
+				}
+				return nil
+			})
+
+			_, err := bc.InsertChain(blocks)
+			if tt.wantErr {
+				var report *core.DivergenceReport
+				require.ErrorAsf(t, err, &report, "%T.InsertChain()", bc)
+			} else {
+				require.NoErrorf(t, err, "%T.InsertChain()", bc)
+			}
+
+			if len(tt.wantFields) == 0 {
+				tt.wantFields = map[uint64][]string{}
+			}
+			assert.Equal(t, tt.wantFields, gotFields, "divergent fields reported")
+		})
+	}
+}
+
+func TestCompareBlockResults(t *testing.T) {
+	receipt := func(status uint64, logData ...byte) *types.Receipt {
+		return &types.Receipt{
+			Status: status,
+			Logs:   []*types.Log{{Address: common.Address{1}, Data: logData}},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		reference, actual *core.BlockResult
+		want              []core.Divergence
+	}{
+		{
+			name:      "equal",
+			reference: &core.BlockResult{Receipts: types.Receipts{receipt(1, 42)}},
+			actual:    &core.BlockResult{Receipts: types.Receipts{receipt(1, 42)}},
+		},
+		{
+			name:      "error_only",
+			reference: &core.BlockResult{Err: "boom", GasUsed: 1},
+			actual:    &core.BlockResult{GasUsed: 2},
+			want:      []core.Divergence{{Field: "error", Reference: "boom", Actual: ""}},
+		},
+		{
+			name:      "receipt_fields",
+			reference: &core.BlockResult{Receipts: types.Receipts{receipt(1, 42)}},
+			actual:    &core.BlockResult{Receipts: types.Receipts{receipt(0, 43)}},
+			want: []core.Divergence{
+				{Field: "receipts[0].status", Reference: "1", Actual: "0"},
+				{Field: "receipts[0].logs[0].data", Reference: "2a", Actual: "2b"},
+			},
+		},
+		{
+			name:      "receipt_count",
+			reference: &core.BlockResult{Receipts: types.Receipts{receipt(1)}},
+			actual:    &core.BlockResult{},
+			want:      []core.Divergence{{Field: "len(receipts)", Reference: "1", Actual: "0"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, core.CompareBlockResults(tt.reference, tt.actual))
+		})
+	}
+}
+
+func TestRecordedResultsMissing(t *testing.T) {
+	_, err := core.RecordedResults{}.BlockResult(types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}), nil, vm.Config{})
+	require.ErrorIs(t, err, core.ErrNoRecordedResult)
+}
@@ -19,6 +19,7 @@ package core
 import (
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/internal/libevm/pseudo"
 )
 
 // NewTxsEvent is posted when a batch of transactions enter the transaction pool.
@@ -34,10 +35,18 @@ type ChainEvent struct {
 	Block *types.Block
 	Hash  common.Hash
 	Logs  []*types.Log
+
+	extra *pseudo.Type // libevm
 }
 
 type ChainSideEvent struct {
 	Block *types.Block
+
+	extra *pseudo.Type // libevm
 }
 
-type ChainHeadEvent struct{ Block *types.Block }
+type ChainHeadEvent struct {
+	Block *types.Block
+
+	extra *pseudo.Type // libevm
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ava-labs/libevm/internal/libevm/pseudo"
+	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/log"
+)
+
+// EventExtras configures the extra payloads carried by [ChainEvent],
+// [ChainHeadEvent], and [ChainSideEvent] values sent by a [BlockChain]. See
+// [RegisterEventExtras].
+type EventExtras[E any] struct {
+	// ChainEvent, ChainHeadEvent, and ChainSideEvent, if non-nil, are called by
+	// the [BlockChain] immediately before sending the respective event, and
+	// return its extra payload. Events for which the function is nil carry a
+	// zero-value E.
+	//
+	// They are called on the goroutine that sends the event, typically while
+	// holding the chain mutex, and MUST NOT call BlockChain methods that
+	// acquire it.
+	ChainEvent     func(*BlockChain, *ChainEvent) E
+	ChainHeadEvent func(*BlockChain, *ChainHeadEvent) E
+	ChainSideEvent func(*BlockChain, *ChainSideEvent) E
+}
+
+// EventPayloads provides access to the extra payloads registered with
+// [RegisterEventExtras]. Subscribers receive events by value and SHOULD pass a
+// pointer to their copy; e.g. `payloads.ChainEvent.Get(&ev)`.
+type EventPayloads[E any] struct {
+	ChainEvent     pseudo.Accessor[*ChainEvent, E]
+	ChainHeadEvent pseudo.Accessor[*ChainHeadEvent, E]
+	ChainSideEvent pseudo.Accessor[*ChainSideEvent, E]
+}
+
+// RegisterEventExtras registers the type `E` to be carried as an extra payload
+// of chain events, populated as configured by the [EventExtras]. It is expected
+// to be called in an `init()` function and MUST NOT be called more than once.
+func RegisterEventExtras[E any](e EventExtras[E]) EventPayloads[E] {
+	registeredEventExtras.MustRegister(&eventExtras{
+		chain: populator(e.ChainEvent),
+		head:  populator(e.ChainHeadEvent),
+		side:  populator(e.ChainSideEvent),
+	})
+	log.Info("Registered core event extras", "type", log.TypeOf(pseudo.Zero[E]().Value.Get()))
+
+	return EventPayloads[E]{
+		ChainEvent: pseudo.NewAccessor[*ChainEvent, E](
+			func(ev *ChainEvent) *pseudo.Type { return extraOrZero[E](&ev.extra) },
+			func(ev *ChainEvent, t *pseudo.Type) { ev.extra = t },
+		),
+		ChainHeadEvent: pseudo.NewAccessor[*ChainHeadEvent, E](
+			func(ev *ChainHeadEvent) *pseudo.Type { return extraOrZero[E](&ev.extra) },
+			func(ev *ChainHeadEvent, t *pseudo.Type) { ev.extra = t },
+		),
+		ChainSideEvent: pseudo.NewAccessor[*ChainSideEvent, E](
+			func(ev *ChainSideEvent) *pseudo.Type { return extraOrZero[E](&ev.extra) },
+			func(ev *ChainSideEvent, t *pseudo.Type) { ev.extra = t },
+		),
+	}
+}
+
+// TestOnlyClearRegisteredEventExtras clears the [EventExtras] previously passed
+// to [RegisterEventExtras]. It panics if called from a non-testing call stack.
+func TestOnlyClearRegisteredEventExtras() {
+	registeredEventExtras.TestOnlyClear()
+}
+
+var registeredEventExtras register.AtMostOnce[*eventExtras]
+
+// eventExtras are the type-erased [EventExtras]. Every function is non-nil and
+// returns the payload to be carried by the event.
+type eventExtras struct {
+	chain func(*BlockChain, *ChainEvent) *pseudo.Type
+	head  func(*BlockChain, *ChainHeadEvent) *pseudo.Type
+	side  func(*BlockChain, *ChainSideEvent) *pseudo.Type
+}
+
+func populator[Ev, E any](fn func(*BlockChain, *Ev) E) func(*BlockChain, *Ev) *pseudo.Type {
+	if fn == nil {
+		return func(*BlockChain, *Ev) *pseudo.Type { return pseudo.Zero[E]().Type }
+	}
+	return func(bc *BlockChain, ev *Ev) *pseudo.Type {
+		return pseudo.From(fn(bc, ev)).Type
+	}
+}
+
+// extraOrZero returns `*t`, first setting it to a zero-value E if nil, which
+// is the case for events not sent by a [BlockChain].
+func extraOrZero[E any](t **pseudo.Type) *pseudo.Type {
+	if *t == nil {
+		*t = pseudo.Zero[E]().Type
+	}
+	return *t
+}
+
+// withExtras returns the event with its extra payload populated, if
+// [RegisterEventExtras] has been called.
+func (ev ChainEvent) withExtras(bc *BlockChain) ChainEvent {
+	if r := &registeredEventExtras; r.Registered() {
+		ev.extra = r.Get().chain(bc, &ev)
+	}
+	return ev
+}
+
+// withExtras is the [ChainHeadEvent] equivalent of [ChainEvent.withExtras].
+func (ev ChainHeadEvent) withExtras(bc *BlockChain) ChainHeadEvent {
+	if r := &registeredEventExtras; r.Registered() {
+		ev.extra = r.Get().head(bc, &ev)
+	}
+	return ev
+}
+
+// withExtras is the [ChainSideEvent] equivalent of [ChainEvent.withExtras].
+func (ev ChainSideEvent) withExtras(bc *BlockChain) ChainSideEvent {
+	if r := &registeredEventExtras; r.Registered() {
+		ev.extra = r.Get().side(bc, &ev)
+	}
+	return ev
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/params"
+)
+
+type eventExtra struct {
+	Kind   string
+	Number uint64
+	Head   uint64
+}
+
+func TestEventExtras(t *testing.T) {
+	TestOnlyClearRegisteredEventExtras()
+	t.Cleanup(TestOnlyClearRegisteredEventExtras)
+	extras := RegisterEventExtras(EventExtras[eventExtra]{
+		ChainEvent: func(bc *BlockChain, ev *ChainEvent) eventExtra {
+			return eventExtra{
+				Kind:   "chain",
+				Number: ev.Block.NumberU64(),
+			}
+		},
+		ChainHeadEvent: func(bc *BlockChain, ev *ChainHeadEvent) eventExtra {
+			return eventExtra{
+				Kind:   "head",
+				Number: ev.Block.NumberU64(),
+				Head:   bc.CurrentBlock().Number.Uint64(),
+			}
+		},
+	})
+
+	gspec := &Genesis{Config: params.TestChainConfig}
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 2, nil)
+
+	bc, err := NewBlockChain(rawdb.NewMemoryDatabase(), DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err, "NewBlockChain()")
+	defer bc.Stop()
+
+	chainEvents := make(chan ChainEvent, len(blocks))
+	headEvents := make(chan ChainHeadEvent, len(blocks))
+	chainSub := bc.SubscribeChainEvent(chainEvents)
+	defer chainSub.Unsubscribe()
+	headSub := bc.SubscribeChainHeadEvent(headEvents)
+	defer headSub.Unsubscribe()
+
+	_, err = bc.InsertChain(blocks)
+	require.NoError(t, err, "InsertChain()")
+
+	for _, b := range blocks {
+		ev := <-chainEvents
+		want := eventExtra{Kind: "chain", Number: b.NumberU64()}
+		assert.Equal(t, want, extras.ChainEvent.Get(&ev), "ChainEvent extra payload")
+	}
+	ev := <-headEvents
+	last := blocks[len(blocks)-1].NumberU64()
+	want := eventExtra{Kind: "head", Number: last, Head: last}
+	assert.Equal(t, want, extras.ChainHeadEvent.Get(&ev), "ChainHeadEvent extra payload")
+
+	t.Run("zero_values", func(t *testing.T) {
+		assert.Zero(t, extras.ChainEvent.Get(&ChainEvent{}), "ChainEvent not sent by BlockChain")
+		side := ChainSideEvent{}.withExtras(bc)
+		assert.Zero(t, extras.ChainSideEvent.Get(&side), "ChainSideEvent without population hook")
+
+		ev := ChainEvent{}
+		extras.ChainEvent.Set(&ev, eventExtra{Kind: "set"})
+		assert.Equal(t, "set", extras.ChainEvent.Get(&ev).Kind, "Get() after Set()")
+	})
+}
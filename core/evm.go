@@ -61,7 +61,7 @@ func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common
 	if header.Difficulty.Cmp(common.Big0) == 0 {
 		random = &header.MixDigest
 	}
-	return vm.BlockContext{
+	bCtx := vm.BlockContext{ // libevm: assigned instead of returned
 		CanTransfer: CanTransfer,
 		Transfer:    Transfer,
 		GetHash:     GetHashFn(header, chain),
@@ -75,6 +75,9 @@ func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common
 		Random:      random,
 		Header:      header,
 	}
+	bCtx.SetPrecompileInvocations(vm.NewPrecompileInvocations()) // libevm: shared by copies
+	setBlockOracle(&bCtx, header)                                // libevm
+	return bCtx
 }
 
 // NewEVMTxContext creates a new transaction context for a single transaction.
@@ -0,0 +1,59 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+
+package core
+
+import (
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/register"
+)
+
+// A BlockOracle provides the payload passed to [vm.BlockContext.SetBlockOracle]
+// by [NewEVMBlockContext], and therefore by [StateProcessor.Process], block
+// builders, and RPC methods that execute on top of a block. If it returns
+// false then no payload is set.
+//
+// See [vm.BlockContext.SetBlockOracle] regarding consensus; in particular, all
+// nodes MUST return equivalent payloads for the same header.
+type BlockOracle func(*types.Header) (payload any, ok bool)
+
+// RegisterBlockOracle registers the [BlockOracle] consulted by
+// [NewEVMBlockContext]. It is expected to be called in an `init()` function
+// and MUST NOT be called more than once.
+func RegisterBlockOracle(o BlockOracle) {
+	registeredBlockOracle.MustRegister(o)
+}
+
+// TestOnlyClearBlockOracle clears the [BlockOracle] previously passed to
+// [RegisterBlockOracle]. It panics if called from a non-testing call stack.
+func TestOnlyClearBlockOracle() {
+	registeredBlockOracle.TestOnlyClear()
+}
+
+var registeredBlockOracle register.AtMostOnce[BlockOracle]
+
+// setBlockOracle sets the payload provided by the registered [BlockOracle],
+// if any.
+func setBlockOracle(c *vm.BlockContext, hdr *types.Header) {
+	if !registeredBlockOracle.Registered() {
+		return
+	}
+	if payload, ok := registeredBlockOracle.Get()(hdr); ok {
+		c.SetBlockOracle(payload)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestRegisterBlockOracle(t *testing.T) {
+	type price struct{ wei uint64 }
+	// The oracle deliberately skips some blocks to demonstrate that no payload
+	// is set.
+	const skip = 2
+	oracle := func(h *types.Header) (any, bool) {
+		n := h.Number.Uint64()
+		return &price{wei: 10 * n}, n != skip
+	}
+	core.RegisterBlockOracle(oracle)
+	t.Cleanup(core.TestOnlyClearBlockOracle)
+
+	// The precompile records the payload in its own storage.
+	precompile := common.Address{'o', 'r', 'a', 'c', 'l', 'e'}
+	slot := common.Hash{}
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				val := common.Hash{0xff} // no payload
+				if p, ok := env.BlockOracle(); ok {
+					val = uint256.NewInt(p.(*price).wei).Bytes32() //nolint:forcetypeassert // Known type
+				}
+				env.StateDB().SetState(precompile, slot, val)
+				return nil, nil
+			}),
+		},
+	}
+	extras := hooks.Register(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	config := *params.TestChainConfig
+	extras.ChainConfig.Set(&config, hooks)
+	gspec := &core.Genesis{
+		Config: &config,
+		Alloc: types.GenesisAlloc{
+			addr:       {Balance: big.NewInt(params.Ether)},
+			precompile: {Nonce: 1, Balance: new(big.Int)}, // avoid EIP-158 deletion of storage
+		},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	signer := types.LatestSigner(gspec.Config)
+
+	const numBlocks = 3
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), numBlocks, func(_ int, b *core.BlockGen) {
+		b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    b.TxNonce(addr),
+			To:       &precompile,
+			Gas:      100_000,
+			GasPrice: b.BaseFee(),
+		}))
+	})
+	bc, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err, "core.NewBlockChain()")
+	defer bc.Stop()
+	_, err = bc.InsertChain(blocks)
+	require.NoError(t, err, "%T.InsertChain()", bc)
+
+	for _, block := range blocks {
+		sdb, err := bc.StateAt(block.Root())
+		require.NoErrorf(t, err, "%T.StateAt(block %d)", bc, block.NumberU64())
+
+		want := common.Hash{0xff}
+		if p, ok := oracle(block.Header()); ok {
+			want = uint256.NewInt(p.(*price).wei).Bytes32() //nolint:forcetypeassert // Known type
+		}
+		assert.Equalf(t, want, sdb.GetState(precompile, slot), "payload recorded by precompile in block %d", block.NumberU64())
+	}
+}
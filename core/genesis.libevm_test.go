@@ -0,0 +1,46 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/triedb"
+)
+
+func TestSetupGenesisBlockValidatesExtras(t *testing.T) {
+	errInvalid := errors.New("invalid extras")
+	hooks := &hookstest.Stub{
+		ValidateFn: func(*params.ChainConfig) error { return errInvalid },
+	}
+	extras := hooks.Register(t)
+
+	config := *params.TestChainConfig
+	extras.ChainConfig.Set(&config, hooks)
+	gspec := &core.Genesis{Config: &config}
+
+	db := rawdb.NewMemoryDatabase()
+	_, _, err := core.SetupGenesisBlock(db, triedb.NewDatabase(db, nil), gspec)
+	require.ErrorIs(t, err, errInvalid, "SetupGenesisBlock()")
+}
@@ -0,0 +1,308 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/log"
+	"github.com/ava-labs/libevm/metrics"
+)
+
+// A HistoryRetentionPolicy determines which ancient chain segments MAY be
+// pruned, in the style of EIP-4444 history expiry. See
+// [SetHistoryRetentionPolicy].
+type HistoryRetentionPolicy interface {
+	// PruneBefore returns the lowest block number, given the current chain
+	// head, of the range of blocks whose history MUST be retained. The
+	// history of all lower-numbered blocks MAY be pruned.
+	PruneBefore(head uint64) uint64
+	// Retain reports whether the history of a block numbered below the value
+	// returned by PruneBefore MUST nonetheless be retained; e.g. for epoch
+	// checkpoints.
+	Retain(number uint64) bool
+}
+
+// RecentHistory is a [HistoryRetentionPolicy] that retains the most recent
+// Blocks blocks, and every block with a number that is a multiple of a
+// non-zero CheckpointInterval. The genesis block is therefore always retained
+// if there are checkpoints.
+type RecentHistory struct {
+	Blocks             uint64
+	CheckpointInterval uint64
+}
+
+var _ HistoryRetentionPolicy = RecentHistory{}
+
+// PruneBefore implements the [HistoryRetentionPolicy] interface.
+func (r RecentHistory) PruneBefore(head uint64) uint64 {
+	if n := head + 1; n > r.Blocks {
+		return n - r.Blocks
+	}
+	return 0
+}
+
+// Retain implements the [HistoryRetentionPolicy] interface.
+func (r RecentHistory) Retain(number uint64) bool {
+	return r.CheckpointInterval != 0 && number%r.CheckpointInterval == 0
+}
+
+// SetHistoryRetentionPolicy registers the policy consumed by a background
+// pruner, started by every [BlockChain], that truncates the ancient store.
+// Blocks that are below the pruning threshold but which the policy requires to
+// be retained are first copied to the key-value store. It is expected to be
+// called in an `init()` function and MUST NOT be called more than once.
+//
+// The pruner never removes history that is still referenced by the
+// transaction index; i.e. it never truncates beyond the tail set by the
+// [BlockChain]'s transaction indexer, which SHOULD therefore be configured
+// with a lookup limit no greater than the retention range. Snapshot and trie
+// layers only reference state, not history, and are unaffected.
+func SetHistoryRetentionPolicy(p HistoryRetentionPolicy) {
+	historyPolicy.MustRegister(p)
+}
+
+// TestOnlyClearHistoryRetentionPolicy clears the policy previously passed to
+// [SetHistoryRetentionPolicy]. It panics if called from a non-testing call
+// stack.
+func TestOnlyClearHistoryRetentionPolicy() {
+	historyPolicy.TestOnlyClear()
+}
+
+var historyPolicy register.AtMostOnce[HistoryRetentionPolicy]
+
+var (
+	historyTailGauge     = metrics.NewRegisteredGauge("chain/history/tail", nil)
+	historyTargetGauge   = metrics.NewRegisteredGauge("chain/history/target", nil)
+	historyPrunedMeter   = metrics.NewRegisteredMeter("chain/history/pruned", nil)
+	historyRetainedMeter = metrics.NewRegisteredMeter("chain/history/retained", nil)
+)
+
+// HistoryPruneProgress describes the progress of history pruning.
+type HistoryPruneProgress struct {
+	Tail   uint64 // number of the oldest block in the ancient store
+	Target uint64 // tail being pruned towards, after index protections
+}
+
+// Done returns whether pruning has reached its target.
+func (p HistoryPruneProgress) Done() bool {
+	return p.Tail >= p.Target
+}
+
+// historyPruner truncates the ancient store according to a
+// [HistoryRetentionPolicy], in response to chain-head events.
+type historyPruner struct {
+	policy   HistoryRetentionPolicy
+	db       ethdb.Database
+	indexing bool // whether the transaction indexer is enabled
+
+	mu       sync.Mutex
+	progress HistoryPruneProgress
+
+	term   chan struct{}
+	closed chan struct{}
+}
+
+// startHistoryPruner starts a background pruner iff a policy has been
+// registered with [SetHistoryRetentionPolicy].
+func (bc *BlockChain) startHistoryPruner(indexing bool) {
+	if !historyPolicy.Registered() {
+		return
+	}
+	p := &historyPruner{
+		policy:   historyPolicy.Get(),
+		db:       bc.db,
+		indexing: indexing,
+		term:     make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+	bc.historyPruner = p
+	go p.loop(bc)
+	log.Info("Initialized history pruner", "policy", log.TypeOf(p.policy))
+}
+
+// stopHistoryPruner stops the pruner, if any, waiting for it to return.
+func (bc *BlockChain) stopHistoryPruner() {
+	if p := bc.historyPruner; p != nil {
+		p.close()
+	}
+}
+
+// HistoryPruneProgress returns the progress of the background history pruner,
+// or an error if there is none.
+func (bc *BlockChain) HistoryPruneProgress() (HistoryPruneProgress, error) {
+	p := bc.historyPruner
+	if p == nil {
+		return HistoryPruneProgress{}, errors.New("no history retention policy set")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.progress, nil
+}
+
+func (p *historyPruner) loop(bc *BlockChain) {
+	defer close(p.closed)
+
+	var (
+		stop   chan struct{} // Non-nil if background routine is active.
+		done   chan struct{} // Non-nil if background routine is active.
+		headCh = make(chan ChainHeadEvent)
+		sub    = bc.SubscribeChainHeadEvent(headCh)
+	)
+	defer sub.Unsubscribe()
+
+	launch := func(head uint64) {
+		stop = make(chan struct{})
+		done = make(chan struct{})
+		go p.run(head, stop, done)
+	}
+	// The head header is used, instead of the head block, as history is
+	// available up to it after snap sync.
+	if head := rawdb.ReadHeadHeader(p.db); head != nil {
+		launch(head.Number.Uint64())
+	}
+
+	for {
+		select {
+		case head := <-headCh:
+			if done == nil {
+				launch(head.Block.NumberU64())
+			}
+		case <-done:
+			stop, done = nil, nil
+		case <-p.term:
+			if stop != nil {
+				close(stop)
+				<-done
+			}
+			return
+		}
+	}
+}
+
+func (p *historyPruner) close() {
+	select {
+	case p.term <- struct{}{}:
+		<-p.closed
+	case <-p.closed:
+	}
+}
+
+// run performs a single pruning pass, closing `done` upon return.
+func (p *historyPruner) run(head uint64, stop, done chan struct{}) {
+	defer close(done)
+	if err := p.prune(head, stop); err != nil {
+		log.Error("Failed to prune history", "head", head, "err", err)
+	}
+}
+
+func (p *historyPruner) setProgress(tail, target uint64) {
+	p.mu.Lock()
+	p.progress = HistoryPruneProgress{Tail: tail, Target: target}
+	p.mu.Unlock()
+	historyTailGauge.Update(int64(tail))     //nolint:gosec // Won't overflow for a very long time
+	historyTargetGauge.Update(int64(target)) //nolint:gosec // Won't overflow for a very long time
+}
+
+// target returns the tail towards which the ancient store can be pruned,
+// honouring both the policy and protections for indexed data.
+func (p *historyPruner) target(head uint64) (uint64, error) {
+	target := p.policy.PruneBefore(head)
+
+	frozen, err := p.db.Ancients()
+	if err != nil {
+		return 0, err
+	}
+	// Only ancient data is pruned, and the last frozen block is retained as
+	// it may still be the only source of the chain head during recovery.
+	if frozen == 0 {
+		return 0, nil
+	}
+	target = min(target, frozen-1)
+
+	if p.indexing {
+		// Transactions are unindexed by reading their block bodies, which
+		// MUST therefore remain available until the indexer has moved past
+		// them.
+		tail := rawdb.ReadTxIndexTail(p.db)
+		if tail == nil {
+			return 0, nil
+		}
+		target = min(target, *tail)
+	}
+	return target, nil
+}
+
+func (p *historyPruner) prune(head uint64, stop chan struct{}) error {
+	tail, err := p.db.Tail()
+	if err != nil {
+		return err
+	}
+	target, err := p.target(head)
+	if err != nil {
+		return err
+	}
+	p.setProgress(tail, max(tail, target))
+	if target <= tail {
+		return nil
+	}
+
+	// Retained blocks are copied before truncation so that an interrupted
+	// pass is safely repeatable; copies are idempotent.
+	var (
+		batch    = p.db.NewBatch()
+		retained int64
+	)
+	for n := tail; n < target; n++ {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		if !p.policy.Retain(n) {
+			continue
+		}
+		if err := rawdb.CopyAncientBlock(p.db, batch, n); err != nil {
+			return err
+		}
+		retained++
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	old, err := p.db.TruncateTail(target)
+	if err != nil {
+		return fmt.Errorf("truncating ancient tail to %d: %w", target, err)
+	}
+	historyPrunedMeter.Mark(int64(target - old)) //nolint:gosec // Won't overflow for a very long time
+	historyRetainedMeter.Mark(retained)
+	p.setProgress(target, target)
+	log.Info("Pruned ancient history", "from", old, "to", target, "retained", retained)
+	return nil
+}
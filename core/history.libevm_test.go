@@ -0,0 +1,232 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/ethdb/leveldb"
+	"github.com/ava-labs/libevm/params"
+)
+
+type historyTestChain struct {
+	gspec             *Genesis
+	blocks            []*types.Block
+	kvDir, ancientDir string
+}
+
+// newHistoryTestChain generates a chain and imports it such that all blocks
+// below `ancientLimit` are in the freezer.
+func newHistoryTestChain(t *testing.T, numBlocks int, ancientLimit uint64) *historyTestChain {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	gspec := &Genesis{
+		Config:  params.TestChainConfig,
+		Alloc:   types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	signer := types.LatestSigner(gspec.Config)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, ethash.NewFaker(), numBlocks, func(i int, b *BlockGen) {
+		tx := types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    b.TxNonce(addr),
+			To:       &common.Address{},
+			Gas:      params.TxGas,
+			GasPrice: b.header.BaseFee,
+		})
+		b.AddTx(tx)
+	})
+
+	c := &historyTestChain{
+		gspec:      gspec,
+		blocks:     blocks,
+		kvDir:      t.TempDir(),
+		ancientDir: t.TempDir(),
+	}
+	db := c.open(t)
+	defer db.Close()
+
+	bc, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err, "NewBlockChain()")
+	defer bc.Stop()
+
+	headers := make([]*types.Header, len(blocks))
+	for i, b := range blocks {
+		headers[i] = b.Header()
+	}
+	_, err = bc.InsertHeaderChain(headers)
+	require.NoError(t, err, "InsertHeaderChain()")
+	_, err = bc.InsertReceiptChain(blocks, receipts, ancientLimit)
+	require.NoError(t, err, "InsertReceiptChain()")
+	return c
+}
+
+func (c *historyTestChain) open(t *testing.T) ethdb.Database {
+	t.Helper()
+	kv, err := leveldb.New(c.kvDir, 0, 0, "", false)
+	require.NoError(t, err, "leveldb.New()")
+	db, err := rawdb.NewDatabaseWithFreezer(kv, c.ancientDir, "", false)
+	require.NoError(t, err, "rawdb.NewDatabaseWithFreezer()")
+	return db
+}
+
+func (c *historyTestChain) hash(n uint64) common.Hash {
+	if n == 0 {
+		return c.gspec.ToBlock().Hash()
+	}
+	return c.blocks[n-1].Hash()
+}
+
+// assertHistory asserts that all blocks in [0,head] are available iff they are
+// at or above `tail` or are retained under `policy`. The genesis block is
+// always kept in the key-value store by the freezer.
+func (c *historyTestChain) assertHistory(t *testing.T, db ethdb.Database, tail uint64, policy HistoryRetentionPolicy) {
+	t.Helper()
+	for n := uint64(0); n <= uint64(len(c.blocks)); n++ {
+		want := n == 0 || n >= tail || policy.Retain(n)
+		hash := c.hash(n)
+
+		assert.Equalf(t, want, rawdb.ReadCanonicalHash(db, n) == hash, "block %d canonical hash available", n)
+		assert.Equalf(t, want, rawdb.ReadBlock(db, hash, n) != nil, "block %d available", n)
+		assert.Equalf(t, want, rawdb.ReadTd(db, hash, n) != nil, "block %d total difficulty available", n)
+		if n > 0 {
+			assert.Equalf(t, want, len(rawdb.ReadRawReceipts(db, hash, n)) > 0, "block %d receipts available", n)
+		}
+	}
+}
+
+func TestHistoryPruning(t *testing.T) {
+	const (
+		numBlocks    = 64
+		ancientLimit = 48
+	)
+	tests := []struct {
+		name      string
+		policy    RecentHistory
+		indexing  bool
+		indexTail *uint64
+		wantTail  uint64
+	}{
+		{
+			name:     "policy",
+			policy:   RecentHistory{Blocks: 40, CheckpointInterval: 10},
+			wantTail: numBlocks + 1 - 40,
+		},
+		{
+			name:     "frozen_limit",
+			policy:   RecentHistory{Blocks: 4, CheckpointInterval: 7},
+			wantTail: ancientLimit, // blocks [0, ancientLimit] are frozen and the last is retained
+		},
+		{
+			name:     "tx_index_unavailable",
+			policy:   RecentHistory{Blocks: 40},
+			indexing: true,
+			wantTail: 0,
+		},
+		{
+			name:      "tx_index_tail",
+			policy:    RecentHistory{Blocks: 40, CheckpointInterval: 5},
+			indexing:  true,
+			indexTail: func() *uint64 { n := uint64(13); return &n }(),
+			wantTail:  13,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newHistoryTestChain(t, numBlocks, ancientLimit)
+			db := chain.open(t)
+			defer func() { require.NoError(t, db.Close()) }()
+
+			if tt.indexTail != nil {
+				rawdb.WriteTxIndexTail(db, *tt.indexTail)
+			}
+			frozen, err := db.Ancients()
+			require.NoError(t, err)
+			require.Greater(t, frozen, tt.wantTail, "frozen blocks; test setup")
+
+			p := &historyPruner{
+				policy:   tt.policy,
+				db:       db,
+				indexing: tt.indexing,
+			}
+			require.NoError(t, p.prune(numBlocks, nil), "prune()")
+			assert.Equal(t, HistoryPruneProgress{Tail: tt.wantTail, Target: tt.wantTail}, p.progress)
+
+			gotTail, err := db.Tail()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTail, gotTail, "ancient tail")
+			chain.assertHistory(t, db, tt.wantTail, tt.policy)
+
+			require.NoError(t, p.prune(numBlocks, nil), "prune() repeated")
+			gotTail, err = db.Tail()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTail, gotTail, "ancient tail after repeated prune()")
+		})
+	}
+
+	t.Run("reopen", func(t *testing.T) {
+		policy := RecentHistory{Blocks: 40, CheckpointInterval: 10}
+		chain := newHistoryTestChain(t, numBlocks, ancientLimit)
+		db := chain.open(t)
+		p := &historyPruner{policy: policy, db: db}
+		require.NoError(t, p.prune(numBlocks, nil), "prune()")
+		require.NoError(t, db.Close())
+
+		db = chain.open(t)
+		defer db.Close()
+		chain.assertHistory(t, db, numBlocks+1-40, policy)
+	})
+}
+
+func TestHistoryPrunerLifecycle(t *testing.T) {
+	const numBlocks = 32
+	chain := newHistoryTestChain(t, numBlocks, numBlocks)
+
+	policy := RecentHistory{Blocks: 8}
+	SetHistoryRetentionPolicy(policy)
+	t.Cleanup(TestOnlyClearHistoryRetentionPolicy)
+
+	db := chain.open(t)
+	defer db.Close()
+	bc, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), chain.gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err, "NewBlockChain()")
+	defer bc.Stop()
+
+	const wantTail = numBlocks + 1 - 8
+	require.Eventually(t, func() bool {
+		p, err := bc.HistoryPruneProgress()
+		require.NoError(t, err, "HistoryPruneProgress()")
+		return p.Done() && p.Tail == wantTail
+	}, 5*time.Second, 10*time.Millisecond, "HistoryPruneProgress()")
+	chain.assertHistory(t, db, wantTail, policy)
+}
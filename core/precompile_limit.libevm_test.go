@@ -0,0 +1,145 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+type nopPrecompile struct{}
+
+func (nopPrecompile) RequiredGas([]byte) uint64  { return 0 }
+func (nopPrecompile) Run([]byte) ([]byte, error) { return nil, nil }
+
+func TestPrecompileInvocationLimitAcrossBlock(t *testing.T) {
+	const limit = 2
+	precompile := common.Address{'l', 'i', 'm'}
+	// The limit is changed between building and importing blocks to simulate
+	// a builder that doesn't enforce the same limit as validators.
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: make(map[common.Address]libevm.PrecompiledContract),
+	}
+	extras := hooks.Register(t)
+	setLimit := func(n uint64) {
+		r := vm.NewPrecompileRegistry()
+		r.RegisterAt(precompile, nopPrecompile{}, nil, vm.WithInvocationLimit(n))
+		hooks.PrecompileOverrides[precompile], _ = r.For(params.Rules{}).PrecompileOverride(precompile)
+	}
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	config := *params.TestChainConfig
+	extras.ChainConfig.Set(&config, hooks)
+	gspec := &core.Genesis{
+		Config:  &config,
+		Alloc:   types.GenesisAlloc{crypto.PubkeyToAddress(key.PublicKey): {Balance: big.NewInt(params.Ether)}},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	engine := ethash.NewFaker()
+
+	// generate builds a single block in which each of `calls` transactions
+	// invokes the limited precompile once.
+	generate := func(t *testing.T, calls int) (*types.Block, types.Receipts) {
+		t.Helper()
+		_, blocks, receipts := core.GenerateChainWithGenesis(gspec, engine, 1, func(_ int, b *core.BlockGen) {
+			signer := types.LatestSigner(gspec.Config)
+			for i := 0; i < calls; i++ {
+				tx := types.MustSignNewTx(key, signer, &types.LegacyTx{
+					Nonce:    uint64(i),
+					To:       &precompile,
+					Gas:      100_000,
+					GasPrice: big.NewInt(10 * params.InitialBaseFee),
+				})
+				b.AddTx(tx)
+			}
+		})
+		return blocks[0], receipts[0]
+	}
+	insert := func(t *testing.T, block *types.Block) error {
+		t.Helper()
+		bc, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, engine, vm.Config{}, nil, nil)
+		require.NoError(t, err, "core.NewBlockChain()")
+		defer bc.Stop()
+		_, err = bc.InsertChain(types.Blocks{block})
+		return err
+	}
+	statuses := func(rs types.Receipts) []uint64 {
+		var s []uint64
+		for _, r := range rs {
+			s = append(s, r.Status)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name         string
+		calls        int
+		builderLimit uint64
+		wantStatuses []uint64
+		wantErr      bool
+	}{
+		{
+			name:         "at_limit",
+			calls:        limit,
+			builderLimit: limit,
+			wantStatuses: []uint64{types.ReceiptStatusSuccessful, types.ReceiptStatusSuccessful},
+		},
+		{
+			name:         "beyond_limit_enforced_by_builder",
+			calls:        limit + 1,
+			builderLimit: limit,
+			wantStatuses: []uint64{types.ReceiptStatusSuccessful, types.ReceiptStatusSuccessful, types.ReceiptStatusFailed},
+		},
+		{
+			name:         "over_limit",
+			calls:        limit + 1,
+			builderLimit: limit + 1,
+			wantStatuses: []uint64{types.ReceiptStatusSuccessful, types.ReceiptStatusSuccessful, types.ReceiptStatusSuccessful},
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLimit(tt.builderLimit)
+			block, receipts := generate(t, tt.calls)
+			require.Equal(t, tt.wantStatuses, statuses(receipts), "receipt statuses of built block")
+
+			setLimit(limit)
+			err := insert(t, block)
+			if tt.wantErr {
+				require.Error(t, err, "InsertChain() of block built beyond limit")
+			} else {
+				require.NoError(t, err, "InsertChain()")
+			}
+		})
+	}
+}
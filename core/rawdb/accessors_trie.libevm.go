@@ -0,0 +1,77 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/testonly"
+)
+
+var registeredStateSchemes = make(map[string]func(ethdb.Reader) bool)
+
+// RegisterStateScheme registers a named state scheme, in addition to
+// [HashScheme] and [PathScheme], that is recognised by [ReadStateScheme] and
+// therefore by [ParseStateScheme]. If `isStored` is non-nil and returns true
+// for a database then `name` is considered the scheme of its persistent state.
+// Registered schemes are checked before the built-in ones, in lexicographical
+// order of name.
+//
+// RegisterStateScheme MUST NOT be called more than once for the same `name`,
+// nor with the name of a built-in scheme. It is not threadsafe and SHOULD be
+// called in an `init()` function. Most users SHOULD call
+// [triedb.RegisterScheme] instead.
+func RegisterStateScheme(name string, isStored func(ethdb.Reader) bool) {
+	switch name {
+	case "", HashScheme, PathScheme:
+		panic(fmt.Sprintf("state scheme %q is reserved", name))
+	}
+	if _, ok := registeredStateSchemes[name]; ok {
+		panic(fmt.Sprintf("state scheme %q already registered", name))
+	}
+	registeredStateSchemes[name] = isStored
+}
+
+// IsRegisteredStateScheme reports whether `name` was registered with
+// [RegisterStateScheme].
+func IsRegisteredStateScheme(name string) bool {
+	_, ok := registeredStateSchemes[name]
+	return ok
+}
+
+// TestOnlyClearRegisteredStateSchemes clears all schemes previously registered
+// with [RegisterStateScheme]. It panics if called from a non-testing call
+// stack.
+func TestOnlyClearRegisteredStateSchemes() {
+	testonly.OrPanic(func() {
+		clear(registeredStateSchemes)
+	})
+}
+
+// readRegisteredStateScheme returns the first registered scheme for which the
+// database holds persistent state, and true, or false if there is none.
+func readRegisteredStateScheme(db ethdb.Reader) (string, bool) {
+	for _, name := range slices.Sorted(maps.Keys(registeredStateSchemes)) {
+		if isStored := registeredStateSchemes[name]; isStored != nil && isStored(db) {
+			return name, true
+		}
+	}
+	return "", false
+}
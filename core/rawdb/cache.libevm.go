@@ -0,0 +1,317 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/lru"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/metrics"
+)
+
+// ReadCacheConfig configures the read-through cache installed by
+// [NewReadCachingDatabase]. Each limit is the maximum total size, in bytes, of
+// the cached values of a particular kind; a zero limit disables caching of
+// that kind.
+type ReadCacheConfig struct {
+	HeaderBytes  int // headers, canonical hashes, and hash->number mappings
+	BodyBytes    int
+	ReceiptBytes int
+	TDBytes      int
+	// Namespace is prepended to the names of the hit and miss counters. If
+	// empty when used via [OpenOptions], [OpenOptions.Namespace] is used.
+	Namespace string
+}
+
+type readCacheKind int
+
+const (
+	readCacheHeaders readCacheKind = iota
+	readCacheBodies
+	readCacheReceipts
+	readCacheTDs
+	numReadCacheKinds
+)
+
+func (k readCacheKind) String() string {
+	switch k {
+	case readCacheHeaders:
+		return "header"
+	case readCacheBodies:
+		return "body"
+	case readCacheReceipts:
+		return "receipts"
+	case readCacheTDs:
+		return "td"
+	default:
+		return "unknown"
+	}
+}
+
+func (c *ReadCacheConfig) limit(k readCacheKind) int {
+	switch k {
+	case readCacheHeaders:
+		return c.HeaderBytes
+	case readCacheBodies:
+		return c.BodyBytes
+	case readCacheReceipts:
+		return c.ReceiptBytes
+	case readCacheTDs:
+		return c.TDBytes
+	default:
+		return 0
+	}
+}
+
+// readCacheKindOf classifies a database key, returning false if values stored
+// under it are never cached.
+func readCacheKindOf(key []byte) (readCacheKind, bool) {
+	const numHash = 8 + common.HashLength
+
+	switch n := len(key); {
+	case n == len(headerPrefix)+numHash && bytes.HasPrefix(key, headerPrefix):
+		return readCacheHeaders, true
+	case n == len(headerPrefix)+8+len(headerHashSuffix) && bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerHashSuffix):
+		return readCacheHeaders, true
+	case n == len(headerNumberPrefix)+common.HashLength && bytes.HasPrefix(key, headerNumberPrefix):
+		return readCacheHeaders, true
+	case n == len(headerPrefix)+numHash+len(headerTDSuffix) && bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerTDSuffix):
+		return readCacheTDs, true
+	case n == len(blockBodyPrefix)+numHash && bytes.HasPrefix(key, blockBodyPrefix):
+		return readCacheBodies, true
+	case n == len(blockReceiptsPrefix)+numHash && bytes.HasPrefix(key, blockReceiptsPrefix):
+		return readCacheReceipts, true
+	}
+	return 0, false
+}
+
+// NewReadCachingDatabase wraps `db` with a read-through cache of the key-value
+// entries backing headers, bodies, receipts, total difficulties, canonical
+// hashes, and hash->number mappings. Only values read with `Get()` are cached;
+// ancient data is always read from the freezer.
+//
+// Every write to a cached key, either directly or via a batch obtained from
+// the returned database, invalidates the respective entry. As reorgs rewrite
+// canonical hashes through the same path, the cache never serves a stale
+// canonical chain. Writes made directly to `db` SHOULD be avoided as they
+// bypass invalidation.
+func NewReadCachingDatabase(db ethdb.Database, cfg ReadCacheConfig) ethdb.Database {
+	c := &readCachingDB{Database: db}
+	for k := readCacheKind(0); k < numReadCacheKinds; k++ {
+		limit := cfg.limit(k)
+		if limit <= 0 {
+			continue
+		}
+		prefix := cfg.Namespace + "readcache/" + k.String() + "/"
+		c.caches[k] = &byteLRU{
+			// Empty values are never cached so the entry count can't exceed
+			// the size limit, which is enforced by [byteLRU.add].
+			lru:    lru.NewBasicLRU[string, []byte](limit),
+			limit:  limit,
+			hits:   metrics.GetOrRegisterCounter(prefix+"hit", nil),
+			misses: metrics.GetOrRegisterCounter(prefix+"miss", nil),
+		}
+	}
+	return c
+}
+
+// withReadCache returns `db` wrapped by [NewReadCachingDatabase] iff
+// `o.ReadCache` is non-nil.
+func (o *OpenOptions) withReadCache(db ethdb.Database) ethdb.Database {
+	if o.ReadCache == nil {
+		return db
+	}
+	cfg := *o.ReadCache
+	if cfg.Namespace == "" {
+		cfg.Namespace = o.Namespace
+	}
+	return NewReadCachingDatabase(db, cfg)
+}
+
+type readCachingDB struct {
+	ethdb.Database
+	caches [numReadCacheKinds]*byteLRU
+}
+
+// cacheFor returns the cache responsible for `key`, which MAY be nil.
+func (db *readCachingDB) cacheFor(key []byte) *byteLRU {
+	k, ok := readCacheKindOf(key)
+	if !ok {
+		return nil
+	}
+	return db.caches[k]
+}
+
+func (db *readCachingDB) Has(key []byte) (bool, error) {
+	if c := db.cacheFor(key); c != nil && c.contains(key) {
+		return true, nil
+	}
+	return db.Database.Has(key)
+}
+
+func (db *readCachingDB) Get(key []byte) ([]byte, error) {
+	c := db.cacheFor(key)
+	if c == nil {
+		return db.Database.Get(key)
+	}
+	if v, ok := c.get(key); ok {
+		c.hits.Inc(1)
+		return common.CopyBytes(v), nil
+	}
+	c.misses.Inc(1)
+
+	gen := c.generation()
+	v, err := db.Database.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	c.add(key, common.CopyBytes(v), gen)
+	return v, nil
+}
+
+func (db *readCachingDB) Put(key, value []byte) error {
+	defer db.invalidate(key)
+	return db.Database.Put(key, value)
+}
+
+func (db *readCachingDB) Delete(key []byte) error {
+	defer db.invalidate(key)
+	return db.Database.Delete(key)
+}
+
+func (db *readCachingDB) invalidate(keys ...[]byte) {
+	for _, k := range keys {
+		if c := db.cacheFor(k); c != nil {
+			c.remove(k)
+		}
+	}
+}
+
+func (db *readCachingDB) NewBatch() ethdb.Batch {
+	return &readCachingBatch{Batch: db.Database.NewBatch(), db: db}
+}
+
+func (db *readCachingDB) NewBatchWithSize(size int) ethdb.Batch {
+	return &readCachingBatch{Batch: db.Database.NewBatchWithSize(size), db: db}
+}
+
+// A readCachingBatch records the cached keys that it writes, invalidating them
+// once the batch is flushed.
+type readCachingBatch struct {
+	ethdb.Batch
+	db      *readCachingDB
+	touched [][]byte
+}
+
+func (b *readCachingBatch) touch(key []byte) {
+	if b.db.cacheFor(key) != nil {
+		b.touched = append(b.touched, common.CopyBytes(key))
+	}
+}
+
+func (b *readCachingBatch) Put(key, value []byte) error {
+	b.touch(key)
+	return b.Batch.Put(key, value)
+}
+
+func (b *readCachingBatch) Delete(key []byte) error {
+	b.touch(key)
+	return b.Batch.Delete(key)
+}
+
+func (b *readCachingBatch) Write() error {
+	defer b.db.invalidate(b.touched...)
+	return b.Batch.Write()
+}
+
+func (b *readCachingBatch) Reset() {
+	b.touched = b.touched[:0]
+	b.Batch.Reset()
+}
+
+// A byteLRU is a least-recently-used cache bounded by the total length of its
+// values.
+type byteLRU struct {
+	mu    sync.Mutex
+	lru   lru.BasicLRU[string, []byte]
+	size  int
+	limit int
+	// gen is incremented on every removal, allowing a reader that missed the
+	// cache to detect a concurrent write before populating it.
+	gen uint64
+
+	hits, misses metrics.Counter
+}
+
+func (c *byteLRU) get(key []byte) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Get(string(key))
+}
+
+func (c *byteLRU) contains(key []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Contains(string(key))
+}
+
+func (c *byteLRU) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// add caches the value iff no removal has occurred since `gen` was obtained
+// from [byteLRU.generation].
+func (c *byteLRU) add(key, val []byte, gen uint64) {
+	if len(val) == 0 || len(val) > c.limit {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+
+	k := string(key)
+	if old, ok := c.lru.Peek(k); ok {
+		c.size -= len(old)
+	}
+	c.lru.Add(k, val)
+	c.size += len(val)
+	for c.size > c.limit {
+		_, old, ok := c.lru.RemoveOldest()
+		if !ok {
+			break
+		}
+		c.size -= len(old)
+	}
+}
+
+func (c *byteLRU) remove(key []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	k := string(key)
+	if old, ok := c.lru.Peek(k); ok {
+		c.size -= len(old)
+		c.lru.Remove(k)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/metrics"
+)
+
+type getCountingDB struct {
+	ethdb.Database
+	gets int
+}
+
+func (db *getCountingDB) Get(key []byte) ([]byte, error) {
+	db.gets++
+	return db.Database.Get(key)
+}
+
+func TestReadCachingDatabase(t *testing.T) {
+	const namespace = "test/readcache/"
+	underlying := &getCountingDB{Database: NewMemoryDatabase()}
+	db := NewReadCachingDatabase(underlying, ReadCacheConfig{
+		HeaderBytes: 1 << 20,
+		BodyBytes:   1, // too small for any body
+		Namespace:   namespace,
+	})
+
+	hdr := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), Extra: []byte("original")}
+	WriteHeader(db, hdr)
+	hash, num := hdr.Hash(), hdr.Number.Uint64()
+
+	// assertGets asserts the number of reads that fell through to the
+	// underlying database after calling `fn()`.
+	assertGets := func(t *testing.T, want int, fn func()) {
+		t.Helper()
+		before := underlying.gets
+		fn()
+		assert.Equalf(t, want, underlying.gets-before, "%T.Get() calls", underlying)
+	}
+
+	t.Run("headers", func(t *testing.T) {
+		assertGets(t, 1, func() {
+			require.Equal(t, hash, ReadHeader(db, hash, num).Hash(), "ReadHeader() on miss")
+		})
+		assertGets(t, 0, func() {
+			require.Equal(t, hash, ReadHeader(db, hash, num).Hash(), "ReadHeader() on hit")
+		})
+		has, err := db.Has(headerKey(num, hash))
+		require.NoError(t, err)
+		assert.True(t, has, "Has(cached header key)")
+
+		assert.Equal(t, int64(1), metrics.GetOrRegisterCounter(namespace+"readcache/header/hit", nil).Snapshot().Count(), "hits")
+		assert.Equal(t, int64(1), metrics.GetOrRegisterCounter(namespace+"readcache/header/miss", nil).Snapshot().Count(), "misses")
+	})
+
+	t.Run("disabled_or_too_large", func(t *testing.T) {
+		WriteBody(db, hash, num, &types.Body{})
+		WriteReceipts(db, hash, num, types.Receipts{})
+		for range 2 {
+			assertGets(t, 1, func() {
+				require.NotNil(t, ReadBodyRLP(db, hash, num))
+			})
+			assertGets(t, 1, func() {
+				require.NotNil(t, ReadReceiptsRLP(db, hash, num))
+			})
+		}
+	})
+
+	t.Run("reorg", func(t *testing.T) {
+		WriteCanonicalHash(db, hash, num)
+		assertGets(t, 1, func() {
+			require.Equal(t, hash, ReadCanonicalHash(db, num))
+		})
+		assertGets(t, 0, func() {
+			require.Equal(t, hash, ReadCanonicalHash(db, num))
+		})
+
+		other := common.Hash{'o', 't', 'h', 'e', 'r'}
+		batch := db.NewBatch()
+		WriteCanonicalHash(batch, other, num)
+		assert.Equal(t, hash, ReadCanonicalHash(db, num), "ReadCanonicalHash() before batch write")
+		require.NoError(t, batch.Write())
+		assertGets(t, 1, func() {
+			assert.Equal(t, other, ReadCanonicalHash(db, num), "ReadCanonicalHash() after reorg")
+		})
+
+		DeleteCanonicalHash(db, num)
+		assert.Zero(t, ReadCanonicalHash(db, num), "ReadCanonicalHash() after deletion")
+	})
+}
+
+func TestOpenWithReadCache(t *testing.T) {
+	db, err := Open(OpenOptions{
+		Directory: t.TempDir(),
+		Ephemeral: true,
+		ReadCache: &ReadCacheConfig{HeaderBytes: 1 << 10},
+	})
+	require.NoError(t, err, "Open()")
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+	assert.IsType(t, &readCachingDB{}, db)
+}
@@ -43,7 +43,9 @@ const (
 // The background thread will keep moving ancient chain segments from key-value
 // database to flat files for saving space on live database.
 type chainFreezer struct {
-	threshold atomic.Uint64 // Number of recent blocks not to freeze (params.FullImmutabilityThreshold apart from tests)
+	threshold  atomic.Uint64          // Number of recent blocks not to freeze (params.FullImmutabilityThreshold apart from tests)
+	boundary   atomic.Pointer[uint64] // libevm: see [SetFreezeBoundary]
+	boundaryMu sync.Mutex             // libevm: serialises [SetFreezeBoundary]
 
 	*Freezer
 	quit    chan struct{}
@@ -123,6 +125,9 @@ func (f *chainFreezer) freeze(db ethdb.KeyValueStore) {
 		}
 		number := ReadHeaderNumber(nfdb, hash)
 		threshold := f.threshold.Load()
+		if number != nil {
+			threshold = f.boundedThreshold(*number, threshold) // libevm
+		}
 		frozen := f.frozen.Load()
 		switch {
 		case number == nil:
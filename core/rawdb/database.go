@@ -234,6 +234,11 @@ func NewDatabaseWithFreezer(db ethdb.KeyValueStore, ancient string, namespace st
 			// match, otherwise we might mix up freezers across chains and destroy both
 			// the freezer and the key-value store.
 			frgenesis, err := frdb.Ancient(ChainFreezerHashTable, 0)
+			if tail, _ := frdb.Tail(); tail > 0 {
+				// libevm: history expiry has pruned the genesis block from the
+				// freezer so it can't be cross-validated.
+				frgenesis, err = kvgenesis, nil
+			}
 			if err != nil {
 				printChainMetadata(db)
 				return nil, fmt.Errorf("failed to retrieve genesis from ancient %v", err)
@@ -285,6 +290,7 @@ func NewDatabaseWithFreezer(db ethdb.KeyValueStore, ancient string, namespace st
 		}
 	}
 	// Freezer is consistent with the key-value database, permit combining the two
+	frdb.loadFreezeBoundary(db) // libevm
 	if !frdb.readonly {
 		frdb.wg.Add(1)
 		go func() {
@@ -367,6 +373,8 @@ type OpenOptions struct {
 	// Ephemeral means that filesystem sync operations should be avoided: data integrity in the face of
 	// a crash is not important. This option should typically be used in tests.
 	Ephemeral bool
+	// ReadCache, if non-nil, enables a read-through cache of hot chain data.
+	ReadCache *ReadCacheConfig // libevm: see [NewReadCachingDatabase]
 }
 
 // openKeyValueDatabase opens a disk-based key-value database, e.g. leveldb or pebble.
@@ -410,14 +418,14 @@ func Open(o OpenOptions) (ethdb.Database, error) {
 		return nil, err
 	}
 	if len(o.AncientsDirectory) == 0 {
-		return kvdb, nil
+		return o.withReadCache(kvdb), nil // libevm
 	}
 	frdb, err := NewDatabaseWithFreezer(kvdb, o.AncientsDirectory, o.Namespace, o.ReadOnly)
 	if err != nil {
 		kvdb.Close()
 		return nil, err
 	}
-	return frdb, nil
+	return o.withReadCache(frdb), nil // libevm
 }
 
 type counter uint64
@@ -453,7 +461,7 @@ func (s *stat) Count() string {
 // InspectDatabase traverses the entire database and checks the size
 // of all different categories of data.
 func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte, opts ...InspectDatabaseOption) error {
-	libevmConfig := options.As[inspectDatabaseConfig](opts...)
+	libevmConfig := options.As[inspectDatabaseConfig](append(schemaInspectOpts(), opts...)...)
 	it := db.NewIterator(keyPrefix, keyStart)
 	defer it.Release()
 
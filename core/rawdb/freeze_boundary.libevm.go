@@ -0,0 +1,127 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/libevm/ethdb"
+)
+
+// freezeBoundaryKey tracks the last value passed to [SetFreezeBoundary] so it
+// is never lowered, even across restarts.
+var freezeBoundaryKey = []byte("libevm-freeze-boundary")
+
+// readFreezeBoundary retrieves the persisted freeze boundary, or nil if none
+// has been set.
+func readFreezeBoundary(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(freezeBoundaryKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// SetFreezeBoundary sets the number of the highest block that MAY be migrated
+// from the key-value store to the ancient store, replacing the default
+// threshold of [params.FullImmutabilityThreshold] blocks below the head. This
+// allows a consensus engine with fast finality to freeze blocks as soon as
+// they are final, instead of after a fixed depth.
+//
+// The boundary MUST only be set to the number of a final block. As freezing
+// is irreversible, it is an error to lower the boundary once set. The
+// background freezer never freezes beyond the current head block, regardless
+// of the boundary. The boundary is persisted in the key-value store and
+// restored when the database is reopened. An error is returned if `db` has no
+// writable chain freezer.
+func SetFreezeBoundary(db ethdb.Database, number uint64) error {
+	f, err := chainFreezerOf(db)
+	if err != nil {
+		return err
+	}
+	f.boundaryMu.Lock()
+	defer f.boundaryMu.Unlock()
+
+	if old := f.boundary.Load(); old != nil && number < *old {
+		return fmt.Errorf("freeze boundary %d below current boundary %d", number, *old)
+	}
+	// Persisting before the in-memory update guarantees that the freezer never
+	// acts on a boundary that would be forgotten by a restart.
+	if err := db.Put(freezeBoundaryKey, encodeBlockNumber(number)); err != nil {
+		return fmt.Errorf("persisting freeze boundary: %v", err)
+	}
+	f.boundary.Store(&number)
+	return nil
+}
+
+// loadFreezeBoundary restores the boundary persisted by [SetFreezeBoundary].
+func (f *chainFreezer) loadFreezeBoundary(db ethdb.KeyValueReader) {
+	if b := readFreezeBoundary(db); b != nil {
+		f.boundary.Store(b)
+	}
+}
+
+// FreezeBoundary returns the value last passed to [SetFreezeBoundary] for
+// the database, and whether such a boundary has been set.
+func FreezeBoundary(db ethdb.Database) (uint64, bool) {
+	f, err := chainFreezerOf(db)
+	if err != nil {
+		return 0, false
+	}
+	if b := f.boundary.Load(); b != nil {
+		return *b, true
+	}
+	return 0, false
+}
+
+var errNoChainFreezer = errors.New("database has no chain freezer")
+
+// chainFreezerOf returns the writable chain freezer backing `db`, looking
+// through wrapping databases returned by this package.
+func chainFreezerOf(db ethdb.Database) (*chainFreezer, error) {
+	switch db := db.(type) {
+	case *readCachingDB:
+		return chainFreezerOf(db.Database)
+	case *freezerdb:
+		f, ok := db.AncientStore.(*chainFreezer)
+		if !ok {
+			return nil, errNoChainFreezer
+		}
+		if f.readonly {
+			return nil, errReadOnly
+		}
+		return f, nil
+	}
+	return nil, errNoChainFreezer
+}
+
+// boundedThreshold returns the freezing threshold, given the head block
+// `number`, that is equivalent to the boundary set by [SetFreezeBoundary], or
+// `threshold` if no boundary has been set.
+func (f *chainFreezer) boundedThreshold(number, threshold uint64) uint64 {
+	b := f.boundary.Load()
+	if b == nil {
+		return threshold
+	}
+	if *b >= number {
+		return 0
+	}
+	return number - *b
+}
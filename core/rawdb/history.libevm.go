@@ -0,0 +1,60 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/ethdb"
+)
+
+// CopyAncientBlock copies all chain-freezer data of block `number` from the
+// ancient store to the key-value store, via `w`. This allows the block to
+// remain readable after the ancient tail is truncated beyond it, as all chain
+// accessors fall back to the key-value store.
+func CopyAncientBlock(db ethdb.AncientReaderOp, w ethdb.KeyValueWriter, number uint64) error {
+	data := make(map[string][]byte)
+	for _, table := range []string{
+		ChainFreezerHashTable,
+		ChainFreezerHeaderTable,
+		ChainFreezerBodiesTable,
+		ChainFreezerReceiptTable,
+		ChainFreezerDifficultyTable,
+	} {
+		d, err := db.Ancient(table, number)
+		if err != nil {
+			return fmt.Errorf("reading ancient %q of block %d: %w", table, number, err)
+		}
+		data[table] = d
+	}
+
+	hash := common.BytesToHash(data[ChainFreezerHashTable])
+	for key, val := range map[string][]byte{
+		string(headerHashKey(number)):          hash.Bytes(),
+		string(headerNumberKey(hash)):          encodeBlockNumber(number),
+		string(headerKey(number, hash)):        data[ChainFreezerHeaderTable],
+		string(blockBodyKey(number, hash)):     data[ChainFreezerBodiesTable],
+		string(blockReceiptsKey(number, hash)): data[ChainFreezerReceiptTable],
+		string(headerTDKey(number, hash)):      data[ChainFreezerDifficultyTable],
+	} {
+		if err := w.Put([]byte(key), val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,329 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/log"
+)
+
+// An IntegrityIssueKind classifies an [IntegrityIssue].
+type IntegrityIssueKind int
+
+// Kinds of issues detected by [VerifyChainIntegrity].
+const (
+	MissingCanonicalHash IntegrityIssueKind = iota + 1
+	MissingHeader
+	HeaderHashMismatch
+	BrokenParentLink
+	MissingHeaderNumber
+	MissingBody
+	TxRootMismatch
+	UncleHashMismatch
+	MissingReceipts
+	ReceiptCountMismatch
+	ReceiptRootMismatch
+	MissingTxLookup
+	AncientKVMismatch
+)
+
+// String returns a human-readable representation of the kind.
+func (k IntegrityIssueKind) String() string {
+	switch k {
+	case MissingCanonicalHash:
+		return "missing canonical hash"
+	case MissingHeader:
+		return "missing header"
+	case HeaderHashMismatch:
+		return "header hash mismatch"
+	case BrokenParentLink:
+		return "broken parent link"
+	case MissingHeaderNumber:
+		return "missing hash->number index"
+	case MissingBody:
+		return "missing body"
+	case TxRootMismatch:
+		return "tx root mismatch"
+	case UncleHashMismatch:
+		return "uncle hash mismatch"
+	case MissingReceipts:
+		return "missing receipts"
+	case ReceiptCountMismatch:
+		return "receipt count mismatch"
+	case ReceiptRootMismatch:
+		return "receipt root mismatch"
+	case MissingTxLookup:
+		return "missing tx lookup entry"
+	case AncientKVMismatch:
+		return "ancient/kv canonical hash mismatch"
+	default:
+		return fmt.Sprintf("unknown %T(%d)", k, int(k))
+	}
+}
+
+// Rebuildable reports whether the kind refers to an index that can be
+// regenerated from other chain data, and is therefore fixed by
+// [WithIndexRepair].
+func (k IntegrityIssueKind) Rebuildable() bool {
+	switch k {
+	case MissingHeaderNumber, MissingTxLookup:
+		return true
+	default:
+		return false
+	}
+}
+
+// An IntegrityIssue is a single problem detected by [VerifyChainIntegrity].
+type IntegrityIssue struct {
+	Kind   IntegrityIssueKind
+	Number uint64
+	Hash   common.Hash // zero if the canonical hash is unknown
+	// TxHash is only set for [MissingTxLookup] issues.
+	TxHash common.Hash
+	// Repaired is true i.f.f. [WithIndexRepair] was used and the issue was
+	// fixed.
+	Repaired bool
+}
+
+// String returns a human-readable representation of the issue.
+func (i *IntegrityIssue) String() string {
+	s := fmt.Sprintf("block %d (%#x): %v", i.Number, i.Hash, i.Kind)
+	if i.Kind == MissingTxLookup {
+		s += fmt.Sprintf(" for tx %#x", i.TxHash)
+	}
+	if i.Repaired {
+		s += " (repaired)"
+	}
+	return s
+}
+
+// A VerifyChainIntegrityOption configures [VerifyChainIntegrity].
+type VerifyChainIntegrityOption = options.Option[integrityConfig]
+
+type integrityConfig struct {
+	newHasher    func() types.TrieHasher
+	repair       bool
+	skipTxLookup bool
+}
+
+func newIntegrityOpt(fn func(*integrityConfig)) VerifyChainIntegrityOption {
+	return options.Func[integrityConfig](fn)
+}
+
+// WithTrieHasher returns an option that enables verification of transaction
+// and receipt roots, using tries returned by `fn`. Typically this will be
+// `trie.NewStackTrie(nil)`, which can't be used as a default because of
+// circular imports. Without this option, roots are not verified.
+func WithTrieHasher(fn func() types.TrieHasher) VerifyChainIntegrityOption {
+	return newIntegrityOpt(func(c *integrityConfig) {
+		c.newHasher = fn
+	})
+}
+
+// WithIndexRepair returns an option that causes indexes that can be rebuilt
+// from other chain data (hash->number and transaction lookups) to be rewritten
+// when found to be missing.
+func WithIndexRepair() VerifyChainIntegrityOption {
+	return newIntegrityOpt(func(c *integrityConfig) {
+		c.repair = true
+	})
+}
+
+// WithoutTxLookupVerification returns an option that disables verification of
+// transaction lookup entries, which is useful if they have been intentionally
+// pruned (e.g. by a transaction-index limit).
+func WithoutTxLookupVerification() VerifyChainIntegrityOption {
+	return newIntegrityOpt(func(c *integrityConfig) {
+		c.skipTxLookup = true
+	})
+}
+
+// VerifyChainIntegrity audits the canonical chain in the inclusive range
+// [from, to], returning all detected issues. For every block it checks:
+//
+//  1. Existence of the canonical hash, header, body and receipts;
+//  2. That the header hashes to the canonical hash and links to its parent;
+//  3. Header-committed roots of the body and receipts (see [WithTrieHasher]);
+//  4. Completeness of hash->number and transaction-lookup indexes; and
+//  5. That canonical hashes below the ancient/key-value boundary agree.
+//
+// The returned error is only non-nil if the audit itself failed; a corrupt
+// database is reported via the returned issues.
+func VerifyChainIntegrity(db ethdb.Database, from, to uint64, opts ...VerifyChainIntegrityOption) ([]*IntegrityIssue, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid range: from (%d) > to (%d)", from, to)
+	}
+	cfg := options.As[integrityConfig](opts...)
+
+	frozen, err := db.Ancients()
+	if err != nil {
+		// Databases without a freezer are perfectly valid.
+		frozen = 0
+	}
+
+	var (
+		issues []*IntegrityIssue
+		batch  = db.NewBatch()
+	)
+	report := func(kind IntegrityIssueKind, num uint64, hash common.Hash) *IntegrityIssue {
+		i := &IntegrityIssue{
+			Kind:   kind,
+			Number: num,
+			Hash:   hash,
+		}
+		issues = append(issues, i)
+		return i
+	}
+
+	var parent common.Hash
+	if from > 0 {
+		parent = ReadCanonicalHash(db, from-1)
+	}
+
+	for num := from; num <= to; num++ {
+		hash := ReadCanonicalHash(db, num)
+		if hash == (common.Hash{}) {
+			report(MissingCanonicalHash, num, hash)
+			parent = hash
+			if num == to { // avoid overflow when to == MaxUint64
+				break
+			}
+			continue
+		}
+
+		if num < frozen {
+			if kv, _ := db.Get(headerHashKey(num)); len(kv) > 0 && !bytes.Equal(kv, hash[:]) {
+				report(AncientKVMismatch, num, hash)
+			}
+		}
+
+		if n := ReadHeaderNumber(db, hash); n == nil || *n != num {
+			i := report(MissingHeaderNumber, num, hash)
+			if cfg.repair {
+				WriteHeaderNumber(batch, hash, num)
+				i.Repaired = true
+			}
+		}
+
+		cfg.verifyBlock(db, batch, report, num, hash, parent)
+
+		parent = hash
+		if num == to {
+			break
+		}
+	}
+
+	if batch.ValueSize() > 0 {
+		if err := batch.Write(); err != nil {
+			return issues, fmt.Errorf("writing repaired indexes: %v", err)
+		}
+	}
+
+	repaired := 0
+	for _, i := range issues {
+		if i.Repaired {
+			repaired++
+		}
+	}
+	log.Info("Verified chain integrity", "from", from, "to", to, "issues", len(issues), "repaired", repaired)
+	return issues, nil
+}
+
+// verifyBlock performs all checks that require the block's header.
+func (cfg *integrityConfig) verifyBlock(
+	db ethdb.Database,
+	batch ethdb.Batch,
+	report func(IntegrityIssueKind, uint64, common.Hash) *IntegrityIssue,
+	num uint64, hash, parent common.Hash,
+) {
+	header := ReadHeader(db, hash, num)
+	if header == nil {
+		report(MissingHeader, num, hash)
+		return
+	}
+	if header.Hash() != hash {
+		report(HeaderHashMismatch, num, hash)
+	}
+	if num > 0 && parent != (common.Hash{}) && header.ParentHash != parent {
+		report(BrokenParentLink, num, hash)
+	}
+
+	body := ReadBody(db, hash, num)
+	if body == nil {
+		report(MissingBody, num, hash)
+	} else {
+		if types.CalcUncleHash(body.Uncles) != header.UncleHash {
+			report(UncleHashMismatch, num, hash)
+		}
+		if cfg.newHasher != nil && types.DeriveSha(types.Transactions(body.Transactions), cfg.newHasher()) != header.TxHash {
+			report(TxRootMismatch, num, hash)
+		}
+		if !cfg.skipTxLookup {
+			for _, tx := range body.Transactions {
+				if hasTxLookupEntry(db, tx.Hash(), num) {
+					continue
+				}
+				i := report(MissingTxLookup, num, hash)
+				i.TxHash = tx.Hash()
+				if cfg.repair {
+					WriteTxLookupEntries(batch, num, []common.Hash{tx.Hash()})
+					i.Repaired = true
+				}
+			}
+		}
+	}
+
+	receipts := ReadRawReceipts(db, hash, num)
+	if receipts == nil {
+		report(MissingReceipts, num, hash)
+		return
+	}
+	if body == nil {
+		return
+	}
+	if len(receipts) != len(body.Transactions) {
+		report(ReceiptCountMismatch, num, hash)
+		return
+	}
+	// Raw receipts are stored without their type, which is part of their
+	// consensus encoding, so it is derived from the respective transactions in
+	// the same manner as [types.Receipts.DeriveFields].
+	for i, tx := range body.Transactions {
+		receipts[i].Type = tx.Type()
+	}
+	if cfg.newHasher != nil && types.DeriveSha(receipts, cfg.newHasher()) != header.ReceiptHash {
+		report(ReceiptRootMismatch, num, hash)
+	}
+}
+
+// hasTxLookupEntry reports whether the transaction is indexed as being in block
+// `num`. The current encoding of block 0 is empty, which [ReadTxLookupEntry]
+// treats as missing, so existence of the key is checked instead.
+func hasTxLookupEntry(db ethdb.Reader, tx common.Hash, num uint64) bool {
+	if num == 0 {
+		data, err := db.Get(txLookupKey(tx))
+		return err == nil && len(data) == 0
+	}
+	n := ReadTxLookupEntry(db, tx)
+	return n != nil && *n == num
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/ethdb"
+)
+
+// writeIntegrityTestChain writes a canonical chain of `n` blocks, each with a
+// single transaction and respective receipt. Transactions in odd-numbered
+// blocks are of type [types.DynamicFeeTxType], the others are legacy.
+func writeIntegrityTestChain(t *testing.T, db ethdb.Database, n int) []*types.Block {
+	t.Helper()
+
+	var (
+		blocks []*types.Block
+		parent common.Hash
+	)
+	for i := 0; i < n; i++ {
+		to := common.Address{byte(i)}
+		var data types.TxData = &types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &to,
+			Value:    big.NewInt(1),
+			Gas:      21_000,
+			GasPrice: big.NewInt(1),
+		}
+		if i%2 == 1 {
+			data = &types.DynamicFeeTx{
+				Nonce:     uint64(i),
+				To:        &to,
+				Value:     big.NewInt(1),
+				Gas:       21_000,
+				GasTipCap: big.NewInt(1),
+				GasFeeCap: big.NewInt(1),
+			}
+		}
+		tx := types.NewTx(data)
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21_000,
+			Logs:              []*types.Log{},
+		}
+		hdr := &types.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(int64(i)),
+		}
+		b := types.NewBlock(hdr, []*types.Transaction{tx}, nil, []*types.Receipt{receipt}, newTestHasher())
+
+		WriteBlock(db, b)
+		WriteCanonicalHash(db, b.Hash(), b.NumberU64())
+		WriteReceipts(db, b.Hash(), b.NumberU64(), types.Receipts{receipt})
+		WriteTxLookupEntriesByBlock(db, b)
+
+		blocks = append(blocks, b)
+		parent = b.Hash()
+	}
+	return blocks
+}
+
+func TestVerifyChainIntegrity(t *testing.T) {
+	db := NewMemoryDatabase()
+	blocks := writeIntegrityTestChain(t, db, 6)
+	last := uint64(len(blocks) - 1)
+
+	opts := []VerifyChainIntegrityOption{
+		WithTrieHasher(func() types.TrieHasher { return newTestHasher() }),
+	}
+	issues, err := VerifyChainIntegrity(db, 0, last, opts...)
+	require.NoError(t, err, "VerifyChainIntegrity() on pristine chain")
+	require.Empty(t, issues, "VerifyChainIntegrity() on pristine chain")
+
+	DeleteHeaderNumber(db, blocks[1].Hash())
+	DeleteTxLookupEntry(db, blocks[2].Transactions()[0].Hash())
+	DeleteBody(db, blocks[3].Hash(), 3)
+	WriteReceipts(db, blocks[4].Hash(), 4, types.Receipts{{Status: types.ReceiptStatusFailed}})
+	DeleteCanonicalHash(db, 5)
+
+	want := []*IntegrityIssue{
+		{Kind: MissingHeaderNumber, Number: 1, Hash: blocks[1].Hash()},
+		{Kind: MissingTxLookup, Number: 2, Hash: blocks[2].Hash(), TxHash: blocks[2].Transactions()[0].Hash()},
+		{Kind: MissingBody, Number: 3, Hash: blocks[3].Hash()},
+		{Kind: ReceiptRootMismatch, Number: 4, Hash: blocks[4].Hash()},
+		{Kind: MissingCanonicalHash, Number: 5},
+	}
+	issues, err = VerifyChainIntegrity(db, 0, last, opts...)
+	require.NoError(t, err, "VerifyChainIntegrity() on corrupted chain")
+	assert.Equal(t, want, issues, "VerifyChainIntegrity() on corrupted chain")
+
+	issues, err = VerifyChainIntegrity(db, 0, last, append(opts, WithIndexRepair())...)
+	require.NoError(t, err, "VerifyChainIntegrity(..., WithIndexRepair())")
+	for _, w := range want {
+		w.Repaired = w.Kind.Rebuildable()
+	}
+	assert.Equal(t, want, issues, "VerifyChainIntegrity(..., WithIndexRepair())")
+
+	issues, err = VerifyChainIntegrity(db, 0, last, opts...)
+	require.NoError(t, err, "VerifyChainIntegrity() after repair")
+	for _, i := range issues {
+		assert.Falsef(t, i.Kind.Rebuildable(), "rebuildable issue %v remains after repair", i)
+	}
+	assert.Len(t, issues, 3, "issues remaining after repair")
+
+	t.Run("without_tx_lookup", func(t *testing.T) {
+		DeleteTxLookupEntry(db, blocks[0].Transactions()[0].Hash())
+		issues, err := VerifyChainIntegrity(db, 0, 0, WithoutTxLookupVerification())
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+	})
+
+	t.Run("broken_parent_link", func(t *testing.T) {
+		db := NewMemoryDatabase()
+		blocks := writeIntegrityTestChain(t, db, 2)
+		orphan := types.NewBlock(&types.Header{Number: big.NewInt(2)}, nil, nil, nil, newTestHasher())
+		WriteBlock(db, orphan)
+		WriteCanonicalHash(db, orphan.Hash(), 2)
+		WriteReceipts(db, orphan.Hash(), 2, nil)
+
+		issues, err := VerifyChainIntegrity(db, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []*IntegrityIssue{{Kind: BrokenParentLink, Number: 2, Hash: orphan.Hash()}}, issues)
+		require.NotEqual(t, blocks[1].Hash(), orphan.ParentHash(), "test setup")
+	})
+
+	t.Run("invalid_range", func(t *testing.T) {
+		_, err := VerifyChainIntegrity(db, 1, 0)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	cockroach "github.com/cockroachdb/pebble"
+
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/ethdb/pebble"
+)
+
+// ErrOverlappingPrefix is returned, wrapped, by [NewPebbleDBWithNamespace] if
+// the requested prefix overlaps with that of another open database sharing the
+// same pebble DB.
+var ErrOverlappingPrefix = errors.New("overlapping key prefix")
+
+// NewPebbleDBWithNamespace returns a database, without a freezer, backed by
+// an already-open pebble DB and confined to keys beginning with `prefix`. A
+// single pebble DB, and therefore its block cache and file handles, MAY be
+// shared by any number of such databases, each with a distinct prefix; no
+// prefix may be a prefix of another's, which is enforced for all databases
+// that are open at the same time.
+//
+// Closing the returned database doesn't close `db`, which remains the
+// responsibility of the caller, but frees `prefix` for reuse. See
+// [pebble.NewFromDB] for available options, which SHOULD include a unique
+// metrics namespace for each database.
+func NewPebbleDBWithNamespace(db *cockroach.DB, prefix []byte, opts ...pebble.Option) (ethdb.Database, error) {
+	release, err := sharedPrefixes.claim(db, string(prefix))
+	if err != nil {
+		return nil, err
+	}
+	return &namespacedPebbleDB{
+		Database: NewTable(NewDatabase(pebble.NewFromDB(db, opts...)), string(prefix)),
+		release:  release,
+	}, nil
+}
+
+// namespacedPebbleDB releases its prefix when closed.
+type namespacedPebbleDB struct {
+	ethdb.Database
+	release func()
+}
+
+func (db *namespacedPebbleDB) Close() error {
+	defer db.release()
+	return db.Database.Close()
+}
+
+// sharedPrefixes tracks the prefixes of all open databases returned by
+// [NewPebbleDBWithNamespace], keyed by their underlying pebble DB.
+var sharedPrefixes = prefixRegistry{
+	byDB: make(map[*cockroach.DB]map[string]struct{}),
+}
+
+type prefixRegistry struct {
+	mu   sync.Mutex
+	byDB map[*cockroach.DB]map[string]struct{}
+}
+
+// claim records `prefix` as in use for `db`, returning an error if it overlaps
+// with an existing one. The returned function releases the prefix and is safe
+// to call multiple times.
+func (r *prefixRegistry) claim(db *cockroach.DB, prefix string) (release func(), _ error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inUse, ok := r.byDB[db]
+	if !ok {
+		inUse = make(map[string]struct{})
+		r.byDB[db] = inUse
+	}
+	for p := range inUse {
+		if strings.HasPrefix(p, prefix) || strings.HasPrefix(prefix, p) {
+			return nil, fmt.Errorf("%w: %q and %q", ErrOverlappingPrefix, prefix, p)
+		}
+	}
+	inUse[prefix] = struct{}{}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			delete(inUse, prefix)
+			if len(inUse) == 0 {
+				delete(r.byDB, db)
+			}
+		})
+	}, nil
+}
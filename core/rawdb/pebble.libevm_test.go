@@ -0,0 +1,98 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ethpebble "github.com/ava-labs/libevm/ethdb/pebble"
+)
+
+func TestNewPebbleDBWithNamespace(t *testing.T) {
+	shared, err := pebble.Open("", &pebble.Options{FS: vfs.NewMem()})
+	require.NoError(t, err, "pebble.Open()")
+
+	evm, err := NewPebbleDBWithNamespace(shared, []byte("evm/"), ethpebble.WithMetricsNamespace("evm/"))
+	require.NoError(t, err, "NewPebbleDBWithNamespace(evm/)")
+	vm, err := NewPebbleDBWithNamespace(shared, []byte("vm/"), ethpebble.WithMetricsNamespace("vm/"))
+	require.NoError(t, err, "NewPebbleDBWithNamespace(vm/)")
+
+	key := []byte("key")
+	require.NoError(t, evm.Put(key, []byte("evm")), "Put()")
+	require.NoError(t, vm.Put(key, []byte("vm")), "Put()")
+
+	got, err := evm.Get(key)
+	require.NoError(t, err, "Get()")
+	assert.Equal(t, []byte("evm"), got, "Get() from first database")
+	got, err = vm.Get(key)
+	require.NoError(t, err, "Get()")
+	assert.Equal(t, []byte("vm"), got, "Get() from second database")
+
+	raw, closer, err := shared.Get([]byte("evm/key"))
+	require.NoError(t, err, "%T.Get(<prefixed key>)", shared)
+	assert.Equal(t, []byte("evm"), raw, "value stored under prefixed key in shared DB")
+	require.NoError(t, closer.Close(), "%T.Close()", closer)
+
+	var keys []string
+	it := vm.NewIterator(nil, nil)
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	it.Release()
+	require.NoError(t, it.Error(), "iterator error")
+	assert.Equal(t, []string{"key"}, keys, "keys iterated over in second database")
+
+	require.NoError(t, evm.Close(), "Close()")
+	got, err = vm.Get(key)
+	require.NoError(t, err, "Get() after closing other database")
+	assert.Equal(t, []byte("vm"), got, "Get() after closing other database")
+
+	require.NoError(t, vm.Close(), "Close()")
+	require.NoError(t, shared.Close(), "%T.Close() after all databases closed", shared)
+}
+
+func TestNewPebbleDBWithNamespaceOverlap(t *testing.T) {
+	shared, err := pebble.Open("", &pebble.Options{FS: vfs.NewMem()})
+	require.NoError(t, err, "pebble.Open()")
+	other, err := pebble.Open("", &pebble.Options{FS: vfs.NewMem()})
+	require.NoError(t, err, "pebble.Open()")
+
+	db, err := NewPebbleDBWithNamespace(shared, []byte("vm/"))
+	require.NoError(t, err, "NewPebbleDBWithNamespace(vm/)")
+
+	for _, prefix := range []string{"vm/", "vm/evm/", "vm", ""} {
+		_, err := NewPebbleDBWithNamespace(shared, []byte(prefix))
+		require.ErrorIsf(t, err, ErrOverlappingPrefix, "NewPebbleDBWithNamespace(%q) while %q open", prefix, "vm/")
+	}
+
+	otherDB, err := NewPebbleDBWithNamespace(other, []byte("vm/"))
+	require.NoError(t, err, "NewPebbleDBWithNamespace(vm/) with different pebble DB")
+
+	require.NoError(t, db.Close(), "Close()")
+	reopened, err := NewPebbleDBWithNamespace(shared, []byte("vm/evm/"))
+	require.NoError(t, err, "NewPebbleDBWithNamespace(vm/evm/) after closing overlapping database")
+
+	require.NoError(t, reopened.Close(), "Close()")
+	require.NoError(t, otherDB.Close(), "Close()")
+	require.NoError(t, shared.Close(), "%T.Close()", shared)
+	require.NoError(t, other.Close(), "%T.Close()", other)
+}
@@ -0,0 +1,318 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/log"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// A PruneOption configures the behaviour of [PruneRange].
+type PruneOption = options.Option[pruneConfig]
+
+type pruneConfig struct {
+	batchSize  int
+	rate       int
+	progress   func(PruneProgress)
+	checkpoint []byte
+}
+
+// DefaultPruneBatchSize is the number of keys deleted per batch by
+// [PruneRange] unless overridden with [WithPruneBatchSize].
+const DefaultPruneBatchSize = 10_000
+
+// WithPruneBatchSize sets the maximum number of keys deleted in each batch,
+// which is also the granularity of progress reports and checkpoints.
+func WithPruneBatchSize(n int) PruneOption {
+	return options.Func[pruneConfig](func(c *pruneConfig) {
+		c.batchSize = n
+	})
+}
+
+// WithPruneRateLimit limits deletion to at most `keysPerSecond`, on average,
+// by pausing between batches. A non-positive value disables rate limiting.
+func WithPruneRateLimit(keysPerSecond int) PruneOption {
+	return options.Func[pruneConfig](func(c *pruneConfig) {
+		c.rate = keysPerSecond
+	})
+}
+
+// WithPruneProgress results in `fn` being called, synchronously on the
+// pruning goroutine, after every batch is written.
+func WithPruneProgress(fn func(PruneProgress)) PruneOption {
+	return options.Func[pruneConfig](func(c *pruneConfig) {
+		c.progress = fn
+	})
+}
+
+// WithPruneCheckpoint makes pruning resumable. The position of the next key
+// to be deleted is stored in the database, under a reserved key derived from
+// `id` that is never pruned, atomically with every batch. If a checkpoint with
+// the same `id` exists when [PruneRange] is called, pruning resumes from it,
+// unless it was recorded for a different prefix or range, in which case the
+// task fails with [ErrPruneCheckpointMismatch]. The checkpoint is deleted once
+// pruning completes. Each concurrent [PruneRange] call MUST use a different
+// `id`.
+func WithPruneCheckpoint(id []byte) PruneOption {
+	return options.Func[pruneConfig](func(c *pruneConfig) {
+		c.checkpoint = common.CopyBytes(id)
+	})
+}
+
+// pruneCheckpointPrefix + id -> RLP([pruneCheckpoint])
+//
+// All keys with this prefix are reserved and skipped by [PruneRange], even if
+// they are in the range being pruned.
+var pruneCheckpointPrefix = []byte("libevm-prune-checkpoint-")
+
+func pruneCheckpointKey(id []byte) []byte {
+	return append(common.CopyBytes(pruneCheckpointPrefix), id...)
+}
+
+// A pruneCheckpoint records the next key to be deleted along with the range
+// being pruned, all as full keys.
+type pruneCheckpoint struct {
+	Prefix, Start, End []byte
+	Bounded            bool // false if End is nil
+	Next               []byte
+}
+
+// ErrPruneCheckpointMismatch is returned by [PruneTask.Wait] if the checkpoint
+// identified via [WithPruneCheckpoint] was recorded for a different prefix or
+// range.
+var ErrPruneCheckpointMismatch = errors.New("prune checkpoint recorded for different range")
+
+// PruneProgress describes the progress of a [PruneTask].
+type PruneProgress struct {
+	Deleted uint64 // keys deleted by this task, excluding before resumption
+	Next    []byte // next key to consider; nil when Done
+	Done    bool
+}
+
+// ErrPruneStopped is returned by [PruneTask.Wait] if the task was stopped
+// before completion.
+var ErrPruneStopped = errors.New("pruning stopped")
+
+// A PruneTask is a background deletion started by [PruneRange].
+type PruneTask struct {
+	quit     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+	err      error
+}
+
+// PruneRange deletes, in a background goroutine, all keys in `db` that begin
+// with `prefix` and for which the remainder of the key is in [start, end). A
+// nil `end` denotes an unbounded range. Keys are deleted in batches, without
+// holding an iterator open across batch writes, so as not to block other
+// database users.
+//
+// The returned task MUST be waited upon before closing `db`.
+func PruneRange(db ethdb.KeyValueStore, prefix, start, end []byte, opts ...PruneOption) *PruneTask {
+	cfg := options.ApplyTo(&pruneConfig{
+		batchSize: DefaultPruneBatchSize,
+	}, opts...)
+	if cfg.batchSize <= 0 {
+		cfg.batchSize = DefaultPruneBatchSize
+	}
+
+	t := &PruneTask{
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	p := &pruner{
+		pruneConfig: cfg,
+		db:          db,
+		prefix:      common.CopyBytes(prefix),
+		start:       append(common.CopyBytes(prefix), start...),
+	}
+	p.next = p.start
+	if end != nil {
+		p.end = append(common.CopyBytes(prefix), end...)
+	}
+	go func() {
+		defer close(t.done)
+		t.err = p.run(t.quit)
+	}()
+	return t
+}
+
+// Stop signals the task to stop after its current batch. It does not wait for
+// the task to return; use [PruneTask.Wait].
+func (t *PruneTask) Stop() {
+	t.stopOnce.Do(func() { close(t.quit) })
+}
+
+// Wait blocks until the task returns, returning [ErrPruneStopped] if it was
+// stopped before completion, and nil on success.
+func (t *PruneTask) Wait() error {
+	<-t.done
+	return t.err
+}
+
+type pruner struct {
+	*pruneConfig
+	db               ethdb.KeyValueStore
+	prefix           []byte
+	start, next, end []byte // full keys; `end` is nil if unbounded
+	deleted          uint64
+}
+
+func (p *pruner) run(quit <-chan struct{}) error {
+	if p.checkpoint != nil {
+		if err := p.resume(); err != nil {
+			return err
+		}
+	}
+	for {
+		start := time.Now()
+		done, err := p.batch()
+		if err != nil {
+			return err
+		}
+		if p.progress != nil {
+			pp := PruneProgress{
+				Deleted: p.deleted,
+				Done:    done,
+			}
+			if !done {
+				pp.Next = common.CopyBytes(p.next)
+			}
+			p.progress(pp)
+		}
+		if done {
+			return nil
+		}
+
+		// Checked before waiting because select{} is random if both are ready.
+		select {
+		case <-quit:
+			return ErrPruneStopped
+		default:
+		}
+		if p.rate <= 0 {
+			continue
+		}
+		wait := time.Duration(p.batchSize)*time.Second/time.Duration(p.rate) - time.Since(start)
+		select {
+		case <-quit:
+			return ErrPruneStopped
+		case <-time.After(max(wait, 0)):
+		}
+	}
+}
+
+// resume advances `p.next` to the stored checkpoint, if any, after confirming
+// that it was recorded for the same range.
+func (p *pruner) resume() error {
+	buf, _ := p.db.Get(pruneCheckpointKey(p.checkpoint))
+	if len(buf) == 0 {
+		return nil
+	}
+	var cp pruneCheckpoint
+	if err := rlp.DecodeBytes(buf, &cp); err != nil {
+		return fmt.Errorf("%w: decoding: %v", ErrPruneCheckpointMismatch, err)
+	}
+	if want := p.newCheckpoint(); !bytes.Equal(cp.Prefix, want.Prefix) ||
+		!bytes.Equal(cp.Start, want.Start) ||
+		!bytes.Equal(cp.End, want.End) ||
+		cp.Bounded != want.Bounded {
+		return fmt.Errorf("%w: id %q", ErrPruneCheckpointMismatch, p.checkpoint)
+	}
+	if bytes.Compare(cp.Next, p.next) > 0 {
+		log.Info("Resuming pruning from checkpoint", "prefix", common.Bytes2Hex(p.prefix), "next", common.Bytes2Hex(cp.Next))
+		p.next = cp.Next
+	}
+	return nil
+}
+
+func (p *pruner) newCheckpoint() *pruneCheckpoint {
+	return &pruneCheckpoint{
+		Prefix:  p.prefix,
+		Start:   p.start,
+		End:     p.end,
+		Bounded: p.end != nil,
+		Next:    p.next,
+	}
+}
+
+// batch deletes the next batch of keys, returning true if the range has been
+// exhausted.
+func (p *pruner) batch() (bool, error) {
+	it := p.db.NewIterator(p.prefix, p.next[len(p.prefix):])
+	var (
+		keys [][]byte
+		last []byte // last key considered, even if reserved
+	)
+	for len(keys) < p.batchSize && it.Next() {
+		key := it.Key()
+		if p.end != nil && bytes.Compare(key, p.end) >= 0 {
+			break
+		}
+		last = common.CopyBytes(key)
+		if bytes.HasPrefix(key, pruneCheckpointPrefix) {
+			continue
+		}
+		keys = append(keys, last)
+	}
+	exhausted := len(keys) < p.batchSize
+	err := it.Error()
+	it.Release()
+	if err != nil {
+		return false, err
+	}
+
+	b := p.db.NewBatch()
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return false, err
+		}
+	}
+	if last != nil {
+		// The successor of the last key, in lexicographical order.
+		p.next = append(last, 0)
+	}
+	if p.checkpoint != nil {
+		var err error
+		if exhausted {
+			err = b.Delete(pruneCheckpointKey(p.checkpoint))
+		} else {
+			var buf []byte
+			buf, err = rlp.EncodeToBytes(p.newCheckpoint())
+			if err == nil {
+				err = b.Put(pruneCheckpointKey(p.checkpoint), buf)
+			}
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	if err := b.Write(); err != nil {
+		return false, fmt.Errorf("writing pruning batch: %v", err)
+	}
+	p.deleted += uint64(len(keys))
+	return exhausted, nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/ethdb"
+)
+
+func TestPruneRange(t *testing.T) {
+	prefix := []byte("prune")
+	key := func(i byte) []byte {
+		return append(append([]byte{}, prefix...), i)
+	}
+	setup := func(t *testing.T) ethdb.KeyValueStore {
+		t.Helper()
+		db := NewMemoryDatabase()
+		for i := range 100 {
+			require.NoError(t, db.Put(key(byte(i)), []byte{1}))
+		}
+		require.NoError(t, db.Put([]byte("other"), []byte{1}))
+		return db
+	}
+	remaining := func(t *testing.T, db ethdb.KeyValueStore) (bytes []byte) {
+		t.Helper()
+		require.NoError(t, IteratePrefix(db, prefix, func(k, _ []byte) error {
+			bytes = append(bytes, k[len(prefix)])
+			return nil
+		}))
+		return bytes
+	}
+	span := func(from, to byte) (s []byte) {
+		for i := from; i < to; i++ {
+			s = append(s, i)
+		}
+		return s
+	}
+
+	// stopAfterFirstBatch starts pruning with a checkpoint and stops it once
+	// the first batch has been written.
+	stopAfterFirstBatch := func(t *testing.T, db ethdb.KeyValueStore, prefix, start, end, id []byte, batchSize int) {
+		t.Helper()
+		proceed := make(chan struct{})
+		reported := make(chan struct{})
+		task := PruneRange(db, prefix, start, end,
+			WithPruneBatchSize(batchSize),
+			WithPruneCheckpoint(id),
+			WithPruneProgress(func(PruneProgress) {
+				reported <- struct{}{}
+				<-proceed
+			}),
+		)
+		<-reported
+		task.Stop()
+		close(proceed)
+		require.ErrorIs(t, task.Wait(), ErrPruneStopped, "Wait() after Stop()")
+	}
+
+	t.Run("bounded", func(t *testing.T) {
+		db := setup(t)
+		var progress []PruneProgress
+		task := PruneRange(db, prefix, []byte{10}, []byte{50},
+			WithPruneBatchSize(7),
+			WithPruneProgress(func(p PruneProgress) { progress = append(progress, p) }),
+		)
+		require.NoError(t, task.Wait(), "Wait()")
+
+		assert.Equal(t, append(span(0, 10), span(50, 100)...), remaining(t, db), "keys remaining")
+		has, err := db.Has([]byte("other"))
+		require.NoError(t, err)
+		assert.True(t, has, "key with other prefix retained")
+
+		require.Len(t, progress, 6, "progress reports; 40 keys in batches of 7")
+		for i, p := range progress[:5] {
+			assert.Equalf(t, uint64(7*(i+1)), p.Deleted, "progress[%d].Deleted", i)
+			assert.Falsef(t, p.Done, "progress[%d].Done", i)
+		}
+		assert.Equal(t, PruneProgress{Deleted: 40, Done: true}, progress[5], "final progress")
+	})
+
+	t.Run("unbounded", func(t *testing.T) {
+		db := setup(t)
+		require.NoError(t, PruneRange(db, prefix, nil, nil).Wait(), "Wait()")
+		assert.Empty(t, remaining(t, db), "keys remaining")
+	})
+
+	t.Run("stop_and_resume", func(t *testing.T) {
+		db := setup(t)
+		id := []byte("test")
+		stopAfterFirstBatch(t, db, prefix, nil, nil, id, 30)
+		assert.Equal(t, span(30, 100), remaining(t, db), "keys remaining after Stop()")
+
+		// Keys before the checkpoint are not revisited.
+		require.NoError(t, db.Put(key(0), []byte{1}))
+		var last PruneProgress
+		task := PruneRange(db, prefix, nil, nil,
+			WithPruneCheckpoint(id),
+			WithPruneProgress(func(p PruneProgress) { last = p }),
+		)
+		require.NoError(t, task.Wait(), "Wait() after resumption")
+		assert.Equal(t, []byte{0}, remaining(t, db), "keys remaining after resumption")
+		assert.Equal(t, uint64(70), last.Deleted, "keys deleted after resumption")
+
+		has, err := db.Has(pruneCheckpointKey(id))
+		require.NoError(t, err)
+		assert.False(t, has, "checkpoint deleted on completion")
+	})
+
+	t.Run("checkpoint_in_pruned_range", func(t *testing.T) {
+		db := setup(t)
+		id := []byte("test")
+		// An empty prefix covers all keys, including the checkpoint.
+		stopAfterFirstBatch(t, db, nil, nil, nil, id, 30)
+		has, err := db.Has(pruneCheckpointKey(id))
+		require.NoError(t, err)
+		require.True(t, has, "checkpoint retained after Stop()")
+
+		require.NoError(t, PruneRange(db, nil, nil, nil, WithPruneCheckpoint(id)).Wait(), "Wait() after resumption")
+		it := db.NewIterator(nil, nil)
+		defer it.Release()
+		assert.False(t, it.Next(), "any keys remaining")
+	})
+
+	t.Run("checkpoint_mismatch", func(t *testing.T) {
+		db := setup(t)
+		id := []byte("test")
+		stopAfterFirstBatch(t, db, prefix, []byte{10}, []byte{90}, id, 30)
+		want := append(span(0, 10), span(40, 100)...)
+		require.Equal(t, want, remaining(t, db), "keys remaining after Stop()")
+
+		for _, r := range []struct {
+			name               string
+			prefix, start, end []byte
+		}{
+			{"prefix", []byte("other"), []byte{10}, []byte{90}},
+			{"start", prefix, []byte{0}, []byte{90}},
+			{"end", prefix, []byte{10}, []byte{80}},
+			{"unbounded", prefix, []byte{10}, nil},
+		} {
+			err := PruneRange(db, r.prefix, r.start, r.end, WithPruneCheckpoint(id)).Wait()
+			assert.ErrorIsf(t, err, ErrPruneCheckpointMismatch, "Wait() with different %s", r.name)
+		}
+		assert.Equal(t, want, remaining(t, db), "keys remaining after mismatched resumptions")
+
+		require.NoError(t, PruneRange(db, prefix, []byte{10}, []byte{90}, WithPruneCheckpoint(id)).Wait(), "Wait() with matching range")
+		assert.Equal(t, append(span(0, 10), span(90, 100)...), remaining(t, db), "keys remaining after resumption")
+	})
+
+	t.Run("rate_limit", func(t *testing.T) {
+		db := setup(t)
+		start := time.Now()
+		require.NoError(t, PruneRange(db, prefix, nil, nil,
+			WithPruneBatchSize(25),
+			WithPruneRateLimit(1000),
+		).Wait(), "Wait()")
+		// 4 batches of 25 keys at 1000 keys/s, with no pause after the last.
+		assert.GreaterOrEqual(t, time.Since(start), 75*time.Millisecond, "duration of rate-limited pruning")
+	})
+}
@@ -0,0 +1,290 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/log"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// A PrefixSpec declares a custom key prefix used by a chain.
+type PrefixSpec struct {
+	Prefix []byte
+	// KeyLen, if non-zero, is the exact length of matching keys, including
+	// the prefix. Otherwise all keys beginning with the prefix match.
+	KeyLen int
+	// Category is the human-readable name under which [InspectDatabase]
+	// reports matching keys.
+	Category string
+}
+
+// Matches returns whether `key` belongs to the prefix.
+func (s PrefixSpec) Matches(key []byte) bool {
+	if s.KeyLen != 0 && len(key) != s.KeyLen {
+		return false
+	}
+	return bytes.HasPrefix(key, s.Prefix)
+}
+
+// overlaps returns whether any key could match both `s` and `t`.
+func (s PrefixSpec) overlaps(t PrefixSpec) bool {
+	if !bytes.HasPrefix(s.Prefix, t.Prefix) && !bytes.HasPrefix(t.Prefix, s.Prefix) {
+		return false
+	}
+	return s.KeyLen == 0 || t.KeyLen == 0 || s.KeyLen == t.KeyLen
+}
+
+// A SchemaEntry is a declaration of a custom key prefix, for registration with
+// [RegisterSchema]. It is typically an [*Accessor].
+type SchemaEntry interface {
+	Spec() PrefixSpec
+}
+
+// RegisterSchema registers the custom key prefixes used by a chain. Keys
+// matching a registered prefix are reported by [InspectDatabase] in their own
+// category instead of as unaccounted data, unless they are already matched by
+// an upstream category. It MUST NOT be called more than once, and it panics if
+// any entry has an empty prefix or category, if two entries share a category,
+// or if any key could match more than one entry.
+func RegisterSchema(entries ...SchemaEntry) {
+	specs := make([]PrefixSpec, len(entries))
+	for i, e := range entries {
+		specs[i] = e.Spec()
+	}
+	if err := validateSchema(specs); err != nil {
+		panic(err)
+	}
+	registeredSchema.MustRegister(specs)
+}
+
+// TestOnlyClearRegisteredSchema clears the entries previously passed to
+// [RegisterSchema]. It panics if called from a non-testing call stack.
+func TestOnlyClearRegisteredSchema() {
+	registeredSchema.TestOnlyClear()
+}
+
+// RegisteredSchema returns the specs of the entries passed to
+// [RegisterSchema], in the order in which they were provided.
+func RegisteredSchema() []PrefixSpec {
+	if !registeredSchema.Registered() {
+		return nil
+	}
+	return slices.Clone(registeredSchema.Get())
+}
+
+var registeredSchema register.AtMostOnce[[]PrefixSpec]
+
+func validateSchema(specs []PrefixSpec) error {
+	categories := make(map[string]bool)
+	for i, s := range specs {
+		switch {
+		case len(s.Prefix) == 0:
+			return fmt.Errorf("schema entry %d (%q) has empty prefix", i, s.Category)
+		case s.Category == "":
+			return fmt.Errorf("schema entry %d (prefix %#x) has empty category", i, s.Prefix)
+		case s.KeyLen != 0 && s.KeyLen < len(s.Prefix):
+			return fmt.Errorf("schema entry %q has key length %d shorter than its prefix", s.Category, s.KeyLen)
+		case categories[s.Category]:
+			return fmt.Errorf("duplicate schema category %q", s.Category)
+		}
+		categories[s.Category] = true
+
+		for _, t := range specs[:i] {
+			if s.overlaps(t) {
+				return fmt.Errorf("schema entries %q and %q overlap", t.Category, s.Category)
+			}
+		}
+	}
+	return nil
+}
+
+// schemaInspectOpts returns options that integrate the registered schema into
+// [InspectDatabase]. Rows are inserted after the last key-value store row.
+func schemaInspectOpts() []InspectDatabaseOption {
+	if !registeredSchema.Registered() {
+		return nil
+	}
+	specs := registeredSchema.Get()
+	stats := make([]DatabaseStat, len(specs))
+	return []InspectDatabaseOption{
+		WithDatabaseStatRecorder(func(key []byte, size common.StorageSize) bool {
+			for i, s := range specs {
+				if s.Matches(key) {
+					stats[i].Add(size)
+					return true
+				}
+			}
+			return false
+		}),
+		WithDatabaseStatsTransformer(func(rows [][]string) [][]string {
+			const kvStore = "Key-Value store"
+			at := 0
+			for i, r := range rows {
+				if len(r) > 0 && r[0] == kvStore {
+					at = i + 1
+				}
+			}
+			add := make([][]string, len(specs))
+			for i, s := range specs {
+				add[i] = []string{kvStore, s.Category, stats[i].Size(), stats[i].Count()}
+			}
+			return slices.Insert(rows, at, add...)
+		}),
+	}
+}
+
+// IteratePrefix calls `fn` with every key-value pair in `db` for which the key
+// begins with `prefix`, in ascending key order, stopping at and returning the
+// first non-nil error returned by either `fn` or the iterator. The slices
+// passed to `fn` MUST NOT be retained as they MAY be reused by the iterator.
+func IteratePrefix(db ethdb.Iteratee, prefix []byte, fn func(key, value []byte) error) error {
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if err := fn(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// A Codec encodes and decodes values stored under a custom prefix.
+type Codec[T any] interface {
+	Encode(T) ([]byte, error)
+	Decode([]byte) (T, error)
+}
+
+// RLPCodec returns a [Codec] that RLP-encodes values.
+func RLPCodec[T any]() Codec[T] {
+	return rlpCodec[T]{}
+}
+
+type rlpCodec[T any] struct{}
+
+func (rlpCodec[T]) Encode(v T) ([]byte, error) {
+	return rlp.EncodeToBytes(v)
+}
+
+func (rlpCodec[T]) Decode(b []byte) (T, error) {
+	var v T
+	err := rlp.DecodeBytes(b, &v)
+	return v, err
+}
+
+// BytesCodec returns a [Codec] that stores values verbatim.
+func BytesCodec() Codec[[]byte] {
+	return bytesCodec{}
+}
+
+type bytesCodec struct{}
+
+func (bytesCodec) Encode(v []byte) ([]byte, error) { return v, nil }
+func (bytesCodec) Decode(b []byte) ([]byte, error) { return common.CopyBytes(b), nil }
+
+// An Accessor provides typed Read, Write, Has, and Delete helpers, in the
+// style of other rawdb accessors, for values stored under a custom prefix.
+// Database keys are the prefix followed by a caller-provided suffix.
+type Accessor[T any] struct {
+	spec  PrefixSpec
+	codec Codec[T]
+}
+
+var _ SchemaEntry = (*Accessor[struct{}])(nil)
+
+// NewAccessor returns an [Accessor] for values stored under the prefix. It
+// SHOULD be passed to [RegisterSchema].
+func NewAccessor[T any](spec PrefixSpec, codec Codec[T]) *Accessor[T] {
+	spec.Prefix = common.CopyBytes(spec.Prefix)
+	return &Accessor[T]{spec, codec}
+}
+
+// Spec implements [SchemaEntry].
+func (a *Accessor[T]) Spec() PrefixSpec {
+	s := a.spec
+	s.Prefix = common.CopyBytes(s.Prefix)
+	return s
+}
+
+// Key returns the database key of the value identified by `suffix`.
+func (a *Accessor[T]) Key(suffix []byte) []byte {
+	return append(common.CopyBytes(a.spec.Prefix), suffix...)
+}
+
+// Has returns whether a value identified by `suffix` exists.
+func (a *Accessor[T]) Has(db ethdb.KeyValueReader, suffix []byte) bool {
+	ok, _ := db.Has(a.Key(suffix))
+	return ok
+}
+
+// Read returns the value identified by `suffix`, and whether it was found.
+// A value that fails to decode is logged and reported as not found.
+func (a *Accessor[T]) Read(db ethdb.KeyValueReader, suffix []byte) (T, bool) {
+	var zero T
+	data, _ := db.Get(a.Key(suffix))
+	if len(data) == 0 {
+		return zero, false
+	}
+	v, err := a.codec.Decode(data)
+	if err != nil {
+		log.Error("Invalid custom-prefix value", "category", a.spec.Category, "suffix", common.Bytes2Hex(suffix), "err", err)
+		return zero, false
+	}
+	return v, true
+}
+
+// Write stores the value identified by `suffix`.
+func (a *Accessor[T]) Write(db ethdb.KeyValueWriter, suffix []byte, v T) {
+	data, err := a.codec.Encode(v)
+	if err != nil {
+		log.Crit("Failed to encode custom-prefix value", "category", a.spec.Category, "err", err)
+	}
+	if err := db.Put(a.Key(suffix), data); err != nil {
+		log.Crit("Failed to store custom-prefix value", "category", a.spec.Category, "err", err)
+	}
+}
+
+// Delete removes the value identified by `suffix`.
+func (a *Accessor[T]) Delete(db ethdb.KeyValueWriter, suffix []byte) {
+	if err := db.Delete(a.Key(suffix)); err != nil {
+		log.Crit("Failed to delete custom-prefix value", "category", a.spec.Category, "err", err)
+	}
+}
+
+// Iterate calls `fn` with the suffix and decoded value of every key matching
+// the [PrefixSpec], in ascending key order, stopping at and returning the
+// first non-nil error, including decoding errors. Suffixes MUST NOT be
+// retained.
+func (a *Accessor[T]) Iterate(db ethdb.Iteratee, fn func(suffix []byte, v T) error) error {
+	return IteratePrefix(db, a.spec.Prefix, func(key, value []byte) error {
+		if !a.spec.Matches(key) {
+			return nil
+		}
+		v, err := a.codec.Decode(value)
+		if err != nil {
+			return fmt.Errorf("decoding %q value at key %#x: %w", a.spec.Category, key, err)
+		}
+		return fn(key[len(a.spec.Prefix):], v)
+	})
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaValue struct {
+	A uint64
+	B []byte
+}
+
+func TestSchema(t *testing.T) {
+	TestOnlyClearRegisteredSchema()
+	t.Cleanup(TestOnlyClearRegisteredSchema)
+
+	var (
+		structs = NewAccessor(PrefixSpec{Prefix: []byte("libevm-s"), Category: "Structs"}, RLPCodec[schemaValue]())
+		fixed   = NewAccessor(PrefixSpec{Prefix: []byte("libevm-f"), KeyLen: 10, Category: "Fixed"}, BytesCodec())
+	)
+	RegisterSchema(structs, fixed)
+	assert.Equal(t, []PrefixSpec{structs.Spec(), fixed.Spec()}, RegisteredSchema(), "RegisteredSchema()")
+
+	db := NewMemoryDatabase()
+	want := map[string]schemaValue{
+		"x": {A: 1, B: []byte{1}},
+		"y": {A: 2, B: []byte{2}},
+	}
+	for k, v := range want {
+		structs.Write(db, []byte(k), v)
+	}
+	fixed.Write(db, []byte("ab"), []byte("fixed"))
+	fixed.Write(db, []byte("abc"), []byte("wrong length"))
+
+	t.Run("accessors", func(t *testing.T) {
+		got, ok := structs.Read(db, []byte("x"))
+		require.True(t, ok, "Read() of existing value")
+		assert.Equal(t, want["x"], got, "Read()")
+		assert.True(t, structs.Has(db, []byte("y")), "Has() of existing value")
+
+		_, ok = structs.Read(db, []byte("z"))
+		assert.False(t, ok, "Read() of missing value")
+
+		require.NoError(t, db.Put(structs.Key([]byte("bad")), []byte{0xff}))
+		_, ok = structs.Read(db, []byte("bad"))
+		assert.False(t, ok, "Read() of undecodable value")
+		structs.Delete(db, []byte("bad"))
+		assert.False(t, structs.Has(db, []byte("bad")), "Has() after Delete()")
+	})
+
+	t.Run("iterate", func(t *testing.T) {
+		got := make(map[string]schemaValue)
+		require.NoError(t, structs.Iterate(db, func(suffix []byte, v schemaValue) error {
+			got[string(suffix)] = v
+			return nil
+		}))
+		assert.Equal(t, want, got, "Iterate() over RLP values")
+
+		var suffixes []string
+		require.NoError(t, fixed.Iterate(db, func(suffix []byte, _ []byte) error {
+			suffixes = append(suffixes, string(suffix))
+			return nil
+		}))
+		assert.Equal(t, []string{"ab"}, suffixes, "Iterate() respects KeyLen")
+
+		errStop := errors.New("stop")
+		var n int
+		err := IteratePrefix(db, []byte("libevm-"), func(_, _ []byte) error {
+			n++
+			return errStop
+		})
+		assert.ErrorIs(t, err, errStop, "IteratePrefix() propagates error")
+		assert.Equal(t, 1, n, "IteratePrefix() stops on error")
+	})
+
+	t.Run("inspect", func(t *testing.T) {
+		var rows [][]string
+		require.NoError(t, InspectDatabase(db, nil, nil,
+			WithSkipFreezers(),
+			WithDatabaseStatsTransformer(func(r [][]string) [][]string {
+				rows = r
+				return r
+			}),
+		))
+		find := func(category string) []string {
+			for _, r := range rows {
+				if r[1] == category {
+					return r
+				}
+			}
+			return nil
+		}
+		s := find("Structs")
+		require.NotNil(t, s, "InspectDatabase() row for %q", "Structs")
+		assert.Equal(t, "Key-Value store", s[0], "database")
+		assert.Equal(t, "2", s[3], "count")
+		f := find("Fixed")
+		require.NotNil(t, f, "InspectDatabase() row for %q", "Fixed")
+		assert.Equal(t, "1", f[3], "count excludes keys of wrong length")
+	})
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []PrefixSpec
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			specs: []PrefixSpec{
+				{Prefix: []byte("a"), KeyLen: 2, Category: "A2"},
+				{Prefix: []byte("ab"), KeyLen: 3, Category: "AB3"},
+				{Prefix: []byte("b"), Category: "B"},
+			},
+		},
+		{
+			name:    "empty_prefix",
+			specs:   []PrefixSpec{{Category: "X"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty_category",
+			specs:   []PrefixSpec{{Prefix: []byte("x")}},
+			wantErr: true,
+		},
+		{
+			name:    "key_shorter_than_prefix",
+			specs:   []PrefixSpec{{Prefix: []byte("xyz"), KeyLen: 2, Category: "X"}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate_category",
+			specs: []PrefixSpec{
+				{Prefix: []byte("x"), Category: "X"},
+				{Prefix: []byte("y"), Category: "X"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "nested_unbounded",
+			specs: []PrefixSpec{
+				{Prefix: []byte("x"), Category: "X"},
+				{Prefix: []byte("xy"), KeyLen: 5, Category: "XY"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "nested_same_length",
+			specs: []PrefixSpec{
+				{Prefix: []byte("x"), KeyLen: 5, Category: "X"},
+				{Prefix: []byte("xy"), KeyLen: 5, Category: "XY"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSchema(tt.specs)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/log"
+)
+
+// txIndexBackfillKey tracks the lowest block whose transactions MUST be
+// indexed, regardless of the configured retention limit.
+var txIndexBackfillKey = []byte("libevm-tx-index-backfill")
+
+// ReadTxIndexBackfill retrieves the lowest block number whose transactions
+// were requested to be indexed in addition to the retention limit, or nil if
+// no such request exists.
+func ReadTxIndexBackfill(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(txIndexBackfillKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteTxIndexBackfill stores the lowest block number whose transactions MUST
+// be indexed, extending the range otherwise implied by the retention limit.
+func WriteTxIndexBackfill(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(txIndexBackfillKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the transaction index backfill target", "err", err)
+	}
+}
+
+// DeleteTxIndexBackfill removes the backfill target, after which indexing
+// reverts to the range implied by the retention limit.
+func DeleteTxIndexBackfill(db ethdb.KeyValueWriter) {
+	if err := db.Delete(txIndexBackfillKey); err != nil {
+		log.Crit("Failed to delete the transaction index backfill target", "err", err)
+	}
+}
+
+// TxIndexRangeError is returned when a transaction is not found amongst the
+// indexed blocks [Tail, Head], either while indexing of earlier blocks is in
+// progress or, if so configured, because earlier blocks are beyond the
+// retention limit. The transaction MAY therefore exist in a block below Tail.
+type TxIndexRangeError struct {
+	Tail, Head uint64
+}
+
+func (e *TxIndexRangeError) Error() string {
+	return fmt.Sprintf("transaction not found in indexed blocks [%d, %d]; earlier blocks are not indexed", e.Tail, e.Head)
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/metrics"
+)
+
+// ErrConcurrentReads is the panic value when a StateDB is modified while
+// concurrent reads are enabled; see [StateDB.EnableConcurrentReads].
+var ErrConcurrentReads = errors.New("modifying StateDB with concurrent reads enabled")
+
+// numReadShards is the number of account-level locks used when concurrent
+// reads are enabled.
+const numReadShards = 64
+
+// concurrentReads holds the locks used when concurrent reads are enabled. Locks
+// are only ever acquired in the order accounts, then any one of the others,
+// which are never held together.
+type concurrentReads struct {
+	// accounts serialise all reads of a single account, including its
+	// storage, code, and storage trie.
+	accounts [numReadShards]sync.Mutex
+	objects  sync.RWMutex // [StateDB.stateObjects]
+	trie     sync.Mutex   // the account trie and the prefetcher
+	err      sync.Mutex   // [StateDB.dbErr]
+
+	prevReadOnly error // restored by [StateDB.DisableConcurrentReads]
+}
+
+// EnableConcurrentReads makes the following methods safe for concurrent use
+// from multiple goroutines, without the need for per-goroutine copies:
+// [StateDB.Exist], [StateDB.Empty], [StateDB.GetBalance], [StateDB.GetNonce],
+// [StateDB.GetStorageRoot], [StateDB.GetCode], [StateDB.GetCodeSize],
+// [StateDB.GetCodeHash], [StateDB.GetState], [StateDB.GetCommittedState],
+// [StateDB.HasSelfDestructed], [StateDB.Error], and [GetExtra]. Reads of
+// different accounts proceed in parallel while those of the same account are
+// serialised.
+//
+// While enabled, the StateDB MUST NOT be modified and any journalled change
+// results in a panic with [ErrConcurrentReads]. Methods not listed above,
+// including those that only read (e.g. [StateDB.Copy]), MUST NOT be called
+// concurrently with any other. Expensive metrics are not collected while
+// concurrent reads are enabled.
+//
+// EnableConcurrentReads and [StateDB.DisableConcurrentReads] MUST NOT be
+// called concurrently with any other method. Enabling more than once is a
+// no-op.
+func (s *StateDB) EnableConcurrentReads() {
+	if s.concurrent != nil {
+		return
+	}
+	s.concurrent = &concurrentReads{
+		prevReadOnly: s.journal.readOnly,
+	}
+	if s.journal.readOnly == nil {
+		s.journal.readOnly = ErrConcurrentReads
+	}
+}
+
+// DisableConcurrentReads reverses [StateDB.EnableConcurrentReads].
+func (s *StateDB) DisableConcurrentReads() {
+	if s.concurrent == nil {
+		return
+	}
+	s.journal.readOnly = s.concurrent.prevReadOnly
+	s.concurrent = nil
+}
+
+// ConcurrentReadsEnabled reports whether [StateDB.EnableConcurrentReads] is in
+// effect.
+func (s *StateDB) ConcurrentReadsEnabled() bool {
+	return s.concurrent != nil
+}
+
+func noopUnlock() {}
+
+// lock locks `mu` i.f.f. concurrent reads are enabled, returning the function
+// to unlock it. Callers on hot paths SHOULD only call it, and the other lock*
+// methods, if `s.concurrent != nil` to avoid the overhead when disabled.
+func (s *StateDB) lock(mu func(*concurrentReads) sync.Locker) (unlock func()) {
+	if s.concurrent == nil {
+		return noopUnlock
+	}
+	l := mu(s.concurrent)
+	l.Lock()
+	return l.Unlock
+}
+
+func (s *StateDB) lockAccount(addr common.Address) (unlock func()) {
+	return s.lock(func(c *concurrentReads) sync.Locker {
+		return &c.accounts[addr[common.AddressLength-1]%numReadShards]
+	})
+}
+
+func (s *StateDB) lockObjects() (unlock func()) {
+	return s.lock(func(c *concurrentReads) sync.Locker { return &c.objects })
+}
+
+func (s *StateDB) lockTrie() (unlock func()) {
+	return s.lock(func(c *concurrentReads) sync.Locker { return &c.trie })
+}
+
+func (s *StateDB) lockErr() (unlock func()) {
+	return s.lock(func(c *concurrentReads) sync.Locker { return &c.err })
+}
+
+// liveStateObject returns the live object at `addr`, if any.
+func (s *StateDB) liveStateObject(addr common.Address) *stateObject {
+	if s.concurrent != nil {
+		defer s.lock(func(c *concurrentReads) sync.Locker { return c.objects.RLocker() })()
+	}
+	return s.stateObjects[addr]
+}
+
+// readAccountTrie reads the account from the account trie, which isn't safe
+// for concurrent use, even for reads.
+func (s *StateDB) readAccountTrie(addr common.Address) (*types.StateAccount, error) {
+	if s.concurrent != nil {
+		defer s.lockTrie()()
+	}
+	return s.trie.GetAccount(addr)
+}
+
+// hashAddress returns the Keccak256 hash of the address, only using the shared
+// hasher if concurrent reads are disabled.
+func (s *StateDB) hashAddress(addr common.Address) common.Hash {
+	if s.concurrent != nil {
+		return crypto.Keccak256Hash(addr.Bytes())
+	}
+	return crypto.HashData(s.hasher, addr.Bytes())
+}
+
+// expensiveMetrics reports whether expensive metrics, which are accumulated
+// without synchronisation, are to be collected.
+func (s *StateDB) expensiveMetrics() bool {
+	return metrics.EnabledExpensive && s.concurrent == nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm"
+)
+
+func TestConcurrentReads(t *testing.T) {
+	sdb, addrs, keys := stateWithPendingChanges(t, 10, 30)
+	// Include some accounts and slots that aren't live objects, which must be
+	// loaded on demand.
+	for i := range 20 {
+		addrs = append(addrs, common.Address{'n', 'e', 'w', byte(i)})
+	}
+	keys = append(keys, common.Hash{'n', 'e', 'w'})
+
+	want := sdb.Copy()
+	readers := make(map[string]func(libevm.StateReader) any)
+	for _, addr := range addrs {
+		for name, fn := range map[string]func(libevm.StateReader) any{
+			"GetBalance":        func(r libevm.StateReader) any { return r.GetBalance(addr) },
+			"GetNonce":          func(r libevm.StateReader) any { return r.GetNonce(addr) },
+			"GetCodeHash":       func(r libevm.StateReader) any { return r.GetCodeHash(addr) },
+			"GetCode":           func(r libevm.StateReader) any { return r.GetCode(addr) },
+			"GetCodeSize":       func(r libevm.StateReader) any { return r.(*StateDB).GetCodeSize(addr) },
+			"GetStorageRoot":    func(r libevm.StateReader) any { return r.(*StateDB).GetStorageRoot(addr) },
+			"HasSelfDestructed": func(r libevm.StateReader) any { return r.HasSelfDestructed(addr) },
+			"Exist":             func(r libevm.StateReader) any { return r.Exist(addr) },
+			"Empty":             func(r libevm.StateReader) any { return r.Empty(addr) },
+		} {
+			readers[fmt.Sprintf("%s(%v)", name, addr)] = fn
+		}
+		for _, key := range keys {
+			readers[fmt.Sprintf("GetState(%v, %v)", addr, key)] = func(r libevm.StateReader) any {
+				return r.GetState(addr, key)
+			}
+			readers[fmt.Sprintf("GetCommittedState(%v, %v)", addr, key)] = func(r libevm.StateReader) any {
+				return r.GetCommittedState(addr, key)
+			}
+		}
+	}
+	wantVals := make(map[string]any, len(readers))
+	for name, fn := range readers {
+		wantVals[name] = fn(want)
+	}
+
+	sdb.EnableConcurrentReads()
+	require.True(t, sdb.ConcurrentReadsEnabled(), "ConcurrentReadsEnabled() after enabling")
+
+	const numWorkers = 8
+	var wg sync.WaitGroup
+	for range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Map iteration order is randomised, so each worker reads in a
+			// different order.
+			for name, fn := range readers {
+				assert.Equalf(t, wantVals[name], fn(sdb), "%T.%s with concurrent reads", sdb, name)
+			}
+			assert.NoErrorf(t, sdb.Error(), "%T.Error()", sdb)
+		}()
+	}
+	wg.Wait()
+
+	t.Run("writes_panic", func(t *testing.T) {
+		addr := addrs[1]
+		for name, fn := range map[string]func(*StateDB){
+			"SetBalance":   func(s *StateDB) { s.SetBalance(addr, uint256.NewInt(1)) },
+			"SetNonce":     func(s *StateDB) { s.SetNonce(addr, 1) },
+			"SetState":     func(s *StateDB) { s.SetState(addr, common.Hash{}, common.Hash{1}) },
+			"SetCode":      func(s *StateDB) { s.SetCode(addr, []byte{1}) },
+			"SelfDestruct": func(s *StateDB) { s.SelfDestruct(addr) },
+			"AddRefund":    func(s *StateDB) { s.AddRefund(1) },
+			"AddLog":       func(s *StateDB) { s.AddLog(&types.Log{}) },
+		} {
+			t.Run(name, func(t *testing.T) {
+				assert.PanicsWithValue(t, ErrConcurrentReads, func() { fn(sdb) })
+			})
+		}
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		sdb.DisableConcurrentReads()
+		require.False(t, sdb.ConcurrentReadsEnabled(), "ConcurrentReadsEnabled() after disabling")
+		sdb.SetBalance(addrs[1], uint256.NewInt(42))
+		assert.Equal(t, uint256.NewInt(42), sdb.GetBalance(addrs[1]), "GetBalance() after write")
+	})
+
+	t.Run("read_only_copy", func(t *testing.T) {
+		c := sdb.CopyForRead()
+		c.EnableConcurrentReads()
+		c.DisableConcurrentReads()
+		assert.PanicsWithValue(t, ErrReadOnlyCopy, func() { c.AddRefund(1) }, "read-only copy remains so")
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+	"maps"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+)
+
+// ErrReadOnlyCopy is the panic value when a copy returned by
+// [StateDB.CopyForRead] is modified.
+var ErrReadOnlyCopy = errors.New("modifying read-only StateDB copy")
+
+// CopyForRead returns a read-only copy of the StateDB, which is significantly
+// cheaper than [StateDB.Copy] when there is a large amount of pending state.
+//
+// Account data, code, and tries are shared with `s` as they are never modified
+// in place. Only the storage values cached by live objects are copied, and none
+// of the block-level change sets, logs, preimages, nor the journal are copied.
+// The copy is therefore unable to be committed and, unlike `s`, MUST NOT be
+// modified; any journalled change (balance, nonce, code, storage, refund, log,
+// access-list, etc.) results in a panic with [ErrReadOnlyCopy]. Reads of
+// accounts or slots not cached by `s` are loaded into the copy's own caches, so
+// the copy MUST NOT be used concurrently, but `s` MAY continue to be modified
+// while the copy is in use.
+func (s *StateDB) CopyForRead() *StateDB {
+	state := &StateDB{
+		db:                   s.db,
+		trie:                 s.db.CopyTrie(s.trie),
+		hasher:               crypto.NewKeccakState(),
+		snaps:                s.snaps,
+		snap:                 s.snap,
+		originalRoot:         s.originalRoot,
+		stateObjects:         make(map[common.Address]*stateObject, len(s.stateObjects)),
+		stateObjectsPending:  make(map[common.Address]struct{}),
+		stateObjectsDirty:    make(map[common.Address]struct{}),
+		stateObjectsDestruct: maps.Clone(s.stateObjectsDestruct),
+		dbErr:                s.dbErr,
+		refund:               s.refund,
+		thash:                s.thash,
+		txIndex:              s.txIndex,
+		logs:                 make(map[common.Hash][]*types.Log),
+		preimages:            make(map[common.Hash][]byte),
+		accessList:           s.accessList.Copy(),
+		transientStorage:     s.transientStorage.Copy(),
+		journal:              newJournal(),
+		trieOpts:             s.trieOpts,
+	}
+	state.journal.readOnly = ErrReadOnlyCopy
+	for addr, obj := range s.stateObjects {
+		state.stateObjects[addr] = obj.readCopy(state)
+	}
+	return state
+}
+
+// readCopy returns a copy of the object for use by [StateDB.CopyForRead].
+// Pending storage is flattened into the origin cache, which is equivalent for
+// reads of both current and committed values, and the storage trie is shared
+// copy-on-write.
+func (s *stateObject) readCopy(db *StateDB) *stateObject {
+	obj := &stateObject{
+		db:             db,
+		address:        s.address,
+		addrHash:       s.addrHash,
+		origin:         s.origin,
+		data:           s.data,
+		code:           s.code,
+		originStorage:  make(Storage, len(s.originStorage)+len(s.pendingStorage)),
+		pendingStorage: make(Storage),
+		dirtyStorage:   maps.Clone(s.dirtyStorage),
+		selfDestructed: s.selfDestructed,
+		deleted:        s.deleted,
+	}
+	if s.trie != nil {
+		obj.trie = db.db.CopyTrie(s.trie)
+	}
+	maps.Copy(obj.originStorage, s.originStorage)
+	maps.Copy(obj.originStorage, s.pendingStorage)
+	return obj
+}
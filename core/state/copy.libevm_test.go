@@ -0,0 +1,210 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm"
+)
+
+// stateWithPendingChanges returns a StateDB with committed, pending (i.e.
+// hashed into the tries but not committed), and dirty (i.e. unfinalised)
+// changes, along with the addresses and storage keys that it touches.
+func stateWithPendingChanges(tb testing.TB, numAccounts, numSlots int) (*StateDB, []common.Address, []common.Hash) {
+	tb.Helper()
+
+	addrs := make([]common.Address, numAccounts)
+	for i := range addrs {
+		addrs[i] = common.BigToAddress(uint256.NewInt(uint64(i + 1)).ToBig())
+	}
+	keys := make([]common.Hash, numSlots)
+	for i := range keys {
+		keys[i] = common.BigToHash(uint256.NewInt(uint64(i + 1)).ToBig())
+	}
+	val := func(i, j, round int) common.Hash {
+		return common.BigToHash(uint256.NewInt(uint64(1_000_000*round + 1000*i + j)).ToBig())
+	}
+
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	sdb, err := New(types.EmptyRootHash, db, nil)
+	require.NoError(tb, err, "New()")
+
+	write := func(round int, filter func(i, j int) bool) {
+		for i, addr := range addrs {
+			sdb.SetNonce(addr, uint64(round))
+			sdb.SetBalance(addr, uint256.NewInt(uint64(round*i)))
+			for j, key := range keys {
+				if filter(i, j) {
+					sdb.SetState(addr, key, val(i, j, round))
+				}
+			}
+		}
+	}
+
+	write(1, func(int, int) bool { return true })
+	sdb.SetCode(addrs[0], []byte{1, 2, 3})
+	root, err := sdb.Commit(1, false)
+	require.NoErrorf(tb, err, "%T.Commit()", sdb)
+
+	sdb, err = New(root, db, nil)
+	require.NoError(tb, err, "New()")
+	write(2, func(_, j int) bool { return j%3 == 0 })
+	sdb.SelfDestruct(addrs[0])
+	sdb.IntermediateRoot(true)
+	write(3, func(_, j int) bool { return j%3 == 1 })
+
+	sdb.SetTxContext(common.Hash{42}, 7)
+	sdb.AddRefund(99)
+	sdb.CreateAccount(addrs[len(addrs)-1])
+
+	return sdb, addrs, keys
+}
+
+func TestCopyForRead(t *testing.T) {
+	sdb, addrs, keys := stateWithPendingChanges(t, 10, 30)
+	// Include some accounts and slots that aren't live objects.
+	addrs = append(addrs, common.Address{'n', 'e', 'w'})
+	keys = append(keys, common.Hash{'n', 'e', 'w'})
+
+	full := sdb.Copy()
+	read := sdb.CopyForRead()
+
+	// [StateDB.Copy] doesn't propagate the tx context.
+	assert.Equal(t, sdb.TxHash(), read.TxHash(), "TxHash()")
+	assert.Equal(t, sdb.TxIndex(), read.TxIndex(), "TxIndex()")
+
+	readers := map[string]func(libevm.StateReader) any{
+		"GetRefund": func(r libevm.StateReader) any { return r.GetRefund() },
+	}
+	for _, addr := range addrs {
+		for name, fn := range map[string]func(libevm.StateReader) any{
+			"GetBalance":        func(r libevm.StateReader) any { return r.GetBalance(addr) },
+			"GetNonce":          func(r libevm.StateReader) any { return r.GetNonce(addr) },
+			"GetCodeHash":       func(r libevm.StateReader) any { return r.GetCodeHash(addr) },
+			"GetCode":           func(r libevm.StateReader) any { return r.GetCode(addr) },
+			"HasSelfDestructed": func(r libevm.StateReader) any { return r.HasSelfDestructed(addr) },
+			"Exist":             func(r libevm.StateReader) any { return r.Exist(addr) },
+			"Empty":             func(r libevm.StateReader) any { return r.Empty(addr) },
+		} {
+			readers[fmt.Sprintf("%s(%v)", name, addr)] = fn
+		}
+		for _, key := range keys {
+			readers[fmt.Sprintf("GetState(%v, %v)", addr, key)] = func(r libevm.StateReader) any {
+				return r.GetState(addr, key)
+			}
+			readers[fmt.Sprintf("GetCommittedState(%v, %v)", addr, key)] = func(r libevm.StateReader) any {
+				return r.GetCommittedState(addr, key)
+			}
+		}
+	}
+
+	for name, fn := range readers {
+		want := fn(full)
+		assert.Equalf(t, want, fn(read), "%T.CopyForRead().%s", sdb, name)
+		assert.Equalf(t, want, fn(sdb), "%T.%s", sdb, name)
+	}
+
+	t.Run("original_unaffected", func(t *testing.T) {
+		sdb.SetState(addrs[1], keys[1], common.Hash{'x'})
+		sdb.SetBalance(addrs[1], uint256.NewInt(0))
+		sdb.IntermediateRoot(true)
+		sdb.CreateAccount(addrs[2])
+
+		for name, fn := range readers {
+			assert.Equalf(t, fn(full), fn(read), "%T.CopyForRead().%s after modifying original", sdb, name)
+		}
+	})
+
+	t.Run("writes_panic", func(t *testing.T) {
+		addr := addrs[1]
+		for name, fn := range map[string]func(*StateDB){
+			"SetBalance":   func(s *StateDB) { s.SetBalance(addr, uint256.NewInt(1)) },
+			"SetNonce":     func(s *StateDB) { s.SetNonce(addr, 1) },
+			"SetState":     func(s *StateDB) { s.SetState(addr, common.Hash{}, common.Hash{1}) },
+			"SetCode":      func(s *StateDB) { s.SetCode(addr, []byte{1}) },
+			"SelfDestruct": func(s *StateDB) { s.SelfDestruct(addr) },
+			"AddRefund":    func(s *StateDB) { s.AddRefund(1) },
+			"AddLog":       func(s *StateDB) { s.AddLog(&types.Log{}) },
+		} {
+			t.Run(name, func(t *testing.T) {
+				assert.PanicsWithValue(t, ErrReadOnlyCopy, func() { fn(sdb.CopyForRead()) })
+			})
+		}
+	})
+}
+
+func TestCopyForReadConcurrent(t *testing.T) {
+	sdb, addrs, keys := stateWithPendingChanges(t, 10, 30)
+
+	const numCopies = 8
+	copies := make([]*StateDB, numCopies)
+	for i := range copies {
+		copies[i] = sdb.CopyForRead()
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range copies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, addr := range addrs {
+				c.GetBalance(addr)
+				for _, key := range keys {
+					c.GetState(addr, key)
+					c.GetCommittedState(addr, key)
+				}
+			}
+		}()
+	}
+	// The original MAY be modified while copies are in use, which is
+	// exercised under the race detector.
+	for i, addr := range addrs {
+		sdb.SetState(addr, keys[i], common.Hash{'x'})
+		sdb.AddBalance(addr, uint256.NewInt(1))
+	}
+	sdb.IntermediateRoot(true)
+	wg.Wait()
+}
+
+func BenchmarkStateDBCopy(b *testing.B) {
+	for _, numSlots := range []int{10, 100, 1000} {
+		sdb, _, _ := stateWithPendingChanges(b, 100, numSlots)
+
+		b.Run(fmt.Sprintf("slots=%d", numSlots), func(b *testing.B) {
+			b.Run("Copy", func(b *testing.B) {
+				for range b.N {
+					sdb.Copy()
+				}
+			})
+			b.Run("CopyForRead", func(b *testing.B) {
+				for range b.N {
+					sdb.CopyForRead()
+				}
+			})
+		})
+	}
+}
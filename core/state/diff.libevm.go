@@ -0,0 +1,277 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/rlp"
+	"github.com/ava-labs/libevm/trie"
+)
+
+// An AccountDiff describes an account that differs between the two state roots
+// passed to [DiffRoots].
+type AccountDiff struct {
+	AddressHash common.Hash
+	// Address is only non-nil if its preimage is known to the [Database].
+	Address  *common.Address
+	Old, New *types.StateAccount // nil if the account is absent
+}
+
+// A StorageDiff describes a storage slot that differs between the two state
+// roots passed to [DiffRoots]. Absent slots have zero values.
+type StorageDiff struct {
+	AddressHash, SlotHash common.Hash
+	Old, New              common.Hash
+}
+
+// A DiffOption configures the behaviour of [DiffRoots].
+type DiffOption = options.Option[diffConfig]
+
+type diffConfig struct {
+	prefix    []byte
+	onAccount func(AccountDiff) error
+	onStorage func(StorageDiff) error
+}
+
+// WithAccountDiffs results in `fn` being called for every account that differs
+// between the roots, in order of address hash. Returning a non-nil error stops
+// the walk and the error is propagated by [DiffRoots].
+func WithAccountDiffs(fn func(AccountDiff) error) DiffOption {
+	return options.Func[diffConfig](func(c *diffConfig) {
+		c.onAccount = fn
+	})
+}
+
+// WithStorageDiffs results in `fn` being called for every storage slot that
+// differs between the roots, in order of address hash and then of slot hash.
+// Slots of an account are reported immediately after the account itself, and
+// all slots of created or deleted accounts are included. Returning a non-nil
+// error stops the walk and the error is propagated by [DiffRoots].
+func WithStorageDiffs(fn func(StorageDiff) error) DiffOption {
+	return options.Func[diffConfig](func(c *diffConfig) {
+		c.onStorage = fn
+	})
+}
+
+// WithAddressHashPrefix limits [DiffRoots] to accounts with address hashes
+// beginning with `prefix`, allowing work to be partitioned.
+func WithAddressHashPrefix(prefix []byte) DiffOption {
+	return options.Func[diffConfig](func(c *diffConfig) {
+		c.prefix = common.CopyBytes(prefix)
+	})
+}
+
+// DiffRoots streams the differences between the states at roots `a` (old) and
+// `b` (new), without replaying the blocks between them; e.g. for audits or
+// analysis of reorgs. Differences are reported via the callbacks set with
+// [WithAccountDiffs] and [WithStorageDiffs], either of which MAY be omitted.
+//
+// Sub-tries common to both roots are skipped, so the cost is proportional to
+// the size of the difference, not of the state. Both roots MUST be available
+// in the [Database].
+func DiffRoots(db Database, a, b common.Hash, opts ...DiffOption) error {
+	cfg := options.As[diffConfig](opts...)
+
+	oldTrie, err := db.OpenTrie(a)
+	if err != nil {
+		return fmt.Errorf("opening account trie %v: %v", a, err)
+	}
+	newTrie, err := db.OpenTrie(b)
+	if err != nil {
+		return fmt.Errorf("opening account trie %v: %v", b, err)
+	}
+	d := &differ{
+		diffConfig: cfg,
+		db:         db,
+		roots:      [2]common.Hash{a, b},
+	}
+	return diffLeaves(oldTrie, newTrie, cfg.prefix, func(key, oldVal, newVal []byte) error {
+		return d.account(newTrie, key, oldVal, newVal)
+	})
+}
+
+type differ struct {
+	*diffConfig
+	db    Database
+	roots [2]common.Hash // old, new
+}
+
+func (d *differ) account(newTrie Trie, key, oldVal, newVal []byte) error {
+	diff := AccountDiff{
+		AddressHash: common.BytesToHash(key),
+	}
+	if pre := newTrie.GetKey(key); len(pre) == common.AddressLength {
+		addr := common.BytesToAddress(pre)
+		diff.Address = &addr
+	}
+
+	var err error
+	if diff.Old, err = decodeAccountLeaf(oldVal); err != nil {
+		return err
+	}
+	if diff.New, err = decodeAccountLeaf(newVal); err != nil {
+		return err
+	}
+	if fn := d.onAccount; fn != nil {
+		if err := fn(diff); err != nil {
+			return err
+		}
+	}
+	if d.onStorage == nil {
+		return nil
+	}
+
+	var storageRoots [2]common.Hash
+	for i, acc := range []*types.StateAccount{diff.Old, diff.New} {
+		storageRoots[i] = types.EmptyRootHash
+		if acc != nil {
+			storageRoots[i] = acc.Root
+		}
+	}
+	if storageRoots[0] == storageRoots[1] {
+		return nil
+	}
+	return d.storage(diff.AddressHash, storageRoots)
+}
+
+func (d *differ) storage(addrHash common.Hash, storageRoots [2]common.Hash) error {
+	var tries [2]Trie
+	for i, root := range storageRoots {
+		tr, err := trie.NewStateTrie(trie.StorageTrieID(d.roots[i], addrHash, root), d.db.TrieDB())
+		if err != nil {
+			return fmt.Errorf("opening storage trie %v of account %v: %v", root, addrHash, err)
+		}
+		tries[i] = tr
+	}
+	return diffLeaves(tries[0], tries[1], nil, func(key, oldVal, newVal []byte) error {
+		diff := StorageDiff{
+			AddressHash: addrHash,
+			SlotHash:    common.BytesToHash(key),
+		}
+		var err error
+		if diff.Old, err = decodeStorageLeaf(oldVal); err != nil {
+			return err
+		}
+		if diff.New, err = decodeStorageLeaf(newVal); err != nil {
+			return err
+		}
+		return d.onStorage(diff)
+	})
+}
+
+func decodeAccountLeaf(val []byte) (*types.StateAccount, error) {
+	if val == nil {
+		return nil, nil
+	}
+	acc := new(types.StateAccount)
+	if err := rlp.DecodeBytes(val, acc); err != nil {
+		return nil, fmt.Errorf("decoding account: %v", err)
+	}
+	return acc, nil
+}
+
+func decodeStorageLeaf(val []byte) (common.Hash, error) {
+	if val == nil {
+		return common.Hash{}, nil
+	}
+	_, content, _, err := rlp.Split(val)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("decoding storage slot: %v", err)
+	}
+	return common.BytesToHash(content), nil
+}
+
+// diffLeaves calls `fn` for every key, beginning with `prefix`, with different
+// values in the two tries, in key order. Absent values are nil.
+func diffLeaves(oldTrie, newTrie Trie, prefix []byte, fn func(key, oldVal, newVal []byte) error) error {
+	// Leaves only in the old trie are those removed or changed, and vice versa.
+	removed, err := differenceLeaves(newTrie, oldTrie, prefix)
+	if err != nil {
+		return err
+	}
+	added, err := differenceLeaves(oldTrie, newTrie, prefix)
+	if err != nil {
+		return err
+	}
+
+	r, rOK := removed.next()
+	a, aOK := added.next()
+	for rOK || aOK {
+		var err error
+		switch cmp := bytes.Compare(r.key, a.key); {
+		case !aOK || (rOK && cmp < 0):
+			err = fn(r.key, r.val, nil)
+			r, rOK = removed.next()
+		case !rOK || cmp > 0:
+			err = fn(a.key, nil, a.val)
+			a, aOK = added.next()
+		default:
+			err = fn(r.key, r.val, a.val)
+			r, rOK = removed.next()
+			a, aOK = added.next()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := removed.it.Err; err != nil {
+		return err
+	}
+	return added.it.Err
+}
+
+// A leafIterator yields the leaves, beginning with a prefix, that are in one
+// trie but not another.
+type leafIterator struct {
+	it     *trie.Iterator
+	prefix []byte
+}
+
+type leaf struct {
+	key, val []byte
+}
+
+func differenceLeaves(a, b Trie, prefix []byte) (*leafIterator, error) {
+	aIt, err := a.NodeIterator(prefix)
+	if err != nil {
+		return nil, err
+	}
+	bIt, err := b.NodeIterator(prefix)
+	if err != nil {
+		return nil, err
+	}
+	diff, _ := trie.NewDifferenceIterator(aIt, bIt)
+	return &leafIterator{
+		it:     trie.NewIterator(diff),
+		prefix: prefix,
+	}, nil
+}
+
+func (l *leafIterator) next() (leaf, bool) {
+	if !l.it.Next() || !bytes.HasPrefix(l.it.Key, l.prefix) {
+		return leaf{}, false
+	}
+	return leaf{
+		key: common.CopyBytes(l.it.Key),
+		val: common.CopyBytes(l.it.Value),
+	}, true
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/triedb"
+)
+
+func TestDiffRoots(t *testing.T) {
+	db := NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), &triedb.Config{Preimages: true})
+
+	var (
+		unchanged = common.Address{'u'}
+		balance   = common.Address{'b'}
+		storage   = common.Address{'s'}
+		deleted   = common.Address{'d'}
+		created   = common.Address{'c'}
+	)
+	slot := func(b byte) common.Hash { return common.Hash{b} }
+
+	commit := func(t *testing.T, parent common.Hash, fn func(*StateDB)) common.Hash {
+		t.Helper()
+		sdb, err := New(parent, db, nil)
+		require.NoError(t, err, "New()")
+		fn(sdb)
+		root, err := sdb.Commit(0, true)
+		require.NoError(t, err, "Commit()")
+		return root
+	}
+
+	a := commit(t, types.EmptyRootHash, func(sdb *StateDB) {
+		for _, addr := range []common.Address{unchanged, balance, storage, deleted} {
+			sdb.SetBalance(addr, uint256.NewInt(1))
+			sdb.SetState(addr, slot(1), slot(1))
+		}
+		sdb.SetState(storage, slot(2), slot(2))
+	})
+	b := commit(t, a, func(sdb *StateDB) {
+		sdb.SetBalance(balance, uint256.NewInt(2))
+		sdb.SetState(storage, slot(1), slot(42))      // modified
+		sdb.SetState(storage, slot(2), common.Hash{}) // cleared
+		sdb.SetState(storage, slot(3), slot(3))       // added
+		sdb.SelfDestruct(deleted)
+		sdb.SetBalance(created, uint256.NewInt(1))
+		sdb.SetState(created, slot(1), slot(1))
+	})
+
+	type diffs struct {
+		accounts map[common.Address][2]*uint256.Int // old and new balances
+		order    []common.Hash
+		storage  []StorageDiff
+	}
+	run := func(t *testing.T, a, b common.Hash, opts ...DiffOption) diffs {
+		t.Helper()
+		got := diffs{
+			accounts: make(map[common.Address][2]*uint256.Int),
+		}
+		opts = append(opts,
+			WithAccountDiffs(func(d AccountDiff) error {
+				require.NotNil(t, d.Address, "AccountDiff.Address with preimages recorded")
+				require.Equal(t, crypto.Keccak256Hash(d.Address[:]), d.AddressHash, "AccountDiff.AddressHash")
+				var bals [2]*uint256.Int
+				for i, acc := range []*types.StateAccount{d.Old, d.New} {
+					if acc != nil {
+						bals[i] = acc.Balance
+					}
+				}
+				got.accounts[*d.Address] = bals
+				got.order = append(got.order, d.AddressHash)
+				return nil
+			}),
+			WithStorageDiffs(func(d StorageDiff) error {
+				got.storage = append(got.storage, d)
+				return nil
+			}),
+		)
+		require.NoError(t, DiffRoots(db, a, b, opts...), "DiffRoots()")
+		return got
+	}
+
+	got := run(t, a, b)
+	one, two := uint256.NewInt(1), uint256.NewInt(2)
+	assert.Equal(t, map[common.Address][2]*uint256.Int{
+		balance: {one, two},
+		storage: {one, one},
+		deleted: {one, nil},
+		created: {nil, one},
+	}, got.accounts, "account diffs")
+	assert.True(t, slices.IsSortedFunc(got.order, common.Hash.Cmp), "accounts reported in order of address hash")
+
+	hash := func(a common.Address) common.Hash { return crypto.Keccak256Hash(a[:]) }
+	slotHash := func(s common.Hash) common.Hash { return crypto.Keccak256Hash(s[:]) }
+	wantStorage := []StorageDiff{
+		{AddressHash: hash(storage), SlotHash: slotHash(slot(1)), Old: slot(1), New: slot(42)},
+		{AddressHash: hash(storage), SlotHash: slotHash(slot(2)), Old: slot(2)},
+		{AddressHash: hash(storage), SlotHash: slotHash(slot(3)), New: slot(3)},
+		{AddressHash: hash(deleted), SlotHash: slotHash(slot(1)), Old: slot(1)},
+		{AddressHash: hash(created), SlotHash: slotHash(slot(1)), New: slot(1)},
+	}
+	sortStorage := func(s []StorageDiff) {
+		slices.SortFunc(s, func(a, b StorageDiff) int {
+			if c := a.AddressHash.Cmp(b.AddressHash); c != 0 {
+				return c
+			}
+			return a.SlotHash.Cmp(b.SlotHash)
+		})
+	}
+	sortStorage(wantStorage)
+	assert.Equal(t, wantStorage, got.storage, "storage diffs")
+
+	t.Run("reversed", func(t *testing.T) {
+		got := run(t, b, a)
+		assert.Equal(t, map[common.Address][2]*uint256.Int{
+			balance: {two, one},
+			storage: {one, one},
+			deleted: {nil, one},
+			created: {one, nil},
+		}, got.accounts, "account diffs")
+	})
+
+	t.Run("same_root", func(t *testing.T) {
+		got := run(t, b, b)
+		assert.Empty(t, got.accounts, "account diffs")
+		assert.Empty(t, got.storage, "storage diffs")
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		prefix := hash(storage).Bytes()[:1]
+		got := run(t, a, b, WithAddressHashPrefix(prefix))
+		for _, h := range got.order {
+			assert.Truef(t, bytes.HasPrefix(h[:], prefix), "address hash %v has prefix %#x", h, prefix)
+		}
+		assert.Contains(t, got.accounts, storage, "account diffs")
+		for _, d := range got.storage {
+			assert.Equal(t, hash(storage), d.AddressHash, "storage diff address hash")
+		}
+	})
+
+	t.Run("callback_error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		var calls int
+		err := DiffRoots(db, a, b, WithAccountDiffs(func(AccountDiff) error {
+			calls++
+			return errStop
+		}))
+		require.ErrorIs(t, err, errStop, "DiffRoots() with erroring callback")
+		assert.Equal(t, 1, calls, "callbacks after error")
+	})
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync/atomic"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/state/snapshot"
+	"github.com/ava-labs/libevm/metrics"
+)
+
+var (
+	existenceSkipMeter          = metrics.NewRegisteredMeter("state/existence/skip", nil)
+	existenceFalsePositiveMeter = metrics.NewRegisteredMeter("state/existence/falsepositive", nil)
+)
+
+// An AccountExistenceFilter is a probabilistic set of account-address hashes,
+// consulted by a [StateDB] before reading an account from the snapshot or the
+// trie. If MayContain returns false then the account is treated as absent
+// without any further lookup.
+//
+// Implementations MUST NOT return false negatives; i.e. MayContain MUST return
+// true for every account that exists under any state root that will be opened
+// with the filter. False positives are permitted, and fall back to the regular
+// lookup. The [StateDB] adds every account that it commits, but a filter MUST
+// be populated with pre-existing state (see [FillAccountExistenceFilter]) and
+// is invalidated by any state written other than via [StateDB.Commit], such as
+// by state sync.
+//
+// Implementations MUST be safe for concurrent use.
+type AccountExistenceFilter interface {
+	MayContain(addrHash common.Hash) bool
+	Add(addrHash common.Hash)
+}
+
+// An AccountExistenceFilterer is an optional interface for a [Database]. If
+// implemented, the returned filter, if non-nil, is consulted by all [StateDB]
+// instances using the Database.
+type AccountExistenceFilterer interface {
+	AccountExistenceFilter() AccountExistenceFilter
+}
+
+// WithAccountExistenceFilter returns a [Database] that is equivalent to `db`
+// but that also implements [AccountExistenceFilterer], returning `f`. It is
+// suitable for use in a [DatabaseInterceptor].
+func WithAccountExistenceFilter(db Database, f AccountExistenceFilter) Database {
+	return &existenceFilteredDatabase{db, f}
+}
+
+type existenceFilteredDatabase struct {
+	Database
+	filter AccountExistenceFilter
+}
+
+func (db *existenceFilteredDatabase) AccountExistenceFilter() AccountExistenceFilter {
+	return db.filter
+}
+
+func (s *StateDB) existenceFilter() AccountExistenceFilter {
+	if f, ok := s.db.(AccountExistenceFilterer); ok {
+		return f.AccountExistenceFilter()
+	}
+	return nil
+}
+
+// accountDefinitelyAbsent reports whether the existence filter, if any, rules
+// out the account at `addr`.
+func (s *StateDB) accountDefinitelyAbsent(addr common.Address) bool {
+	f := s.existenceFilter()
+	if f == nil || f.MayContain(s.hashAddress(addr)) {
+		return false
+	}
+	existenceSkipMeter.Mark(1)
+	return true
+}
+
+// existenceFilterMissed records a lookup of an absent account that the
+// existence filter failed to rule out.
+func (s *StateDB) existenceFilterMissed() {
+	if s.existenceFilter() != nil {
+		existenceFalsePositiveMeter.Mark(1)
+	}
+}
+
+// addToExistenceFilter adds the committed account to the existence filter, if
+// any.
+func (s *StateDB) addToExistenceFilter(addrHash common.Hash) {
+	if f := s.existenceFilter(); f != nil {
+		f.Add(addrHash)
+	}
+}
+
+// FillAccountExistenceFilter adds every account in the state trie with the
+// specified root to `f`, returning the number of accounts added.
+func FillAccountExistenceFilter(f AccountExistenceFilter, db Database, root common.Hash) (uint64, error) {
+	tr, err := db.OpenTrie(root)
+	if err != nil {
+		return 0, err
+	}
+	it, err := tr.NodeIterator(nil)
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	for it.Next(true) {
+		if it.Leaf() {
+			f.Add(common.BytesToHash(it.LeafKey()))
+			n++
+		}
+	}
+	return n, it.Error()
+}
+
+// FillAccountExistenceFilterFromSnapshot is equivalent to
+// [FillAccountExistenceFilter] but reads accounts from a snapshot iterator,
+// which it releases before returning.
+func FillAccountExistenceFilterFromSnapshot(f AccountExistenceFilter, it snapshot.AccountIterator) (uint64, error) {
+	defer it.Release()
+	var n uint64
+	for it.Next() {
+		f.Add(it.Hash())
+		n++
+	}
+	return n, it.Error()
+}
+
+// An AccountBloom is a bloom filter over account-address hashes, implementing
+// [AccountExistenceFilter]. It is safe for concurrent use.
+type AccountBloom struct {
+	words  []atomic.Uint64
+	bits   uint64
+	hashes uint64
+}
+
+var _ AccountExistenceFilter = (*AccountBloom)(nil)
+
+// NewAccountBloom returns an empty [AccountBloom] sized such that, after
+// `expectedAccounts` additions, the false-positive rate is approximately
+// `falsePositiveRate`, which MUST be in the open interval (0,1).
+func NewAccountBloom(expectedAccounts uint64, falsePositiveRate float64) (*AccountBloom, error) {
+	if !(falsePositiveRate > 0 && falsePositiveRate < 1) {
+		return nil, fmt.Errorf("false-positive rate %v not in (0,1)", falsePositiveRate)
+	}
+	n := math.Max(float64(expectedAccounts), 1)
+	bits := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	words := uint64(math.Ceil(bits / 64))
+	hashes := uint64(math.Max(math.Round(bits/n*math.Ln2), 1))
+
+	return &AccountBloom{
+		words:  make([]atomic.Uint64, words),
+		bits:   words * 64,
+		hashes: hashes,
+	}, nil
+}
+
+// SizeBytes returns the memory used by the filter's bit array.
+func (b *AccountBloom) SizeBytes() uint64 {
+	return b.bits / 8
+}
+
+// positions calls `fn` with the word index and bit mask of every bit
+// representing `h`. As `h` is already a cryptographic hash, double hashing
+// derived from it is sufficient.
+func (b *AccountBloom) positions(h common.Hash, fn func(word uint64, mask uint64) bool) {
+	h1 := binary.BigEndian.Uint64(h[:8])
+	h2 := binary.BigEndian.Uint64(h[8:16]) | 1
+	for i := uint64(0); i < b.hashes; i++ {
+		bit := (h1 + i*h2) % b.bits
+		if !fn(bit/64, 1<<(bit%64)) {
+			return
+		}
+	}
+}
+
+// Add adds `h` to the filter.
+func (b *AccountBloom) Add(h common.Hash) {
+	b.positions(h, func(w, mask uint64) bool {
+		b.words[w].Or(mask)
+		return true
+	})
+}
+
+// MayContain returns false only if `h` has never been added to the filter.
+func (b *AccountBloom) MayContain(h common.Hash) bool {
+	found := true
+	b.positions(h, func(w, mask uint64) bool {
+		found = b.words[w].Load()&mask != 0
+		return found
+	})
+	return found
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+)
+
+func TestAccountBloom(t *testing.T) {
+	const (
+		n      = 10_000
+		fpRate = 0.01
+	)
+	b, err := NewAccountBloom(n, fpRate)
+	require.NoError(t, err, "NewAccountBloom()")
+
+	hash := func(i, salt uint64) common.Hash {
+		return crypto.Keccak256Hash(uint256.NewInt(i).Bytes(), uint256.NewInt(salt).Bytes())
+	}
+	for i := uint64(0); i < n; i++ {
+		b.Add(hash(i, 0))
+	}
+	for i := uint64(0); i < n; i++ {
+		require.Truef(t, b.MayContain(hash(i, 0)), "MayContain(added hash %d)", i)
+	}
+
+	var falsePositives int
+	for i := uint64(0); i < n; i++ {
+		if b.MayContain(hash(i, 1)) {
+			falsePositives++
+		}
+	}
+	assert.Lessf(t, float64(falsePositives)/n, 2*fpRate, "false-positive rate after %d additions", n)
+
+	for _, rate := range []float64{0, 1, -1} {
+		_, err := NewAccountBloom(n, rate)
+		assert.Errorf(t, err, "NewAccountBloom(%d, %v)", n, rate)
+	}
+}
+
+// denyAll is an [AccountExistenceFilter] that rules out all accounts,
+// demonstrating that the [StateDB] trusts it.
+type denyAll struct{}
+
+func (denyAll) MayContain(common.Hash) bool { return false }
+func (denyAll) Add(common.Hash)             {}
+
+func TestAccountExistenceFilter(t *testing.T) {
+	bloom, err := NewAccountBloom(100, 0.01)
+	require.NoError(t, err, "NewAccountBloom()")
+
+	base := NewDatabase(rawdb.NewMemoryDatabase())
+	db := WithAccountExistenceFilter(base, bloom)
+
+	present := common.Address{'p'}
+	absent := common.Address{'a'}
+
+	sdb, err := New(types.EmptyRootHash, db, nil)
+	require.NoError(t, err, "New()")
+	sdb.SetBalance(present, uint256.NewInt(1))
+	root, err := sdb.Commit(1, true)
+	require.NoError(t, err, "%T.Commit()", sdb)
+
+	addrHash := crypto.Keccak256Hash(present.Bytes())
+	assert.True(t, bloom.MayContain(addrHash), "committed account added to filter")
+
+	t.Run("no_false_negatives", func(t *testing.T) {
+		sdb, err := New(root, db, nil)
+		require.NoError(t, err, "New()")
+		assert.True(t, sdb.Exist(present), "Exist(committed account)")
+		assert.False(t, sdb.Exist(absent), "Exist(never-created account)")
+		assert.True(t, sdb.Copy().Exist(present), "Exist(committed account) on copy")
+	})
+
+	t.Run("false_positive_fallback", func(t *testing.T) {
+		allow, err := NewAccountBloom(1, 0.01)
+		require.NoError(t, err, "NewAccountBloom()")
+		allow.Add(crypto.Keccak256Hash(absent.Bytes()))
+
+		sdb, err := New(root, WithAccountExistenceFilter(base, allow), nil)
+		require.NoError(t, err, "New()")
+		assert.False(t, sdb.Exist(absent), "Exist(absent account in filter)")
+	})
+
+	t.Run("short_circuit", func(t *testing.T) {
+		sdb, err := New(root, WithAccountExistenceFilter(base, denyAll{}), nil)
+		require.NoError(t, err, "New()")
+		assert.False(t, sdb.Exist(present), "Exist() when ruled out by filter")
+	})
+
+	t.Run("fill_from_trie", func(t *testing.T) {
+		fresh, err := NewAccountBloom(100, 0.01)
+		require.NoError(t, err, "NewAccountBloom()")
+		n, err := FillAccountExistenceFilter(fresh, db, root)
+		require.NoError(t, err, "FillAccountExistenceFilter()")
+		assert.Equal(t, uint64(1), n, "number of accounts added")
+		assert.True(t, fresh.MayContain(addrHash), "MayContain(account in trie)")
+	})
+}
@@ -37,6 +37,8 @@ type journalEntry interface {
 type journal struct {
 	entries []journalEntry         // Current changes tracked by the journal
 	dirties map[common.Address]int // Dirty accounts and the number of changes
+
+	readOnly error // libevm: non-nil panic value; see [StateDB.CopyForRead] and [StateDB.EnableConcurrentReads]
 }
 
 // newJournal creates a new initialized journal.
@@ -48,6 +50,9 @@ func newJournal() *journal {
 
 // append inserts a new modification entry to the end of the change journal.
 func (j *journal) append(entry journalEntry) {
+	if j.readOnly != nil { // libevm
+		panic(j.readOnly)
+	}
 	j.entries = append(j.entries, entry)
 	if addr := entry.dirtied(); addr != nil {
 		j.dirties[*addr]++
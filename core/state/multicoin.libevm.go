@@ -0,0 +1,197 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// AssetBalances are the per-asset (i.e. multi-coin) balances of an account,
+// keyed by asset ID. They are intended to be carried as a field of the
+// StateAccount extra payload registered with [types.RegisterExtras], which
+// MUST implement [MultiCoinPayload].
+//
+// AssetBalances are immutable; [AssetBalances.With] returns a modified copy.
+// Their zero value holds no balances and is equivalent to a set of only zero
+// balances, which are never stored.
+type AssetBalances struct {
+	bals map[common.Hash]*uint256.Int
+}
+
+// Balance returns a copy of the balance of the asset, which is zero if there
+// is no balance.
+func (b AssetBalances) Balance(assetID common.Hash) *uint256.Int {
+	if bal, ok := b.bals[assetID]; ok {
+		return new(uint256.Int).Set(bal)
+	}
+	return new(uint256.Int)
+}
+
+// With returns a copy of `b` with the balance of the asset set.
+func (b AssetBalances) With(assetID common.Hash, balance *uint256.Int) AssetBalances {
+	bals := maps.Clone(b.bals)
+	if balance.IsZero() {
+		delete(bals, assetID)
+	} else {
+		if bals == nil {
+			bals = make(map[common.Hash]*uint256.Int)
+		}
+		bals[assetID] = new(uint256.Int).Set(balance)
+	}
+	if len(bals) == 0 {
+		return AssetBalances{}
+	}
+	return AssetBalances{bals}
+}
+
+// AssetIDs returns the IDs of all assets with non-zero balances, in ascending
+// order.
+func (b AssetBalances) AssetIDs() []common.Hash {
+	return slices.SortedFunc(maps.Keys(b.bals), func(x, y common.Hash) int {
+		return bytes.Compare(x[:], y[:])
+	})
+}
+
+// assetBalance is the RLP representation of a single entry in
+// [AssetBalances].
+type assetBalance struct {
+	ID      common.Hash
+	Balance *uint256.Int
+}
+
+// EncodeRLP implements the [rlp.Encoder] interface, encoding balances as a
+// list of (ID, balance) pairs in ascending order of ID.
+func (b AssetBalances) EncodeRLP(w io.Writer) error {
+	ids := b.AssetIDs()
+	list := make([]assetBalance, len(ids))
+	for i, id := range ids {
+		list[i] = assetBalance{id, b.bals[id]}
+	}
+	return rlp.Encode(w, list)
+}
+
+// DecodeRLP implements the [rlp.Decoder] interface.
+func (b *AssetBalances) DecodeRLP(s *rlp.Stream) error {
+	var list []assetBalance
+	if err := s.Decode(&list); err != nil {
+		return err
+	}
+	var out AssetBalances
+	for i, e := range list {
+		if i > 0 && bytes.Compare(list[i-1].ID[:], e.ID[:]) >= 0 {
+			return fmt.Errorf("%T RLP not in strictly ascending order of ID", b)
+		}
+		if e.Balance.IsZero() {
+			return fmt.Errorf("%T RLP includes zero balance for asset %v", b, e.ID)
+		}
+		out = out.With(e.ID, e.Balance)
+	}
+	*b = out
+	return nil
+}
+
+// A MultiCoinPayload is a StateAccount extra payload that carries
+// [AssetBalances]. WithAssetBalances MUST NOT modify its receiver, and both
+// methods MUST accept the zero value of `SA` (e.g. a nil pointer) as their
+// receiver.
+type MultiCoinPayload[SA any] interface {
+	AssetBalances() AssetBalances
+	WithAssetBalances(AssetBalances) SA
+}
+
+// RegisterMultiCoin enables the [StateDB] multi-coin methods, e.g.
+// [StateDB.GetBalanceOf], with balances stored in the `SA` payload, which MUST
+// be the same type as that passed to [types.RegisterExtras]. It is expected to
+// be called in an `init()` function and MUST NOT be called more than once.
+func RegisterMultiCoin[SA MultiCoinPayload[SA]]() {
+	registeredMultiCoin.MustRegister(multiCoin[SA]{
+		extra: RegisterAccountExtraAccessor[SA](),
+	})
+}
+
+// TestOnlyClearMultiCoin clears the registration made by [RegisterMultiCoin].
+// It panics if called from a non-testing call stack.
+func TestOnlyClearMultiCoin() {
+	registeredMultiCoin.TestOnlyClear()
+}
+
+var registeredMultiCoin register.AtMostOnce[multiCoinHooks]
+
+type multiCoinHooks interface {
+	balances(*StateDB, common.Address) AssetBalances
+	setBalances(*StateDB, common.Address, AssetBalances)
+}
+
+type multiCoin[SA MultiCoinPayload[SA]] struct {
+	extra AccountExtraAccessor[SA]
+}
+
+func (m multiCoin[SA]) balances(s *StateDB, addr common.Address) AssetBalances {
+	return m.extra.Get(s, addr).AssetBalances()
+}
+
+func (m multiCoin[SA]) setBalances(s *StateDB, addr common.Address, b AssetBalances) {
+	m.extra.Set(s, addr, m.extra.Get(s, addr).WithAssetBalances(b))
+}
+
+func multiCoinOrPanic() multiCoinHooks {
+	r := &registeredMultiCoin
+	if !r.Registered() {
+		panic("state.RegisterMultiCoin() not called")
+	}
+	return r.Get()
+}
+
+// GetBalanceOf returns the account's balance of the asset. It panics if
+// [RegisterMultiCoin] hasn't been called.
+func (s *StateDB) GetBalanceOf(addr common.Address, assetID common.Hash) *uint256.Int {
+	return multiCoinOrPanic().balances(s, addr).Balance(assetID)
+}
+
+// AddBalanceOf adds `amount` to the account's balance of the asset. As with
+// [StateDB.AddBalance], the change is journaled. A zero `amount` is a no-op.
+// AddBalanceOf panics if [RegisterMultiCoin] hasn't been called.
+func (s *StateDB) AddBalanceOf(addr common.Address, assetID common.Hash, amount *uint256.Int) {
+	s.modifyBalanceOf(addr, assetID, amount, (*uint256.Int).Add)
+}
+
+// SubBalanceOf subtracts `amount` from the account's balance of the asset.
+// Callers MUST first check that the balance is sufficient as, like
+// [StateDB.SubBalance], the result otherwise underflows. See
+// [StateDB.AddBalanceOf] re journaling, zero amounts, and panics.
+func (s *StateDB) SubBalanceOf(addr common.Address, assetID common.Hash, amount *uint256.Int) {
+	s.modifyBalanceOf(addr, assetID, amount, (*uint256.Int).Sub)
+}
+
+func (s *StateDB) modifyBalanceOf(addr common.Address, assetID common.Hash, amount *uint256.Int, op func(z, x, y *uint256.Int) *uint256.Int) {
+	mc := multiCoinOrPanic()
+	if amount.IsZero() {
+		return
+	}
+	bals := mc.balances(s, addr)
+	mc.setBalances(s, addr, bals.With(assetID, op(new(uint256.Int), bals.Balance(assetID), amount)))
+}
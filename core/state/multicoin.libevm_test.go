@@ -0,0 +1,118 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state_test
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+type multiCoinAccount struct {
+	Balances state.AssetBalances
+}
+
+func (a multiCoinAccount) AssetBalances() state.AssetBalances { return a.Balances }
+
+func (a multiCoinAccount) WithAssetBalances(b state.AssetBalances) multiCoinAccount {
+	a.Balances = b
+	return a
+}
+
+func TestMultiCoin(t *testing.T) {
+	types.TestOnlyClearRegisteredExtras()
+	t.Cleanup(types.TestOnlyClearRegisteredExtras)
+	types.RegisterExtras[
+		types.NOOPHeaderHooks, *types.NOOPHeaderHooks,
+		types.NOOPBlockBodyHooks, *types.NOOPBlockBodyHooks,
+		multiCoinAccount,
+	]()
+
+	views := newWithSnaps(t)
+	db := views.newStateDB(t, types.EmptyRootHash)
+	var (
+		addr         = common.Address{'a'}
+		assetA       = common.Hash{'A'}
+		assetB       = common.Hash{'B'}
+		wantBalances = func(t *testing.T, db *state.StateDB, a, b uint64) {
+			t.Helper()
+			assert.Equal(t, uint256.NewInt(a), db.GetBalanceOf(addr, assetA), "GetBalanceOf(A)")
+			assert.Equal(t, uint256.NewInt(b), db.GetBalanceOf(addr, assetB), "GetBalanceOf(B)")
+		}
+	)
+
+	state.TestOnlyClearMultiCoin()
+	t.Cleanup(state.TestOnlyClearMultiCoin)
+	require.Panics(t, func() { db.GetBalanceOf(addr, assetA) }, "GetBalanceOf() before RegisterMultiCoin()")
+	state.RegisterMultiCoin[multiCoinAccount]()
+
+	wantBalances(t, db, 0, 0)
+	db.AddBalanceOf(addr, assetA, uint256.NewInt(10))
+	db.AddBalanceOf(addr, assetB, uint256.NewInt(20))
+	wantBalances(t, db, 10, 20)
+	assert.True(t, db.GetBalance(addr).IsZero(), "native balance unaffected")
+
+	snap := db.Snapshot()
+	db.SubBalanceOf(addr, assetA, uint256.NewInt(10))
+	db.AddBalanceOf(addr, assetB, uint256.NewInt(1))
+	wantBalances(t, db, 0, 21)
+	db.RevertToSnapshot(snap)
+	wantBalances(t, db, 10, 20)
+
+	cp := db.Copy()
+	cp.AddBalanceOf(addr, assetA, uint256.NewInt(1))
+	wantBalances(t, db, 10, 20)
+	wantBalances(t, cp, 11, 20)
+
+	root, err := db.Commit(1, false)
+	require.NoErrorf(t, err, "%T.Commit()", db)
+	reopened := views.newStateDB(t, root)
+	wantBalances(t, reopened, 10, 20)
+
+	t.Run("empty_after_spending", func(t *testing.T) {
+		db := reopened
+		db.SubBalanceOf(addr, assetA, uint256.NewInt(10))
+		db.SubBalanceOf(addr, assetB, uint256.NewInt(20))
+		assert.True(t, db.Empty(addr), "Empty() after spending all assets")
+	})
+}
+
+func TestAssetBalancesRLP(t *testing.T) {
+	var b state.AssetBalances
+	b = b.With(common.Hash{2}, uint256.NewInt(2)).With(common.Hash{1}, uint256.NewInt(1))
+
+	buf, err := rlp.EncodeToBytes(b)
+	require.NoError(t, err, "rlp.EncodeToBytes()")
+	var got state.AssetBalances
+	require.NoError(t, rlp.DecodeBytes(buf, &got), "rlp.DecodeBytes()")
+	assert.Equal(t, b, got, "round trip")
+	assert.Equal(t, []common.Hash{{1}, {2}}, got.AssetIDs(), "AssetIDs()")
+
+	unordered, err := rlp.EncodeToBytes([]any{
+		[]any{common.Hash{2}, uint256.NewInt(2)},
+		[]any{common.Hash{1}, uint256.NewInt(1)},
+	})
+	require.NoError(t, err)
+	require.Error(t, rlp.DecodeBytes(unordered, new(state.AssetBalances)), "decoding unordered IDs")
+}
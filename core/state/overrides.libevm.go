@@ -0,0 +1,116 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+// An OverrideAccount specifies the fields of an account to override, typically
+// for the `stateOverrides` argument of `eth_call` and similar RPC methods. Nil
+// fields are left unchanged, while non-nil, empty values (e.g. an empty code
+// slice or storage map) clear the respective field.
+//
+// At most one of State and StateDiff MAY be non-nil. State replaces the entire
+// storage of the account while StateDiff only sets the specified slots.
+type OverrideAccount struct {
+	Nonce     *uint64
+	Code      *[]byte
+	Balance   *uint256.Int
+	State     map[common.Hash]common.Hash
+	StateDiff map[common.Hash]common.Hash
+	// MovePrecompileTo, if non-nil, moves the precompile at the overridden
+	// address to the specified one; see [MovePrecompiles].
+	MovePrecompileTo *common.Address
+}
+
+// An OverrideSet is a collection of overridden accounts.
+type OverrideSet map[common.Address]OverrideAccount
+
+// ApplyOverrides applies the overrides to the [StateDB] and then finalises it,
+// such that the overrides behave as if they were made by a transaction
+// immediately preceding any subsequent execution. Precompile moves are ignored
+// as they don't affect state; see [MovePrecompiles].
+//
+// Accounts are overridden in order of their addresses, for determinism, and an
+// error is returned if an account has both State and StateDiff set, in which
+// case `sdb` MAY have been partially modified.
+func ApplyOverrides(sdb *StateDB, overrides OverrideSet) error {
+	for _, addr := range slices.SortedFunc(maps.Keys(overrides), common.Address.Cmp) {
+		acc := overrides[addr]
+		if acc.State != nil && acc.StateDiff != nil {
+			return fmt.Errorf("account %v has both state and state diff", addr)
+		}
+		if acc.Nonce != nil {
+			sdb.SetNonce(addr, *acc.Nonce)
+		}
+		if acc.Code != nil {
+			sdb.SetCode(addr, *acc.Code)
+		}
+		if acc.Balance != nil {
+			sdb.SetBalance(addr, acc.Balance)
+		}
+		if acc.State != nil {
+			sdb.SetStorage(addr, acc.State)
+		}
+		for key, val := range acc.StateDiff {
+			sdb.SetState(addr, key, val)
+		}
+	}
+	sdb.Finalise(false)
+	return nil
+}
+
+// MovePrecompiles modifies `precompiles`, typically a copy of
+// [vm.PrecompiledContracts] for the active rules, in place to reflect the
+// MovePrecompileTo fields of the overrides. Every overridden precompile is
+// removed from its original address, even if not moved, such that its address
+// is treated as a regular account.
+//
+// An error is returned if an account with MovePrecompileTo set isn't a
+// precompile or if the destination is itself overridden. A destination that is
+// already a precompile is replaced. Note that the destination account's state
+// is not cleared.
+func MovePrecompiles[P any](overrides OverrideSet, precompiles map[common.Address]P) error {
+	moved := make(map[common.Address]P)
+	for _, addr := range slices.SortedFunc(maps.Keys(overrides), common.Address.Cmp) {
+		p, isPrecompile := precompiles[addr]
+		to := overrides[addr].MovePrecompileTo
+		if to == nil {
+			if isPrecompile {
+				delete(precompiles, addr)
+			}
+			continue
+		}
+		if !isPrecompile {
+			return fmt.Errorf("account %v is not a precompile", addr)
+		}
+		if _, ok := overrides[*to]; ok {
+			return fmt.Errorf("account %v is already overridden", *to)
+		}
+		delete(precompiles, addr)
+		moved[*to] = p
+	}
+	maps.Copy(precompiles, moved)
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	sdb, err := New(types.EmptyRootHash, db, nil)
+	require.NoError(t, err, "New()")
+
+	var (
+		full   = common.Address{'f', 'u', 'l', 'l'}
+		diff   = common.Address{'d', 'i', 'f', 'f'}
+		k1, k2 = common.Hash{1}, common.Hash{2}
+		v1, v2 = common.Hash{'v', 1}, common.Hash{'v', 2}
+	)
+	sdb.SetState(full, k1, v1)
+	sdb.SetState(diff, k1, v1)
+	sdb.SetCode(diff, []byte{0xfe})
+	root, err := sdb.Commit(0, false)
+	require.NoError(t, err, "Commit()")
+	sdb, err = New(root, db, nil)
+	require.NoError(t, err, "New()")
+
+	nonce := uint64(42)
+	code := []byte{}
+	require.NoError(t, ApplyOverrides(sdb, OverrideSet{
+		full: {
+			Nonce:   &nonce,
+			Balance: uint256.NewInt(314159),
+			State:   map[common.Hash]common.Hash{k2: v2},
+		},
+		diff: {
+			Code:      &code,
+			StateDiff: map[common.Hash]common.Hash{k2: v2},
+		},
+	}), "ApplyOverrides()")
+
+	assert.Equal(t, nonce, sdb.GetNonce(full), "overridden nonce")
+	assert.Equal(t, uint256.NewInt(314159), sdb.GetBalance(full), "overridden balance")
+	assert.Equal(t, common.Hash{}, sdb.GetState(full, k1), "slot cleared by full state override")
+	assert.Equal(t, v2, sdb.GetState(full, k2), "slot set by full state override")
+
+	assert.Empty(t, sdb.GetCode(diff), "code cleared by empty override")
+	assert.Equal(t, v1, sdb.GetState(diff, k1), "slot unchanged by state diff")
+	assert.Equal(t, v2, sdb.GetState(diff, k2), "slot set by state diff")
+
+	err = ApplyOverrides(sdb, OverrideSet{
+		full: {
+			State:     map[common.Hash]common.Hash{},
+			StateDiff: map[common.Hash]common.Hash{},
+		},
+	})
+	assert.Error(t, err, "ApplyOverrides() with both State and StateDiff")
+}
+
+func TestMovePrecompiles(t *testing.T) {
+	var (
+		pre1, pre2 = common.Address{1}, common.Address{2}
+		regular    = common.Address{'r', 'e', 'g'}
+		dest       = common.Address{'d', 'e', 's', 't'}
+	)
+	newPrecompiles := func() map[common.Address]string {
+		return map[common.Address]string{
+			pre1: "one",
+			pre2: "two",
+		}
+	}
+
+	tests := []struct {
+		name      string
+		overrides OverrideSet
+		want      map[common.Address]string
+		wantErr   bool
+	}{
+		{
+			name:      "no_overrides",
+			overrides: nil,
+			want:      newPrecompiles(),
+		},
+		{
+			name:      "move",
+			overrides: OverrideSet{pre1: {MovePrecompileTo: &dest}},
+			want: map[common.Address]string{
+				dest: "one",
+				pre2: "two",
+			},
+		},
+		{
+			name:      "move_onto_precompile",
+			overrides: OverrideSet{pre1: {MovePrecompileTo: &pre2}},
+			want: map[common.Address]string{
+				pre2: "one",
+			},
+		},
+		{
+			name: "swap_is_rejected",
+			overrides: OverrideSet{
+				pre1: {MovePrecompileTo: &pre2},
+				pre2: {MovePrecompileTo: &pre1},
+			},
+			wantErr: true,
+		},
+		{
+			name:      "override_without_move",
+			overrides: OverrideSet{pre2: {}},
+			want: map[common.Address]string{
+				pre1: "one",
+			},
+		},
+		{
+			name:      "not_a_precompile",
+			overrides: OverrideSet{regular: {MovePrecompileTo: &dest}},
+			wantErr:   true,
+		},
+		{
+			name: "destination_overridden",
+			overrides: OverrideSet{
+				pre1: {MovePrecompileTo: &dest},
+				dest: {},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newPrecompiles()
+			err := MovePrecompiles(tt.overrides, got)
+			if tt.wantErr {
+				require.Error(t, err, "MovePrecompiles()")
+				return
+			}
+			require.NoError(t, err, "MovePrecompiles()")
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
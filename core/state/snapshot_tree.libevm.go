@@ -0,0 +1,63 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/triedb"
+)
+
+// A SnapshotTreeConstructor constructs an alternative [SnapshotTree]
+// implementation, e.g. one that is native to a custom state backend, for state
+// stored in `db` and `tdb`, with the current chain head having state root
+// `head`.
+type SnapshotTreeConstructor func(db ethdb.Database, tdb *triedb.Database, head common.Hash) (SnapshotTree, error)
+
+// RegisterSnapshotTreeConstructor registers the constructor of the
+// [SnapshotTree] to be used by `core.BlockChain` instead of a standard
+// snapshot.Tree, regardless of its snapshot configuration. It is expected to
+// be called in an `init()` function and MUST NOT be called more than once.
+//
+// The returned [SnapshotTree] is passed to every [StateDB] opened by the
+// `BlockChain` and therefore receives all updates via [StateDB.Commit].
+// Functionality specific to a standard snapshot.Tree, such as journaling on
+// shutdown and serving snap sync, is disabled.
+func RegisterSnapshotTreeConstructor(c SnapshotTreeConstructor) {
+	registeredSnapshotTreeConstructor.MustRegister(c)
+}
+
+// RegisteredSnapshotTreeConstructor returns the constructor passed to
+// [RegisterSnapshotTreeConstructor], and true, or false if none was
+// registered.
+func RegisteredSnapshotTreeConstructor() (SnapshotTreeConstructor, bool) {
+	r := &registeredSnapshotTreeConstructor
+	if !r.Registered() {
+		return nil, false
+	}
+	return r.Get(), true
+}
+
+// TestOnlyClearRegisteredSnapshotTreeConstructor clears the constructor
+// previously passed to [RegisterSnapshotTreeConstructor]. It panics if called
+// from a non-testing call stack.
+func TestOnlyClearRegisteredSnapshotTreeConstructor() {
+	registeredSnapshotTreeConstructor.TestOnlyClear()
+}
+
+var registeredSnapshotTreeConstructor register.AtMostOnce[SnapshotTreeConstructor]
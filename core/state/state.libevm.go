@@ -17,6 +17,8 @@
 package state
 
 import (
+	"fmt"
+
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core/types"
 	"github.com/ava-labs/libevm/internal/libevm/pseudo"
@@ -26,6 +28,9 @@ import (
 // with the address, or a zero-value `SA` if not found. The [pseudo.Accessor]
 // MUST be sourced from [types.RegisterExtras].
 func GetExtra[SA any](s *StateDB, a pseudo.Accessor[types.StateOrSlimAccount, SA], addr common.Address) SA {
+	if s.concurrent != nil {
+		defer s.lockAccount(addr)()
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return a.Get(&stateObject.data)
@@ -34,6 +39,22 @@ func GetExtra[SA any](s *StateDB, a pseudo.Accessor[types.StateOrSlimAccount, SA
 	return zero
 }
 
+// GetExtraPayload returns a copy of the extra payload carried by the
+// [types.StateAccount] associated with the address, or nil if not found.
+// Unlike [GetExtra], it doesn't require knowledge of the payload type so is
+// suitable for type-agnostic consumers such as tracers; see
+// [types.StateAccountExtra.MarshalJSON].
+func (s *StateDB) GetExtraPayload(addr common.Address) *types.StateAccountExtra {
+	if s.concurrent != nil {
+		defer s.lockAccount(addr)()
+	}
+	stateObject := s.getStateObject(addr)
+	if stateObject != nil {
+		return stateObject.data.Copy().Extra
+	}
+	return nil
+}
+
 // SetExtra sets the extra payload for the address. See [GetExtra] for details.
 func SetExtra[SA any](s *StateDB, a pseudo.Accessor[types.StateOrSlimAccount, SA], addr common.Address, extra SA) {
 	stateObject := s.getOrNewStateObject(addr)
@@ -63,3 +84,36 @@ func (e extraChange[SA]) dirtied() *common.Address { return e.account }
 func (e extraChange[SA]) revert(s *StateDB) {
 	e.accessor.Set(&s.getStateObject(*e.account).data, e.prev)
 }
+
+// An AccountExtraAccessor provides typed access to the `SA` extra payload of
+// accounts in a [StateDB]. Modifications are journaled, and therefore reverted
+// by [StateDB.RevertToSnapshot], and they mark the account as dirty such that
+// they are included in [StateDB.Commit].
+type AccountExtraAccessor[SA any] struct{}
+
+// RegisterAccountExtraAccessor returns an [AccountExtraAccessor] for the `SA`
+// type, which MUST be the same as that passed to [types.RegisterExtras]. As the
+// returned value may be constructed before [types.RegisterExtras] is called,
+// e.g. as a package-level variable, the type is only checked on use; its
+// methods panic if it is incorrect.
+func RegisterAccountExtraAccessor[SA any]() AccountExtraAccessor[SA] {
+	return AccountExtraAccessor[SA]{}
+}
+
+func (x AccountExtraAccessor[SA]) accessor() pseudo.Accessor[types.StateOrSlimAccount, SA] {
+	a, err := types.StateAccountAccessor[SA]()
+	if err != nil {
+		panic(fmt.Sprintf("%T: %v", AccountExtraAccessor[SA]{}, err))
+	}
+	return a
+}
+
+// Get is equivalent to [GetExtra].
+func (x AccountExtraAccessor[SA]) Get(s *StateDB, addr common.Address) SA {
+	return GetExtra(s, x.accessor(), addr)
+}
+
+// Set is equivalent to [SetExtra].
+func (x AccountExtraAccessor[SA]) Set(s *StateDB, addr common.Address, extra SA) {
+	SetExtra(s, x.accessor(), addr, extra)
+}
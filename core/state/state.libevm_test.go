@@ -174,3 +174,41 @@ func newWithSnaps(t *testing.T) stateViews {
 		database: state.NewDatabase(ethDB),
 	}
 }
+
+func TestAccountExtraAccessor(t *testing.T) {
+	type accountExtra struct {
+		Value uint64
+	}
+	extras := state.RegisterAccountExtraAccessor[accountExtra]()
+
+	types.TestOnlyClearRegisteredExtras()
+	t.Cleanup(types.TestOnlyClearRegisteredExtras)
+
+	views := newWithSnaps(t)
+	stateDB := views.newStateDB(t, types.EmptyRootHash)
+	addr := common.Address{'a'}
+
+	require.Panics(t, func() { extras.Get(stateDB, addr) }, "Get() before types.RegisterExtras()")
+
+	types.RegisterExtras[
+		types.NOOPHeaderHooks, *types.NOOPHeaderHooks,
+		types.NOOPBlockBodyHooks, *types.NOOPBlockBodyHooks,
+		accountExtra,
+	]()
+	require.Panics(t, func() {
+		state.RegisterAccountExtraAccessor[uint64]().Get(stateDB, addr)
+	}, "Get() with incorrect type")
+
+	assert.Zero(t, extras.Get(stateDB, addr), "Get() before Set()")
+	extras.Set(stateDB, addr, accountExtra{Value: 1})
+
+	snap := stateDB.Snapshot()
+	extras.Set(stateDB, addr, accountExtra{Value: 2})
+	assert.Equal(t, accountExtra{Value: 2}, extras.Get(stateDB, addr), "Get() after overwriting")
+	stateDB.RevertToSnapshot(snap)
+	assert.Equal(t, accountExtra{Value: 1}, extras.Get(stateDB, addr), "Get() after reverting to snapshot")
+
+	root, err := stateDB.Commit(1, false)
+	require.NoErrorf(t, err, "%T.Commit()", stateDB)
+	assert.Equal(t, accountExtra{Value: 1}, extras.Get(views.newStateDB(t, root), addr), "Get() from committed state")
+}
@@ -142,6 +142,9 @@ func (s *stateObject) touch() {
 // if it's not loaded previously. An error will be returned if trie can't
 // be loaded.
 func (s *stateObject) getTrie() (Trie, error) {
+	if s.db.concurrent != nil { // libevm
+		defer s.db.lockTrie()()
+	}
 	if s.trie == nil {
 		// Try fetching from prefetcher first
 		if s.data.Root != types.EmptyRootHash && s.db.prefetcher != nil {
@@ -149,7 +152,7 @@ func (s *stateObject) getTrie() (Trie, error) {
 			s.trie = s.db.prefetcher.trie(s.addrHash, s.data.Root)
 		}
 		if s.trie == nil {
-			tr, err := s.db.db.OpenStorageTrie(s.db.originalRoot, s.address, s.data.Root, s.db.trie)
+			tr, err := s.db.db.OpenStorageTrie(s.db.originalRoot, s.address, s.data.Root, s.db.trie, s.db.trieOpts...) // libevm: trieOpts
 			if err != nil {
 				return nil, err
 			}
@@ -197,7 +200,7 @@ func (s *stateObject) GetCommittedState(key common.Hash) common.Hash {
 	if s.db.snap != nil {
 		start := time.Now()
 		enc, err = s.db.snap.Storage(s.addrHash, crypto.Keccak256Hash(key.Bytes()))
-		if metrics.EnabledExpensive {
+		if s.db.expensiveMetrics() { // libevm: was metrics.EnabledExpensive
 			s.db.SnapshotStorageReads += time.Since(start)
 		}
 		if len(enc) > 0 {
@@ -217,7 +220,7 @@ func (s *stateObject) GetCommittedState(key common.Hash) common.Hash {
 			return common.Hash{}
 		}
 		val, err := tr.GetStorage(s.address, key.Bytes())
-		if metrics.EnabledExpensive {
+		if s.db.expensiveMetrics() { // libevm: was metrics.EnabledExpensive
 			s.db.StorageReads += time.Since(start)
 		}
 		if err != nil {
@@ -298,7 +301,8 @@ func (s *stateObject) updateTrie() (Trie, error) {
 	}
 	// Insert all the pending storage updates into the trie
 	usedStorage := make([][]byte, 0, len(s.pendingStorage))
-	for key, value := range s.pendingStorage {
+	for _, key := range sortedKeys(s.pendingStorage) { // libevm: deterministic order
+		value := s.pendingStorage[key]
 		// Skip noop changes, persist actual changes
 		if value == s.originStorage[key] {
 			continue
@@ -444,7 +448,7 @@ func (s *stateObject) deepCopy(db *StateDB) *stateObject {
 		address:  s.address,
 		addrHash: s.addrHash,
 		origin:   s.origin,
-		data:     s.data,
+		data:     *s.data.Copy(), // libevm: deep copy of extras
 	}
 	if s.trie != nil {
 		obj.trie = db.db.CopyTrie(s.trie)
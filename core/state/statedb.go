@@ -106,6 +106,8 @@ type StateDB struct {
 	logs    map[common.Hash][]*types.Log
 	logSize uint
 
+	logOrigin types.LogOrigin // libevm: see [StateDB.SetLogOrigin]
+
 	// Preimages occurred seen by VM in the scope of block.
 	preimages map[common.Hash][]byte
 
@@ -142,12 +144,16 @@ type StateDB struct {
 
 	// Testing hooks
 	onCommit func(states *triestate.Set) // Hook invoked when commit is performed
+
+	concurrent *concurrentReads              // libevm: see [StateDB.EnableConcurrentReads]
+	trieOpts   []stateconf.StateDBTrieOption // libevm: propagated to all tries opened
 }
 
-// New creates a new state from a given trie.
-func New(root common.Hash, db Database, snaps SnapshotTree) (*StateDB, error) {
+// New creates a new state from a given trie. The options are propagated to
+// every trie opened by the returned state, including by its prefetcher.
+func New(root common.Hash, db Database, snaps SnapshotTree, opts ...stateconf.StateDBTrieOption) (*StateDB, error) { // libevm: opts
 	snaps = clearTypedNilPointer(snaps)
-	tr, err := db.OpenTrie(root)
+	tr, err := db.OpenTrie(root, opts...) // libevm: opts
 	if err != nil {
 		return nil, err
 	}
@@ -170,6 +176,7 @@ func New(root common.Hash, db Database, snaps SnapshotTree) (*StateDB, error) {
 		accessList:           newAccessList(),
 		transientStorage:     newTransientStorage(),
 		hasher:               crypto.NewKeccakState(),
+		trieOpts:             opts, // libevm
 	}
 	if sdb.snaps != nil {
 		sdb.snap = sdb.snaps.Snapshot(root)
@@ -186,7 +193,7 @@ func (s *StateDB) StartPrefetcher(namespace string, opts ...PrefetcherOption) {
 		s.prefetcher = nil
 	}
 	if s.snap != nil {
-		s.prefetcher = newTriePrefetcher(s.db, s.originalRoot, namespace, opts...)
+		s.prefetcher = newTriePrefetcher(s.dbWithTrieOpts(), s.originalRoot, namespace, opts...) // libevm: dbWithTrieOpts()
 	}
 }
 
@@ -201,6 +208,9 @@ func (s *StateDB) StopPrefetcher() {
 
 // setError remembers the first non-nil error it is called with.
 func (s *StateDB) setError(err error) {
+	if s.concurrent != nil { // libevm
+		defer s.lockErr()()
+	}
 	if s.dbErr == nil {
 		s.dbErr = err
 	}
@@ -208,6 +218,9 @@ func (s *StateDB) setError(err error) {
 
 // Error returns the memorized database failure occurred earlier.
 func (s *StateDB) Error() error {
+	if s.concurrent != nil { // libevm
+		defer s.lockErr()()
+	}
 	return s.dbErr
 }
 
@@ -217,6 +230,7 @@ func (s *StateDB) AddLog(log *types.Log) {
 	log.TxHash = s.thash
 	log.TxIndex = uint(s.txIndex)
 	log.Index = s.logSize
+	log.Origin = s.logOrigin // libevm
 	s.logs[s.thash] = append(s.logs[s.thash], log)
 	s.logSize++
 }
@@ -274,18 +288,27 @@ func (s *StateDB) SubRefund(gas uint64) {
 // Exist reports whether the given account address exists in the state.
 // Notably this also returns true for self-destructed accounts.
 func (s *StateDB) Exist(addr common.Address) bool {
+	if s.concurrent != nil { // libevm
+		defer s.lockAccount(addr)()
+	}
 	return s.getStateObject(addr) != nil
 }
 
 // Empty returns whether the state object is either non-existent
 // or empty according to the EIP161 specification (balance = nonce = code = 0)
 func (s *StateDB) Empty(addr common.Address) bool {
+	if s.concurrent != nil { // libevm
+		defer s.lockAccount(addr)()
+	}
 	so := s.getStateObject(addr)
 	return so == nil || so.empty()
 }
 
 // GetBalance retrieves the balance from the given address or 0 if object not found
 func (s *StateDB) GetBalance(addr common.Address) *uint256.Int {
+	if s.concurrent != nil { // libevm
+		defer s.lockAccount(addr)()
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Balance()
@@ -295,6 +318,9 @@ func (s *StateDB) GetBalance(addr common.Address) *uint256.Int {
 
 // GetNonce retrieves the nonce from the given address or 0 if object not found
 func (s *StateDB) GetNonce(addr common.Address) uint64 {
+	if s.concurrent != nil { // libevm
+		defer s.lockAccount(addr)()
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Nonce()
@@ -306,6 +332,9 @@ func (s *StateDB) GetNonce(addr common.Address) uint64 {
 // GetStorageRoot retrieves the storage root from the given address or empty
 // if object not found.
 func (s *StateDB) GetStorageRoot(addr common.Address) common.Hash {
+	if s.concurrent != nil { // libevm
+		defer s.lockAccount(addr)()
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Root()
@@ -319,6 +348,9 @@ func (s *StateDB) TxIndex() int {
 }
 
 func (s *StateDB) GetCode(addr common.Address) []byte {
+	if s.concurrent != nil { // libevm
+		defer s.lockAccount(addr)()
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Code()
@@ -327,6 +359,9 @@ func (s *StateDB) GetCode(addr common.Address) []byte {
 }
 
 func (s *StateDB) GetCodeSize(addr common.Address) int {
+	if s.concurrent != nil { // libevm
+		defer s.lockAccount(addr)()
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.CodeSize()
@@ -335,6 +370,9 @@ func (s *StateDB) GetCodeSize(addr common.Address) int {
 }
 
 func (s *StateDB) GetCodeHash(addr common.Address) common.Hash {
+	if s.concurrent != nil { // libevm
+		defer s.lockAccount(addr)()
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return common.BytesToHash(stateObject.CodeHash())
@@ -344,6 +382,9 @@ func (s *StateDB) GetCodeHash(addr common.Address) common.Hash {
 
 // GetState retrieves a value from the given account's storage trie.
 func (s *StateDB) GetState(addr common.Address, hash common.Hash, opts ...stateconf.StateDBStateOption) common.Hash {
+	if s.concurrent != nil { // libevm
+		defer s.lockAccount(addr)()
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		hash = transformStateKey(addr, hash, opts...)
@@ -354,6 +395,9 @@ func (s *StateDB) GetState(addr common.Address, hash common.Hash, opts ...statec
 
 // GetCommittedState retrieves a value from the given account's committed storage trie.
 func (s *StateDB) GetCommittedState(addr common.Address, hash common.Hash, opts ...stateconf.StateDBStateOption) common.Hash {
+	if s.concurrent != nil { // libevm
+		defer s.lockAccount(addr)()
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		hash = transformStateKey(addr, hash, opts...)
@@ -368,6 +412,9 @@ func (s *StateDB) Database() Database {
 }
 
 func (s *StateDB) HasSelfDestructed(addr common.Address) bool {
+	if s.concurrent != nil { // libevm
+		defer s.lockAccount(addr)()
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.selfDestructed
@@ -567,19 +614,23 @@ func (s *StateDB) getStateObject(addr common.Address) *stateObject {
 // destructed object instead of wiping all knowledge about the state object.
 func (s *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
 	// Prefer live objects if any is available
-	if obj := s.stateObjects[addr]; obj != nil {
+	if obj := s.liveStateObject(addr); obj != nil { // libevm: was s.stateObjects[addr]
 		return obj
 	}
+	if s.accountDefinitelyAbsent(addr) { // libevm
+		return nil
+	}
 	// If no live objects are available, attempt to use snapshots
 	var data *types.StateAccount
 	if s.snap != nil {
 		start := time.Now()
-		acc, err := s.snap.Account(crypto.HashData(s.hasher, addr.Bytes()))
-		if metrics.EnabledExpensive {
+		acc, err := s.snap.Account(s.hashAddress(addr)) // libevm: was crypto.HashData(s.hasher, addr.Bytes())
+		if s.expensiveMetrics() {                       // libevm: was metrics.EnabledExpensive
 			s.SnapshotAccountReads += time.Since(start)
 		}
 		if err == nil {
 			if acc == nil {
+				s.existenceFilterMissed() // libevm
 				return nil
 			}
 			data = &types.StateAccount{
@@ -601,8 +652,8 @@ func (s *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
 	if data == nil {
 		start := time.Now()
 		var err error
-		data, err = s.trie.GetAccount(addr)
-		if metrics.EnabledExpensive {
+		data, err = s.readAccountTrie(addr) // libevm: was s.trie.GetAccount(addr)
+		if s.expensiveMetrics() {           // libevm: was metrics.EnabledExpensive
 			s.AccountReads += time.Since(start)
 		}
 		if err != nil {
@@ -610,6 +661,7 @@ func (s *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
 			return nil
 		}
 		if data == nil {
+			s.existenceFilterMissed() // libevm
 			return nil
 		}
 	}
@@ -620,6 +672,9 @@ func (s *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
 }
 
 func (s *StateDB) setStateObject(object *stateObject) {
+	if s.concurrent != nil { // libevm
+		defer s.lockObjects()()
+	}
 	s.stateObjects[object.Address()] = object
 }
 
@@ -699,6 +754,8 @@ func (s *StateDB) Copy() *StateDB {
 		db:                   s.db,
 		trie:                 s.db.CopyTrie(s.trie),
 		originalRoot:         s.originalRoot,
+		logOrigin:            s.logOrigin, // libevm
+		trieOpts:             s.trieOpts,  // libevm
 		accounts:             copySet(s.accounts),
 		storages:             copy2DSet(s.storages),
 		accountsOrigin:       copySet(s.accountsOrigin),
@@ -816,7 +873,7 @@ func (s *StateDB) GetRefund() uint64 {
 // into the tries just yet. Only IntermediateRoot or Commit will do that.
 func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 	addressesToPrefetch := make([][]byte, 0, len(s.journal.dirties))
-	for addr := range s.journal.dirties {
+	for _, addr := range sortedKeys(s.journal.dirties) { // libevm: deterministic order
 		obj, exist := s.stateObjects[addr]
 		if !exist {
 			// ripeMD is 'touched' at block 1714175, in tx 0x1237f737031e40bcde4a8b7e717b2d15e3ecadfe49bb1bbc71ee9deb09c6fcf2
@@ -888,7 +945,8 @@ func (s *StateDB) IntermediateRoot(deleteEmptyObjects bool) common.Hash {
 	// the account prefetcher. Instead, let's process all the storage updates
 	// first, giving the account prefetches just a few more milliseconds of time
 	// to pull useful data from disk.
-	for addr := range s.stateObjectsPending {
+	pending := sortedKeys(s.stateObjectsPending) // libevm: deterministic order
+	for _, addr := range pending {
 		if obj := s.stateObjects[addr]; !obj.deleted {
 			obj.updateRoot()
 		}
@@ -902,7 +960,7 @@ func (s *StateDB) IntermediateRoot(deleteEmptyObjects bool) common.Hash {
 		}
 	}
 	usedAddrs := make([][]byte, 0, len(s.stateObjectsPending))
-	for addr := range s.stateObjectsPending {
+	for _, addr := range pending {
 		if obj := s.stateObjects[addr]; obj.deleted {
 			s.deleteStateObject(obj)
 			s.AccountDeleted += 1
@@ -991,7 +1049,7 @@ func (s *StateDB) fastDeleteStorage(addrHash common.Hash, root common.Hash) (boo
 // employed when the associated state snapshot is not available. It iterates the
 // storage slots along with all internal trie nodes via trie directly.
 func (s *StateDB) slowDeleteStorage(addr common.Address, addrHash common.Hash, root common.Hash) (bool, common.StorageSize, map[common.Hash][]byte, *trienode.NodeSet, error) {
-	tr, err := s.db.OpenStorageTrie(s.originalRoot, addr, root, s.trie)
+	tr, err := s.db.OpenStorageTrie(s.originalRoot, addr, root, s.trie, s.trieOpts...) // libevm: trieOpts
 	if err != nil {
 		return false, 0, nil, nil, fmt.Errorf("failed to open storage trie, err: %w", err)
 	}
@@ -1099,7 +1157,8 @@ func (s *StateDB) handleDestruction(nodes *trienode.MergedNodeSet) (map[common.A
 	if s.db.TrieDB().Scheme() == rawdb.HashScheme {
 		return incomplete, nil
 	}
-	for addr, prev := range s.stateObjectsDestruct {
+	for _, addr := range sortedKeys(s.stateObjectsDestruct) { // libevm: deterministic order
+		prev := s.stateObjectsDestruct[addr]
 		// The original account was non-existing, and it's marked as destructed
 		// in the scope of block. It can be case (a) or (b).
 		// - for (a), skip it without doing anything.
@@ -1180,11 +1239,12 @@ func (s *StateDB) Commit(block uint64, deleteEmptyObjects bool, opts ...statecon
 		return common.Hash{}, err
 	}
 	// Handle all state updates afterwards
-	for addr := range s.stateObjectsDirty {
+	for _, addr := range sortedKeys(s.stateObjectsDirty) { // libevm: deterministic order
 		obj := s.stateObjects[addr]
 		if obj.deleted {
 			continue
 		}
+		s.addToExistenceFilter(obj.addrHash) // libevm
 		// Write any contract code associated with the state object
 		if obj.code != nil && obj.dirtyCode {
 			rawdb.WriteCode(codeWriter, common.BytesToHash(obj.CodeHash()), obj.code)
@@ -17,10 +17,13 @@
 package state
 
 import (
+	"maps"
 	"reflect"
+	"slices"
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core/state/snapshot"
+	"github.com/ava-labs/libevm/core/types"
 	"github.com/ava-labs/libevm/libevm"
 	"github.com/ava-labs/libevm/libevm/register"
 	"github.com/ava-labs/libevm/libevm/stateconf"
@@ -31,6 +34,29 @@ func (s *StateDB) TxHash() common.Hash {
 	return s.thash
 }
 
+// SetLogOrigin sets the [types.LogOrigin] of all logs subsequently added with
+// [StateDB.AddLog]. Execution paths other than transactions MUST set it before
+// emitting logs and restore [types.LogOriginTransaction] afterwards.
+func (s *StateDB) SetLogOrigin(o types.LogOrigin) {
+	s.logOrigin = o
+}
+
+// sortedKeys returns the keys of `m` in ascending order.
+//
+// [StateDB.Finalise], [StateDB.IntermediateRoot], and [StateDB.Commit] iterate
+// over dirty accounts and storage slots in this order, as do the insertions
+// into account and storage tries. Any [Trie] implementation, commit observer,
+// or database override therefore receives updates in an order that is
+// identical across nodes, regardless of Go's randomised map iteration.
+func sortedKeys[K interface {
+	comparable
+	Cmp(K) int
+}, V any](m map[K]V) []K {
+	return slices.SortedFunc(maps.Keys(m), func(a, b K) int {
+		return a.Cmp(b)
+	})
+}
+
 // SnapshotTree mirrors the functionality of a [snapshot.Tree], allowing for
 // drop-in replacements. This is intended as a temporary feature as a workaround
 // until a standard Tree can be used.
@@ -119,3 +145,25 @@ func transformStateKey(addr common.Address, key common.Hash, opts ...stateconf.S
 	}
 	return r.Get().TransformStateKey(addr, key)
 }
+
+// dbWithTrieOpts returns the [Database] underlying `s`, modified such that
+// the options passed to [New] are propagated to all tries that it opens.
+func (s *StateDB) dbWithTrieOpts() Database {
+	if len(s.trieOpts) == 0 {
+		return s.db
+	}
+	return trieOptsDB{s.db, s.trieOpts}
+}
+
+type trieOptsDB struct {
+	Database
+	opts []stateconf.StateDBTrieOption
+}
+
+func (db trieOptsDB) OpenTrie(root common.Hash, opts ...stateconf.StateDBTrieOption) (Trie, error) {
+	return db.Database.OpenTrie(root, append(slices.Clone(db.opts), opts...)...)
+}
+
+func (db trieOptsDB) OpenStorageTrie(stateRoot common.Hash, addr common.Address, root common.Hash, tr Trie, opts ...stateconf.StateDBTrieOption) (Trie, error) {
+	return db.Database.OpenStorageTrie(stateRoot, addr, root, tr, append(slices.Clone(db.opts), opts...)...)
+}
@@ -17,6 +17,7 @@
 package state
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,6 +27,7 @@ import (
 	"github.com/ava-labs/libevm/core/rawdb"
 	"github.com/ava-labs/libevm/core/state/snapshot"
 	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
 	"github.com/ava-labs/libevm/ethdb"
 	"github.com/ava-labs/libevm/libevm"
 	"github.com/ava-labs/libevm/libevm/stateconf"
@@ -140,6 +142,65 @@ func (r *triedbRecorder) Reader(_ common.Hash) (database.Reader, error) {
 	return r.Database.Reader(common.Hash{})
 }
 
+// trieOpenRecorder records the payloads of [stateconf.WithTrieOpenPayload]
+// options received when opening tries, recording nil if Reader() is called
+// without options.
+type trieOpenRecorder struct {
+	*hashdb.Database
+	payloads []any
+}
+
+func (r *trieOpenRecorder) Reader(root common.Hash) (database.Reader, error) {
+	r.payloads = append(r.payloads, nil)
+	return r.Database.Reader(root)
+}
+
+func (r *trieOpenRecorder) ReaderWithOptions(root common.Hash, opts ...stateconf.StateDBTrieOption) (database.Reader, error) {
+	r.payloads = append(r.payloads, stateconf.ExtractTrieOpenPayload(opts...))
+	return r.Database.Reader(root)
+}
+
+func TestStateDBPropagatesTrieOptions(t *testing.T) {
+	memdb := rawdb.NewMemoryDatabase()
+	rec := &trieOpenRecorder{Database: hashdb.New(memdb, nil, &trie.MerkleResolver{})}
+	tdb := triedb.NewDatabase(memdb, &triedb.Config{
+		DBOverride: func(ethdb.Database) triedb.DBOverride {
+			return rec
+		},
+	})
+	db := NewDatabaseWithNodeDB(memdb, tdb)
+
+	var (
+		addrs = []common.Address{{1}, {2}, {3}}
+		key   = common.Hash{'k'}
+		val   = common.Hash{'v'}
+	)
+	sdb, err := New(types.EmptyRootHash, db, nil)
+	require.NoError(t, err, "New()")
+	for _, a := range addrs {
+		sdb.SetState(a, key, val)
+	}
+	root, err := sdb.Commit(0, false)
+	require.NoErrorf(t, err, "%T.Commit()", sdb)
+	require.NoErrorf(t, tdb.Commit(root, false), "%T.Commit()", tdb)
+
+	const payload = "speculative"
+	rec.payloads = nil
+	sdb, err = New(root, db, nil, stateconf.WithTrieOpenPayload(payload))
+	require.NoError(t, err, "New(..., WithTrieOpenPayload())")
+	assert.Equal(t, val, sdb.GetState(addrs[0], key), "GetState()")
+	assert.Equalf(t, val, sdb.Copy().GetState(addrs[1], key), "%T.Copy().GetState()", sdb)
+	assert.Equalf(t, val, sdb.CopyForRead().GetState(addrs[2], key), "%T.CopyForRead().GetState()", sdb)
+	// One account trie plus one storage trie per account.
+	assert.Equal(t, []any{payload, payload, payload, payload}, rec.payloads, "payloads received when opening tries")
+
+	rec.payloads = nil
+	sdb, err = New(root, db, nil)
+	require.NoError(t, err, "New() without options")
+	sdb.GetState(addrs[0], key)
+	assert.Equal(t, []any{nil, nil}, rec.payloads, "Reader() called without options")
+}
+
 type highByteFlipper struct{}
 
 func flipHighByte(h common.Hash) common.Hash {
@@ -237,3 +298,85 @@ func TestTransformStateKey(t *testing.T) {
 	assertCommittedEq(t, regularKey, flippedVal)
 	assertCommittedEq(t, flippedKey, flippedVal, noTransform)
 }
+
+type orderRecordingDB struct {
+	Database
+	rec *orderRecorder
+}
+
+type orderRecorder struct {
+	accounts []common.Address
+	slots    map[common.Address][]common.Hash
+}
+
+type orderRecordingTrie struct {
+	Trie
+	rec *orderRecorder
+}
+
+func (db orderRecordingDB) OpenTrie(root common.Hash, opts ...stateconf.StateDBTrieOption) (Trie, error) {
+	tr, err := db.Database.OpenTrie(root, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return orderRecordingTrie{tr, db.rec}, nil
+}
+
+func (db orderRecordingDB) OpenStorageTrie(stateRoot common.Hash, addr common.Address, root common.Hash, tr Trie, opts ...stateconf.StateDBTrieOption) (Trie, error) {
+	st, err := db.Database.OpenStorageTrie(stateRoot, addr, root, tr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return orderRecordingTrie{st, db.rec}, nil
+}
+
+func (t orderRecordingTrie) UpdateAccount(addr common.Address, acc *types.StateAccount) error {
+	t.rec.accounts = append(t.rec.accounts, addr)
+	return t.Trie.UpdateAccount(addr, acc)
+}
+
+func (t orderRecordingTrie) UpdateStorage(addr common.Address, key, value []byte) error {
+	t.rec.slots[addr] = append(t.rec.slots[addr], common.BytesToHash(key))
+	return t.Trie.UpdateStorage(addr, key, value)
+}
+
+func TestDeterministicTrieUpdateOrder(t *testing.T) {
+	// Hashing provides an ordering that differs from that of insertion.
+	addrs := make([]common.Address, 32)
+	keys := make([]common.Hash, 32)
+	for i := range addrs {
+		keys[i] = crypto.Keccak256Hash([]byte{byte(i)})
+		addrs[i] = common.BytesToAddress(keys[i][:])
+	}
+	lessAddr := func(a, b common.Address) int { return a.Cmp(b) }
+	lessHash := func(a, b common.Hash) int { return a.Cmp(b) }
+
+	for i := 0; i < 5; i++ {
+		rec := &orderRecorder{
+			slots: make(map[common.Address][]common.Hash),
+		}
+		db := orderRecordingDB{
+			Database: NewDatabase(rawdb.NewMemoryDatabase()),
+			rec:      rec,
+		}
+		sdb, err := New(types.EmptyRootHash, db, nil)
+		require.NoError(t, err, "New()")
+
+		for _, addr := range addrs {
+			sdb.SetNonce(addr, 1)
+			for _, key := range keys {
+				sdb.SetState(addr, key, common.Hash{1})
+			}
+		}
+		_, err = sdb.Commit(0, false)
+		require.NoErrorf(t, err, "%T.Commit()", sdb)
+
+		require.Len(t, rec.accounts, len(addrs), "accounts updated")
+		assert.Truef(t, slices.IsSortedFunc(rec.accounts, lessAddr), "%T.UpdateAccount() calls sorted by address", rec)
+		require.Len(t, rec.slots, len(addrs), "storage tries updated")
+		for addr, got := range rec.slots {
+			require.Lenf(t, got, len(keys), "slots updated for %v", addr)
+			assert.Truef(t, slices.IsSortedFunc(got, lessHash), "%T.UpdateStorage() calls for %v sorted by key", rec, addr)
+		}
+	}
+}
@@ -55,6 +55,9 @@ type triePrefetcher struct {
 }
 
 func newTriePrefetcher(db Database, root common.Hash, namespace string, opts ...PrefetcherOption) *triePrefetcher {
+	if p, ok := attachRetained(root, opts); ok { // libevm
+		return p
+	}
 	prefix := triePrefetchMetricsPrefix + namespace
 	p := &triePrefetcher{
 		db:       db,
@@ -104,6 +107,7 @@ func (p *triePrefetcher) close() {
 			}
 		}
 	}
+	p.retain() // libevm
 	p.releaseWorkerPools()
 	// Clear out all fetchers (will crash on a second call, deliberate)
 	p.fetchers = nil
@@ -18,6 +18,7 @@ package state
 
 import (
 	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/lru"
 	"github.com/ava-labs/libevm/libevm/options"
 	"github.com/ava-labs/libevm/libevm/sync"
 	"github.com/ava-labs/libevm/log"
@@ -28,6 +29,8 @@ type PrefetcherOption = options.Option[prefetcherConfig]
 
 type prefetcherConfig struct {
 	newWorkers func() WorkerPool
+	cache      *PrefetchCache
+	block      common.Hash
 }
 
 // A WorkerPool executes functions asynchronously. Done() is called to signal
@@ -47,6 +50,87 @@ func WithWorkerPools(ctor func() WorkerPool) PrefetcherOption {
 	})
 }
 
+// WithPrefetchCache shares the tries loaded by a prefetcher between multiple
+// executions of the same block, typically verification and acceptance. When
+// the prefetcher is closed, its loaded tries are retained in the cache, keyed
+// by block hash. If the cache already holds tries retained for the block, and
+// for the same parent state root, then they are attached to the new
+// prefetcher instead of fetching again; the entry is then removed from the
+// cache.
+//
+// An attached prefetcher is inactive: it only serves the retained tries and
+// any other state is read from the [Database] as if there were no prefetcher.
+func WithPrefetchCache(c *PrefetchCache, block common.Hash) PrefetcherOption {
+	return options.Func[prefetcherConfig](func(cfg *prefetcherConfig) {
+		cfg.cache = c
+		cfg.block = block
+	})
+}
+
+// A PrefetchCache retains the tries loaded by prefetchers configured with
+// [WithPrefetchCache]. It is safe for concurrent use.
+type PrefetchCache struct {
+	blocks *lru.Cache[common.Hash, *triePrefetcher]
+}
+
+// NewPrefetchCache returns a cache that retains the tries loaded for at most
+// `blocks` blocks, evicting those of the least-recently retained block.
+func NewPrefetchCache(blocks int) *PrefetchCache {
+	return &PrefetchCache{
+		blocks: lru.NewCache[common.Hash, *triePrefetcher](blocks),
+	}
+}
+
+// Invalidate removes the tries retained for the specified blocks, which
+// SHOULD be called for all blocks that are rejected or reorged out before
+// being executed again.
+func (c *PrefetchCache) Invalidate(blocks ...common.Hash) {
+	for _, b := range blocks {
+		c.blocks.Remove(b)
+	}
+}
+
+// Purge removes all retained tries.
+func (c *PrefetchCache) Purge() {
+	c.blocks.Purge()
+}
+
+// Len returns the number of blocks for which tries are retained.
+func (c *PrefetchCache) Len() int {
+	return c.blocks.Len()
+}
+
+// attachRetained returns an inactive copy of the prefetcher retained for the
+// block configured with [WithPrefetchCache], iff one exists at the same state
+// root.
+func attachRetained(root common.Hash, opts []PrefetcherOption) (*triePrefetcher, bool) {
+	cfg := options.As[prefetcherConfig](opts...)
+	if cfg.cache == nil {
+		return nil, false
+	}
+	p, ok := cfg.cache.blocks.Get(cfg.block)
+	if !ok || p.root != root {
+		return nil, false
+	}
+	cfg.cache.blocks.Remove(cfg.block)
+	return p, true
+}
+
+// retain stores an inactive copy of `p` in the cache configured with
+// [WithPrefetchCache], if any. It MUST be called before `p.fetchers` is
+// cleared.
+func (p *triePrefetcher) retain() {
+	if p.fetches != nil {
+		// Already inactive, either as a copy or an attached prefetcher.
+		return
+	}
+	cfg := options.As[prefetcherConfig](p.options...)
+	if cfg.cache == nil {
+		return
+	}
+	cfg.cache.blocks.Add(cfg.block, p.copy())
+}
+
 type subfetcherPool struct {
 	workers WorkerPool
 	tries   sync.Pool[Trie]
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/libevm/common"
 )
@@ -78,3 +79,45 @@ func TestStopPrefetcherWaitsOnWorkers(t *testing.T) {
 	// called.
 	assert.Equalf(t, 2, pool.preconditionsToStopPrefetcher, "%T.StopPrefetcher() returned early", db)
 }
+
+func TestPrefetchCache(t *testing.T) {
+	db := filledStateDB()
+	root := db.originalRoot
+	cache := NewPrefetchCache(2)
+	block := common.Hash{'b', 'l', 'o', 'c', 'k'}
+	opt := WithPrefetchCache(cache, block)
+
+	verify := newTriePrefetcher(db.db, root, "", opt)
+	verify.prefetch(common.Hash{}, root, common.Address{}, [][]byte{common.HexToHash("aaa").Bytes()})
+	want := verify.trie(common.Hash{}, root)
+	require.NotNil(t, want, "trie() during verification")
+	verify.close()
+	require.Equal(t, 1, cache.Len(), "cache length after close()")
+
+	other := newTriePrefetcher(db.db, common.Hash{'x'}, "", opt)
+	assert.Nil(t, other.fetches, "prefetcher at different root is active")
+	// Closing would retain `other` in place of `verify`.
+	defer other.close()
+
+	accept := newTriePrefetcher(db.db, root, "", opt)
+	require.NotNil(t, accept.fetches, "prefetcher attached to retained tries is inactive")
+	assert.Zero(t, cache.Len(), "cache length after attaching")
+	got := accept.trie(common.Hash{}, root)
+	require.NotNil(t, got, "trie() of attached prefetcher")
+	assert.Equal(t, want.Hash(), got.Hash(), "trie hash")
+	accept.close()
+	assert.Zero(t, cache.Len(), "attached prefetcher retained again")
+
+	t.Run("invalidate", func(t *testing.T) {
+		p := newTriePrefetcher(db.db, root, "", opt)
+		p.prefetch(common.Hash{}, root, common.Address{}, [][]byte{common.HexToHash("aaa").Bytes()})
+		p.close()
+		require.Equal(t, 1, cache.Len(), "cache length after close()")
+		cache.Invalidate(block)
+		assert.Zero(t, cache.Len(), "cache length after Invalidate()")
+
+		p = newTriePrefetcher(db.db, root, "", opt)
+		assert.Nil(t, p.fetches, "prefetcher after invalidation is active")
+		p.close()
+	})
+}
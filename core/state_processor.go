@@ -76,9 +76,11 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		vmenv   = vm.NewEVM(context, vm.TxContext{}, statedb, p.config, cfg)
 		signer  = types.MakeSigner(p.config, header.Number, header.Time)
 	)
+	defer vmenv.Release() // libevm: no-op unless [vm.Config.PoolAllocations]
 	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
 		ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
 	}
+	ProcessBeforeBlock(statedb, block) // libevm
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions() {
 		msg, err := TransactionToMessage(tx, signer, header.BaseFee)
@@ -98,8 +100,10 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	if len(withdrawals) > 0 && !p.config.IsShanghai(block.Number(), block.Time()) {
 		return nil, nil, 0, errors.New("withdrawals before shanghai")
 	}
+	ProcessAfterBlock(statedb, block, receipts) // libevm
 	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards)
 	p.engine.Finalize(p.bc, header, statedb, block.Transactions(), block.Uncles(), withdrawals)
+	allLogs = withNonTransactionLogs(allLogs, statedb, blockNumber.Uint64(), blockHash) // libevm
 
 	return receipts, allLogs, *usedGas, nil
 }
@@ -159,14 +163,27 @@ func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, sta
 // for the transaction, gas used and an error if the transaction failed,
 // indicating the block was invalid.
 func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, error) {
+	return ApplyTransactionWithInvocations(config, bc, author, gp, statedb, header, tx, usedGas, cfg, nil) // libevm
+}
+
+// ApplyTransactionWithInvocations is equivalent to [ApplyTransaction] except
+// that invocations of precompiles registered with [vm.WithInvocationLimit] are
+// counted by `inv`, which MUST be shared by all transactions in the block. A
+// nil `inv` counts the invocations of the single transaction only, so block
+// builders MUST use this function if any precompile is limited.
+func ApplyTransactionWithInvocations(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config, inv *vm.PrecompileInvocations) (*types.Receipt, error) { // libevm
 	msg, err := TransactionToMessage(tx, types.MakeSigner(config, header.Number, header.Time), header.BaseFee)
 	if err != nil {
 		return nil, err
 	}
 	// Create a new context to be used in the EVM environment
 	blockContext := NewEVMBlockContext(header, bc, author)
+	if inv != nil { // libevm
+		blockContext.SetPrecompileInvocations(inv)
+	}
 	txContext := NewEVMTxContext(msg)
 	vmenv := vm.NewEVM(blockContext, txContext, statedb, config, cfg)
+	defer vmenv.Release() // libevm: no-op unless [vm.Config.PoolAllocations]
 	return applyTransaction(msg, config, gp, statedb, header.Number, header.Hash(), tx, usedGas, vmenv)
 }
 
@@ -184,6 +201,8 @@ func ProcessBeaconBlockRoot(beaconRoot common.Hash, vmenv *vm.EVM, statedb *stat
 		To:        &params.BeaconRootsStorageAddress,
 		Data:      beaconRoot[:],
 	}
+	statedb.SetLogOrigin(types.LogOriginSystemCall)        // libevm
+	defer statedb.SetLogOrigin(types.LogOriginTransaction) // libevm
 	vmenv.Reset(NewEVMTxContext(msg), statedb)
 	statedb.AddAddressToAccessList(params.BeaconRootsStorageAddress)
 	_, _, _ = vmenv.Call(vm.AccountRef(msg.From), *msg.To, msg.Data, 30_000_000, common.U2560)
@@ -17,14 +17,132 @@
 package core
 
 import (
+	"cmp"
 	"encoding/binary"
+	"slices"
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core/state"
 	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/register"
 	"github.com/ava-labs/libevm/params"
 )
 
+// ProcessorHooks are called by [StateProcessor.Process] to run chain-specific
+// logic around the execution of a block's transactions. They are also called
+// when building blocks, by [GenerateChain] and the miner, in which case the
+// [types.Block] carries the header under construction and, for AfterBlock, the
+// transactions included so far. Hooks that modify state MUST therefore depend
+// only on fields that are already final when they are called, otherwise built
+// blocks will be invalid.
+type ProcessorHooks interface {
+	// BeforeBlock is called after all hard-fork and beacon-root state
+	// modifications but before the first transaction is applied.
+	BeforeBlock(vm.StateDB, *types.Block)
+	// AfterBlock is called after the last transaction is applied, with the
+	// receipts of all transactions, but before the consensus engine finalizes
+	// the block. Logs added to a [state.StateDB] have origin
+	// [types.LogOriginPostBlock].
+	AfterBlock(vm.StateDB, *types.Block, types.Receipts)
+}
+
+// NOOPProcessorHooks implements [ProcessorHooks] such that every method is a
+// no-op. It MAY be embedded to satisfy the interface.
+type NOOPProcessorHooks struct{}
+
+var _ ProcessorHooks = NOOPProcessorHooks{}
+
+// BeforeBlock is a no-op.
+func (NOOPProcessorHooks) BeforeBlock(vm.StateDB, *types.Block) {}
+
+// AfterBlock is a no-op.
+func (NOOPProcessorHooks) AfterBlock(vm.StateDB, *types.Block, types.Receipts) {}
+
+// RegisterProcessorHooks registers the hooks called by
+// [StateProcessor.Process] and block builders. It is expected to be called in
+// an `init()` function and MUST NOT be called more than once.
+func RegisterProcessorHooks(h ProcessorHooks) {
+	registeredProcessorHooks.MustRegister(h)
+}
+
+// TestOnlyClearProcessorHooks clears the hooks previously passed to
+// [RegisterProcessorHooks]. It panics if called from a non-testing call stack.
+func TestOnlyClearProcessorHooks() {
+	registeredProcessorHooks.TestOnlyClear()
+}
+
+var registeredProcessorHooks register.AtMostOnce[ProcessorHooks]
+
+// ProcessBeforeBlock calls the registered [ProcessorHooks.BeforeBlock]. Block
+// builders MUST call it, after any beacon-root modifications and before the
+// first transaction, to match [StateProcessor.Process].
+func ProcessBeforeBlock(sdb vm.StateDB, b *types.Block) {
+	processorHooks().BeforeBlock(sdb, b)
+}
+
+// ProcessAfterBlock calls the registered [ProcessorHooks.AfterBlock]. Block
+// builders MUST call it, after the last transaction and before the consensus
+// engine finalizes the block, to match [StateProcessor.Process].
+func ProcessAfterBlock(sdb vm.StateDB, b *types.Block, rs types.Receipts) {
+	if s, ok := sdb.(logOriginSetter); ok {
+		s.SetLogOrigin(types.LogOriginPostBlock)
+		defer s.SetLogOrigin(types.LogOriginTransaction)
+	}
+	processorHooks().AfterBlock(sdb, b, rs)
+}
+
+// NonTransactionLogs returns copies of all logs in the [state.StateDB] with an
+// origin other than [types.LogOriginTransaction], in the order they were
+// emitted. They are annotated with the block number and hash, and their
+// transaction hash is cleared. Such logs are not included in any receipt so
+// block builders MUST, like [StateProcessor.Process], pass them to
+// [BlockChain.WriteBlockAndSetHead] for delivery to log subscribers.
+func NonTransactionLogs(sdb *state.StateDB, blockNumber uint64, blockHash common.Hash) []*types.Log {
+	var logs []*types.Log
+	for _, l := range sdb.Logs() {
+		if l.Origin == types.LogOriginTransaction {
+			continue
+		}
+		cp := *l
+		cp.BlockNumber = blockNumber
+		cp.BlockHash = blockHash
+		cp.TxHash = common.Hash{}
+		logs = append(logs, &cp)
+	}
+	slices.SortFunc(logs, func(a, b *types.Log) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+	return logs
+}
+
+// withNonTransactionLogs returns `txLogs`, which MUST be in emission order,
+// merged with the [NonTransactionLogs] of the [state.StateDB].
+func withNonTransactionLogs(txLogs []*types.Log, sdb *state.StateDB, blockNumber uint64, blockHash common.Hash) []*types.Log {
+	other := NonTransactionLogs(sdb, blockNumber, blockHash)
+	if len(other) == 0 {
+		return txLogs
+	}
+	all := append(txLogs, other...)
+	slices.SortStableFunc(all, func(a, b *types.Log) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+	return all
+}
+
+// A logOriginSetter is a [vm.StateDB] that tags logs with their origin, such
+// as a [state.StateDB].
+type logOriginSetter interface {
+	SetLogOrigin(types.LogOrigin)
+}
+
+func processorHooks() ProcessorHooks {
+	if registeredProcessorHooks.Registered() {
+		return registeredProcessorHooks.Get()
+	}
+	return NOOPProcessorHooks{}
+}
+
 var beaconRootsCodeHash = common.HexToHash(`0xf57acd40259872606d76197ef052f3d35588dadf919ee1f0e3cb9b62d3f4b02c`)
 
 // SetBeaconBlockRoot is equivalent to [ProcessBeaconBlockRoot] except that it
@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus/ethash"
 	"github.com/ava-labs/libevm/core"
 	"github.com/ava-labs/libevm/core/state"
 	"github.com/ava-labs/libevm/core/types"
@@ -103,3 +104,105 @@ func TestSetBeaconBlockRoot(t *testing.T) {
 		assert.Equalf(t, gotStateRoots[1], gotStateRoots[0], "%T.IntermediateRoot() after SetBeaconBlockRoot() vs gold-standard ProcessBeaconBlockRoot()", &state.StateDB{})
 	}
 }
+
+type processorHooksRecorder struct {
+	core.NOOPProcessorHooks
+	reward        common.Address
+	before, after []*types.Block
+	receipts      types.Receipts
+}
+
+func (r *processorHooksRecorder) BeforeBlock(sdb vm.StateDB, b *types.Block) {
+	r.before = append(r.before, b)
+	sdb.AddBalance(r.reward, uint256.NewInt(1))
+}
+
+func (r *processorHooksRecorder) AfterBlock(sdb vm.StateDB, b *types.Block, rs types.Receipts) {
+	r.after = append(r.after, b)
+	r.receipts = rs
+	sdb.AddBalance(r.reward, uint256.NewInt(2))
+}
+
+func TestProcessorHooks(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	gspec := &core.Genesis{
+		Config:  params.TestChainConfig,
+		Alloc:   types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	signer := types.LatestSigner(gspec.Config)
+	db, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 1, func(_ int, b *core.BlockGen) {
+		for range 2 {
+			b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+				Nonce:    b.TxNonce(addr),
+				To:       &common.Address{},
+				Gas:      params.TxGas,
+				GasPrice: b.BaseFee(),
+			}))
+		}
+	})
+	bc, err := core.NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err, "NewBlockChain()")
+	defer bc.Stop()
+
+	hooks := &processorHooksRecorder{reward: common.Address{'r'}}
+	core.RegisterProcessorHooks(hooks)
+	t.Cleanup(core.TestOnlyClearProcessorHooks)
+
+	sdb, err := bc.StateAt(bc.Genesis().Root())
+	require.NoError(t, err, "StateAt(genesis)")
+	block := blocks[0]
+	receipts, _, _, err := bc.Processor().Process(block, sdb, vm.Config{})
+	require.NoError(t, err, "Process()")
+
+	assert.Equal(t, []*types.Block{block}, hooks.before, "BeforeBlock() calls")
+	assert.Equal(t, []*types.Block{block}, hooks.after, "AfterBlock() calls")
+	assert.Equal(t, receipts, hooks.receipts, "receipts passed to AfterBlock()")
+	assert.Len(t, hooks.receipts, 2, "receipts passed to AfterBlock()")
+	assert.Equal(t, uint256.NewInt(3), sdb.GetBalance(hooks.reward), "balance modified by hooks")
+}
+
+func TestProcessorHooksWhenBuilding(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	hooks := &processorHooksRecorder{reward: common.Address{'r'}}
+	core.RegisterProcessorHooks(hooks)
+	t.Cleanup(core.TestOnlyClearProcessorHooks)
+
+	gspec := &core.Genesis{
+		Config:  params.TestChainConfig,
+		Alloc:   types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	signer := types.LatestSigner(gspec.Config)
+	const numBlocks = 2
+	db, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), numBlocks, func(i int, b *core.BlockGen) {
+		if i == 0 {
+			return // hooks MUST still be called for empty blocks
+		}
+		b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    b.TxNonce(addr),
+			To:       &common.Address{},
+			Gas:      params.TxGas,
+			GasPrice: b.BaseFee(),
+		}))
+	})
+	require.Len(t, hooks.before, numBlocks, "BeforeBlock() calls during GenerateChain()")
+	require.Len(t, hooks.after, numBlocks, "AfterBlock() calls during GenerateChain()")
+
+	bc, err := core.NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err, "NewBlockChain()")
+	defer bc.Stop()
+
+	_, err = bc.InsertChain(blocks)
+	require.NoError(t, err, "InsertChain(GenerateChain())")
+
+	sdb, err := bc.State()
+	require.NoError(t, err, "State()")
+	assert.Equal(t, uint256.NewInt(3*numBlocks), sdb.GetBalance(hooks.reward), "balance modified by hooks")
+}
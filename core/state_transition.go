@@ -78,6 +78,7 @@ func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation b
 	dataLen := uint64(len(data))
 	// Bump the required gas by the amount of transactional data
 	if dataLen > 0 {
+		baseGas := gas // libevm
 		// Zero and non-zero bytes are priced differently
 		var nz uint64
 		for _, byt := range data {
@@ -101,6 +102,12 @@ func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation b
 		}
 		gas += z * params.TxDataZeroGas
 
+		dataGas, err := libevmDataGas(baseGas, gas-baseGas, data, rules) // libevm
+		if err != nil {
+			return 0, err
+		}
+		gas = baseGas + dataGas
+
 		if isContractCreation && rules.IsShanghai { // libevm: Shanghai introduced EIP-3860
 			lenWords := toWordSize(dataLen)
 			if (math.MaxUint64-gas)/params.InitCodeWordGas < lenWords {
@@ -151,6 +158,11 @@ type Message struct {
 	// account nonce in state. It also disables checking that the sender is an EOA.
 	// This field will be set to true for operations like RPC eth_call.
 	SkipAccountChecks bool
+
+	// libevm: set by [TransactionToMessage] for [types.SystemTxData]; see
+	// the equivalent methods for semantics.
+	Mint     *big.Int
+	SkipFees bool
 }
 
 // TransactionToMessage converts a transaction into a Message.
@@ -175,6 +187,7 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 	}
 	var err error
 	msg.From, err = types.Sender(s, tx)
+	msg.setSystemTxFields(tx) // libevm
 	return msg, err
 }
 
@@ -218,6 +231,8 @@ type StateTransition struct {
 	initialGas   uint64
 	state        vm.StateDB
 	evm          *vm.EVM
+
+	gasPayer common.Address // libevm: set by buyGas()
 }
 
 // NewStateTransition initialises and returns a new state transition object.
@@ -239,13 +254,20 @@ func (st *StateTransition) to() common.Address {
 }
 
 func (st *StateTransition) buyGas() error {
+	st.gasPayer = st.payerOfGas() // libevm
+
+	if st.msg.SkipFees { // libevm
+		return st.buyFreeGas()
+	}
 	mgval := new(big.Int).SetUint64(st.msg.GasLimit)
 	mgval = mgval.Mul(mgval, st.msg.GasPrice)
 	balanceCheck := new(big.Int).Set(mgval)
 	if st.msg.GasFeeCap != nil {
 		balanceCheck.SetUint64(st.msg.GasLimit)
 		balanceCheck = balanceCheck.Mul(balanceCheck, st.msg.GasFeeCap)
-		balanceCheck.Add(balanceCheck, st.msg.Value)
+		if st.gasPayer == st.msg.From { // libevm: otherwise checked by checkSponsoredValue()
+			balanceCheck.Add(balanceCheck, st.msg.Value)
+		}
 	}
 	if st.evm.ChainConfig().IsCancun(st.evm.Context.BlockNumber, st.evm.Context.Time) {
 		if blobGas := st.blobGasUsed(); blobGas > 0 {
@@ -261,10 +283,13 @@ func (st *StateTransition) buyGas() error {
 	}
 	balanceCheckU256, overflow := uint256.FromBig(balanceCheck)
 	if overflow {
-		return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, st.msg.From.Hex())
+		return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, st.gasPayer.Hex()) // libevm: was st.msg.From
 	}
-	if have, want := st.state.GetBalance(st.msg.From), balanceCheckU256; have.Cmp(want) < 0 {
-		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From.Hex(), have, want)
+	if have, want := st.state.GetBalance(st.gasPayer), balanceCheckU256; have.Cmp(want) < 0 { // libevm: was st.msg.From
+		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.gasPayer.Hex(), have, want)
+	}
+	if err := st.checkSponsoredValue(); err != nil { // libevm
+		return err
 	}
 	if err := st.gp.SubGas(st.msg.GasLimit); err != nil {
 		return err
@@ -273,7 +298,8 @@ func (st *StateTransition) buyGas() error {
 
 	st.initialGas = st.msg.GasLimit
 	mgvalU256, _ := uint256.FromBig(mgval)
-	st.state.SubBalance(st.msg.From, mgvalU256)
+	st.state.SubBalance(st.gasPayer, mgvalU256)                                        // libevm: was st.msg.From
+	st.captureBalanceChange(st.gasPayer, mgvalU256, true, vm.BalanceChangeGasPurchase) // libevm
 	return nil
 }
 
@@ -301,7 +327,7 @@ func (st *StateTransition) preCheck() error {
 		}
 	}
 	// Make sure that transaction gasFeeCap is greater than the baseFee (post london)
-	if st.evm.ChainConfig().IsLondon(st.evm.Context.BlockNumber) {
+	if st.evm.ChainConfig().IsLondon(st.evm.Context.BlockNumber) && !msg.SkipFees { // libevm: SkipFees
 		// Skip the checks if gas fields are zero and baseFee was explicitly disabled (eth_call)
 		skipCheck := st.evm.Config.NoBaseFee && msg.GasFeeCap.BitLen() == 0 && msg.GasTipCap.BitLen() == 0
 		if !skipCheck {
@@ -420,8 +446,8 @@ func (st *StateTransition) transitionDb() (*ExecutionResult, error) {
 	}
 
 	// Check whether the init code size has been exceeded.
-	if rules.IsShanghai && contractCreation && len(msg.Data) > params.MaxInitCodeSize {
-		return nil, fmt.Errorf("%w: code size %v limit %v", ErrMaxInitCodeSizeExceeded, len(msg.Data), params.MaxInitCodeSize)
+	if limit := rules.Hooks().MaxInitCodeSize(); rules.IsShanghai && contractCreation && len(msg.Data) > limit { // libevm: configurable limit
+		return nil, fmt.Errorf("%w: code size %v limit %v", ErrMaxInitCodeSizeExceeded, len(msg.Data), limit)
 	}
 
 	// Execute the preparatory steps for state transition which includes:
@@ -455,14 +481,12 @@ func (st *StateTransition) transitionDb() (*ExecutionResult, error) {
 	}
 	effectiveTipU256, _ := uint256.FromBig(effectiveTip)
 
-	if st.evm.Config.NoBaseFee && msg.GasFeeCap.Sign() == 0 && msg.GasTipCap.Sign() == 0 {
+	if st.evm.Config.NoBaseFee && msg.GasFeeCap.Sign() == 0 && msg.GasTipCap.Sign() == 0 || msg.SkipFees { // libevm: SkipFees
 		// Skip fee payment when NoBaseFee is set and the fee fields
 		// are 0. This avoids a negative effectiveTip being applied to
 		// the coinbase when simulating calls.
 	} else {
-		fee := new(uint256.Int).SetUint64(st.gasUsed())
-		fee.Mul(fee, effectiveTipU256)
-		st.state.AddBalance(st.evm.Context.Coinbase, fee)
+		st.distributeFees(rules, effectiveTipU256) // libevm: was crediting tip to Coinbase
 	}
 
 	return &ExecutionResult{
@@ -486,7 +510,8 @@ func (st *StateTransition) refundGas(refundQuotient uint64) uint64 {
 	// Return ETH for remaining gas, exchanged at the original rate.
 	remaining := uint256.NewInt(st.gasRemaining)
 	remaining = remaining.Mul(remaining, uint256.MustFromBig(st.msg.GasPrice))
-	st.state.AddBalance(st.msg.From, remaining)
+	st.state.AddBalance(st.gasPayer, remaining)                                       // libevm: was st.msg.From
+	st.captureBalanceChange(st.gasPayer, remaining, false, vm.BalanceChangeGasRefund) // libevm
 
 	// Also return remaining gas to the block gas counter so it is
 	// available for the next transaction.
@@ -17,9 +17,15 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"math/big"
 
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/num"
 	"github.com/ava-labs/libevm/core/types"
 	"github.com/ava-labs/libevm/core/vm"
 	"github.com/ava-labs/libevm/libevm"
@@ -56,14 +62,27 @@ var _ = (*vm.EVM)(nil)
 // libevm-specific behaviour: if, during execution, [vm.EVM.InvalidateExecution]
 // is called with a non-nil error then said error will be returned, wrapped. All
 // state transitions (e.g. nonce incrementing) will be reverted to a snapshot
-// taken before execution.
+// taken before execution. The same snapshot is reverted to if value was minted
+// by a system transaction that then fails with a consensus error.
 func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	if err := st.canExecuteTransaction(); err != nil {
 		return nil, err
 	}
+	if err := VerifyPredicates(st.rulesHooks(), st.blockHeader(), st.msg.From, st.msg.To, st.msg.AccessList); err != nil {
+		return nil, err
+	}
 
-	snap := st.state.Snapshot()   // computationally cheap operation
+	snap := st.state.Snapshot() // computationally cheap operation
+	minted, err := st.mint()
+	if err != nil {
+		return nil, err
+	}
 	res, err := st.transitionDb() // original geth implementation
+	if err != nil && minted {
+		// A consensus error invalidates the transaction, which MUST therefore
+		// not leave minted value to be spent by a later one.
+		st.state.RevertToSnapshot(snap)
+	}
 
 	// [NOTE]: At the time of implementation of this libevm override, non-nil
 	// values of `err` and `invalid` (below) are mutually exclusive. However, as
@@ -94,6 +113,118 @@ func (st *StateTransition) canExecuteTransaction() error {
 	return nil
 }
 
+// ErrInvalidPredicates is returned, wrapped, if the
+// [params.RulesHooks.VerifyPredicates] hook rejects a transaction.
+var ErrInvalidPredicates = errors.New("invalid transaction predicates")
+
+// VerifyPredicates is a convenience wrapper for calling the
+// [params.RulesHooks.VerifyPredicates] hook, which is skipped if the access
+// list is empty. A non-nil error returned by the hook is wrapped with
+// [ErrInvalidPredicates].
+func VerifyPredicates(hooks params.RulesHooks, header *types.Header, from common.Address, to *common.Address, al types.AccessList) error {
+	if len(al) == 0 {
+		return nil
+	}
+	if err := hooks.VerifyPredicates(header, from, to, libevmAccessList(al)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPredicates, err)
+	}
+	return nil
+}
+
+// blockHeader returns the header of the block in which the transaction is
+// being executed. As [vm.BlockContext.Header] isn't guaranteed to be set, a
+// partial header is otherwise derived from the other context fields.
+func (st *StateTransition) blockHeader() *types.Header {
+	ctx := st.evm.Context
+	if ctx.Header != nil {
+		return ctx.Header
+	}
+	return &types.Header{
+		Number:     ctx.BlockNumber,
+		Time:       ctx.Time,
+		Coinbase:   ctx.Coinbase,
+		BaseFee:    ctx.BaseFee,
+		GasLimit:   ctx.GasLimit,
+		Difficulty: ctx.Difficulty,
+	}
+}
+
+// setSystemTxFields populates the libevm-specific fields of the [Message] if
+// `tx` is a system transaction. Fees are zeroed so that they can never be
+// charged nor refunded, and account checks are skipped as the sender is
+// defined by consensus and MAY be a contract.
+func (msg *Message) setSystemTxFields(tx *types.Transaction) {
+	sys, ok := tx.SystemData()
+	if !ok {
+		return
+	}
+	if m := sys.Mint(); m != nil {
+		msg.Mint = new(big.Int).Set(m)
+	}
+	msg.SkipAccountChecks = true
+	if !sys.PaysFees() {
+		msg.SkipFees = true
+		msg.GasPrice = new(big.Int)
+		msg.GasFeeCap = new(big.Int)
+		msg.GasTipCap = new(big.Int)
+	}
+}
+
+// mint credits [Message.Mint], if any, to the sender, reporting whether a
+// non-zero value was credited.
+func (st *StateTransition) mint() (bool, error) {
+	if st.msg.Mint == nil || st.msg.Mint.Sign() == 0 {
+		return false, nil
+	}
+	m, overflow := num.U256FromBig(st.msg.Mint)
+	if overflow {
+		return false, fmt.Errorf("invalid mint value %v for address %v", st.msg.Mint, st.msg.From.Hex())
+	}
+	st.state.AddBalance(st.msg.From, m)
+	st.captureBalanceChange(st.msg.From, m, false, vm.BalanceChangeMint)
+	return true, nil
+}
+
+// buyFreeGas is the equivalent of [StateTransition.buyGas] when
+// [Message.SkipFees] is true. The gas is consumed from the block's limit but no
+// balance is charged.
+func (st *StateTransition) buyFreeGas() error {
+	if err := st.gp.SubGas(st.msg.GasLimit); err != nil {
+		return err
+	}
+	st.gasRemaining += st.msg.GasLimit
+	st.initialGas = st.msg.GasLimit
+	return nil
+}
+
+// payerOfGas is a convenience wrapper for calling the
+// [params.RulesHooks.GasPayer] hook, returning the sender if the transaction
+// isn't sponsored.
+func (st *StateTransition) payerOfGas() common.Address {
+	if payer, ok := st.rulesHooks().GasPayer(st.msg.From, st.msg.To, st.state); ok {
+		return payer
+	}
+	return st.msg.From
+}
+
+// checkSponsoredValue checks that the sender of a sponsored transaction can
+// afford the value being transferred, which is otherwise included in the
+// balance check performed by [StateTransition.buyGas].
+func (st *StateTransition) checkSponsoredValue() error {
+	if st.gasPayer == st.msg.From || st.msg.GasFeeCap == nil {
+		return nil
+	}
+	from := st.msg.From
+	value, overflow := num.U256FromBig(st.msg.Value)
+	if overflow {
+		return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, from.Hex())
+	}
+	if have := st.state.GetBalance(from); have.Cmp(value) < 0 {
+		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, from.Hex(), have, value)
+	}
+	return nil
+}
+
 // afterGasRefund updates the gas remaining to reflect the
 // [params.RulesHooks.ShouldRefundGas] and
 // [params.RulesHooks.MinimumGasConsumption] methods. It MUST be called after
@@ -115,6 +246,75 @@ func (st *StateTransition) afterGasRefund(refunded uint64) {
 	)
 }
 
+// distributeFees credits the fees paid for gas to the recipients returned by
+// the [params.RulesHooks.FeeRecipients] hook. The default recipients result in
+// the same behaviour as upstream geth: the tip is credited to the coinbase and
+// the base fee is burnt.
+func (st *StateTransition) distributeFees(rules params.Rules, effectiveTip *uint256.Int) {
+	gasUsed := uint256.NewInt(st.gasUsed())
+	tip := new(uint256.Int).Mul(gasUsed, effectiveTip)
+	baseFee := st.baseFeePaid(rules, gasUsed)
+
+	baseFeeTo, tipTo := rules.Hooks().FeeRecipients(st.blockHeader(), baseFee.Clone(), tip.Clone())
+
+	st.state.AddBalance(tipTo, tip)
+	st.captureBalanceChange(tipTo, tip, false, vm.BalanceChangeTip)
+
+	if baseFeeTo == nil || baseFee.IsZero() {
+		return
+	}
+	st.state.AddBalance(*baseFeeTo, baseFee)
+	st.captureBalanceChange(*baseFeeTo, baseFee, false, vm.BalanceChangeBaseFee)
+}
+
+// baseFeePaid returns the base-fee portion of the fees paid for `gasUsed`. It
+// is zero before London and if the EVM is configured with NoBaseFee, in which
+// case the sender isn't guaranteed to have paid the base fee; crediting it
+// would therefore mint value, e.g. during eth_call.
+func (st *StateTransition) baseFeePaid(rules params.Rules, gasUsed *uint256.Int) *uint256.Int {
+	if !rules.IsLondon || st.evm.Config.NoBaseFee || st.evm.Context.BaseFee == nil {
+		return new(uint256.Int)
+	}
+	baseFee, overflow := uint256.FromBig(st.evm.Context.BaseFee)
+	if overflow {
+		// Unreachable as the base fee is bounded by the fee cap, which was
+		// already deducted from the payer's balance.
+		return new(uint256.Int)
+	}
+	return baseFee.Mul(baseFee, gasUsed)
+}
+
+// captureBalanceChange reports, to the tracer if it is a
+// [vm.BalanceChangeLogger], a change of `delta` to the balance of `addr`. The
+// change MUST have already been applied to the state, and is a debit iff
+// `debit` is true. Zero-value changes are not reported.
+func (st *StateTransition) captureBalanceChange(addr common.Address, delta *uint256.Int, debit bool, reason vm.BalanceChangeReason) {
+	tracer := st.evm.Config.Tracer
+	if _, ok := tracer.(vm.BalanceChangeLogger); !ok || delta.IsZero() {
+		return
+	}
+	curr := new(uint256.Int).Set(st.state.GetBalance(addr))
+	prev := new(uint256.Int)
+	if debit {
+		prev.Add(curr, delta)
+	} else {
+		prev.Sub(curr, delta)
+	}
+	vm.CaptureBalanceChange(tracer, addr, prev, curr, reason)
+}
+
+// libevmDataGas is a convenience wrapper for calling the
+// [params.RulesHooks.DataGas] hook, which receives the default, per-byte gas
+// charged for the transaction data. It returns [ErrGasUintOverflow] if the
+// returned gas can't be added to `currGas`.
+func libevmDataGas(currGas, defaultGas uint64, data []byte, rules params.Rules) (uint64, error) {
+	gas := rules.Hooks().DataGas(data, defaultGas)
+	if math.MaxUint64-currGas < gas {
+		return 0, ErrGasUintOverflow
+	}
+	return gas, nil
+}
+
 // libevmAccessListGas is a convenience wrapper for calling the
 // [params.RulesHooks.AccessListGas] hook. It converts the raw access list to a
 // DTO and calls the hook. Returns the gas to be charged for the access list,
@@ -123,15 +323,7 @@ func (st *StateTransition) afterGasRefund(refunded uint64) {
 // would cause an overflow with [currGas]. It MUST be called with a non-nil
 // access list.
 func libevmAccessListGas(currGas uint64, raw types.AccessList, rules params.Rules) (gas uint64, override bool, err error) {
-	list := make(libevm.AccessList, len(raw))
-	for i, tuple := range raw {
-		list[i] = libevm.AccessTuple{
-			Address:     tuple.Address,
-			StorageKeys: tuple.StorageKeys,
-		}
-	}
-
-	hookGas, override, err := rules.Hooks().AccessListGas(list)
+	hookGas, override, err := rules.Hooks().AccessListGas(libevmAccessList(raw))
 	if !override || err != nil {
 		return 0, false, err
 	}
@@ -140,3 +332,15 @@ func libevmAccessListGas(currGas uint64, raw types.AccessList, rules params.Rule
 	}
 	return hookGas, true, nil
 }
+
+// libevmAccessList converts the access list to its DTO equivalent.
+func libevmAccessList(raw types.AccessList) libevm.AccessList {
+	list := make(libevm.AccessList, len(raw))
+	for i, tuple := range raw {
+		list[i] = libevm.AccessTuple{
+			Address:     tuple.Address,
+			StorageKeys: tuple.StorageKeys,
+		}
+	}
+	return list
+}
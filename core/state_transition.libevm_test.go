@@ -16,6 +16,7 @@
 package core_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/txpool"
 	"github.com/ava-labs/libevm/core/types"
 	"github.com/ava-labs/libevm/core/vm"
 	"github.com/ava-labs/libevm/crypto"
@@ -366,3 +368,817 @@ func TestGasRefunds(t *testing.T) {
 		})
 	}
 }
+
+func TestGasPayer(t *testing.T) {
+	const (
+		gasLimit = 50_000
+		value    = 10
+	)
+	var (
+		sender    = common.Address{'s', 'e', 'n', 'd', 'e', 'r'}
+		sponsor   = common.Address{'s', 'p', 'o', 'n', 's', 'o', 'r'}
+		recipient = common.Address{'t', 'o'}
+	)
+
+	tests := []struct {
+		name                            string
+		payer                           *common.Address // nil => not sponsored
+		nonce                           uint64
+		senderBalance, payerBalance     uint64
+		wantErr                         error
+		wantErrContains                 string
+		wantSenderDebit, wantPayerDebit uint64
+	}{
+		{
+			name:            "not_sponsored",
+			senderBalance:   gasLimit + value,
+			wantSenderDebit: params.TxGas + value,
+		},
+		{
+			name:            "not_sponsored_insufficient_funds",
+			senderBalance:   gasLimit + value - 1,
+			wantErr:         core.ErrInsufficientFunds,
+			wantErrContains: sender.Hex(),
+		},
+		{
+			name:            "sponsored_by_sender",
+			payer:           &sender,
+			senderBalance:   gasLimit + value,
+			wantSenderDebit: params.TxGas + value,
+		},
+		{
+			name:            "sponsored",
+			payer:           &sponsor,
+			senderBalance:   value,
+			payerBalance:    gasLimit,
+			wantSenderDebit: value,
+			wantPayerDebit:  params.TxGas,
+		},
+		{
+			name:            "payer_insufficient_funds",
+			payer:           &sponsor,
+			senderBalance:   gasLimit + value,
+			payerBalance:    gasLimit - 1,
+			wantErr:         core.ErrInsufficientFunds,
+			wantErrContains: sponsor.Hex(),
+		},
+		{
+			name:            "sender_insufficient_value",
+			payer:           &sponsor,
+			senderBalance:   value - 1,
+			payerBalance:    gasLimit + value,
+			wantErr:         core.ErrInsufficientFunds,
+			wantErrContains: sender.Hex(),
+		},
+		{
+			name:            "sender_nonce_checked",
+			payer:           &sponsor,
+			nonce:           1,
+			senderBalance:   value,
+			payerBalance:    gasLimit,
+			wantErr:         core.ErrNonceTooHigh,
+			wantErrContains: sender.Hex(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := &hookstest.Stub{
+				GasPayerFn: func(from common.Address, to *common.Address, _ libevm.StateReader) (common.Address, bool) {
+					assert.Equal(t, sender, from, "GasPayer(from)")
+					assert.Equal(t, &recipient, to, "GasPayer(to)")
+					if tt.payer == nil {
+						return common.Address{}, false
+					}
+					return *tt.payer, true
+				},
+			}
+			hooks.Register(t)
+
+			sdb, evm := ethtest.NewZeroEVM(t)
+			sdb.SetBalance(sender, uint256.NewInt(tt.senderBalance))
+			sdb.SetBalance(sponsor, uint256.NewInt(tt.payerBalance))
+
+			msg := &core.Message{
+				From:      sender,
+				To:        &recipient,
+				Nonce:     tt.nonce,
+				Value:     big.NewInt(value),
+				GasLimit:  gasLimit,
+				GasPrice:  big.NewInt(1),
+				GasFeeCap: big.NewInt(1),
+				GasTipCap: big.NewInt(1),
+			}
+			res, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(30e6))
+			require.ErrorIs(t, err, tt.wantErr, "core.ApplyMessage()")
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErrContains, "core.ApplyMessage()")
+				return
+			}
+			require.NoError(t, res.Err, "%T.Err", res)
+
+			balance := func(a common.Address) uint64 {
+				return sdb.GetBalance(a).Uint64()
+			}
+			assert.Equal(t, tt.senderBalance-tt.wantSenderDebit, balance(sender), "sender balance")
+			assert.Equal(t, tt.payerBalance-tt.wantPayerDebit, balance(sponsor), "sponsor balance")
+			assert.Equal(t, uint64(value), balance(recipient), "recipient balance")
+			assert.Equal(t, uint64(1), sdb.GetNonce(sender), "sender nonce")
+			assert.Zero(t, sdb.GetNonce(sponsor), "sponsor nonce")
+		})
+	}
+}
+
+// depositTx is a minimal [types.SystemTxData]. Only the methods required by
+// [core.TransactionToMessage] are implemented; the embedded interface is nil.
+type depositTx struct {
+	types.CustomTxData
+	from, to    common.Address
+	mint, value *big.Int
+	gas         uint64
+	gasPrice    *big.Int
+	paysFees    bool
+}
+
+const depositTxType = 0x7e
+
+func (tx *depositTx) TxType() byte                 { return depositTxType }
+func (tx *depositTx) From() common.Address         { return tx.from }
+func (tx *depositTx) Mint() *big.Int               { return tx.mint }
+func (tx *depositTx) PaysFees() bool               { return tx.paysFees }
+func (tx *depositTx) To() *common.Address          { return &tx.to }
+func (tx *depositTx) Value() *big.Int              { return tx.value }
+func (tx *depositTx) Gas() uint64                  { return tx.gas }
+func (tx *depositTx) GasPrice() *big.Int           { return tx.gasPrice }
+func (tx *depositTx) GasFeeCap() *big.Int          { return tx.gasPrice }
+func (tx *depositTx) GasTipCap() *big.Int          { return tx.gasPrice }
+func (tx *depositTx) Nonce() uint64                { return 0 }
+func (tx *depositTx) Data() []byte                 { return nil }
+func (tx *depositTx) AccessList() types.AccessList { return nil }
+
+func (tx *depositTx) Copy() types.CustomTxData {
+	cp := *tx
+	return &cp
+}
+
+func (tx *depositTx) RawSignatureValues() (v, r, s *big.Int) {
+	return new(big.Int), new(big.Int), new(big.Int)
+}
+
+func TestSystemTx(t *testing.T) {
+	types.TestOnlyClearRegisteredTxTypes()
+	t.Cleanup(types.TestOnlyClearRegisteredTxTypes)
+	types.RegisterTxType(depositTxType, func([]byte) (types.CustomTxData, error) {
+		return nil, errors.New("unimplemented")
+	})
+
+	rng := ethtest.NewPseudoRand(3512)
+	var (
+		system    = rng.Address()
+		recipient = rng.Address()
+		coinbase  = rng.Address()
+	)
+	const (
+		mint     = 1e9
+		value    = 1e6
+		gasLimit = 1e5
+		baseFee  = 10
+	)
+	chainID := big.NewInt(1)
+
+	tests := []struct {
+		name            string
+		paysFees        bool
+		gasPrice        int64
+		wantSystemDebit uint64
+		wantCoinbase    uint64
+	}{
+		{
+			name:            "fee_free",
+			gasPrice:        0, // would otherwise fail the base-fee check
+			wantSystemDebit: value,
+		},
+		{
+			name:            "fee_free_ignores_price",
+			gasPrice:        baseFee + 1,
+			wantSystemDebit: value,
+		},
+		{
+			name:            "pays_fees",
+			paysFees:        true,
+			gasPrice:        baseFee + 1,
+			wantSystemDebit: value + params.TxGas*(baseFee+1),
+			wantCoinbase:    params.TxGas,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdb, evm := ethtest.NewZeroEVM(
+				t,
+				ethtest.WithChainConfig(&params.ChainConfig{
+					ChainID:     chainID,
+					LondonBlock: big.NewInt(0),
+				}),
+				ethtest.WithBlockContext(vm.BlockContext{
+					CanTransfer: core.CanTransfer,
+					Transfer:    core.Transfer,
+					BlockNumber: big.NewInt(1),
+					BaseFee:     big.NewInt(baseFee),
+					Coinbase:    coinbase,
+				}),
+			)
+
+			tx := types.NewCustomTx(&depositTx{
+				from:     system,
+				to:       recipient,
+				mint:     big.NewInt(mint),
+				value:    big.NewInt(value),
+				gas:      gasLimit,
+				gasPrice: big.NewInt(tt.gasPrice),
+				paysFees: tt.paysFees,
+			})
+			require.True(t, tx.IsSystemTx(), "IsSystemTx()")
+
+			msg, err := core.TransactionToMessage(tx, types.LatestSignerForChainID(chainID), big.NewInt(baseFee))
+			require.NoError(t, err, "core.TransactionToMessage()")
+			assert.Equal(t, system, msg.From, "%T.From", msg)
+			assert.Equal(t, !tt.paysFees, msg.SkipFees, "%T.SkipFees", msg)
+			assert.True(t, msg.SkipAccountChecks, "%T.SkipAccountChecks", msg)
+
+			gp := new(core.GasPool).AddGas(30e6)
+			res, err := core.ApplyMessage(evm, msg, gp)
+			require.NoError(t, err, "core.ApplyMessage()")
+			require.NoError(t, res.Err, "%T.Err", res)
+
+			balance := func(a common.Address) uint64 {
+				return sdb.GetBalance(a).Uint64()
+			}
+			assert.Equal(t, mint-tt.wantSystemDebit, balance(system), "minted sender balance")
+			assert.Equal(t, uint64(value), balance(recipient), "recipient balance")
+			assert.Equal(t, tt.wantCoinbase, balance(coinbase), "coinbase balance")
+			assert.Equal(t, uint64(1), sdb.GetNonce(system), "sender nonce")
+			assert.Equal(t, uint64(30e6-params.TxGas), gp.Gas(), "gas remaining in pool")
+		})
+	}
+}
+
+func TestSizeLimitHooks(t *testing.T) {
+	const (
+		maxInitCode = 64
+		maxData     = 128
+	)
+	hooks := &hookstest.Stub{
+		MaxInitCodeSizeFn: func() int { return maxInitCode },
+		MaxTxDataBytesFn:  func() int { return maxData },
+	}
+	hooks.Register(t)
+
+	config := params.MergedTestChainConfig
+	head := &types.Header{
+		Number:   big.NewInt(1),
+		GasLimit: 30e6,
+	}
+	newEVM := func(t *testing.T) *vm.EVM {
+		t.Helper()
+		_, evm := ethtest.NewZeroEVM(t,
+			ethtest.WithChainConfig(config),
+			ethtest.WithBlockContext(vm.BlockContext{
+				CanTransfer: core.CanTransfer,
+				Transfer:    core.Transfer,
+				BlockNumber: head.Number,
+				BaseFee:     big.NewInt(0),
+				Random:      &common.Hash{}, // implies post-Merge, required for Shanghai
+			}),
+		)
+		return evm
+	}
+
+	// Each layer MUST agree on which input is acceptable; the tx pool and RPC
+	// are additionally subject to the data limit.
+	tests := []struct {
+		name             string
+		create           bool
+		size             int
+		wantConsensus    error
+		wantPool         error
+		wantCreateToFail bool
+	}{
+		{
+			name:   "init_code_at_limit",
+			create: true,
+			size:   maxInitCode,
+		},
+		{
+			name:             "init_code_over_limit",
+			create:           true,
+			size:             maxInitCode + 1,
+			wantConsensus:    core.ErrMaxInitCodeSizeExceeded,
+			wantPool:         core.ErrMaxInitCodeSizeExceeded,
+			wantCreateToFail: true,
+		},
+		{
+			name: "data_at_limit",
+			size: maxData,
+		},
+		{
+			name:     "data_over_limit",
+			size:     maxData + 1,
+			wantPool: txpool.ErrOversizedData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := make([]byte, tt.size)
+			var to *common.Address
+			if !tt.create {
+				to = &common.Address{'t', 'o'}
+			}
+
+			t.Run("state_transition", func(t *testing.T) {
+				msg := &core.Message{
+					To:        to,
+					Value:     big.NewInt(0),
+					GasLimit:  1e6,
+					GasPrice:  big.NewInt(0),
+					GasFeeCap: big.NewInt(0),
+					GasTipCap: big.NewInt(0),
+					Data:      data,
+				}
+				_, err := core.ApplyMessage(newEVM(t), msg, new(core.GasPool).AddGas(30e6))
+				require.ErrorIs(t, err, tt.wantConsensus, "core.ApplyMessage()")
+			})
+
+			t.Run("txpool", func(t *testing.T) {
+				tx := types.NewTx(&types.DynamicFeeTx{
+					To:        to,
+					Gas:       1e6,
+					GasFeeCap: big.NewInt(0),
+					GasTipCap: big.NewInt(0),
+					Data:      data,
+				})
+				opts := &txpool.ValidationOptions{
+					Config:  config,
+					Accept:  1 << types.DynamicFeeTxType,
+					MaxSize: math.MaxUint64,
+					MinTip:  big.NewInt(0),
+				}
+				err := txpool.ValidateTransaction(tx, head, types.LatestSigner(config), opts)
+				if tt.wantPool == nil {
+					// The unsigned transaction is invalid for other reasons,
+					// which are checked after size limits.
+					require.ErrorIs(t, err, txpool.ErrInvalidSender, "txpool.ValidateTransaction()")
+					return
+				}
+				require.ErrorIs(t, err, tt.wantPool, "txpool.ValidateTransaction()")
+			})
+
+			if !tt.create {
+				return
+			}
+			t.Run("CREATE", func(t *testing.T) {
+				evm := newEVM(t)
+				creator := common.Address{'c', 'r', 'e', 'a', 't', 'o', 'r'}
+				evm.StateDB.SetCode(creator, []byte{
+					byte(vm.PUSH1), byte(tt.size), byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.CREATE),
+				})
+				_, _, err := evm.Call(vm.AccountRef{}, creator, nil, 1e6, uint256.NewInt(0))
+				if tt.wantCreateToFail {
+					require.Error(t, err, "CREATE with oversized init code")
+					return
+				}
+				require.NoError(t, err, "CREATE")
+			})
+		})
+	}
+}
+
+func TestFeeRecipients(t *testing.T) {
+	const (
+		baseFee = 2
+		tip     = 1
+	)
+	var (
+		sender   = common.Address{'s', 'e', 'n', 'd', 'e', 'r'}
+		coinbase = common.Address{'c', 'o', 'i', 'n'}
+		treasury = common.Address{'t', 'r', 'e', 'a', 's', 'u', 'r', 'y'}
+		tipper   = common.Address{'t', 'i', 'p'}
+	)
+
+	tests := []struct {
+		name              string
+		noBaseFee         bool
+		baseFeeTo         *common.Address
+		tipTo             common.Address
+		wantBaseFee       uint64
+		wantBalanceChange map[common.Address]uint64
+	}{
+		{
+			name:        "default",
+			tipTo:       coinbase,
+			wantBaseFee: params.TxGas * baseFee,
+			wantBalanceChange: map[common.Address]uint64{
+				coinbase: params.TxGas * tip,
+			},
+		},
+		{
+			name:        "base_fee_to_treasury",
+			baseFeeTo:   &treasury,
+			tipTo:       coinbase,
+			wantBaseFee: params.TxGas * baseFee,
+			wantBalanceChange: map[common.Address]uint64{
+				coinbase: params.TxGas * tip,
+				treasury: params.TxGas * baseFee,
+			},
+		},
+		{
+			name:        "all_to_treasury",
+			baseFeeTo:   &treasury,
+			tipTo:       treasury,
+			wantBaseFee: params.TxGas * baseFee,
+			wantBalanceChange: map[common.Address]uint64{
+				treasury: params.TxGas * (baseFee + tip),
+			},
+		},
+		{
+			name:        "tip_redirected",
+			tipTo:       tipper,
+			wantBaseFee: params.TxGas * baseFee,
+			wantBalanceChange: map[common.Address]uint64{
+				tipper: params.TxGas * tip,
+			},
+		},
+		{
+			name:      "no_base_fee_not_credited",
+			noBaseFee: true,
+			baseFeeTo: &treasury,
+			tipTo:     coinbase,
+			wantBalanceChange: map[common.Address]uint64{
+				coinbase: params.TxGas * tip,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := &hookstest.Stub{
+				FeeRecipientsFn: func(header libevm.BlockHeader, gotBaseFee, gotTip *uint256.Int) (*common.Address, common.Address) {
+					assert.Equal(t, coinbase, header.BlockCoinbase(), "FeeRecipients(header).BlockCoinbase()")
+					assert.Equal(t, tt.wantBaseFee, gotBaseFee.Uint64(), "FeeRecipients(baseFee)")
+					assert.Equal(t, uint64(params.TxGas*tip), gotTip.Uint64(), "FeeRecipients(tip)")
+					return tt.baseFeeTo, tt.tipTo
+				},
+			}
+			hooks.Register(t)
+
+			sdb, evm := ethtest.NewZeroEVM(t,
+				ethtest.WithChainConfig(params.TestChainConfig),
+				ethtest.WithBlockContext(vm.BlockContext{
+					CanTransfer: core.CanTransfer,
+					Transfer:    core.Transfer,
+					Coinbase:    coinbase,
+					BlockNumber: big.NewInt(1),
+					BaseFee:     big.NewInt(baseFee),
+				}),
+			)
+			// Set after construction because [vm.NewEVM] requires a non-nil
+			// gas price when NoBaseFee is true.
+			evm.Config.NoBaseFee = tt.noBaseFee
+			const funds = 1e6
+			sdb.SetBalance(sender, uint256.NewInt(funds))
+
+			msg := &core.Message{
+				From:      sender,
+				To:        &common.Address{},
+				GasLimit:  params.TxGas,
+				GasPrice:  big.NewInt(baseFee + tip),
+				GasFeeCap: big.NewInt(baseFee + 10*tip),
+				GasTipCap: big.NewInt(tip),
+				Value:     big.NewInt(0),
+			}
+			res, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(30e6))
+			require.NoError(t, err, "core.ApplyMessage()")
+			require.NoError(t, res.Err, "%T.Err", res)
+
+			assert.Equal(t, uint64(funds-params.TxGas*(baseFee+tip)), sdb.GetBalance(sender).Uint64(), "sender balance")
+			for _, addr := range []common.Address{coinbase, treasury, tipper} {
+				assert.Equalf(t, tt.wantBalanceChange[addr], sdb.GetBalance(addr).Uint64(), "balance of %v", addr)
+			}
+		})
+	}
+}
+
+func TestVerifyPredicates(t *testing.T) {
+	predicateAddr := common.Address{'p', 'r', 'e', 'd'}
+	errBadPredicate := errors.New("bad predicate")
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	to := &common.Address{'t', 'o'}
+
+	head := &types.Header{
+		Number:   big.NewInt(1),
+		Time:     42,
+		GasLimit: 30e6,
+	}
+
+	var calls int
+	hooks := &hookstest.Stub{
+		VerifyPredicatesFn: func(header libevm.BlockHeader, from common.Address, gotTo *common.Address, al libevm.AccessList) error {
+			calls++
+			assert.Equal(t, head.Number, header.BlockNumber(), "VerifyPredicates(header).BlockNumber()")
+			assert.Equal(t, head.Time, header.BlockTime(), "VerifyPredicates(header).BlockTime()")
+			assert.Equal(t, sender, from, "VerifyPredicates(from)")
+			assert.Equal(t, to, gotTo, "VerifyPredicates(to)")
+			for _, tuple := range al {
+				if tuple.Address == predicateAddr && len(tuple.StorageKeys) != 1 {
+					return errBadPredicate
+				}
+			}
+			return nil
+		},
+	}
+	hooks.Register(t)
+
+	config := params.MergedTestChainConfig
+
+	tests := []struct {
+		name       string
+		accessList types.AccessList
+		wantCalls  int
+		wantErr    error
+	}{
+		{
+			name: "empty_access_list",
+		},
+		{
+			name: "valid",
+			accessList: types.AccessList{
+				{Address: predicateAddr, StorageKeys: []common.Hash{{}}},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "invalid",
+			accessList: types.AccessList{
+				{Address: predicateAddr},
+			},
+			wantCalls: 1,
+			wantErr:   core.ErrInvalidPredicates,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Run("state_transition", func(t *testing.T) {
+				calls = 0
+				sdb, evm := ethtest.NewZeroEVM(t,
+					ethtest.WithChainConfig(config),
+					ethtest.WithBlockContext(vm.BlockContext{
+						CanTransfer: core.CanTransfer,
+						Transfer:    core.Transfer,
+						BlockNumber: head.Number,
+						Time:        head.Time,
+						BaseFee:     big.NewInt(0),
+						Random:      &common.Hash{},
+					}),
+				)
+				msg := &core.Message{
+					From:       sender,
+					To:         to,
+					Value:      big.NewInt(0),
+					GasLimit:   1e6,
+					GasPrice:   big.NewInt(0),
+					GasFeeCap:  big.NewInt(0),
+					GasTipCap:  big.NewInt(0),
+					AccessList: tt.accessList,
+				}
+				_, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(30e6))
+				require.ErrorIs(t, err, tt.wantErr, "core.ApplyMessage()")
+				assert.Equal(t, tt.wantCalls, calls, "VerifyPredicates() calls")
+				if tt.wantErr != nil {
+					assert.Zero(t, sdb.GetNonce(sender), "nonce after rejection")
+				}
+			})
+
+			t.Run("txpool", func(t *testing.T) {
+				calls = 0
+				signer := types.LatestSigner(config)
+				tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+					ChainID:    config.ChainID,
+					To:         to,
+					Gas:        1e6,
+					GasFeeCap:  big.NewInt(0),
+					GasTipCap:  big.NewInt(0),
+					AccessList: tt.accessList,
+				})
+				opts := &txpool.ValidationOptions{
+					Config:  config,
+					Accept:  1 << types.DynamicFeeTxType,
+					MaxSize: math.MaxUint64,
+					MinTip:  big.NewInt(0),
+				}
+				err := txpool.ValidateTransaction(tx, head, signer, opts)
+				require.ErrorIs(t, err, tt.wantErr, "txpool.ValidateTransaction()")
+				assert.Equal(t, tt.wantCalls, calls, "VerifyPredicates() calls")
+			})
+		})
+	}
+}
+
+func TestDataGasHook(t *testing.T) {
+	var (
+		gotDefault uint64
+		overflow   bool
+	)
+	hooks := &hookstest.Stub{
+		DataGasFn: func(data []byte, defaultGas uint64) uint64 {
+			gotDefault = defaultGas
+			if overflow {
+				return math.MaxUint64
+			}
+			// A crude compression estimate, which is far below the default.
+			return uint64(len(data))
+		},
+	}
+	hooks.Register(t)
+
+	config := params.MergedTestChainConfig
+	head := &types.Header{
+		Number:   big.NewInt(1),
+		GasLimit: 30e6,
+	}
+	rules := config.Rules(head.Number, true, 0)
+
+	const dataLen = 100
+	data := bytes.Repeat([]byte{1}, dataLen)
+
+	t.Run("IntrinsicGas", func(t *testing.T) {
+		got, err := core.IntrinsicGas(data, nil, false, rules)
+		require.NoError(t, err, "core.IntrinsicGas()")
+		assert.Equal(t, params.TxGas+dataLen, got, "core.IntrinsicGas()")
+		assert.Equal(t, dataLen*params.TxDataNonZeroGasEIP2028, gotDefault, "default gas passed to hook")
+
+		gotDefault = 0
+		got, err = core.IntrinsicGas(nil, nil, false, rules)
+		require.NoError(t, err, "core.IntrinsicGas(nil, ...)")
+		assert.Equal(t, params.TxGas, got, "core.IntrinsicGas(nil, ...)")
+		assert.Zero(t, gotDefault, "hook not called for empty data")
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		overflow = true
+		defer func() { overflow = false }()
+		_, err := core.IntrinsicGas(data, nil, false, rules)
+		require.ErrorIs(t, err, core.ErrGasUintOverflow, "core.IntrinsicGas()")
+	})
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	to := &common.Address{'t', 'o'}
+
+	// Each layer MUST agree on the intrinsic gas.
+	tests := []struct {
+		gasLimit uint64
+		wantErr  error
+	}{
+		{
+			gasLimit: params.TxGas + dataLen,
+		},
+		{
+			gasLimit: params.TxGas + dataLen - 1,
+			wantErr:  core.ErrIntrinsicGas,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("gas_limit_%d", tt.gasLimit), func(t *testing.T) {
+			t.Run("state_transition", func(t *testing.T) {
+				_, evm := ethtest.NewZeroEVM(t,
+					ethtest.WithChainConfig(config),
+					ethtest.WithBlockContext(vm.BlockContext{
+						CanTransfer: core.CanTransfer,
+						Transfer:    core.Transfer,
+						BlockNumber: head.Number,
+						BaseFee:     big.NewInt(0),
+						Random:      &common.Hash{},
+					}),
+				)
+				msg := &core.Message{
+					From:      sender,
+					To:        to,
+					Value:     big.NewInt(0),
+					GasLimit:  tt.gasLimit,
+					GasPrice:  big.NewInt(0),
+					GasFeeCap: big.NewInt(0),
+					GasTipCap: big.NewInt(0),
+					Data:      data,
+				}
+				_, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(30e6))
+				require.ErrorIs(t, err, tt.wantErr, "core.ApplyMessage()")
+			})
+
+			t.Run("txpool", func(t *testing.T) {
+				signer := types.LatestSigner(config)
+				tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+					ChainID:   config.ChainID,
+					To:        to,
+					Gas:       tt.gasLimit,
+					GasFeeCap: big.NewInt(0),
+					GasTipCap: big.NewInt(0),
+					Data:      data,
+				})
+				opts := &txpool.ValidationOptions{
+					Config:  config,
+					Accept:  1 << types.DynamicFeeTxType,
+					MaxSize: math.MaxUint64,
+					MinTip:  big.NewInt(0),
+				}
+				err := txpool.ValidateTransaction(tx, head, signer, opts)
+				require.ErrorIs(t, err, tt.wantErr, "txpool.ValidateTransaction()")
+			})
+		})
+	}
+}
+
+func TestSystemTxMintRevertedOnConsensusError(t *testing.T) {
+	types.TestOnlyClearRegisteredTxTypes()
+	t.Cleanup(types.TestOnlyClearRegisteredTxTypes)
+	types.RegisterTxType(depositTxType, func([]byte) (types.CustomTxData, error) {
+		return nil, errors.New("unimplemented")
+	})
+
+	rng := ethtest.NewPseudoRand(3512)
+	var (
+		system    = rng.Address()
+		recipient = rng.Address()
+	)
+	const (
+		mint     = 1e9
+		gasLimit = 1e5
+		baseFee  = 10
+	)
+	chainID := big.NewInt(1)
+
+	tests := []struct {
+		name     string
+		paysFees bool
+		gasPrice int64
+		blockGas uint64
+		wantErr  error
+	}{
+		{
+			name:     "block_gas_exhausted",
+			gasPrice: 0,
+			blockGas: gasLimit - 1,
+			wantErr:  core.ErrGasLimitReached,
+		},
+		{
+			name:     "fees_exceed_mint",
+			paysFees: true,
+			gasPrice: mint, // gasLimit*mint > mint
+			blockGas: 30e6,
+			wantErr:  core.ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdb, evm := ethtest.NewZeroEVM(
+				t,
+				ethtest.WithChainConfig(&params.ChainConfig{
+					ChainID:     chainID,
+					LondonBlock: big.NewInt(0),
+				}),
+				ethtest.WithBlockContext(vm.BlockContext{
+					CanTransfer: core.CanTransfer,
+					Transfer:    core.Transfer,
+					BlockNumber: big.NewInt(1),
+					BaseFee:     big.NewInt(baseFee),
+				}),
+			)
+
+			tx := types.NewCustomTx(&depositTx{
+				from:     system,
+				to:       recipient,
+				mint:     big.NewInt(mint),
+				value:    new(big.Int),
+				gas:      gasLimit,
+				gasPrice: big.NewInt(tt.gasPrice),
+				paysFees: tt.paysFees,
+			})
+			msg, err := core.TransactionToMessage(tx, types.LatestSignerForChainID(chainID), big.NewInt(baseFee))
+			require.NoError(t, err, "core.TransactionToMessage()")
+
+			gp := new(core.GasPool).AddGas(tt.blockGas)
+			_, err = core.ApplyMessage(evm, msg, gp)
+			require.ErrorIs(t, err, tt.wantErr, "core.ApplyMessage()")
+
+			assert.Zero(t, sdb.GetBalance(system).Uint64(), "sender balance after failed mint")
+			assert.Zero(t, sdb.GetNonce(system), "sender nonce after failed mint")
+		})
+	}
+}
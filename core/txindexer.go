@@ -47,6 +47,7 @@ type txIndexer struct {
 	limit    uint64
 	db       ethdb.Database
 	progress chan chan TxIndexProgress
+	backfill chan txIndexBackfill // libevm
 	term     chan chan struct{}
 	closed   chan struct{}
 }
@@ -57,6 +58,7 @@ func newTxIndexer(limit uint64, chain *BlockChain) *txIndexer {
 		limit:    limit,
 		db:       chain.db,
 		progress: make(chan chan TxIndexProgress),
+		backfill: make(chan txIndexBackfill), // libevm
 		term:     make(chan chan struct{}),
 		closed:   make(chan struct{}),
 	}
@@ -83,20 +85,22 @@ func (indexer *txIndexer) run(tail *uint64, head uint64, stop chan struct{}, don
 	if head == 0 {
 		return
 	}
+	limit := indexer.limitAt(head) // libevm: accounts for backfill requests
+
 	// The tail flag is not existent, it means the node is just initialized
 	// and all blocks in the chain (part of them may from ancient store) are
 	// not indexed yet, index the chain according to the configured limit.
 	if tail == nil {
 		from := uint64(0)
-		if indexer.limit != 0 && head >= indexer.limit {
-			from = head - indexer.limit + 1
+		if limit != 0 && head >= limit {
+			from = head - limit + 1
 		}
 		rawdb.IndexTransactions(indexer.db, from, head+1, stop, true)
 		return
 	}
 	// The tail flag is existent (which means indexes in [tail, head] should be
 	// present), while the whole chain are requested for indexing.
-	if indexer.limit == 0 || head < indexer.limit {
+	if limit == 0 || head < limit {
 		if *tail > 0 {
 			// It can happen when chain is rewound to a historical point which
 			// is even lower than the indexes tail, recap the indexing target
@@ -111,12 +115,12 @@ func (indexer *txIndexer) run(tail *uint64, head uint64, stop chan struct{}, don
 	}
 	// The tail flag is existent, adjust the index range according to configured
 	// limit and the latest chain head.
-	if head-indexer.limit+1 < *tail {
+	if head-limit+1 < *tail {
 		// Reindex a part of missing indices and rewind index tail to HEAD-limit
-		rawdb.IndexTransactions(indexer.db, head-indexer.limit+1, *tail, stop, true)
+		rawdb.IndexTransactions(indexer.db, head-limit+1, *tail, stop, true)
 	} else {
 		// Unindex a part of stale indices and forward index tail to HEAD-limit
-		rawdb.UnindexTransactions(indexer.db, *tail, head-indexer.limit+1, stop, false)
+		rawdb.UnindexTransactions(indexer.db, *tail, head-limit+1, stop, false)
 	}
 }
 
@@ -131,6 +135,7 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 		done     chan struct{}                       // Non-nil if background routine is active.
 		lastHead uint64                              // The latest announced chain head (whose tx indexes are assumed created)
 		lastTail = rawdb.ReadTxIndexTail(indexer.db) // The oldest indexed block, nil means nothing indexed
+		rerun    bool                                // libevm: a backfill was requested while a routine was active
 
 		headCh = make(chan ChainHeadEvent)
 		sub    = chain.SubscribeChainHeadEvent(headCh)
@@ -158,6 +163,23 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 			stop = nil
 			done = nil
 			lastTail = rawdb.ReadTxIndexTail(indexer.db)
+			// libevm: pick up a backfill requested while the routine was active
+			if rerun && lastHead != 0 {
+				rerun = false
+				stop = make(chan struct{})
+				done = make(chan struct{})
+				go indexer.run(lastTail, lastHead, stop, done)
+			}
+		case req := <-indexer.backfill: // libevm
+			rawdb.WriteTxIndexBackfill(indexer.db, req.from)
+			close(req.ack)
+			if done != nil {
+				rerun = true
+			} else if lastHead != 0 {
+				stop = make(chan struct{})
+				done = make(chan struct{})
+				go indexer.run(rawdb.ReadTxIndexTail(indexer.db), lastHead, stop, done)
+			}
 		case ch := <-indexer.progress:
 			ch <- indexer.report(lastHead, lastTail)
 		case ch := <-indexer.term:
@@ -176,8 +198,9 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 
 // report returns the tx indexing progress.
 func (indexer *txIndexer) report(head uint64, tail *uint64) TxIndexProgress {
-	total := indexer.limit
-	if indexer.limit == 0 || total > head {
+	limit := indexer.limitAt(head) // libevm: accounts for backfill requests
+	total := limit
+	if limit == 0 || total > head {
 		total = head + 1 // genesis included
 	}
 	var indexed uint64
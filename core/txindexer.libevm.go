@@ -0,0 +1,125 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+
+	"github.com/ava-labs/libevm/core/rawdb"
+)
+
+// TxIndexStatus extends [TxIndexProgress] with the configuration and state
+// that determine the range of indexed blocks.
+type TxIndexStatus struct {
+	TxIndexProgress
+	Limit    uint64  // retention limit in blocks; 0 denotes the entire chain
+	Backfill *uint64 // lowest block requested via [BlockChain.BackfillTxIndex], if any
+	Tail     *uint64 // oldest indexed block; nil if nothing is indexed
+}
+
+// IndexProgress returns the transaction indexing progress along with the
+// retention configuration.
+func (bc *BlockChain) IndexProgress() (TxIndexStatus, error) {
+	progress, err := bc.TxIndexProgress()
+	if err != nil {
+		return TxIndexStatus{}, err
+	}
+	return TxIndexStatus{
+		TxIndexProgress: progress,
+		Limit:           bc.txIndexer.limit,
+		Backfill:        rawdb.ReadTxIndexBackfill(bc.db),
+		Tail:            rawdb.ReadTxIndexTail(bc.db),
+	}, nil
+}
+
+// BackfillTxIndex requests that transactions in all blocks from `from` to the
+// head be indexed, in addition to those retained by the configured limit. The
+// indexing occurs in the background and its progress can be monitored with
+// [BlockChain.IndexProgress].
+//
+// The request is persisted so backfilling resumes, from the lowest block
+// already indexed, if the chain is restarted before it completes. Blocks at or
+// above `from` remain indexed beyond the retention limit until the request is
+// removed with [rawdb.DeleteTxIndexBackfill].
+func (bc *BlockChain) BackfillTxIndex(from uint64) error {
+	if bc.txIndexer == nil {
+		return errors.New("tx indexer is not enabled")
+	}
+	return bc.txIndexer.requestBackfill(from)
+}
+
+type txIndexBackfill struct {
+	from uint64
+	ack  chan struct{}
+}
+
+func (indexer *txIndexer) requestBackfill(from uint64) error {
+	req := txIndexBackfill{
+		from: from,
+		ack:  make(chan struct{}),
+	}
+	select {
+	case indexer.backfill <- req:
+		<-req.ack
+		return nil
+	case <-indexer.closed:
+		return errors.New("indexer is closed")
+	}
+}
+
+// limitAt returns the number of blocks, up to and including `head`, whose
+// transactions are to be indexed. This is the configured limit unless a
+// backfill request extends it.
+func (indexer *txIndexer) limitAt(head uint64) uint64 {
+	if indexer.limit == 0 {
+		return 0
+	}
+	from := rawdb.ReadTxIndexBackfill(indexer.db)
+	if from == nil || *from > head || head-*from+1 <= indexer.limit {
+		return indexer.limit
+	}
+	return head - *from + 1
+}
+
+// SetTxLookupRangeErrors configures whether [BlockChain.GetTransactionLookup]
+// returns a [rawdb.TxIndexRangeError], instead of a nil lookup, for a
+// transaction missing from a complete index that doesn't cover the entire
+// chain; i.e. when the index tail is above genesis.
+//
+// It is disabled by default because such a transaction is indistinguishable
+// from one that doesn't exist (yet), so the error is also returned for pending
+// transactions, which breaks clients that poll for receipts and expect null
+// until inclusion. It SHOULD therefore only be enabled on nodes whose clients
+// query historical transactions.
+func (bc *BlockChain) SetTxLookupRangeErrors(enabled bool) {
+	bc.txRangeErrs.Store(enabled)
+}
+
+// txIndexRangeError returns a [rawdb.TxIndexRangeError] if the transaction
+// index does not cover the entire chain, otherwise nil. Once indexing is
+// complete it MUST only be used if enabled via
+// [BlockChain.SetTxLookupRangeErrors].
+func (bc *BlockChain) txIndexRangeError() error {
+	tail := rawdb.ReadTxIndexTail(bc.db)
+	if tail == nil || *tail == 0 {
+		return nil
+	}
+	return &rawdb.TxIndexRangeError{
+		Tail: *tail,
+		Head: bc.CurrentBlock().Number.Uint64(),
+	}
+}
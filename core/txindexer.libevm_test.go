@@ -0,0 +1,181 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestTxIndexBackfill(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	var (
+		addr  = crypto.PubkeyToAddress(key.PublicKey)
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine = ethash.NewFaker()
+	)
+	const (
+		numBlocks = 33
+		limit     = 8
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, numBlocks, func(i int, gen *BlockGen) {
+		tx, err := types.SignTx(
+			types.NewTransaction(uint64(i), common.Address{}, big.NewInt(1), params.TxGas, big.NewInt(10*params.InitialBaseFee), nil),
+			types.HomesteadSigner{}, key,
+		)
+		require.NoError(t, err, "types.SignTx()")
+		gen.AddTx(tx)
+	})
+	txAt := func(number uint64) common.Hash {
+		return blocks[number-1].Transactions()[0].Hash()
+	}
+
+	db := rawdb.NewMemoryDatabase()
+	newChain := func(t *testing.T) *BlockChain {
+		t.Helper()
+		l := uint64(limit)
+		bc, err := NewBlockChain(db, DefaultCacheConfigWithScheme(rawdb.HashScheme), gspec, nil, engine, vm.Config{}, nil, &l)
+		require.NoError(t, err, "NewBlockChain()")
+		// The indexer subscribes to head events asynchronously so may miss
+		// those from an immediate insertion. Any response to a progress
+		// request means that it is subscribed.
+		_, err = bc.TxIndexProgress()
+		require.NoError(t, err, "TxIndexProgress()")
+		return bc
+	}
+	waitForTail := func(t *testing.T, bc *BlockChain, want uint64) TxIndexStatus {
+		t.Helper()
+		var status TxIndexStatus
+		require.Eventuallyf(t, func() bool {
+			var err error
+			status, err = bc.IndexProgress()
+			return err == nil && status.Done() && status.Tail != nil && *status.Tail == want
+		}, 5*time.Second, 10*time.Millisecond, "%T.IndexProgress().Tail == %d", bc, want)
+		return status
+	}
+
+	bc := newChain(t)
+	_, err = bc.InsertChain(blocks[:numBlocks-1])
+	require.NoError(t, err, "InsertChain()")
+
+	const head = numBlocks - 1
+	status := waitForTail(t, bc, head-limit+1)
+	// Inserting a block as the head also indexes its transactions, so remove
+	// them to mimic a node that only ever indexed the latest blocks.
+	for n := uint64(1); n < head-limit+1; n++ {
+		rawdb.DeleteTxLookupEntry(db, txAt(n))
+	}
+	require.Equal(t, uint64(limit), status.Limit, "Limit")
+	require.Nil(t, status.Backfill, "Backfill")
+
+	lookup, _, err := bc.GetTransactionLookup(txAt(5))
+	require.NoError(t, err, "GetTransactionLookup() of unindexed transaction")
+	require.Nil(t, lookup, "GetTransactionLookup() of unindexed transaction")
+	lookup, _, err = bc.GetTransactionLookup(common.Hash{'u', 'n', 'k', 'n', 'o', 'w', 'n'})
+	require.NoError(t, err, "GetTransactionLookup() of unknown transaction")
+	require.Nil(t, lookup, "GetTransactionLookup() of unknown transaction")
+
+	t.Run("range_errors_enabled", func(t *testing.T) {
+		bc.SetTxLookupRangeErrors(true)
+		defer bc.SetTxLookupRangeErrors(false)
+
+		want := &rawdb.TxIndexRangeError{Tail: head - limit + 1, Head: head}
+		for _, h := range []common.Hash{txAt(5), {'u', 'n', 'k', 'n', 'o', 'w', 'n'}} {
+			_, _, err := bc.GetTransactionLookup(h)
+			require.Equalf(t, want, err, "GetTransactionLookup(%v) after indexing", h)
+		}
+		lookup, _, err := bc.GetTransactionLookup(txAt(head))
+		require.NoError(t, err, "GetTransactionLookup() of indexed transaction")
+		require.Equal(t, uint64(head), lookup.BlockIndex, "BlockIndex")
+	})
+
+	const from = 10
+	// Writing the request directly, without notifying the indexer, mimics the
+	// period during which the backfill is in progress.
+	rawdb.WriteTxIndexBackfill(db, from)
+	_, _, err = bc.GetTransactionLookup(txAt(5))
+	want := &rawdb.TxIndexRangeError{Tail: head - limit + 1, Head: head}
+	require.Equal(t, want, err, "GetTransactionLookup() of unindexed transaction during backfill")
+
+	require.NoError(t, bc.BackfillTxIndex(from), "BackfillTxIndex()")
+	status = waitForTail(t, bc, from)
+	require.Equal(t, uint64(from), *status.Backfill, "Backfill")
+
+	lookup, _, err = bc.GetTransactionLookup(txAt(from))
+	require.NoError(t, err, "GetTransactionLookup() of backfilled transaction")
+	require.Equal(t, uint64(from), lookup.BlockIndex, "BlockIndex")
+	lookup, _, err = bc.GetTransactionLookup(txAt(from - 1))
+	require.NoError(t, err, "GetTransactionLookup() below backfill")
+	require.Nil(t, lookup, "GetTransactionLookup() below backfill")
+	bc.Stop()
+
+	t.Run("persisted_across_restart", func(t *testing.T) {
+		bc := newChain(t)
+		defer bc.Stop()
+		_, err := bc.InsertChain(blocks[numBlocks-1:])
+		require.NoError(t, err, "InsertChain()")
+
+		require.Eventually(t, func() bool {
+			return rawdb.ReadTxLookupEntry(db, txAt(numBlocks)) != nil
+		}, 5*time.Second, 10*time.Millisecond, "new block indexed")
+		waitForTail(t, bc, from)
+	})
+}
+
+func TestTxIndexerLimitAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    uint64
+		backfill *uint64
+		head     uint64
+		want     uint64
+	}{
+		{"no_backfill", 8, nil, 100, 8},
+		{"entire_chain", 0, common.PointerTo[uint64](50), 100, 0},
+		{"backfill_extends", 8, common.PointerTo[uint64](50), 100, 51},
+		{"backfill_within_limit", 8, common.PointerTo[uint64](95), 100, 8},
+		{"backfill_above_head", 8, common.PointerTo[uint64](101), 100, 8},
+		{"backfill_genesis", 8, common.PointerTo[uint64](0), 100, 101},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := rawdb.NewMemoryDatabase()
+			if tt.backfill != nil {
+				rawdb.WriteTxIndexBackfill(db, *tt.backfill)
+			}
+			indexer := &txIndexer{limit: tt.limit, db: db}
+			require.Equal(t, tt.want, indexer.limitAt(tt.head))
+		})
+	}
+}
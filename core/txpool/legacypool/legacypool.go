@@ -627,6 +627,7 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 	opts := &txpool.ValidationOptionsWithState{
 		State: pool.currentState,
+		Head:  pool.currentHead.Load(), // libevm
 
 		FirstNonceGap: nil, // Pool allows arbitrary arrival order, don't invalidate nonce gaps
 		UsedAndLeftSlots: func(addr common.Address) (int, int) {
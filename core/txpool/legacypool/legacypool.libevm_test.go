@@ -0,0 +1,82 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/core/txpool"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm"
+)
+
+func TestValidationHook(t *testing.T) {
+	pool, key := setupPool()
+	defer pool.Close()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, addr, big.NewInt(1e18))
+
+	const rejectedPrice = 7
+	errRejected := errors.New("rejected by hook")
+	txpool.RegisterValidationHook(func(tx *types.Transaction, h *types.Header, sr libevm.StateReader) error {
+		assert.NotNil(t, h, "header passed to hook")
+		assert.NotZero(t, sr.GetBalance(addr).Sign(), "balance read via hook's state")
+		if tx.GasPrice().Cmp(big.NewInt(rejectedPrice)) == 0 {
+			return errRejected
+		}
+		return nil
+	})
+	t.Cleanup(txpool.TestOnlyClearValidationHook)
+
+	accepted := pricedTransaction(0, 100_000, big.NewInt(1), key)
+	require.NoError(t, pool.addLocal(accepted), "addLocal(accepted)")
+
+	tests := []struct {
+		name string
+		add  func(*types.Transaction) error
+		tx   *types.Transaction
+	}{
+		{
+			name: "local",
+			add:  pool.addLocal,
+			tx:   pricedTransaction(1, 100_000, big.NewInt(rejectedPrice), key),
+		},
+		{
+			name: "remote",
+			add:  pool.addRemoteSync,
+			tx:   pricedTransaction(1, 100_000, big.NewInt(rejectedPrice), key),
+		},
+		{
+			name: "replacement",
+			add:  pool.addLocal,
+			tx:   pricedTransaction(0, 100_000, big.NewInt(rejectedPrice), key),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.add(tt.tx), errRejected)
+			assert.Nil(t, pool.Get(tt.tx.Hash()), "rejected tx in pool")
+		})
+	}
+	assert.NotNil(t, pool.Get(accepted.Hash()), "accepted tx in pool after rejected replacement")
+}
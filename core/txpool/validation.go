@@ -73,8 +73,12 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 		return fmt.Errorf("%w: type %d rejected, pool not yet in Cancun", core.ErrTxTypeNotSupported, tx.Type())
 	}
 	// Check whether the init code size has been exceeded
-	if opts.Config.IsShanghai(head.Number, head.Time) && tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
-		return fmt.Errorf("%w: code size %v, limit %v", core.ErrMaxInitCodeSizeExceeded, len(tx.Data()), params.MaxInitCodeSize)
+	rules := opts.Config.Rules(head.Number, true, head.Time) // libevm
+	if limit := rules.Hooks().MaxInitCodeSize(); opts.Config.IsShanghai(head.Number, head.Time) && tx.To() == nil && len(tx.Data()) > limit {
+		return fmt.Errorf("%w: code size %v, limit %v", core.ErrMaxInitCodeSizeExceeded, len(tx.Data()), limit)
+	}
+	if limit := rules.Hooks().MaxTxDataBytes(); len(tx.Data()) > limit { // libevm
+		return fmt.Errorf("%w: data size %v, limit %v", ErrOversizedData, len(tx.Data()), limit)
 	}
 	// Transactions can't be negative. This may never happen using RLP decoded
 	// transactions but may occur for transactions created using the RPC.
@@ -97,9 +101,13 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 		return core.ErrTipAboveFeeCap
 	}
 	// Make sure the transaction is signed properly
-	if _, err := types.Sender(signer, tx); err != nil {
+	from, err := types.Sender(signer, tx) // libevm: was discarded
+	if err != nil {
 		return ErrInvalidSender
 	}
+	if err := core.VerifyPredicates(rules.Hooks(), head, from, tx.To(), tx.AccessList()); err != nil { // libevm
+		return err
+	}
 	// Ensure the transaction has more gas than the bare minimum needed to cover
 	// the transaction metadata
 	intrGas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, opts.Config.Rules(head.Number, true, head.Time))
@@ -191,6 +199,8 @@ type ValidationOptionsWithState struct {
 	// ExistingCost is a mandatory callback to retrieve an already pooled
 	// transaction's cost with the given nonce to check for overdrafts.
 	ExistingCost func(addr common.Address, nonce uint64) *big.Int
+
+	Head *types.Header // libevm: passed to the hook registered with [RegisterValidationHook]
 }
 
 // ValidateTransactionWithState is a helper method to check whether a transaction
@@ -245,5 +255,5 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 			return fmt.Errorf("%w: pooled %d txs", ErrAccountLimitExceeded, used)
 		}
 	}
-	return nil
+	return runValidationHook(tx, opts) // libevm
 }
@@ -0,0 +1,58 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/register"
+)
+
+// A ValidationHook performs chain-specific validation of a transaction being
+// added to a pool; e.g. enforcing allow-lists. It receives the pool's current
+// head and state, and a non-nil error results in the transaction being
+// rejected. See [RegisterValidationHook].
+type ValidationHook func(*types.Transaction, *types.Header, libevm.StateReader) error
+
+// RegisterValidationHook registers a hook that is called by
+// [ValidateTransactionWithState], after all other checks pass, and therefore
+// by both the legacy and blob pools for local and remote transactions alike,
+// as well as for replacements. Errors returned by the hook are propagated
+// unchanged. It is expected to be called in an `init()` function and MUST NOT
+// be called more than once.
+func RegisterValidationHook(h ValidationHook) {
+	validationHook.MustRegister(h)
+}
+
+// TestOnlyClearValidationHook clears the hook previously passed to
+// [RegisterValidationHook]. It panics if called from a non-testing call
+// stack.
+func TestOnlyClearValidationHook() {
+	validationHook.TestOnlyClear()
+}
+
+var validationHook register.AtMostOnce[ValidationHook]
+
+// runValidationHook calls the hook registered with [RegisterValidationHook],
+// if any. The header is nil if [ValidationOptionsWithState.Head] was not set
+// by the caller.
+func runValidationHook(tx *types.Transaction, opts *ValidationOptionsWithState) error {
+	if !validationHook.Registered() {
+		return nil
+	}
+	return validationHook.Get()(tx, opts.Head, opts.State)
+}
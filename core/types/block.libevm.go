@@ -17,10 +17,15 @@
 package types
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
+	"math/big"
+
+	"github.com/ava-labs/libevm/common"
 
 	"github.com/ava-labs/libevm/internal/libevm/pseudo"
+	"github.com/ava-labs/libevm/libevm"
 	"github.com/ava-labs/libevm/rlp"
 )
 
@@ -54,12 +59,90 @@ func (h *Header) UnmarshalJSON(b []byte) error {
 
 // EncodeRLP implements the [rlp.Encoder] interface.
 func (h *Header) EncodeRLP(w io.Writer) error {
-	return h.hooks().EncodeRLP(h, w)
+	hooks := h.hooks()
+	if t, ok := hooks.(HeaderRLPTransformer); ok {
+		return encodeTransformedHeaderRLP(h, hooks, t, w)
+	}
+	return hooks.EncodeRLP(h, w)
 }
 
 // DecodeRLP implements the [rlp.Decoder] interface.
 func (h *Header) DecodeRLP(s *rlp.Stream) error {
-	return h.hooks().DecodeRLP(h, s)
+	hooks := h.hooks()
+	if t, ok := hooks.(HeaderRLPTransformer); ok {
+		return decodeTransformedHeaderRLP(h, hooks, t, s)
+	}
+	return hooks.DecodeRLP(h, s)
+}
+
+var _ libevm.BlockHeader = (*Header)(nil)
+
+// BlockNumber returns a copy of the Number field.
+func (h *Header) BlockNumber() *big.Int { return new(big.Int).Set(h.Number) }
+
+// BlockTime returns the Time field.
+func (h *Header) BlockTime() uint64 { return h.Time }
+
+// BlockCoinbase returns the Coinbase field.
+func (h *Header) BlockCoinbase() common.Address { return h.Coinbase }
+
+// BlockBaseFee returns a copy of the BaseFee field, or nil if it is nil.
+func (h *Header) BlockBaseFee() *big.Int {
+	if h.BaseFee == nil {
+		return nil
+	}
+	return new(big.Int).Set(h.BaseFee)
+}
+
+// A HeaderRLPTransformer MAY be implemented by a type registered with
+// [RegisterExtras] for [Header] payloads. It allows items to be inserted into,
+// or stripped from, arbitrary positions in the RLP encoding of a [Header]
+// without having to re-implement the entire encoding in
+// [HeaderHooks.EncodeRLP] and [HeaderHooks.DecodeRLP].
+//
+// TransformEncodedHeaderRLP receives the parsed output of
+// [HeaderHooks.EncodeRLP] and MAY modify it in place; the modified tree is
+// then written as the final encoding. TransformHeaderRLPForDecoding receives
+// the parsed input, before it is passed to [HeaderHooks.DecodeRLP], and
+// SHOULD reverse the encoding transformation, typically populating the
+// payload carried by the [Header] with any stripped items.
+type HeaderRLPTransformer interface {
+	TransformEncodedHeaderRLP(*Header, *rlp.ItemNode) error
+	TransformHeaderRLPForDecoding(*Header, *rlp.ItemNode) error
+}
+
+func encodeTransformedHeaderRLP(h *Header, hooks HeaderHooks, t HeaderRLPTransformer, w io.Writer) error {
+	var buf bytes.Buffer
+	if err := hooks.EncodeRLP(h, &buf); err != nil {
+		return err
+	}
+	tree, err := rlp.ParseTree(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	if err := t.TransformEncodedHeaderRLP(h, tree); err != nil {
+		return err
+	}
+	return tree.EncodeRLP(w)
+}
+
+func decodeTransformedHeaderRLP(h *Header, hooks HeaderHooks, t HeaderRLPTransformer, s *rlp.Stream) error {
+	raw, err := s.Raw()
+	if err != nil {
+		return err
+	}
+	tree, err := rlp.ParseTree(raw)
+	if err != nil {
+		return err
+	}
+	if err := t.TransformHeaderRLPForDecoding(h, tree); err != nil {
+		return err
+	}
+	b, err := rlp.EncodeTree(tree)
+	if err != nil {
+		return err
+	}
+	return hooks.DecodeRLP(h, rlp.NewStream(bytes.NewReader(b), uint64(len(b))))
 }
 
 // NOOPHeaderHooks implements [HeaderHooks] such that they are equivalent to
@@ -122,6 +205,11 @@ func (b *extblock) DecodeRLP(s *rlp.Stream) error {
 
 // BlockBodyHooks are required for all types registered with [RegisterExtras]
 // for [Block] and [Body] payloads.
+//
+// [Block.Hash] is always equal to [Header.Hash], so block payloads are not
+// committed to by the hash. Chains that require such a commitment SHOULD
+// include it in the header (e.g. a hash of the payload) and encode it via
+// [HeaderHooks].
 type BlockBodyHooks interface {
 	BlockRLPFieldsForEncoding(*BlockRLPProxy) *rlp.Fields
 	BlockRLPFieldPointersForDecoding(*BlockRLPProxy) *rlp.Fields
@@ -130,6 +218,27 @@ type BlockBodyHooks interface {
 	PostRPCMarshal(b *Block, marshalled map[string]any)
 }
 
+// A BlockBodyVerifier MAY be implemented by a type registered with
+// [RegisterExtras] for [Block] and [Body] payloads. Unlike the transactions,
+// uncles, and withdrawals, a payload carried by a body received from a peer is
+// not committed to by any of the header's roots, so VerifyBodyExtra SHOULD
+// check the payload against whatever commitment the header carries instead.
+// It is called, via [Body.VerifyExtra], by the downloader for every body that
+// it requests from peers; bodies of headers for which [Header.EmptyBody] is
+// true are never requested.
+type BlockBodyVerifier interface {
+	VerifyBodyExtra(*Body, *Header) error
+}
+
+// VerifyExtra returns the result of [BlockBodyVerifier.VerifyBodyExtra] if the
+// registered [Block] and [Body] payload implements it, otherwise nil.
+func (b *Body) VerifyExtra(h *Header) error {
+	if v, ok := b.hooks().(BlockBodyVerifier); ok {
+		return v.VerifyBodyExtra(b, h)
+	}
+	return nil
+}
+
 // NOOPBlockBodyHooks implements [BlockBodyHooks] such that they are equivalent
 // to no type having been registered.
 type NOOPBlockBodyHooks struct{}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
@@ -272,3 +273,70 @@ func TestBlockWithX(t *testing.T) {
 		})
 	}
 }
+
+type treeTransformingHeaderHooks struct {
+	NOOPHeaderHooks
+	Inserted []byte
+}
+
+const headerTreeInsertionIndex = 1
+
+func (hh *treeTransformingHeaderHooks) TransformEncodedHeaderRLP(_ *Header, tree *rlp.ItemNode) error {
+	tree.Children = slices.Insert(tree.Children, headerTreeInsertionIndex, &rlp.ItemNode{
+		Kind:    rlp.String,
+		Content: hh.Inserted,
+	})
+	return nil
+}
+
+func (hh *treeTransformingHeaderHooks) TransformHeaderRLPForDecoding(_ *Header, tree *rlp.ItemNode) error {
+	if len(tree.Children) <= headerTreeInsertionIndex {
+		return errors.New("too few items")
+	}
+	hh.Inserted = slices.Clone(tree.Children[headerTreeInsertionIndex].Content)
+	tree.Children = slices.Delete(tree.Children, headerTreeInsertionIndex, headerTreeInsertionIndex+1)
+	return nil
+}
+
+func TestHeaderRLPTransformer(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	defer TestOnlyClearRegisteredExtras()
+
+	extras := RegisterExtras[
+		treeTransformingHeaderHooks, *treeTransformingHeaderHooks,
+		NOOPBlockBodyHooks, *NOOPBlockBodyHooks,
+		struct{},
+	]()
+	rng := ethtest.NewPseudoRand(24680)
+
+	hdr := &Header{
+		ParentHash: rng.Hash(),
+		Number:     rng.BigUint64(),
+		Extra:      []byte("extra"),
+	}
+	inserted := rng.Bytes(40)
+	extras.Header.Set(hdr, &treeTransformingHeaderHooks{Inserted: inserted})
+
+	got, err := rlp.EncodeToBytes(hdr)
+	require.NoErrorf(t, err, "rlp.EncodeToBytes(%T)", hdr)
+
+	t.Run("encoding", func(t *testing.T) {
+		type withoutMethods Header
+		want, err := rlp.EncodeToBytes((*withoutMethods)(hdr))
+		require.NoError(t, err, "rlp.EncodeToBytes(<geth header>)")
+		wantTree, err := rlp.ParseTree(want)
+		require.NoError(t, err, "rlp.ParseTree(<geth header>)")
+
+		gotTree, err := rlp.ParseTree(got)
+		require.NoError(t, err, "rlp.ParseTree(<transformed header>)")
+		require.Len(t, gotTree.Children, len(wantTree.Children)+1, "number of items in transformed header")
+		assert.Equal(t, inserted, gotTree.Children[headerTreeInsertionIndex].Content, "inserted item")
+	})
+
+	t.Run("decoding", func(t *testing.T) {
+		decoded := new(Header)
+		require.NoErrorf(t, rlp.DecodeBytes(got, decoded), "rlp.DecodeBytes(..., %T)", decoded)
+		assert.Equal(t, hdr.Hash(), decoded.Hash(), "round-trip hash")
+		assert.Equal(t, inserted, extras.Header.Get(decoded).Inserted, "payload populated from stripped item")
+	})
+}
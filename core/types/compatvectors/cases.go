@@ -0,0 +1,266 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package compatvectors
+
+import (
+	"math/big"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// File types, as recorded in [File.Type].
+const (
+	HeaderType      = "header"
+	BodyType        = "body"
+	AccountType     = "account"
+	SlimAccountType = "slimAccount"
+)
+
+const noExtras = "none"
+
+func ptrTo[T any](v T) *T { return &v }
+
+func baseHeader() *types.Header {
+	return &types.Header{
+		ParentHash:  hashOf("parent"),
+		UncleHash:   types.EmptyUncleHash,
+		Coinbase:    addressOf("coinbase"),
+		Root:        hashOf("root"),
+		TxHash:      types.EmptyTxsHash,
+		ReceiptHash: types.EmptyReceiptsHash,
+		Difficulty:  big.NewInt(131_072),
+		Number:      big.NewInt(1),
+		GasLimit:    8_000_000,
+		GasUsed:     21_000,
+		Time:        1_700_000_000,
+		Extra:       []byte("libevm"),
+		MixDigest:   hashOf("mix"),
+		Nonce:       types.EncodeNonce(42),
+	}
+}
+
+type headerCase struct {
+	name string
+	hdr  *types.Header
+}
+
+func headerCases() []headerCase {
+	frontier := baseHeader()
+
+	london := baseHeader()
+	london.BaseFee = big.NewInt(25e9)
+
+	shanghai := types.CopyHeader(london)
+	shanghai.WithdrawalsHash = ptrTo(types.EmptyWithdrawalsHash)
+
+	cancun := types.CopyHeader(shanghai)
+	cancun.BlobGasUsed = ptrTo[uint64](131_072)
+	cancun.ExcessBlobGas = ptrTo[uint64](0)
+	cancun.ParentBeaconRoot = ptrTo(hashOf("beacon"))
+
+	return []headerCase{
+		{"frontier", frontier},
+		{"london", london},
+		{"shanghai", shanghai},
+		{"cancun", cancun},
+	}
+}
+
+func headerVectors(extras string, cases []headerCase, value func(*types.Header) any) (*File, error) {
+	f := &File{Type: HeaderType, Extras: extras}
+	for _, c := range cases {
+		buf, err := rlp.EncodeToBytes(c.hdr)
+		if err != nil {
+			return nil, err
+		}
+		f.Vectors = append(f.Vectors, newVector(c.name, value(c.hdr), buf))
+	}
+	return f, nil
+}
+
+func headers() (*File, error) {
+	return headerVectors(noExtras, headerCases(), func(h *types.Header) any { return h })
+}
+
+var chainID = big.NewInt(43114)
+
+func transactions() ([]*types.Transaction, error) {
+	key, err := crypto.ToECDSA(hashOf("key").Bytes())
+	if err != nil {
+		return nil, err
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	to := addressOf("to")
+
+	var txs []*types.Transaction
+	for _, data := range []types.TxData{
+		&types.LegacyTx{
+			Nonce:    0,
+			GasPrice: big.NewInt(25e9),
+			Gas:      21_000,
+			To:       &to,
+			Value:    big.NewInt(1),
+		},
+		&types.AccessListTx{
+			ChainID:  chainID,
+			Nonce:    1,
+			GasPrice: big.NewInt(25e9),
+			Gas:      50_000,
+			To:       &to,
+			Data:     []byte{1, 2, 3},
+			AccessList: types.AccessList{{
+				Address:     to,
+				StorageKeys: []common.Hash{hashOf("slot")},
+			}},
+		},
+		&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     2,
+			GasTipCap: big.NewInt(1e9),
+			GasFeeCap: big.NewInt(50e9),
+			Gas:       100_000,
+			Data:      []byte("create"),
+		},
+	} {
+		tx, err := types.SignNewTx(key, signer, data)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+type bodyCase struct {
+	name string
+	body *types.Body
+}
+
+func bodyCases() ([]bodyCase, error) {
+	txs, err := transactions()
+	if err != nil {
+		return nil, err
+	}
+	return []bodyCase{
+		{"empty", &types.Body{}},
+		{"transactions", &types.Body{Transactions: txs}},
+		{"uncles", &types.Body{Uncles: []*types.Header{baseHeader()}}},
+		{"empty_withdrawals", &types.Body{Withdrawals: []*types.Withdrawal{}}},
+		{"withdrawals", &types.Body{
+			Transactions: txs,
+			Withdrawals: []*types.Withdrawal{{
+				Index:     1,
+				Validator: 2,
+				Address:   addressOf("withdrawal"),
+				Amount:    3,
+			}},
+		}},
+	}, nil
+}
+
+func bodyVectors(extras string, cases []bodyCase, value func(*types.Body) any) (*File, error) {
+	f := &File{Type: BodyType, Extras: extras}
+	for _, c := range cases {
+		buf, err := rlp.EncodeToBytes(c.body)
+		if err != nil {
+			return nil, err
+		}
+		f.Vectors = append(f.Vectors, newVector(c.name, value(c.body), buf))
+	}
+	return f, nil
+}
+
+func bodies() (*File, error) {
+	cases, err := bodyCases()
+	if err != nil {
+		return nil, err
+	}
+	return bodyVectors(noExtras, cases, func(b *types.Body) any { return b })
+}
+
+type accountCase struct {
+	name string
+	acc  *types.StateAccount
+}
+
+func accountCases() []accountCase {
+	return []accountCase{
+		{"empty", types.NewEmptyStateAccount()},
+		{"contract", &types.StateAccount{
+			Nonce:    1,
+			Balance:  uint256.NewInt(1e18),
+			Root:     hashOf("storage"),
+			CodeHash: crypto.Keccak256([]byte("code")),
+		}},
+	}
+}
+
+// accountJSON is the [Vector.Value] of accounts.
+type accountJSON struct {
+	Nonce    hexutil.Uint64 `json:"nonce"`
+	Balance  *hexutil.U256  `json:"balance"`
+	Root     common.Hash    `json:"root"`
+	CodeHash hexutil.Bytes  `json:"codeHash"`
+	Extra    any            `json:"extra,omitempty"`
+}
+
+func newAccountJSON(a *types.StateAccount, extra any) *accountJSON {
+	return &accountJSON{
+		Nonce:    hexutil.Uint64(a.Nonce),
+		Balance:  (*hexutil.U256)(a.Balance),
+		Root:     a.Root,
+		CodeHash: a.CodeHash,
+		Extra:    extra,
+	}
+}
+
+func accountVectors(typ, extras string, cases []accountCase, value func(*types.StateAccount) any) (*File, error) {
+	f := &File{Type: typ, Extras: extras}
+	for _, c := range cases {
+		var (
+			buf []byte
+			err error
+		)
+		switch typ {
+		case AccountType:
+			buf, err = rlp.EncodeToBytes(c.acc)
+		case SlimAccountType:
+			buf = types.SlimAccountRLP(*c.acc)
+		}
+		if err != nil {
+			return nil, err
+		}
+		f.Vectors = append(f.Vectors, newVector(c.name, value(c.acc), buf))
+	}
+	return f, nil
+}
+
+func withoutExtras(a *types.StateAccount) any { return newAccountJSON(a, nil) }
+
+func accounts() (*File, error) {
+	return accountVectors(AccountType, noExtras, accountCases(), withoutExtras)
+}
+
+func slimAccounts() (*File, error) {
+	return accountVectors(SlimAccountType, noExtras, accountCases(), withoutExtras)
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package compatvectors generates golden test vectors of canonical RLP
+// encodings, and their hashes, for [types.Header], [types.Body], and
+// [types.StateAccount] values, both with and without representative extras
+// registered via [types.RegisterExtras]. The vectors are committed as JSON in
+// the testdata directory, allowing implementations in other languages to
+// assert byte-level equality with libevm.
+package compatvectors
+
+//go:generate go run ./gen -out testdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/crypto"
+)
+
+// A Vector is a single test vector.
+type Vector struct {
+	Name string `json:"name"`
+	// Value is a JSON description of the encoded value. It is informational
+	// only and MUST NOT be relied upon for byte-level compatibility.
+	Value any           `json:"value"`
+	RLP   hexutil.Bytes `json:"rlp"`
+	// Hash is the Keccak-256 hash of RLP. For headers this is the block hash.
+	Hash common.Hash `json:"hash"`
+}
+
+// A File is the contents of a single JSON file of vectors.
+type File struct {
+	Type    string   `json:"type"`
+	Extras  string   `json:"extras"`
+	Vectors []Vector `json:"vectors"`
+}
+
+// newVector returns a [Vector] with its Hash computed from `rlp`.
+func newVector(name string, value any, rlp []byte) Vector {
+	return Vector{
+		Name:  name,
+		Value: value,
+		RLP:   rlp,
+		Hash:  crypto.Keccak256Hash(rlp),
+	}
+}
+
+// Generate returns all vectors, keyed by file name. It MUST NOT be called
+// while any extras are registered with [types.RegisterExtras], as the vectors
+// without extras would otherwise be incorrect; those with extras are generated
+// with temporary registration.
+func Generate() (map[string]*File, error) {
+	files := make(map[string]*File)
+	for _, g := range []struct {
+		name string
+		fn   func() (*File, error)
+	}{
+		{"headers.json", headers},
+		{"bodies.json", bodies},
+		{"accounts.json", accounts},
+		{"slim_accounts.json", slimAccounts},
+		{"headers_extras.json", headersWithExtras},
+		{"bodies_extras.json", bodiesWithExtras},
+		{"accounts_extras.json", accountsWithExtras},
+		{"slim_accounts_extras.json", slimAccountsWithExtras},
+	} {
+		f, err := g.fn()
+		if err != nil {
+			return nil, fmt.Errorf("generating %q: %v", g.name, err)
+		}
+		files[g.name] = f
+	}
+	return files, nil
+}
+
+// Marshal returns the canonical JSON encoding of the [File], as written to the
+// testdata directory.
+func (f *File) Marshal() ([]byte, error) {
+	buf, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, '\n'), nil
+}
+
+// WriteDir writes all vectors returned by [Generate] to the directory.
+func WriteDir(dir string) error {
+	files, err := Generate()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for n := range files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		buf, err := files[n].Marshal()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, n), buf, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hashOf returns a deterministic hash for use as an arbitrary field value.
+func hashOf(s string) common.Hash {
+	return crypto.Keccak256Hash([]byte(s))
+}
+
+// addressOf returns a deterministic address for use as an arbitrary field
+// value.
+func addressOf(s string) common.Address {
+	return common.BytesToAddress(hashOf(s).Bytes())
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package compatvectors
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+func TestGoldenFiles(t *testing.T) {
+	files, err := Generate()
+	require.NoError(t, err, "Generate()")
+
+	onDisk, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	require.NoError(t, err)
+	require.Lenf(t, onDisk, len(files), "number of testdata files; run `go generate` to update")
+
+	for name, f := range files {
+		t.Run(name, func(t *testing.T) {
+			want, err := os.ReadFile(filepath.Join("testdata", name))
+			require.NoError(t, err)
+			got, err := f.Marshal()
+			require.NoError(t, err, "%T.Marshal()", f)
+			assert.Equal(t, string(want), string(got), "run `go generate` to update")
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	require.NoError(t, err)
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			buf, err := os.ReadFile(path)
+			require.NoError(t, err)
+			var f File
+			require.NoError(t, json.Unmarshal(buf, &f))
+			require.NotEmpty(t, f.Vectors)
+
+			check := func(ExtraPayloads) error {
+				for _, v := range f.Vectors {
+					t.Run(v.Name, func(t *testing.T) {
+						roundTrip(t, f.Type, v)
+					})
+				}
+				return nil
+			}
+			if f.Extras == noExtras {
+				require.NoError(t, check(ExtraPayloads{}))
+			} else {
+				require.NoError(t, WithExtras(check))
+			}
+		})
+	}
+}
+
+func roundTrip(t *testing.T, typ string, v Vector) {
+	t.Helper()
+
+	var got []byte
+	switch typ {
+	case HeaderType:
+		h := new(types.Header)
+		require.NoError(t, rlp.DecodeBytes(v.RLP, h), "rlp.DecodeBytes(..., %T)", h)
+		assert.Equal(t, v.Hash, h.Hash(), "%T.Hash()", h)
+		got = mustEncode(t, h)
+	case BodyType:
+		b := new(types.Body)
+		require.NoError(t, rlp.DecodeBytes(v.RLP, b), "rlp.DecodeBytes(..., %T)", b)
+		got = mustEncode(t, b)
+	case AccountType:
+		a := new(types.StateAccount)
+		require.NoError(t, rlp.DecodeBytes(v.RLP, a), "rlp.DecodeBytes(..., %T)", a)
+		got = mustEncode(t, a)
+	case SlimAccountType:
+		a, err := types.FullAccount(v.RLP)
+		require.NoError(t, err, "types.FullAccount()")
+		got = types.SlimAccountRLP(*a)
+	default:
+		t.Fatalf("unknown type %q", typ)
+	}
+	assert.Equal(t, []byte(v.RLP), got, "re-encoded RLP")
+}
+
+func mustEncode(t *testing.T, v any) []byte {
+	t.Helper()
+	buf, err := rlp.EncodeToBytes(v)
+	require.NoErrorf(t, err, "rlp.EncodeToBytes(%T)", v)
+	return buf
+}
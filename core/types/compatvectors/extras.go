@@ -0,0 +1,223 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package compatvectors
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// Extras describes the representative extras, as recorded in [File.Extras].
+const Extras = "header: extDataHash and blockGasCost appended; body: version and extData replace withdrawals; account: bool"
+
+// HeaderExtra is a representative [types.Header] payload that appends its
+// fields to the end of the standard RLP encoding.
+type HeaderExtra struct {
+	types.NOOPHeaderHooks
+	ExtDataHash  common.Hash `json:"extDataHash"`
+	BlockGasCost *big.Int    `json:"blockGasCost"`
+}
+
+var _ types.HeaderRLPTransformer = (*HeaderExtra)(nil)
+
+// TransformEncodedHeaderRLP appends the extra fields to the header's list.
+func (e *HeaderExtra) TransformEncodedHeaderRLP(_ *types.Header, tree *rlp.ItemNode) error {
+	for _, v := range []any{e.ExtDataHash, e.BlockGasCost} {
+		buf, err := rlp.EncodeToBytes(v)
+		if err != nil {
+			return err
+		}
+		item, err := rlp.ParseTree(buf)
+		if err != nil {
+			return err
+		}
+		tree.Children = append(tree.Children, item)
+	}
+	return nil
+}
+
+var errMissingHeaderExtra = errors.New("header RLP missing extra fields")
+
+// TransformHeaderRLPForDecoding strips the extra fields from the end of the
+// header's list, decoding them into the receiver.
+func (e *HeaderExtra) TransformHeaderRLPForDecoding(_ *types.Header, tree *rlp.ItemNode) error {
+	n := len(tree.Children)
+	if n < 2 {
+		return errMissingHeaderExtra
+	}
+	extra := tree.Children[n-2:]
+	tree.Children = tree.Children[:n-2]
+
+	for i, dst := range []any{&e.ExtDataHash, &e.BlockGasCost} {
+		buf, err := rlp.EncodeTree(extra[i])
+		if err != nil {
+			return err
+		}
+		if err := rlp.DecodeBytes(buf, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BodyExtra is a representative [types.Body] payload that replaces the
+// optional withdrawals with required fields of its own.
+type BodyExtra struct {
+	types.NOOPBlockBodyHooks
+	Version uint32  `json:"version"`
+	ExtData *[]byte `json:"extData"`
+}
+
+// Copy returns a deep copy of the receiver.
+func (e *BodyExtra) Copy() *BodyExtra {
+	cp := &BodyExtra{Version: e.Version}
+	if e.ExtData != nil {
+		d := append([]byte{}, *e.ExtData...)
+		cp.ExtData = &d
+	}
+	return cp
+}
+
+// BodyRLPFieldsForEncoding returns the transactions, uncles, and extra fields.
+func (e *BodyExtra) BodyRLPFieldsForEncoding(b *types.Body) *rlp.Fields {
+	return &rlp.Fields{
+		Required: []any{b.Transactions, b.Uncles, e.Version, e.ExtData},
+	}
+}
+
+// BodyRLPFieldPointersForDecoding is the inverse of
+// [BodyExtra.BodyRLPFieldsForEncoding].
+func (e *BodyExtra) BodyRLPFieldPointersForDecoding(b *types.Body) *rlp.Fields {
+	return &rlp.Fields{
+		Required: []any{&b.Transactions, &b.Uncles, &e.Version, rlp.Nillable(&e.ExtData)},
+	}
+}
+
+// ExtraPayloads are the payload accessors for the representative extras.
+type ExtraPayloads = types.ExtraPayloads[*HeaderExtra, *BodyExtra, bool]
+
+// WithExtras calls `fn` with the representative extras temporarily
+// registered.
+func WithExtras(fn func(ExtraPayloads) error) error {
+	return libevm.WithTemporaryExtrasLock(func(lock libevm.ExtrasLock) error {
+		return types.WithTempRegisteredExtras[HeaderExtra, BodyExtra, bool](lock, fn)
+	})
+}
+
+// extraJSON is the [Vector.Value] of types carrying extras.
+type extraJSON[T any] struct {
+	Value T   `json:"value"`
+	Extra any `json:"extra"`
+}
+
+func headersWithExtras() (f *File, _ error) {
+	err := WithExtras(func(extras ExtraPayloads) error {
+		var cases []headerCase
+		for _, c := range headerCases() {
+			for _, e := range []struct {
+				suffix string
+				extra  *HeaderExtra
+			}{
+				{"zero_extras", &HeaderExtra{}},
+				{"extras", &HeaderExtra{
+					ExtDataHash:  hashOf("extData"),
+					BlockGasCost: big.NewInt(100_000),
+				}},
+			} {
+				h := types.CopyHeader(c.hdr)
+				extras.Header.Set(h, e.extra)
+				cases = append(cases, headerCase{fmt.Sprintf("%s_%s", c.name, e.suffix), h})
+			}
+		}
+
+		var err error
+		f, err = headerVectors(Extras, cases, func(h *types.Header) any {
+			return &extraJSON[*types.Header]{h, extras.Header.Get(h)}
+		})
+		return err
+	})
+	return f, err
+}
+
+func bodiesWithExtras() (f *File, _ error) {
+	err := WithExtras(func(extras ExtraPayloads) error {
+		base, err := bodyCases()
+		if err != nil {
+			return err
+		}
+		var cases []bodyCase
+		for _, c := range base {
+			if c.body.Withdrawals != nil {
+				continue // dropped by [BodyExtra]
+			}
+			for _, e := range []struct {
+				suffix string
+				extra  *BodyExtra
+			}{
+				{"zero_extras", &BodyExtra{}},
+				{"extras", &BodyExtra{
+					Version: 1,
+					ExtData: &[]byte{0xde, 0xad, 0xbe, 0xef},
+				}},
+			} {
+				b := *c.body
+				extras.Body.Set(&b, e.extra)
+				cases = append(cases, bodyCase{fmt.Sprintf("%s_%s", c.name, e.suffix), &b})
+			}
+		}
+
+		f, err = bodyVectors(Extras, cases, func(b *types.Body) any {
+			return &extraJSON[*types.Body]{b, extras.Body.Get(b)}
+		})
+		return err
+	})
+	return f, err
+}
+
+func accountsWithExtrasOfType(typ string) (f *File, _ error) {
+	err := WithExtras(func(extras ExtraPayloads) error {
+		var cases []accountCase
+		for _, c := range accountCases() {
+			for _, flag := range []bool{false, true} {
+				a := c.acc.Copy()
+				extras.StateAccount.Set(a, flag)
+				cases = append(cases, accountCase{fmt.Sprintf("%s_%t", c.name, flag), a})
+			}
+		}
+
+		var err error
+		f, err = accountVectors(typ, Extras, cases, func(a *types.StateAccount) any {
+			return newAccountJSON(a, extras.StateAccount.Get(a))
+		})
+		return err
+	})
+	return f, err
+}
+
+func accountsWithExtras() (*File, error) {
+	return accountsWithExtrasOfType(AccountType)
+}
+
+func slimAccountsWithExtras() (*File, error) {
+	return accountsWithExtrasOfType(SlimAccountType)
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// The gen command writes the compatvectors golden files.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/libevm/core/types/compatvectors"
+)
+
+func main() {
+	out := flag.String("out", "testdata", "output directory")
+	flag.Parse()
+
+	if err := compatvectors.WriteDir(*out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common/hexutil"
+)
+
+// A FieldDiff is a single difference between two values, as reported by
+// [DiffHeaders] and [DiffBlocks].
+type FieldDiff struct {
+	// Path identifies the differing value relative to the compared types; e.g.
+	// `Header.GasUsed`, `Body.Transactions[2].Nonce`, or `Header.Extras.Foo`
+	// for a field of a payload registered with [RegisterExtras]. Paths ending
+	// in `(hash)` or `(root)` are recomputed from the respective component.
+	Path string
+	A, B any
+}
+
+// String returns a human-readable representation of the difference.
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %s != %s", d.Path, formatDiffValue(d.A), formatDiffValue(d.B))
+}
+
+// FieldDiffs are the field-level differences between two values, ordered by
+// position, with hashes preceding the fields from which they are computed.
+type FieldDiffs []FieldDiff
+
+// String returns one line per [FieldDiff], suitable for logging.
+func (ds FieldDiffs) String() string {
+	lines := make([]string, len(ds))
+	for i, d := range ds {
+		lines[i] = d.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DiffHeaders returns the field-level differences between the headers,
+// including those of any extra payloads registered with [RegisterExtras], and
+// the recomputed header hashes. It returns an empty slice i.f.f. the headers
+// are equivalent. Nil headers are only equivalent to each other; if exactly one
+// is nil then the sole difference is reported with the path `Header`.
+func DiffHeaders(a, b *Header) FieldDiffs {
+	var d differ
+	d.headers("", a, b)
+	return d.diffs
+}
+
+// DiffBlocks returns the field-level differences between the blocks, including
+// those of their headers (as per [DiffHeaders]), bodies, and extra payloads.
+// Hashes are recomputed at each layer to pinpoint the diverging component; the
+// transaction and withdrawal roots use `hasher`, which is typically a
+// `trie.NewStackTrie(nil)`. Nil blocks are treated as nil headers are by
+// [DiffHeaders], with the path `Block`.
+func DiffBlocks(a, b *Block, hasher TrieHasher) FieldDiffs {
+	var d differ
+	if d.eitherNil("Block", a, b) {
+		return d.diffs
+	}
+	d.headers("Header.", a.Header(), b.Header())
+
+	txA, txB := a.Transactions(), b.Transactions()
+	d.compare("Body.Transactions(root)", DeriveSha(txA, hasher), DeriveSha(txB, hasher))
+	d.compare("Body.Transactions.len", len(txA), len(txB))
+	for i := range min(len(txA), len(txB)) {
+		d.transactions(fmt.Sprintf("Body.Transactions[%d].", i), txA[i], txB[i])
+	}
+
+	unA, unB := a.Uncles(), b.Uncles()
+	d.compare("Body.Uncles(hash)", CalcUncleHash(unA), CalcUncleHash(unB))
+	d.compare("Body.Uncles.len", len(unA), len(unB))
+	for i := range min(len(unA), len(unB)) {
+		d.headers(fmt.Sprintf("Body.Uncles[%d].", i), unA[i], unB[i])
+	}
+
+	wA, wB := a.Withdrawals(), b.Withdrawals()
+	if wA != nil || wB != nil {
+		d.compare("Body.Withdrawals(root)", DeriveSha(wA, hasher), DeriveSha(wB, hasher))
+	}
+	d.compare("Body.Withdrawals.len", len(wA), len(wB))
+	for i := range min(len(wA), len(wB)) {
+		d.fields(fmt.Sprintf("Body.Withdrawals[%d].", i), reflect.ValueOf(*wA[i]), reflect.ValueOf(*wB[i]))
+	}
+
+	d.extras("Body.Extras", a.hooks(), b.hooks())
+	return d.diffs
+}
+
+type differ struct {
+	diffs FieldDiffs
+}
+
+func (d *differ) headers(prefix string, a, b *Header) {
+	path := strings.TrimSuffix(prefix, ".")
+	if path == "" {
+		path = "Header"
+	}
+	if d.eitherNil(path, a, b) {
+		return
+	}
+	d.compare(prefix+"(hash)", a.Hash(), b.Hash())
+	d.fields(prefix, reflect.ValueOf(*a), reflect.ValueOf(*b))
+	d.extras(prefix+"Extras", a.hooks(), b.hooks())
+}
+
+func (d *differ) transactions(prefix string, a, b *Transaction) {
+	d.compare(prefix+"(hash)", a.Hash(), b.Hash())
+	d.compare(prefix+"Type", a.Type(), b.Type())
+	d.compare(prefix+"ChainId", a.ChainId(), b.ChainId())
+	d.compare(prefix+"Nonce", a.Nonce(), b.Nonce())
+	d.compare(prefix+"Gas", a.Gas(), b.Gas())
+	d.compare(prefix+"GasPrice", a.GasPrice(), b.GasPrice())
+	d.compare(prefix+"GasTipCap", a.GasTipCap(), b.GasTipCap())
+	d.compare(prefix+"GasFeeCap", a.GasFeeCap(), b.GasFeeCap())
+	d.compare(prefix+"To", a.To(), b.To())
+	d.compare(prefix+"Value", a.Value(), b.Value())
+	d.compare(prefix+"Data", a.Data(), b.Data())
+	d.compare(prefix+"AccessList", a.AccessList(), b.AccessList())
+	d.compare(prefix+"BlobHashes", a.BlobHashes(), b.BlobHashes())
+
+	va, ra, sa := a.RawSignatureValues()
+	vb, rb, sb := b.RawSignatureValues()
+	d.compare(prefix+"V", va, vb)
+	d.compare(prefix+"R", ra, rb)
+	d.compare(prefix+"S", sa, sb)
+}
+
+// eitherNil returns true if either of the pointers is nil, in which case no
+// further comparison is possible, recording a difference if only one is nil.
+func (d *differ) eitherNil(path string, a, b any) bool {
+	aNil, bNil := reflect.ValueOf(a).IsNil(), reflect.ValueOf(b).IsNil()
+	if aNil != bNil {
+		d.diffs = append(d.diffs, FieldDiff{path, a, b})
+	}
+	return aNil || bNil
+}
+
+// fields compares all exported fields of the structs.
+func (d *differ) fields(prefix string, a, b reflect.Value) {
+	for i := range a.NumField() {
+		f := a.Type().Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		d.compare(prefix+f.Name, a.Field(i).Interface(), b.Field(i).Interface())
+	}
+}
+
+// extras compares registered payloads, field by field if they are (pointers
+// to) structs with exported fields, otherwise as a whole.
+func (d *differ) extras(path string, a, b any) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !va.IsValid() || !vb.IsValid() || va.Type() != vb.Type() {
+		d.compare(path, a, b)
+		return
+	}
+	for va.Kind() == reflect.Pointer {
+		if va.IsNil() || vb.IsNil() {
+			d.compare(path, a, b)
+			return
+		}
+		va, vb = va.Elem(), vb.Elem()
+	}
+	n := len(d.diffs)
+	if va.Kind() == reflect.Struct {
+		d.fields(path+".", va, vb)
+	}
+	if len(d.diffs) == n && !reflect.DeepEqual(a, b) {
+		// Only unexported fields differ, or the payload isn't a struct.
+		d.diffs = append(d.diffs, FieldDiff{path, a, b})
+	}
+}
+
+func (d *differ) compare(path string, a, b any) {
+	if !diffValuesEqual(a, b) {
+		d.diffs = append(d.diffs, FieldDiff{path, a, b})
+	}
+}
+
+func diffValuesEqual(a, b any) bool {
+	switch a := a.(type) {
+	case *big.Int:
+		b, ok := b.(*big.Int)
+		if !ok || a == nil || b == nil {
+			return ok && a == b
+		}
+		return a.Cmp(b) == 0
+	case *uint256.Int:
+		b, ok := b.(*uint256.Int)
+		if !ok || a == nil || b == nil {
+			return ok && a == b
+		}
+		return a.Eq(b)
+	case []byte:
+		b, ok := b.([]byte)
+		return ok && string(a) == string(b) // treats nil and empty as equal
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func formatDiffValue(v any) string {
+	switch v := v.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case fmt.Stringer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return "<nil>"
+		}
+		return v.String()
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		return fmt.Sprintf("%+v", rv.Elem().Interface())
+	}
+	return fmt.Sprintf("%+v", v)
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	. "github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/trie"
+)
+
+type diffHeaderExtra struct {
+	NOOPHeaderHooks
+	Fee    uint64
+	hidden int
+}
+
+func diffPaths(ds FieldDiffs) []string {
+	var paths []string
+	for _, d := range ds {
+		paths = append(paths, d.Path)
+	}
+	return paths
+}
+
+func TestDiffHeaders(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+	extras := RegisterExtras[
+		diffHeaderExtra, *diffHeaderExtra,
+		NOOPBlockBodyHooks, *NOOPBlockBodyHooks,
+		struct{},
+	]()
+
+	newHeader := func() *Header {
+		return &Header{
+			Number:   big.NewInt(42),
+			GasUsed:  21_000,
+			Extra:    []byte{1, 2, 3},
+			BaseFee:  big.NewInt(7),
+			Coinbase: common.Address{'c'},
+		}
+	}
+
+	a, b := newHeader(), newHeader()
+	// Equivalent but distinct *big.Int and nil vs empty payloads are equal.
+	b.Number = new(big.Int).SetBytes([]byte{42})
+	assert.Empty(t, DiffHeaders(a, b), "DiffHeaders() of equivalent headers")
+
+	b.GasUsed++
+	b.Extra = []byte{1, 2, 4}
+	extras.Header.Get(b).Fee = 99
+	got := DiffHeaders(a, b)
+	assert.Equal(t, []string{"(hash)", "GasUsed", "Extra", "Extras.Fee"}, diffPaths(got), "DiffHeaders() paths")
+	assert.Contains(t, got.String(), "GasUsed: 21000 != 21001")
+	assert.Contains(t, got.String(), "Extra: 0x010203 != 0x010204")
+	assert.Contains(t, got.String(), "Extras.Fee: 0 != 99")
+
+	t.Run("unexported_extras_field", func(t *testing.T) {
+		a, b := newHeader(), newHeader()
+		extras.Header.Get(b).hidden = 1
+		assert.Equal(t, []string{"Extras"}, diffPaths(DiffHeaders(a, b)))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Empty(t, DiffHeaders(nil, nil), "DiffHeaders(nil, nil)")
+		assert.Equal(t, []string{"Header"}, diffPaths(DiffHeaders(nil, newHeader())), "DiffHeaders(nil, non-nil)")
+		assert.Equal(t, []string{"Header"}, diffPaths(DiffHeaders(newHeader(), nil)), "DiffHeaders(non-nil, nil)")
+	})
+}
+
+func TestDiffBlocks(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+
+	newTx := func(nonce uint64) *Transaction {
+		return NewTx(&LegacyTx{
+			Nonce:    nonce,
+			To:       &common.Address{'t', 'o'},
+			Gas:      21_000,
+			GasPrice: big.NewInt(1),
+			Value:    big.NewInt(0),
+		})
+	}
+	newBlock := func(txs ...*Transaction) *Block {
+		return NewBlock(&Header{Number: big.NewInt(1)}, txs, nil, nil, trie.NewStackTrie(nil))
+	}
+
+	a := newBlock(newTx(0), newTx(1))
+	assert.Empty(t, DiffBlocks(a, newBlock(newTx(0), newTx(1)), trie.NewStackTrie(nil)), "DiffBlocks() of equivalent blocks")
+
+	got := DiffBlocks(a, newBlock(newTx(0), newTx(2)), trie.NewStackTrie(nil))
+	want := []string{
+		"Header.(hash)",
+		"Header.TxHash",
+		"Body.Transactions(root)",
+		"Body.Transactions[1].(hash)",
+		"Body.Transactions[1].Nonce",
+	}
+	require.Equal(t, want, diffPaths(got), "DiffBlocks() paths")
+
+	got = DiffBlocks(a, newBlock(newTx(0)), trie.NewStackTrie(nil))
+	assert.Contains(t, diffPaths(got), "Body.Transactions.len")
+
+	assert.Empty(t, DiffBlocks(nil, nil, trie.NewStackTrie(nil)), "DiffBlocks(nil, nil)")
+	assert.Equal(t, []string{"Block"}, diffPaths(DiffBlocks(a, nil, trie.NewStackTrie(nil))), "DiffBlocks(non-nil, nil)")
+	assert.Contains(t, DiffBlocks(nil, a, trie.NewStackTrie(nil)).String(), "Block: <nil> != ", "DiffBlocks(nil, non-nil).String()")
+}
@@ -24,6 +24,7 @@ func (l Log) MarshalJSON() ([]byte, error) {
 		BlockHash   common.Hash    `json:"blockHash" rlp:"-"`
 		Index       hexutil.Uint   `json:"logIndex" rlp:"-"`
 		Removed     bool           `json:"removed" rlp:"-"`
+		Origin      LogOrigin      `json:"origin,omitempty" rlp:"-"`
 	}
 	var enc Log
 	enc.Address = l.Address
@@ -35,6 +36,7 @@ func (l Log) MarshalJSON() ([]byte, error) {
 	enc.BlockHash = l.BlockHash
 	enc.Index = hexutil.Uint(l.Index)
 	enc.Removed = l.Removed
+	enc.Origin = l.Origin
 	return json.Marshal(&enc)
 }
 
@@ -50,6 +52,7 @@ func (l *Log) UnmarshalJSON(input []byte) error {
 		BlockHash   *common.Hash    `json:"blockHash" rlp:"-"`
 		Index       *hexutil.Uint   `json:"logIndex" rlp:"-"`
 		Removed     *bool           `json:"removed" rlp:"-"`
+		Origin      *LogOrigin      `json:"origin,omitempty" rlp:"-"`
 	}
 	var dec Log
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -86,5 +89,8 @@ func (l *Log) UnmarshalJSON(input []byte) error {
 	if dec.Removed != nil {
 		l.Removed = *dec.Removed
 	}
+	if dec.Origin != nil {
+		l.Origin = *dec.Origin
+	}
 	return nil
 }
@@ -51,6 +51,9 @@ type Log struct {
 	// The Removed field is true if this log was reverted due to a chain reorganisation.
 	// You must pay attention to this field if you receive logs through a filter query.
 	Removed bool `json:"removed" rlp:"-"`
+
+	// Origin identifies the execution path that emitted the log.
+	Origin LogOrigin `json:"origin,omitempty" rlp:"-"` // libevm
 }
 
 type logMarshaling struct {
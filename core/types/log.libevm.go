@@ -0,0 +1,67 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types
+
+import "fmt"
+
+// A LogOrigin identifies the execution path that emitted a [Log]. It is a
+// derived field, not secured by consensus nor persisted with receipts, and it
+// is the responsibility of the emitting path to set it. Logs read back from a
+// database therefore report their origin as [LogOriginTransaction] unless
+// explicitly restored.
+type LogOrigin uint8
+
+// Known [LogOrigin] values. The zero value is the default for all logs emitted
+// by the EVM during transaction execution, including by precompiles.
+const (
+	LogOriginTransaction LogOrigin = iota
+	LogOriginSystemCall            // e.g. the EIP-4788 beacon-root call
+	LogOriginPostBlock             // emitted by post-block processor hooks
+)
+
+var logOriginNames = map[LogOrigin]string{
+	LogOriginTransaction: "transaction",
+	LogOriginSystemCall:  "systemCall",
+	LogOriginPostBlock:   "postBlock",
+}
+
+// String returns the name of the origin, as used in its text encoding.
+func (o LogOrigin) String() string {
+	if n, ok := logOriginNames[o]; ok {
+		return n
+	}
+	return fmt.Sprintf("LogOrigin(%d)", uint8(o))
+}
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (o LogOrigin) MarshalText() ([]byte, error) {
+	if _, ok := logOriginNames[o]; !ok {
+		return nil, fmt.Errorf("unknown %T %d", o, uint8(o))
+	}
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (o *LogOrigin) UnmarshalText(text []byte) error {
+	for v, n := range logOriginNames {
+		if n == string(text) {
+			*o = v
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown %T %q", *o, text)
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+func TestLogOriginJSON(t *testing.T) {
+	for _, o := range []LogOrigin{LogOriginTransaction, LogOriginSystemCall, LogOriginPostBlock} {
+		t.Run(o.String(), func(t *testing.T) {
+			in := &Log{Topics: []common.Hash{}, Data: []byte{}, Origin: o}
+			buf, err := json.Marshal(in)
+			require.NoError(t, err, "json.Marshal(%T)", in)
+
+			var raw map[string]any
+			require.NoError(t, json.Unmarshal(buf, &raw))
+			if o == LogOriginTransaction {
+				assert.NotContains(t, raw, "origin", "default origin is omitted")
+			} else {
+				assert.Equal(t, o.String(), raw["origin"])
+			}
+
+			got := new(Log)
+			require.NoError(t, json.Unmarshal(buf, got), "json.Unmarshal(..., %T)", got)
+			assert.Equal(t, o, got.Origin)
+		})
+	}
+
+	_, err := LogOrigin(255).MarshalText()
+	assert.Error(t, err, "MarshalText() of unknown origin")
+}
@@ -203,8 +203,8 @@ func (r *Receipt) decodeTyped(b []byte) error {
 	if len(b) <= 1 {
 		return errShortTypedReceipt
 	}
-	switch b[0] {
-	case DynamicFeeTxType, AccessListTxType, BlobTxType:
+	switch typ := b[0]; {
+	case typ == DynamicFeeTxType, typ == AccessListTxType, typ == BlobTxType, isRegisteredTxType(typ): // libevm: registered types
 		var data receiptRLP
 		err := rlp.DecodeBytes(b[1:], &data)
 		if err != nil {
@@ -311,8 +311,8 @@ func (rs Receipts) EncodeIndex(i int, w *bytes.Buffer) {
 		return
 	}
 	w.WriteByte(r.Type)
-	switch r.Type {
-	case AccessListTxType, DynamicFeeTxType, BlobTxType:
+	switch typ := r.Type; {
+	case typ == AccessListTxType, typ == DynamicFeeTxType, typ == BlobTxType, isRegisteredTxType(typ): // libevm: registered types
 		rlp.Encode(w, data)
 	default:
 		// For unsupported types, write nothing. Since this is for
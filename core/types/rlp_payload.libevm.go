@@ -17,8 +17,11 @@
 package types
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"reflect"
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/internal/libevm/pseudo"
@@ -78,16 +81,10 @@ func payloadsAndConstructors[
 			(*Block).extraPayload,
 			func(b *Block, t *pseudo.Type) { b.extra = t },
 		),
-		StateAccount: pseudo.NewAccessor[StateOrSlimAccount, SA](
-			func(a StateOrSlimAccount) *pseudo.Type { return a.extra().payload() },
-			func(a StateOrSlimAccount, t *pseudo.Type) { a.extra().t = t },
-		),
+		StateAccount: stateAccountAccessor[SA](),
 	}
 	ctors := &extraConstructors{
-		stateAccountType: func() string {
-			var x SA
-			return fmt.Sprintf("%T", x)
-		}(),
+		stateAccountType: reflect.TypeFor[SA](),
 		// The [ExtraPayloads] that we returns is based on [HPtr,BPtr,SA], not
 		// [H,B,SA] so our constructors MUST match that. This guarantees that
 		// calls to the [HeaderHooks] and [BlockBodyHooks] methods will never be
@@ -100,6 +97,28 @@ func payloadsAndConstructors[
 	return payloads, ctors
 }
 
+func stateAccountAccessor[SA any]() pseudo.Accessor[StateOrSlimAccount, SA] {
+	return pseudo.NewAccessor[StateOrSlimAccount, SA](
+		func(a StateOrSlimAccount) *pseudo.Type { return a.extra().payload() },
+		func(a StateOrSlimAccount, t *pseudo.Type) { a.extra().t = t },
+	)
+}
+
+// StateAccountAccessor returns an accessor equivalent to the StateAccount field
+// of the [ExtraPayloads] returned by [RegisterExtras], for use by packages that
+// only know the `SA` type. It returns an error if there is no registration or
+// if `SA` is not the registered type.
+func StateAccountAccessor[SA any]() (pseudo.Accessor[StateOrSlimAccount, SA], error) {
+	r := registeredExtras
+	if !r.Registered() {
+		return pseudo.Accessor[StateOrSlimAccount, SA]{}, errors.New("no core/types extras registered")
+	}
+	if got, want := r.Get().stateAccountType, reflect.TypeFor[SA](); got != want {
+		return pseudo.Accessor[StateOrSlimAccount, SA]{}, fmt.Errorf("registered StateAccount extra type %v; requested %v", got, want)
+	}
+	return stateAccountAccessor[SA](), nil
+}
+
 // WithTempRegisteredExtras temporarily registers `HPtr`, `BPtr`, and `SA` as if
 // calling [RegisterExtras] the same type parameters. The [ExtraPayloads] are
 // passed to `fn` instead of being returned; the argument MUST NOT be persisted
@@ -159,7 +178,7 @@ func TestOnlyClearRegisteredExtras() {
 var registeredExtras register.AtMostOnce[*extraConstructors]
 
 type extraConstructors struct {
-	stateAccountType string
+	stateAccountType reflect.Type
 	newHeader        func() *pseudo.Type
 	newBlockOrBody   func() *pseudo.Type
 	newStateAccount  func() *pseudo.Type
@@ -378,6 +397,7 @@ var _ interface {
 	rlp.Encoder
 	rlp.Decoder
 	fmt.Formatter
+	json.Marshaler
 } = (*StateAccountExtra)(nil)
 
 // EncodeRLP implements the [rlp.Encoder] interface.
@@ -422,6 +442,20 @@ func (e *StateAccountExtra) Format(s fmt.State, verb rune) {
 	_, _ = s.Write([]byte(out))
 }
 
+// MarshalJSON implements the [json.Marshaler] interface, encoding the `SA`
+// payload registered with [RegisterExtras], or its zero value if `e` carries
+// none. It returns `null` if no type was registered.
+func (e *StateAccountExtra) MarshalJSON() ([]byte, error) {
+	switch r := registeredExtras; {
+	case !r.Registered():
+		return []byte("null"), nil
+	case e == nil, e.t == nil:
+		return r.Get().newStateAccount().MarshalJSON()
+	default:
+		return e.t.MarshalJSON()
+	}
+}
+
 // RLPHash returns the hash of the RLP encoding of `x`.
 func RLPHash(x any) common.Hash {
 	return rlpHash(x)
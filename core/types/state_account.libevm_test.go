@@ -214,9 +214,68 @@ func TestSlimAccountRLP(t *testing.T) {
 	}
 }
 
+func TestStateAccountExtraJSON(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+
+	marshal := func(t *testing.T, e *StateAccountExtra) string {
+		t.Helper()
+		buf, err := e.MarshalJSON()
+		require.NoErrorf(t, err, "%T.MarshalJSON()", e)
+		return string(buf)
+	}
+
+	acc := new(StateAccount)
+	require.Equal(t, `null`, marshal(t, acc.Extra), "without registered type")
+
+	extras := RegisterExtras[
+		NOOPHeaderHooks, *NOOPHeaderHooks,
+		NOOPBlockBodyHooks, *NOOPBlockBodyHooks,
+		map[string]int,
+	]()
+	require.Equal(t, `null`, marshal(t, acc.Extra), "nil extra of registered map type")
+
+	extras.StateAccount.Set(acc, map[string]int{"foo": 42})
+	require.Equal(t, `{"foo":42}`, marshal(t, acc.Extra), "after setting payload")
+	require.Equal(t, `{"foo":42}`, marshal(t, acc.Copy().Extra), "copied account")
+}
+
 func repeatAsHash(x byte) (h common.Hash) {
 	for i := range h {
 		h[i] = x
 	}
 	return h
 }
+
+func TestStateAccountAccessor(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+
+	// Both types have the same name, and therefore the same %T, but are
+	// nonetheless distinct.
+	type payload struct{ X int }
+	accessor := func() (pseudo.Accessor[StateOrSlimAccount, payload], error) {
+		return StateAccountAccessor[payload]()
+	}
+	sameName := func() error {
+		type payload struct{ X int }
+		_, err := StateAccountAccessor[payload]()
+		return err
+	}
+
+	_, err := accessor()
+	require.Error(t, err, "StateAccountAccessor() without registration")
+
+	RegisterExtras[
+		NOOPHeaderHooks, *NOOPHeaderHooks,
+		NOOPBlockBodyHooks, *NOOPBlockBodyHooks,
+		payload,
+	]()
+	a, err := accessor()
+	require.NoError(t, err, "StateAccountAccessor() of registered type")
+	require.Error(t, sameName(), "StateAccountAccessor() of different type with same name")
+
+	acc := NewEmptyStateAccount()
+	a.Set(acc, payload{X: 42})
+	require.Equal(t, payload{X: 42}, a.Get(acc), "Get() after Set()")
+}
@@ -207,7 +207,11 @@ func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
 	case BlobTxType:
 		inner = new(BlobTx)
 	default:
-		return nil, ErrTxTypeNotSupported
+		// libevm: transaction types registered with RegisterTxType()
+		var ok bool
+		if inner, ok = newRegisteredTxData(b[0]); !ok {
+			return nil, ErrTxTypeNotSupported
+		}
 	}
 	err := inner.decode(b[1:])
 	return inner, err
@@ -0,0 +1,334 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/libevm/testonly"
+)
+
+// CustomTxData is the exported equivalent of [TxData], implemented by
+// transaction types registered with [RegisterTxType]. The methods carry the
+// same semantics as their unexported counterparts on [TxData].
+type CustomTxData interface {
+	TxType() byte
+	Copy() CustomTxData
+
+	ChainID() *big.Int
+	AccessList() AccessList
+	Data() []byte
+	Gas() uint64
+	GasPrice() *big.Int
+	GasTipCap() *big.Int
+	GasFeeCap() *big.Int
+	Value() *big.Int
+	Nonce() uint64
+	To() *common.Address
+
+	RawSignatureValues() (v, r, s *big.Int)
+	SetSignatureValues(chainID, v, r, s *big.Int)
+	EffectiveGasPrice(dst *big.Int, baseFee *big.Int) *big.Int
+
+	// EncodePayload writes the EIP-2718 payload, excluding the type byte. It
+	// MUST be the inverse of the decoder passed to [RegisterTxType].
+	EncodePayload(*bytes.Buffer) error
+	// SigHash returns the hash to be signed by the sender, for use by all
+	// signers from Berlin onwards. The V signature value is interpreted as a
+	// y-parity, as with all other typed transactions.
+	SigHash(chainID *big.Int) common.Hash
+}
+
+// A TxTypeOption configures a transaction type registered with
+// [RegisterTxType].
+type TxTypeOption = options.Option[txTypeConfig]
+
+type txTypeConfig struct {
+	decode, decodeJSON func([]byte) (CustomTxData, error)
+}
+
+// WithJSONDecoder registers a decoder for the JSON representation of the
+// transaction type, which receives the entire JSON object, including the
+// "type" field. Without such a decoder [Transaction.UnmarshalJSON] returns
+// [ErrTxTypeNotSupported] for the type.
+//
+// If the [CustomTxData] implements [json.Marshaler] then its output is used
+// verbatim by [Transaction.MarshalJSON]; otherwise the standard fields common
+// to all transactions are marshalled.
+func WithJSONDecoder(fn func([]byte) (CustomTxData, error)) TxTypeOption {
+	return options.Func[txTypeConfig](func(c *txTypeConfig) {
+		c.decodeJSON = fn
+	})
+}
+
+var registeredTxTypes = make(map[byte]*txTypeConfig)
+
+// ErrTxTypeMismatch is returned when decoding a transaction of a type
+// registered with [RegisterTxType] if the decoded [CustomTxData.TxType] differs
+// from the type in the encoding.
+var ErrTxTypeMismatch = errors.New("decoded transaction type differs from encoded type")
+
+// RegisterTxType registers an EIP-2718 transaction type, allowing
+// [Transaction] values carrying [CustomTxData] to be constructed with
+// [NewCustomTx] or decoded with any of the standard methods. The decoder
+// receives the payload following the type byte.
+//
+// RegisterTxType MUST NOT be called more than once for the same `id`, which
+// MUST NOT be that of a geth transaction type nor greater than 0x7f. It is not
+// threadsafe and SHOULD be called in an `init()` function.
+func RegisterTxType(id byte, decoder func([]byte) (CustomTxData, error), opts ...TxTypeOption) {
+	switch id {
+	case LegacyTxType, AccessListTxType, DynamicFeeTxType, BlobTxType:
+		panic(fmt.Sprintf("transaction type %#x is reserved", id))
+	}
+	if id > 0x7f {
+		panic(fmt.Sprintf("transaction type %#x is not a valid EIP-2718 type", id))
+	}
+	if _, ok := registeredTxTypes[id]; ok {
+		panic(fmt.Sprintf("transaction type %#x already registered", id))
+	}
+	cfg := options.ApplyTo(&txTypeConfig{decode: decoder}, opts...)
+	registeredTxTypes[id] = cfg
+}
+
+// TestOnlyClearRegisteredTxTypes clears all types previously registered with
+// [RegisterTxType]. It panics if called from a non-testing call stack.
+func TestOnlyClearRegisteredTxTypes() {
+	testonly.OrPanic(func() {
+		clear(registeredTxTypes)
+	})
+}
+
+// NewCustomTx is equivalent to [NewTx] for transaction types registered with
+// [RegisterTxType]. It panics if `inner.TxType()` isn't registered.
+func NewCustomTx(inner CustomTxData) *Transaction {
+	if _, ok := registeredTxTypes[inner.TxType()]; !ok {
+		panic(fmt.Sprintf("%T.TxType() = %#x not registered", inner, inner.TxType()))
+	}
+	return NewTx(&customTx{custom: inner})
+}
+
+// CustomData returns the [CustomTxData] carried by the transaction, and a
+// boolean indicating whether it is of a type registered with
+// [RegisterTxType]. The returned value MUST NOT be modified.
+func (tx *Transaction) CustomData() (CustomTxData, bool) {
+	c, ok := tx.inner.(*customTx)
+	if !ok {
+		return nil, false
+	}
+	return c.custom, true
+}
+
+// newRegisteredTxData returns an empty [TxData] for decoding a registered
+// transaction type.
+func newRegisteredTxData(typ byte) (TxData, bool) {
+	cfg, ok := registeredTxTypes[typ]
+	if !ok {
+		return nil, false
+	}
+	return &customTx{typ: typ, decoder: cfg.decode}, true
+}
+
+func isRegisteredTxType(typ byte) bool {
+	_, ok := registeredTxTypes[typ]
+	return ok
+}
+
+// customTx adapts a [CustomTxData] into a [TxData].
+type customTx struct {
+	custom  CustomTxData
+	typ     byte                               // only used by decode()
+	decoder func([]byte) (CustomTxData, error) // only used by decode()
+}
+
+var _ TxData = (*customTx)(nil)
+
+func (c *customTx) txType() byte { return c.custom.TxType() }
+func (c *customTx) copy() TxData { return &customTx{custom: c.custom.Copy()} }
+
+func (c *customTx) chainID() *big.Int      { return c.custom.ChainID() }
+func (c *customTx) accessList() AccessList { return c.custom.AccessList() }
+func (c *customTx) data() []byte           { return c.custom.Data() }
+func (c *customTx) gas() uint64            { return c.custom.Gas() }
+func (c *customTx) gasPrice() *big.Int     { return c.custom.GasPrice() }
+func (c *customTx) gasTipCap() *big.Int    { return c.custom.GasTipCap() }
+func (c *customTx) gasFeeCap() *big.Int    { return c.custom.GasFeeCap() }
+func (c *customTx) value() *big.Int        { return c.custom.Value() }
+func (c *customTx) nonce() uint64          { return c.custom.Nonce() }
+func (c *customTx) to() *common.Address    { return c.custom.To() }
+
+func (c *customTx) rawSignatureValues() (v, r, s *big.Int) {
+	return c.custom.RawSignatureValues()
+}
+
+func (c *customTx) setSignatureValues(chainID, v, r, s *big.Int) {
+	c.custom.SetSignatureValues(chainID, v, r, s)
+}
+
+func (c *customTx) effectiveGasPrice(dst *big.Int, baseFee *big.Int) *big.Int {
+	return c.custom.EffectiveGasPrice(dst, baseFee)
+}
+
+func (c *customTx) encode(b *bytes.Buffer) error {
+	return c.custom.EncodePayload(b)
+}
+
+func (c *customTx) decode(b []byte) error {
+	if c.decoder == nil {
+		return ErrTxTypeNotSupported
+	}
+	data, err := c.decoder(b)
+	if err != nil {
+		return err
+	}
+	if err := checkDecodedTxType(c.typ, data); err != nil {
+		return err
+	}
+	c.custom = data
+	return nil
+}
+
+func checkDecodedTxType(typ byte, data CustomTxData) error {
+	if got := data.TxType(); got != typ {
+		return fmt.Errorf("%w: %T.TxType() = %#x; encoded as %#x", ErrTxTypeMismatch, data, got, typ)
+	}
+	return nil
+}
+
+// EncodeRLP writes the raw payload, as required by [prefixedRlpHash] when
+// computing the transaction hash.
+func (c *customTx) EncodeRLP(w io.Writer) error {
+	var buf bytes.Buffer
+	if err := c.encode(&buf); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// marshalJSON completes the [txJSON], which MUST already have its type and
+// hash populated, and marshals it.
+func (c *customTx) marshalJSON(enc *txJSON) ([]byte, error) {
+	if m, ok := c.custom.(json.Marshaler); ok {
+		return m.MarshalJSON()
+	}
+	var (
+		nonce = c.nonce()
+		gas   = c.gas()
+		data  = c.data()
+		al    = c.accessList()
+	)
+	enc.ChainID = (*hexutil.Big)(c.chainID())
+	enc.Nonce = (*hexutil.Uint64)(&nonce)
+	enc.To = c.to()
+	enc.Gas = (*hexutil.Uint64)(&gas)
+	enc.GasPrice = (*hexutil.Big)(c.gasPrice())
+	enc.MaxPriorityFeePerGas = (*hexutil.Big)(c.gasTipCap())
+	enc.MaxFeePerGas = (*hexutil.Big)(c.gasFeeCap())
+	enc.Value = (*hexutil.Big)(c.value())
+	enc.Input = (*hexutil.Bytes)(&data)
+	enc.AccessList = &al
+	v, r, s := c.rawSignatureValues()
+	enc.V = (*hexutil.Big)(v)
+	enc.R = (*hexutil.Big)(r)
+	enc.S = (*hexutil.Big)(s)
+	if v != nil && v.IsUint64() {
+		yParity := v.Uint64()
+		enc.YParity = (*hexutil.Uint64)(&yParity)
+	}
+	return json.Marshal(enc)
+}
+
+// decodeRegisteredTxJSON decodes the JSON `input` of a registered transaction
+// type.
+func decodeRegisteredTxJSON(typ hexutil.Uint64, input []byte) (TxData, error) {
+	if typ > 0xff {
+		return nil, ErrTxTypeNotSupported
+	}
+	cfg, ok := registeredTxTypes[byte(typ)]
+	if !ok || cfg.decodeJSON == nil {
+		return nil, ErrTxTypeNotSupported
+	}
+	data, err := cfg.decodeJSON(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDecodedTxType(byte(typ), data); err != nil {
+		return nil, err
+	}
+	return &customTx{custom: data}, nil
+}
+
+// customTxSender, customTxSignatureValues, and customTxSigHash implement the
+// [Signer] methods of the same names for registered transaction types, for
+// all signers from Berlin onwards. [SystemTxData] types bypass signatures
+// altogether.
+
+func customTxSender(tx *Transaction, chainID *big.Int) (common.Address, error) {
+	c, ok := tx.inner.(*customTx)
+	if !ok {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
+	if sys, ok := c.custom.(SystemTxData); ok {
+		return sys.From(), nil
+	}
+	V, R, S := c.rawSignatureValues()
+	if V == nil || R == nil || S == nil {
+		return common.Address{}, ErrInvalidSig
+	}
+	// As with all other typed transactions, V is a y-parity so add 27 to
+	// become equivalent to unprotected Homestead signatures.
+	V = new(big.Int).Add(V, big.NewInt(27))
+	if id := c.chainID(); id == nil || id.Cmp(chainID) != 0 {
+		return common.Address{}, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, id, chainID)
+	}
+	return recoverPlain(c.custom.SigHash(chainID), R, S, V, true)
+}
+
+func customTxSignatureValues(tx *Transaction, chainID *big.Int, sig []byte) (R, S, V *big.Int, err error) {
+	c, ok := tx.inner.(*customTx)
+	if !ok {
+		return nil, nil, nil, ErrTxTypeNotSupported
+	}
+	if _, ok := c.custom.(SystemTxData); ok {
+		return nil, nil, nil, ErrSystemTxSigning
+	}
+	// We also accept ID zero here, because it indicates that the chain ID was
+	// not specified in the tx.
+	if id := c.chainID(); id != nil && id.Sign() != 0 && id.Cmp(chainID) != 0 {
+		return nil, nil, nil, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, id, chainID)
+	}
+	R, S, _ = decodeSignature(sig)
+	V = big.NewInt(int64(sig[64]))
+	return R, S, V, nil
+}
+
+func customTxSigHash(tx *Transaction, chainID *big.Int) (common.Hash, bool) {
+	c, ok := tx.inner.(*customTx)
+	if !ok {
+		return common.Hash{}, false
+	}
+	return c.custom.SigHash(chainID), true
+}
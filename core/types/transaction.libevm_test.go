@@ -0,0 +1,363 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	. "github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+const sponsoredTxType = 0x7e
+
+// sponsoredTx is a fee-delegation transaction, used to demonstrate
+// [RegisterTxType].
+type sponsoredTx struct {
+	f sponsoredTxFields
+}
+
+// sponsoredTxFields are separate from [sponsoredTx] to avoid clashes with
+// [CustomTxData] method names.
+type sponsoredTxFields struct {
+	ChainID   *big.Int
+	Nonce     uint64
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+	Gas       uint64
+	To        *common.Address `rlp:"nil"`
+	Value     *big.Int
+	Input     []byte
+	Sponsor   common.Address
+	V, R, S   *big.Int
+}
+
+var _ CustomTxData = (*sponsoredTx)(nil)
+
+func (tx *sponsoredTx) TxType() byte { return sponsoredTxType }
+
+func (tx *sponsoredTx) Copy() CustomTxData {
+	cp := *tx
+	cp.f.Input = bytes.Clone(tx.f.Input)
+	f := &cp.f
+	for _, x := range []**big.Int{&f.ChainID, &f.GasTipCap, &f.GasFeeCap, &f.Value, &f.V, &f.R, &f.S} {
+		if *x != nil {
+			*x = new(big.Int).Set(*x)
+		}
+	}
+	return &cp
+}
+
+func (tx *sponsoredTx) ChainID() *big.Int      { return tx.f.ChainID }
+func (tx *sponsoredTx) AccessList() AccessList { return nil }
+func (tx *sponsoredTx) Data() []byte           { return tx.f.Input }
+func (tx *sponsoredTx) Gas() uint64            { return tx.f.Gas }
+func (tx *sponsoredTx) GasPrice() *big.Int     { return tx.f.GasFeeCap }
+func (tx *sponsoredTx) GasTipCap() *big.Int    { return tx.f.GasTipCap }
+func (tx *sponsoredTx) GasFeeCap() *big.Int    { return tx.f.GasFeeCap }
+func (tx *sponsoredTx) Value() *big.Int        { return tx.f.Value }
+func (tx *sponsoredTx) Nonce() uint64          { return tx.f.Nonce }
+func (tx *sponsoredTx) To() *common.Address    { return tx.f.To }
+
+func (tx *sponsoredTx) RawSignatureValues() (v, r, s *big.Int) {
+	return tx.f.V, tx.f.R, tx.f.S
+}
+
+func (tx *sponsoredTx) SetSignatureValues(chainID, v, r, s *big.Int) {
+	tx.f.ChainID, tx.f.V, tx.f.R, tx.f.S = chainID, v, r, s
+}
+
+func (tx *sponsoredTx) EffectiveGasPrice(dst *big.Int, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return dst.Set(tx.f.GasFeeCap)
+	}
+	tip := dst.Sub(tx.f.GasFeeCap, baseFee)
+	if tip.Cmp(tx.f.GasTipCap) > 0 {
+		tip.Set(tx.f.GasTipCap)
+	}
+	return tip.Add(tip, baseFee)
+}
+
+func (tx *sponsoredTx) EncodePayload(b *bytes.Buffer) error {
+	return rlp.Encode(b, &tx.f)
+}
+
+func (tx *sponsoredTx) SigHash(chainID *big.Int) common.Hash {
+	return crypto.Keccak256Hash(
+		[]byte{sponsoredTxType},
+		mustRLP(chainID, tx.f.Nonce, tx.f.GasTipCap, tx.f.GasFeeCap, tx.f.Gas, tx.f.To, tx.f.Value, tx.f.Input, tx.f.Sponsor),
+	)
+}
+
+func mustRLP(vals ...any) []byte {
+	b, err := rlp.EncodeToBytes(vals)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+type sponsoredTxJSON struct {
+	Type    hexutil.Uint64 `json:"type"`
+	Payload hexutil.Bytes  `json:"payload"`
+}
+
+func (tx *sponsoredTx) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tx.EncodePayload(&buf); err != nil {
+		return nil, err
+	}
+	return json.Marshal(sponsoredTxJSON{
+		Type:    sponsoredTxType,
+		Payload: buf.Bytes(),
+	})
+}
+
+func decodeSponsoredTx(b []byte) (CustomTxData, error) {
+	tx := new(sponsoredTx)
+	if err := rlp.DecodeBytes(b, &tx.f); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func decodeSponsoredTxJSON(b []byte) (CustomTxData, error) {
+	var enc sponsoredTxJSON
+	if err := json.Unmarshal(b, &enc); err != nil {
+		return nil, err
+	}
+	return decodeSponsoredTx(enc.Payload)
+}
+
+func TestRegisterTxType(t *testing.T) {
+	TestOnlyClearRegisteredTxTypes()
+	t.Cleanup(TestOnlyClearRegisteredTxTypes)
+	RegisterTxType(sponsoredTxType, decodeSponsoredTx, WithJSONDecoder(decodeSponsoredTxJSON))
+
+	for _, id := range []byte{LegacyTxType, AccessListTxType, DynamicFeeTxType, BlobTxType, 0x80, sponsoredTxType} {
+		assert.Panicsf(t, func() {
+			RegisterTxType(id, decodeSponsoredTx)
+		}, "RegisterTxType(%#x, ...)", id)
+	}
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	chainID := big.NewInt(43114)
+	signer := LatestSignerForChainID(chainID)
+
+	to := common.Address{'t', 'o'}
+	tx, err := SignTx(NewCustomTx(&sponsoredTx{f: sponsoredTxFields{
+		Nonce:     42,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(100),
+		Gas:       21_000,
+		To:        &to,
+		Value:     big.NewInt(1e9),
+		Input:     []byte("hello"),
+		Sponsor:   common.Address{'s', 'p', 'o', 'n', 's', 'o', 'r'},
+	}}), signer, key)
+	require.NoError(t, err, "SignTx(NewCustomTx(...))")
+
+	assert.Equal(t, uint8(sponsoredTxType), tx.Type(), "Type()")
+	assert.Equal(t, chainID, tx.ChainId(), "ChainId()")
+	assert.Equal(t, &to, tx.To(), "To()")
+	assert.Equal(t, big.NewInt(1), tx.EffectiveGasTipValue(big.NewInt(50)), "EffectiveGasTipValue()")
+
+	sender, err := Sender(signer, tx)
+	require.NoError(t, err, "Sender()")
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), sender, "Sender()")
+
+	_, err = Sender(LatestSignerForChainID(big.NewInt(1)), tx)
+	assert.ErrorIs(t, err, ErrInvalidChainId, "Sender() with different chain ID")
+
+	bin, err := tx.MarshalBinary()
+	require.NoError(t, err, "MarshalBinary()")
+	assert.Equal(t, byte(sponsoredTxType), bin[0], "MarshalBinary()[0]")
+	assert.Equal(t, crypto.Keccak256Hash(bin), tx.Hash(), "Hash() is of MarshalBinary() output")
+
+	roundTrips := []struct {
+		name string
+		enc  func(*Transaction) ([]byte, error)
+		dec  func([]byte, *Transaction) error
+	}{
+		{
+			name: "binary",
+			enc:  (*Transaction).MarshalBinary,
+			dec: func(b []byte, tx *Transaction) error {
+				return tx.UnmarshalBinary(b)
+			},
+		},
+		{
+			name: "rlp",
+			enc: func(tx *Transaction) ([]byte, error) {
+				return rlp.EncodeToBytes(tx)
+			},
+			dec: func(b []byte, tx *Transaction) error {
+				return rlp.DecodeBytes(b, tx)
+			},
+		},
+		{
+			name: "json",
+			enc:  (*Transaction).MarshalJSON,
+			dec: func(b []byte, tx *Transaction) error {
+				return tx.UnmarshalJSON(b)
+			},
+		},
+	}
+
+	for _, rt := range roundTrips {
+		t.Run(rt.name, func(t *testing.T) {
+			buf, err := rt.enc(tx)
+			require.NoError(t, err, "encode")
+			got := new(Transaction)
+			require.NoError(t, rt.dec(buf, got), "decode")
+
+			assert.Equal(t, tx.Hash(), got.Hash(), "Hash()")
+			want, _ := tx.CustomData()
+			gotData, ok := got.CustomData()
+			require.True(t, ok, "CustomData() returns true")
+			assert.Equal(t, want, gotData, "CustomData()")
+
+			sender, err := Sender(signer, got)
+			require.NoError(t, err, "Sender()")
+			assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), sender, "Sender()")
+		})
+	}
+
+	t.Run("receipt", func(t *testing.T) {
+		r := &Receipt{
+			Type:              sponsoredTxType,
+			Status:            ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21_000,
+			Logs:              []*Log{},
+		}
+		buf, err := r.MarshalBinary()
+		require.NoError(t, err, "%T.MarshalBinary()", r)
+		got := new(Receipt)
+		require.NoError(t, got.UnmarshalBinary(buf), "%T.UnmarshalBinary()", got)
+		assert.Equal(t, r.Type, got.Type, "%T.Type", got)
+	})
+
+	t.Run("type_mismatch", func(t *testing.T) {
+		const otherType = sponsoredTxType - 1
+		RegisterTxType(otherType, decodeSponsoredTx, WithJSONDecoder(decodeSponsoredTxJSON))
+
+		mismatched := append([]byte{otherType}, bin[1:]...)
+		assert.ErrorIs(t, new(Transaction).UnmarshalBinary(mismatched), ErrTxTypeMismatch, "UnmarshalBinary()")
+
+		var payload bytes.Buffer
+		want, _ := tx.CustomData()
+		require.NoError(t, want.EncodePayload(&payload), "EncodePayload()")
+		buf, err := json.Marshal(sponsoredTxJSON{Type: otherType, Payload: payload.Bytes()})
+		require.NoError(t, err, "json.Marshal()")
+		assert.ErrorIs(t, new(Transaction).UnmarshalJSON(buf), ErrTxTypeMismatch, "UnmarshalJSON()")
+	})
+
+	t.Run("sender_nil_values", func(t *testing.T) {
+		v, r, s := tx.RawSignatureValues()
+		tests := []struct {
+			name   string
+			fields sponsoredTxFields
+			want   error
+		}{
+			{
+				name:   "unsigned",
+				fields: sponsoredTxFields{ChainID: chainID},
+				want:   ErrInvalidSig,
+			},
+			{
+				name:   "nil_chain_id",
+				fields: sponsoredTxFields{V: v, R: r, S: s},
+				want:   ErrInvalidChainId,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := Sender(signer, NewCustomTx(&sponsoredTx{f: tt.fields}))
+				assert.ErrorIs(t, err, tt.want, "Sender()")
+			})
+		}
+	})
+
+	t.Run("unregistered", func(t *testing.T) {
+		TestOnlyClearRegisteredTxTypes()
+		assert.ErrorIs(t, new(Transaction).UnmarshalBinary(bin), ErrTxTypeNotSupported, "UnmarshalBinary()")
+	})
+}
+
+// systemSponsoredTx converts a [sponsoredTx] into a [SystemTxData].
+type systemSponsoredTx struct {
+	*sponsoredTx
+	from common.Address
+}
+
+var _ SystemTxData = (*systemSponsoredTx)(nil)
+
+func (tx *systemSponsoredTx) Copy() CustomTxData {
+	return &systemSponsoredTx{
+		sponsoredTx: tx.sponsoredTx.Copy().(*sponsoredTx), //nolint:forcetypeassert // known concrete type
+		from:        tx.from,
+	}
+}
+
+func (tx *systemSponsoredTx) From() common.Address { return tx.from }
+func (tx *systemSponsoredTx) Mint() *big.Int       { return nil }
+func (tx *systemSponsoredTx) PaysFees() bool       { return false }
+
+func TestSystemTx(t *testing.T) {
+	TestOnlyClearRegisteredTxTypes()
+	t.Cleanup(TestOnlyClearRegisteredTxTypes)
+	RegisterTxType(sponsoredTxType, decodeSponsoredTx)
+
+	chainID := big.NewInt(43114)
+	signer := LatestSignerForChainID(chainID)
+	from := common.Address{'s', 'y', 's'}
+	inner := &sponsoredTx{f: sponsoredTxFields{
+		ChainID: chainID,
+		V:       new(big.Int),
+		R:       new(big.Int),
+		S:       new(big.Int),
+	}}
+
+	regular := NewCustomTx(inner)
+	assert.False(t, regular.IsSystemTx(), "IsSystemTx() of non-system custom type")
+	assert.False(t, NewTx(&LegacyTx{}).IsSystemTx(), "IsSystemTx() of geth type")
+
+	tx := NewCustomTx(&systemSponsoredTx{sponsoredTx: inner, from: from})
+	require.True(t, tx.IsSystemTx(), "IsSystemTx()")
+	sys, ok := tx.SystemData()
+	require.True(t, ok, "SystemData()")
+	assert.Equal(t, from, sys.From(), "SystemData().From()")
+
+	got, err := Sender(signer, tx)
+	require.NoError(t, err, "Sender() without signature")
+	assert.Equal(t, from, got, "Sender()")
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	_, err = SignTx(tx, signer, key)
+	assert.ErrorIs(t, err, ErrSystemTxSigning, "SignTx()")
+}
@@ -153,6 +153,9 @@ func (tx *Transaction) MarshalJSON() ([]byte, error) {
 			enc.Commitments = itx.Sidecar.Commitments
 			enc.Proofs = itx.Sidecar.Proofs
 		}
+
+	case *customTx: // libevm
+		return itx.marshalJSON(&enc)
 	}
 	return json.Marshal(&enc)
 }
@@ -410,7 +413,11 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 		}
 
 	default:
-		return ErrTxTypeNotSupported
+		// libevm: transaction types registered with RegisterTxType()
+		inner, err = decodeRegisteredTxJSON(dec.Type, input)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Now set the inner transaction.
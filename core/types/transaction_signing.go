@@ -337,7 +337,7 @@ func (s eip2930Signer) Sender(tx *Transaction) (common.Address, error) {
 		// id, add 27 to become equivalent to unprotected Homestead signatures.
 		V = new(big.Int).Add(V, big.NewInt(27))
 	default:
-		return common.Address{}, ErrTxTypeNotSupported
+		return customTxSender(tx, s.chainId) // libevm: was ErrTxTypeNotSupported
 	}
 	if tx.ChainId().Cmp(s.chainId) != 0 {
 		return common.Address{}, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, tx.ChainId(), s.chainId)
@@ -358,7 +358,7 @@ func (s eip2930Signer) SignatureValues(tx *Transaction, sig []byte) (R, S, V *bi
 		R, S, _ = decodeSignature(sig)
 		V = big.NewInt(int64(sig[64]))
 	default:
-		return nil, nil, nil, ErrTxTypeNotSupported
+		return customTxSignatureValues(tx, s.chainId, sig) // libevm: was ErrTxTypeNotSupported
 	}
 	return R, S, V, nil
 }
@@ -383,6 +383,9 @@ func (s eip2930Signer) Hash(tx *Transaction) common.Hash {
 				tx.AccessList(),
 			})
 	default:
+		if h, ok := customTxSigHash(tx, s.chainId); ok { // libevm
+			return h
+		}
 		// This _should_ not happen, but in case someone sends in a bad
 		// json struct via RPC, it's probably more prudent to return an
 		// empty hash instead of killing the node with a panic
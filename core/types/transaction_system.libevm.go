@@ -0,0 +1,69 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+// A SystemTxData is a [CustomTxData] of a "system" transaction type, such as a
+// rollup deposit: one that is originated by consensus rather than signed by an
+// account holder. Such types are registered with [RegisterTxType] as usual,
+// and their receipts use the standard typed-receipt encoding.
+//
+// All [Signer]s from Berlin onwards return From() as the sender without any
+// signature recovery, and refuse to sign, so RawSignatureValues() SHOULD return
+// zero values. The execution semantics are defined
+// by the remaining methods and honoured by `core.TransactionToMessage` and the
+// state transition.
+type SystemTxData interface {
+	CustomTxData
+	// From returns the sender of the transaction.
+	From() common.Address
+	// Mint returns the value, which MAY be nil, to be credited to From() before
+	// execution.
+	Mint() *big.Int
+	// PaysFees returns whether the sender pays for gas, as with a regular
+	// transaction. If false then all fee checks, gas purchase, refunds, and
+	// tips are skipped, but gas is still consumed from the block's limit.
+	PaysFees() bool
+}
+
+// ErrSystemTxSigning is returned when attempting to sign a [SystemTxData]
+// transaction.
+var ErrSystemTxSigning = errors.New("system transactions are not signed")
+
+// SystemData returns the [SystemTxData] carried by the transaction, and a
+// boolean indicating whether it is a system transaction. The returned value
+// MUST NOT be modified.
+func (tx *Transaction) SystemData() (SystemTxData, bool) {
+	c, ok := tx.CustomData()
+	if !ok {
+		return nil, false
+	}
+	s, ok := c.(SystemTxData)
+	return s, ok
+}
+
+// IsSystemTx returns whether the transaction carries [SystemTxData].
+func (tx *Transaction) IsSystemTx() bool {
+	_, ok := tx.SystemData()
+	return ok
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/crypto/kzg4844"
+	"github.com/ava-labs/libevm/params"
+)
+
+// PointEvaluationHooks is an optional extension of [Hooks]. If implemented by
+// the registered [Hooks], the verification backend of the EIP-4844
+// point-evaluation precompile can be replaced, e.g. by a different commitment
+// scheme, while keeping the precompile's input and output format, gas cost,
+// and address unchanged. Replacing or repricing the entire precompile is
+// instead possible via [params.RulesHooks.PrecompileOverride].
+type PointEvaluationHooks interface {
+	// PointEvaluationVerifier returns the verifier used under the specific
+	// rules, or nil to use the default KZG verification.
+	PointEvaluationVerifier(params.Rules) PointEvaluationVerifier
+}
+
+// A PointEvaluationVerifier verifies the decoded input of the point-evaluation
+// precompile. A nil error is considered proof that `Claim` is the evaluation,
+// at `Point`, of the polynomial committed to by `Commitment`, and that
+// `VersionedHash` is the versioned hash of `Commitment`.
+type PointEvaluationVerifier interface {
+	VerifyPointEvaluation(PointEvaluationInput) error
+}
+
+// PointEvaluationInput is the decoded input to the point-evaluation
+// precompile.
+type PointEvaluationInput struct {
+	VersionedHash common.Hash
+	Point         kzg4844.Point
+	Claim         kzg4844.Claim
+	Commitment    kzg4844.Commitment
+	Proof         kzg4844.Proof
+}
+
+func (evm *EVM) pointEvaluationVerifier() PointEvaluationVerifier {
+	if !libevmHooks.Registered() {
+		return nil
+	}
+	h, ok := libevmHooks.Get().(PointEvaluationHooks)
+	if !ok {
+		return nil
+	}
+	return h.PointEvaluationVerifier(evm.chainRules)
+}
+
+// runPointEvaluation is equivalent to [kzgPointEvaluation.Run] except that
+// verification is delegated to `v`.
+func runPointEvaluation(v PointEvaluationVerifier, input []byte) ([]byte, error) {
+	if len(input) != blobVerifyInputLength {
+		return nil, errBlobVerifyInvalidInputLength
+	}
+	var in PointEvaluationInput
+	copy(in.VersionedHash[:], input[:32])
+	copy(in.Point[:], input[32:64])
+	copy(in.Claim[:], input[64:96])
+	copy(in.Commitment[:], input[96:144])
+	copy(in.Proof[:], input[144:])
+
+	if err := v.VerifyPointEvaluation(in); err != nil {
+		return nil, err
+	}
+	return common.Hex2Bytes(blobPrecompileReturnValue), nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/params"
+)
+
+// A Checkpoint is the serialisable state of a contract's execution, paused
+// between opcodes by [EVMInterpreter.RunSliced]. It can be encoded with
+// either RLP or JSON.
+//
+// A Checkpoint only captures the state of the interpreter. State changes
+// already made via the [StateDB] are not reverted by pausing, and it is the
+// responsibility of the caller to persist them alongside the Checkpoint.
+type Checkpoint struct {
+	PC                uint64
+	Gas               uint64
+	Stack             []uint256.Int
+	Memory            []byte
+	MemoryLastGasCost uint64
+	ReturnData        []byte
+	ReadOnly          bool
+}
+
+var (
+	// ErrCheckpointingDisabled is returned by [EVMInterpreter.RunSliced] if
+	// [Config.ExperimentalCheckpointing] is false.
+	ErrCheckpointingDisabled = errors.New("experimental checkpointing disabled")
+
+	errCheckpointed = errors.New("execution checkpointed")
+)
+
+// RunSliced is an EXPERIMENTAL equivalent of [EVMInterpreter.Run] that pauses
+// execution once at least `sliceGas` gas has been consumed, returning a
+// [Checkpoint] from which execution can later be resumed. A nil Checkpoint is
+// returned, along with the regular return values of Run, if the contract
+// halts before the slice is exhausted.
+//
+// To resume execution, call RunSliced with the same `contract` and `input`,
+// and the last returned Checkpoint as `from`. The `readOnly` argument and the
+// contract's gas are ignored in favour of the values in `from`. Execution MUST
+// be resumed at the same call depth as that at which it was paused.
+//
+// Only the frame of `contract` is ever paused; nested calls always run to
+// completion within the opcode that invokes them. Tracing is not supported.
+// Checkpointing MUST be enabled via [Config.ExperimentalCheckpointing].
+func (in *EVMInterpreter) RunSliced(contract *Contract, input []byte, readOnly bool, sliceGas uint64, from *Checkpoint) ([]byte, *Checkpoint, error) {
+	switch cfg := in.evm.Config; {
+	case !cfg.ExperimentalCheckpointing:
+		return nil, nil, ErrCheckpointingDisabled
+	case cfg.Tracer != nil:
+		return nil, nil, errors.New("checkpointing with a tracer is unsupported")
+	case sliceGas == 0:
+		return nil, nil, errors.New("zero gas slice")
+	}
+	if from != nil {
+		if err := from.validate(contract); err != nil {
+			return nil, nil, err
+		}
+		contract.Gas = from.Gas
+		readOnly = from.ReadOnly
+	}
+
+	slice := &gasSlice{
+		depth:    in.evm.depth + 1, // incremented by run()
+		startGas: contract.Gas,
+		gas:      sliceGas,
+		readOnly: readOnly,
+		from:     from,
+	}
+	ret, err := in.run(contract, input, readOnly, slice)
+	if err == errCheckpointed {
+		return nil, slice.paused, nil
+	}
+	return ret, nil, err
+}
+
+func (cp *Checkpoint) validate(contract *Contract) error {
+	if n := uint64(len(contract.Code)); cp.PC >= n {
+		return fmt.Errorf("checkpoint PC %d beyond code length %d", cp.PC, n)
+	}
+	if !contract.isCode(cp.PC) {
+		return fmt.Errorf("checkpoint PC %d not on an opcode boundary", cp.PC)
+	}
+	if n := len(cp.Stack); n > int(params.StackLimit) {
+		return fmt.Errorf("checkpoint stack height %d exceeds limit %d", n, params.StackLimit)
+	}
+	size := uint64(len(cp.Memory))
+	if size%32 != 0 {
+		return fmt.Errorf("checkpoint memory length %d not a multiple of 32", size)
+	}
+	// See [memoryGasCost], which rejects larger sizes to avoid overflow.
+	if size > 0x1FFFFFFFE0 {
+		return fmt.Errorf("checkpoint memory length %d: %w", size, ErrGasUintOverflow)
+	}
+	words := size / 32
+	if want := words*params.MemoryGas + words*words/params.QuadCoeffDiv; cp.MemoryLastGasCost != want {
+		return fmt.Errorf("checkpoint memory gas cost %d inconsistent with length %d; expected %d", cp.MemoryLastGasCost, size, want)
+	}
+	return nil
+}
+
+// A gasSlice carries the state of a [EVMInterpreter.RunSliced] call. All
+// methods are no-ops on a nil receiver.
+type gasSlice struct {
+	depth         int
+	startGas, gas uint64
+	readOnly      bool
+	from, paused  *Checkpoint
+}
+
+// resume restores the state of the interpreter from the [Checkpoint] that
+// execution is resuming from, if any.
+func (s *gasSlice) resume(pc *uint64, scope *ScopeContext, in *EVMInterpreter) {
+	if s == nil || s.from == nil {
+		return
+	}
+	cp := s.from
+	*pc = cp.PC
+	scope.Stack.data = append(scope.Stack.data[:0], cp.Stack...)
+	scope.Memory.store = slices.Clone(cp.Memory)
+	scope.Memory.lastGasCost = cp.MemoryLastGasCost
+	in.returnData = slices.Clone(cp.ReturnData)
+}
+
+// maybePause returns true, having populated `s.paused`, iff the gas slice is
+// exhausted and `scope` is the frame being sliced.
+func (s *gasSlice) maybePause(pc uint64, scope *ScopeContext, in *EVMInterpreter) bool {
+	if s == nil || in.evm.depth != s.depth || s.startGas-scope.Contract.Gas < s.gas {
+		return false
+	}
+	s.paused = &Checkpoint{
+		PC:                pc,
+		Gas:               scope.Contract.Gas,
+		Stack:             slices.Clone(scope.Stack.data),
+		Memory:            slices.Clone(scope.Memory.store),
+		MemoryLastGasCost: scope.Memory.lastGasCost,
+		ReturnData:        slices.Clone(in.returnData),
+		ReadOnly:          s.readOnly,
+	}
+	return true
+}
@@ -0,0 +1,293 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// checkpointTestCode returns code that, for i in [0,10), stores i*i in both
+// memory and storage slot i, before calling `callee` and copying its return
+// data to memory. The entire memory is then returned.
+func checkpointTestCode(callee common.Address) []byte {
+	code := []byte{
+		byte(vm.PUSH1), 0,
+		byte(vm.JUMPDEST),                          // loop; pc == 2
+		byte(vm.DUP1), byte(vm.DUP1), byte(vm.MUL), // [i, i*i]
+		byte(vm.DUP1), byte(vm.DUP3), byte(vm.SSTORE), // slot[i] = i*i
+		byte(vm.DUP2), byte(vm.PUSH1), 32, byte(vm.MUL), byte(vm.MSTORE), // mem[32i] = i*i
+		byte(vm.PUSH1), 1, byte(vm.ADD),
+		byte(vm.DUP1), byte(vm.PUSH1), 10, byte(vm.GT),
+		byte(vm.PUSH1), 2, byte(vm.JUMPI),
+		byte(vm.POP),
+		// CALL(gas, callee, 0, 0, 0, 0, 0)
+		byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0,
+		byte(vm.PUSH20),
+	}
+	code = append(code, callee.Bytes()...)
+	code = append(code,
+		byte(vm.GAS), byte(vm.CALL), byte(vm.POP),
+		// RETURNDATACOPY(320, 0, RETURNDATASIZE)
+		byte(vm.RETURNDATASIZE), byte(vm.PUSH1), 0, byte(vm.PUSH2), 0x01, 0x40, byte(vm.RETURNDATACOPY),
+		byte(vm.MSIZE), byte(vm.PUSH1), 0, byte(vm.RETURN),
+	)
+	return code
+}
+
+// returns 42 as a 32-byte word
+var checkpointCalleeCode = []byte{
+	byte(vm.PUSH1), 42, byte(vm.PUSH1), 0, byte(vm.MSTORE),
+	byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN),
+}
+
+type checkpointTest struct {
+	caller, self, callee common.Address
+	code                 []byte
+}
+
+func newCheckpointTest() *checkpointTest {
+	callee := common.Address{'c', 'a', 'l', 'l', 'e', 'e'}
+	return &checkpointTest{
+		caller: common.Address{'c', 'a', 'l', 'l', 'e', 'r'},
+		self:   common.Address{'s', 'e', 'l', 'f'},
+		callee: callee,
+		code:   checkpointTestCode(callee),
+	}
+}
+
+func (c *checkpointTest) newStateDB(t *testing.T) *state.StateDB {
+	t.Helper()
+	_, _, sdb := ethtest.NewEmptyStateDB(t)
+	sdb.SetCode(c.self, c.code)
+	sdb.SetCode(c.callee, checkpointCalleeCode)
+	// The access list would usually be populated by [state.StateDB.Prepare]
+	// but there is no transaction in these tests.
+	sdb.AddAddressToAccessList(c.self)
+	return sdb
+}
+
+// newInterpreter returns a new interpreter, simulating execution in a fresh
+// block, backed by `sdb`.
+func (c *checkpointTest) newInterpreter(sdb vm.StateDB, cfg vm.Config) *vm.EVMInterpreter {
+	evm := vm.NewEVM(
+		vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			BlockNumber: big.NewInt(1),
+		},
+		vm.TxContext{},
+		sdb,
+		params.TestChainConfig,
+		cfg,
+	)
+	return evm.Interpreter()
+}
+
+func (c *checkpointTest) newContract(gas uint64) *vm.Contract {
+	contract := vm.NewContract(vm.AccountRef(c.caller), vm.AccountRef(c.self), uint256.NewInt(0), gas)
+	contract.SetCallCode(&c.self, crypto.Keccak256Hash(c.code), c.code)
+	return contract
+}
+
+func (c *checkpointTest) storage(sdb vm.StateDB) []common.Hash {
+	var s []common.Hash
+	for i := 0; i < 10; i++ {
+		s = append(s, sdb.GetState(c.self, common.BigToHash(big.NewInt(int64(i)))))
+	}
+	return s
+}
+
+func TestCheckpointResume(t *testing.T) {
+	const gas = 1e6
+	enabled := vm.Config{ExperimentalCheckpointing: true}
+	test := newCheckpointTest()
+
+	wantDB := test.newStateDB(t)
+	wantContract := test.newContract(gas)
+	want, err := test.newInterpreter(wantDB, enabled).Run(wantContract, nil, false)
+	require.NoError(t, err, "straight-through Run()")
+	require.Len(t, want, 352, "straight-through output length; sanity check")
+	require.Equal(t, byte(42), want[351], "straight-through output from call; sanity check")
+
+	roundTrips := map[string]func(*testing.T, *vm.Checkpoint) *vm.Checkpoint{
+		"rlp": func(t *testing.T, cp *vm.Checkpoint) *vm.Checkpoint {
+			t.Helper()
+			buf, err := rlp.EncodeToBytes(cp)
+			require.NoErrorf(t, err, "rlp.EncodeToBytes(%T)", cp)
+			got := new(vm.Checkpoint)
+			require.NoErrorf(t, rlp.DecodeBytes(buf, got), "rlp.DecodeBytes(..., %T)", got)
+			return got
+		},
+		"json": func(t *testing.T, cp *vm.Checkpoint) *vm.Checkpoint {
+			t.Helper()
+			buf, err := json.Marshal(cp)
+			require.NoErrorf(t, err, "json.Marshal(%T)", cp)
+			got := new(vm.Checkpoint)
+			require.NoErrorf(t, json.Unmarshal(buf, got), "json.Unmarshal(..., %T)", got)
+			return got
+		},
+	}
+
+	for _, slice := range []uint64{1, 3, 50, 1000, 30_000, gas} {
+		for name, roundTrip := range roundTrips {
+			t.Run(fmt.Sprintf("slice_%d_%s", slice, name), func(t *testing.T) {
+				sdb := test.newStateDB(t)
+				var (
+					cp     *vm.Checkpoint
+					got    []byte
+					err    error
+					slices int
+				)
+				for {
+					contract := test.newContract(gas)
+					got, cp, err = test.newInterpreter(sdb, enabled).RunSliced(contract, nil, false, slice, cp)
+					require.NoErrorf(t, err, "RunSliced() #%d", slices)
+					slices++
+					if cp == nil {
+						assert.Equal(t, wantContract.Gas, contract.Gas, "remaining gas")
+						break
+					}
+					cp = roundTrip(t, cp)
+				}
+
+				assert.Equal(t, want, got, "return data")
+				assert.Equal(t, test.storage(wantDB), test.storage(sdb), "storage")
+				if slice < gas-wantContract.Gas {
+					assert.Greater(t, slices, 1, "number of slices")
+				}
+			})
+		}
+	}
+}
+
+func TestCheckpointErrors(t *testing.T) {
+	test := newCheckpointTest()
+	sdb := test.newStateDB(t)
+
+	tests := []struct {
+		name string
+		cfg  vm.Config
+		gas  uint64
+		from *vm.Checkpoint
+	}{
+		{
+			name: "disabled",
+			gas:  1,
+		},
+		{
+			name: "zero_slice",
+			cfg:  vm.Config{ExperimentalCheckpointing: true},
+		},
+		{
+			name: "pc_beyond_code",
+			cfg:  vm.Config{ExperimentalCheckpointing: true},
+			gas:  1,
+			from: &vm.Checkpoint{PC: uint64(len(test.code))},
+		},
+		{
+			name: "pc_in_push_data",
+			cfg:  vm.Config{ExperimentalCheckpointing: true},
+			gas:  1,
+			from: &vm.Checkpoint{PC: 1},
+		},
+		{
+			name: "memory_not_word_aligned",
+			cfg:  vm.Config{ExperimentalCheckpointing: true},
+			gas:  1,
+			from: &vm.Checkpoint{Memory: make([]byte, 31)},
+		},
+		{
+			name: "memory_gas_cost_inconsistent",
+			cfg:  vm.Config{ExperimentalCheckpointing: true},
+			gas:  1,
+			from: &vm.Checkpoint{Memory: make([]byte, 32)},
+		},
+		{
+			name: "stack_overflow",
+			cfg:  vm.Config{ExperimentalCheckpointing: true},
+			gas:  1,
+			from: &vm.Checkpoint{Stack: make([]uint256.Int, params.StackLimit+1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := test.newInterpreter(sdb, tt.cfg).RunSliced(test.newContract(1e6), nil, false, tt.gas, tt.from)
+			require.Error(t, err)
+		})
+	}
+
+	_, _, err := test.newInterpreter(sdb, vm.Config{}).RunSliced(test.newContract(1e6), nil, false, 1, nil)
+	require.ErrorIs(t, err, vm.ErrCheckpointingDisabled)
+}
+
+// BenchmarkInterpreterLoop measures opcode dispatch, which is affected by the
+// per-opcode check for a checkpoint. The "run" case, which never checkpoints,
+// can be compared against a build without checkpointing support.
+func BenchmarkInterpreterLoop(b *testing.B) {
+	const (
+		iterations = 10_000
+		gas        = 1e6
+	)
+	self := common.Address{'s', 'e', 'l', 'f'}
+	code := []byte{
+		byte(vm.PUSH2), iterations >> 8, iterations & 0xff,
+		byte(vm.JUMPDEST), // loop; pc == 3
+		byte(vm.PUSH1), 1, byte(vm.SWAP1), byte(vm.SUB),
+		byte(vm.DUP1), byte(vm.PUSH1), 3, byte(vm.JUMPI),
+		byte(vm.STOP),
+	}
+	newContract := func() *vm.Contract {
+		c := vm.NewContract(vm.AccountRef{}, vm.AccountRef(self), uint256.NewInt(0), gas)
+		c.SetCallCode(&self, crypto.Keccak256Hash(code), code)
+		return c
+	}
+	_, _, sdb := ethtest.NewEmptyStateDB(b)
+	in := newCheckpointTest().newInterpreter(sdb, vm.Config{ExperimentalCheckpointing: true})
+
+	b.Run("run", func(b *testing.B) {
+		for range b.N {
+			if _, err := in.Run(newContract(), nil, false); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("run_sliced_without_pause", func(b *testing.B) {
+		for range b.N {
+			_, cp, err := in.RunSliced(newContract(), nil, false, gas, nil)
+			if err != nil || cp != nil {
+				b.Fatalf("RunSliced() got (%v, %v); want (nil, nil)", cp, err)
+			}
+		}
+	})
+}
@@ -176,7 +176,7 @@ func activePrecompiles(rules params.Rules) []common.Address {
 // - the _remaining_ gas,
 // - any error that occurred
 func (args *evmCallArgs) RunPrecompiledContract(p PrecompiledContract, input []byte, suppliedGas uint64) (ret []byte, remainingGas uint64, err error) {
-	gasCost := p.RequiredGas(input)
+	gasCost := args.requiredGas(p, input) // libevm
 	if suppliedGas < gasCost {
 		return nil, 0, ErrOutOfGas
 	}
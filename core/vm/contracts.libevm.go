@@ -19,6 +19,7 @@ package vm
 import (
 	"fmt"
 	"math/big"
+	"slices"
 
 	"github.com/holiman/uint256"
 	"golang.org/x/exp/slog"
@@ -42,7 +43,9 @@ type P256Verify struct {
 // ActivePrecompiles returns the precompiles enabled with the current configuration.
 func ActivePrecompiles(rules params.Rules) []common.Address {
 	orig := activePrecompiles(rules) // original, upstream implementation
-	active := rules.Hooks().ActivePrecompiles(append([]common.Address{}, orig...))
+	scheduled := appendScheduledPrecompiles(&rules, append([]common.Address{}, orig...))
+	custom := appendUnique(scheduled, rules.CustomPrecompiles()...)
+	active := rules.Hooks().ActivePrecompiles(custom)
 
 	// As all set computation is done lazily and only when debugging, there is
 	// some duplication in favour of simplified code.
@@ -65,6 +68,33 @@ func ActivePrecompiles(rules params.Rules) []common.Address {
 	return active
 }
 
+// CustomPrecompiles returns the addresses returned by [ActivePrecompiles] that
+// aren't also active in the upstream, geth implementation under the same
+// rules, in the same order. These include those of the [PrecompileSchedule]
+// and the [params.CustomPrecompiler], and any added by the [params.RulesHooks]
+// ActivePrecompiles hook, which MUST NOT call CustomPrecompiles.
+func CustomPrecompiles(rules params.Rules) []common.Address {
+	upstream := set.From(activePrecompiles(rules)...)
+	var custom []common.Address
+	for _, a := range ActivePrecompiles(rules) {
+		if _, ok := upstream[a]; !ok {
+			custom = append(custom, a)
+		}
+	}
+	return custom
+}
+
+// appendUnique returns `to` with every element of `addrs` that it doesn't
+// already contain appended.
+func appendUnique(to []common.Address, addrs ...common.Address) []common.Address {
+	for _, a := range addrs {
+		if !slices.Contains(to, a) {
+			to = append(to, a)
+		}
+	}
+	return to
+}
+
 // evmCallArgs mirrors the parameters of the [EVM] methods Call(), CallCode(),
 // DelegateCall() and StaticCall(). Its fields are identical to those of the
 // parameters, prepended with the receiver name and call type. As
@@ -131,14 +161,45 @@ func (t CallType) OpCode() OpCode {
 	return INVALID
 }
 
+// requiredGas returns the gas to charge before running the
+// [PrecompiledContract], as determined by [params.RulesHooks.PrecompileGas].
+func (args *evmCallArgs) requiredGas(p PrecompiledContract, input []byte) uint64 {
+	gas := p.RequiredGas(input)
+	if args.evm == nil { // only in upstream geth tests
+		return gas
+	}
+	return args.evm.chainRules.Hooks().PrecompileGas(args.addr, input, gas)
+}
+
 // run runs the [PrecompiledContract], differentiating between stateful and
-// regular types, updating `args.gasRemaining` in the stateful case.
+// regular types, updating `args.gasRemaining` in the stateful case. Per-block
+// invocation limits, if any, are enforced before running the contract.
 func (args *evmCallArgs) run(p PrecompiledContract, input []byte) (ret []byte, err error) {
+	if lp, ok := p.(*limitedPrecompile); ok {
+		if err := args.evm.consumePrecompileInvocation(args.addr, lp.limit); err != nil {
+			return nil, err
+		}
+		p = lp.PrecompiledContract
+	}
+
+	if _, ok := p.(*kzgPointEvaluation); ok {
+		if v := args.evm.pointEvaluationVerifier(); v != nil {
+			return runPointEvaluation(v, input)
+		}
+	}
+
 	sp, ok := p.(statefulPrecompile)
 	if !ok {
 		return p.Run(input)
 	}
+	if g := args.evm.Config.DeterminismGuard; g != nil {
+		return g.run(args, sp, input)
+	}
+	return args.runStateful(sp, input)
+}
 
+// runStateful runs the stateful precompile, updating `args.gasRemaining`.
+func (args *evmCallArgs) runStateful(sp statefulPrecompile, input []byte) ([]byte, error) {
 	env := args.env()
 	// Depth and read-only setting are handled by [EVMInterpreter.Run],
 	// which isn't used for precompiles, so we need to do it ourselves to
@@ -153,7 +214,7 @@ func (args *evmCallArgs) run(p PrecompiledContract, input []byte) (ret []byte, e
 		defer func() { in.readOnly = false }()
 	}
 
-	ret, err = sp(env, input)
+	ret, err := sp(env, input)
 	args.gasRemaining = env.Gas()
 	return ret, err
 }
@@ -213,10 +274,45 @@ type PrecompileEnvironment interface {
 	BlockHeader() (types.Header, error)
 	BlockNumber() *big.Int
 	BlockTime() uint64
+	// Clock returns a source of wall-clock time, which MUST NOT affect
+	// consensus. If a [DeterminismGuard] is configured then all reads are
+	// reported as violations.
+	Clock() Clock
+	// BlockOracle returns the payload passed to [BlockContext.SetBlockOracle]
+	// and true, or false if no payload was set. See the method's
+	// documentation regarding consensus.
+	BlockOracle() (payload any, ok bool)
+	// BlobHashes and BlobBaseFee return copies of the respective
+	// [TxContext] and [BlockContext] fields, as used by the BLOBHASH and
+	// BLOBBASEFEE opcodes. BlobBaseFee returns nil if the field is unset.
+	BlobHashes() []common.Hash
+	BlobBaseFee() *big.Int
 
 	// Invalidate invalidates the transaction calling this precompile.
 	InvalidateExecution(error)
 
+	// Scratch returns a [Scratchpad] scoped to the current call frame, for
+	// intermediate values that MUST NOT be written to state.
+	Scratch() *Scratchpad
+
+	// TransientState and SetTransientState are equivalent to the TLOAD and
+	// TSTORE opcodes, respectively, but MAY access any address. They are
+	// available regardless of whether EIP-1153 is active. SetTransientState
+	// returns [ErrWriteProtection] if ReadOnly() is true.
+	TransientState(addr common.Address, key common.Hash) common.Hash
+	SetTransientState(addr common.Address, key, value common.Hash) error
+	// ReadStorage returns the value of any account's storage slot, charging
+	// gas as the SLOAD opcode would under the active rules; i.e. with cold and
+	// warm access costs, and warming the slot, post-Berlin. It is available
+	// regardless of ReadOnly() and returns [ErrOutOfGas] if the remaining gas
+	// is insufficient, in which case the slot isn't read nor warmed.
+	ReadStorage(addr common.Address, key common.Hash) (common.Hash, error)
+	// AddRefund and SubRefund modify the gas-refund counter of the
+	// transaction. Both return [ErrWriteProtection] if ReadOnly() is true, and
+	// SubRefund returns [ErrRefundUnderflow] if `gas` exceeds the counter.
+	AddRefund(gas uint64) error
+	SubRefund(gas uint64) error
+
 	// Call is equivalent to [EVM.Call] except that the `caller` argument is
 	// removed and automatically determined according to the type of call that
 	// invoked the precompile.
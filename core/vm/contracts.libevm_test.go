@@ -17,12 +17,14 @@ package vm_test
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"math/big"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
@@ -33,6 +35,7 @@ import (
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/state"
 	"github.com/ava-labs/libevm/core/types"
 	"github.com/ava-labs/libevm/core/vm"
 	"github.com/ava-labs/libevm/crypto"
@@ -324,6 +327,219 @@ func TestNewStatefulPrecompile(t *testing.T) {
 	}
 }
 
+func TestBlockOracle(t *testing.T) {
+	type payload struct{ price uint64 }
+
+	precompile := common.HexToAddress("60C0DE") // GO CODE
+	var (
+		got   any
+		gotOK bool
+	)
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				got, gotOK = env.BlockOracle()
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	tests := []struct {
+		name   string
+		set    bool
+		oracle any
+	}{
+		{
+			name: "not_set",
+		},
+		{
+			name:   "set",
+			set:    true,
+			oracle: &payload{price: 42},
+		},
+		{
+			name: "set_nil",
+			set:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockCtx := core.NewEVMBlockContext(
+				&types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0)},
+				nil, &common.Address{},
+			)
+			if tt.set {
+				blockCtx.SetBlockOracle(tt.oracle)
+			}
+			_, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(blockCtx))
+
+			_, _, err := evm.Call(vm.AccountRef{}, precompile, nil, 1e6, uint256.NewInt(0))
+			require.NoError(t, err, "%T.Call([precompile])", evm)
+			assert.Equal(t, tt.set, gotOK, "%T.BlockOracle() ok", (vm.PrecompileEnvironment)(nil))
+			assert.Equal(t, tt.oracle, got, "%T.BlockOracle() payload", (vm.PrecompileEnvironment)(nil))
+		})
+	}
+}
+
+func TestBlobEnvironment(t *testing.T) {
+	precompile := common.HexToAddress("60C0DE")
+	var (
+		gotHashes []common.Hash
+		gotFee    *big.Int
+	)
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				gotHashes = env.BlobHashes()
+				gotFee = env.BlobBaseFee()
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	blockCtx := core.NewEVMBlockContext(
+		&types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0)},
+		nil, &common.Address{},
+	)
+	blockCtx.BlobBaseFee = big.NewInt(314159)
+	_, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(blockCtx))
+	hashes := []common.Hash{{1}, {2}}
+	evm.TxContext.BlobHashes = hashes
+
+	_, _, err := evm.Call(vm.AccountRef{}, precompile, nil, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "%T.Call([precompile])", evm)
+	assert.Equal(t, hashes, gotHashes, "BlobHashes()")
+	assert.Equal(t, blockCtx.BlobBaseFee, gotFee, "BlobBaseFee()")
+
+	gotHashes[0] = common.Hash{}
+	gotFee.SetUint64(0)
+	assert.Equal(t, common.Hash{1}, evm.TxContext.BlobHashes[0], "BlobHashes() returns a copy")
+	assert.Equal(t, int64(314159), evm.Context.BlobBaseFee.Int64(), "BlobBaseFee() returns a copy")
+}
+
+type pointEvaluationHooks struct {
+	vm.NOOPHooks
+	verifier vm.PointEvaluationVerifier
+	gotRules []params.Rules
+}
+
+func (h *pointEvaluationHooks) PointEvaluationVerifier(r params.Rules) vm.PointEvaluationVerifier {
+	h.gotRules = append(h.gotRules, r)
+	return h.verifier
+}
+
+type pointEvaluationVerifier struct {
+	err error
+	got []vm.PointEvaluationInput
+}
+
+func (v *pointEvaluationVerifier) VerifyPointEvaluation(in vm.PointEvaluationInput) error {
+	v.got = append(v.got, in)
+	return v.err
+}
+
+func TestPointEvaluationVerifier(t *testing.T) {
+	// 0x0a; see EIP-4844
+	pointEval := common.BytesToAddress([]byte{10})
+	input := make([]byte, 192)
+	_, err := rand.Read(input)
+	require.NoError(t, err, "rand.Read()")
+
+	var want vm.PointEvaluationInput
+	copy(want.VersionedHash[:], input)
+	copy(want.Point[:], input[32:])
+	copy(want.Claim[:], input[64:])
+	copy(want.Commitment[:], input[96:])
+	copy(want.Proof[:], input[144:])
+
+	errRejected := errors.New("rejected")
+	// Random input fails the default KZG version check.
+	errDefault := errors.New("mismatched versioned hash")
+
+	tests := []struct {
+		name     string
+		verifier *pointEvaluationVerifier
+		wantErr  error
+	}{
+		{
+			name:    "default",
+			wantErr: errDefault,
+		},
+		{
+			name:     "accepted",
+			verifier: &pointEvaluationVerifier{},
+		},
+		{
+			name:     "rejected",
+			verifier: &pointEvaluationVerifier{err: errRejected},
+			wantErr:  errRejected,
+		},
+	}
+
+	registrations := []struct {
+		name     string
+		register func(*pointEvaluationHooks)
+	}{
+		{
+			name:     "single",
+			register: func(h *pointEvaluationHooks) { vm.RegisterHooks(h) },
+		},
+		{
+			name: "composed",
+			register: func(h *pointEvaluationHooks) {
+				// The first set implements [vm.PointEvaluationHooks] but
+				// returns a nil verifier, which MUST be skipped.
+				vm.RegisterHooks(&pointEvaluationHooks{}, vm.NOOPHooks{}, h)
+			},
+		},
+	}
+
+	for _, reg := range registrations {
+		for _, tt := range tests {
+			t.Run(reg.name+"/"+tt.name, func(t *testing.T) {
+				if tt.verifier != nil {
+					tt.verifier.got = nil
+				}
+				h := &pointEvaluationHooks{}
+				if tt.verifier != nil {
+					h.verifier = tt.verifier
+				}
+				vm.TestOnlyClearRegisteredHooks()
+				reg.register(h)
+				t.Cleanup(vm.TestOnlyClearRegisteredHooks)
+
+				blockCtx := core.NewEVMBlockContext(
+					&types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0)},
+					nil, &common.Address{},
+				)
+				_, evm := ethtest.NewZeroEVM(t,
+					ethtest.WithBlockContext(blockCtx),
+					ethtest.WithChainConfig(params.MergedTestChainConfig),
+				)
+
+				const gas uint64 = 1e6
+				got, gasLeft, err := evm.Call(vm.AccountRef{}, pointEval, input, gas, uint256.NewInt(0))
+				if tt.wantErr != nil {
+					require.ErrorContains(t, err, tt.wantErr.Error(), "%T.Call([point evaluation])", evm)
+				} else {
+					require.NoError(t, err, "%T.Call([point evaluation])", evm)
+					assert.Equal(t, gas-params.BlobTxPointEvaluationPrecompileGas, gasLeft, "gas left")
+					assert.Len(t, got, 64, "output")
+				}
+
+				require.Len(t, h.gotRules, 1, "calls to PointEvaluationVerifier()")
+				assert.True(t, h.gotRules[0].IsCancun, "PointEvaluationVerifier() receives chain rules")
+				if tt.verifier != nil {
+					assert.Equal(t, []vm.PointEvaluationInput{want}, tt.verifier.got, "VerifyPointEvaluation() input")
+				}
+			})
+		}
+	}
+}
+
 func TestPrecompileInvalidatesExecution(t *testing.T) {
 	errIfInvalidated := errors.New("execution invalidated")
 	inputToInvalidate := []byte("invalidate")
@@ -642,6 +858,33 @@ func TestActivePrecompilesOverride(t *testing.T) {
 	require.Equal(t, precompiles, vm.ActivePrecompiles(newRules()), "vm.ActivePrecompiles() returns overridden addresses")
 }
 
+func TestCustomPrecompiles(t *testing.T) {
+	newRules := func() params.Rules {
+		return new(params.ChainConfig).Rules(big.NewInt(0), false, 0)
+	}
+	defaultActive := vm.ActivePrecompiles(newRules())
+	require.NotEmpty(t, defaultActive, "default active precompiles")
+
+	custom := []common.Address{{'c', 1}, {'c', 2}}
+	added := common.Address{'a'}
+	hooks := &hookstest.Stub{
+		CustomPrecompilesFn: func() []common.Address {
+			// Including an upstream precompile MUST NOT result in duplication.
+			return append(slices.Clone(custom), defaultActive[0])
+		},
+		ActivePrecompilesFn: func(active []common.Address) []common.Address {
+			assert.Equal(t, append(slices.Clone(defaultActive), custom...), active, "ActivePrecompiles() hook receives default and custom addresses")
+			return append(active, added)
+		},
+	}
+	hooks.Register(t)
+
+	rules := newRules()
+	assert.Equal(t, append(slices.Clone(custom), defaultActive[0]), rules.CustomPrecompiles(), "params.Rules.CustomPrecompiles()")
+	assert.Equal(t, append(append(slices.Clone(defaultActive), custom...), added), vm.ActivePrecompiles(rules), "vm.ActivePrecompiles()")
+	assert.Equal(t, append(slices.Clone(custom), added), vm.CustomPrecompiles(rules), "vm.CustomPrecompiles()")
+}
+
 func TestPrecompileMakeCall(t *testing.T) {
 	// There is one test per *CALL* op code:
 	//
@@ -878,3 +1121,404 @@ func TestPrecompileCallWithTracer(t *testing.T) {
 	require.NoErrorf(t, json.Unmarshal(gotJSON, &got), "json.Unmarshal(%T.GetResult(), %T)", tracer, &got)
 	require.Equal(t, value, got[contract].Storage[zeroHash], "value loaded with SLOAD")
 }
+
+func TestPrecompileScratchpad(t *testing.T) {
+	rng := ethtest.NewPseudoRand(31415)
+	precompile := rng.Address()
+	key := rng.Hash()
+	outerVal, innerVal := rng.Hash(), rng.Hash()
+
+	var (
+		beforeOuter, beforeInner common.Hash
+		innerHadKey              bool
+	)
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				s := env.Scratch()
+				if len(input) > 0 { // reentrant call
+					innerHadKey = s.Has(key)
+					beforeInner = s.Get(key)
+					s.Set(key, innerVal)
+					return nil, vm.ErrExecutionReverted
+				}
+
+				beforeOuter = s.Get(key)
+				s.Set(key, outerVal)
+				if _, err := env.Call(precompile, []byte{1}, env.Gas()/2, uint256.NewInt(0)); !errors.Is(err, vm.ErrExecutionReverted) {
+					return nil, fmt.Errorf("reentrant call: got err %v; want %v", err, vm.ErrExecutionReverted)
+				}
+				return s.Get(key).Bytes(), nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	state, evm := ethtest.NewZeroEVM(t)
+	for i := 0; i < 2; i++ {
+		got, _, err := evm.Call(vm.AccountRef(rng.Address()), precompile, nil, 1e6, uint256.NewInt(0))
+		require.NoErrorf(t, err, "evm.Call([precompile]) #%d", i)
+
+		assert.Equalf(t, outerVal, common.BytesToHash(got), "#%d outer value after reentrant call", i)
+		assert.Zerof(t, beforeOuter, "#%d outer value before Set()", i)
+		assert.Zerof(t, beforeInner, "#%d inner value before Set()", i)
+		assert.Falsef(t, innerHadKey, "#%d inner Has() before Set()", i)
+	}
+	assert.Zero(t, state.GetState(precompile, key), "state value at scratchpad key")
+}
+
+func TestPrecompileCallOptions(t *testing.T) {
+	var (
+		sut     = common.HexToAddress("7E57ED")
+		dest    = common.HexToAddress("DE57")
+		failing = common.HexToAddress("FA11")
+		funder  = common.HexToAddress("F0ADE12")
+	)
+	errFailing := errors.New("failing precompile")
+
+	// Configured by each test case, before calling the SUT.
+	var (
+		target common.Address
+		value  *uint256.Int
+		opts   []vm.CallOption
+	)
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			sut: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				return env.Call(target, nil, env.Gas(), value, opts...)
+			}),
+			dest: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				var readOnly byte
+				if env.ReadOnly() {
+					readOnly = 1
+				}
+				return binary.BigEndian.AppendUint64([]byte{readOnly}, env.Gas()), nil
+			}),
+			failing: vm.NewStatefulPrecompile(func(vm.PrecompileEnvironment, []byte) ([]byte, error) {
+				return nil, errFailing
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	const (
+		gasLimit = 1e6
+		gasCap   = 1e4
+		funds    = 100
+	)
+	tests := []struct {
+		name         string
+		target       common.Address
+		value        uint64
+		opts         []vm.CallOption
+		wantErr      error
+		wantReadOnly bool
+		wantGas      uint64 // received by `dest`
+		wantFunder   uint64
+		wantDest     uint64 // balance
+	}{
+		{
+			name:       "default",
+			target:     dest,
+			wantGas:    gasLimit,
+			wantFunder: funds,
+		},
+		{
+			name:       "gas_cap",
+			target:     dest,
+			opts:       []vm.CallOption{vm.WithGasCap(gasCap)},
+			wantGas:    gasCap,
+			wantFunder: funds,
+		},
+		{
+			name:         "static",
+			target:       dest,
+			opts:         []vm.CallOption{vm.WithStaticContext()},
+			wantReadOnly: true,
+			wantGas:      gasLimit,
+			wantFunder:   funds,
+		},
+		{
+			name:       "static_with_value",
+			target:     dest,
+			value:      1,
+			opts:       []vm.CallOption{vm.WithStaticContext()},
+			wantErr:    vm.ErrWriteProtection,
+			wantFunder: funds,
+		},
+		{
+			name:       "value_from_funder",
+			target:     dest,
+			value:      10,
+			opts:       []vm.CallOption{vm.WithValueTransferFrom(funder)},
+			wantGas:    gasLimit,
+			wantFunder: funds - 10,
+			wantDest:   10,
+		},
+		{
+			name:       "value_from_funder_insufficient",
+			target:     dest,
+			value:      funds + 1,
+			opts:       []vm.CallOption{vm.WithValueTransferFrom(funder)},
+			wantErr:    vm.ErrInsufficientBalance,
+			wantFunder: funds,
+		},
+		{
+			name:       "value_from_funder_reverted",
+			target:     failing,
+			value:      10,
+			opts:       []vm.CallOption{vm.WithValueTransferFrom(funder)},
+			wantErr:    errFailing,
+			wantFunder: funds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, evm := ethtest.NewZeroEVM(t)
+			state.SetBalance(funder, uint256.NewInt(funds))
+			target = tt.target
+			value = uint256.NewInt(tt.value)
+			opts = tt.opts
+
+			got, _, err := evm.Call(vm.AccountRef{}, sut, nil, gasLimit, uint256.NewInt(0))
+			require.ErrorIs(t, err, tt.wantErr, "%T.Call()", evm)
+			if tt.wantErr == nil {
+				require.Len(t, got, 9, "return data")
+				assert.Equal(t, tt.wantReadOnly, got[0] == 1, "called contract ReadOnly()")
+				assert.Equal(t, tt.wantGas, binary.BigEndian.Uint64(got[1:]), "called contract Gas()")
+			}
+			assert.Equal(t, uint256.NewInt(tt.wantFunder), state.GetBalance(funder), "funder balance")
+			assert.Equal(t, uint256.NewInt(tt.wantDest), state.GetBalance(tt.target), "called contract balance")
+			assert.True(t, state.GetBalance(sut).IsZero(), "SUT balance")
+		})
+	}
+}
+
+func TestPrecompileTransientStateAndRefunds(t *testing.T) {
+	var (
+		sut   = common.HexToAddress("7E57ED")
+		other = common.HexToAddress("07E12")
+		key   = common.Hash{'k', 'e', 'y'}
+		val   = common.Hash{'v', 'a', 'l'}
+	)
+	const (
+		add = 100
+		sub = 30
+	)
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			sut: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				if err := env.SetTransientState(other, key, val); err != nil {
+					return nil, err
+				}
+				if err := env.AddRefund(add); err != nil {
+					return nil, err
+				}
+				toSub := uint64(sub)
+				if len(input) > 0 { // underflow
+					toSub = add + 1
+				}
+				if err := env.SubRefund(toSub); err != nil {
+					return nil, err
+				}
+				got := env.TransientState(other, key)
+				return got[:], nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	t.Run("read_write", func(t *testing.T) {
+		state, evm := ethtest.NewZeroEVM(t)
+		got, _, err := evm.Call(vm.AccountRef{}, sut, nil, 1e6, uint256.NewInt(0))
+		require.NoError(t, err, "%T.Call()", evm)
+		assert.Equal(t, val[:], got, "TransientState() after SetTransientState()")
+		assert.Equal(t, val, state.GetTransientState(other, key), "transient state after call")
+		assert.Equal(t, uint64(add-sub), state.GetRefund(), "refund after AddRefund() and SubRefund()")
+	})
+
+	t.Run("refund_underflow", func(t *testing.T) {
+		state, evm := ethtest.NewZeroEVM(t)
+		_, _, err := evm.Call(vm.AccountRef{}, sut, []byte{1}, 1e6, uint256.NewInt(0))
+		require.ErrorIs(t, err, vm.ErrRefundUnderflow, "%T.Call()", evm)
+		assert.Zero(t, state.GetRefund(), "refund reverted")
+	})
+
+	t.Run("read_only", func(t *testing.T) {
+		_, evm := ethtest.NewZeroEVM(t)
+		_, _, err := evm.StaticCall(vm.AccountRef{}, sut, nil, 1e6)
+		require.ErrorIs(t, err, vm.ErrWriteProtection, "%T.StaticCall()", evm)
+	})
+}
+
+func TestPrecompileReadStorage(t *testing.T) {
+	var (
+		sut    = common.HexToAddress("7E57ED")
+		oracle = common.HexToAddress("0AC1E")
+		key    = common.Hash{'k', 'e', 'y'}
+		val    = common.Hash{'v', 'a', 'l'}
+	)
+
+	// The precompile reads the slot twice and returns the value, followed by
+	// the gas charged for each read.
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			sut: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				out := make([]byte, 0, common.HashLength+16)
+				for i := range 2 {
+					before := env.Gas()
+					got, err := env.ReadStorage(oracle, key)
+					if err != nil {
+						return nil, err
+					}
+					if i == 0 {
+						out = append(out, got[:]...)
+					}
+					out = binary.BigEndian.AppendUint64(out, before-env.Gas())
+				}
+				return out, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	tests := []struct {
+		name           string
+		config         *params.ChainConfig
+		wantFirstRead  uint64
+		wantSecondRead uint64
+		wantAccessList bool
+	}{
+		{
+			name:           "frontier",
+			config:         &params.ChainConfig{},
+			wantFirstRead:  params.SloadGasFrontier,
+			wantSecondRead: params.SloadGasFrontier,
+		},
+		{
+			name:           "berlin",
+			config:         params.TestChainConfig,
+			wantFirstRead:  params.ColdSloadCostEIP2929,
+			wantSecondRead: params.WarmStorageReadCostEIP2929,
+			wantAccessList: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newEVM := func(t *testing.T) (*state.StateDB, *vm.EVM) {
+				t.Helper()
+				sdb, evm := ethtest.NewZeroEVM(t,
+					ethtest.WithChainConfig(tt.config),
+					ethtest.WithBlockContext(vm.BlockContext{BlockNumber: big.NewInt(0)}),
+				)
+				sdb.SetState(oracle, key, val)
+				return sdb, evm
+			}
+
+			sdb, evm := newEVM(t)
+			got, _, err := evm.StaticCall(vm.AccountRef{}, sut, nil, 1e6)
+			require.NoError(t, err, "%T.StaticCall()", evm)
+			require.Len(t, got, common.HashLength+16)
+			assert.Equal(t, val, common.BytesToHash(got[:common.HashLength]), "ReadStorage()")
+			assert.Equal(t, tt.wantFirstRead, binary.BigEndian.Uint64(got[common.HashLength:]), "gas charged for first read")
+			assert.Equal(t, tt.wantSecondRead, binary.BigEndian.Uint64(got[common.HashLength+8:]), "gas charged for second read")
+			_, slotWarm := sdb.SlotInAccessList(oracle, key)
+			assert.Equal(t, tt.wantAccessList, slotWarm, "slot in access list")
+
+			t.Run("out_of_gas", func(t *testing.T) {
+				_, evm := newEVM(t)
+				_, _, err := evm.StaticCall(vm.AccountRef{}, sut, nil, tt.wantFirstRead-1)
+				require.ErrorIs(t, err, vm.ErrOutOfGas, "%T.StaticCall()", evm)
+			})
+		})
+	}
+}
+
+func TestPrecompileGasHook(t *testing.T) {
+	var (
+		identity = common.BytesToAddress([]byte{4})
+		stateful = common.HexToAddress("5E1FC4A26E")
+	)
+	const (
+		perByte    = 100
+		selfCharge = 1_000
+	)
+
+	var gotDefault map[common.Address]uint64
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			stateful: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				if !env.UseGas(selfCharge) {
+					return nil, vm.ErrOutOfGas
+				}
+				return input, nil
+			}),
+		},
+		PrecompileGasFn: func(addr common.Address, input []byte, defaultGas uint64) uint64 {
+			gotDefault[addr] = defaultGas
+			return defaultGas + perByte*uint64(len(input))
+		},
+	}
+	hooks.Register(t)
+
+	input := []byte("hello, world")
+	identityGas := params.IdentityBaseGas + params.IdentityPerWordGas*uint64((len(input)+31)/32)
+
+	tests := []struct {
+		name    string
+		addr    common.Address
+		gas     uint64
+		wantErr error
+		// wantUsed is the total gas consumed, which is only checked on success.
+		wantUsed    uint64
+		wantDefault uint64
+	}{
+		{
+			name:        "stateless_repriced",
+			addr:        identity,
+			gas:         1e6,
+			wantUsed:    identityGas + perByte*uint64(len(input)),
+			wantDefault: identityGas,
+		},
+		{
+			name:        "stateless_insufficient_after_repricing",
+			addr:        identity,
+			gas:         identityGas,
+			wantErr:     vm.ErrOutOfGas,
+			wantDefault: identityGas,
+		},
+		{
+			name:        "stateful_charges_hook_and_self",
+			addr:        stateful,
+			gas:         1e6,
+			wantUsed:    perByte*uint64(len(input)) + selfCharge,
+			wantDefault: 0,
+		},
+		{
+			name:        "stateful_self_charge_after_hook",
+			addr:        stateful,
+			gas:         perByte * uint64(len(input)),
+			wantErr:     vm.ErrOutOfGas,
+			wantDefault: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDefault = make(map[common.Address]uint64)
+			_, evm := ethtest.NewZeroEVM(t)
+
+			got, gasLeft, err := evm.Call(vm.AccountRef{}, tt.addr, input, tt.gas, uint256.NewInt(0))
+			require.ErrorIsf(t, err, tt.wantErr, "%T.Call()", evm)
+			assert.Equal(t, map[common.Address]uint64{tt.addr: tt.wantDefault}, gotDefault, "default gas passed to hook")
+			if tt.wantErr != nil {
+				return
+			}
+			assert.Equal(t, input, got, "return data")
+			assert.Equal(t, tt.wantUsed, tt.gas-gasLeft, "gas used")
+		})
+	}
+}
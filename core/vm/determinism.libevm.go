@@ -0,0 +1,219 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/log"
+)
+
+// A Clock is a source of wall-clock time. Precompiles and hooks that require
+// the time for purposes other than consensus (e.g. metrics or timeouts) SHOULD
+// use [PrecompileEnvironment.Clock] instead of [time.Now], allowing reads to
+// be detected by a [DeterminismGuard].
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// A DeterminismGuard detects common sources of nondeterminism in stateful
+// precompiles, which would otherwise only be discovered once they resulted in
+// a consensus failure. It is enabled by setting [Config.DeterminismGuard] and
+// is intended for use in debugging and CI, NOT in production.
+//
+// When enabled, every stateful precompile is executed multiple times with the
+// same input, reverting state between executions, and any difference in
+// returned data, error, or remaining gas is reported as a
+// [DivergentOutput]. As Go randomises map iteration order, this is also likely
+// (but not guaranteed) to detect outputs that depend on map iteration; more
+// executions increase the likelihood. All reads of the [Clock] returned by
+// [PrecompileEnvironment.Clock] are reported as a [ClockRead].
+//
+// Only the state and return values of the final execution are retained. A
+// DeterminismGuard is safe for concurrent use but MUST NOT be copied after
+// first use.
+type DeterminismGuard struct {
+	// Runs is the number of times that each stateful precompile is executed;
+	// values less than 2 are treated as 2.
+	Runs int
+	// Clock, if non-nil, is the source of time returned by the guarded
+	// [Clock]; otherwise [time.Now] is used.
+	Clock Clock
+	// OnViolation, if non-nil, is called synchronously with every violation
+	// as it is detected; otherwise violations are logged at the error level.
+	// Violations are recorded regardless.
+	OnViolation func(*DeterminismViolation)
+
+	mu         sync.Mutex
+	violations []*DeterminismViolation
+}
+
+// A ViolationKind describes the nature of a [DeterminismViolation].
+type ViolationKind int
+
+// Kinds of [DeterminismViolation].
+const (
+	ClockRead ViolationKind = iota + 1
+	DivergentOutput
+)
+
+// String returns a human-readable representation of the kind.
+func (k ViolationKind) String() string {
+	switch k {
+	case ClockRead:
+		return "clock read"
+	case DivergentOutput:
+		return "divergent output"
+	default:
+		return fmt.Sprintf("ViolationKind(%d)", int(k))
+	}
+}
+
+// A DeterminismViolation is a potential source of nondeterminism detected by
+// a [DeterminismGuard].
+type DeterminismViolation struct {
+	Kind       ViolationKind
+	Precompile common.Address
+	Details    string
+}
+
+// Error implements the error interface.
+func (v *DeterminismViolation) Error() string {
+	msg := fmt.Sprintf("%v by precompile %v", v.Kind, v.Precompile)
+	if v.Details == "" {
+		return msg
+	}
+	return msg + ": " + v.Details
+}
+
+// Violations returns all violations detected so far, in the order in which
+// they were detected.
+func (g *DeterminismGuard) Violations() []*DeterminismViolation {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return slices.Clone(g.violations)
+}
+
+// Err returns all violations detected so far, joined with [errors.Join], or
+// nil if there are none. It is convenient for failing CI runs.
+func (g *DeterminismGuard) Err() error {
+	var errs []error
+	for _, v := range g.Violations() {
+		errs = append(errs, v)
+	}
+	return errors.Join(errs...)
+}
+
+func (g *DeterminismGuard) report(v *DeterminismViolation) {
+	g.mu.Lock()
+	g.violations = append(g.violations, v)
+	g.mu.Unlock()
+
+	if g.OnViolation != nil {
+		g.OnViolation(v)
+		return
+	}
+	log.Error("Nondeterminism detected", "kind", v.Kind, "precompile", v.Precompile, "details", v.Details)
+}
+
+func (g *DeterminismGuard) clock(precompile common.Address) Clock {
+	return guardedClock{g, precompile}
+}
+
+type guardedClock struct {
+	g          *DeterminismGuard
+	precompile common.Address
+}
+
+func (c guardedClock) Now() time.Time {
+	c.g.report(&DeterminismViolation{
+		Kind:       ClockRead,
+		Precompile: c.precompile,
+	})
+	if c.g.Clock != nil {
+		return c.g.Clock.Now()
+	}
+	return time.Now()
+}
+
+// run executes `sp` [DeterminismGuard.Runs] times, reverting state between
+// executions and reporting any divergence in outputs.
+func (g *DeterminismGuard) run(args *evmCallArgs, sp statefulPrecompile, input []byte) ([]byte, error) {
+	var (
+		db          = args.evm.StateDB
+		runs        = max(g.Runs, 2)
+		gas         = args.gasRemaining
+		snap        int
+		first, last precompileOutput
+	)
+	for i := range runs {
+		if i > 0 {
+			db.RevertToSnapshot(snap)
+			args.gasRemaining = gas
+		}
+		if i < runs-1 {
+			snap = db.Snapshot()
+		}
+		ret, err := args.runStateful(sp, slices.Clone(input))
+		last = precompileOutput{ret, err, args.gasRemaining}
+		if i == 0 {
+			first = precompileOutput{slices.Clone(ret), err, args.gasRemaining}
+			continue
+		}
+		if diff := first.diff(last); diff != "" {
+			g.report(&DeterminismViolation{
+				Kind:       DivergentOutput,
+				Precompile: args.addr,
+				Details:    fmt.Sprintf("execution %d of %d: %s", i+1, runs, diff),
+			})
+		}
+	}
+	return last.ret, last.err
+}
+
+type precompileOutput struct {
+	ret          []byte
+	err          error
+	gasRemaining uint64
+}
+
+// diff returns a description of the differences between `o` and `p`, or the
+// empty string if they are equivalent. Errors are compared by message.
+func (o precompileOutput) diff(p precompileOutput) string {
+	var diffs []string
+	if !bytes.Equal(o.ret, p.ret) {
+		diffs = append(diffs, fmt.Sprintf("returned data %#x != %#x", o.ret, p.ret))
+	}
+	if (o.err == nil) != (p.err == nil) || (o.err != nil && o.err.Error() != p.err.Error()) {
+		diffs = append(diffs, fmt.Sprintf("error %v != %v", o.err, p.err))
+	}
+	if o.gasRemaining != p.gasRemaining {
+		diffs = append(diffs, fmt.Sprintf("remaining gas %d != %d", o.gasRemaining, p.gasRemaining))
+	}
+	return strings.Join(diffs, "; ")
+}
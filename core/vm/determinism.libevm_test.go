@@ -0,0 +1,164 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestDeterminismGuard(t *testing.T) {
+	var (
+		deterministic = common.HexToAddress("0xd0")
+		counter       = common.HexToAddress("0xc0")
+		mapOrder      = common.HexToAddress("0xa0")
+		clockReader   = common.HexToAddress("0xb0")
+	)
+	slot := common.Hash{'s'}
+
+	var calls int
+	m := make(map[byte]struct{})
+	for i := range 64 {
+		m[byte(i)] = struct{}{}
+	}
+	now := time.Unix(42, 0)
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			deterministic: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				sdb := env.StateDB()
+				v := sdb.GetState(deterministic, slot)
+				v[31]++
+				sdb.SetState(deterministic, slot, v)
+				env.UseGas(uint64(len(input)))
+				return v[:], nil
+			}),
+			counter: vm.NewStatefulPrecompile(func(vm.PrecompileEnvironment, []byte) ([]byte, error) {
+				calls++
+				return []byte{byte(calls)}, nil
+			}),
+			mapOrder: vm.NewStatefulPrecompile(func(vm.PrecompileEnvironment, []byte) ([]byte, error) {
+				for k := range m {
+					return []byte{k}, nil
+				}
+				return nil, nil
+			}),
+			clockReader: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				return []byte{byte(env.Clock().Now().Unix())}, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	tests := []struct {
+		name      string
+		addr      common.Address
+		wantKinds []vm.ViolationKind
+		wantRet   []byte
+	}{
+		{
+			name:    "deterministic",
+			addr:    deterministic,
+			wantRet: common.Hash{31: 1}.Bytes(),
+		},
+		{
+			name:      "global_counter",
+			addr:      counter,
+			wantKinds: []vm.ViolationKind{vm.DivergentOutput, vm.DivergentOutput},
+		},
+		{
+			name:      "map_iteration",
+			addr:      mapOrder,
+			wantKinds: []vm.ViolationKind{vm.DivergentOutput},
+		},
+		{
+			name:      "clock",
+			addr:      clockReader,
+			wantKinds: []vm.ViolationKind{vm.ClockRead, vm.ClockRead, vm.ClockRead},
+			wantRet:   []byte{42},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reported []*vm.DeterminismViolation
+			guard := &vm.DeterminismGuard{
+				Runs:  3,
+				Clock: fixedClock(now),
+				OnViolation: func(v *vm.DeterminismViolation) {
+					reported = append(reported, v)
+				},
+			}
+			if tt.addr == mapOrder {
+				// Increase the likelihood of detection.
+				guard.Runs = 32
+			}
+			sdb, evm := ethtest.NewZeroEVM(t, ethtest.WithVMConfig(vm.Config{DeterminismGuard: guard}))
+
+			ret, _, err := evm.Call(vm.AccountRef{}, tt.addr, []byte{1, 2, 3}, 1e6, uint256.NewInt(0))
+			require.NoError(t, err, "Call()")
+			if tt.wantRet != nil {
+				assert.Equal(t, tt.wantRet, ret, "Call() returned data")
+			}
+			if tt.addr == deterministic {
+				assert.Equal(t, common.Hash{31: 1}, sdb.GetState(deterministic, slot), "state after reverting all but the last execution")
+			}
+
+			got := guard.Violations()
+			assert.Equal(t, got, reported, "violations passed to OnViolation()")
+			var kinds []vm.ViolationKind
+			for _, v := range got {
+				kinds = append(kinds, v.Kind)
+				assert.Equalf(t, tt.addr, v.Precompile, "%T.Precompile", v)
+			}
+			if tt.addr == mapOrder {
+				require.NotEmpty(t, kinds, "violations")
+				kinds = kinds[:1]
+			}
+			assert.Equal(t, tt.wantKinds, kinds, "kinds of violations")
+			if len(kinds) == 0 {
+				assert.NoErrorf(t, guard.Err(), "%T.Err()", guard)
+			} else {
+				assert.ErrorIsf(t, guard.Err(), got[0], "%T.Err()", guard)
+			}
+		})
+	}
+
+	t.Run("unguarded_clock", func(t *testing.T) {
+		_, evm := ethtest.NewZeroEVM(t)
+		before := byte(time.Now().Unix())
+		ret, _, err := evm.Call(vm.AccountRef{}, clockReader, nil, 1e6, uint256.NewInt(0))
+		after := byte(time.Now().Unix())
+		require.NoError(t, err, "Call()")
+		require.Len(t, ret, 1, "Call() returned data")
+		assert.Contains(t, []byte{before, after}, ret[0], "Clock().Now() without guard")
+	})
+}
@@ -17,14 +17,17 @@
 package vm
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
+	"slices"
 
 	"github.com/holiman/uint256"
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/common/math"
 	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm/internal/gascost"
 	"github.com/ava-labs/libevm/libevm"
 	"github.com/ava-labs/libevm/libevm/options"
 	"github.com/ava-labs/libevm/params"
@@ -38,6 +41,8 @@ type environment struct {
 	callType CallType
 
 	rawSelf, rawCaller common.Address
+
+	scratch Scratchpad
 }
 
 func (e *environment) Gas() uint64            { return e.self.Gas }
@@ -51,8 +56,79 @@ func (e *environment) IncomingCallType() CallType        { return e.callType }
 func (e *environment) BlockNumber() *big.Int             { return new(big.Int).Set(e.evm.Context.BlockNumber) }
 func (e *environment) BlockTime() uint64                 { return e.evm.Context.Time }
 
+func (e *environment) Clock() Clock {
+	if g := e.evm.Config.DeterminismGuard; g != nil {
+		return g.clock(e.rawSelf)
+	}
+	return systemClock{}
+}
+
+func (e *environment) BlockOracle() (any, bool) {
+	o := e.evm.Context.oracle
+	return o.payload, o.set
+}
+
+func (e *environment) BlobHashes() []common.Hash { return slices.Clone(e.evm.TxContext.BlobHashes) }
+
+func (e *environment) BlobBaseFee() *big.Int {
+	if f := e.evm.Context.BlobBaseFee; f != nil {
+		return new(big.Int).Set(f)
+	}
+	return nil
+}
+
 func (e *environment) InvalidateExecution(err error) { e.evm.InvalidateExecution(err) }
 
+func (e *environment) Scratch() *Scratchpad { return &e.scratch }
+
+func (e *environment) TransientState(addr common.Address, key common.Hash) common.Hash {
+	return e.evm.StateDB.GetTransientState(addr, key)
+}
+
+func (e *environment) SetTransientState(addr common.Address, key, value common.Hash) error {
+	if e.ReadOnly() {
+		return ErrWriteProtection
+	}
+	e.evm.StateDB.SetTransientState(addr, key, value)
+	return nil
+}
+
+func (e *environment) ReadStorage(addr common.Address, key common.Hash) (common.Hash, error) {
+	db := e.evm.StateDB
+	cost, cold := gascost.ReadStorage(e.evm.chainRules, db, addr, key)
+	if !e.UseGas(cost) {
+		return common.Hash{}, ErrOutOfGas
+	}
+	if cold {
+		db.AddSlotToAccessList(addr, key)
+	}
+	return db.GetState(addr, key), nil
+}
+
+// ErrRefundUnderflow is returned by [PrecompileEnvironment.SubRefund] if the
+// amount to subtract exceeds the transaction's gas-refund counter.
+var ErrRefundUnderflow = errors.New("gas refund counter below zero")
+
+func (e *environment) AddRefund(gas uint64) error {
+	if e.ReadOnly() {
+		return ErrWriteProtection
+	}
+	e.evm.StateDB.AddRefund(gas)
+	return nil
+}
+
+func (e *environment) SubRefund(gas uint64) error {
+	if e.ReadOnly() {
+		return ErrWriteProtection
+	}
+	// [StateDB.SubRefund] panics on underflow.
+	if gas > e.evm.StateDB.GetRefund() {
+		return ErrRefundUnderflow
+	}
+	e.evm.StateDB.SubRefund(gas)
+	return nil
+}
+
 func (e *environment) refundGas(add uint64) error {
 	gas, overflow := math.SafeAdd(e.self.Gas, add)
 	if overflow {
@@ -104,8 +180,10 @@ func (e *environment) Call(addr common.Address, input []byte, gas uint64, value
 }
 
 func (e *environment) callContract(typ CallType, addr common.Address, input []byte, gas uint64, value *uint256.Int, opts ...CallOption) (retData []byte, retErr error) {
+	cfg := options.As[callConfig](opts...)
+
 	var caller ContractRef = e.self
-	if options.As[callConfig](opts...).unsafeCallerAddressProxying {
+	if cfg.unsafeCallerAddressProxying {
 		// Note that, in addition to being unsafe, this breaks an EVM
 		// assumption that the caller ContractRef is always a *Contract.
 		caller = AccountRef(e.self.CallerAddress)
@@ -116,12 +194,33 @@ func (e *environment) callContract(typ CallType, addr common.Address, input []by
 		}
 	}
 
-	if e.ReadOnly() && value != nil && !value.IsZero() {
+	transfersValue := value != nil && !value.IsZero()
+	if transfersValue && (e.ReadOnly() || cfg.static) {
 		return nil, ErrWriteProtection
 	}
+	if cfg.static && typ == Call {
+		typ = StaticCall
+	}
+	if c := cfg.gasCap; c != nil {
+		gas = min(gas, *c)
+	}
+	if from := cfg.valueFrom; from != nil && transfersValue {
+		if !e.evm.Context.CanTransfer(e.evm.StateDB, *from, value) {
+			return nil, ErrInsufficientBalance
+		}
+	}
 	if !e.UseGas(gas) {
 		return nil, ErrOutOfGas
 	}
+	if from := cfg.valueFrom; from != nil && transfersValue {
+		snap := e.evm.StateDB.Snapshot()
+		e.evm.Context.Transfer(e.evm.StateDB, *from, caller.Address(), value)
+		defer func() {
+			if retErr != nil {
+				e.evm.StateDB.RevertToSnapshot(snap)
+			}
+		}()
+	}
 
 	if t := e.evm.Config.Tracer; t != nil {
 		var bigVal *big.Int
@@ -143,7 +242,13 @@ func (e *environment) callContract(typ CallType, addr common.Address, input []by
 			return nil, err
 		}
 		return ret, callErr
-	case CallCode, DelegateCall, StaticCall:
+	case StaticCall:
+		ret, returnGas, callErr := e.evm.StaticCall(caller, addr, input, gas)
+		if err := e.refundGas(returnGas); err != nil {
+			return nil, err
+		}
+		return ret, callErr
+	case CallCode, DelegateCall:
 		// TODO(arr4n): these cases should be very similar to CALL, hence the
 		// early abstraction, to signal to future maintainers. If implementing
 		// them, there's likely no need to honour the
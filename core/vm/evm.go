@@ -43,6 +43,9 @@ func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 		log.Debug("Overriding precompile", "address", addr, "implementation", log.TypeOf(p))
 		return p, p != nil
 	}
+	if p, ok := scheduledPrecompile(&evm.chainRules, addr); ok { // libevm
+		return p, true
+	}
 	var precompiles map[common.Address]PrecompiledContract
 	switch {
 	case evm.chainRules.IsCancun:
@@ -82,6 +85,9 @@ type BlockContext struct {
 	Random      *common.Hash   // Provides information for PREVRANDAO
 
 	Header *types.Header // libevm addition; not guaranteed to be set
+
+	oracle      blockOracle            // libevm addition; see [BlockContext.SetBlockOracle]
+	invocations *PrecompileInvocations // libevm addition; see [BlockContext.SetPrecompileInvocations]
 }
 
 // TxContext provides the EVM with information about a transaction.
@@ -130,7 +136,8 @@ type EVM struct {
 	callGasTemp uint64
 
 	// libevm
-	executionInvalidated error // see [EVM.InvalidateExecution]
+	executionInvalidated error           // see [EVM.InvalidateExecution]
+	reusableInterpreter  *EVMInterpreter // see [EVM.Release]
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -148,13 +155,16 @@ func NewEVM(blockCtx BlockContext, txCtx TxContext, statedb StateDB, chainConfig
 		}
 	}
 	blockCtx, txCtx, statedb, chainConfig, config = overrideNewEVMArgs(blockCtx, txCtx, statedb, chainConfig, config)
-	evm := &EVM{
+	evm := newEVM(config) // libevm: MAY be pooled; see [EVM.Release]
+	*evm = EVM{
 		Context:     blockCtx,
 		TxContext:   txCtx,
 		StateDB:     statedb,
 		Config:      config,
 		chainConfig: chainConfig,
 		chainRules:  chainConfig.Rules(blockCtx.BlockNumber, blockCtx.Random != nil, blockCtx.Time),
+
+		reusableInterpreter: evm.reusableInterpreter, // libevm
 	}
 	evm.interpreter = NewEVMInterpreter(evm)
 	return evm
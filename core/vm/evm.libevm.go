@@ -54,6 +54,12 @@ func (evm *EVM) canCreateContract(caller ContractRef, contractToCreate common.Ad
 	return gas, err
 }
 
+// maxInitCodeSize returns the EIP-3860 limit as determined by the
+// [params.RulesHooks].
+func (evm *EVM) maxInitCodeSize() uint64 {
+	return uint64(evm.chainRules.Hooks().MaxInitCodeSize()) //nolint:gosec // Lengths are non-negative
+}
+
 // Call executes the contract associated with the addr with the given input as
 // parameters. It also handles any necessary value transfer required and takes
 // the necessary steps to create accounts and reverses the state in case of an
@@ -80,7 +86,7 @@ func (evm *EVM) spendPreprocessingGas(gas uint64) (uint64, error) {
 	if internalCall := evm.depth > 0; internalCall || !libevmHooks.Registered() {
 		return gas, nil
 	}
-	c, err := libevmHooks.Get().PreprocessingGasCharge(evm.StateDB.TxHash())
+	c, err := libevmHooks.Get().PreprocessingGasCharge(evm.Context, evm.StateDB.TxHash())
 	if err != nil {
 		return gas, err
 	}
@@ -107,3 +113,29 @@ func (evm *EVM) InvalidateExecution(err error) {
 func (evm *EVM) ExecutionInvalidated() error {
 	return evm.executionInvalidated
 }
+
+// blockOracle carries the payload set by [BlockContext.SetBlockOracle].
+type blockOracle struct {
+	payload any
+	set     bool
+}
+
+// SetBlockOracle sets the payload returned by
+// [PrecompileEnvironment.BlockOracle] for all precompiles run with the
+// [BlockContext]. It allows the node to inject externally sourced, per-block
+// data (e.g. prices or bridge roots) that precompiles would otherwise have to
+// read from state.
+//
+// The payload is node-local and is NOT part of consensus. Unless the chain
+// commits to it by some other means (e.g. in a header field), precompiles
+// that alter their behaviour based on it risk diverging between nodes. The
+// payload SHOULD be treated as immutable once set.
+//
+// Block contexts constructed by [core.NewEVMBlockContext] have the payload set
+// by [core.RegisterBlockOracle], if any.
+func (c *BlockContext) SetBlockOracle(payload any) {
+	c.oracle = blockOracle{
+		payload: payload,
+		set:     true,
+	}
+}
@@ -16,6 +16,8 @@
 package vm
 
 import (
+	"errors"
+	"math"
 	"math/big"
 	"testing"
 
@@ -48,7 +50,7 @@ func (o *evmArgOverrider) OverrideEVMResetArgs(r params.Rules, _ *EVMResetArgs)
 	}
 }
 
-func (*evmArgOverrider) PreprocessingGasCharge(common.Hash) (uint64, error) {
+func (*evmArgOverrider) PreprocessingGasCharge(BlockContext, common.Hash) (uint64, error) {
 	return 0, nil
 }
 
@@ -113,3 +115,146 @@ func TestOverrideEVMResetArgs(t *testing.T) {
 	assert.Equalf(t, big.NewInt(chainID), hooks.gotResetChainID, "%T.ChainID passed to Reset() hook", params.Rules{})
 	assert.Equalf(t, big.NewInt(gasPrice), evm.GasPrice, "%T.GasPrice set by Reset() hook", evm)
 }
+
+// orderedHooks records the order in which its hooks are called, while also
+// charging a fixed preprocessing gas.
+type orderedHooks struct {
+	NOOPHooks
+	name   string
+	calls  *[]string
+	charge uint64
+	err    error
+}
+
+func (h orderedHooks) OverrideNewEVMArgs(args *NewEVMArgs) *NewEVMArgs {
+	*h.calls = append(*h.calls, h.name)
+	return args
+}
+
+func (h orderedHooks) OverrideEVMResetArgs(_ params.Rules, args *EVMResetArgs) *EVMResetArgs {
+	*h.calls = append(*h.calls, h.name)
+	return args
+}
+
+func (h orderedHooks) PreprocessingGasCharge(BlockContext, common.Hash) (uint64, error) {
+	return h.charge, h.err
+}
+
+func TestComposeHooks(t *testing.T) {
+	var calls []string
+	a := orderedHooks{name: "a", calls: &calls, charge: 1}
+	b := orderedHooks{name: "b", calls: &calls, charge: 2}
+
+	assert.Equal(t, Hooks(a), ComposeHooks(a), "ComposeHooks() of single set")
+
+	t.Run("registered", func(t *testing.T) {
+		calls = nil
+		TestOnlyClearRegisteredHooks()
+		RegisterHooks(a, b)
+		t.Cleanup(TestOnlyClearRegisteredHooks)
+
+		evm := NewEVM(BlockContext{}, TxContext{}, nil, &params.ChainConfig{}, Config{})
+		assert.Equal(t, []string{"a", "b"}, calls, "order of OverrideNewEVMArgs() calls")
+
+		calls = nil
+		evm.Reset(TxContext{}, nil)
+		assert.Equal(t, []string{"a", "b"}, calls, "order of OverrideEVMResetArgs() calls")
+	})
+
+	errA := errors.New("a")
+	tests := []struct {
+		name    string
+		hooks   []Hooks
+		want    uint64
+		wantErr error
+	}{
+		{
+			name:  "sum",
+			hooks: []Hooks{a, b},
+			want:  3,
+		},
+		{
+			name:    "error",
+			hooks:   []Hooks{b, orderedHooks{charge: 1, err: errA}, a},
+			wantErr: errA,
+		},
+		{
+			name:    "overflow",
+			hooks:   []Hooks{a, orderedHooks{charge: math.MaxUint64}},
+			wantErr: ErrGasUintOverflow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComposeHooks(tt.hooks[0], tt.hooks[1:]...).PreprocessingGasCharge(BlockContext{}, common.Hash{})
+			require.ErrorIs(t, err, tt.wantErr, "PreprocessingGasCharge()")
+			assert.Equal(t, tt.want, got, "PreprocessingGasCharge()")
+		})
+	}
+}
+
+type preprocessorStub struct {
+	charge uint64
+	err    error
+}
+
+func (p preprocessorStub) PreprocessingGasCharge(BlockContext, common.Hash) (uint64, error) {
+	return p.charge, p.err
+}
+
+func TestComposePreprocessors(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	tests := []struct {
+		name     string
+		ps       []Preprocessor
+		want     uint64
+		wantErrs []error
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "sum",
+			ps:   []Preprocessor{preprocessorStub{charge: 1}, preprocessorStub{charge: 2}, preprocessorStub{charge: 3}},
+			want: 6,
+		},
+		{
+			name: "all_errors_combined",
+			ps: []Preprocessor{
+				preprocessorStub{charge: 1, err: errA},
+				preprocessorStub{charge: 2},
+				preprocessorStub{err: errB},
+			},
+			wantErrs: []error{errA, errB},
+		},
+		{
+			name: "overflow",
+			ps: []Preprocessor{
+				preprocessorStub{charge: math.MaxUint64},
+				preprocessorStub{charge: 1},
+				preprocessorStub{err: errA},
+			},
+			wantErrs: []error{ErrGasUintOverflow, errA},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, sut := range []Preprocessor{
+				ComposePreprocessors(tt.ps...),
+				NewPreprocessorHooks(tt.ps...),
+			} {
+				got, err := sut.PreprocessingGasCharge(BlockContext{}, common.Hash{})
+				for _, want := range tt.wantErrs {
+					require.ErrorIsf(t, err, want, "%T.PreprocessingGasCharge()", sut)
+				}
+				if len(tt.wantErrs) == 0 {
+					require.NoErrorf(t, err, "%T.PreprocessingGasCharge()", sut)
+				}
+				assert.Equalf(t, tt.want, got, "%T.PreprocessingGasCharge()", sut)
+			}
+		})
+	}
+}
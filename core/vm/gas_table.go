@@ -310,7 +310,7 @@ func gasCreateEip3860(evm *EVM, contract *Contract, stack *Stack, mem *Memory, m
 		return 0, err
 	}
 	size, overflow := stack.Back(2).Uint64WithOverflow()
-	if overflow || size > params.MaxInitCodeSize {
+	if overflow || size > evm.maxInitCodeSize() { // libevm: configurable limit
 		return 0, ErrGasUintOverflow
 	}
 	// Since size <= params.MaxInitCodeSize, these multiplication cannot overflow
@@ -326,7 +326,7 @@ func gasCreate2Eip3860(evm *EVM, contract *Contract, stack *Stack, mem *Memory,
 		return 0, err
 	}
 	size, overflow := stack.Back(2).Uint64WithOverflow()
-	if overflow || size > params.MaxInitCodeSize {
+	if overflow || size > evm.maxInitCodeSize() { // libevm: configurable limit
 		return 0, ErrGasUintOverflow
 	}
 	// Since size <= params.MaxInitCodeSize, these multiplication cannot overflow
@@ -17,16 +17,138 @@
 package vm
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/math"
 	"github.com/ava-labs/libevm/libevm"
 	"github.com/ava-labs/libevm/libevm/register"
 	"github.com/ava-labs/libevm/params"
 )
 
 // RegisterHooks registers the Hooks. It is expected to be called in an `init()`
-// function and MUST NOT be called more than once.
-func RegisterHooks(h Hooks) {
-	libevmHooks.MustRegister(h)
+// function and MUST NOT be called more than once. If more than one set of Hooks
+// is provided then they are registered as if composed with [ComposeHooks],
+// allowing independent concerns to be implemented separately.
+func RegisterHooks(h Hooks, more ...Hooks) {
+	libevmHooks.MustRegister(ComposeHooks(h, more...))
+}
+
+// ComposeHooks returns Hooks that call every set of Hooks, in the order
+// received, such that:
+//
+//   - OverrideNewEVMArgs and OverrideEVMResetArgs receive the arguments
+//     returned by the previous set; and
+//   - PreprocessingGasCharge is equivalent to that of [ComposePreprocessors].
+//
+// The returned Hooks also implement every optional extension of [Hooks], such
+// as [PointEvaluationHooks], by calling those sets that implement it; see the
+// respective methods for details.
+//
+// If `more` is empty then `h` is returned unchanged.
+func ComposeHooks(h Hooks, more ...Hooks) Hooks {
+	if len(more) == 0 {
+		return h
+	}
+	return composedHooks(append([]Hooks{h}, more...))
+}
+
+type composedHooks []Hooks
+
+var _ Hooks = composedHooks(nil)
+
+func (c composedHooks) OverrideNewEVMArgs(args *NewEVMArgs) *NewEVMArgs {
+	for _, h := range c {
+		args = h.OverrideNewEVMArgs(args)
+	}
+	return args
+}
+
+func (c composedHooks) OverrideEVMResetArgs(r params.Rules, args *EVMResetArgs) *EVMResetArgs {
+	for _, h := range c {
+		args = h.OverrideEVMResetArgs(r, args)
+	}
+	return args
+}
+
+func (c composedHooks) PreprocessingGasCharge(block BlockContext, tx common.Hash) (uint64, error) {
+	return sumPreprocessingGas(c, block, tx)
+}
+
+var _ PointEvaluationHooks = composedHooks(nil)
+
+// PointEvaluationVerifier returns the first non-nil verifier returned by those
+// sets of Hooks that implement [PointEvaluationHooks], or nil if there is none.
+func (c composedHooks) PointEvaluationVerifier(r params.Rules) PointEvaluationVerifier {
+	for _, h := range c {
+		p, ok := h.(PointEvaluationHooks)
+		if !ok {
+			continue
+		}
+		if v := p.PointEvaluationVerifier(r); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// ComposePreprocessors returns a [Preprocessor] that charges the sum of the
+// charges of all `ps`, allowing independent sources of preprocessing (e.g. one
+// [Preprocessor] per family of precompiles) to be registered together via
+// [NewPreprocessorHooks]. If any `ps` returns an error, or if the sum
+// overflows, then the returned Preprocessor returns all such errors, combined
+// with [errors.Join].
+func ComposePreprocessors(ps ...Preprocessor) Preprocessor {
+	return composedPreprocessors(ps)
+}
+
+type composedPreprocessors []Preprocessor
+
+func (c composedPreprocessors) PreprocessingGasCharge(block BlockContext, tx common.Hash) (uint64, error) {
+	return sumPreprocessingGas(c, block, tx)
+}
+
+func sumPreprocessingGas[P Preprocessor](ps []P, block BlockContext, tx common.Hash) (uint64, error) {
+	var (
+		total uint64
+		errs  []error
+	)
+	for i, p := range ps {
+		g, err := p.PreprocessingGasCharge(block, tx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("preprocessor [%d] %T: %w", i, p, err))
+			continue
+		}
+		var overflow bool
+		total, overflow = math.SafeAdd(total, g)
+		if overflow {
+			errs = append(errs, fmt.Errorf("%w: preprocessing gas charge of preprocessor [%d] %T", ErrGasUintOverflow, i, p))
+		}
+	}
+	if len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+	return total, nil
+}
+
+// NewPreprocessorHooks returns [NOOPHooks] except for PreprocessingGasCharge,
+// which is that of [ComposePreprocessors] applied to all `ps`. Multiple
+// `parallel.Processor` instances, for example, can therefore be registered
+// with:
+//
+//	vm.RegisterHooks(vm.NewPreprocessorHooks(p0, p1))
+func NewPreprocessorHooks(ps ...Preprocessor) Hooks {
+	return preprocessorHooks{Preprocessor: ComposePreprocessors(ps...)}
+}
+
+type preprocessorHooks struct {
+	NOOPHooks
+	Preprocessor
+}
+
+func (h preprocessorHooks) PreprocessingGasCharge(block BlockContext, tx common.Hash) (uint64, error) {
+	return h.Preprocessor.PreprocessingGasCharge(block, tx)
 }
 
 // WithTempRegisteredHooks temporarily registers `h` as if calling
@@ -64,9 +186,11 @@ type Hooks interface {
 
 // A Preprocessor performs computation on a transaction before the
 // [EVMInterpreter] is invoked and reports its gas charge for spending at the
-// beginning of [EVM.Call] or [EVM.Create].
+// beginning of [EVM.Call] or [EVM.Create]. The [BlockContext] is that of the
+// block in which the transaction is being executed, allowing implementations
+// that handle multiple blocks concurrently to identify which one applies.
 type Preprocessor interface {
-	PreprocessingGasCharge(tx common.Hash) (uint64, error)
+	PreprocessingGasCharge(block BlockContext, tx common.Hash) (uint64, error)
 }
 
 // NewEVMArgs are the arguments received by [NewEVM], available for override
@@ -124,6 +248,6 @@ func (NOOPHooks) OverrideEVMResetArgs(_ params.Rules, a *EVMResetArgs) *EVMReset
 }
 
 // PreprocessingGasCharge returns (0, nil).
-func (NOOPHooks) PreprocessingGasCharge(common.Hash) (uint64, error) {
+func (NOOPHooks) PreprocessingGasCharge(BlockContext, common.Hash) (uint64, error) {
 	return 0, nil
 }
@@ -0,0 +1,48 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package gascost computes gas costs that are shared by the vm package and its
+// test doubles, without exporting them from the vm package itself.
+package gascost
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A SlotAccessList reports whether a storage slot is in the access list.
+type SlotAccessList interface {
+	SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool)
+}
+
+// ReadStorage returns the gas cost of an SLOAD of the slot under the given
+// rules, and whether the slot is cold, in which case the caller MUST add it to
+// the access list after charging the cost.
+func ReadStorage(rules params.Rules, db SlotAccessList, addr common.Address, key common.Hash) (cost uint64, cold bool) {
+	switch {
+	case rules.IsBerlin:
+		if _, warm := db.SlotInAccessList(addr, key); warm {
+			return params.WarmStorageReadCostEIP2929, false
+		}
+		return params.ColdSloadCostEIP2929, true
+	case rules.IsIstanbul:
+		return params.SloadGasEIP2200, false
+	case rules.IsEIP150:
+		return params.SloadGasEIP150, false
+	default:
+		return params.SloadGasFrontier, false
+	}
+}
@@ -29,6 +29,10 @@ type Config struct {
 	NoBaseFee               bool      // Forces the EIP-1559 baseFee to 0 (needed for 0 price calls)
 	EnablePreimageRecording bool      // Enables recording of SHA3/keccak preimages
 	ExtraEips               []int     // Additional EIPS that are to be enabled
+
+	ExperimentalCheckpointing bool              // libevm: enables [EVMInterpreter.RunSliced]
+	PoolAllocations           bool              // libevm: enables reuse of allocations; see [EVM.Release]
+	DeterminismGuard          *DeterminismGuard // libevm: debugging only; see [DeterminismGuard]
 }
 
 // ScopeContext contains the things that are per-call, such as stack and memory,
@@ -95,7 +99,7 @@ func NewEVMInterpreter(evm *EVM) *EVMInterpreter {
 		}
 	}
 	evm.Config.ExtraEips = extraEips
-	return &EVMInterpreter{evm: evm, table: table}
+	return evm.newInterpreter(table) // libevm: MAY be pooled; see [EVM.Release]
 }
 
 // Run loops and evaluates the contract's code with the given input data and returns
@@ -105,6 +109,10 @@ func NewEVMInterpreter(evm *EVM) *EVMInterpreter {
 // considered a revert-and-consume-all-gas operation except for
 // ErrExecutionReverted which means revert-and-keep-gas-left.
 func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (ret []byte, err error) {
+	return in.run(contract, input, readOnly, nil)
+}
+
+func (in *EVMInterpreter) run(contract *Contract, input []byte, readOnly bool, slice *gasSlice) (ret []byte, err error) {
 	// Increment the call depth which is restricted to 1024
 	in.evm.depth++
 	defer func() { in.evm.depth-- }()
@@ -127,7 +135,7 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 
 	var (
 		op          OpCode        // current opcode
-		mem         = NewMemory() // bound memory
+		mem         = in.memory() // bound memory; libevm: MAY be pooled
 		stack       = newstack()  // local stack
 		callContext = &ScopeContext{
 			Memory:   mem,
@@ -151,8 +159,10 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 	// they are returned to the pools
 	defer func() {
 		returnStack(stack)
+		ret = in.releaseMemory(mem, ret) // libevm
 	}()
 	contract.Input = input
+	slice.resume(&pc, callContext, in) // libevm: no-op if nil
 
 	if debug {
 		defer func() {
@@ -174,6 +184,9 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 			// Capture pre-execution values for tracing.
 			logged, pcCopy, gasCopy = false, pc, contract.Gas
 		}
+		if slice != nil && slice.maybePause(pc, callContext, in) { // libevm
+			return nil, errCheckpointed
+		}
 		// Get the operation from the jump table and validate the stack to ensure there are
 		// enough stack items available to perform the operation.
 		op = contract.GetOp(pc)
@@ -0,0 +1,59 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+// A BalanceChangeReason annotates a balance change reported to a
+// [BalanceChangeLogger]. Chains MAY define their own reasons in addition to
+// those defined by libevm.
+type BalanceChangeReason string
+
+// Reasons for balance changes made by transaction processing outside of EVM
+// execution.
+const (
+	BalanceChangeGasPurchase BalanceChangeReason = "gas_purchase"
+	BalanceChangeGasRefund   BalanceChangeReason = "gas_refund"
+	BalanceChangeTip         BalanceChangeReason = "tip"
+	BalanceChangeBaseFee     BalanceChangeReason = "base_fee"
+	BalanceChangeMint        BalanceChangeReason = "mint"
+)
+
+// A BalanceChangeLogger is an optional extension of an [EVMLogger], notified
+// of balance changes that are otherwise only observable as a difference
+// between pre- and post-transaction state; e.g. gas purchase and refund, and
+// fee distribution. Value transfers are not reported as they are already
+// visible via [EVMLogger.CaptureStart] and [EVMLogger.CaptureEnter].
+//
+// CaptureBalanceChange MAY be called before [EVMLogger.CaptureTxStart] and
+// after [EVMLogger.CaptureEnd]. Implementations MUST NOT modify `prev` nor
+// `curr`.
+type BalanceChangeLogger interface {
+	CaptureBalanceChange(addr common.Address, prev, curr *uint256.Int, reason BalanceChangeReason)
+}
+
+// CaptureBalanceChange calls [BalanceChangeLogger.CaptureBalanceChange] if `l`
+// implements the interface, and is otherwise a noop.
+func CaptureBalanceChange(l EVMLogger, addr common.Address, prev, curr *uint256.Int, reason BalanceChangeReason) {
+	if bl, ok := l.(BalanceChangeLogger); ok {
+		bl.CaptureBalanceChange(addr, prev, curr, reason)
+	}
+}
@@ -16,10 +16,16 @@
 
 package vm
 
-import "github.com/ava-labs/libevm/libevm/options"
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm/options"
+)
 
 type callConfig struct {
 	unsafeCallerAddressProxying bool
+	gasCap                      *uint64
+	static                      bool
+	valueFrom                   *common.Address
 }
 
 // A CallOption modifies the default behaviour of a contract call.
@@ -37,3 +43,35 @@ func WithUNSAFECallerAddressProxying() CallOption {
 		c.unsafeCallerAddressProxying = true
 	})
 }
+
+// WithGasCap limits the gas forwarded to the called contract to at most `gas`,
+// regardless of the amount requested. Only the forwarded amount is deducted
+// from the precompile's available gas.
+func WithGasCap(gas uint64) CallOption {
+	return options.Func[callConfig](func(c *callConfig) {
+		c.gasCap = &gas
+	})
+}
+
+// WithStaticContext results in the call being made as if by STATICCALL, such
+// that the called contract, and any that it calls, can't modify state. The
+// value transferred by such a call MUST be nil or zero.
+func WithStaticContext() CallOption {
+	return options.Func[callConfig](func(c *callConfig) {
+		c.static = true
+	})
+}
+
+// WithValueTransferFrom results in the value of the call being debited from
+// the specified account instead of from the caller of the contract. The value
+// is first transferred from the account to the caller, and the transfer is
+// reverted if the call fails.
+//
+// The precompile is responsible for determining that the account has
+// authorised the transfer; this option is therefore equivalent to modifying
+// balances via [PrecompileEnvironment.StateDB].
+func WithValueTransferFrom(addr common.Address) CallOption {
+	return options.Func[callConfig](func(c *callConfig) {
+		c.valueFrom = &addr
+	})
+}
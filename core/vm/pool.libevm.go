@@ -0,0 +1,106 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"slices"
+	"sync"
+)
+
+// Pools used iff [Config.PoolAllocations] is true.
+var (
+	evmPool = sync.Pool{
+		New: func() any { return new(EVM) },
+	}
+	memoryPool = sync.Pool{
+		New: func() any { return new(Memory) },
+	}
+)
+
+// maxPooledMemory is the maximum capacity of a [Memory] returned to the pool,
+// to avoid retaining the largest buffers indefinitely.
+const maxPooledMemory = 16 << 10
+
+// newEVM returns a pooled EVM if `c.PoolAllocations` is true, otherwise a new
+// one. All exported fields of the returned EVM MUST be overwritten.
+func newEVM(c Config) *EVM {
+	if !c.PoolAllocations {
+		return new(EVM)
+	}
+	return evmPool.Get().(*EVM) //nolint:forcetypeassert // invariant of the pool
+}
+
+// Release returns the EVM, and its interpreter, to a pool for reuse by
+// [NewEVM] if it was constructed with [Config.PoolAllocations] set, otherwise
+// it is a no-op. Neither the EVM nor its interpreter can be used after calling
+// Release, and the caller MUST NOT retain any references to them.
+//
+// Release MUST NOT be called while a call on the EVM is in progress. Values
+// derived from the EVM (e.g. return data, logs, and receipts) are unaffected
+// as they are never backed by pooled memory.
+func (evm *EVM) Release() {
+	if !evm.Config.PoolAllocations {
+		return
+	}
+	in := evm.interpreter
+	// Clearing references allows their garbage collection while pooled, and
+	// guarantees that nothing leaks between uses.
+	*evm = EVM{}
+	if in != nil {
+		*in = EVMInterpreter{hasher: in.hasher}
+		evm.reusableInterpreter = in
+	}
+	evmPool.Put(evm)
+}
+
+// newInterpreter returns an interpreter for the EVM, reusing that retained by
+// [EVM.Release] if possible.
+func (evm *EVM) newInterpreter(table *JumpTable) *EVMInterpreter {
+	in := evm.reusableInterpreter
+	if in == nil {
+		return &EVMInterpreter{evm: evm, table: table}
+	}
+	evm.reusableInterpreter = nil
+	in.evm = evm
+	in.table = table
+	return in
+}
+
+// memory returns a pooled [Memory] if [Config.PoolAllocations] is true,
+// otherwise a new one.
+func (in *EVMInterpreter) memory() *Memory {
+	if !in.evm.Config.PoolAllocations {
+		return NewMemory()
+	}
+	return memoryPool.Get().(*Memory) //nolint:forcetypeassert // invariant of the pool
+}
+
+// releaseMemory returns `m` to the pool if [Config.PoolAllocations] is true.
+// As the data returned by RETURN and REVERT is a slice of memory, `ret` is
+// copied before being returned, and the copy MUST be used in its place.
+func (in *EVMInterpreter) releaseMemory(m *Memory, ret []byte) []byte {
+	if !in.evm.Config.PoolAllocations {
+		return ret
+	}
+	ret = slices.Clone(ret)
+	if cap(m.store) <= maxPooledMemory {
+		m.store = m.store[:0]
+		m.lastGasCost = 0
+		memoryPool.Put(m)
+	}
+	return ret
+}
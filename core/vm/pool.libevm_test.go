@@ -0,0 +1,135 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/params"
+)
+
+// echoCode returns code that copies its calldata to memory before returning
+// it, optionally preceded by 32 bytes of memory that it never writes to.
+func echoCode(withUnwritten bool) []byte {
+	var dest byte
+	if withUnwritten {
+		dest = 32
+	}
+	return []byte{
+		byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0, byte(vm.PUSH1), dest, byte(vm.CALLDATACOPY),
+		byte(vm.CALLDATASIZE), byte(vm.PUSH1), dest, byte(vm.ADD), byte(vm.PUSH1), 0, byte(vm.RETURN),
+	}
+}
+
+func TestPoolAllocations(t *testing.T) {
+	echo := common.Address{'e', 'c', 'h', 'o'}
+	unwritten := common.Address{'u', 'n', 'w', 'r', 'i', 't', 't', 'e', 'n'}
+
+	const (
+		workers = 8
+		calls   = 100
+	)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var prev [][]byte
+			for i := range calls {
+				sdb, evm := ethtest.NewZeroEVM(t, ethtest.WithVMConfig(vm.Config{PoolAllocations: true}))
+				sdb.SetCode(echo, echoCode(false))
+				sdb.SetCode(unwritten, echoCode(true))
+
+				input := bytes.Repeat([]byte(fmt.Sprintf("%d:%d;", w, i)), 1+i%10)
+				got, _, err := evm.Call(vm.AccountRef{}, echo, input, 1e6, uint256.NewInt(0))
+				if !assert.NoError(t, err, "Call(echo)") {
+					return
+				}
+				assert.Equal(t, input, got, "Call(echo) return data")
+				prev = append(prev, got)
+
+				got, _, err = evm.Call(vm.AccountRef{}, unwritten, input, 1e6, uint256.NewInt(0))
+				if !assert.NoError(t, err, "Call(unwritten)") {
+					return
+				}
+				assert.Equal(t, append(make([]byte, 32), input...), got, "Call(unwritten) return data is zero-prefixed")
+
+				evm.Release()
+			}
+
+			for i, p := range prev {
+				want := bytes.Repeat([]byte(fmt.Sprintf("%d:%d;", w, i)), 1+i%10)
+				assert.Equalf(t, want, p, "return data of call %d after subsequent reuse of pooled memory", i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestReleaseWithoutPooling(t *testing.T) {
+	echo := common.Address{'e', 'c', 'h', 'o'}
+	sdb, evm := ethtest.NewZeroEVM(t)
+	sdb.SetCode(echo, echoCode(false))
+
+	evm.Release()
+	input := []byte("still usable")
+	got, _, err := evm.Call(vm.AccountRef{}, echo, input, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "Call() after Release() of non-pooled EVM")
+	require.Equal(t, input, got)
+}
+
+func BenchmarkPoolAllocations(b *testing.B) {
+	const txsPerBlock = 1000
+	echo := common.Address{'e', 'c', 'h', 'o'}
+	input := bytes.Repeat([]byte{1}, 1024)
+
+	for _, pool := range []bool{false, true} {
+		b.Run(fmt.Sprintf("pool=%t", pool), func(b *testing.B) {
+			sdb, _ := ethtest.NewZeroEVM(b)
+			sdb.SetCode(echo, echoCode(false))
+			blockCtx := vm.BlockContext{
+				CanTransfer: core.CanTransfer,
+				Transfer:    core.Transfer,
+			}
+
+			b.ReportAllocs()
+			for range b.N {
+				for range txsPerBlock {
+					// As with [core.ApplyTransaction], which constructs a new
+					// EVM for each transaction.
+					evm := vm.NewEVM(blockCtx, vm.TxContext{}, sdb, &params.ChainConfig{}, vm.Config{PoolAllocations: pool})
+					if _, _, err := evm.Call(vm.AccountRef{}, echo, input, 1e6, uint256.NewInt(0)); err != nil {
+						b.Fatal(err)
+					}
+					evm.Release()
+				}
+			}
+		})
+	}
+}
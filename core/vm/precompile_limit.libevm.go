@@ -0,0 +1,144 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm/options"
+)
+
+// ErrPrecompileInvocationLimit is returned, wrapped, by a precompile called
+// more times in a block than permitted by [WithInvocationLimit].
+var ErrPrecompileInvocationLimit = errors.New("precompile invocation limit reached")
+
+type registrationConfig struct {
+	invocationLimit *uint64
+}
+
+// A RegistrationOption modifies the behaviour of a precompile registered with
+// a [PrecompileRegistry].
+type RegistrationOption = options.Option[registrationConfig]
+
+// WithInvocationLimit limits the number of times that the precompile can be
+// called in a single block, after which all calls fail with
+// [ErrPrecompileInvocationLimit], consuming all gas. Invocations are counted
+// by address, regardless of the call type, and every invocation that doesn't
+// exceed the limit is counted, even if it or the transaction subsequently
+// reverts.
+//
+// Counts are tracked by the [PrecompileInvocations] of the [BlockContext]. As
+// the limit affects consensus, all EVM instances used to process the same
+// block MUST share the same counts, as they do if they are constructed from a
+// single BlockContext returned by [core.NewEVMBlockContext]; see
+// [BlockContext.SetPrecompileInvocations].
+func WithInvocationLimit(perBlock uint64) RegistrationOption {
+	return options.Func[registrationConfig](func(c *registrationConfig) {
+		c.invocationLimit = &perBlock
+	})
+}
+
+// limitedPrecompile wraps a precompile registered with [WithInvocationLimit].
+// It is unwrapped by [evmCallArgs.run] only once the invocation is counted.
+type limitedPrecompile struct {
+	PrecompiledContract
+	limit uint64
+}
+
+// PrecompileInvocations counts the number of times that each precompile has
+// been invoked in a block. It is safe for concurrent use.
+type PrecompileInvocations struct {
+	mu     sync.Mutex
+	counts map[common.Address]uint64
+}
+
+// NewPrecompileInvocations returns an empty [PrecompileInvocations].
+func NewPrecompileInvocations() *PrecompileInvocations {
+	return &PrecompileInvocations{
+		counts: make(map[common.Address]uint64),
+	}
+}
+
+// Count returns the number of counted invocations of the precompile at the
+// address. Only precompiles registered with [WithInvocationLimit] are counted.
+func (p *PrecompileInvocations) Count(addr common.Address) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.counts[addr]
+}
+
+// Copy returns a deep copy of the counts, which MAY be used to restore them if
+// a transaction is excluded from a block after being executed.
+func (p *PrecompileInvocations) Copy() *PrecompileInvocations {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return &PrecompileInvocations{
+		counts: maps.Clone(p.counts),
+	}
+}
+
+// Reset clears all counts, typically at a block boundary when reusing the
+// same [BlockContext].
+func (p *PrecompileInvocations) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clear(p.counts)
+}
+
+// consume counts an invocation of the precompile at the address unless doing
+// so would exceed the limit, in which case it returns an error.
+func (p *PrecompileInvocations) consume(addr common.Address, limit uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := p.counts[addr]
+	if n >= limit {
+		return fmt.Errorf("%w: %v limited to %d per block", ErrPrecompileInvocationLimit, addr, limit)
+	}
+	p.counts[addr] = n + 1
+	return nil
+}
+
+// SetPrecompileInvocations sets the counts used to enforce
+// [WithInvocationLimit], replacing those allocated by
+// [core.NewEVMBlockContext]. Counts are carried by pointer, so copies of the
+// BlockContext, including those held by every [EVM] constructed from it, share
+// them. A new [PrecompileInvocations], or a call to
+// [PrecompileInvocations.Reset], is required at every block boundary.
+//
+// If a BlockContext has no counts, all calls to limited precompiles fail.
+func (c *BlockContext) SetPrecompileInvocations(p *PrecompileInvocations) {
+	c.invocations = p
+}
+
+// PrecompileInvocations returns the counts used to enforce
+// [WithInvocationLimit], or nil if there are none.
+func (c *BlockContext) PrecompileInvocations() *PrecompileInvocations {
+	return c.invocations
+}
+
+// consumePrecompileInvocation is a convenience wrapper around
+// [PrecompileInvocations.consume].
+func (evm *EVM) consumePrecompileInvocation(addr common.Address, limit uint64) error {
+	if evm.Context.invocations == nil {
+		return fmt.Errorf("%w: %v called without %T in %T", ErrPrecompileInvocationLimit, addr, evm.Context.invocations, evm.Context)
+	}
+	return evm.Context.invocations.consume(addr, limit)
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestPrecompileInvocationLimit(t *testing.T) {
+	var (
+		limited   = common.Address{'l'}
+		unlimited = common.Address{'u'}
+	)
+	const limit = 2
+
+	registry := vm.NewPrecompileRegistry()
+	registry.RegisterAt(limited, &precompileStub{returnData: []byte("limited")}, nil, vm.WithInvocationLimit(limit))
+	registry.RegisterAt(unlimited, &precompileStub{returnData: []byte("unlimited")}, nil)
+
+	hookstest.Register(t, params.Extras[params.NOOPHooks, registryRulesHooks]{
+		NewRules: func(_ *params.ChainConfig, r *params.Rules, _ params.NOOPHooks, _ *big.Int, _ bool, _ uint64) registryRulesHooks {
+			return registryRulesHooks{precompiles: registry.For(*r)}
+		},
+	})
+
+	newEVM := func(t *testing.T, inv *vm.PrecompileInvocations) *vm.EVM {
+		t.Helper()
+		bCtx := vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			BlockNumber: big.NewInt(0),
+		}
+		bCtx.SetPrecompileInvocations(inv)
+		_, evm := ethtest.NewZeroEVM(t,
+			ethtest.WithChainConfig(params.MergedTestChainConfig),
+			ethtest.WithBlockContext(bCtx),
+		)
+		return evm
+	}
+	call := func(t *testing.T, evm *vm.EVM, addr common.Address) error {
+		t.Helper()
+		_, _, err := evm.Call(vm.AccountRef{}, addr, nil, 1e6, uint256.NewInt(0))
+		return err
+	}
+
+	t.Run("counts_persist_across_reset", func(t *testing.T) {
+		evm := newEVM(t, vm.NewPrecompileInvocations())
+		for i := 0; i < limit; i++ {
+			require.NoErrorf(t, call(t, evm, limited), "call %d to limited precompile", i)
+			evm.Reset(evm.TxContext, evm.StateDB)
+		}
+		require.ErrorIs(t, call(t, evm, limited), vm.ErrPrecompileInvocationLimit, "call beyond limit")
+		for i := 0; i <= limit; i++ {
+			require.NoErrorf(t, call(t, evm, unlimited), "call %d to unlimited precompile", i)
+		}
+
+		inv := evm.Context.PrecompileInvocations()
+		assert.Equal(t, uint64(limit), inv.Count(limited), "failed invocation not counted")
+		assert.Zero(t, inv.Count(unlimited), "unlimited precompile not counted")
+
+		inv.Reset()
+		assert.Zero(t, inv.Count(limited), "after Reset()")
+		require.NoError(t, call(t, evm, limited), "call after Reset()")
+	})
+
+	t.Run("no_counts", func(t *testing.T) {
+		evm := newEVM(t, nil)
+		require.ErrorIs(t, call(t, evm, limited), vm.ErrPrecompileInvocationLimit, "call to limited precompile")
+		require.NoError(t, call(t, evm, unlimited), "call to unlimited precompile")
+	})
+
+	t.Run("shared_counts", func(t *testing.T) {
+		inv := vm.NewPrecompileInvocations()
+		for i := 0; i < limit; i++ {
+			require.NoErrorf(t, call(t, newEVM(t, inv), limited), "call %d with new EVM", i)
+		}
+		assert.Equal(t, uint64(limit), inv.Count(limited), "count shared by EVMs")
+
+		cp := inv.Copy()
+		cp.Reset()
+		assert.Equal(t, uint64(limit), inv.Count(limited), "count after Reset() of Copy()")
+		require.ErrorIs(t, call(t, newEVM(t, inv), limited), vm.ErrPrecompileInvocationLimit, "call beyond limit with new EVM")
+
+		require.NoError(t, call(t, newEVM(t, vm.NewPrecompileInvocations()), limited), "new block with fresh counts")
+	})
+}
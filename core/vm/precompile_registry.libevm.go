@@ -0,0 +1,215 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A PrecompileRegistry is a declarative alternative to implementing the
+// [params.RulesHooks] PrecompileOverride and ActivePrecompiles methods by hand.
+// Precompiles are registered along with a predicate determining whether they
+// are active under a given set of [params.Rules], and [PrecompileRegistry.For]
+// returns a [PrecompileSet] whose methods can be returned directly by said
+// hooks; e.g.
+//
+//	var registry = vm.NewPrecompileRegistry()
+//
+//	func init() {
+//		registry.RegisterAt(addr, contract, func(r params.Rules) bool { ... })
+//	}
+//
+//	type rulesHooks struct {
+//		params.NOOPHooks
+//		precompiles *vm.PrecompileSet
+//	}
+//
+//	func (h rulesHooks) PrecompileOverride(a common.Address) (libevm.PrecompiledContract, bool) {
+//		return h.precompiles.PrecompileOverride(a)
+//	}
+//
+//	// ... and similarly for ActivePrecompiles()
+//
+//	params.Extras[...]{
+//		NewRules: func(_ *params.ChainConfig, r *params.Rules, ...) rulesHooks {
+//			return rulesHooks{precompiles: registry.For(*r)}
+//		},
+//	}
+//
+// All methods are safe for concurrent use.
+type PrecompileRegistry struct {
+	mu      sync.RWMutex
+	entries []*registeredPrecompile
+	// sets caches the [PrecompileSet] for each distinct combination of active
+	// entries, keyed by [activationKey]. It is reset by every registration.
+	sets map[string]*PrecompileSet
+}
+
+type registeredPrecompile struct {
+	addr     common.Address
+	contract func() PrecompiledContract
+	activeIf func(params.Rules) bool
+}
+
+// NewPrecompileRegistry returns an empty [PrecompileRegistry].
+func NewPrecompileRegistry() *PrecompileRegistry {
+	return &PrecompileRegistry{
+		sets: make(map[string]*PrecompileSet),
+	}
+}
+
+// RegisterAt registers the contract at the address, active i.f.f. `activeIf`
+// returns true for the [params.Rules] passed to [PrecompileRegistry.For]. A nil
+// `activeIf` is always active.
+//
+// Multiple contracts MAY be registered at the same address, typically to
+// upgrade a precompile at a network upgrade. If more than one is active then
+// the last to be registered takes precedence, along with its options; see
+// [WithInvocationLimit].
+func (r *PrecompileRegistry) RegisterAt(addr common.Address, contract PrecompiledContract, activeIf func(params.Rules) bool, opts ...RegistrationOption) {
+	r.register(addr, func() PrecompiledContract { return contract }, activeIf, opts...)
+}
+
+// RegisterLazyAt is equivalent to [PrecompileRegistry.RegisterAt] except that
+// the contract is only constructed the first time that it is active, after
+// which the same instance is reused.
+func (r *PrecompileRegistry) RegisterLazyAt(addr common.Address, newContract func() PrecompiledContract, activeIf func(params.Rules) bool, opts ...RegistrationOption) {
+	r.register(addr, sync.OnceValue(newContract), activeIf, opts...)
+}
+
+func (r *PrecompileRegistry) register(addr common.Address, contract func() PrecompiledContract, activeIf func(params.Rules) bool, opts ...RegistrationOption) {
+	if activeIf == nil {
+		activeIf = func(params.Rules) bool { return true }
+	}
+	if limit := options.As(opts...).invocationLimit; limit != nil {
+		unlimited := contract
+		contract = sync.OnceValue(func() PrecompiledContract {
+			return &limitedPrecompile{unlimited(), *limit}
+		})
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, &registeredPrecompile{addr, contract, activeIf})
+	clear(r.sets)
+}
+
+// For returns the precompiles active under the [params.Rules]. The activation
+// predicates are evaluated on every call but the returned [PrecompileSet] is
+// cached and shared by all Rules under which the same registrations are
+// active.
+//
+// If For is called by [params.Extras.NewRules] then the activation predicates
+// MUST NOT depend on the Rules' extra payload as it is yet to be set.
+func (r *PrecompileRegistry) For(rules params.Rules) *PrecompileSet {
+	r.mu.RLock()
+	active := make([]bool, len(r.entries))
+	for i, e := range r.entries {
+		active[i] = e.activeIf(rules)
+	}
+	key := activationKey(active)
+	set, ok := r.sets[key]
+	entries := r.entries
+	r.mu.RUnlock()
+	if ok {
+		return set
+	}
+
+	set = &PrecompileSet{
+		contracts: make(map[common.Address]func() PrecompiledContract),
+	}
+	for i, e := range entries {
+		if !active[i] {
+			continue
+		}
+		if _, ok := set.contracts[e.addr]; !ok {
+			set.addrs = append(set.addrs, e.addr)
+		}
+		set.contracts[e.addr] = e.contract
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == len(entries) {
+		// Registration is append-only so equal lengths mean that the set is
+		// still valid.
+		r.sets[key] = set
+	}
+	return set
+}
+
+// activationKey encodes which registrations are active, for use as a map key.
+func activationKey(active []bool) string {
+	key := make([]byte, (len(active)+7)/8)
+	for i, a := range active {
+		if a {
+			key[i/8] |= 1 << (i % 8)
+		}
+	}
+	return string(key)
+}
+
+// A PrecompileSet is the set of precompiles active under a particular set of
+// [params.Rules], as returned by [PrecompileRegistry.For]. Its methods have the
+// same signatures as their [params.RulesHooks] equivalents, to which they can
+// be returned directly. A nil *PrecompileSet is empty.
+type PrecompileSet struct {
+	addrs     []common.Address // in order of first registration
+	contracts map[common.Address]func() PrecompiledContract
+}
+
+// PrecompileOverride returns the precompile active at the address, if any. If
+// there is none then it returns false, signalling default precompile
+// behaviour.
+func (s *PrecompileSet) PrecompileOverride(addr common.Address) (libevm.PrecompiledContract, bool) {
+	if s == nil {
+		return nil, false
+	}
+	c, ok := s.contracts[addr]
+	if !ok {
+		return nil, false
+	}
+	return c(), true
+}
+
+// ActivePrecompiles returns `active` with the addresses of all precompiles in
+// the set appended, unless already present.
+func (s *PrecompileSet) ActivePrecompiles(active []common.Address) []common.Address {
+	if s == nil {
+		return active
+	}
+	for _, a := range s.addrs {
+		if !slices.Contains(active, a) {
+			active = append(active, a)
+		}
+	}
+	return active
+}
+
+// Addresses returns the addresses of all precompiles in the set, in order of
+// their first registration.
+func (s *PrecompileSet) Addresses() []common.Address {
+	if s == nil {
+		return nil
+	}
+	return slices.Clone(s.addrs)
+}
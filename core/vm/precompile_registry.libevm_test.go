@@ -0,0 +1,157 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+type registryRulesHooks struct {
+	params.NOOPHooks
+	precompiles *vm.PrecompileSet
+}
+
+func (h registryRulesHooks) PrecompileOverride(a common.Address) (libevm.PrecompiledContract, bool) {
+	return h.precompiles.PrecompileOverride(a)
+}
+
+func (h registryRulesHooks) ActivePrecompiles(active []common.Address) []common.Address {
+	return h.precompiles.ActivePrecompiles(active)
+}
+
+func TestPrecompileRegistry(t *testing.T) {
+	var (
+		stable     = common.Address{'s'}
+		upgraded   = common.Address{'u'}
+		cancunOnly = common.Address{'c'}
+		lazy       = common.Address{'l'}
+	)
+	isCancun := func(r params.Rules) bool { return r.IsCancun }
+	notCancun := func(r params.Rules) bool { return !r.IsCancun }
+
+	var constructed int
+	registry := vm.NewPrecompileRegistry()
+	registry.RegisterAt(stable, &precompileStub{returnData: []byte("stable")}, nil)
+	registry.RegisterAt(upgraded, &precompileStub{returnData: []byte("v1")}, nil)
+	registry.RegisterAt(upgraded, &precompileStub{returnData: []byte("v2")}, isCancun)
+	registry.RegisterAt(cancunOnly, &precompileStub{returnData: []byte("cancun")}, isCancun)
+	registry.RegisterLazyAt(lazy, func() vm.PrecompiledContract {
+		constructed++
+		return &precompileStub{returnData: []byte("lazy")}
+	}, notCancun)
+
+	hookstest.Register(t, params.Extras[params.NOOPHooks, registryRulesHooks]{
+		NewRules: func(_ *params.ChainConfig, r *params.Rules, _ params.NOOPHooks, _ *big.Int, _ bool, _ uint64) registryRulesHooks {
+			return registryRulesHooks{precompiles: registry.For(*r)}
+		},
+	})
+
+	var (
+		preCancun = params.Rules{IsShanghai: true}
+		cancun    = params.Rules{IsShanghai: true, IsCancun: true}
+	)
+	assert.Same(t, registry.For(cancun), registry.For(cancun), "PrecompileSet cached for equivalent Rules")
+	assert.Equal(t, []common.Address{stable, upgraded, lazy}, registry.For(preCancun).Addresses(), "pre-Cancun addresses")
+	assert.Equal(t, []common.Address{stable, upgraded, cancunOnly}, registry.For(cancun).Addresses(), "Cancun addresses")
+	assert.Zero(t, constructed, "lazy precompile constructed before use")
+
+	tests := []struct {
+		name     string
+		config   *params.ChainConfig
+		addr     common.Address
+		want     []byte
+		wantNone bool
+	}{
+		{
+			name:   "always_active",
+			config: params.MergedTestChainConfig,
+			addr:   stable,
+			want:   []byte("stable"),
+		},
+		{
+			name:   "original_before_upgrade",
+			config: noCancunConfig(),
+			addr:   upgraded,
+			want:   []byte("v1"),
+		},
+		{
+			name:   "upgraded",
+			config: params.MergedTestChainConfig,
+			addr:   upgraded,
+			want:   []byte("v2"),
+		},
+		{
+			name:     "inactive",
+			config:   noCancunConfig(),
+			addr:     cancunOnly,
+			wantNone: true,
+		},
+		{
+			name:   "lazy",
+			config: noCancunConfig(),
+			addr:   lazy,
+			want:   []byte("lazy"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, evm := ethtest.NewZeroEVM(t,
+				ethtest.WithChainConfig(tt.config),
+				ethtest.WithBlockContext(vm.BlockContext{
+					CanTransfer: core.CanTransfer,
+					Transfer:    core.Transfer,
+					BlockNumber: big.NewInt(0),
+					Random:      &common.Hash{}, // post-merge
+				}),
+			)
+			rules := evm.ChainConfig().Rules(evm.Context.BlockNumber, true, evm.Context.Time)
+
+			got, _, err := evm.Call(vm.AccountRef{}, tt.addr, nil, 1e6, uint256.NewInt(0))
+			require.NoError(t, err, "%T.Call()", evm)
+			if tt.wantNone {
+				assert.Empty(t, got, "%T.Call() return data", evm)
+				assert.NotContains(t, vm.ActivePrecompiles(rules), tt.addr, "vm.ActivePrecompiles()")
+				return
+			}
+			assert.Equal(t, tt.want, got, "%T.Call() return data", evm)
+			assert.Contains(t, vm.ActivePrecompiles(rules), tt.addr, "vm.ActivePrecompiles()")
+		})
+	}
+	assert.Equal(t, 1, constructed, "lazy precompile constructions")
+}
+
+func noCancunConfig() *params.ChainConfig {
+	c := *params.MergedTestChainConfig
+	c.CancunTime = nil
+	c.PragueTime = nil
+	c.VerkleTime = nil
+	return &c
+}
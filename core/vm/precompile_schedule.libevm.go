@@ -0,0 +1,132 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A PrecompileSchedule maps addresses to the upgrades of the precompiles at
+// said addresses, allowing precompiles to be activated, replaced, and
+// deactivated by block timestamp without implementing the [params.RulesHooks]
+// PrecompileOverride and ActivePrecompiles methods. See
+// [RegisterPrecompileSchedule].
+type PrecompileSchedule map[common.Address][]PrecompileUpgrade
+
+// A PrecompileUpgrade activates a precompile from the block timestamp onwards.
+// A nil Contract deactivates any previously scheduled precompile, returning the
+// address to its default behaviour.
+type PrecompileUpgrade struct {
+	Timestamp uint64
+	Contract  PrecompiledContract
+}
+
+// RegisterPrecompileSchedule registers the schedule, which is thereafter
+// consulted by the [EVM] and by [ActivePrecompiles], using the timestamp
+// returned by [params.Rules.Timestamp]. The [params.RulesHooks]
+// PrecompileOverride method takes precedence over the schedule, as does its
+// ActivePrecompiles method, which receives addresses that include those
+// scheduled to be active.
+//
+// The upgrades for every address MUST be in strictly increasing order of
+// timestamp, otherwise RegisterPrecompileSchedule panics. It is expected to be
+// called in an `init()` function and MUST NOT be called more than once.
+func RegisterPrecompileSchedule(s PrecompileSchedule) {
+	precompileSchedule.MustRegister(newPrecompileSchedule(s))
+}
+
+// TestOnlyClearPrecompileSchedule clears the [PrecompileSchedule] previously
+// passed to [RegisterPrecompileSchedule]. It panics if called from a
+// non-testing call stack.
+func TestOnlyClearPrecompileSchedule() {
+	precompileSchedule.TestOnlyClear()
+}
+
+var precompileSchedule register.AtMostOnce[*registeredSchedule]
+
+// A registeredSchedule is an immutable copy of a [PrecompileSchedule], with
+// addresses sorted to make [ActivePrecompiles] deterministic.
+type registeredSchedule struct {
+	addrs    []common.Address
+	upgrades map[common.Address][]PrecompileUpgrade
+}
+
+func newPrecompileSchedule(s PrecompileSchedule) *registeredSchedule {
+	r := &registeredSchedule{
+		upgrades: make(map[common.Address][]PrecompileUpgrade, len(s)),
+	}
+	for addr, ups := range s {
+		for i := 1; i < len(ups); i++ {
+			if ups[i].Timestamp <= ups[i-1].Timestamp {
+				panic(fmt.Sprintf("precompile upgrades for %v not in strictly increasing order of timestamp", addr))
+			}
+		}
+		r.addrs = append(r.addrs, addr)
+		r.upgrades[addr] = slices.Clone(ups)
+	}
+	slices.SortFunc(r.addrs, func(a, b common.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return r
+}
+
+// at returns the precompile scheduled at the address as of the timestamp, if
+// any.
+func (r *registeredSchedule) at(addr common.Address, timestamp uint64) (PrecompiledContract, bool) {
+	ups := r.upgrades[addr]
+	// Index of the first upgrade strictly after the timestamp, so the one
+	// before it is the latest to have been activated.
+	i := sort.Search(len(ups), func(i int) bool {
+		return ups[i].Timestamp > timestamp
+	})
+	if i == 0 || ups[i-1].Contract == nil {
+		return nil, false
+	}
+	return ups[i-1].Contract, true
+}
+
+// scheduledPrecompile returns the precompile scheduled at the address under
+// the rules, if any.
+func scheduledPrecompile(rules *params.Rules, addr common.Address) (PrecompiledContract, bool) {
+	if !precompileSchedule.Registered() {
+		return nil, false
+	}
+	return precompileSchedule.Get().at(addr, rules.Timestamp())
+}
+
+// appendScheduledPrecompiles returns `active` with the addresses of all
+// precompiles scheduled to be active under the rules appended, unless already
+// present.
+func appendScheduledPrecompiles(rules *params.Rules, active []common.Address) []common.Address {
+	if !precompileSchedule.Registered() {
+		return active
+	}
+	s := precompileSchedule.Get()
+	for _, addr := range s.addrs {
+		if _, ok := s.at(addr, rules.Timestamp()); ok && !slices.Contains(active, addr) {
+			active = append(active, addr)
+		}
+	}
+	return active
+}
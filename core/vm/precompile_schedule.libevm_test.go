@@ -0,0 +1,125 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestPrecompileSchedule(t *testing.T) {
+	var (
+		upgraded    = common.Address{'u'}
+		deactivated = common.Address{'d'}
+		overridden  = common.Address{'o'}
+		ecrecover   = common.BytesToAddress([]byte{1})
+	)
+	stub := func(s string) vm.PrecompiledContract {
+		return &precompileStub{returnData: []byte(s)}
+	}
+
+	vm.TestOnlyClearPrecompileSchedule()
+	t.Cleanup(vm.TestOnlyClearPrecompileSchedule)
+	vm.RegisterPrecompileSchedule(vm.PrecompileSchedule{
+		upgraded: {
+			{Timestamp: 10, Contract: stub("v1")},
+			{Timestamp: 20, Contract: stub("v2")},
+		},
+		deactivated: {
+			{Timestamp: 10, Contract: stub("temporary")},
+			{Timestamp: 20, Contract: nil},
+		},
+		overridden: {
+			{Timestamp: 0, Contract: stub("scheduled")},
+		},
+		ecrecover: {
+			{Timestamp: 20, Contract: stub("not ecrecover")},
+		},
+	})
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			overridden: stub("hook"),
+		},
+	}
+	hooks.Register(t)
+
+	tests := []struct {
+		addr     common.Address
+		time     uint64
+		want     []byte
+		wantNone bool
+	}{
+		{addr: upgraded, time: 9, wantNone: true},
+		{addr: upgraded, time: 10, want: []byte("v1")},
+		{addr: upgraded, time: 19, want: []byte("v1")},
+		{addr: upgraded, time: 20, want: []byte("v2")},
+		{addr: upgraded, time: 1000, want: []byte("v2")},
+		{addr: deactivated, time: 15, want: []byte("temporary")},
+		{addr: deactivated, time: 20, wantNone: true},
+		{addr: overridden, time: 0, want: []byte("hook")},
+		{addr: ecrecover, time: 20, want: []byte("not ecrecover")},
+	}
+
+	for _, tt := range tests {
+		_, evm := ethtest.NewZeroEVM(t,
+			ethtest.WithBlockContext(vm.BlockContext{
+				CanTransfer: core.CanTransfer,
+				Transfer:    core.Transfer,
+				BlockNumber: big.NewInt(0),
+				Time:        tt.time,
+			}),
+		)
+		rules := evm.ChainConfig().Rules(evm.Context.BlockNumber, false, evm.Context.Time)
+		require.Equal(t, tt.time, rules.Timestamp(), "%T.Timestamp()", rules)
+
+		got, _, err := evm.Call(vm.AccountRef{}, tt.addr, nil, 1e6, uint256.NewInt(0))
+		require.NoErrorf(t, err, "%T.Call(%v) at time %d", evm, tt.addr, tt.time)
+		if tt.wantNone {
+			assert.Emptyf(t, got, "%T.Call(%v) at time %d", evm, tt.addr, tt.time)
+			assert.NotContainsf(t, vm.ActivePrecompiles(rules), tt.addr, "vm.ActivePrecompiles() at time %d", tt.time)
+			continue
+		}
+		assert.Equalf(t, tt.want, got, "%T.Call(%v) at time %d", evm, tt.addr, tt.time)
+		assert.Containsf(t, vm.ActivePrecompiles(rules), tt.addr, "vm.ActivePrecompiles() at time %d", tt.time)
+	}
+}
+
+func TestPrecompileScheduleOrder(t *testing.T) {
+	vm.TestOnlyClearPrecompileSchedule()
+	t.Cleanup(vm.TestOnlyClearPrecompileSchedule)
+
+	assert.Panics(t, func() {
+		vm.RegisterPrecompileSchedule(vm.PrecompileSchedule{
+			{'x'}: {
+				{Timestamp: 10, Contract: &precompileStub{}},
+				{Timestamp: 10, Contract: &precompileStub{}},
+			},
+		})
+	}, "duplicate timestamps")
+}
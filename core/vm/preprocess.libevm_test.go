@@ -43,7 +43,7 @@ type preprocessingCharger struct {
 
 var errUnknownTx = errors.New("unknown tx")
 
-func (p preprocessingCharger) PreprocessingGasCharge(tx common.Hash) (uint64, error) {
+func (p preprocessingCharger) PreprocessingGasCharge(_ vm.BlockContext, tx common.Hash) (uint64, error) {
 	c, ok := p.charge[tx]
 	if !ok {
 		return 0, fmt.Errorf("%w: %v", errUnknownTx, tx)
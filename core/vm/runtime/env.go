@@ -41,6 +41,7 @@ func NewEnv(cfg *Config) *vm.EVM {
 		BlobBaseFee: cfg.BlobBaseFee,
 		Random:      cfg.Random,
 	}
+	blockContext.SetPrecompileInvocations(vm.NewPrecompileInvocations()) // libevm
 
 	return vm.NewEVM(blockContext, txContext, cfg.State, cfg.ChainConfig, cfg.EVMConfig)
 }
@@ -0,0 +1,61 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import "github.com/ava-labs/libevm/common"
+
+// A Scratchpad is an ephemeral key-value store available to a stateful
+// precompile via [PrecompileEnvironment.Scratch]. It is scoped to a single call
+// frame: every invocation of a precompile, including reentrant ones, receives
+// its own, empty Scratchpad that is discarded when the precompile returns,
+// regardless of whether or not it reverts. Values are therefore never
+// committed to state, nor journalled, and their use consumes no gas.
+//
+// The zero value is ready to use. A Scratchpad is not safe for concurrent use.
+type Scratchpad struct {
+	vals map[common.Hash]common.Hash
+}
+
+// Get returns the value stored against `key`, or the zero hash if none exists.
+func (s *Scratchpad) Get(key common.Hash) common.Hash {
+	return s.vals[key]
+}
+
+// Has reports whether a value has been stored against `key`. Unlike storage
+// semantics, storing the zero hash is distinct from deletion.
+func (s *Scratchpad) Has(key common.Hash) bool {
+	_, ok := s.vals[key]
+	return ok
+}
+
+// Set stores `val` against `key`, overwriting any previous value.
+func (s *Scratchpad) Set(key, val common.Hash) {
+	if s.vals == nil {
+		s.vals = make(map[common.Hash]common.Hash)
+	}
+	s.vals[key] = val
+}
+
+// Delete removes the value stored against `key`, if any.
+func (s *Scratchpad) Delete(key common.Hash) {
+	delete(s.vals, key)
+}
+
+// Len returns the number of keys with stored values.
+func (s *Scratchpad) Len() int {
+	return len(s.vals)
+}
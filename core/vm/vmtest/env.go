@@ -0,0 +1,445 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package vmtest provides a mock [vm.PrecompileEnvironment] for unit-testing
+// stateful precompiles without constructing an EVM.
+package vmtest
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/core/vm/internal/gascost"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/params"
+)
+
+// DefaultGas is the gas available to an [Env] unless overridden with
+// [WithGas].
+const DefaultGas uint64 = 1_000_000
+
+// An Option configures an [Env].
+type Option = options.Option[config]
+
+type config struct {
+	chainConfig *params.ChainConfig
+	rules       *params.Rules
+	header      *types.Header
+	addresses   libevm.AddressContext
+	callType    vm.CallType
+	readOnly    bool
+	gas         uint64
+	value       *uint256.Int
+	setupState  []func(*state.StateDB)
+	callHandler func(SubCall) CallResult
+	oracle      any
+	oracleSet   bool
+	blobHashes  []common.Hash
+	blobBaseFee *big.Int
+	clock       vm.Clock
+}
+
+// WithChainConfig overrides the default [params.MergedTestChainConfig].
+func WithChainConfig(c *params.ChainConfig) Option {
+	return options.Func[config](func(cfg *config) { cfg.chainConfig = c })
+}
+
+// WithRules overrides the [params.Rules], which otherwise default to those of
+// the chain config at the block header.
+func WithRules(r params.Rules) Option {
+	return options.Func[config](func(cfg *config) { cfg.rules = &r })
+}
+
+// WithBlockHeader overrides the default header, which only has a Number of 1.
+func WithBlockHeader(h *types.Header) Option {
+	return options.Func[config](func(cfg *config) { cfg.header = types.CopyHeader(h) })
+}
+
+// WithAddresses sets the addresses returned by [Env.Addresses]. If the Raw
+// field is nil then it is populated with EVMSemantic.
+func WithAddresses(a libevm.AddressContext) Option {
+	return options.Func[config](func(cfg *config) { cfg.addresses = a })
+}
+
+// WithCallType sets the [vm.CallType] of the incoming call, which otherwise
+// defaults to [vm.Call]. A [vm.StaticCall] implies [WithReadOnly].
+func WithCallType(t vm.CallType) Option {
+	return options.Func[config](func(cfg *config) { cfg.callType = t })
+}
+
+// WithReadOnly sets whether the precompile is called in a read-only context.
+func WithReadOnly(readOnly bool) Option {
+	return options.Func[config](func(cfg *config) { cfg.readOnly = readOnly })
+}
+
+// WithGas overrides the [DefaultGas] available to the precompile.
+func WithGas(gas uint64) Option {
+	return options.Func[config](func(cfg *config) { cfg.gas = gas })
+}
+
+// WithValue sets the value sent with the incoming call.
+func WithValue(v *uint256.Int) Option {
+	return options.Func[config](func(cfg *config) { cfg.value = new(uint256.Int).Set(v) })
+}
+
+// WithState calls `fn` with the [state.StateDB] before the [Env] is returned,
+// allowing state to be scripted. It MAY be used multiple times, in which
+// case the functions are called in order.
+func WithState(fn func(*state.StateDB)) Option {
+	return options.Func[config](func(cfg *config) { cfg.setupState = append(cfg.setupState, fn) })
+}
+
+// WithCallHandler sets the function that determines the result of every
+// [Env.Call] sub-call. By default, sub-calls return no data, use no gas, and
+// don't error.
+func WithCallHandler(fn func(SubCall) CallResult) Option {
+	return options.Func[config](func(cfg *config) { cfg.callHandler = fn })
+}
+
+// WithBlockOracle sets the payload returned by [Env.BlockOracle].
+func WithBlockOracle(payload any) Option {
+	return options.Func[config](func(cfg *config) {
+		cfg.oracle = payload
+		cfg.oracleSet = true
+	})
+}
+
+// WithClock sets the [vm.Clock] returned by [Env.Clock]. The default clock
+// always returns the block time, as set by [WithBlockHeader].
+func WithClock(c vm.Clock) Option {
+	return options.Func[config](func(cfg *config) {
+		cfg.clock = c
+	})
+}
+
+// WithBlobs sets the values returned by [Env.BlobHashes] and
+// [Env.BlobBaseFee].
+func WithBlobs(hashes []common.Hash, baseFee *big.Int) Option {
+	return options.Func[config](func(cfg *config) {
+		cfg.blobHashes = slices.Clone(hashes)
+		if baseFee != nil {
+			cfg.blobBaseFee = new(big.Int).Set(baseFee)
+		}
+	})
+}
+
+// A SubCall records a call made via [Env.Call].
+type SubCall struct {
+	Addr    common.Address
+	Input   []byte
+	Gas     uint64
+	Value   *uint256.Int
+	Options []vm.CallOption
+}
+
+// A CallResult is the scripted result of a [SubCall]. GasUsed is capped at the
+// gas made available to the sub-call.
+type CallResult struct {
+	Ret     []byte
+	GasUsed uint64
+	Err     error
+}
+
+// A GasCharge is an entry in the gas ledger of an [Env].
+type GasCharge struct {
+	Source string // "UseGas", "ReadStorage", or "Call"
+	Amount uint64
+}
+
+// Env is a mock [vm.PrecompileEnvironment]. Its state is held in a real, empty
+// [state.StateDB], which can be scripted via [WithState] or [Env.State]. All
+// other methods of the interface are backed by values set via [Option]s,
+// except for StateDB() and ReadOnlyState(), which return the [state.StateDB],
+// and Call(), which records a [SubCall].
+type Env struct {
+	t   testing.TB
+	cfg *config
+
+	db       *state.StateDB
+	scratch  vm.Scratchpad
+	gas      uint64
+	ledger   []GasCharge
+	subCalls []SubCall
+	invalid  error
+}
+
+var _ vm.PrecompileEnvironment = (*Env)(nil)
+
+// NewEnv returns a new [Env] configured with the [Option]s.
+func NewEnv(t testing.TB, opts ...Option) *Env {
+	t.Helper()
+
+	cfg := options.ApplyTo(&config{
+		chainConfig: params.MergedTestChainConfig,
+		header:      &types.Header{Number: big.NewInt(1)},
+		callType:    vm.Call,
+		gas:         DefaultGas,
+		value:       new(uint256.Int),
+	}, opts...)
+	if cfg.rules == nil {
+		r := cfg.chainConfig.Rules(cfg.header.Number, cfg.chainConfig.TerminalTotalDifficultyPassed, cfg.header.Time)
+		cfg.rules = &r
+	}
+	if cfg.addresses.Raw == nil {
+		raw := cfg.addresses.EVMSemantic
+		cfg.addresses.Raw = &raw
+	}
+	if cfg.callType == vm.StaticCall {
+		cfg.readOnly = true
+	}
+
+	db, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err, "state.New()")
+	for _, fn := range cfg.setupState {
+		fn(db)
+	}
+	return &Env{
+		t:   t,
+		cfg: cfg,
+		db:  db,
+		gas: cfg.gas,
+	}
+}
+
+// Run calls `p` with the [Env] and `input`.
+func (e *Env) Run(p vm.PrecompiledStatefulContract, input []byte) ([]byte, error) {
+	return p(e, input)
+}
+
+// State returns the underlying [state.StateDB], regardless of
+// [Env.ReadOnly].
+func (e *Env) State() *state.StateDB { return e.db }
+
+// ChainConfig implements [vm.PrecompileEnvironment].
+func (e *Env) ChainConfig() *params.ChainConfig { return e.cfg.chainConfig }
+
+// Rules implements [vm.PrecompileEnvironment].
+func (e *Env) Rules() params.Rules { return *e.cfg.rules }
+
+// StateDB implements [vm.PrecompileEnvironment], returning nil if
+// [Env.ReadOnly] is true.
+func (e *Env) StateDB() vm.StateDB {
+	if e.ReadOnly() {
+		return nil
+	}
+	return e.db
+}
+
+// ReadOnlyState implements [vm.PrecompileEnvironment].
+func (e *Env) ReadOnlyState() libevm.StateReader { return e.db }
+
+// IncomingCallType implements [vm.PrecompileEnvironment].
+func (e *Env) IncomingCallType() vm.CallType { return e.cfg.callType }
+
+// Addresses implements [vm.PrecompileEnvironment].
+func (e *Env) Addresses() *libevm.AddressContext {
+	a := e.cfg.addresses
+	raw := *a.Raw
+	a.Raw = &raw
+	return &a
+}
+
+// ReadOnly implements [vm.PrecompileEnvironment].
+func (e *Env) ReadOnly() bool { return e.cfg.readOnly }
+
+// Gas implements [vm.PrecompileEnvironment].
+func (e *Env) Gas() uint64 { return e.gas }
+
+// UseGas implements [vm.PrecompileEnvironment], recording the charge in the
+// gas ledger if there is sufficient gas.
+func (e *Env) UseGas(gas uint64) bool {
+	return e.charge("UseGas", gas)
+}
+
+func (e *Env) charge(src string, gas uint64) bool {
+	if gas > e.gas {
+		return false
+	}
+	e.gas -= gas
+	e.ledger = append(e.ledger, GasCharge{Source: src, Amount: gas})
+	return true
+}
+
+// Value implements [vm.PrecompileEnvironment].
+func (e *Env) Value() *uint256.Int { return new(uint256.Int).Set(e.cfg.value) }
+
+// BlockHeader implements [vm.PrecompileEnvironment].
+func (e *Env) BlockHeader() (types.Header, error) { return *types.CopyHeader(e.cfg.header), nil }
+
+// BlockNumber implements [vm.PrecompileEnvironment].
+func (e *Env) BlockNumber() *big.Int { return new(big.Int).Set(e.cfg.header.Number) }
+
+// BlockTime implements [vm.PrecompileEnvironment].
+func (e *Env) BlockTime() uint64 { return e.cfg.header.Time }
+
+// Clock implements [vm.PrecompileEnvironment].
+func (e *Env) Clock() vm.Clock {
+	if e.cfg.clock != nil {
+		return e.cfg.clock
+	}
+	return blockTimeClock(e.cfg.header.Time)
+}
+
+type blockTimeClock uint64
+
+func (c blockTimeClock) Now() time.Time { return time.Unix(int64(c), 0) } //nolint:gosec // Block times don't overflow int64
+
+// BlockOracle implements [vm.PrecompileEnvironment].
+func (e *Env) BlockOracle() (any, bool) { return e.cfg.oracle, e.cfg.oracleSet }
+
+// BlobHashes implements [vm.PrecompileEnvironment].
+func (e *Env) BlobHashes() []common.Hash { return slices.Clone(e.cfg.blobHashes) }
+
+// BlobBaseFee implements [vm.PrecompileEnvironment].
+func (e *Env) BlobBaseFee() *big.Int {
+	if f := e.cfg.blobBaseFee; f != nil {
+		return new(big.Int).Set(f)
+	}
+	return nil
+}
+
+// InvalidateExecution implements [vm.PrecompileEnvironment], recording the
+// error for retrieval via [Env.Invalidated].
+func (e *Env) InvalidateExecution(err error) { e.invalid = err }
+
+// Scratch implements [vm.PrecompileEnvironment].
+func (e *Env) Scratch() *vm.Scratchpad { return &e.scratch }
+
+// TransientState implements [vm.PrecompileEnvironment].
+func (e *Env) TransientState(addr common.Address, key common.Hash) common.Hash {
+	return e.db.GetTransientState(addr, key)
+}
+
+// SetTransientState implements [vm.PrecompileEnvironment].
+func (e *Env) SetTransientState(addr common.Address, key, value common.Hash) error {
+	if e.ReadOnly() {
+		return vm.ErrWriteProtection
+	}
+	e.db.SetTransientState(addr, key, value)
+	return nil
+}
+
+// ReadStorage implements [vm.PrecompileEnvironment], charging gas in the same
+// manner as the real environment.
+func (e *Env) ReadStorage(addr common.Address, key common.Hash) (common.Hash, error) {
+	cost, cold := gascost.ReadStorage(*e.cfg.rules, e.db, addr, key)
+	if !e.charge("ReadStorage", cost) {
+		return common.Hash{}, vm.ErrOutOfGas
+	}
+	if cold {
+		e.db.AddSlotToAccessList(addr, key)
+	}
+	return e.db.GetState(addr, key), nil
+}
+
+// AddRefund implements [vm.PrecompileEnvironment].
+func (e *Env) AddRefund(gas uint64) error {
+	if e.ReadOnly() {
+		return vm.ErrWriteProtection
+	}
+	e.db.AddRefund(gas)
+	return nil
+}
+
+// SubRefund implements [vm.PrecompileEnvironment].
+func (e *Env) SubRefund(gas uint64) error {
+	if e.ReadOnly() {
+		return vm.ErrWriteProtection
+	}
+	if gas > e.db.GetRefund() {
+		return vm.ErrRefundUnderflow
+	}
+	e.db.SubRefund(gas)
+	return nil
+}
+
+// Call implements [vm.PrecompileEnvironment], recording the [SubCall] and
+// returning the result of the handler set with [WithCallHandler]. The gas made
+// available to the sub-call is capped at [Env.Gas], and the amount used is
+// recorded in the gas ledger.
+func (e *Env) Call(addr common.Address, input []byte, gas uint64, value *uint256.Int, opts ...vm.CallOption) ([]byte, error) {
+	gas = min(gas, e.gas)
+	call := SubCall{
+		Addr:    addr,
+		Input:   slices.Clone(input),
+		Gas:     gas,
+		Options: opts,
+	}
+	if value != nil {
+		call.Value = new(uint256.Int).Set(value)
+	}
+	e.subCalls = append(e.subCalls, call)
+
+	if e.cfg.callHandler == nil {
+		return nil, nil
+	}
+	res := e.cfg.callHandler(call)
+	e.charge("Call", min(res.GasUsed, gas))
+	return res.Ret, res.Err
+}
+
+// Logs returns all logs emitted via the [state.StateDB].
+func (e *Env) Logs() []*types.Log { return e.db.Logs() }
+
+// SubCalls returns all calls made via [Env.Call], in order.
+func (e *Env) SubCalls() []SubCall { return slices.Clone(e.subCalls) }
+
+// GasLedger returns all gas charges, in order.
+func (e *Env) GasLedger() []GasCharge { return slices.Clone(e.ledger) }
+
+// GasUsed returns the total gas charged.
+func (e *Env) GasUsed() uint64 { return e.cfg.gas - e.gas }
+
+// Invalidated returns the error passed to the last call to
+// [Env.InvalidateExecution], or nil if none was made.
+func (e *Env) Invalidated() error { return e.invalid }
+
+// AssertGasUsed asserts that [Env.GasUsed] equals `want`.
+func (e *Env) AssertGasUsed(want uint64) bool {
+	e.t.Helper()
+	return assert.Equalf(e.t, want, e.GasUsed(), "gas used; ledger: %+v", e.ledger)
+}
+
+// AssertLogs asserts that [Env.Logs] are equivalent to `want`, considering
+// only the Address, Topics, and Data fields.
+func (e *Env) AssertLogs(want ...*types.Log) bool {
+	e.t.Helper()
+	type log struct {
+		Address common.Address
+		Topics  []common.Hash
+		Data    []byte
+	}
+	strip := func(ls []*types.Log) []log {
+		out := make([]log, len(ls))
+		for i, l := range ls {
+			out[i] = log{l.Address, l.Topics, l.Data}
+		}
+		return out
+	}
+	return assert.Equal(e.t, strip(want), strip(e.Logs()), "logs")
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vmtest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/core/vm/vmtest"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestEnv(t *testing.T) {
+	var (
+		self   = common.Address{'s', 'e', 'l', 'f'}
+		callee = common.Address{'c', 'a', 'l', 'l', 'e', 'e'}
+		key    = common.Hash{'k'}
+		val    = common.Hash{'v'}
+		topic  = common.Hash{'t'}
+		errSub = errors.New("sub-call failed")
+	)
+
+	// The precompile reads a slot, emits it as a log, and forwards its input
+	// to another contract.
+	precompile := func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+		self := env.Addresses().EVMSemantic.Self
+		v, err := env.ReadStorage(self, key)
+		if err != nil {
+			return nil, err
+		}
+		if !env.UseGas(100) {
+			return nil, vm.ErrOutOfGas
+		}
+		env.StateDB().AddLog(&types.Log{
+			Address: self,
+			Topics:  []common.Hash{topic},
+			Data:    v.Bytes(),
+		})
+		return env.Call(callee, input, 1000, env.Value())
+	}
+
+	env := vmtest.NewEnv(
+		t,
+		vmtest.WithAddresses(libevm.AddressContext{
+			EVMSemantic: libevm.CallerAndSelf{Self: self},
+		}),
+		vmtest.WithValue(uint256.NewInt(42)),
+		vmtest.WithState(func(db *state.StateDB) {
+			db.SetState(self, key, val)
+		}),
+		vmtest.WithCallHandler(func(c vmtest.SubCall) vmtest.CallResult {
+			return vmtest.CallResult{Ret: c.Input, GasUsed: 5000, Err: errSub}
+		}),
+	)
+
+	input := []byte("input")
+	ret, err := env.Run(precompile, input)
+	require.ErrorIs(t, err, errSub)
+	assert.Equal(t, input, ret, "returned data")
+
+	assert.Equal(t, []vmtest.SubCall{{
+		Addr:  callee,
+		Input: input,
+		Gas:   1000,
+		Value: uint256.NewInt(42),
+	}}, env.SubCalls(), "sub-calls")
+
+	env.AssertLogs(&types.Log{Address: self, Topics: []common.Hash{topic}, Data: val.Bytes()})
+
+	assert.Equal(t, []vmtest.GasCharge{
+		{Source: "ReadStorage", Amount: params.ColdSloadCostEIP2929},
+		{Source: "UseGas", Amount: 100},
+		{Source: "Call", Amount: 1000}, // capped at the gas sent
+	}, env.GasLedger(), "gas ledger")
+	env.AssertGasUsed(params.ColdSloadCostEIP2929 + 100 + 1000)
+	assert.Equal(t, vmtest.DefaultGas-env.GasUsed(), env.Gas(), "remaining gas")
+
+	t.Run("warm_slot", func(t *testing.T) {
+		_, err := env.ReadStorage(self, key)
+		require.NoError(t, err)
+		assert.Equal(t, vmtest.GasCharge{Source: "ReadStorage", Amount: params.WarmStorageReadCostEIP2929}, env.GasLedger()[3])
+	})
+}
+
+func TestEnvReadOnly(t *testing.T) {
+	env := vmtest.NewEnv(t, vmtest.WithCallType(vm.StaticCall), vmtest.WithGas(1))
+
+	assert.True(t, env.ReadOnly(), "ReadOnly() with static call")
+	assert.Nil(t, env.StateDB(), "StateDB() when read-only")
+	assert.NotNil(t, env.ReadOnlyState(), "ReadOnlyState() when read-only")
+	assert.ErrorIs(t, env.SetTransientState(common.Address{}, common.Hash{}, common.Hash{}), vm.ErrWriteProtection)
+	assert.ErrorIs(t, env.AddRefund(1), vm.ErrWriteProtection)
+
+	_, err := env.ReadStorage(common.Address{}, common.Hash{})
+	assert.ErrorIs(t, err, vm.ErrOutOfGas, "ReadStorage() with insufficient gas")
+	assert.False(t, env.UseGas(2), "UseGas() with insufficient gas")
+	assert.Empty(t, env.GasLedger(), "gas ledger after failed charges")
+
+	errInvalid := errors.New("invalid")
+	env.InvalidateExecution(errInvalid)
+	assert.Equal(t, errInvalid, env.Invalidated(), "Invalidated()")
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/trie"
+)
+
+// A Witness is the subset of a block's pre-state that is required to execute
+// it: every trie node and contract code read. Each is identified by its
+// Keccak256 hash, so, given a trusted pre-state root, all reads from a witness
+// are verified by construction.
+//
+// Witnesses are only supported for the hash-based state scheme.
+type Witness struct {
+	Nodes [][]byte `json:"nodes"` // RLP-encoded nodes of the account and storage tries
+	Codes [][]byte `json:"codes"`
+}
+
+// ErrIncompleteWitness is returned by [ProcessWithWitness] if execution
+// required state absent from the [Witness].
+var ErrIncompleteWitness = errors.New("incomplete witness")
+
+// ErrWitnessRootMismatch is returned by [ProcessWithWitness] if the post-state
+// root differs from that of the block.
+var ErrWitnessRootMismatch = errors.New("post-state root mismatch")
+
+// ErrWitnessBlockInvalid is returned by [ProcessWithWitness] if processing the
+// block failed or if the gas used, bloom, or receipt root differ from those in
+// the block's header.
+var ErrWitnessBlockInvalid = errors.New("invalid block")
+
+// StateDatabase returns a [state.Database] holding nothing but the witness.
+func (w *Witness) StateDatabase() state.Database {
+	db := rawdb.NewMemoryDatabase()
+	for _, n := range w.Nodes {
+		rawdb.WriteLegacyTrieNode(db, crypto.Keccak256Hash(n), n)
+	}
+	for _, c := range w.Codes {
+		rawdb.WriteCode(db, crypto.Keccak256Hash(c), c)
+	}
+	return state.NewDatabase(db)
+}
+
+// ProcessWithWitness processes the block with `p`, without any local state,
+// against the state with root `parentRoot` as provided by the [Witness]. The
+// results are checked against the block's header in the same manner as
+// [BlockValidator.ValidateState], and the [BlockResult] is returned even if
+// the returned error is non-nil because of a failed check.
+//
+// Only state accessed via the [state.StateDB] is provided by the witness; `p`
+// is still responsible for providing, e.g., ancestor headers for the BLOCKHASH
+// opcode.
+func ProcessWithWitness(config *params.ChainConfig, p Processor, block *types.Block, parentRoot common.Hash, w *Witness, cfg vm.Config) (*BlockResult, error) {
+	sdb, err := state.New(parentRoot, w.StateDatabase(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: opening pre-state root %v: %v", ErrIncompleteWitness, parentRoot, err)
+	}
+	res := ProcessBlockResult(config, p, block, sdb, cfg)
+	// Reads of missing state are recorded by the [state.StateDB] instead of
+	// being returned by the methods that performed them. This MUST be checked
+	// even if processing failed, as the failure may be a consequence.
+	if err := sdb.Error(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIncompleteWitness, err)
+	}
+	if res.Err != "" {
+		return res, fmt.Errorf("%w: %s", ErrWitnessBlockInvalid, res.Err)
+	}
+	if got, want := res.GasUsed, block.GasUsed(); got != want {
+		return res, fmt.Errorf("%w: gas used %d; block %d has %d", ErrWitnessBlockInvalid, got, block.NumberU64(), want)
+	}
+	if got, want := types.CreateBloom(res.Receipts), block.Bloom(); got != want {
+		return res, fmt.Errorf("%w: bloom %x; block %d has %x", ErrWitnessBlockInvalid, got, block.NumberU64(), want)
+	}
+	if got, want := types.DeriveSha(res.Receipts, trie.NewStackTrie(nil)), block.ReceiptHash(); got != want {
+		return res, fmt.Errorf("%w: receipt root %v; block %d has %v", ErrWitnessBlockInvalid, got, block.NumberU64(), want)
+	}
+	if got, want := res.Root, block.Root(); got != want {
+		return res, fmt.Errorf("%w: got %v; block %d has %v", ErrWitnessRootMismatch, got, block.NumberU64(), want)
+	}
+	return res, nil
+}
+
+// GenerateWitness processes the block with `p`, against the state with root
+// `parentRoot` in `db`, and returns the [Witness] of all state that it
+// accessed, including that required to compute the post-state root. The
+// state in `db` MUST use the hash-based scheme.
+func GenerateWitness(config *params.ChainConfig, p Processor, block *types.Block, parentRoot common.Hash, db ethdb.Database, cfg vm.Config) (*Witness, *BlockResult, error) {
+	rec := &witnessRecorder{
+		Database: db,
+		reads:    make(map[string][]byte),
+	}
+	sdb, err := state.New(parentRoot, state.NewDatabase(rec), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	res := ProcessBlockResult(config, p, block, sdb, cfg)
+	if err := sdb.Error(); err != nil {
+		return nil, nil, err
+	}
+	return rec.witness(), res, nil
+}
+
+// A witnessRecorder records all successful reads from its [ethdb.Database].
+type witnessRecorder struct {
+	ethdb.Database
+
+	mu    sync.Mutex
+	reads map[string][]byte
+}
+
+func (r *witnessRecorder) Get(key []byte) ([]byte, error) {
+	val, err := r.Database.Get(key)
+	if err == nil {
+		r.mu.Lock()
+		r.reads[string(key)] = common.CopyBytes(val)
+		r.mu.Unlock()
+	}
+	return val, err
+}
+
+func (r *witnessRecorder) witness() *Witness {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w := new(Witness)
+	for _, key := range slices.Sorted(maps.Keys(r.reads)) {
+		val := r.reads[key]
+		switch isCode, _ := rawdb.IsCodeKey([]byte(key)); {
+		case isCode:
+			w.Codes = append(w.Codes, val)
+		case len(key) == common.HashLength:
+			w.Nodes = append(w.Nodes, val)
+		}
+	}
+	return w
+}
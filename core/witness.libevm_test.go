@@ -0,0 +1,135 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestProcessWithWitness(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	var (
+		eoa     = crypto.PubkeyToAddress(key.PublicKey)
+		counter = common.Address{'c', 'o', 'u', 'n', 't', 'e', 'r'}
+	)
+
+	gspec := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			eoa: {Balance: big.NewInt(params.Ether)},
+			counter: {
+				// slot[1] += 1
+				Code: []byte{
+					byte(vm.PUSH1), 1, byte(vm.SLOAD),
+					byte(vm.PUSH1), 1, byte(vm.ADD),
+					byte(vm.PUSH1), 1, byte(vm.SSTORE),
+					byte(vm.STOP),
+				},
+			},
+		},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	signer := types.LatestSigner(gspec.Config)
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 3, func(i int, b *core.BlockGen) {
+		b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    b.TxNonce(eoa),
+			To:       &counter,
+			Gas:      100_000,
+			GasPrice: b.BaseFee(),
+		}))
+		b.AddTx(types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    b.TxNonce(eoa),
+			To:       &common.Address{byte(i)},
+			Value:    big.NewInt(1),
+			Gas:      params.TxGas,
+			GasPrice: b.BaseFee(),
+		}))
+	})
+
+	db := rawdb.NewMemoryDatabase()
+	cache := core.DefaultCacheConfigWithScheme(rawdb.HashScheme)
+	cache.TrieDirtyDisabled = true // all historical state is required on disk
+	bc, err := core.NewBlockChain(db, cache, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err, "NewBlockChain()")
+	t.Cleanup(bc.Stop)
+	_, err = bc.InsertChain(blocks)
+	require.NoError(t, err, "InsertChain()")
+
+	proc := core.NewStateProcessor(gspec.Config, bc, bc.Engine())
+	block := blocks[len(blocks)-1]
+	parentRoot := blocks[len(blocks)-2].Root()
+
+	w, want, err := core.GenerateWitness(gspec.Config, proc, block, parentRoot, db, vm.Config{})
+	require.NoError(t, err, "GenerateWitness()")
+	require.Empty(t, want.Err, "GenerateWitness() processing error")
+	require.Equal(t, block.Root(), want.Root, "GenerateWitness() post-state root")
+	require.NotEmpty(t, w.Nodes, "witness nodes")
+	require.Len(t, w.Codes, 1, "witness codes")
+
+	t.Run("complete", func(t *testing.T) {
+		got, err := core.ProcessWithWitness(gspec.Config, proc, block, parentRoot, w, vm.Config{})
+		require.NoError(t, err, "ProcessWithWitness()")
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("wrong_root", func(t *testing.T) {
+		hdr := block.Header()
+		hdr.Root = common.Hash{'w', 'r', 'o', 'n', 'g'}
+		_, err := core.ProcessWithWitness(gspec.Config, proc, block.WithSeal(hdr), parentRoot, w, vm.Config{})
+		require.ErrorIs(t, err, core.ErrWitnessRootMismatch)
+	})
+
+	t.Run("wrong_receipt_root", func(t *testing.T) {
+		hdr := block.Header()
+		hdr.ReceiptHash = common.Hash{'w', 'r', 'o', 'n', 'g'}
+		_, err := core.ProcessWithWitness(gspec.Config, proc, block.WithSeal(hdr), parentRoot, w, vm.Config{})
+		require.ErrorIs(t, err, core.ErrWitnessBlockInvalid)
+	})
+
+	t.Run("invalid_block", func(t *testing.T) {
+		// Dropping the first transaction results in a nonce gap.
+		invalid := block.WithBody(types.Body{Transactions: block.Transactions()[1:]})
+		got, err := core.ProcessWithWitness(gspec.Config, proc, invalid, parentRoot, w, vm.Config{})
+		require.ErrorIs(t, err, core.ErrWitnessBlockInvalid)
+		assert.NotEmpty(t, got.Err, "BlockResult.Err")
+	})
+
+	for i := range w.Nodes {
+		incomplete := &core.Witness{
+			Nodes: append(append([][]byte{}, w.Nodes[:i]...), w.Nodes[i+1:]...),
+			Codes: w.Codes,
+		}
+		_, err := core.ProcessWithWitness(gspec.Config, proc, block, parentRoot, incomplete, vm.Config{})
+		require.ErrorIsf(t, err, core.ErrIncompleteWitness, "ProcessWithWitness() without node %d", i)
+	}
+	_, err = core.ProcessWithWitness(gspec.Config, proc, block, parentRoot, &core.Witness{Nodes: w.Nodes}, vm.Config{})
+	require.ErrorIs(t, err, core.ErrIncompleteWitness, "ProcessWithWitness() without code")
+}
@@ -70,6 +70,8 @@ type fetchResult struct {
 	Transactions types.Transactions
 	Receipts     types.Receipts
 	Withdrawals  types.Withdrawals
+
+	extraBody *types.Body // libevm: carries any extra payload into body()
 }
 
 func newFetchResult(header *types.Header, fastSync bool) *fetchResult {
@@ -89,11 +91,14 @@ func newFetchResult(header *types.Header, fastSync bool) *fetchResult {
 
 // body returns a representation of the fetch result as a types.Body object.
 func (f *fetchResult) body() types.Body {
-	return types.Body{
-		Transactions: f.Transactions,
-		Uncles:       f.Uncles,
-		Withdrawals:  f.Withdrawals,
+	var b types.Body
+	if f.extraBody != nil { // libevm
+		b = *f.extraBody
 	}
+	b.Transactions = f.Transactions
+	b.Uncles = f.Uncles
+	b.Withdrawals = f.Withdrawals
+	return b
 }
 
 // SetBodyDone flags the body as finished.
@@ -783,7 +788,9 @@ func (q *queue) DeliverHeaders(id string, headers []*types.Header, hashes []comm
 // also wakes any threads waiting for data delivery.
 func (q *queue) DeliverBodies(id string, txLists [][]*types.Transaction, txListHashes []common.Hash,
 	uncleLists [][]*types.Header, uncleListHashes []common.Hash,
-	withdrawalLists [][]*types.Withdrawal, withdrawalListHashes []common.Hash) (int, error) {
+	withdrawalLists [][]*types.Withdrawal, withdrawalListHashes []common.Hash,
+	bodies []*types.Body, // libevm: MAY be nil, otherwise carries extra payloads
+) (int, error) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -838,6 +845,12 @@ func (q *queue) DeliverBodies(id string, txLists [][]*types.Transaction, txListH
 				return errInvalidBody
 			}
 		}
+		// libevm: extra payloads aren't committed to by the roots checked above
+		if bodies != nil {
+			if err := bodies[index].VerifyExtra(header); err != nil {
+				return fmt.Errorf("%w: %v", errInvalidBody, err)
+			}
+		}
 		return nil
 	}
 
@@ -845,6 +858,9 @@ func (q *queue) DeliverBodies(id string, txLists [][]*types.Transaction, txListH
 		result.Transactions = txLists[index]
 		result.Uncles = uncleLists[index]
 		result.Withdrawals = withdrawalLists[index]
+		if bodies != nil { // libevm
+			result.extraBody = bodies[index]
+		}
 		result.SetBodyDone()
 	}
 	return q.deliver(id, q.blockTaskPool, q.blockTaskQueue, q.blockPendPool,
@@ -0,0 +1,114 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/trie"
+)
+
+// numberedBody is a body payload that is only valid for the header with the
+// same number.
+type numberedBody struct {
+	types.NOOPBlockBodyHooks
+	Number uint64
+}
+
+func (b *numberedBody) Copy() *numberedBody {
+	cp := *b
+	return &cp
+}
+
+var errWrongNumber = errors.New("wrong body number")
+
+func (b *numberedBody) VerifyBodyExtra(_ *types.Body, h *types.Header) error {
+	if b.Number != h.Number.Uint64() {
+		return errWrongNumber
+	}
+	return nil
+}
+
+func TestDeliverBodiesVerifiesExtras(t *testing.T) {
+	types.TestOnlyClearRegisteredExtras()
+	t.Cleanup(types.TestOnlyClearRegisteredExtras)
+	extras := types.RegisterExtras[
+		types.NOOPHeaderHooks, *types.NOOPHeaderHooks,
+		numberedBody, *numberedBody,
+		struct{},
+	]()
+
+	// The first block has a transaction, so its body is requested.
+	block := chain.blocks[0]
+	header := block.Header()
+	require.False(t, header.EmptyBody(), "%T.EmptyBody()", header)
+
+	tests := []struct {
+		name       string
+		bodyNumber uint64
+		wantErr    error
+	}{
+		{
+			name:       "valid",
+			bodyNumber: block.NumberU64(),
+		},
+		{
+			name:       "invalid",
+			bodyNumber: block.NumberU64() + 1,
+			wantErr:    errInvalidBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newQueue(10, 10)
+			q.Prepare(1, FullSync)
+			q.Schedule([]*types.Header{header}, []common.Hash{header.Hash()}, 1)
+
+			peer := dummyPeer("peer")
+			req, _, _ := q.ReserveBodies(peer, 1)
+			require.NotNil(t, req, "ReserveBodies()")
+			require.Len(t, req.Headers, 1, "ReserveBodies() headers")
+
+			body := block.Body()
+			extras.Body.Set(body, &numberedBody{Number: tt.bodyNumber})
+			var withdrawalsHash common.Hash
+			if h := header.WithdrawalsHash; h != nil {
+				withdrawalsHash = *h
+			}
+
+			accepted, err := q.DeliverBodies(
+				peer.id,
+				[][]*types.Transaction{body.Transactions}, []common.Hash{types.DeriveSha(types.Transactions(body.Transactions), trie.NewStackTrie(nil))},
+				[][]*types.Header{body.Uncles}, []common.Hash{types.CalcUncleHash(body.Uncles)},
+				[][]*types.Withdrawal{body.Withdrawals}, []common.Hash{withdrawalsHash},
+				[]*types.Body{body},
+			)
+			require.ErrorIs(t, err, tt.wantErr, "DeliverBodies()")
+			if tt.wantErr != nil {
+				require.Zero(t, accepted, "DeliverBodies() accepted")
+				return
+			}
+			require.Equal(t, 1, accepted, "DeliverBodies() accepted")
+		})
+	}
+}
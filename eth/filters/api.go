@@ -64,6 +64,7 @@ type FilterAPI struct {
 	filtersMu sync.Mutex
 	filters   map[rpc.ID]*filter
 	timeout   time.Duration
+	rpcSubs   rpcSubscriptions // libevm
 
 	quit chan struct{}
 }
@@ -169,6 +170,8 @@ func (api *FilterAPI) NewPendingTransactions(ctx context.Context, fullTx *bool)
 		defer pendingTxSub.Unsubscribe()
 
 		chainConfig := api.sys.backend.ChainConfig()
+		sub := api.newRPCSubscription("newPendingTransactions", notifier, rpcSub) // libevm
+		defer sub.close()                                                         // libevm
 
 		for {
 			select {
@@ -179,9 +182,9 @@ func (api *FilterAPI) NewPendingTransactions(ctx context.Context, fullTx *bool)
 				for _, tx := range txs {
 					if fullTx != nil && *fullTx {
 						rpcTx := ethapi.NewRPCPendingTransaction(tx, latest, chainConfig)
-						notifier.Notify(rpcSub.ID, rpcTx)
+						sub.notify(rpcTx) // libevm
 					} else {
-						notifier.Notify(rpcSub.ID, tx.Hash())
+						sub.notify(tx.Hash()) // libevm
 					}
 				}
 			case <-rpcSub.Err():
@@ -241,11 +244,13 @@ func (api *FilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 		headers := make(chan *types.Header)
 		headersSub := api.events.SubscribeNewHeads(headers)
 		defer headersSub.Unsubscribe()
+		sub := api.newRPCSubscription("newHeads", notifier, rpcSub) // libevm
+		defer sub.close()                                           // libevm
 
 		for {
 			select {
 			case h := <-headers:
-				notifier.Notify(rpcSub.ID, h)
+				sub.notify(h) // libevm
 			case <-rpcSub.Err():
 				return
 			case <-notifier.Closed():
@@ -274,14 +279,21 @@ func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subsc
 		return nil, err
 	}
 
+	if from := crit.FromBlock; from != nil && from.Sign() > 0 { // libevm: historical catch-up
+		go api.catchUpLogs(notifier, rpcSub, logsSub, matchedLogs, crit)
+		return rpcSub, nil
+	}
+
 	go func() {
 		defer logsSub.Unsubscribe()
+		sub := api.newRPCSubscription("logs", notifier, rpcSub) // libevm
+		defer sub.close()                                       // libevm
 		for {
 			select {
 			case logs := <-matchedLogs:
 				for _, log := range logs {
 					log := log
-					notifier.Notify(rpcSub.ID, &log)
+					sub.notify(&log) // libevm
 				}
 			case <-rpcSub.Err(): // client send an unsubscribe request
 				return
@@ -366,6 +378,7 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 		// Construct the range filter
 		filter = api.sys.NewRangeFilter(begin, end, crit.Addresses, crit.Topics)
 	}
+	filter.WithOrigins(crit.Origins...) // libevm
 	// Run the filter and return all the logs
 	logs, err := filter.Logs(ctx)
 	if err != nil {
@@ -417,6 +430,7 @@ func (api *FilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*types.Lo
 		// Construct the range filter
 		filter = api.sys.NewRangeFilter(begin, end, f.crit.Addresses, f.crit.Topics)
 	}
+	filter.WithOrigins(f.crit.Origins...) // libevm
 	// Run the filter and return all the logs
 	logs, err := filter.Logs(ctx)
 	if err != nil {
@@ -497,11 +511,12 @@ func returnLogs(logs []*types.Log) []*types.Log {
 // UnmarshalJSON sets *args fields with given data.
 func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 	type input struct {
-		BlockHash *common.Hash     `json:"blockHash"`
-		FromBlock *rpc.BlockNumber `json:"fromBlock"`
-		ToBlock   *rpc.BlockNumber `json:"toBlock"`
-		Addresses interface{}      `json:"address"`
-		Topics    []interface{}    `json:"topics"`
+		BlockHash *common.Hash      `json:"blockHash"`
+		FromBlock *rpc.BlockNumber  `json:"fromBlock"`
+		ToBlock   *rpc.BlockNumber  `json:"toBlock"`
+		Addresses interface{}       `json:"address"`
+		Topics    []interface{}     `json:"topics"`
+		Origins   []types.LogOrigin `json:"origins"` // libevm
 	}
 
 	var raw input
@@ -526,6 +541,7 @@ func (args *FilterCriteria) UnmarshalJSON(data []byte) error {
 	}
 
 	args.Addresses = []common.Address{}
+	args.Origins = raw.Origins // libevm
 
 	if raw.Addresses != nil {
 		// raw.Address can contain a single address or an array of addresses
@@ -16,9 +16,208 @@
 
 package filters
 
+import (
+	"context"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/log"
+	"github.com/ava-labs/libevm/rpc"
+)
+
 // CloseAPI releases resources held by the API.
 //
 // This is not implemented as a method to avoid exposing it via RPC.
 func CloseAPI(api *FilterAPI) {
 	close(api.quit)
 }
+
+// catchUpBatchSize is the maximum number of blocks queried at once when
+// delivering historical logs to a subscription.
+const catchUpBatchSize = 1024
+
+// catchUpLogs is the equivalent of the notification loop in [FilterAPI.Logs]
+// for subscriptions with a positive FromBlock. A FromBlock of zero is treated
+// as unset because ethclient, and therefore generated bindings, send it by
+// default; catching up from genesis is better served by eth_getLogs.
+// Historical logs, from FromBlock to the head at the time of subscription (or
+// ToBlock if lower), are delivered before any live logs, which are buffered in
+// the meantime.
+//
+// Live logs are received from `logsSub`, which MUST have been subscribed
+// before calling catchUpLogs, so every reorg that occurs during catch-up is
+// also observed as live events. These are reconciled against the historical
+// blocks actually delivered, so the mixed stream is consistent with a purely
+// live one: removal notifications are sent for, and only for, delivered logs
+// that are later orphaned, and logs are never delivered twice.
+func (api *FilterAPI) catchUpLogs(notifier *rpc.Notifier, rpcSub *rpc.Subscription, logsSub *Subscription, live <-chan []*types.Log, crit FilterCriteria) {
+	defer logsSub.Unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var head uint64
+	if hdr := api.sys.backend.CurrentHeader(); hdr != nil {
+		head = hdr.Number.Uint64()
+	}
+	cu := newLogsCatchUp(head)
+	if to := crit.ToBlock; to != nil && to.Sign() >= 0 && to.Uint64() < cu.head {
+		cu.head = to.Uint64()
+	}
+
+	historical := make(chan []*types.Log)
+	done := make(chan error, 1)
+	go func() {
+		done <- cu.fetch(ctx, api.sys, crit, historical)
+	}()
+
+	sub := api.newRPCSubscription("logs", notifier, rpcSub)
+	defer sub.close()
+	notify := func(logs []*types.Log) {
+		for _, l := range logs {
+			sub.notify(l)
+		}
+	}
+
+	var pending [][]*types.Log // live logs received during catch-up
+	catchingUp := true
+	for {
+		select {
+		case logs := <-historical:
+			cu.markDelivered(logs)
+			notify(logs)
+
+		case err := <-done:
+			if err != nil {
+				log.Warn("Historical log subscription catch-up failed", "from", crit.FromBlock, "err", err)
+				return
+			}
+			catchingUp = false
+			for _, logs := range pending {
+				notify(cu.filterLive(logs))
+			}
+			pending = nil
+
+		case logs := <-live:
+			if catchingUp {
+				pending = append(pending, logs)
+			} else {
+				notify(cu.filterLive(logs))
+			}
+
+		case <-rpcSub.Err(): // client send an unsubscribe request
+			return
+		case <-notifier.Closed(): // connection dropped
+			return
+		}
+	}
+}
+
+// logsCatchUp tracks the historical blocks for which logs were delivered to a
+// subscription, allowing live logs at or below `head` to be reconciled.
+type logsCatchUp struct {
+	head      uint64
+	delivered map[common.Hash]struct{}
+}
+
+func newLogsCatchUp(head uint64) *logsCatchUp {
+	return &logsCatchUp{
+		head:      head,
+		delivered: make(map[common.Hash]struct{}),
+	}
+}
+
+// fetch sends historical logs to `out`, one block per send. Immediately before
+// sending, each block's hash is compared against the canonical hash at its
+// height and, if they differ, the block is skipped as the replacement's logs
+// will be received as live events.
+//
+// Only [types.LogOriginTransaction] logs are available historically so, if
+// the criteria are restricted to other origins, no historical logs are sent.
+func (cu *logsCatchUp) fetch(ctx context.Context, sys *FilterSystem, crit FilterCriteria, out chan<- []*types.Log) error {
+	var origins []types.LogOrigin
+	if len(crit.Origins) > 0 {
+		if !includes(crit.Origins, types.LogOriginTransaction) {
+			return nil
+		}
+		origins = []types.LogOrigin{types.LogOriginTransaction}
+	}
+	for begin := crit.FromBlock.Uint64(); begin <= cu.head; begin += catchUpBatchSize {
+		end := min(begin+catchUpBatchSize-1, cu.head)
+		f := sys.NewRangeFilter(int64(begin), int64(end), crit.Addresses, crit.Topics).WithOrigins(origins...) //nolint:gosec // Bounded by head
+		logs, err := f.Logs(ctx)
+		if err != nil {
+			return err
+		}
+
+		for len(logs) > 0 {
+			n := 1
+			for n < len(logs) && logs[n].BlockHash == logs[0].BlockHash {
+				n++
+			}
+			block := logs[:n]
+			logs = logs[n:]
+
+			hdr, err := sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(block[0].BlockNumber)) //nolint:gosec // Block numbers are bounded by head
+			if err != nil {
+				return err
+			}
+			if hdr == nil || hdr.Hash() != block[0].BlockHash {
+				continue
+			}
+			select {
+			case out <- block:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if end == cu.head {
+			break // avoid overflow of `begin`
+		}
+	}
+	return nil
+}
+
+func (cu *logsCatchUp) markDelivered(logs []*types.Log) {
+	for _, l := range logs {
+		cu.delivered[l.BlockHash] = struct{}{}
+	}
+}
+
+// filterLive returns the subset of live `logs` that are consistent with the
+// historical logs already delivered, updating the tracked state accordingly.
+// Logs above the catch-up head are always returned. At or below it, removals
+// are only returned for delivered blocks, and additions only for undelivered
+// ones.
+func (cu *logsCatchUp) filterLive(logs []*types.Log) []*types.Log {
+	type decision struct{ keep, removed bool }
+	blocks := make(map[common.Hash]decision)
+
+	var out []*types.Log
+	for _, l := range logs {
+		if l.BlockNumber > cu.head {
+			out = append(out, l)
+			continue
+		}
+		d, ok := blocks[l.BlockHash]
+		if !ok {
+			_, delivered := cu.delivered[l.BlockHash]
+			d = decision{keep: l.Removed == delivered, removed: l.Removed}
+			blocks[l.BlockHash] = d
+		}
+		if d.keep {
+			out = append(out, l)
+		}
+	}
+
+	for h, d := range blocks {
+		switch {
+		case !d.keep:
+		case d.removed:
+			delete(cu.delivered, h)
+		default:
+			cu.delivered[h] = struct{}{}
+		}
+	}
+	return out
+}
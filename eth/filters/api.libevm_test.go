@@ -17,13 +17,25 @@
 package filters
 
 import (
+	"context"
+	"math/big"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/consensus/ethash"
 	"github.com/ava-labs/libevm/core"
 	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
 	"github.com/ava-labs/libevm/event"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/rpc"
 )
 
 // A closeableTestBackend tracks all subscriptions that it produces, allowing
@@ -55,3 +67,150 @@ func TestClose(t *testing.T) {
 	api := NewFilterAPI(sys, false)
 	CloseAPI(api)
 }
+
+func TestLogsCatchUpFilterLive(t *testing.T) {
+	const head = 10
+	var (
+		delivered = common.Hash{'d'}
+		orphan    = common.Hash{'o'}
+	)
+	logAt := func(hash common.Hash, num uint64, removed bool) *types.Log {
+		return &types.Log{BlockHash: hash, BlockNumber: num, Removed: removed}
+	}
+
+	cu := newLogsCatchUp(head)
+	cu.markDelivered([]*types.Log{logAt(delivered, 5, false)})
+
+	steps := []struct {
+		name string
+		live []*types.Log
+		want []*types.Log
+	}{
+		{
+			name: "duplicate_of_delivered",
+			live: []*types.Log{logAt(delivered, 5, false), logAt(delivered, 5, false)},
+			want: nil,
+		},
+		{
+			name: "removal_of_undelivered",
+			live: []*types.Log{logAt(orphan, 5, true)},
+			want: nil,
+		},
+		{
+			name: "removal_of_delivered",
+			live: []*types.Log{logAt(delivered, 5, true), logAt(delivered, 5, true)},
+			want: []*types.Log{logAt(delivered, 5, true), logAt(delivered, 5, true)},
+		},
+		{
+			name: "repeat_removal",
+			live: []*types.Log{logAt(delivered, 5, true)},
+			want: nil,
+		},
+		{
+			name: "readdition_after_removal",
+			live: []*types.Log{logAt(delivered, 5, false)},
+			want: []*types.Log{logAt(delivered, 5, false)},
+		},
+		{
+			name: "above_head",
+			live: []*types.Log{logAt(orphan, head+1, true), logAt(delivered, head+1, false)},
+			want: []*types.Log{logAt(orphan, head+1, true), logAt(delivered, head+1, false)},
+		},
+	}
+	for _, s := range steps {
+		assert.Equalf(t, s.want, cu.filterLive(s.live), "%s: filterLive()", s.name)
+	}
+}
+
+func TestLogsSubscriptionCatchUp(t *testing.T) {
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		api          = NewFilterAPI(sys, false)
+
+		key, _  = crypto.GenerateKey()
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		emitter = common.Address{'e'}
+		signer  = types.HomesteadSigner{}
+		genesis = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}},
+		}
+	)
+	t.Cleanup(func() { CloseAPI(api) })
+
+	const numBlocks = 4
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), numBlocks, func(i int, b *core.BlockGen) {
+		r := &types.Receipt{Logs: []*types.Log{{Address: emitter, Topics: []common.Hash{}, Data: []byte{}}}}
+		r.Bloom = types.CreateBloom(types.Receipts{r})
+		b.AddUncheckedReceipt(r)
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: uint64(i), To: &common.Address{}, Gas: params.TxGas, GasPrice: b.BaseFee()}), signer, key) //nolint:gosec // Non-negative index
+		require.NoError(t, err, "types.SignTx()")
+		b.AddTx(tx)
+	})
+	for _, block := range blocks {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), []*types.Receipt{{
+			Logs: []*types.Log{{Address: emitter, Topics: []common.Hash{}, Data: []byte{}}},
+		}})
+	}
+
+	server := rpc.NewServer()
+	t.Cleanup(server.Stop)
+	require.NoError(t, server.RegisterName("eth", api), "%T.RegisterName()", server)
+	client := rpc.DialInProc(server)
+	t.Cleanup(client.Close)
+
+	const from = 2
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	got := make(chan types.Log, 16)
+	sub, err := client.EthSubscribe(ctx, got, "logs", map[string]any{
+		"fromBlock": hexutil.Uint64(from),
+		"address":   emitter,
+	})
+	require.NoError(t, err, "eth_subscribe logs")
+	t.Cleanup(sub.Unsubscribe)
+
+	recv := func(t *testing.T) types.Log {
+		t.Helper()
+		select {
+		case l := <-got:
+			return l
+		case err := <-sub.Err():
+			t.Fatalf("subscription error: %v", err)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for log")
+		}
+		return types.Log{}
+	}
+
+	var historical []types.Log
+	for _, b := range blocks[from-1:] {
+		l := recv(t)
+		assert.Equal(t, b.Hash(), l.BlockHash, "historical log BlockHash")
+		assert.False(t, l.Removed, "historical log Removed")
+		historical = append(historical, l)
+	}
+
+	dup := historical[0]
+	removed := historical[1]
+	removed.Removed = true
+	orphaned := types.Log{Address: emitter, Topics: []common.Hash{}, Data: []byte{}, BlockNumber: removed.BlockNumber, BlockHash: common.Hash{'o'}, Removed: true}
+	next := types.Log{Address: emitter, Topics: []common.Hash{}, Data: []byte{}, BlockNumber: numBlocks + 1, BlockHash: common.Hash{'n'}}
+
+	backend.logsFeed.Send([]*types.Log{&dup})                                     // already delivered
+	backend.rmLogsFeed.Send(core.RemovedLogsEvent{Logs: []*types.Log{&orphaned}}) // never delivered
+	backend.rmLogsFeed.Send(core.RemovedLogsEvent{Logs: []*types.Log{&removed}})  // delivered then orphaned
+	backend.logsFeed.Send([]*types.Log{&next})                                    // live
+
+	// The two feeds are independent so there is no guaranteed order.
+	assert.ElementsMatch(t, []types.Log{removed, next}, []types.Log{recv(t), recv(t)}, "removal of delivered historical log and live log")
+	select {
+	case l := <-got:
+		t.Errorf("unexpected log %+v", l)
+	default:
+	}
+}
@@ -33,6 +33,7 @@ type Filter struct {
 
 	addresses []common.Address
 	topics    [][]common.Hash
+	origins   []types.LogOrigin // libevm: see [Filter.WithOrigins]
 
 	block      *common.Hash // Block hash if filtering a single block
 	begin, end int64        // Range interval if filtering multiple blocks
@@ -95,6 +96,9 @@ func newFilter(sys *FilterSystem, addresses []common.Address, topics [][]common.
 // Logs searches the blockchain for matching log entries, returning all from the
 // first block that contains matches, updating the start of the filter accordingly.
 func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
+	if err := f.checkHistoricalOrigins(); err != nil { // libevm
+		return nil, err
+	}
 	// If we're doing singleton block filtering, execute and return
 	if f.block != nil {
 		header, err := f.sys.backend.HeaderByHash(ctx, *f.block)
@@ -309,7 +313,7 @@ func (f *Filter) checkMatches(ctx context.Context, header *types.Header) ([]*typ
 	if err != nil {
 		return nil, err
 	}
-	logs := filterLogs(cached.logs, nil, nil, f.addresses, f.topics)
+	logs := filterLogs(cached.logs, nil, nil, f.addresses, f.topics, f.origins...) // libevm: origins
 	if len(logs) == 0 {
 		return nil, nil
 	}
@@ -342,7 +346,7 @@ func (f *Filter) pendingLogs() []*types.Log {
 		for _, r := range receipts {
 			unfiltered = append(unfiltered, r.Logs...)
 		}
-		return filterLogs(unfiltered, nil, nil, f.addresses, f.topics)
+		return filterLogs(unfiltered, nil, nil, f.addresses, f.topics, f.origins...) // libevm: origins
 	}
 	return nil
 }
@@ -358,8 +362,11 @@ func includes[T comparable](things []T, element T) bool {
 }
 
 // filterLogs creates a slice of logs matching the given criteria.
-func filterLogs(logs []*types.Log, fromBlock, toBlock *big.Int, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+func filterLogs(logs []*types.Log, fromBlock, toBlock *big.Int, addresses []common.Address, topics [][]common.Hash, origins ...types.LogOrigin) []*types.Log { // libevm: origins
 	var check = func(log *types.Log) bool {
+		if len(origins) > 0 && !includes(origins, log.Origin) { // libevm
+			return false
+		}
 		if fromBlock != nil && fromBlock.Int64() >= 0 && fromBlock.Uint64() > log.BlockNumber {
 			return false
 		}
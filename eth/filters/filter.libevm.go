@@ -0,0 +1,51 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/libevm/core/types"
+)
+
+// WithOrigins restricts the logs returned by [Filter.Logs] to those with any of
+// the specified [types.LogOrigin] values. If no origins are specified then
+// logs of all origins are matched, which is the default. WithOrigins returns
+// the receiver to allow chaining.
+//
+// Only [types.LogOriginTransaction] logs are persisted, via their receipts, so
+// [Filter.Logs] returns [ErrNonTransactionOrigin] if any other origin is
+// specified. Logs of all origins are delivered to live subscriptions.
+func (f *Filter) WithOrigins(origins ...types.LogOrigin) *Filter {
+	f.origins = origins
+	return f
+}
+
+// ErrNonTransactionOrigin is returned by [Filter.Logs] if the filter matches
+// a [types.LogOrigin] other than [types.LogOriginTransaction]. Such logs are
+// only delivered to live subscriptions.
+var ErrNonTransactionOrigin = errors.New("historical logs are only available for transaction origin")
+
+func (f *Filter) checkHistoricalOrigins() error {
+	for _, o := range f.origins {
+		if o != types.LogOriginTransaction {
+			return fmt.Errorf("%w: %v", ErrNonTransactionOrigin, o)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus/beacon"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/event"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestFilterLogsByOrigin(t *testing.T) {
+	var (
+		txLog     = &types.Log{Address: common.Address{1}}
+		sysLog    = &types.Log{Address: common.Address{2}, Origin: types.LogOriginSystemCall}
+		postBlock = &types.Log{Address: common.Address{3}, Origin: types.LogOriginPostBlock}
+		all       = []*types.Log{txLog, sysLog, postBlock}
+	)
+
+	tests := []struct {
+		name      string
+		origins   []types.LogOrigin
+		addresses []common.Address
+		want      []*types.Log
+	}{
+		{
+			name: "default_all",
+			want: all,
+		},
+		{
+			name:    "transaction",
+			origins: []types.LogOrigin{types.LogOriginTransaction},
+			want:    []*types.Log{txLog},
+		},
+		{
+			name:    "system_and_post_block",
+			origins: []types.LogOrigin{types.LogOriginSystemCall, types.LogOriginPostBlock},
+			want:    []*types.Log{sysLog, postBlock},
+		},
+		{
+			name:      "combined_with_address",
+			origins:   []types.LogOrigin{types.LogOriginSystemCall, types.LogOriginPostBlock},
+			addresses: []common.Address{{3}},
+			want:      []*types.Log{postBlock},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterLogs(all, nil, nil, tt.addresses, nil, tt.origins...)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type postBlockLogger struct {
+	core.NOOPProcessorHooks
+	log *types.Log
+}
+
+func (h postBlockLogger) AfterBlock(sdb vm.StateDB, _ *types.Block, _ types.Receipts) {
+	l := *h.log // AddLog() modifies its argument, which may be reused across blocks
+	sdb.AddLog(&l)
+}
+
+func TestLogOriginsFromEmittingPaths(t *testing.T) {
+	var (
+		sysAddr   = params.BeaconRootsStorageAddress
+		txAddr    = common.Address{'t', 'x'}
+		postBlock = &types.Log{Address: common.Address{'p', 'o', 's', 't'}}
+	)
+	core.RegisterProcessorHooks(postBlockLogger{log: postBlock})
+	t.Cleanup(core.TestOnlyClearProcessorHooks)
+
+	sdb, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		BlockNumber: big.NewInt(1),
+	}))
+	sdb.SetCode(sysAddr, []byte{
+		byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.LOG0), byte(vm.STOP),
+	})
+
+	core.ProcessBeaconBlockRoot(common.Hash{}, evm, sdb)
+	sdb.AddLog(&types.Log{Address: txAddr})
+	core.ProcessAfterBlock(sdb, types.NewBlockWithHeader(&types.Header{}), nil)
+	sdb.AddLog(&types.Log{Address: txAddr})
+
+	logs := sdb.Logs()
+	require.Len(t, logs, 4, "%T.Logs()", sdb)
+
+	tests := []struct {
+		origin types.LogOrigin
+		want   []common.Address
+	}{
+		{types.LogOriginTransaction, []common.Address{txAddr, txAddr}},
+		{types.LogOriginSystemCall, []common.Address{sysAddr}},
+		{types.LogOriginPostBlock, []common.Address{postBlock.Address}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin.String(), func(t *testing.T) {
+			var got []common.Address
+			for _, l := range filterLogs(logs, nil, nil, nil, nil, tt.origin) {
+				got = append(got, l.Address)
+			}
+			assert.Equal(t, tt.want, got, "addresses of filtered logs")
+		})
+	}
+}
+
+// A chainLogsBackend delivers the logs of a [core.BlockChain] to a
+// [FilterSystem].
+type chainLogsBackend struct {
+	*testBackend
+	chain *core.BlockChain
+}
+
+func (b *chainLogsBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return b.chain.SubscribeLogsEvent(ch)
+}
+
+func TestNonTransactionLogsFromBlockChain(t *testing.T) {
+	var (
+		sysAddr   = params.BeaconRootsStorageAddress
+		postBlock = &types.Log{Address: common.Address{'p', 'o', 's', 't'}, Topics: []common.Hash{}, Data: []byte{}}
+	)
+	core.RegisterProcessorHooks(postBlockLogger{log: postBlock})
+	t.Cleanup(core.TestOnlyClearProcessorHooks)
+
+	gspec := &core.Genesis{
+		Config: params.MergedTestChainConfig,
+		Alloc: types.GenesisAlloc{
+			sysAddr: {Code: []byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.LOG0), byte(vm.STOP)}},
+		},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	engine := beacon.New(ethash.NewFaker())
+	const numBlocks = 2
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, engine, numBlocks, func(_ int, b *core.BlockGen) {
+		b.SetPoS()
+		b.SetParentBeaconRoot(common.Hash{})
+	})
+
+	db := rawdb.NewMemoryDatabase()
+	chain, err := core.NewBlockChain(db, nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	require.NoError(t, err, "core.NewBlockChain()")
+	t.Cleanup(chain.Stop)
+
+	backend := &chainLogsBackend{testBackend: &testBackend{db: db}, chain: chain}
+	api := NewFilterAPI(NewFilterSystem(backend, Config{}), false)
+	t.Cleanup(func() { CloseAPI(api) })
+
+	id, err := api.NewFilter(FilterCriteria{
+		Origins: []types.LogOrigin{types.LogOriginSystemCall, types.LogOriginPostBlock},
+	})
+	require.NoError(t, err, "%T.NewFilter()", api)
+
+	_, err = chain.InsertChain(blocks)
+	require.NoError(t, err, "%T.InsertChain()", chain)
+
+	type origin struct {
+		Address common.Address
+		Origin  types.LogOrigin
+		Number  uint64
+	}
+	var want []origin
+	for _, b := range blocks {
+		want = append(want,
+			origin{sysAddr, types.LogOriginSystemCall, b.NumberU64()},
+			origin{postBlock.Address, types.LogOriginPostBlock, b.NumberU64()},
+		)
+	}
+
+	var got []origin
+	require.Eventually(t, func() bool {
+		changes, err := api.GetFilterChanges(id)
+		require.NoError(t, err, "%T.GetFilterChanges()", api)
+		for _, l := range changes.([]*types.Log) {
+			got = append(got, origin{l.Address, l.Origin, l.BlockNumber})
+		}
+		return len(got) >= len(want)
+	}, 5*time.Second, 10*time.Millisecond, "non-transaction logs delivered to filter")
+	assert.Equal(t, want, got, "non-transaction logs delivered to filter")
+
+	t.Run("historical", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := api.GetLogs(ctx, FilterCriteria{
+			FromBlock: big.NewInt(0),
+			Origins:   []types.LogOrigin{types.LogOriginPostBlock},
+		})
+		require.ErrorIsf(t, err, ErrNonTransactionOrigin, "%T.GetLogs() with non-transaction origin", api)
+
+		logs, err := api.GetLogs(ctx, FilterCriteria{
+			FromBlock: big.NewInt(0),
+			Origins:   []types.LogOrigin{types.LogOriginTransaction},
+		})
+		require.NoErrorf(t, err, "%T.GetLogs() with transaction origin", api)
+		assert.Empty(t, logs, "%T.GetLogs() with transaction origin", api)
+	})
+}
+
+func TestFilterCriteriaOriginsJSON(t *testing.T) {
+	var crit FilterCriteria
+	require.NoError(t, json.Unmarshal([]byte(`{"origins":["systemCall","postBlock"]}`), &crit))
+	assert.Equal(t, []types.LogOrigin{types.LogOriginSystemCall, types.LogOriginPostBlock}, crit.Origins)
+
+	crit = FilterCriteria{}
+	require.NoError(t, json.Unmarshal([]byte(`{}`), &crit))
+	assert.Empty(t, crit.Origins, "default")
+
+	assert.Error(t, json.Unmarshal([]byte(`{"origins":["unknown"]}`), &crit), "unknown origin")
+}
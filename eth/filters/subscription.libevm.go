@@ -0,0 +1,265 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/libevm/metrics"
+	"github.com/ava-labs/libevm/rpc"
+)
+
+// An OverflowPolicy determines how a subscription handles a notification when
+// its buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the subscription's event loop until there is space
+	// in the buffer, which is equivalent to the upstream geth behaviour.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop discards the notification.
+	OverflowDrop
+	// OverflowDisconnect discards the notification and closes the client's
+	// connection.
+	OverflowDisconnect
+)
+
+// SubscriptionConfig configures the buffering of notifications sent to RPC
+// subscriptions (e.g. over WebSocket). The zero value retains the upstream
+// geth behaviour of sending each notification synchronously.
+type SubscriptionConfig struct {
+	BufferSize int            // maximum number of pending notifications per subscription (0 for unbuffered)
+	Overflow   OverflowPolicy // behaviour when the buffer is full; ignored if unbuffered
+}
+
+var (
+	subscriptionDroppedMeter    = metrics.NewRegisteredMeter("eth/filters/subscriptions/dropped", nil)
+	subscriptionDisconnectMeter = metrics.NewRegisteredMeter("eth/filters/subscriptions/disconnects", nil)
+	subscriptionBufferedGauge   = metrics.NewRegisteredGauge("eth/filters/subscriptions/buffered", nil)
+	subscriptionActiveGauge     = metrics.NewRegisteredGauge("eth/filters/subscriptions/active", nil)
+)
+
+// An rpcSubscription sends notifications to a single RPC subscription,
+// respecting the [SubscriptionConfig] of the [FilterAPI] that created it.
+type rpcSubscription struct {
+	typ      string
+	created  time.Time
+	notifier *rpc.Notifier
+	sub      *rpc.Subscription
+	overflow OverflowPolicy
+	reg      *rpcSubscriptions
+
+	queue      chan notification // nil if unbuffered
+	done       chan struct{}
+	disconnect sync.Once
+
+	sent, dropped atomic.Uint64
+	inFlight      atomic.Int64 // enqueue time, in Unix nanoseconds, of the notification being sent; 0 if none
+}
+
+type notification struct {
+	data any
+	at   time.Time
+}
+
+// newRPCSubscription registers a new subscription of the named type with the
+// API. The returned value's close() method MUST be called when the
+// subscription ends.
+func (api *FilterAPI) newRPCSubscription(typ string, notifier *rpc.Notifier, sub *rpc.Subscription) *rpcSubscription {
+	cfg := api.sys.cfg.Subscriptions
+	s := &rpcSubscription{
+		typ:      typ,
+		created:  time.Now(),
+		notifier: notifier,
+		sub:      sub,
+		overflow: cfg.Overflow,
+		reg:      &api.rpcSubs,
+		done:     make(chan struct{}),
+	}
+	if cfg.BufferSize > 0 {
+		s.queue = make(chan notification, cfg.BufferSize)
+		go s.sendLoop()
+	}
+	s.reg.add(s)
+	return s
+}
+
+// notify sends `data` to the subscriber, either immediately or via the buffer.
+func (s *rpcSubscription) notify(data any) {
+	n := notification{data: data, at: time.Now()}
+	if s.queue == nil {
+		s.send(n)
+		return
+	}
+
+	select {
+	case s.queue <- n:
+		subscriptionBufferedGauge.Inc(1)
+		return
+	default:
+	}
+
+	switch s.overflow {
+	case OverflowDrop:
+		s.drop()
+	case OverflowDisconnect:
+		s.drop()
+		s.disconnect.Do(func() {
+			subscriptionDisconnectMeter.Mark(1)
+			s.notifier.CloseConnection()
+		})
+	default:
+		select {
+		case s.queue <- n:
+			subscriptionBufferedGauge.Inc(1)
+		case <-s.sub.Err():
+		case <-s.notifier.Closed():
+		}
+	}
+}
+
+func (s *rpcSubscription) drop() {
+	s.dropped.Add(1)
+	subscriptionDroppedMeter.Mark(1)
+}
+
+func (s *rpcSubscription) send(n notification) {
+	s.inFlight.Store(n.at.UnixNano())
+	s.notifier.Notify(s.sub.ID, n.data)
+	s.inFlight.Store(0)
+	s.sent.Add(1)
+}
+
+func (s *rpcSubscription) sendLoop() {
+	for {
+		select {
+		case n := <-s.queue:
+			subscriptionBufferedGauge.Dec(1)
+			s.send(n)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// close stops the sending of buffered notifications, discarding any that
+// remain, and deregisters the subscription.
+func (s *rpcSubscription) close() {
+	close(s.done)
+	s.reg.remove(s)
+	if s.queue == nil {
+		return
+	}
+	for {
+		select {
+		case <-s.queue:
+			subscriptionBufferedGauge.Dec(1)
+		default:
+			return
+		}
+	}
+}
+
+// SubscriptionStatus describes an active RPC subscription.
+type SubscriptionStatus struct {
+	ID       rpc.ID        `json:"id"`
+	Type     string        `json:"type"`
+	Created  time.Time     `json:"created"`
+	Sent     uint64        `json:"sent"`
+	Dropped  uint64        `json:"dropped"`
+	Buffered int           `json:"buffered"` // notifications waiting to be sent
+	Lag      time.Duration `json:"lag"`      // nanoseconds for which the notification currently being sent has been pending
+}
+
+func (s *rpcSubscription) status(now time.Time) SubscriptionStatus {
+	st := SubscriptionStatus{
+		ID:       s.sub.ID,
+		Type:     s.typ,
+		Created:  s.created,
+		Sent:     s.sent.Load(),
+		Dropped:  s.dropped.Load(),
+		Buffered: len(s.queue),
+	}
+	if at := s.inFlight.Load(); at != 0 {
+		st.Lag = now.Sub(time.Unix(0, at))
+	}
+	return st
+}
+
+// rpcSubscriptions is the set of active subscriptions of a [FilterAPI]. Its
+// zero value is ready to use.
+type rpcSubscriptions struct {
+	mu   sync.Mutex
+	subs map[*rpcSubscription]struct{}
+}
+
+func (r *rpcSubscriptions) add(s *rpcSubscription) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.subs == nil {
+		r.subs = make(map[*rpcSubscription]struct{})
+	}
+	r.subs[s] = struct{}{}
+	subscriptionActiveGauge.Inc(1)
+}
+
+func (r *rpcSubscriptions) remove(s *rpcSubscription) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subs, s)
+	subscriptionActiveGauge.Dec(1)
+}
+
+func (r *rpcSubscriptions) status() []SubscriptionStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	st := make([]SubscriptionStatus, 0, len(r.subs))
+	for s := range r.subs {
+		st = append(st, s.status(now))
+	}
+	sort.Slice(st, func(i, j int) bool {
+		return st[i].Created.Before(st[j].Created)
+	})
+	return st
+}
+
+// SubscriptionStatusNamespace is the RPC namespace under which a
+// [SubscriptionStatusAPI] is expected to be registered, making it available
+// as `subscription_status`.
+const SubscriptionStatusNamespace = "subscription"
+
+// SubscriptionStatusAPI is a debugging API that reports on the RPC
+// subscriptions of a [FilterAPI].
+type SubscriptionStatusAPI struct {
+	api *FilterAPI
+}
+
+// NewSubscriptionStatusAPI returns an API reporting on the subscriptions of
+// `api`.
+func NewSubscriptionStatusAPI(api *FilterAPI) *SubscriptionStatusAPI {
+	return &SubscriptionStatusAPI{api}
+}
+
+// Status returns the status of all active subscriptions, ordered by creation.
+func (s *SubscriptionStatusAPI) Status() []SubscriptionStatus {
+	return s.api.rpcSubs.status()
+}
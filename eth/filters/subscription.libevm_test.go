@@ -0,0 +1,132 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/rpc"
+)
+
+// subscribeWithoutReading subscribes to new heads over a [net.Pipe] and then
+// stops reading from the connection, simulating a client that can't keep up.
+func subscribeWithoutReading(t *testing.T, api *FilterAPI) net.Conn {
+	t.Helper()
+
+	srv := rpc.NewServer()
+	t.Cleanup(srv.Stop)
+	require.NoError(t, srv.RegisterName("eth", api), "RegisterName()")
+
+	server, client := net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	go srv.ServeCodec(rpc.NewCodec(server), 0)
+
+	_, err := client.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_subscribe","params":["newHeads"]}`))
+	require.NoError(t, err, "writing eth_subscribe request")
+	var resp struct {
+		Result rpc.ID `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(client).Decode(&resp), "decoding eth_subscribe response")
+	require.NotEmpty(t, resp.Result, "subscription ID")
+
+	status := NewSubscriptionStatusAPI(api)
+	require.Eventually(t, func() bool {
+		return len(status.Status()) == 1
+	}, time.Second, 5*time.Millisecond, "subscription registered")
+	return client
+}
+
+func sendHeads(backend *testBackend, n int) {
+	for i := 0; i < n; i++ {
+		backend.chainFeed.Send(core.ChainEvent{
+			Block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i))}),
+		})
+	}
+}
+
+func TestSubscriptionOverflowDrop(t *testing.T) {
+	const (
+		bufferSize = 2
+		numHeads   = 10
+	)
+	backend, sys := newTestFilterSystem(t, rawdb.NewMemoryDatabase(), Config{
+		Subscriptions: SubscriptionConfig{
+			BufferSize: bufferSize,
+			Overflow:   OverflowDrop,
+		},
+	})
+	api := NewFilterAPI(sys, false)
+	t.Cleanup(func() { CloseAPI(api) })
+	status := NewSubscriptionStatusAPI(api)
+
+	subscribeWithoutReading(t, api)
+	// Wait for one notification to be blocked on the write to the client
+	// before filling the buffer, so the rest are deterministically dropped.
+	sendHeads(backend, 1)
+	require.Eventually(t, func() bool {
+		return status.Status()[0].Lag > 0
+	}, time.Second, time.Millisecond, "first notification in flight")
+	sendHeads(backend, numHeads-1)
+
+	want := SubscriptionStatus{
+		Type:     "newHeads",
+		Buffered: bufferSize,
+		Dropped:  numHeads - bufferSize - 1,
+	}
+	var got SubscriptionStatus
+	require.Eventuallyf(t, func() bool {
+		got = status.Status()[0]
+		return got.Dropped == want.Dropped
+	}, time.Second, 5*time.Millisecond, "%T.Dropped", got)
+
+	assert.Equal(t, want.Type, got.Type, "Type")
+	assert.Equal(t, want.Buffered, got.Buffered, "Buffered")
+	assert.Zero(t, got.Sent, "Sent")
+	assert.Positive(t, got.Lag, "Lag")
+}
+
+func TestSubscriptionOverflowDisconnect(t *testing.T) {
+	backend, sys := newTestFilterSystem(t, rawdb.NewMemoryDatabase(), Config{
+		Subscriptions: SubscriptionConfig{
+			BufferSize: 1,
+			Overflow:   OverflowDisconnect,
+		},
+	})
+	api := NewFilterAPI(sys, false)
+	t.Cleanup(func() { CloseAPI(api) })
+	status := NewSubscriptionStatusAPI(api)
+
+	client := subscribeWithoutReading(t, api)
+	sendHeads(backend, 3)
+
+	require.Eventually(t, func() bool {
+		return len(status.Status()) == 0
+	}, time.Second, 5*time.Millisecond, "subscription removed after disconnect")
+	_, err := io.ReadAll(client)
+	require.NoError(t, err, "reading until EOF")
+}
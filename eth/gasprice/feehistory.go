@@ -89,6 +89,7 @@ func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
 		bf.results.nextBaseFee = new(big.Int)
 	}
 	bf.results.gasUsedRatio = float64(bf.header.GasUsed) / float64(bf.header.GasLimit)
+	overridden := oracle.overrideFeeHistory(bf) // libevm
 	if len(percentiles) == 0 {
 		// rewards were not requested, return null
 		return
@@ -107,9 +108,13 @@ func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
 		return
 	}
 
+	tipBaseFee := bf.block.BaseFee()
+	if overridden {
+		tipBaseFee = bf.results.baseFee
+	}
 	sorter := make([]txGasAndReward, len(bf.block.Transactions()))
 	for i, tx := range bf.block.Transactions() {
-		reward, _ := tx.EffectiveGasTip(bf.block.BaseFee())
+		reward, _ := tx.EffectiveGasTip(tipBaseFee)
 		sorter[i] = txGasAndReward{gasUsed: bf.receipts[i].GasUsed, reward: reward}
 	}
 	slices.SortStableFunc(sorter, func(a, b txGasAndReward) int {
@@ -0,0 +1,62 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"math/big"
+
+	"github.com/ava-labs/libevm/core/types"
+)
+
+// FeeHistoryOverrider is an optional extension to [OracleBackend], allowing
+// chains that don't store fee parameters in upstream [types.Header] fields
+// (e.g. those using header extras) to provide them to [Oracle.FeeHistory]. If
+// not implemented, the upstream header fields and EIP-1559 calculations are
+// used instead.
+//
+// The base fee returned for a block is also used when computing the effective
+// tips from which reward percentiles are derived.
+type FeeHistoryOverrider interface {
+	// FeeHistoryBaseFee returns the base fee of the block with the header. A
+	// nil return value is reported as zero.
+	FeeHistoryBaseFee(*types.Header) *big.Int
+	// FeeHistoryNextBaseFee returns the base fee of the child of the block
+	// with the header. A nil return value is reported as zero.
+	FeeHistoryNextBaseFee(*types.Header) *big.Int
+	// FeeHistoryGasUsedRatio returns the ratio of gas used to the gas limit
+	// of the block with the header.
+	FeeHistoryGasUsedRatio(*types.Header) float64
+}
+
+// overrideFeeHistory replaces the base fees and gas-used ratio in `bf.results`
+// if the backend is a [FeeHistoryOverrider], reporting whether it did so.
+func (oracle *Oracle) overrideFeeHistory(bf *blockFees) bool {
+	o, ok := oracle.backend.(FeeHistoryOverrider)
+	if !ok {
+		return false
+	}
+	nonNil := func(x *big.Int) *big.Int {
+		if x == nil {
+			return new(big.Int)
+		}
+		return x
+	}
+	bf.results.baseFee = nonNil(o.FeeHistoryBaseFee(bf.header))
+	bf.results.nextBaseFee = nonNil(o.FeeHistoryNextBaseFee(bf.header))
+	bf.results.gasUsedRatio = o.FeeHistoryGasUsedRatio(bf.header)
+	return true
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/rpc"
+)
+
+type feeHistoryOverrideBackend struct {
+	*testBackend
+}
+
+var _ FeeHistoryOverrider = feeHistoryOverrideBackend{}
+
+// overriddenBaseFee is sufficiently high that the effective tip of all test
+// transactions is capped at 1 gwei by their fee cap of 100 gwei.
+var overriddenBaseFee = big.NewInt(99 * params.GWei)
+
+func (feeHistoryOverrideBackend) FeeHistoryBaseFee(*types.Header) *big.Int {
+	return overriddenBaseFee
+}
+
+func (feeHistoryOverrideBackend) FeeHistoryNextBaseFee(h *types.Header) *big.Int {
+	return new(big.Int).Add(overriddenBaseFee, h.Number)
+}
+
+func (feeHistoryOverrideBackend) FeeHistoryGasUsedRatio(h *types.Header) float64 {
+	return float64(h.Number.Uint64()) / 100
+}
+
+func TestFeeHistoryOverrider(t *testing.T) {
+	const (
+		last  = 30
+		count = 4
+	)
+	percentiles := []float64{0, 50, 100}
+
+	backend := feeHistoryOverrideBackend{newTestBackend(t, big.NewInt(0), false)}
+	t.Cleanup(backend.teardown)
+	oracle := NewOracle(backend, Config{
+		MaxHeaderHistory: 1000,
+		MaxBlockHistory:  1000,
+	})
+
+	first, reward, baseFee, ratio, err := oracle.FeeHistory(context.Background(), count, last, percentiles)
+	require.NoError(t, err, "FeeHistory()")
+	require.Equal(t, uint64(last-count+1), first.Uint64(), "first block")
+	require.Len(t, reward, count)
+	require.Len(t, baseFee, count+1)
+	require.Len(t, ratio, count)
+
+	oneGwei := big.NewInt(params.GWei)
+	for i := 0; i < count; i++ {
+		num := first.Uint64() + uint64(i)
+		assert.Equalf(t, overriddenBaseFee, baseFee[i], "base fee of block %d", num)
+		assert.Equalf(t, float64(num)/100, ratio[i], "gas-used ratio of block %d", num)
+		for j, r := range reward[i] {
+			assert.Equalf(t, oneGwei, r, "reward percentile %v of block %d", percentiles[j], num)
+		}
+	}
+	wantNext := new(big.Int).Add(overriddenBaseFee, big.NewInt(last))
+	assert.Equal(t, wantNext, baseFee[count], "next base fee after last block")
+
+	t.Run("default_unchanged", func(t *testing.T) {
+		oracle := NewOracle(backend.testBackend, Config{MaxHeaderHistory: 1000, MaxBlockHistory: 1000})
+		_, reward, baseFee, _, err := oracle.FeeHistory(context.Background(), count, last, percentiles)
+		require.NoError(t, err, "FeeHistory()")
+		hdr, err := backend.HeaderByNumber(context.Background(), rpc.BlockNumber(last-count+1))
+		require.NoError(t, err)
+		assert.Equal(t, hdr.BaseFee, baseFee[0], "base fee from header")
+		assert.NotEqual(t, oneGwei, reward[count-1][0], "reward without override")
+	})
+}
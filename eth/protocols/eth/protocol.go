@@ -237,6 +237,8 @@ type BlockBody struct {
 	Transactions []*types.Transaction // Transactions contained within a block
 	Uncles       []*types.Header      // Uncles contained within a block
 	Withdrawals  []*types.Withdrawal  `rlp:"optional"` // Withdrawals contained within a block
+
+	body *types.Body // libevm: retains any extra payload; see [BlockBody.DecodeRLP]
 }
 
 // Unpack retrieves the transactions and uncles from the range packet and returns
@@ -0,0 +1,72 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"io"
+
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// EncodeRLP implements the [rlp.Encoder] interface. The encoding is that of
+// the equivalent [types.Body], including any extra payload retained by
+// [BlockBody.DecodeRLP], thus honouring the [types.BlockBodyHooks] registered
+// with [types.RegisterExtras]. Bodies served from the database are already
+// encoded in this manner; see [BlockBodiesRLPResponse].
+func (b *BlockBody) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, b.Body())
+}
+
+// DecodeRLP implements the [rlp.Decoder] interface, decoding a [types.Body]
+// and retaining its extra payload, which is available via [BlockBody.Body].
+func (b *BlockBody) DecodeRLP(s *rlp.Stream) error {
+	body := new(types.Body)
+	if err := s.Decode(body); err != nil {
+		return err
+	}
+	b.Transactions, b.Uncles, b.Withdrawals = body.Transactions, body.Uncles, body.Withdrawals
+	b.body = body
+	return nil
+}
+
+// Body returns the [types.Body] equivalent of `b`, including any extra payload
+// retained by [BlockBody.DecodeRLP]. The extra payload is shared, not copied.
+//
+// Note that the announcement-based block fetcher, only used before the Merge,
+// reassembles blocks from transactions and uncles alone so drops the extra
+// payload. The downloader propagates it via [BlockBodiesResponse.Bodies], but
+// only requests bodies for which [types.Header.EmptyBody] is false. As the
+// extra payload is not committed to by the header's roots, the downloader
+// rejects bodies for which [types.Body.VerifyExtra] returns an error.
+func (b *BlockBody) Body() *types.Body {
+	body := new(types.Body)
+	if b.body != nil {
+		*body = *b.body
+	}
+	body.Transactions, body.Uncles, body.Withdrawals = b.Transactions, b.Uncles, b.Withdrawals
+	return body
+}
+
+// Bodies returns the [BlockBody.Body] of every element of the response.
+func (p *BlockBodiesResponse) Bodies() []*types.Body {
+	bodies := make([]*types.Body, len(*p))
+	for i, b := range *p {
+		bodies[i] = b.Body()
+	}
+	return bodies
+}
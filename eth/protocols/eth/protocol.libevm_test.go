@@ -0,0 +1,130 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// taggedBody extends the body RLP with an optional field after the
+// withdrawals.
+type taggedBody struct {
+	types.NOOPBlockBodyHooks
+	Tag *uint64
+}
+
+func (e *taggedBody) Copy() *taggedBody {
+	cp := *e
+	return &cp
+}
+
+func (e *taggedBody) BodyRLPFieldsForEncoding(b *types.Body) *rlp.Fields {
+	return &rlp.Fields{
+		Required: []any{b.Transactions, b.Uncles},
+		Optional: []any{b.Withdrawals, e.Tag},
+	}
+}
+
+func (e *taggedBody) BodyRLPFieldPointersForDecoding(b *types.Body) *rlp.Fields {
+	return &rlp.Fields{
+		Required: []any{&b.Transactions, &b.Uncles},
+		Optional: []any{&b.Withdrawals, &e.Tag},
+	}
+}
+
+// vanillaBlockBody is the upstream geth [BlockBody], without any hooks.
+type vanillaBlockBody struct {
+	Transactions []*types.Transaction
+	Uncles       []*types.Header
+	Withdrawals  []*types.Withdrawal `rlp:"optional"`
+}
+
+func TestBlockBodyExtras(t *testing.T) {
+	uncle := &types.Header{Number: common.Big1}
+
+	t.Run("no_extras_registered", func(t *testing.T) {
+		body := &BlockBody{Uncles: []*types.Header{uncle}}
+		got, err := rlp.EncodeToBytes(body)
+		require.NoError(t, err, "rlp.EncodeToBytes(%T)", body)
+		want, err := rlp.EncodeToBytes(&vanillaBlockBody{Uncles: body.Uncles})
+		require.NoError(t, err, "rlp.EncodeToBytes(%T)", &vanillaBlockBody{})
+		assert.Equal(t, want, got, "identical to vanilla encoding")
+	})
+
+	types.TestOnlyClearRegisteredExtras()
+	t.Cleanup(types.TestOnlyClearRegisteredExtras)
+	extras := types.RegisterExtras[
+		types.NOOPHeaderHooks, *types.NOOPHeaderHooks,
+		taggedBody, *taggedBody,
+		struct{},
+	]()
+
+	tag := uint64(42)
+	tagged := &types.Body{Uncles: []*types.Header{uncle}}
+	extras.Body.Set(tagged, &taggedBody{Tag: &tag})
+	// The database, and hence [BlockBodiesRLPResponse], stores bodies encoded
+	// as [types.Body].
+	servedRLP, err := rlp.EncodeToBytes(tagged)
+	require.NoError(t, err, "rlp.EncodeToBytes(%T)", tagged)
+
+	var packet BlockBodiesPacket
+	{
+		raw, err := rlp.EncodeToBytes(&BlockBodiesRLPPacket{
+			RequestId:              1,
+			BlockBodiesRLPResponse: []rlp.RawValue{servedRLP},
+		})
+		require.NoError(t, err, "rlp.EncodeToBytes(%T)", &BlockBodiesRLPPacket{})
+		require.NoError(t, rlp.DecodeBytes(raw, &packet), "rlp.DecodeBytes(..., %T)", &packet)
+	}
+	require.Len(t, packet.BlockBodiesResponse, 1)
+
+	t.Run("decode_from_registered_peer", func(t *testing.T) {
+		bodies := packet.Bodies()
+		require.Len(t, bodies, 1)
+		got := extras.Body.Get(bodies[0])
+		require.NotNil(t, got.Tag, "extra payload retained")
+		assert.Equal(t, tag, *got.Tag, "extra payload")
+		assert.Equal(t, 1, len(bodies[0].Uncles), "uncles")
+	})
+
+	t.Run("re-encode_equals_served", func(t *testing.T) {
+		got, err := rlp.EncodeToBytes(packet.BlockBodiesResponse[0])
+		require.NoError(t, err, "rlp.EncodeToBytes(%T)", packet.BlockBodiesResponse[0])
+		assert.Equal(t, servedRLP, got, "BlockBody encoding equals types.Body encoding")
+	})
+
+	t.Run("vanilla_decoder", func(t *testing.T) {
+		// Intentional divergence: peers without the registered hooks reject
+		// bodies with non-empty extras...
+		var v vanillaBlockBody
+		require.Error(t, rlp.DecodeBytes(servedRLP, &v), "vanilla decoding of body with extras")
+
+		// ... but accept those without.
+		empty := &BlockBody{Uncles: []*types.Header{uncle}}
+		buf, err := rlp.EncodeToBytes(empty)
+		require.NoError(t, err, "rlp.EncodeToBytes(%T)", empty)
+		require.NoError(t, rlp.DecodeBytes(buf, &v), "vanilla decoding of body without extras")
+		assert.Len(t, v.Uncles, 1, "vanilla-decoded uncles")
+	})
+}
@@ -203,6 +203,8 @@ type blockTraceResult struct {
 type txTraceTask struct {
 	statedb *state.StateDB // Intermediate state prepped for tracing
 	index   int            // Transaction offset in the block
+
+	invocations *vm.PrecompileInvocations // libevm: counts prior to the transaction
 }
 
 // TraceChain returns the structured logs created during the execution of EVM
@@ -655,7 +657,9 @@ func (api *API) traceBlockParallel(ctx context.Context, block *types.Block, stat
 					TxIndex:     task.index,
 					TxHash:      txs[task.index].Hash(),
 				}
-				res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, config)
+				taskCtx := blockCtx                                // libevm
+				taskCtx.SetPrecompileInvocations(task.invocations) // libevm
+				res, err := api.traceTx(ctx, msg, txctx, taskCtx, task.statedb, config)
 				if err != nil {
 					results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Error: err.Error()}
 					continue
@@ -670,7 +674,7 @@ func (api *API) traceBlockParallel(ctx context.Context, block *types.Block, stat
 txloop:
 	for i, tx := range txs {
 		// Send the trace task over for execution
-		task := &txTraceTask{statedb: statedb.Copy(), index: i}
+		task := &txTraceTask{statedb: statedb.Copy(), index: i, invocations: blockCtx.PrecompileInvocations().Copy()} // libevm: invocations
 		select {
 		case <-ctx.Done():
 			failed = ctx.Err()
@@ -828,7 +832,7 @@ func containsTx(block *types.Block, hash common.Hash) bool {
 func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
 	found, _, blockHash, blockNumber, index, err := api.backend.GetTransaction(ctx, hash)
 	if err != nil {
-		return nil, ethapi.NewTxIndexingError()
+		return nil, ethapi.NewTxLookupError(err) // libevm: was NewTxIndexingError()
 	}
 	// Only mined txes are supported
 	if !found {
@@ -0,0 +1,128 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/eth/tracers/logger"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/rpc"
+)
+
+type nopPrecompile struct{}
+
+func (nopPrecompile) RequiredGas([]byte) uint64  { return 0 }
+func (nopPrecompile) Run([]byte) ([]byte, error) { return nil, nil }
+
+func TestTracePrecompileInvocationLimit(t *testing.T) {
+	precompile := common.Address{'l', 'i', 'm'}
+	registry := vm.NewPrecompileRegistry()
+	registry.RegisterAt(precompile, nopPrecompile{}, nil, vm.WithInvocationLimit(1))
+	limited, _ := registry.For(params.Rules{}).PrecompileOverride(precompile)
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{precompile: limited},
+	}
+	extras := hooks.Register(t)
+	config := *params.TestChainConfig
+	extras.ChainConfig.Set(&config, hooks)
+
+	// A tracer flagged as JS forces the parallel tracing path.
+	const parallelTracer = "libevmParallelStructLogger"
+	DefaultDirectory.Register(parallelTracer, func(*Context, json.RawMessage) (Tracer, error) {
+		return logger.NewStructLogger(nil), nil
+	}, true)
+	t.Cleanup(func() { delete(DefaultDirectory.elems, parallelTracer) })
+
+	accounts := newAccounts(1)
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	signer := types.HomesteadSigner{}
+	var txs []*types.Transaction
+	backend := newTestBackend(t, 1, genesis, func(_ int, b *core.BlockGen) {
+		for i := range 2 {
+			tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+				Nonce:    uint64(i), //nolint:gosec // Non-negative
+				To:       &precompile,
+				Gas:      100_000,
+				GasPrice: b.BaseFee(),
+			}), signer, accounts[0].key)
+			require.NoError(t, err, "types.SignTx()")
+			b.AddTx(tx)
+			txs = append(txs, tx)
+		}
+	})
+	defer backend.chain.Stop()
+	api := NewAPI(backend)
+
+	receipts := backend.chain.GetReceiptsByHash(backend.chain.CurrentBlock().Hash())
+	require.Len(t, receipts, 2, "receipts")
+	require.Equal(t, types.ReceiptStatusSuccessful, receipts[0].Status, "first invocation")
+	require.Equal(t, types.ReceiptStatusFailed, receipts[1].Status, "invocation beyond limit")
+	wantFailed := []bool{false, true}
+
+	failed := func(t *testing.T, result any) bool {
+		t.Helper()
+		buf, err := json.Marshal(result)
+		require.NoError(t, err, "json.Marshal(trace result)")
+		var res struct{ Failed bool }
+		require.NoError(t, json.Unmarshal(buf, &res), "json.Unmarshal(trace result)")
+		return res.Failed
+	}
+
+	ctx := context.Background()
+	for name, cfg := range map[string]*TraceConfig{
+		"sequential": nil,
+		"parallel":   {Tracer: func() *string { s := parallelTracer; return &s }()},
+	} {
+		t.Run("TraceBlockByNumber/"+name, func(t *testing.T) {
+			results, err := api.TraceBlockByNumber(ctx, rpc.BlockNumber(1), cfg)
+			require.NoError(t, err, "TraceBlockByNumber()")
+			var got []bool
+			for _, r := range results {
+				got = append(got, failed(t, r.Result))
+			}
+			assert.Equal(t, wantFailed, got, "failed transactions")
+		})
+	}
+
+	t.Run("TraceTransaction", func(t *testing.T) {
+		for i, tx := range txs {
+			result, err := api.TraceTransaction(ctx, tx.Hash(), nil)
+			require.NoErrorf(t, err, "TraceTransaction(%d)", i)
+			assert.Equalf(t, wantFailed[i], failed(t, result), "TraceTransaction(%d) failed", i)
+		}
+	})
+}
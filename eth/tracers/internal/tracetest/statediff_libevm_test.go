@@ -0,0 +1,217 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/eth/tracers"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+type stateDiffTestState struct {
+	Balance  *hexutil.Big                `json:"balance"`
+	Nonce    uint64                      `json:"nonce"`
+	CodeHash common.Hash                 `json:"codeHash"`
+	Code     hexutil.Bytes               `json:"code"`
+	Storage  map[common.Hash]common.Hash `json:"storage"`
+	Extra    json.RawMessage             `json:"extra"`
+}
+
+type stateDiffTestResult map[common.Address]struct {
+	Pre            *stateDiffTestState `json:"pre"`
+	Post           *stateDiffTestState `json:"post"`
+	BalanceChanges []struct {
+		Reason string `json:"reason"`
+		Delta  string `json:"delta"` // signed, so unsupported by [hexutil.Big]
+	} `json:"balanceChanges"`
+}
+
+func TestStateDiffTracer(t *testing.T) {
+	types.TestOnlyClearRegisteredExtras()
+	t.Cleanup(types.TestOnlyClearRegisteredExtras)
+	extras := types.RegisterExtras[
+		types.NOOPHeaderHooks, *types.NOOPHeaderHooks,
+		types.NOOPBlockBodyHooks, *types.NOOPBlockBodyHooks,
+		bool,
+	]().StateAccount
+
+	rng := ethtest.NewPseudoRand(42)
+	var (
+		sender    = rng.Address()
+		contract  = rng.Address()
+		recipient = rng.Address()
+		reverter  = rng.Address()
+		destroyed = rng.Address()
+		coinbase  = rng.Address()
+		precomp   = common.HexToAddress("0x0100000000000000000000000000000000000000")
+	)
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precomp: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				state.SetExtra(env.StateDB().(*state.StateDB), extras, contract, true) //nolint:forcetypeassert // known in test
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	call := func(to common.Address, value byte) []byte {
+		code := []byte{
+			byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0,
+			byte(vm.PUSH1), value,
+			byte(vm.PUSH20),
+		}
+		code = append(code, to.Bytes()...)
+		return append(code, byte(vm.GAS), byte(vm.CALL), byte(vm.POP))
+	}
+	var code []byte
+	code = append(code, byte(vm.PUSH1), 1, byte(vm.PUSH1), 0, byte(vm.SSTORE))
+	code = append(code, call(recipient, 7)...)
+	code = append(code, call(precomp, 0)...)
+	code = append(code, call(reverter, 5)...)
+	code = append(code, call(destroyed, 0)...)
+	code = append(code, byte(vm.STOP))
+
+	// Pre-Cancun, so the account is removed.
+	destroyedCode := []byte{byte(vm.CALLER), byte(vm.SELFDESTRUCT)}
+
+	const (
+		contractBalance = 100
+		value           = 10
+	)
+	config := params.TestChainConfig
+	run := func(t *testing.T, name string, cfg json.RawMessage) json.RawMessage {
+		t.Helper()
+		tracer, err := tracers.DefaultDirectory.New(name, new(tracers.Context), cfg)
+		require.NoErrorf(t, err, "tracers.DefaultDirectory.New(%q)", name)
+
+		sdb, evm := ethtest.NewZeroEVM(t,
+			ethtest.WithChainConfig(config),
+			ethtest.WithBlockContext(vm.BlockContext{
+				CanTransfer: core.CanTransfer,
+				Transfer:    core.Transfer,
+				Coinbase:    coinbase,
+				BlockNumber: big.NewInt(1),
+				BaseFee:     big.NewInt(params.GWei),
+				GasLimit:    1e7,
+			}),
+			ethtest.WithVMConfig(vm.Config{Tracer: tracer}),
+		)
+		sdb.SetBalance(sender, uint256.NewInt(params.Ether))
+		sdb.SetCode(contract, code)
+		sdb.SetBalance(contract, uint256.NewInt(contractBalance))
+		sdb.SetCode(reverter, []byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.REVERT)})
+		sdb.SetCode(destroyed, destroyedCode)
+		sdb.Finalise(true)
+
+		msg := &core.Message{
+			From:      sender,
+			To:        &contract,
+			Value:     big.NewInt(value),
+			GasLimit:  1e6,
+			GasPrice:  big.NewInt(2 * params.GWei),
+			GasFeeCap: big.NewInt(2 * params.GWei),
+			GasTipCap: big.NewInt(params.GWei),
+		}
+		_, err = core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(msg.GasLimit))
+		require.NoError(t, err, "core.ApplyMessage()")
+
+		res, err := tracer.GetResult()
+		require.NoErrorf(t, err, "%T.GetResult()", tracer)
+		return res
+	}
+
+	raw := run(t, "stateDiffTracer", nil)
+	var got stateDiffTestResult
+	require.NoErrorf(t, json.Unmarshal(raw, &got), "json.Unmarshal(%T.GetResult())", got)
+
+	reasons := make(map[common.Address][]string)
+	for addr, d := range got {
+		sum := new(big.Int)
+		for _, c := range d.BalanceChanges {
+			reasons[addr] = append(reasons[addr], c.Reason)
+			delta, ok := new(big.Int).SetString(c.Delta, 0)
+			require.Truef(t, ok, "%q for %v is not a valid delta", c.Delta, addr)
+			sum.Add(sum, delta)
+		}
+
+		balance := func(s *stateDiffTestState) *big.Int {
+			if s == nil {
+				return new(big.Int)
+			}
+			return s.Balance.ToInt()
+		}
+		want := new(big.Int).Sub(balance(d.Post), balance(d.Pre))
+		assert.Equalf(t, want, sum, "sum of balance changes of %v equals post - pre", addr)
+	}
+	assert.Equal(t, map[common.Address][]string{
+		sender:    {"gas_purchase", "transfer", "gas_refund"},
+		contract:  {"transfer", "transfer"},
+		recipient: {"transfer"},
+		coinbase:  {"tip"},
+	}, reasons, "balance-change reasons; reverted transfer and untouched accounts excluded")
+
+	if s := got[sender]; assert.NotNil(t, s.Pre, "sender pre-state") && assert.NotNil(t, s.Post, "sender post-state") {
+		assert.Equal(t, big.NewInt(params.Ether), s.Pre.Balance.ToInt(), "sender pre-tx balance")
+		assert.Equal(t, uint64(0), s.Pre.Nonce, "sender pre-tx nonce")
+		assert.Equal(t, uint64(1), s.Post.Nonce, "sender post-tx nonce")
+	}
+	if c := got[contract]; assert.NotNil(t, c.Pre, "contract pre-state") && assert.NotNil(t, c.Post, "contract post-state") {
+		assert.Equal(t, big.NewInt(contractBalance), c.Pre.Balance.ToInt(), "contract pre-tx balance")
+		assert.Equal(t, big.NewInt(contractBalance+value-7), c.Post.Balance.ToInt(), "contract post-tx balance")
+		slot := common.Hash{}
+		assert.Equal(t, map[common.Hash]common.Hash{slot: {}}, c.Pre.Storage, "contract pre-tx storage")
+		assert.Equal(t, map[common.Hash]common.Hash{slot: common.BigToHash(big.NewInt(1))}, c.Post.Storage, "contract post-tx storage")
+		assert.JSONEq(t, `false`, string(c.Pre.Extra), "contract pre-tx extra")
+		assert.JSONEq(t, `true`, string(c.Post.Extra), "contract post-tx extra")
+		assert.Empty(t, c.Pre.Code, "contract pre-tx code; omitted as unchanged")
+	}
+	if d, ok := got[destroyed]; assert.Truef(t, ok, "self-destructed account %v in result", destroyed) {
+		if assert.NotNil(t, d.Pre, "self-destructed pre-state") {
+			assert.Equal(t, hexutil.Bytes(destroyedCode), d.Pre.Code, "self-destructed pre-tx code")
+		}
+		assert.Nil(t, d.Post, "self-destructed post-state")
+	}
+	if r := got[recipient]; assert.NotNil(t, r.Post, "recipient post-state") {
+		assert.Nil(t, r.Pre, "recipient pre-state")
+		assert.Equal(t, big.NewInt(7), r.Post.Balance.ToInt(), "recipient post-tx balance")
+	}
+
+	t.Run("muxTracer", func(t *testing.T) {
+		var got map[string]json.RawMessage
+		res := run(t, "muxTracer", json.RawMessage(`{"stateDiffTracer":{}}`))
+		require.NoError(t, json.Unmarshal(res, &got))
+		assert.JSONEq(t, string(raw), string(got["stateDiffTracer"]))
+	})
+}
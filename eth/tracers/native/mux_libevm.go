@@ -0,0 +1,34 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+)
+
+var _ vm.BalanceChangeLogger = (*muxTracer)(nil)
+
+// CaptureBalanceChange implements the [vm.BalanceChangeLogger] interface,
+// propagating the change to all tracers that also implement it.
+func (t *muxTracer) CaptureBalanceChange(addr common.Address, prev, curr *uint256.Int, reason vm.BalanceChangeReason) {
+	for _, t := range t.tracers {
+		vm.CaptureBalanceChange(t, addr, prev, curr, reason)
+	}
+}
@@ -0,0 +1,416 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/eth/tracers"
+	"github.com/ava-labs/libevm/log"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("stateDiffTracer", newStateDiffTracer, false)
+}
+
+// Reasons for balance changes observed by the [stateDiffTracer] itself, in
+// addition to those reported via the [vm.BalanceChangeLogger] interface.
+const (
+	balanceChangeTransfer     vm.BalanceChangeReason = "transfer"
+	balanceChangeSelfDestruct vm.BalanceChangeReason = "selfdestruct"
+	// balanceChangeUnattributed accounts for any difference between pre- and
+	// post-transaction balances that wasn't otherwise reported; e.g. by a
+	// precompile modifying balances directly.
+	balanceChangeUnattributed vm.BalanceChangeReason = "unattributed"
+)
+
+// stateDiffTracer reports the pre- and post-transaction states of all accounts
+// modified by a transaction, including any extra payloads registered with
+// [types.RegisterExtras], along with reason-annotated balance changes that sum
+// to the difference in balances. Storage is limited to slots accessed via
+// SLOAD and SSTORE, and empty accounts are reported as absent (null).
+//
+// The result is a JSON object keyed by address. As with all other fields, the
+// order of keys is deterministic, making the output suitable for indexing.
+// Balance changes are encoded as signed hex values; e.g. "-0x1" for a debit.
+type stateDiffTracer struct {
+	noopTracer
+	env       *vm.EVM
+	accounts  map[common.Address]*stateDiffAccount
+	frames    [][]*balanceChange // uncommitted value transfers, by call depth
+	result    map[common.Address]*stateDiff
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+// stateDiffAccount tracks a single account. All balance changes, committed or
+// not, are accumulated in `delta` so that the pre-transaction balance can be
+// derived from the current one when the account is first observed.
+type stateDiffAccount struct {
+	pre     *stateDiffState // nil until observed
+	delta   *big.Int
+	changes []*balanceChange // committed
+}
+
+type stateDiffState struct {
+	Balance  *hexutil.Big                `json:"balance"`
+	Nonce    uint64                      `json:"nonce"`
+	CodeHash common.Hash                 `json:"codeHash"`
+	Code     hexutil.Bytes               `json:"code,omitempty"`
+	Storage  map[common.Hash]common.Hash `json:"storage,omitempty"`
+	Extra    json.RawMessage             `json:"extra"`
+
+	zeroExtra bool
+}
+
+type balanceChange struct {
+	addr   common.Address
+	Reason vm.BalanceChangeReason `json:"reason"`
+	Delta  *hexutil.Big           `json:"delta"`
+}
+
+// stateDiff is the result for a single account; nil states denote absence.
+type stateDiff struct {
+	Pre            *stateDiffState  `json:"pre"`
+	Post           *stateDiffState  `json:"post"`
+	BalanceChanges []*balanceChange `json:"balanceChanges"`
+}
+
+func newStateDiffTracer(ctx *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
+	return &stateDiffTracer{
+		accounts: make(map[common.Address]*stateDiffAccount),
+		result:   make(map[common.Address]*stateDiff),
+	}, nil
+}
+
+var _ vm.BalanceChangeLogger = (*stateDiffTracer)(nil)
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *stateDiffTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.frames = [][]*balanceChange{nil}
+	t.transfer(from, to, value, balanceChangeTransfer)
+
+	for addr := range t.accounts {
+		// Accounts with balance changes before execution; e.g. gas purchase.
+		t.lookupAccount(addr)
+	}
+	if pre := t.lookupAccount(from); pre != nil && pre.Nonce > 0 {
+		// The nonce is incremented before execution of calls and creations.
+		pre.Nonce--
+	}
+	t.lookupCreated(to, create)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *stateDiffTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	t.popFrame(err)
+}
+
+// CaptureEnter implements the EVMLogger interface, called when entering a new
+// scope (via CALL*, CREATE or SELFDESTRUCT).
+func (t *stateDiffTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.frames = append(t.frames, nil)
+	switch typ {
+	case vm.DELEGATECALL, vm.STATICCALL:
+	case vm.SELFDESTRUCT:
+		t.transfer(from, to, value, balanceChangeSelfDestruct)
+	default:
+		t.transfer(from, to, value, balanceChangeTransfer)
+	}
+	t.lookupAccount(from)
+	t.lookupCreated(to, typ == vm.CREATE || typ == vm.CREATE2)
+}
+
+// CaptureExit is called when returning from a scope.
+func (t *stateDiffTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.popFrame(err)
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *stateDiffTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil || t.interrupt.Load() {
+		return
+	}
+	stack := scope.Stack.Data()
+	if len(stack) < 1 || (op != vm.SLOAD && op != vm.SSTORE) {
+		return
+	}
+	addr := scope.Contract.Address()
+	slot := common.Hash(stack[len(stack)-1].Bytes32())
+	pre := t.lookupAccount(addr)
+	if _, ok := pre.Storage[slot]; !ok {
+		pre.Storage[slot] = t.env.StateDB.GetState(addr, slot)
+	}
+}
+
+// CaptureBalanceChange implements the [vm.BalanceChangeLogger] interface. It
+// MAY be called before [stateDiffTracer.CaptureStart], in which case the
+// account is only observed once the EVM is available.
+func (t *stateDiffTracer) CaptureBalanceChange(addr common.Address, prev, curr *uint256.Int, reason vm.BalanceChangeReason) {
+	delta := new(big.Int).Sub(curr.ToBig(), prev.ToBig())
+	t.commit(t.record(addr, delta, reason))
+	if t.env != nil {
+		t.lookupAccount(addr)
+	}
+}
+
+// CaptureTxEnd implements the EVMLogger interface, computing the differences
+// after all balance changes, including fee distribution, have been made.
+func (t *stateDiffTracer) CaptureTxEnd(restGas uint64) {
+	if t.env == nil || t.interrupt.Load() {
+		return
+	}
+	for addr, acc := range t.accounts {
+		if acc.pre == nil {
+			continue
+		}
+		if d := t.diff(addr, acc); d != nil {
+			t.result[addr] = d
+		}
+	}
+}
+
+// GetResult returns the json-encoded state differences, and any error arising
+// from the encoding or forceful termination (via `Stop`).
+func (t *stateDiffTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.result)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *stateDiffTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
+
+// transfer records a value transfer in the current frame.
+func (t *stateDiffTracer) transfer(from, to common.Address, value *big.Int, reason vm.BalanceChangeReason) {
+	if value == nil || value.Sign() == 0 {
+		return
+	}
+	top := len(t.frames) - 1
+	t.frames[top] = append(
+		t.frames[top],
+		t.record(from, new(big.Int).Neg(value), reason),
+		t.record(to, new(big.Int).Set(value), reason),
+	)
+}
+
+// record accumulates `delta` for the account, without committing it.
+func (t *stateDiffTracer) record(addr common.Address, delta *big.Int, reason vm.BalanceChangeReason) *balanceChange {
+	acc := t.account(addr)
+	acc.delta.Add(acc.delta, delta)
+	return &balanceChange{
+		addr:   addr,
+		Reason: reason,
+		Delta:  (*hexutil.Big)(delta),
+	}
+}
+
+func (t *stateDiffTracer) commit(changes ...*balanceChange) {
+	for _, c := range changes {
+		acc := t.accounts[c.addr]
+		acc.changes = append(acc.changes, c)
+	}
+}
+
+// popFrame drops the current frame, merging its value transfers into the
+// parent frame, or committing them if there is no parent. If `err` is non-nil
+// then the transfers were reverted and are instead discarded.
+func (t *stateDiffTracer) popFrame(err error) {
+	top := len(t.frames) - 1
+	if top < 0 {
+		return
+	}
+	changes := t.frames[top]
+	t.frames = t.frames[:top]
+
+	switch {
+	case err != nil:
+		for _, c := range changes {
+			acc := t.accounts[c.addr]
+			acc.delta.Sub(acc.delta, c.Delta.ToInt())
+		}
+	case top == 0:
+		t.commit(changes...)
+	default:
+		t.frames[top-1] = append(t.frames[top-1], changes...)
+	}
+}
+
+func (t *stateDiffTracer) account(addr common.Address) *stateDiffAccount {
+	acc, ok := t.accounts[addr]
+	if !ok {
+		acc = &stateDiffAccount{delta: new(big.Int)}
+		t.accounts[addr] = acc
+	}
+	return acc
+}
+
+// lookupAccount returns the pre-transaction state of the account, recording it
+// if the account hasn't already been observed. The balance is derived from the
+// current one by reversing all changes recorded so far. The code is recorded
+// immediately, in case it is later removed, but storage is populated lazily.
+func (t *stateDiffTracer) lookupAccount(addr common.Address) *stateDiffState {
+	acc := t.account(addr)
+	if acc.pre == nil {
+		acc.pre = t.currentState(addr)
+		acc.pre.Balance.ToInt().Sub(acc.pre.Balance.ToInt(), acc.delta)
+		acc.pre.Code = t.env.StateDB.GetCode(addr)
+	}
+	return acc.pre
+}
+
+// lookupCreated is equivalent to [stateDiffTracer.lookupAccount] but, if
+// `created` is true and the account wasn't already observed, it accounts for
+// the nonce having been set before the creation was traced.
+func (t *stateDiffTracer) lookupCreated(addr common.Address, created bool) {
+	seen := t.accounts[addr] != nil && t.accounts[addr].pre != nil
+	pre := t.lookupAccount(addr)
+	if created && !seen {
+		pre.Nonce = 0
+	}
+}
+
+// currentState returns the account's state, excluding storage.
+func (t *stateDiffTracer) currentState(addr common.Address) *stateDiffState {
+	db := t.env.StateDB
+	s := &stateDiffState{
+		Balance:  (*hexutil.Big)(db.GetBalance(addr).ToBig()),
+		Nonce:    db.GetNonce(addr),
+		CodeHash: db.GetCodeHash(addr),
+		Storage:  make(map[common.Hash]common.Hash),
+	}
+	s.setExtra(db, addr)
+	return s
+}
+
+// setExtra sets the JSON encoding of the account's extra payload, leaving it
+// as `null` if `db` doesn't expose the payload; see [types.StateAccountExtra].
+func (s *stateDiffState) setExtra(db vm.StateDB, addr common.Address) {
+	s.Extra = json.RawMessage(`null`)
+	s.zeroExtra = true
+
+	x, ok := db.(interface {
+		GetExtraPayload(common.Address) *types.StateAccountExtra
+	})
+	if !ok {
+		return
+	}
+	extra := x.GetExtraPayload(addr)
+	buf, err := extra.MarshalJSON() // supports nil receivers, unlike [json.Marshal]
+	if err != nil {
+		log.Warn("failed to encode extra payload", "err", err, "tracer", "stateDiffTracer", "addr", addr)
+		return
+	}
+	s.Extra = buf
+	s.zeroExtra = extra.IsZero()
+}
+
+// diff returns the differences between the pre- and post-transaction states
+// of the account, or nil if there are none and the balance wasn't changed.
+func (t *stateDiffTracer) diff(addr common.Address, acc *stateDiffAccount) *stateDiff {
+	pre := acc.pre
+	post := t.currentState(addr)
+	destructed := t.env.StateDB.HasSelfDestructed(addr)
+	if destructed {
+		post = &stateDiffState{
+			Balance:   new(hexutil.Big),
+			Storage:   make(map[common.Hash]common.Hash),
+			Extra:     json.RawMessage(`null`),
+			zeroExtra: true,
+		}
+	}
+
+	modified := pre.Nonce != post.Nonce ||
+		pre.Balance.ToInt().Cmp(post.Balance.ToInt()) != 0 ||
+		pre.CodeHash != post.CodeHash ||
+		pre.isEmpty() != post.isEmpty() ||
+		!bytes.Equal(pre.Extra, post.Extra)
+	if pre.CodeHash == post.CodeHash {
+		pre.Code = nil // only reported if changed
+	} else if !destructed {
+		post.Code = t.env.StateDB.GetCode(addr)
+	}
+
+	// The pre-state holds the original value of every observed slot.
+	for slot, val := range pre.Storage {
+		if !destructed {
+			post.Storage[slot] = t.env.StateDB.GetState(addr, slot)
+		}
+		if post.Storage[slot] == val {
+			delete(pre.Storage, slot)
+			delete(post.Storage, slot)
+		} else {
+			modified = true
+		}
+	}
+
+	changes := acc.changes
+	residual := new(big.Int).Sub(post.Balance.ToInt(), pre.Balance.ToInt())
+	for _, c := range changes {
+		residual.Sub(residual, c.Delta.ToInt())
+	}
+	if residual.Sign() != 0 {
+		changes = append(changes, &balanceChange{
+			addr:   addr,
+			Reason: balanceChangeUnattributed,
+			Delta:  (*hexutil.Big)(residual),
+		})
+	}
+
+	if !modified && len(changes) == 0 {
+		return nil
+	}
+	d := &stateDiff{
+		Pre:            pre,
+		Post:           post,
+		BalanceChanges: changes,
+	}
+	if d.BalanceChanges == nil {
+		d.BalanceChanges = []*balanceChange{}
+	}
+	if pre.isEmpty() {
+		d.Pre = nil
+	}
+	if post.isEmpty() {
+		d.Post = nil
+	}
+	return d
+}
+
+// isEmpty reports whether the state is that of an empty account, as defined
+// by EIP-161, with the additional requirement of a zero extra payload.
+func (s *stateDiffState) isEmpty() bool {
+	return s.Nonce == 0 &&
+		s.Balance.ToInt().Sign() == 0 &&
+		(s.CodeHash == common.Hash{} || s.CodeHash == types.EmptyCodeHash) &&
+		s.zeroExtra
+}
@@ -471,6 +471,9 @@ func toFilterArg(q ethereum.FilterQuery) (interface{}, error) {
 		}
 		arg["toBlock"] = toBlockNumArg(q.ToBlock)
 	}
+	if len(q.Origins) > 0 { // libevm
+		arg["origins"] = q.Origins
+	}
 	return arg, nil
 }
 
@@ -0,0 +1,84 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package simulated
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm"
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/eth/ethconfig"
+	"github.com/ava-labs/libevm/node"
+)
+
+func TestTransactionLookupWithIndexTail(t *testing.T) {
+	const limit = 2
+	sim := NewBackend(
+		types.GenesisAlloc{
+			testAddr: {Balance: big.NewInt(10000000000000000)},
+		},
+		func(_ *node.Config, ethConf *ethconfig.Config) {
+			ethConf.TransactionHistory = limit
+		},
+	)
+	t.Cleanup(func() { sim.Close() })
+	client := sim.Client()
+	ctx := context.Background()
+
+	send := func(t *testing.T) *types.Transaction {
+		t.Helper()
+		tx, err := newTx(sim, testKey)
+		require.NoError(t, err, "newTx()")
+		require.NoError(t, client.SendTransaction(ctx, tx), "SendTransaction()")
+		return tx
+	}
+
+	unindexed := send(t)
+	for range 2 * limit {
+		sim.Commit()
+	}
+	bc := sim.eth.BlockChain()
+	require.Eventually(t, func() bool {
+		s, err := bc.IndexProgress()
+		return err == nil && s.Done() && s.Tail != nil && *s.Tail > 0
+	}, 5*time.Second, 10*time.Millisecond, "transaction index tail > 0")
+
+	tests := []struct {
+		name string
+		hash common.Hash
+	}{
+		{"unknown", common.Hash{'u', 'n', 'k', 'n', 'o', 'w', 'n'}},
+		{"pending", send(t).Hash()},
+		{"below_tail", unindexed.Hash()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.TransactionReceipt(ctx, tt.hash)
+			assert.ErrorIs(t, err, ethereum.NotFound, "TransactionReceipt()")
+		})
+	}
+
+	_, _, err := client.TransactionByHash(ctx, tests[0].hash)
+	assert.ErrorIs(t, err, ethereum.NotFound, "TransactionByHash(unknown)")
+}
@@ -86,6 +86,8 @@ type Database struct {
 	writeDelayTime      atomic.Int64  // Total time spent in write stalls
 
 	writeOptions *pebble.WriteOptions
+
+	libevm libevmState // libevm
 }
 
 func (d *Database) onCompactionBegin(info pebble.CompactionInfo) {
@@ -231,23 +233,29 @@ func New(file string, cache int, handles int, namespace string, readonly bool, e
 	}
 	db.db = innerDB
 
-	db.compTimeMeter = metrics.NewRegisteredMeter(namespace+"compact/time", nil)
-	db.compReadMeter = metrics.NewRegisteredMeter(namespace+"compact/input", nil)
-	db.compWriteMeter = metrics.NewRegisteredMeter(namespace+"compact/output", nil)
-	db.diskSizeGauge = metrics.NewRegisteredGauge(namespace+"disk/size", nil)
-	db.diskReadMeter = metrics.NewRegisteredMeter(namespace+"disk/read", nil)
-	db.diskWriteMeter = metrics.NewRegisteredMeter(namespace+"disk/write", nil)
-	db.writeDelayMeter = metrics.NewRegisteredMeter(namespace+"compact/writedelay/duration", nil)
-	db.writeDelayNMeter = metrics.NewRegisteredMeter(namespace+"compact/writedelay/counter", nil)
-	db.memCompGauge = metrics.NewRegisteredGauge(namespace+"compact/memory", nil)
-	db.level0CompGauge = metrics.NewRegisteredGauge(namespace+"compact/level0", nil)
-	db.nonlevel0CompGauge = metrics.NewRegisteredGauge(namespace+"compact/nonlevel0", nil)
-	db.seekCompGauge = metrics.NewRegisteredGauge(namespace+"compact/seek", nil)
-	db.manualMemAllocGauge = metrics.NewRegisteredGauge(namespace+"memory/manualalloc", nil)
+	db.startMetrics(namespace) // libevm: extracted for reuse by NewFromDB()
+	return db, nil
+}
+
+// startMetrics registers the metrics under `namespace` and starts the
+// gathering goroutine.
+func (d *Database) startMetrics(namespace string) {
+	d.compTimeMeter = metrics.NewRegisteredMeter(namespace+"compact/time", nil)
+	d.compReadMeter = metrics.NewRegisteredMeter(namespace+"compact/input", nil)
+	d.compWriteMeter = metrics.NewRegisteredMeter(namespace+"compact/output", nil)
+	d.diskSizeGauge = metrics.NewRegisteredGauge(namespace+"disk/size", nil)
+	d.diskReadMeter = metrics.NewRegisteredMeter(namespace+"disk/read", nil)
+	d.diskWriteMeter = metrics.NewRegisteredMeter(namespace+"disk/write", nil)
+	d.writeDelayMeter = metrics.NewRegisteredMeter(namespace+"compact/writedelay/duration", nil)
+	d.writeDelayNMeter = metrics.NewRegisteredMeter(namespace+"compact/writedelay/counter", nil)
+	d.memCompGauge = metrics.NewRegisteredGauge(namespace+"compact/memory", nil)
+	d.level0CompGauge = metrics.NewRegisteredGauge(namespace+"compact/level0", nil)
+	d.nonlevel0CompGauge = metrics.NewRegisteredGauge(namespace+"compact/nonlevel0", nil)
+	d.seekCompGauge = metrics.NewRegisteredGauge(namespace+"compact/seek", nil)
+	d.manualMemAllocGauge = metrics.NewRegisteredGauge(namespace+"memory/manualalloc", nil)
 
 	// Start up the metrics gathering and return
-	go db.meter(metricsGatheringInterval, namespace)
-	return db, nil
+	go d.meter(metricsGatheringInterval, namespace)
 }
 
 // Close stops the metrics collection, flushes any pending data to disk and closes
@@ -268,6 +276,9 @@ func (d *Database) Close() error {
 		}
 		d.quitChan = nil
 	}
+	if d.libevm.close(d) { // libevm
+		return nil
+	}
 	return d.db.Close()
 }
 
@@ -0,0 +1,168 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package pebble
+
+import (
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/log"
+)
+
+// An Option configures a [Database] returned by [NewFromDB].
+type Option = options.Option[fromDBConfig]
+
+type fromDBConfig struct {
+	namespace string
+	listener  *EventListener
+	noSync    bool
+}
+
+// WithMetricsNamespace sets the prefix that metrics reporting uses for
+// surfacing internal stats, equivalent to the `namespace` argument of [New].
+// Databases sharing a pebble DB SHOULD use different namespaces.
+func WithMetricsNamespace(ns string) Option {
+	return options.Func[fromDBConfig](func(c *fromDBConfig) {
+		c.namespace = ns
+	})
+}
+
+// WithEventListener registers the [Database] with the listener, from which it
+// receives compaction and write-stall events for its metrics. The listener
+// MUST be the one installed, via [EventListener.Pebble], in the options used
+// to open the pebble DB.
+func WithEventListener(l *EventListener) Option {
+	return options.Func[fromDBConfig](func(c *fromDBConfig) {
+		c.listener = l
+	})
+}
+
+// WithoutSync disables syncing of writes, equivalent to the `ephemeral`
+// argument of [New].
+func WithoutSync() Option {
+	return options.Func[fromDBConfig](func(c *fromDBConfig) {
+		c.noSync = true
+	})
+}
+
+// NewFromDB wraps an already-open pebble DB, which MAY be shared by any number
+// of [Database] instances; see NewPebbleDBWithNamespace() in the core/rawdb
+// package for sharing a DB between logical databases with separate keyspaces.
+// The caller retains ownership of `db`: closing the returned Database stops its
+// metrics collection but doesn't close `db`, which MUST only be closed after
+// all Databases wrapping it.
+func NewFromDB(db *pebble.DB, opts ...Option) *Database {
+	cfg := options.As[fromDBConfig](opts...)
+
+	d := &Database{
+		db:           db,
+		log:          log.New("database", "shared", "namespace", cfg.namespace),
+		quitChan:     make(chan chan error),
+		writeOptions: &pebble.WriteOptions{Sync: !cfg.noSync},
+		libevm: libevmState{
+			shared:   true,
+			listener: cfg.listener,
+		},
+	}
+	if l := cfg.listener; l != nil {
+		l.add(d)
+	}
+	d.startMetrics(cfg.namespace)
+	return d
+}
+
+// libevmState carries [Database] fields that are only used by libevm
+// additions.
+type libevmState struct {
+	shared   bool
+	listener *EventListener
+}
+
+// close is called by [Database.Close] after stopping metrics collection and
+// returns true if the underlying pebble DB MUST NOT be closed.
+func (s *libevmState) close(d *Database) bool {
+	if l := s.listener; l != nil {
+		l.remove(d)
+	}
+	return s.shared
+}
+
+// An EventListener propagates compaction and write-stall events from a pebble
+// DB to every [Database] registered with [WithEventListener], which is
+// otherwise impossible for a DB opened outside of this package as pebble
+// listeners can't be changed after opening.
+type EventListener struct {
+	mu  sync.Mutex
+	dbs map[*Database]struct{}
+}
+
+// NewEventListener returns a new [EventListener], with no registered
+// Databases.
+func NewEventListener() *EventListener {
+	return &EventListener{
+		dbs: make(map[*Database]struct{}),
+	}
+}
+
+// Pebble returns a listener to be set as the [pebble.Options.EventListener]
+// when opening the DB.
+func (l *EventListener) Pebble() *pebble.EventListener {
+	return &pebble.EventListener{
+		CompactionBegin: func(info pebble.CompactionInfo) {
+			l.each(func(d *Database) { d.onCompactionBegin(info) })
+		},
+		CompactionEnd: func(info pebble.CompactionInfo) {
+			l.each(func(d *Database) {
+				// A Database registered during a compaction didn't observe its
+				// beginning, which would otherwise cause a panic.
+				if d.activeComp > 0 {
+					d.onCompactionEnd(info)
+				}
+			})
+		},
+		WriteStallBegin: func(info pebble.WriteStallBeginInfo) {
+			l.each(func(d *Database) { d.onWriteStallBegin(info) })
+		},
+		WriteStallEnd: func() {
+			l.each((*Database).onWriteStallEnd)
+		},
+	}
+}
+
+// each calls `fn` for every registered Database. Events are serialised, as
+// the [Database] handlers aren't safe for concurrent use.
+func (l *EventListener) each(fn func(*Database)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for d := range l.dbs {
+		fn(d)
+	}
+}
+
+func (l *EventListener) add(d *Database) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dbs[d] = struct{}{}
+}
+
+func (l *EventListener) remove(d *Database) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.dbs, d)
+}
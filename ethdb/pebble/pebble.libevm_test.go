@@ -0,0 +1,76 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package pebble
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/ethdb/dbtest"
+)
+
+func openMem(t *testing.T, l *EventListener) *pebble.DB {
+	t.Helper()
+	opts := &pebble.Options{FS: vfs.NewMem()}
+	if l != nil {
+		opts.EventListener = l.Pebble()
+	}
+	db, err := pebble.Open("", opts)
+	require.NoError(t, err, "pebble.Open()")
+	return db
+}
+
+func TestNewFromDB(t *testing.T) {
+	t.Run("DatabaseSuite", func(t *testing.T) {
+		dbtest.TestDatabaseSuite(t, func() ethdb.KeyValueStore {
+			return NewFromDB(openMem(t, nil), WithoutSync())
+		})
+	})
+
+	t.Run("EventListener", func(t *testing.T) {
+		l := NewEventListener()
+		db := openMem(t, l)
+
+		a := NewFromDB(db, WithEventListener(l), WithMetricsNamespace("a/"))
+		b := NewFromDB(db, WithEventListener(l), WithMetricsNamespace("b/"))
+		compact := func(t *testing.T, d *Database) {
+			t.Helper()
+			require.NoError(t, d.Put([]byte("key"), []byte("value")), "Put()")
+			require.NoError(t, d.Compact(nil, nil), "Compact()")
+		}
+		compactions := func(d *Database) uint32 {
+			return d.level0Comp.Load() + d.nonLevel0Comp.Load()
+		}
+
+		compact(t, a)
+		require.NotZero(t, compactions(a), "compactions observed by Database used for compaction")
+		require.Equal(t, compactions(a), compactions(b), "compactions observed by other Database sharing DB")
+
+		require.NoError(t, b.Close(), "Close()")
+		before := compactions(b)
+		compact(t, a)
+		assert.Equal(t, before, compactions(b), "compactions observed after Close()")
+
+		require.NoError(t, a.Close(), "Close()")
+		require.NoError(t, db.Close(), "%T.Close() after all wrappers closed", db)
+	})
+}
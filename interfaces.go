@@ -185,6 +185,8 @@ type FilterQuery struct {
 	// {{A}, {B}}         matches topic A in first position AND B in second position
 	// {{A, B}, {C, D}}   matches topic (A OR B) in first position AND (C OR D) in second position
 	Topics [][]common.Hash
+
+	Origins []types.LogOrigin // libevm: restricts matches to logs from specific origins; nil or empty matches all; only transaction logs are available historically
 }
 
 // LogFilterer provides access to contract log events using a one-off query or continuous
@@ -47,7 +47,6 @@ import (
 	"github.com/ava-labs/libevm/rlp"
 	"github.com/ava-labs/libevm/rpc"
 	"github.com/ava-labs/libevm/trie"
-	"github.com/holiman/uint256"
 	"github.com/tyler-smith/go-bip39"
 )
 
@@ -966,39 +965,7 @@ func (diff *StateOverride) Apply(state *state.StateDB) error {
 	if diff == nil {
 		return nil
 	}
-	for addr, account := range *diff {
-		// Override account nonce.
-		if account.Nonce != nil {
-			state.SetNonce(addr, uint64(*account.Nonce))
-		}
-		// Override account(contract) code.
-		if account.Code != nil {
-			state.SetCode(addr, *account.Code)
-		}
-		// Override account balance.
-		if account.Balance != nil {
-			u256Balance, _ := uint256.FromBig((*big.Int)(*account.Balance))
-			state.SetBalance(addr, u256Balance)
-		}
-		if account.State != nil && account.StateDiff != nil {
-			return fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
-		}
-		// Replace entire state if caller requires.
-		if account.State != nil {
-			state.SetStorage(addr, *account.State)
-		}
-		// Apply state diff into specified accounts.
-		if account.StateDiff != nil {
-			for key, value := range *account.StateDiff {
-				state.SetState(addr, key, value)
-			}
-		}
-	}
-	// Now finalize the changes. Finalize is normally performed between transactions.
-	// By using finalize, the overrides are semantically behaving as
-	// if they were created in a transaction just before the tracing occur.
-	state.Finalise(false)
-	return nil
+	return stateOverrides(state, *diff) // libevm
 }
 
 // BlockOverrides is a set of header fields to override.
@@ -1632,7 +1599,7 @@ func (s *TransactionAPI) GetTransactionByHash(ctx context.Context, hash common.H
 		if err == nil {
 			return nil, nil
 		}
-		return nil, NewTxIndexingError()
+		return nil, NewTxLookupError(err) // libevm: was NewTxIndexingError()
 	}
 	header, err := s.b.HeaderByHash(ctx, blockHash)
 	if err != nil {
@@ -1652,7 +1619,7 @@ func (s *TransactionAPI) GetRawTransactionByHash(ctx context.Context, hash commo
 		if err == nil {
 			return nil, nil
 		}
-		return nil, NewTxIndexingError()
+		return nil, NewTxLookupError(err) // libevm: was NewTxIndexingError()
 	}
 	return tx.MarshalBinary()
 }
@@ -1661,7 +1628,7 @@ func (s *TransactionAPI) GetRawTransactionByHash(ctx context.Context, hash commo
 func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
 	found, tx, blockHash, blockNumber, index, err := s.b.GetTransaction(ctx, hash)
 	if err != nil {
-		return nil, NewTxIndexingError() // transaction is not fully indexed
+		return nil, NewTxLookupError(err) // transaction is not fully indexed (libevm: or out of range)
 	}
 	if !found {
 		return nil, nil // transaction is not existent or reachable
@@ -2063,7 +2030,7 @@ func (s *DebugAPI) GetRawTransaction(ctx context.Context, hash common.Hash) (hex
 		if err == nil {
 			return nil, nil
 		}
-		return nil, NewTxIndexingError()
+		return nil, NewTxLookupError(err) // libevm: was NewTxIndexingError()
 	}
 	return tx.MarshalBinary()
 }
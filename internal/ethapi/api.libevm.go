@@ -19,7 +19,10 @@ package ethapi
 import (
 	"math/big"
 
+	"github.com/holiman/uint256"
+
 	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/state"
 	"github.com/ava-labs/libevm/core/types"
 	"github.com/ava-labs/libevm/params"
 )
@@ -41,3 +44,38 @@ type RevertError = revertError
 func NewRevertError(revert []byte) *RevertError {
 	return newRevertError(revert)
 }
+
+// stateOverrides converts the overrides for use with [state.ApplyOverrides],
+// to which it then delegates.
+func stateOverrides(sdb *state.StateDB, diff StateOverride) error {
+	set := make(state.OverrideSet, len(diff))
+	for addr, acc := range diff {
+		var o state.OverrideAccount
+		if acc.Nonce != nil {
+			n := uint64(*acc.Nonce)
+			o.Nonce = &n
+		}
+		if acc.Code != nil {
+			c := []byte(*acc.Code)
+			o.Code = &c
+		}
+		if acc.Balance != nil {
+			// Overflow is ignored, as it was before delegation.
+			o.Balance, _ = uint256.FromBig((*big.Int)(*acc.Balance))
+		}
+		if acc.State != nil {
+			o.State = *acc.State
+			if o.State == nil {
+				o.State = make(map[common.Hash]common.Hash)
+			}
+		}
+		if acc.StateDiff != nil {
+			o.StateDiff = *acc.StateDiff
+			if o.StateDiff == nil {
+				o.StateDiff = make(map[common.Hash]common.Hash)
+			}
+		}
+		set[addr] = o
+	}
+	return state.ApplyOverrides(sdb, set)
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"errors"
+
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/core/rawdb"
+)
+
+// TxIndexRangeError is an API error that indicates a transaction was not
+// found amongst the indexed blocks while earlier blocks are being indexed.
+type TxIndexRangeError struct {
+	err *rawdb.TxIndexRangeError
+}
+
+// NewTxLookupError converts an error returned by [Backend.GetTransaction]
+// into an API error. A [rawdb.TxIndexRangeError] results in a
+// [TxIndexRangeError] and all other errors in a [TxIndexingError].
+func NewTxLookupError(err error) error {
+	var r *rawdb.TxIndexRangeError
+	if errors.As(err, &r) {
+		return &TxIndexRangeError{r}
+	}
+	return NewTxIndexingError()
+}
+
+// Error implements the error interface.
+func (e *TxIndexRangeError) Error() string { return e.err.Error() }
+
+// ErrorCode returns the JSON error code.
+func (e *TxIndexRangeError) ErrorCode() int { return -32000 }
+
+// ErrorData returns the range of blocks whose transactions are indexed.
+func (e *TxIndexRangeError) ErrorData() interface{} {
+	return map[string]hexutil.Uint64{
+		"indexedFrom": hexutil.Uint64(e.err.Tail),
+		"indexedTo":   hexutil.Uint64(e.err.Head),
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/rpc"
+)
+
+func TestNewTxLookupError(t *testing.T) {
+	rangeErr := &rawdb.TxIndexRangeError{Tail: 10, Head: 20}
+	got := NewTxLookupError(fmt.Errorf("wrapped: %w", rangeErr))
+
+	var dataErr rpc.DataError
+	require.Truef(t, errors.As(got, &dataErr), "%T implements rpc.DataError", got)
+	require.Equal(t, rangeErr.Error(), got.Error(), "Error()")
+	want := map[string]hexutil.Uint64{"indexedFrom": 10, "indexedTo": 20}
+	require.Equal(t, want, dataErr.ErrorData(), "ErrorData()")
+
+	require.IsType(t, &TxIndexingError{}, NewTxLookupError(errors.New("indexing")), "non-range error")
+}
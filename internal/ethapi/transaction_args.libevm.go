@@ -0,0 +1,40 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/txpool"
+)
+
+// checkSizeLimits enforces the size limits of the [params.RulesHooks] as they
+// would be by [txpool.ValidateTransaction], allowing oversized input to be
+// rejected before gas estimation.
+func (args *TransactionArgs) checkSizeLimits(b Backend) error {
+	head := b.CurrentHeader()
+	rules := b.ChainConfig().Rules(head.Number, true, head.Time)
+	data := args.data()
+	if limit := rules.Hooks().MaxInitCodeSize(); rules.IsShanghai && args.To == nil && len(data) > limit {
+		return fmt.Errorf("%w: code size %v, limit %v", core.ErrMaxInitCodeSizeExceeded, len(data), limit)
+	}
+	if limit := rules.Hooks().MaxTxDataBytes(); len(data) > limit {
+		return fmt.Errorf("%w: data size %v, limit %v", txpool.ErrOversizedData, len(data), limit)
+	}
+	return nil
+}
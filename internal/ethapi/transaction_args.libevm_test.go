@@ -0,0 +1,51 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/core/txpool"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestCheckSizeLimits(t *testing.T) {
+	const maxData = 16
+	hooks := &hookstest.Stub{
+		MaxTxDataBytesFn: func() int { return maxData },
+	}
+	hooks.Register(t)
+
+	b := newBackendMock()
+	for _, size := range []int{0, maxData, maxData + 1} {
+		input := hexutil.Bytes(make([]byte, size))
+		args := &TransactionArgs{
+			To:    &common.Address{},
+			Input: &input,
+		}
+		err := args.checkSizeLimits(b)
+		if size > maxData {
+			require.ErrorIsf(t, err, txpool.ErrOversizedData, "checkSizeLimits() with %d bytes", size)
+		} else {
+			require.NoErrorf(t, err, "checkSizeLimits() with %d bytes", size)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package bench provides a harness for measuring end-to-end block-processing
+// throughput over synthetic workloads.
+//
+// A [Chain] is generated once, from a [Workload], and can then be processed any
+// number of times under different configurations (e.g. state scheme or
+// per-block hooks). Each run produces a [Result] that can be written as JSON
+// for regression tracking or reported to a [testing.B].
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A Chain is a deterministic, pre-generated sequence of blocks. Generation is
+// excluded from all measurements.
+type Chain struct {
+	workload Workload
+	genesis  *core.Genesis
+	blocks   types.Blocks
+	txs      int
+	gas      uint64
+}
+
+// Generate generates a [Chain] of `blocks` blocks, each with `txsPerBlock`
+// transactions from the [Workload]. All transactions are signed by a single,
+// funded sender.
+func Generate(w Workload, blocks, txsPerBlock int) (*Chain, error) {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("libevm/bench")))
+	if err != nil {
+		return nil, err
+	}
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	config := params.AllEthashProtocolChanges
+	alloc := types.GenesisAlloc{
+		sender: {Balance: new(big.Int).Lsh(big.NewInt(1), 128)},
+	}
+	for addr, acc := range w.Alloc() {
+		alloc[addr] = acc
+	}
+	genesis := &core.Genesis{
+		Config:   config,
+		Alloc:    alloc,
+		GasLimit: 1e12,
+		BaseFee:  big.NewInt(params.InitialBaseFee),
+	}
+
+	c := &Chain{
+		workload: w,
+		genesis:  genesis,
+	}
+	signer := types.LatestSigner(config)
+	var genErr error
+	_, c.blocks, _ = core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), blocks, func(_ int, b *core.BlockGen) {
+		num := b.Number().Uint64()
+		for i := range txsPerBlock {
+			call := w.Call(num, i)
+			tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+				ChainID:   config.ChainID,
+				Nonce:     b.TxNonce(sender),
+				GasTipCap: new(big.Int),
+				GasFeeCap: b.BaseFee(),
+				Gas:       call.Gas,
+				To:        call.To,
+				Value:     call.Value,
+				Data:      call.Data,
+			})
+			if err != nil {
+				genErr = err
+				return
+			}
+			b.AddTx(tx)
+		}
+	})
+	if genErr != nil {
+		return nil, fmt.Errorf("signing transaction: %v", genErr)
+	}
+
+	for _, b := range c.blocks {
+		c.txs += len(b.Transactions())
+		c.gas += b.GasUsed()
+	}
+	return c, nil
+}
+
+type config struct {
+	scheme   string
+	vmConfig vm.Config
+	label    string
+	before   func(*state.StateDB, params.Rules, *types.Block) error
+	after    func(*state.StateDB, *types.Block, types.Receipts)
+}
+
+// An Option configures [Chain.Process].
+type Option = options.Option[config]
+
+// WithStateScheme sets the state scheme, [rawdb.HashScheme] (the default) or
+// [rawdb.PathScheme].
+func WithStateScheme(scheme string) Option {
+	return options.Func[config](func(c *config) {
+		c.scheme = scheme
+	})
+}
+
+// WithVMConfig sets the [vm.Config] used when processing blocks.
+func WithVMConfig(vmConfig vm.Config) Option {
+	return options.Func[config](func(c *config) {
+		c.vmConfig = vmConfig
+	})
+}
+
+// WithLabel sets the label of the [Result], allowing different configurations
+// of the same [Workload] to be distinguished.
+func WithLabel(label string) Option {
+	return options.Func[config](func(c *config) {
+		c.label = label
+	})
+}
+
+// WithBlockHooks sets functions to be called immediately before and after
+// processing each block, either of which MAY be nil. Their signatures match
+// the StartBlock and FinishBlock methods of the `parallel.Processor`. The time
+// spent in hooks is included in measurements.
+//
+// The post-state of each block is validated after `after` is called, so hooks
+// MUST NOT modify state unless the [Workload] accounts for it.
+func WithBlockHooks(
+	before func(*state.StateDB, params.Rules, *types.Block) error,
+	after func(*state.StateDB, *types.Block, types.Receipts),
+) Option {
+	return options.Func[config](func(c *config) {
+		c.before = before
+		c.after = after
+	})
+}
+
+// Process processes, validates, and commits every block of the [Chain] on top
+// of a fresh, in-memory database. Only block processing, validation, and
+// commitment are measured.
+func (c *Chain) Process(opts ...Option) (*Result, error) {
+	conf := options.ApplyTo(&config{scheme: rawdb.HashScheme}, opts...)
+
+	bc, err := core.NewBlockChain(
+		rawdb.NewMemoryDatabase(),
+		&core.CacheConfig{
+			TrieCleanLimit: 256,
+			TrieDirtyLimit: 256,
+			TrieTimeLimit:  5 * time.Minute,
+			StateScheme:    conf.scheme,
+		},
+		c.genesis, nil, ethash.NewFaker(), conf.vmConfig, nil, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer bc.Stop()
+
+	chainConfig := c.genesis.Config
+	parent := bc.Genesis().Header()
+
+	start := time.Now()
+	for _, b := range c.blocks {
+		sdb, err := bc.StateAt(parent.Root)
+		if err != nil {
+			return nil, err
+		}
+		if conf.before != nil {
+			rules := chainConfig.Rules(b.Number(), false, b.Time())
+			if err := conf.before(sdb, rules, b); err != nil {
+				return nil, fmt.Errorf("block %d: before hook: %v", b.NumberU64(), err)
+			}
+		}
+		receipts, _, usedGas, err := bc.Processor().Process(b, sdb, conf.vmConfig)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: processing: %v", b.NumberU64(), err)
+		}
+		if conf.after != nil {
+			conf.after(sdb, b, receipts)
+		}
+		if err := bc.Validator().ValidateState(b, sdb, receipts, usedGas); err != nil {
+			return nil, fmt.Errorf("block %d: %v", b.NumberU64(), err)
+		}
+		if _, err := sdb.Commit(b.NumberU64(), chainConfig.IsEIP158(b.Number())); err != nil {
+			return nil, fmt.Errorf("block %d: committing state: %v", b.NumberU64(), err)
+		}
+		parent = b.Header()
+	}
+	return c.result(conf, time.Since(start)), nil
+}
+
+func (c *Chain) result(conf *config, d time.Duration) *Result {
+	r := &Result{
+		Label:    conf.label,
+		Workload: c.workload.Name(),
+		Scheme:   conf.scheme,
+		Blocks:   len(c.blocks),
+		Txs:      c.txs,
+		Gas:      c.gas,
+		Duration: d,
+	}
+	if s := d.Seconds(); s > 0 {
+		r.BlocksPerSecond = float64(r.Blocks) / s
+		r.TxsPerSecond = float64(r.Txs) / s
+		r.MGasPerSecond = float64(r.Gas) / 1e6 / s
+	}
+	return r
+}
+
+// A Result is the outcome of a single call to [Chain.Process].
+type Result struct {
+	Label    string        `json:"label,omitempty"`
+	Workload string        `json:"workload"`
+	Scheme   string        `json:"scheme"`
+	Blocks   int           `json:"blocks"`
+	Txs      int           `json:"txs"`
+	Gas      uint64        `json:"gas"`
+	Duration time.Duration `json:"durationNanos"`
+
+	BlocksPerSecond float64 `json:"blocksPerSecond"`
+	TxsPerSecond    float64 `json:"txsPerSecond"`
+	MGasPerSecond   float64 `json:"mgasPerSecond"`
+}
+
+// WriteJSON writes each [Result] to `w` as a single line of JSON.
+func WriteJSON(w io.Writer, rs ...*Result) error {
+	enc := json.NewEncoder(w)
+	for _, r := range rs {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReportTo reports the throughput of the [Result] as custom benchmark metrics.
+func (r *Result) ReportTo(b *testing.B) {
+	b.Helper()
+	b.ReportMetric(r.BlocksPerSecond, "blocks/s")
+	b.ReportMetric(r.TxsPerSecond, "txs/s")
+	b.ReportMetric(r.MGasPerSecond, "Mgas/s")
+}
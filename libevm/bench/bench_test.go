@@ -0,0 +1,120 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/params"
+)
+
+func workloads() []Workload {
+	return []Workload{
+		Transfers(),
+		StorageWrites(),
+		PrecompileCalls(common.BytesToAddress([]byte{2}), 256), // SHA256
+	}
+}
+
+func TestProcess(t *testing.T) {
+	const (
+		blocks      = 3
+		txsPerBlock = 5
+	)
+
+	for _, w := range workloads() {
+		chain, err := Generate(w, blocks, txsPerBlock)
+		require.NoErrorf(t, err, "Generate(%s)", w.Name())
+
+		for _, scheme := range []string{rawdb.HashScheme, rawdb.PathScheme} {
+			t.Run(fmt.Sprintf("%s/%s", w.Name(), scheme), func(t *testing.T) {
+				var before, after int
+				got, err := chain.Process(
+					WithStateScheme(scheme),
+					WithLabel("test"),
+					WithBlockHooks(
+						func(*state.StateDB, params.Rules, *types.Block) error {
+							before++
+							return nil
+						},
+						func(_ *state.StateDB, b *types.Block, rs types.Receipts) {
+							after++
+							assert.Lenf(t, rs, len(b.Transactions()), "receipts passed to after hook of block %d", b.NumberU64())
+						},
+					),
+				)
+				require.NoError(t, err, "Process()")
+
+				assert.Equal(t, blocks, before, "before-hook calls")
+				assert.Equal(t, blocks, after, "after-hook calls")
+				assert.Equal(t, "test", got.Label, "Label")
+				assert.Equal(t, w.Name(), got.Workload, "Workload")
+				assert.Equal(t, scheme, got.Scheme, "Scheme")
+				assert.Equal(t, blocks, got.Blocks, "Blocks")
+				assert.Equal(t, blocks*txsPerBlock, got.Txs, "Txs")
+				assert.GreaterOrEqual(t, got.Gas, uint64(blocks*txsPerBlock*params.TxGas), "Gas")
+				assert.Positive(t, got.TxsPerSecond, "TxsPerSecond")
+			})
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	in := []*Result{
+		{Workload: "a", Blocks: 1},
+		{Workload: "b", Label: "x", Blocks: 2},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, in...), "WriteJSON()")
+
+	dec := json.NewDecoder(&buf)
+	var got []*Result
+	for dec.More() {
+		r := new(Result)
+		require.NoError(t, dec.Decode(r), "%T.Decode()", dec)
+		got = append(got, r)
+	}
+	assert.Equal(t, in, got, "JSON round trip")
+}
+
+func BenchmarkProcess(b *testing.B) {
+	for _, w := range workloads() {
+		chain, err := Generate(w, 10, 100)
+		require.NoErrorf(b, err, "Generate(%s)", w.Name())
+
+		for _, scheme := range []string{rawdb.HashScheme, rawdb.PathScheme} {
+			b.Run(fmt.Sprintf("%s/%s", w.Name(), scheme), func(b *testing.B) {
+				var res *Result
+				for range b.N {
+					res, err = chain.Process(WithStateScheme(scheme))
+					require.NoError(b, err, "Process()")
+				}
+				res.ReportTo(b)
+			})
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package bench
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A Workload generates the transactions of synthetic blocks. Implementations
+// MUST be deterministic.
+type Workload interface {
+	// Name identifies the workload in a [Result].
+	Name() string
+	// Alloc returns genesis accounts required by the workload, in addition to
+	// the funded sender of all transactions. It MAY return nil.
+	Alloc() types.GenesisAlloc
+	// Call returns the `tx`th transaction in the block with the specified
+	// number.
+	Call(block uint64, tx int) Call
+}
+
+// A Call describes a single synthetic transaction, signed by the harness.
+type Call struct {
+	To    *common.Address
+	Value *big.Int
+	Data  []byte
+	Gas   uint64
+}
+
+// seed returns a deterministic hash derived from its arguments.
+func seed(block uint64, tx int, salt byte) common.Hash {
+	var buf [17]byte
+	binary.BigEndian.PutUint64(buf[:8], block)
+	binary.BigEndian.PutUint64(buf[8:16], uint64(tx)) //nolint:gosec // Non-negative transaction index
+	buf[16] = salt
+	return crypto.Keccak256Hash(buf[:])
+}
+
+// Transfers returns a [Workload] of value transfers, each to a previously
+// non-existent account.
+func Transfers() Workload {
+	return transfers{}
+}
+
+type transfers struct{}
+
+func (transfers) Name() string              { return "transfers" }
+func (transfers) Alloc() types.GenesisAlloc { return nil }
+
+func (transfers) Call(block uint64, tx int) Call {
+	to := common.BytesToAddress(seed(block, tx, 0).Bytes())
+	return Call{
+		To:    &to,
+		Value: big.NewInt(1),
+		Gas:   params.TxGas,
+	}
+}
+
+// StorageContract is the address of the contract used by the [StorageWrites]
+// workload.
+var StorageContract = common.HexToAddress("0x5709a6e")
+
+// storageCode stores the second calldata word in the slot identified by the
+// first: PUSH1 0x20 CALLDATALOAD PUSH1 0x00 CALLDATALOAD SSTORE STOP.
+var storageCode = []byte{0x60, 0x20, 0x35, 0x60, 0x00, 0x35, 0x55, 0x00}
+
+// StorageWrites returns a [Workload] of transactions that each write a
+// previously unset storage slot of [StorageContract].
+func StorageWrites() Workload {
+	return storageWrites{}
+}
+
+type storageWrites struct{}
+
+func (storageWrites) Name() string { return "storage" }
+
+func (storageWrites) Alloc() types.GenesisAlloc {
+	return types.GenesisAlloc{
+		StorageContract: {Code: storageCode, Balance: new(big.Int)},
+	}
+}
+
+func (storageWrites) Call(block uint64, tx int) Call {
+	key, val := seed(block, tx, 0), seed(block, tx, 1)
+	return Call{
+		To:    &StorageContract,
+		Value: new(big.Int),
+		Data:  append(key.Bytes(), val.Bytes()...),
+		Gas:   100_000,
+	}
+}
+
+// PrecompileCalls returns a [Workload] of transactions that each call the
+// precompile at the specified address with `inputLen` bytes of input. The
+// precompile MUST accept arbitrary input of the specified length and its cost
+// MUST be less than 1M gas.
+func PrecompileCalls(addr common.Address, inputLen int) Workload {
+	return precompileCalls{addr, inputLen}
+}
+
+type precompileCalls struct {
+	addr     common.Address
+	inputLen int
+}
+
+func (precompileCalls) Name() string              { return "precompile" }
+func (precompileCalls) Alloc() types.GenesisAlloc { return nil }
+
+func (p precompileCalls) Call(block uint64, tx int) Call {
+	data := make([]byte, 0, p.inputLen+common.HashLength)
+	for salt := byte(0); len(data) < p.inputLen; salt++ {
+		data = append(data, seed(block, tx, salt).Bytes()...)
+	}
+	return Call{
+		To:    &p.addr,
+		Value: new(big.Int),
+		Data:  data[:p.inputLen],
+		Gas:   params.TxGas + params.TxDataNonZeroGasEIP2028*uint64(p.inputLen) + 1_000_000, //nolint:gosec // Non-negative length
+	}
+}
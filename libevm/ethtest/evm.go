@@ -109,3 +109,10 @@ func WithChainConfig(c *params.ChainConfig) EVMOption {
 		args.chainConfig = c
 	})
 }
+
+// WithVMConfig overrides the default, zero-value, configuration.
+func WithVMConfig(c vm.Config) EVMOption {
+	return funcOption(func(args *evmConstructorArgs) {
+		args.config = c
+	})
+}
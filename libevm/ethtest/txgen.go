@@ -0,0 +1,309 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/trie"
+)
+
+// A TxShape is a class of transaction produced by a [TxGenerator].
+type TxShape uint8
+
+// Transaction shapes. All shapes except [ShapeBlob] are
+// [types.DynamicFeeTxType] transactions.
+const (
+	// ShapeTransfer is a value transfer to a pseudorandom address.
+	ShapeTransfer TxShape = iota
+	// ShapeContractCall is a call, with pseudorandom data, to one of the
+	// addresses passed to [WithCallTargets].
+	ShapeContractCall
+	// ShapeAccessListHeavy is a call to a pseudorandom address with a large,
+	// pseudorandom access list.
+	ShapeAccessListHeavy
+	// ShapeBlob is a [types.BlobTxType] transaction, without a sidecar, to a
+	// pseudorandom address.
+	ShapeBlob
+)
+
+// String returns a human-readable name of the TxShape.
+func (s TxShape) String() string {
+	switch s {
+	case ShapeTransfer:
+		return "transfer"
+	case ShapeContractCall:
+		return "contract_call"
+	case ShapeAccessListHeavy:
+		return "access_list_heavy"
+	case ShapeBlob:
+		return "blob"
+	default:
+		return fmt.Sprintf("%T(%d)", s, uint8(s))
+	}
+}
+
+// A TxGenerator produces signed transactions of configurable shapes, from a
+// fixed set of senders. All transactions, and the senders' keys, are
+// deterministic given the seed and options passed to [NewTxGenerator].
+//
+// A TxGenerator is not safe for concurrent use.
+type TxGenerator struct {
+	tb      testing.TB
+	rng     *PseudoRand
+	signer  types.Signer
+	chainID *uint256.Int
+	rules   params.Rules
+	senders []*txSender
+	cfg     *txGeneratorConfig
+}
+
+type txSender struct {
+	key   *ecdsa.PrivateKey
+	addr  common.Address
+	nonce uint64
+}
+
+type txGeneratorConfig struct {
+	numSenders  int
+	shapes      []TxShape
+	callTargets []common.Address
+	callGas     uint64
+	gasFeeCap   *big.Int
+	gasTipCap   *big.Int
+	blobFeeCap  *big.Int
+}
+
+// A TxGeneratorOption configures a [TxGenerator].
+type TxGeneratorOption = options.Option[txGeneratorConfig]
+
+// WithSenders sets the number of senders, from which each transaction's
+// sender is pseudorandomly selected. The default is 1.
+func WithSenders(n int) TxGeneratorOption {
+	return options.Func[txGeneratorConfig](func(c *txGeneratorConfig) {
+		c.numSenders = n
+	})
+}
+
+// WithShapes sets the shapes from which [TxGenerator.Next] pseudorandomly
+// selects. Shapes MAY be repeated to increase their relative frequency. The
+// default is only [ShapeTransfer].
+func WithShapes(shapes ...TxShape) TxGeneratorOption {
+	return options.Func[txGeneratorConfig](func(c *txGeneratorConfig) {
+		c.shapes = shapes
+	})
+}
+
+// WithCallTargets sets the addresses from which the recipient of every
+// [ShapeContractCall] transaction is pseudorandomly selected. It is required
+// if said shape is generated.
+func WithCallTargets(addrs ...common.Address) TxGeneratorOption {
+	return options.Func[txGeneratorConfig](func(c *txGeneratorConfig) {
+		c.callTargets = addrs
+	})
+}
+
+// WithCallGas sets the gas provided to [ShapeContractCall] transactions in
+// addition to their intrinsic gas. The default is 100k.
+func WithCallGas(gas uint64) TxGeneratorOption {
+	return options.Func[txGeneratorConfig](func(c *txGeneratorConfig) {
+		c.callGas = gas
+	})
+}
+
+// WithFees sets the fee and tip caps of all transactions, and the blob fee cap
+// of [ShapeBlob] transactions. All default to 1 gwei.
+func WithFees(gasFeeCap, gasTipCap, blobFeeCap *big.Int) TxGeneratorOption {
+	return options.Func[txGeneratorConfig](func(c *txGeneratorConfig) {
+		c.gasFeeCap = gasFeeCap
+		c.gasTipCap = gasTipCap
+		c.blobFeeCap = blobFeeCap
+	})
+}
+
+// NewTxGenerator constructs a new [TxGenerator] for the chain. Transactions are
+// signed with [types.LatestSigner] and their intrinsic gas is computed under
+// the chain's [params.Rules] at genesis.
+func NewTxGenerator(tb testing.TB, seed uint64, config *params.ChainConfig, opts ...TxGeneratorOption) *TxGenerator {
+	tb.Helper()
+
+	gwei := big.NewInt(params.GWei)
+	cfg := options.ApplyTo(&txGeneratorConfig{
+		numSenders: 1,
+		shapes:     []TxShape{ShapeTransfer},
+		callGas:    100_000,
+		gasFeeCap:  gwei,
+		gasTipCap:  gwei,
+		blobFeeCap: gwei,
+	}, opts...)
+	require.Positive(tb, cfg.numSenders, "number of senders")
+	require.NotEmpty(tb, cfg.shapes, "transaction shapes")
+
+	g := &TxGenerator{
+		tb:      tb,
+		rng:     NewPseudoRand(seed),
+		signer:  types.LatestSigner(config),
+		chainID: uint256.MustFromBig(config.ChainID),
+		rules:   config.Rules(new(big.Int), true, 0),
+		cfg:     cfg,
+	}
+	for i := range cfg.numSenders {
+		key := UNSAFEDeterministicPrivateKey(tb, fmt.Appendf(nil, "tx-generator-%d-%d", seed, i))
+		g.senders = append(g.senders, &txSender{
+			key:  key,
+			addr: crypto.PubkeyToAddress(key.PublicKey),
+		})
+	}
+	return g
+}
+
+// Senders returns the addresses of all senders, in a fixed order.
+func (g *TxGenerator) Senders() []common.Address {
+	addrs := make([]common.Address, len(g.senders))
+	for i, s := range g.senders {
+		addrs[i] = s.addr
+	}
+	return addrs
+}
+
+// Alloc returns a genesis allocation that funds every sender with the balance.
+func (g *TxGenerator) Alloc(balance *big.Int) types.GenesisAlloc {
+	alloc := make(types.GenesisAlloc)
+	for _, s := range g.senders {
+		alloc[s.addr] = types.Account{Balance: new(big.Int).Set(balance)}
+	}
+	return alloc
+}
+
+// Fund adds the balance to every sender's account.
+func (g *TxGenerator) Fund(sdb *state.StateDB, balance *uint256.Int) {
+	for _, s := range g.senders {
+		sdb.AddBalance(s.addr, balance)
+	}
+}
+
+// Txs returns the next `n` transactions; see [TxGenerator.Next].
+func (g *TxGenerator) Txs(n int) types.Transactions {
+	txs := make(types.Transactions, n)
+	for i := range txs {
+		txs[i] = g.Next()
+	}
+	return txs
+}
+
+// Next returns a transaction of a pseudorandom shape selected from those
+// passed to [WithShapes].
+func (g *TxGenerator) Next() *types.Transaction {
+	return g.NextOf(g.cfg.shapes[g.rng.Intn(len(g.cfg.shapes))])
+}
+
+// NextOf returns a transaction of the specified shape, from a pseudorandom
+// sender, using the sender's next nonce.
+func (g *TxGenerator) NextOf(shape TxShape) *types.Transaction {
+	g.tb.Helper()
+
+	sender := g.senders[g.rng.Intn(len(g.senders))]
+	to := g.rng.Address()
+	var (
+		value  *big.Int
+		data   []byte
+		al     types.AccessList
+		hashes []common.Hash
+	)
+
+	switch shape {
+	case ShapeTransfer:
+		value = new(big.Int).SetUint64(1 + g.rng.Uint64n(params.GWei))
+	case ShapeContractCall:
+		require.NotEmptyf(g.tb, g.cfg.callTargets, "%v transaction without %T targets", shape, WithCallTargets)
+		to = g.cfg.callTargets[g.rng.Intn(len(g.cfg.callTargets))]
+		data = g.rng.Bytes(uint(4 + 32*g.rng.Intn(4)))
+	case ShapeAccessListHeavy:
+		for range 4 + g.rng.Intn(5) {
+			tuple := types.AccessTuple{Address: g.rng.Address()}
+			for range 2 + g.rng.Intn(7) {
+				tuple.StorageKeys = append(tuple.StorageKeys, g.rng.Hash())
+			}
+			al = append(al, tuple)
+		}
+	case ShapeBlob:
+		for range 1 + g.rng.Intn(2) {
+			h := g.rng.Hash()
+			h[0] = 0x01 // KZG versioned hash
+			hashes = append(hashes, h)
+		}
+	default:
+		g.tb.Fatalf("unsupported %T %v", shape, shape)
+	}
+
+	gas, err := core.IntrinsicGas(data, al, false, g.rules)
+	require.NoErrorf(g.tb, err, "core.IntrinsicGas() for %v transaction", shape)
+	if shape == ShapeContractCall {
+		gas += g.cfg.callGas
+	}
+
+	var inner types.TxData
+	if shape == ShapeBlob {
+		inner = &types.BlobTx{
+			ChainID:    g.chainID,
+			Nonce:      sender.nonce,
+			GasTipCap:  uint256.MustFromBig(g.cfg.gasTipCap),
+			GasFeeCap:  uint256.MustFromBig(g.cfg.gasFeeCap),
+			Gas:        gas,
+			To:         to,
+			Value:      new(uint256.Int),
+			BlobFeeCap: uint256.MustFromBig(g.cfg.blobFeeCap),
+			BlobHashes: hashes,
+		}
+	} else {
+		inner = &types.DynamicFeeTx{
+			ChainID:    g.chainID.ToBig(),
+			Nonce:      sender.nonce,
+			GasTipCap:  new(big.Int).Set(g.cfg.gasTipCap),
+			GasFeeCap:  new(big.Int).Set(g.cfg.gasFeeCap),
+			Gas:        gas,
+			To:         &to,
+			Value:      value,
+			Data:       data,
+			AccessList: al,
+		}
+	}
+
+	tx, err := types.SignNewTx(sender.key, g.signer, inner)
+	require.NoErrorf(g.tb, err, "types.SignNewTx() for %v transaction", shape)
+	sender.nonce++
+	return tx
+}
+
+// NewBlock is a convenience wrapper around [types.NewBlock], without uncles
+// nor receipts.
+func NewBlock(header *types.Header, txs types.Transactions) *types.Block {
+	return types.NewBlock(header, txs, nil, nil, trie.NewStackTrie(nil))
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestTxGenerator(t *testing.T) {
+	config := params.MergedTestChainConfig
+	target := common.Address{'t', 'a', 'r', 'g', 'e', 't'}
+	opts := []TxGeneratorOption{
+		WithSenders(3),
+		WithShapes(ShapeTransfer, ShapeContractCall, ShapeAccessListHeavy, ShapeBlob),
+		WithCallTargets(target),
+	}
+	const numTxs = 40
+
+	hashes := func(txs types.Transactions) []common.Hash {
+		var hs []common.Hash
+		for _, tx := range txs {
+			hs = append(hs, tx.Hash())
+		}
+		return hs
+	}
+	gen := NewTxGenerator(t, 42, config, opts...)
+	txs := gen.Txs(numTxs)
+
+	t.Run("deterministic", func(t *testing.T) {
+		same := NewTxGenerator(t, 42, config, opts...)
+		assert.Equal(t, gen.Senders(), same.Senders(), "senders with same seed")
+		assert.Equal(t, hashes(txs), hashes(same.Txs(numTxs)), "transaction hashes with same seed")
+
+		other := NewTxGenerator(t, 43, config, opts...)
+		assert.NotEqual(t, gen.Senders(), other.Senders(), "senders with different seed")
+		assert.NotEqual(t, hashes(txs), hashes(other.Txs(numTxs)), "transaction hashes with different seed")
+	})
+
+	t.Run("shapes", func(t *testing.T) {
+		signer := types.LatestSigner(config)
+		nonces := make(map[common.Address]uint64)
+		txTypes := make(map[uint8]int)
+		for i, tx := range txs {
+			from, err := signer.Sender(tx)
+			require.NoErrorf(t, err, "%T.Sender(tx[%d])", signer, i)
+			require.Containsf(t, gen.Senders(), from, "sender of tx[%d]", i)
+			assert.Equalf(t, nonces[from], tx.Nonce(), "nonce of tx[%d]", i)
+			nonces[from]++
+
+			txTypes[tx.Type()]++
+			if tx.To() != nil && *tx.To() == target {
+				assert.NotEmptyf(t, tx.Data(), "data of contract call tx[%d]", i)
+			}
+		}
+		assert.Len(t, nonces, 3, "all senders used")
+		assert.Len(t, txTypes, 2, "dynamic-fee and blob transactions")
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		header := &types.Header{
+			Number:     big.NewInt(0),
+			BaseFee:    big.NewInt(params.GWei),
+			GasLimit:   30e6,
+			Difficulty: new(big.Int),
+		}
+		block := NewBlock(header, txs)
+		require.Len(t, block.Transactions(), numTxs, "block transactions")
+
+		sdb, evm := NewZeroEVM(t,
+			WithChainConfig(config),
+			WithBlockContext(vm.BlockContext{
+				CanTransfer: core.CanTransfer,
+				Transfer:    core.Transfer,
+				BlockNumber: header.Number,
+				BaseFee:     header.BaseFee,
+				BlobBaseFee: big.NewInt(1),
+				GasLimit:    header.GasLimit,
+				Random:      &common.Hash{},
+			}),
+		)
+		gen.Fund(sdb, uint256.NewInt(params.Ether))
+
+		signer := types.LatestSigner(config)
+		gp := new(core.GasPool).AddGas(math.MaxUint64)
+		for i, tx := range block.Transactions() {
+			msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+			require.NoErrorf(t, err, "core.TransactionToMessage(tx[%d])", i)
+			evm.Reset(core.NewEVMTxContext(msg), sdb)
+			res, err := core.ApplyMessage(evm, msg, gp)
+			require.NoErrorf(t, err, "core.ApplyMessage(tx[%d])", i)
+			require.NoErrorf(t, res.Err, "execution of tx[%d]", i)
+		}
+	})
+}
+
+func TestTxGeneratorAlloc(t *testing.T) {
+	gen := NewTxGenerator(t, 0, params.MergedTestChainConfig, WithSenders(2))
+	balance := big.NewInt(params.Ether)
+	alloc := gen.Alloc(balance)
+	require.Len(t, alloc, 2)
+	for _, s := range gen.Senders() {
+		assert.Equalf(t, balance, alloc[s].Balance, "balance of %v", s)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package hellochain
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// HeaderExtra is the [types.Header] extra payload. Its MOTD is appended as the
+// final item of the header's RLP encoding and therefore contributes to the
+// block hash.
+type HeaderExtra struct {
+	// MOTD is the message of the day, used by the greeter precompile. It MUST
+	// be empty before the Hello fork and non-empty thereafter.
+	MOTD string
+
+	types.NOOPHeaderHooks
+}
+
+var _ types.HeaderRLPTransformer = (*HeaderExtra)(nil)
+
+var errMissingMOTD = errors.New("missing MOTD")
+
+// TransformEncodedHeaderRLP appends the MOTD to the encoded header.
+func (e *HeaderExtra) TransformEncodedHeaderRLP(_ *types.Header, tree *rlp.ItemNode) error {
+	tree.Children = append(tree.Children, &rlp.ItemNode{
+		Kind:    rlp.String,
+		Content: []byte(e.MOTD),
+	})
+	return nil
+}
+
+// TransformHeaderRLPForDecoding strips the MOTD, appended by
+// [HeaderExtra.TransformEncodedHeaderRLP], from the encoded header.
+func (e *HeaderExtra) TransformHeaderRLPForDecoding(_ *types.Header, tree *rlp.ItemNode) error {
+	n := len(tree.Children)
+	if n == 0 || tree.Children[n-1].Kind != rlp.String {
+		return errMissingMOTD
+	}
+	e.MOTD = string(tree.Children[n-1].Content)
+	tree.Children = tree.Children[:n-1]
+	return nil
+}
+
+// PostRPCMarshal adds the MOTD to the RPC representation of the header.
+func (e *HeaderExtra) PostRPCMarshal(_ *types.Header, marshalled map[string]any) {
+	marshalled["motd"] = e.MOTD
+}
+
+// MOTD returns the message of the day carried by the header.
+func MOTD(h *types.Header) string {
+	return typesExtras.Header.Get(h).MOTD
+}
+
+// SetMOTD sets the message of the day carried by the header.
+func SetMOTD(h *types.Header, motd string) {
+	typesExtras.Header.Set(h, &HeaderExtra{MOTD: motd})
+}
+
+// BodyExtra is the [types.Body] and [types.Block] extra payload. Its postcards
+// are appended to the required RLP fields of both types, before the optional
+// withdrawals. As the block hash is that of the header, postcards do not
+// contribute to it.
+type BodyExtra struct {
+	Postcards [][]byte
+
+	types.NOOPBlockBodyHooks
+}
+
+// Copy returns a deep copy of the payload.
+func (e *BodyExtra) Copy() *BodyExtra {
+	cp := &BodyExtra{
+		Postcards: make([][]byte, len(e.Postcards)),
+	}
+	for i, p := range e.Postcards {
+		cp.Postcards[i] = slices.Clone(p)
+	}
+	return cp
+}
+
+// BlockRLPFieldsForEncoding appends the postcards to the block's required
+// fields.
+func (e *BodyExtra) BlockRLPFieldsForEncoding(b *types.BlockRLPProxy) *rlp.Fields {
+	return &rlp.Fields{
+		Required: []any{b.Header, b.Txs, b.Uncles, e.Postcards},
+		Optional: []any{b.Withdrawals},
+	}
+}
+
+// BlockRLPFieldPointersForDecoding is the inverse of
+// [BodyExtra.BlockRLPFieldsForEncoding].
+func (e *BodyExtra) BlockRLPFieldPointersForDecoding(b *types.BlockRLPProxy) *rlp.Fields {
+	return &rlp.Fields{
+		Required: []any{&b.Header, &b.Txs, &b.Uncles, &e.Postcards},
+		Optional: []any{&b.Withdrawals},
+	}
+}
+
+// BodyRLPFieldsForEncoding appends the postcards to the body's required
+// fields.
+func (e *BodyExtra) BodyRLPFieldsForEncoding(b *types.Body) *rlp.Fields {
+	return &rlp.Fields{
+		Required: []any{b.Transactions, b.Uncles, e.Postcards},
+		Optional: []any{b.Withdrawals},
+	}
+}
+
+// BodyRLPFieldPointersForDecoding is the inverse of
+// [BodyExtra.BodyRLPFieldsForEncoding].
+func (e *BodyExtra) BodyRLPFieldPointersForDecoding(b *types.Body) *rlp.Fields {
+	return &rlp.Fields{
+		Required: []any{&b.Transactions, &b.Uncles, &e.Postcards},
+		Optional: []any{&b.Withdrawals},
+	}
+}
+
+// Postcards returns the postcards carried by the block.
+func Postcards(b *types.Block) [][]byte {
+	return typesExtras.Block.Get(b).Postcards
+}
+
+// AccountExtra is the [types.StateAccount] extra payload.
+type AccountExtra struct {
+	// Greetings is the number of successful calls that the account has made to
+	// the greeter precompile.
+	Greetings uint64
+}
@@ -0,0 +1,272 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package hellochain
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/trie"
+	"github.com/ava-labs/libevm/triedb"
+	"github.com/ava-labs/libevm/triedb/hashdb"
+)
+
+// DefaultMOTD is used as a block's MOTD after the Hello fork if none is
+// provided to [Chain.BuildBlock].
+const DefaultMOTD = "Hello"
+
+// ErrMOTDBeforeFork is returned by [Chain.BuildBlock] if a MOTD is provided
+// before the Hello fork.
+var ErrMOTDBeforeFork = errors.New("MOTD before Hello fork")
+
+// A Chain is a minimal, single-writer hellochain node. It builds and executes
+// blocks on top of its head, without consensus, and is not safe for concurrent
+// use.
+type Chain struct {
+	config  *params.ChainConfig
+	db      ethdb.Database
+	trieDB  *triedb.Database
+	stateDB state.Database
+	head    *types.Block
+}
+
+var _ core.ChainContext = (*Chain)(nil)
+
+// An Option configures a [Chain].
+type Option = options.Option[chainConfig]
+
+type chainConfig struct {
+	trieDB triedb.DBConstructor
+}
+
+// WithTrieDB overrides the default hashdb backend of the [triedb.Database]. A
+// Firewood-backed chain, for example, would provide its constructor here; no
+// other code changes are required.
+func WithTrieDB(ctor triedb.DBConstructor) Option {
+	return options.Func[chainConfig](func(c *chainConfig) {
+		c.trieDB = ctor
+	})
+}
+
+// NewChain commits the genesis block to the database and returns a [Chain]
+// with it as the head. [Register] MUST have been called beforehand.
+//
+// The genesis allocation is extended, if necessary, with a non-empty account
+// at [GreeterAddress] so that it isn't deleted as per EIP-161 when only its
+// storage is modified.
+func NewChain(db ethdb.Database, genesis *core.Genesis, opts ...Option) (*Chain, error) {
+	tdbConfig := &triedb.Config{HashDB: hashdb.Defaults}
+	if cfg := options.As(opts...); cfg.trieDB != nil {
+		tdbConfig = &triedb.Config{DBOverride: cfg.trieDB}
+	}
+	tdb := triedb.NewDatabase(db, tdbConfig)
+
+	g := *genesis
+	g.Alloc = maps.Clone(genesis.Alloc)
+	if g.Alloc == nil {
+		g.Alloc = make(types.GenesisAlloc)
+	}
+	if _, ok := g.Alloc[GreeterAddress]; !ok {
+		g.Alloc[GreeterAddress] = types.Account{
+			Nonce:   1,
+			Balance: new(big.Int),
+		}
+	}
+	head, err := g.Commit(db, tdb)
+	if err != nil {
+		return nil, fmt.Errorf("committing genesis: %v", err)
+	}
+
+	return &Chain{
+		config:  g.Config,
+		db:      db,
+		trieDB:  tdb,
+		stateDB: state.NewDatabaseWithNodeDB(db, tdb),
+		head:    head,
+	}, nil
+}
+
+// Close closes the underlying [triedb.Database].
+func (c *Chain) Close() error {
+	return c.trieDB.Close()
+}
+
+// Config returns the chain's configuration.
+func (c *Chain) Config() *params.ChainConfig {
+	return c.config
+}
+
+// Head returns the last block built by the chain.
+func (c *Chain) Head() *types.Block {
+	return c.head
+}
+
+// Engine returns nil as the chain has no consensus engine. It is only required
+// to implement [core.ChainContext].
+func (c *Chain) Engine() consensus.Engine {
+	return nil
+}
+
+// GetHeader returns the header read from the database, or nil if it doesn't
+// exist.
+func (c *Chain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return rawdb.ReadHeader(c.db, hash, number)
+}
+
+// BlockByNumber returns the canonical block read from the database, or nil if
+// it doesn't exist. All extras are decoded from their RLP encodings.
+func (c *Chain) BlockByNumber(number uint64) *types.Block {
+	hash := rawdb.ReadCanonicalHash(c.db, number)
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	return rawdb.ReadBlock(c.db, hash, number)
+}
+
+// State returns the state as of the head block.
+func (c *Chain) State() (*state.StateDB, error) {
+	return state.New(c.head.Root(), c.stateDB, nil)
+}
+
+// Greetings returns the number of successful greetings sent by the account, as
+// of the head block.
+func (c *Chain) Greetings(addr common.Address) (uint64, error) {
+	sdb, err := c.State()
+	if err != nil {
+		return 0, err
+	}
+	return state.GetExtra(sdb, typesExtras.StateAccount, addr).Greetings, nil
+}
+
+// TotalGreetings returns the total number of successful greetings, as of the
+// head block.
+func (c *Chain) TotalGreetings() (uint64, error) {
+	sdb, err := c.State()
+	if err != nil {
+		return 0, err
+	}
+	total := new(uint256.Int).SetBytes32(sdb.GetState(GreeterAddress, TotalGreetingsSlot).Bytes())
+	return total.Uint64(), nil
+}
+
+// BuildBlock executes the transactions on top of the head block, writes the
+// resulting block, receipts, and state to the database, and sets the block as
+// the new head. The MOTD MUST be empty before the Hello fork and defaults to
+// [DefaultMOTD] thereafter. Postcards are included in the block body.
+func (c *Chain) BuildBlock(motd string, postcards [][]byte, txs ...*types.Transaction) (*types.Block, types.Receipts, error) {
+	parent := c.head.Header()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 1,
+		Difficulty: big.NewInt(1),
+	}
+	rules := c.config.Rules(header.Number, false, header.Time)
+	switch hello := FromRules(&rules).IsHello; {
+	case !hello && motd != "":
+		return nil, nil, ErrMOTDBeforeFork
+	case hello && motd == "":
+		motd = DefaultMOTD
+	}
+	SetMOTD(header, motd)
+
+	sdb, err := state.New(parent.Root, c.stateDB, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The [parallel.Processor] identifies blocks by hash so MUST receive the
+	// same instance in both StartBlock() and FinishBlock(), even though the
+	// final block, with a state root, has a different hash.
+	pending := types.NewBlock(header, txs, nil, nil, trie.NewStackTrie(nil))
+	if err := processor.StartBlock(sdb, rules, pending); err != nil {
+		return nil, nil, err
+	}
+	receipts, err := c.execute(sdb, rules, header, txs)
+	if err := errors.Join(err, processor.FinishBlock(sdb, pending, receipts)); err != nil {
+		return nil, nil, err
+	}
+
+	root, err := sdb.Commit(header.Number.Uint64(), rules.IsEIP158)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.trieDB.Commit(root, false); err != nil {
+		return nil, nil, err
+	}
+	header.Root = root
+
+	block := types.NewBlock(header, txs, nil, receipts, trie.NewStackTrie(nil))
+	typesExtras.Block.Set(block, &BodyExtra{Postcards: postcards})
+
+	hash, number := block.Hash(), block.NumberU64()
+	rawdb.WriteBlock(c.db, block)
+	rawdb.WriteReceipts(c.db, hash, number, receipts)
+	rawdb.WriteCanonicalHash(c.db, hash, number)
+	rawdb.WriteHeadBlockHash(c.db, hash)
+	rawdb.WriteHeadHeaderHash(c.db, hash)
+	c.head = block
+
+	return block, receipts, nil
+}
+
+// execute applies the transactions to the state and, after the Hello fork,
+// increments the [AccountExtra.Greetings] of the sender of every successful
+// greeting.
+func (c *Chain) execute(sdb *state.StateDB, rules params.Rules, header *types.Header, txs types.Transactions) (types.Receipts, error) {
+	var (
+		gasPool  = new(core.GasPool).AddGas(header.GasLimit)
+		signer   = types.MakeSigner(c.config, header.Number, header.Time)
+		receipts types.Receipts
+		inv      = vm.NewPrecompileInvocations()
+	)
+	for i, tx := range txs {
+		sdb.SetTxContext(tx.Hash(), i)
+		r, err := core.ApplyTransactionWithInvocations(c.config, c, &header.Coinbase, gasPool, sdb, header, tx, &header.GasUsed, vm.Config{}, inv)
+		if err != nil {
+			return receipts, fmt.Errorf("applying transaction %d (%v): %w", i, tx.Hash(), err)
+		}
+		receipts = append(receipts, r)
+
+		if !FromRules(&rules).IsHello || !isGreeting(tx, r) {
+			continue
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return receipts, err
+		}
+		acc := state.GetExtra(sdb, typesExtras.StateAccount, from)
+		acc.Greetings++
+		state.SetExtra(sdb, typesExtras.StateAccount, from, acc)
+	}
+	return receipts, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package hellochain
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/precompiles/parallel"
+)
+
+// GreeterAddress is the address of the greeter precompile, active from the
+// Hello fork.
+var GreeterAddress = common.HexToAddress("0x4e110000000000000000000000000000000000c0")
+
+// GreetingGas is the gas charged, on top of intrinsic gas, for every
+// transaction sent to [GreeterAddress].
+const GreetingGas = 5_000
+
+// TotalGreetingsSlot is the storage slot of [GreeterAddress] holding the total
+// number of greetings, updated at the end of every block.
+var TotalGreetingsSlot = common.Hash{'t', 'o', 't', 'a', 'l'}
+
+// GreetingTopic is the topic of the log emitted for every greeting.
+var GreetingTopic = crypto.Keccak256Hash([]byte("Greeting(bytes)"))
+
+// greeterHandler is a [parallel.Handler] that computes greetings for all
+// transactions sent to [GreeterAddress]. Its results are returned by the
+// precompile, and the number of greetings in each block is added to the
+// [TotalGreetingsSlot] at the end of the block.
+//
+// Note that, as the greeting is computed from the transaction alone, calls to
+// the precompile from other contracts revert.
+type greeterHandler struct{}
+
+var _ parallel.Handler[string, struct{}, greeting, []int] = greeterHandler{}
+
+func addGreeter(p *parallel.Processor) vm.PrecompiledContract {
+	return vm.NewStatefulPrecompile(parallel.AddAsPrecompile(p, greeterHandler{}))
+}
+
+// BeforeBlock returns the MOTD carried by the header, which is empty before
+// the Hello fork.
+func (greeterHandler) BeforeBlock(_ libevm.StateReader, h *types.Header) string {
+	return MOTD(h)
+}
+
+// ShouldProcess returns true, with [GreetingGas], for all transactions sent
+// to [GreeterAddress] after the Hello fork.
+func (greeterHandler) ShouldProcess(tx parallel.IndexedTx, motd string) (bool, uint64) {
+	if to := tx.To(); motd == "" || to == nil || *to != GreeterAddress {
+		return false, 0
+	}
+	return true, GreetingGas
+}
+
+// Prefetch is a no-op as greetings are independent of state.
+func (greeterHandler) Prefetch(libevm.StateReader, parallel.IndexedTx, string) struct{} {
+	return struct{}{}
+}
+
+// Process returns a greeting of the name in the transaction data.
+func (greeterHandler) Process(_ libevm.StateReader, tx parallel.IndexedTx, motd string, _ struct{}) greeting {
+	return greeting(fmt.Sprintf("%s, %s!", motd, tx.Data()))
+}
+
+// PostProcess returns the indices of all processed transactions, in order.
+func (greeterHandler) PostProcess(_ string, res parallel.Results[greeting]) []int {
+	var txs []int
+	for r := range res.TxOrder {
+		txs = append(txs, r.Tx.Index)
+	}
+	return txs
+}
+
+// AfterBlock adds the number of successful greetings to the total.
+func (greeterHandler) AfterBlock(sdb parallel.StateDB, txs []int, b *types.Block, rs types.Receipts) {
+	var n uint64
+	for _, i := range txs {
+		if i < len(rs) && rs[i].Status == types.ReceiptStatusSuccessful {
+			n++
+		}
+	}
+	if n == 0 {
+		return
+	}
+	total := new(uint256.Int).SetBytes32(sdb.GetState(GreeterAddress, TotalGreetingsSlot).Bytes())
+	total.AddUint64(total, n)
+	sdb.SetState(GreeterAddress, TotalGreetingsSlot, total.Bytes32())
+}
+
+func isGreeting(tx *types.Transaction, r *types.Receipt) bool {
+	to := tx.To()
+	return to != nil && *to == GreeterAddress && r.Status == types.ReceiptStatusSuccessful
+}
+
+// A greeting is the result of [greeterHandler.Process].
+type greeting []byte
+
+var _ parallel.PrecompileResult = greeting(nil)
+
+// PrecompileOutput returns the greeting, ignoring the input as it was already
+// used to compute the greeting. Unless called in a read-only context, it also
+// emits the greeting as the data of a log with the [GreetingTopic].
+func (g greeting) PrecompileOutput(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+	if sdb := env.StateDB(); sdb != nil {
+		sdb.AddLog(&types.Log{
+			Address: env.Addresses().EVMSemantic.Self,
+			Topics:  []common.Hash{GreetingTopic},
+			Data:    slices.Clone(g),
+		})
+	}
+	return g, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package hellochain is an end-to-end example of a chain built on libevm. It
+// acts as living documentation of how the various extension points fit
+// together, and as a regression test for changes that cut across them.
+//
+// The following are registered by [Register]:
+//
+//   - [params.ChainConfig] extras defining the "Hello" fork, activated by
+//     timestamp, and [params.Rules] extras that enable the greeter precompile
+//     once the fork is active;
+//   - [types.Header] extras carrying a block's message of the day (MOTD);
+//   - [types.Body] and [types.Block] extras carrying arbitrary postcards;
+//   - [types.StateAccount] extras counting the greetings sent by an account;
+//   - a [parallel.Handler] that computes greetings ahead of EVM execution,
+//     exposed to the EVM as a stateful precompile at [GreeterAddress] and
+//     charged for as a [vm.Preprocessor].
+//
+// A [Chain] processes blocks on top of any [triedb.DBConstructor] backend,
+// defaulting to hashdb, and [NewRPCServer] serves the chain's data under the
+// "hello" namespace.
+//
+// In practice, a chain SHOULD NOT expose a registration function but instead
+// register everything in an init() function. It is exported here so that the
+// example can be imported without side effects.
+package hellochain
+
+import (
+	"math/big"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/precompiles/parallel"
+	"github.com/ava-labs/libevm/params"
+)
+
+var (
+	paramsExtras params.ExtraPayloads[ChainConfigExtra, RulesExtra]
+	typesExtras  types.ExtraPayloads[*HeaderExtra, *BodyExtra, AccountExtra]
+
+	processor *parallel.Processor
+	greeter   vm.PrecompiledContract
+)
+
+// Register registers all extras and hooks, adding the greeter [parallel.Handler]
+// to `p`. It MUST NOT be called more than once, and `p` MUST NOT be closed
+// before the last [Chain] is no longer in use.
+func Register(p *parallel.Processor) {
+	paramsExtras = params.RegisterExtras(params.Extras[ChainConfigExtra, RulesExtra]{
+		NewRules: newRulesExtra,
+	})
+	typesExtras = types.RegisterExtras[
+		HeaderExtra, *HeaderExtra,
+		BodyExtra, *BodyExtra,
+		AccountExtra,
+	]()
+	processor = p
+	greeter = addGreeter(p)
+	vm.RegisterHooks(vm.NewPreprocessorHooks(p))
+}
+
+// ChainConfigExtra is the [params.ChainConfig] extra payload, carried in the
+// "extra" JSON field.
+type ChainConfigExtra struct {
+	HelloTime *uint64 `json:"helloTime,omitempty"`
+
+	params.NOOPHooks
+}
+
+// IsHello reports whether the Hello fork is active at the timestamp.
+func (c ChainConfigExtra) IsHello(timestamp uint64) bool {
+	return c.HelloTime != nil && *c.HelloTime <= timestamp
+}
+
+// FromChainConfig returns the extra payload carried by the ChainConfig.
+func FromChainConfig(c *params.ChainConfig) ChainConfigExtra {
+	return paramsExtras.ChainConfig.Get(c)
+}
+
+// RulesExtra is the [params.Rules] extra payload.
+type RulesExtra struct {
+	IsHello bool
+
+	params.NOOPHooks
+}
+
+func newRulesExtra(_ *params.ChainConfig, _ *params.Rules, c ChainConfigExtra, _ *big.Int, _ bool, timestamp uint64) RulesExtra {
+	return RulesExtra{
+		IsHello: c.IsHello(timestamp),
+	}
+}
+
+// FromRules returns the extra payload carried by the Rules.
+func FromRules(r *params.Rules) RulesExtra {
+	return paramsExtras.Rules.Get(r)
+}
+
+// PrecompileOverride enables the greeter precompile at [GreeterAddress] i.f.f.
+// the Hello fork is active.
+func (r RulesExtra) PrecompileOverride(addr common.Address) (libevm.PrecompiledContract, bool) {
+	if !r.IsHello || addr != GreeterAddress {
+		return nil, false
+	}
+	return greeter, true
+}
+
+// CustomPrecompiles returns [GreeterAddress] i.f.f. the Hello fork is active,
+// implementing the [params.CustomPrecompiler] interface.
+func (r RulesExtra) CustomPrecompiles() []common.Address {
+	if r.IsHello {
+		return []common.Address{GreeterAddress}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package hellochain_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/examples/hellochain"
+	"github.com/ava-labs/libevm/libevm/precompiles/parallel"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/rpc"
+	"github.com/ava-labs/libevm/trie"
+	"github.com/ava-labs/libevm/triedb"
+	"github.com/ava-labs/libevm/triedb/database"
+	"github.com/ava-labs/libevm/triedb/hashdb"
+)
+
+func register(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		params.TestOnlyClearRegisteredExtras()
+		types.TestOnlyClearRegisteredExtras()
+		vm.TestOnlyClearRegisteredHooks()
+	}
+	reset()
+	t.Cleanup(reset)
+
+	p := parallel.New(2, 2)
+	t.Cleanup(p.Close)
+	hellochain.Register(p)
+}
+
+// overrideBackend demonstrates the use of [hellochain.WithTrieDB], which is
+// where an alternative backend such as Firewood would be plugged in.
+type overrideBackend struct {
+	*hashdb.Database
+}
+
+func (b overrideBackend) Reader(root common.Hash) (database.Reader, error) {
+	return b.Database.Reader(root)
+}
+
+func TestHelloChain(t *testing.T) {
+	const forkTime = 2
+
+	tests := []struct {
+		name string
+		opts []hellochain.Option
+	}{
+		{
+			name: "default_triedb",
+		},
+		{
+			name: "triedb_override",
+			opts: []hellochain.Option{
+				hellochain.WithTrieDB(func(db ethdb.Database) triedb.DBOverride {
+					return overrideBackend{hashdb.New(db, nil, trie.MerkleResolver{})}
+				}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			register(t)
+			testHelloChain(t, forkTime, tt.opts...)
+		})
+	}
+}
+
+func testHelloChain(t *testing.T, forkTime uint64, opts ...hellochain.Option) {
+	config := new(params.ChainConfig)
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"chainId": 4242,
+		"homesteadBlock": 0,
+		"eip150Block": 0,
+		"eip155Block": 0,
+		"eip158Block": 0,
+		"byzantiumBlock": 0,
+		"constantinopleBlock": 0,
+		"petersburgBlock": 0,
+		"istanbulBlock": 0,
+		"berlinBlock": 0,
+		"extra": {"helloTime": 2}
+	}`), config), "json.Unmarshal(..., %T)", config)
+	require.Equal(t, forkTime, *hellochain.FromChainConfig(config).HelloTime, "HelloTime")
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	eoa := crypto.PubkeyToAddress(key.PublicKey)
+
+	chain, err := hellochain.NewChain(rawdb.NewMemoryDatabase(), &core.Genesis{
+		Config:     config,
+		GasLimit:   30e6,
+		Difficulty: big.NewInt(1),
+		Alloc: types.GenesisAlloc{
+			eoa: {Balance: big.NewInt(params.Ether)},
+		},
+	}, opts...)
+	require.NoError(t, err, "NewChain()")
+	t.Cleanup(func() {
+		require.NoError(t, chain.Close(), "Close()")
+	})
+
+	var nonce uint64
+	tx := func(to common.Address, data string) *types.Transaction {
+		t.Helper()
+		tx := types.MustSignNewTx(key, types.LatestSigner(config), &types.LegacyTx{
+			Nonce: nonce,
+			To:    &to,
+			Gas:   100_000,
+			Data:  []byte(data),
+		})
+		nonce++
+		return tx
+	}
+	greet := func(name string) *types.Transaction {
+		return tx(hellochain.GreeterAddress, name)
+	}
+	other := common.Address{'o', 't', 'h', 'e', 'r'}
+
+	// Before the fork, the greeter is a regular account.
+	_, _, err = chain.BuildBlock("gm", nil)
+	require.ErrorIs(t, err, hellochain.ErrMOTDBeforeFork, "BuildBlock() with MOTD before fork")
+	_, _, err = chain.BuildBlock("", [][]byte{[]byte("pre-fork")}, greet("Nobody"))
+	require.NoError(t, err, "BuildBlock() before fork")
+
+	blocks := []struct {
+		motd          string
+		txs           types.Transactions
+		wantMOTD      string
+		wantGreetings []string
+	}{
+		{
+			motd:          "Gm",
+			txs:           types.Transactions{greet("Alice"), tx(other, "Alice"), greet("Bob")},
+			wantMOTD:      "Gm",
+			wantGreetings: []string{"Gm, Alice!", "", "Gm, Bob!"},
+		},
+		{
+			txs:           types.Transactions{greet("Carol")},
+			wantMOTD:      hellochain.DefaultMOTD,
+			wantGreetings: []string{"Hello, Carol!"},
+		},
+	}
+	for _, blk := range blocks {
+		postcards := [][]byte{[]byte("wish you were here"), []byte(blk.wantMOTD)}
+		b, receipts, err := chain.BuildBlock(blk.motd, postcards, blk.txs...)
+		require.NoError(t, err, "BuildBlock()")
+		require.GreaterOrEqual(t, b.Time(), forkTime, "block time")
+
+		var gotGreetings []string
+		for _, r := range receipts {
+			require.Equalf(t, types.ReceiptStatusSuccessful, r.Status, "%T.Status", r)
+			var g string
+			for _, l := range r.Logs {
+				if l.Address == hellochain.GreeterAddress && l.Topics[0] == hellochain.GreetingTopic {
+					g = string(l.Data)
+				}
+			}
+			gotGreetings = append(gotGreetings, g)
+		}
+		assert.Equal(t, blk.wantGreetings, gotGreetings, "greetings logged")
+
+		got := chain.BlockByNumber(b.NumberU64())
+		require.NotNil(t, got, "BlockByNumber(%d)", b.NumberU64())
+		assert.Equal(t, b.Hash(), got.Hash(), "block hash after database round trip")
+		assert.Equal(t, blk.wantMOTD, hellochain.MOTD(got.Header()), "MOTD after database round trip")
+		assert.Equal(t, postcards, hellochain.Postcards(got), "postcards after database round trip")
+	}
+
+	gotGreetings, err := chain.Greetings(eoa)
+	require.NoError(t, err, "Greetings()")
+	assert.Equal(t, uint64(3), gotGreetings, "Greetings()")
+	gotTotal, err := chain.TotalGreetings()
+	require.NoError(t, err, "TotalGreetings()")
+	assert.Equal(t, uint64(3), gotTotal, "TotalGreetings()")
+
+	t.Run("rpc", func(t *testing.T) {
+		server, err := hellochain.NewRPCServer(chain)
+		require.NoError(t, err, "NewRPCServer()")
+		t.Cleanup(server.Stop)
+		client := rpc.DialInProc(server)
+		t.Cleanup(client.Close)
+
+		var greetings hexutil.Uint64
+		require.NoError(t, client.Call(&greetings, "hello_greetings", eoa))
+		assert.Equal(t, hexutil.Uint64(3), greetings, "hello_greetings")
+
+		for num, want := range map[uint64]hellochain.Messages{
+			1: {MOTD: "", Postcards: []hexutil.Bytes{[]byte("pre-fork")}},
+			2: {MOTD: "Gm", Postcards: []hexutil.Bytes{[]byte("wish you were here"), []byte("Gm")}},
+		} {
+			var got hellochain.Messages
+			require.NoError(t, client.Call(&got, "hello_messages", hexutil.Uint64(num)))
+			assert.Equalf(t, want, got, "hello_messages(%d)", num)
+		}
+
+		var got hellochain.Messages
+		require.Error(t, client.Call(&got, "hello_messages", hexutil.Uint64(100)), "hello_messages(<non-existent block>)")
+	})
+}
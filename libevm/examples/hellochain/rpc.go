@@ -0,0 +1,70 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package hellochain
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/rpc"
+)
+
+// NewRPCServer returns a server exposing the [API] methods under the "hello"
+// namespace; e.g. `hello_greetings`.
+func NewRPCServer(c *Chain) (*rpc.Server, error) {
+	s := rpc.NewServer()
+	if err := s.RegisterName("hello", &API{chain: c}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// API provides RPC access to hellochain-specific data.
+type API struct {
+	chain *Chain
+}
+
+// Greetings returns the number of successful greetings sent by the account, as
+// of the head block.
+func (a *API) Greetings(addr common.Address) (hexutil.Uint64, error) {
+	n, err := a.chain.Greetings(addr)
+	return hexutil.Uint64(n), err
+}
+
+// Messages are the hellochain extras carried by a block.
+type Messages struct {
+	MOTD      string          `json:"motd"`
+	Postcards []hexutil.Bytes `json:"postcards"`
+}
+
+// Messages returns the MOTD and postcards carried by the canonical block with
+// the specified number.
+func (a *API) Messages(number hexutil.Uint64) (*Messages, error) {
+	b := a.chain.BlockByNumber(uint64(number))
+	if b == nil {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+	m := &Messages{
+		MOTD:      MOTD(b.Header()),
+		Postcards: make([]hexutil.Bytes, len(Postcards(b))),
+	}
+	for i, p := range Postcards(b) {
+		m.Postcards[i] = p
+	}
+	return m, nil
+}
@@ -0,0 +1,576 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package hexjson marshals and unmarshals JSON following the hex-encoding
+// conventions of geth's RPC APIs, as otherwise implemented by the wrapper
+// types of the [hexutil] package. It is intended for RPC representations of
+// extra payloads, which would otherwise require a hand-written wrapper for
+// every numeric or byte field.
+//
+// The following types are encoded as by their respective [hexutil] type:
+//
+//   - unsigned integers of all sizes ([hexutil.Uint64]);
+//   - [big.Int] ([hexutil.Big]);
+//   - [uint256.Int] ([hexutil.U256]); and
+//   - byte slices and arrays ([hexutil.Bytes]).
+//
+// All other values, including those of types that implement [json.Marshaler]
+// or [encoding.TextMarshaler], are encoded as by the [json] package. Structs,
+// slices, arrays, maps, and pointers are traversed recursively; struct fields
+// honour the `json` tag's name, "-", and "omitempty" options, and the fields
+// of embedded structs are promoted.
+package hexjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common/hexutil"
+)
+
+// Marshal is equivalent to [json.Marshal] except for the hex encoding
+// described in the package documentation.
+func Marshal(v any) ([]byte, error) {
+	x, err := Value(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(x)
+}
+
+// Value returns a representation of `v` that, when passed to [json.Marshal],
+// is encoded identically to the output of [Marshal]. It is intended for use
+// in hooks that populate maps for RPC marshalling, such as
+// `types.HeaderHooks.PostRPCMarshal`.
+func Value(v any) (any, error) {
+	e := &encoder{visiting: make(map[visit]struct{})}
+	return e.valueOf(reflect.ValueOf(v))
+}
+
+// ErrCycle is returned, wrapped, by [Marshal] and [Value] if a value contains
+// a reference to itself.
+var ErrCycle = errors.New("hexjson: cycle detected")
+
+// An encoder tracks the pointers, maps, and slices currently being traversed,
+// to detect cycles. Values are removed once traversed so that those referenced
+// more than once without forming a cycle are encoded in full every time.
+type encoder struct {
+	visiting map[visit]struct{}
+}
+
+type visit struct {
+	ptr unsafe.Pointer
+	typ reflect.Type
+	len int // distinguishes slices sharing a backing array
+}
+
+// enter records `v`, which MUST be a non-nil pointer, map, or slice, as being
+// traversed. The returned function MUST be called once traversal is complete.
+func (e *encoder) enter(v reflect.Value) (exit func(), _ error) {
+	k := visit{ptr: v.UnsafePointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		k.len = v.Len()
+	}
+	if _, ok := e.visiting[k]; ok {
+		return nil, fmt.Errorf("%w via %v", ErrCycle, v.Type())
+	}
+	e.visiting[k] = struct{}{}
+	return func() { delete(e.visiting, k) }, nil
+}
+
+var (
+	bigType             = reflect.TypeFor[big.Int]()
+	u256Type            = reflect.TypeFor[uint256.Int]()
+	marshalerType       = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	unmarshalerType     = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+func (e *encoder) valueOf(v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch k := v.Kind(); {
+	case (k == reflect.Pointer || k == reflect.Interface) && v.IsNil():
+		return nil, nil
+	case v.Type() == bigType:
+		b := new(big.Int).Set(addressable(v).Addr().Interface().(*big.Int)) //nolint:forcetypeassert // Known type
+		return (*hexutil.Big)(b), nil
+	case v.Type() == u256Type:
+		u := *addressable(v).Addr().Interface().(*uint256.Int) //nolint:forcetypeassert // Known type
+		return (*hexutil.U256)(&u), nil
+	case k == reflect.Pointer && (v.Type().Elem() == bigType || v.Type().Elem() == u256Type):
+		return e.valueOf(v.Elem())
+	case v.Type().Implements(marshalerType) || v.Type().Implements(textMarshalerType):
+		return v.Interface(), nil
+	case k != reflect.Pointer && (reflect.PointerTo(v.Type()).Implements(marshalerType) || reflect.PointerTo(v.Type()).Implements(textMarshalerType)):
+		return addressable(v).Addr().Interface(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		exit, err := e.enter(v)
+		if err != nil {
+			return nil, err
+		}
+		defer exit()
+		return e.valueOf(v.Elem())
+
+	case reflect.Interface:
+		return e.valueOf(v.Elem())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return hexutil.Uint64(v.Uint()), nil
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return hexutil.Bytes(bytes.Clone(v.Bytes())), nil
+		}
+		exit, err := e.enter(v)
+		if err != nil {
+			return nil, err
+		}
+		defer exit()
+		return e.sequence(v)
+
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make(hexutil.Bytes, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return b, nil
+		}
+		return e.sequence(v)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		exit, err := e.enter(v)
+		if err != nil {
+			return nil, err
+		}
+		defer exit()
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := mapKey(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			if out[key], err = e.valueOf(iter.Value()); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+
+	case reflect.Struct:
+		var out object
+		for _, f := range fields(v.Type()) {
+			fv, ok := fieldByIndex(v, f.index, false)
+			if !ok || (f.omitEmpty && isEmpty(fv)) {
+				continue
+			}
+			x, err := e.valueOf(fv)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.name, err)
+			}
+			out = append(out, member{f.name, x})
+		}
+		return out, nil
+
+	case reflect.Func, reflect.Chan, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return nil, fmt.Errorf("unsupported type %v", v.Type())
+
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// addressable returns `v` if it is addressable, otherwise an addressable copy.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	cp := reflect.New(v.Type()).Elem()
+	cp.Set(v)
+	return cp
+}
+
+func (e *encoder) sequence(v reflect.Value) ([]any, error) {
+	out := make([]any, v.Len())
+	for i := range out {
+		var err error
+		if out[i], err = e.valueOf(v.Index(i)); err != nil {
+			return nil, fmt.Errorf("index %d: %w", i, err)
+		}
+	}
+	return out, nil
+}
+
+// mapKey encodes a map key as done by the [json] package.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %v", k.Type())
+}
+
+// An object is a JSON object with members encoded in order, unlike a map.
+type object []member
+
+type member struct {
+	name string
+	val  any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(m.name)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(m.val)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// fields returns the JSON fields of the struct type, including those promoted
+// from embedded structs. As with the [json] package, a field at a shallower
+// depth shadows those with the same name at greater depths, and multiple
+// fields with the same name at the same depth are all ignored.
+func fields(t reflect.Type) []field {
+	type candidate struct {
+		field
+		depth int
+	}
+	var all []candidate
+
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := range t.NumField() {
+			sf := t.Field(i)
+			tag := sf.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			idx := append(index[:len(index):len(index)], i)
+
+			if sf.Anonymous && name == "" {
+				ft := sf.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					walk(ft, idx)
+					continue
+				}
+			}
+			if !sf.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			all = append(all, candidate{
+				field: field{
+					name:      name,
+					index:     idx,
+					omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+				},
+				depth: len(idx),
+			})
+		}
+	}
+	walk(t, nil)
+
+	shallowest := make(map[string]int)
+	count := make(map[[2]any]int)
+	for _, c := range all {
+		if d, ok := shallowest[c.name]; !ok || c.depth < d {
+			shallowest[c.name] = c.depth
+		}
+		count[[2]any{c.name, c.depth}]++
+	}
+	var out []field
+	for _, c := range all {
+		if c.depth == shallowest[c.name] && count[[2]any{c.name, c.depth}] == 1 {
+			out = append(out, c.field)
+		}
+	}
+	return out
+}
+
+// fieldByIndex is equivalent to [reflect.Value.FieldByIndex] except that nil
+// embedded pointers are either allocated or, if `alloc` is false, result in
+// false being returned.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero() && v.Kind() != reflect.Struct
+	}
+}
+
+// Unmarshal is equivalent to [json.Unmarshal] except for the hex decoding
+// that is the inverse of [Marshal]. `v` MUST be a non-nil pointer.
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("hexjson: Unmarshal(non-nil pointer required, got %T)", v)
+	}
+	return decode(data, rv.Elem())
+}
+
+func decode(data []byte, v reflect.Value) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		switch v.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+			v.SetZero()
+		}
+		return nil
+	}
+
+	switch {
+	case v.Type() == bigType:
+		return json.Unmarshal(data, (*hexutil.Big)(v.Addr().Interface().(*big.Int))) //nolint:forcetypeassert // Known type
+	case v.Type() == u256Type:
+		return json.Unmarshal(data, (*hexutil.U256)(v.Addr().Interface().(*uint256.Int))) //nolint:forcetypeassert // Known type
+	case v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface &&
+		(reflect.PointerTo(v.Type()).Implements(unmarshalerType) || reflect.PointerTo(v.Type()).Implements(textUnmarshalerType)):
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decode(data, v.Elem())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var x hexutil.Uint64
+		if err := json.Unmarshal(data, &x); err != nil {
+			return err
+		}
+		if v.OverflowUint(uint64(x)) {
+			return fmt.Errorf("hex value %#x overflows %v", uint64(x), v.Type())
+		}
+		v.SetUint(uint64(x))
+		return nil
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			var b hexutil.Bytes
+			if err := json.Unmarshal(data, &b); err != nil {
+				return err
+			}
+			s := reflect.MakeSlice(v.Type(), len(b), len(b))
+			reflect.Copy(s, reflect.ValueOf([]byte(b)))
+			v.Set(s)
+			return nil
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return err
+		}
+		s := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, e := range elems {
+			if err := decode(e, s.Index(i)); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		v.Set(s)
+		return nil
+
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			var b hexutil.Bytes
+			if err := json.Unmarshal(data, &b); err != nil {
+				return err
+			}
+			if len(b) != v.Len() {
+				return fmt.Errorf("hex string has length %d, want %d for %v", len(b), v.Len(), v.Type())
+			}
+			reflect.Copy(v, reflect.ValueOf([]byte(b)))
+			return nil
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return err
+		}
+		if len(elems) > v.Len() {
+			return fmt.Errorf("%d elements overflow %v", len(elems), v.Type())
+		}
+		for i, e := range elems {
+			if err := decode(e, v.Index(i)); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(data, &members); err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(members)))
+		}
+		for name, raw := range members {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := parseMapKey(name, key); err != nil {
+				return err
+			}
+			val := reflect.New(v.Type().Elem()).Elem()
+			if err := decode(raw, val); err != nil {
+				return fmt.Errorf("key %q: %w", name, err)
+			}
+			v.SetMapIndex(key, val)
+		}
+		return nil
+
+	case reflect.Struct:
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(data, &members); err != nil {
+			return err
+		}
+		fs := fields(v.Type())
+		for name, raw := range members {
+			f, ok := fieldNamed(fs, name)
+			if !ok {
+				continue
+			}
+			fv, ok := fieldByIndex(v, f.index, true)
+			if !ok {
+				return fmt.Errorf("field %q: cannot set embedded pointer to unexported struct", f.name)
+			}
+			if err := decode(raw, fv); err != nil {
+				return fmt.Errorf("field %q: %w", f.name, err)
+			}
+		}
+		return nil
+
+	default:
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+}
+
+// fieldNamed returns the field with the exact name or, failing that, the first
+// with a case-insensitive match, as done by the [json] package.
+func fieldNamed(fs []field, name string) (field, bool) {
+	for _, f := range fs {
+		if f.name == name {
+			return f, true
+		}
+	}
+	for _, f := range fs {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// parseMapKey is the inverse of [mapKey].
+func parseMapKey(s string, k reflect.Value) error {
+	if k.Kind() == reflect.String {
+		k.SetString(s)
+		return nil
+	}
+	if tu, ok := k.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(s))
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, k.Type().Bits())
+		if err != nil {
+			return err
+		}
+		k.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, k.Type().Bits())
+		if err != nil {
+			return err
+		}
+		k.SetUint(n)
+		return nil
+	}
+	return fmt.Errorf("unsupported map key type %v", k.Type())
+}
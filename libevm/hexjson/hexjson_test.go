@@ -0,0 +1,193 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package hexjson
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+type Embedded struct {
+	Promoted uint16
+	Shadowed uint8
+}
+
+type noExportedFields struct{}
+
+type payload struct {
+	noExportedFields
+	*Embedded
+	Shadowed string
+
+	Uint   uint64
+	Small  uint8 `json:"small"`
+	Signed int64
+	Big    *big.Int
+	BigVal big.Int
+	U256   *uint256.Int
+	Bytes  []byte
+	Array  [3]byte
+	Hash   common.Hash
+	Addrs  []common.Address
+	Nested []map[string]*uint32
+	ByNum  map[uint64]bool
+
+	Omitted *big.Int `json:",omitempty"`
+	Skipped uint64   `json:"-"`
+	hidden  uint64
+}
+
+func TestRoundTrip(t *testing.T) {
+	seven := uint32(7)
+	in := payload{
+		Embedded: &Embedded{Promoted: 0xabc, Shadowed: 1},
+		Shadowed: "outer",
+		Uint:     math.MaxUint64,
+		Small:    255,
+		Signed:   -42,
+		Big:      big.NewInt(1e18),
+		BigVal:   *big.NewInt(16),
+		U256:     uint256.NewInt(256),
+		Bytes:    []byte{0xde, 0xad},
+		Array:    [3]byte{1, 2, 3},
+		Hash:     common.Hash{31: 1},
+		Addrs:    []common.Address{{19: 2}},
+		Nested:   []map[string]*uint32{{"seven": &seven, "nil": nil}},
+		ByNum:    map[uint64]bool{10: true},
+		Skipped:  1,
+		hidden:   1,
+	}
+
+	got, err := Marshal(in)
+	require.NoError(t, err, "Marshal()")
+	const want = `{` +
+		`"Promoted":"0xabc",` +
+		`"Shadowed":"outer",` +
+		`"Uint":"0xffffffffffffffff",` +
+		`"small":"0xff",` +
+		`"Signed":-42,` +
+		`"Big":"0xde0b6b3a7640000",` +
+		`"BigVal":"0x10",` +
+		`"U256":"0x100",` +
+		`"Bytes":"0xdead",` +
+		`"Array":"0x010203",` +
+		`"Hash":"0x0000000000000000000000000000000000000000000000000000000000000001",` +
+		`"Addrs":["0x0000000000000000000000000000000000000002"],` +
+		`"Nested":[{"nil":null,"seven":"0x7"}],` +
+		`"ByNum":{"10":true}` +
+		`}`
+	assert.JSONEq(t, want, string(got), "Marshal()")
+	assert.Equal(t, want, string(got), "Marshal() preserves field order")
+
+	var rt payload
+	require.NoError(t, Unmarshal(got, &rt), "Unmarshal(Marshal())")
+	// Not round-tripped by design.
+	in.Embedded.Shadowed = 0
+	in.Skipped = 0
+	in.hidden = 0
+	assert.Equal(t, in, rt, "Unmarshal(Marshal())")
+
+	t.Run("Value", func(t *testing.T) {
+		v, err := Value(in)
+		require.NoError(t, err, "Value()")
+		m := map[string]any{"payload": v}
+		got, err := json.Marshal(m)
+		require.NoError(t, err, "json.Marshal(map containing Value())")
+		assert.JSONEq(t, `{"payload":`+want+`}`, string(got))
+	})
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name, json string
+		into       any
+	}{
+		{
+			name: "uint_overflow",
+			json: `"0x100"`,
+			into: new(uint8),
+		},
+		{
+			name: "decimal_uint",
+			json: `1`,
+			into: new(uint64),
+		},
+		{
+			name: "byte_array_length",
+			json: `"0x0102"`,
+			into: new([3]byte),
+		},
+		{
+			name: "nested_field",
+			json: `{"Uint":"nothex"}`,
+			into: new(payload),
+		},
+		{
+			name: "non_pointer",
+			json: `{}`,
+			into: payload{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, Unmarshal([]byte(tt.json), tt.into))
+		})
+	}
+}
+
+type node struct {
+	Val  uint64
+	Next *node
+}
+
+func TestMarshalCycles(t *testing.T) {
+	loop := &node{Val: 1}
+	loop.Next = &node{Val: 2, Next: loop}
+
+	selfMap := map[string]any{}
+	selfMap["self"] = selfMap
+
+	selfSlice := make([]any, 1)
+	selfSlice[0] = selfSlice
+
+	for name, v := range map[string]any{
+		"pointer": loop,
+		"map":     selfMap,
+		"slice":   selfSlice,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Marshal(v)
+			require.ErrorIs(t, err, ErrCycle, "Marshal()")
+		})
+	}
+
+	t.Run("shared_without_cycle", func(t *testing.T) {
+		shared := &node{Val: 3}
+		got, err := Marshal([]*node{shared, shared})
+		require.NoError(t, err, "Marshal()")
+		assert.JSONEq(t, `[{"Val":"0x3","Next":null},{"Val":"0x3","Next":null}]`, string(got))
+	})
+}
@@ -20,9 +20,12 @@ package hookstest
 
 import (
 	"encoding/json"
+	"math"
 	"math/big"
 	"testing"
 
+	"github.com/holiman/uint256"
+
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/libevm"
 	"github.com/ava-labs/libevm/params"
@@ -44,13 +47,23 @@ func Register[C params.ChainConfigHooks, R params.RulesHooks](tb testing.TB, ext
 type Stub struct {
 	CheckConfigForkOrderFn  func() error
 	CheckConfigCompatibleFn func(*params.ChainConfig, *big.Int, uint64) *params.ConfigCompatError
+	ValidateFn              func(*params.ChainConfig) error
 	DescriptionSuffix       string
 	PrecompileOverrides     map[common.Address]libevm.PrecompiledContract
 	ActivePrecompilesFn     func([]common.Address) []common.Address
 	AccessListGasFn         func(libevm.AccessList) (uint64, bool, error)
+	DataGasFn               func(data []byte, defaultGas uint64) uint64
 	CanExecuteTransactionFn func(common.Address, *common.Address, libevm.StateReader) error
 	CanCreateContractFn     func(*libevm.AddressContext, uint64, libevm.StateReader) (uint64, error)
 	MinimumGasConsumptionFn func(txGasLimit uint64) uint64
+	GasPayerFn              func(common.Address, *common.Address, libevm.StateReader) (common.Address, bool)
+	MaxInitCodeSizeFn       func() int
+	MaxTxDataBytesFn        func() int
+	PrecompileGasFn         func(common.Address, []byte, uint64) uint64
+	FeeRecipientsFn         func(_ libevm.BlockHeader, baseFee, tip *uint256.Int) (*common.Address, common.Address)
+	VerifyPredicatesFn      func(libevm.BlockHeader, common.Address, *common.Address, libevm.AccessList) error
+	CustomPrecompilesFn     func() []common.Address
+	SystemAddressesFn       func() params.SystemAddresses
 	DisableGasRefunds       bool
 }
 
@@ -102,6 +115,15 @@ func (s Stub) AccessListGas(accessList libevm.AccessList) (uint64, bool, error)
 	return 0, false, nil
 }
 
+// DataGas proxies arguments to the s.DataGasFn function if non-nil, otherwise
+// it returns `defaultGas` unchanged.
+func (s Stub) DataGas(data []byte, defaultGas uint64) uint64 {
+	if f := s.DataGasFn; f != nil {
+		return f(data, defaultGas)
+	}
+	return defaultGas
+}
+
 // CheckConfigForkOrder proxies arguments to the s.CheckConfigForkOrderFn
 // function if non-nil, otherwise it acts as a noop.
 func (s Stub) CheckConfigForkOrder() error {
@@ -120,6 +142,15 @@ func (s Stub) CheckConfigCompatible(newcfg *params.ChainConfig, headNumber *big.
 	return nil
 }
 
+// Validate proxies arguments to the s.ValidateFn function if non-nil,
+// otherwise it acts as a noop.
+func (s Stub) Validate(c *params.ChainConfig) error {
+	if f := s.ValidateFn; f != nil {
+		return f(c)
+	}
+	return nil
+}
+
 // Description returns s.DescriptionSuffix.
 func (s Stub) Description() string {
 	return s.DescriptionSuffix
@@ -163,9 +194,85 @@ func (s Stub) MinimumGasConsumption(limit uint64) uint64 {
 	return 0
 }
 
+// GasPayer proxies arguments to the s.GasPayerFn function if non-nil,
+// otherwise it acts as a noop.
+func (s Stub) GasPayer(from common.Address, to *common.Address, sr libevm.StateReader) (common.Address, bool) {
+	if f := s.GasPayerFn; f != nil {
+		return f(from, to, sr)
+	}
+	return common.Address{}, false
+}
+
+// MaxInitCodeSize proxies to s.MaxInitCodeSizeFn if non-nil, otherwise it
+// returns [params.MaxInitCodeSize].
+func (s Stub) MaxInitCodeSize() int {
+	if f := s.MaxInitCodeSizeFn; f != nil {
+		return f()
+	}
+	return params.MaxInitCodeSize
+}
+
+// MaxTxDataBytes proxies to s.MaxTxDataBytesFn if non-nil, otherwise it
+// returns [math.MaxInt].
+func (s Stub) MaxTxDataBytes() int {
+	if f := s.MaxTxDataBytesFn; f != nil {
+		return f()
+	}
+	return math.MaxInt
+}
+
+// PrecompileGas proxies arguments to the s.PrecompileGasFn function if non-nil,
+// otherwise it returns `defaultGas` unchanged.
+func (s Stub) PrecompileGas(addr common.Address, input []byte, defaultGas uint64) uint64 {
+	if f := s.PrecompileGasFn; f != nil {
+		return f(addr, input, defaultGas)
+	}
+	return defaultGas
+}
+
 var _ interface {
 	params.ChainConfigHooks
 	params.RulesHooks
 	json.Marshaler
 	json.Unmarshaler
 } = Stub{}
+
+// FeeRecipients proxies arguments to the s.FeeRecipientsFn function if
+// non-nil, otherwise it returns the default recipients, burning the base fee.
+func (s Stub) FeeRecipients(header libevm.BlockHeader, baseFee, tip *uint256.Int) (*common.Address, common.Address) {
+	if f := s.FeeRecipientsFn; f != nil {
+		return f(header, baseFee, tip)
+	}
+	return nil, header.BlockCoinbase()
+}
+
+// VerifyPredicates proxies arguments to the s.VerifyPredicatesFn function if
+// non-nil, otherwise it acts as a noop.
+func (s Stub) VerifyPredicates(header libevm.BlockHeader, from common.Address, to *common.Address, al libevm.AccessList) error {
+	if f := s.VerifyPredicatesFn; f != nil {
+		return f(header, from, to, al)
+	}
+	return nil
+}
+
+var _ params.CustomPrecompiler = Stub{}
+
+// CustomPrecompiles proxies to the s.CustomPrecompilesFn function if non-nil,
+// otherwise it returns nil.
+func (s Stub) CustomPrecompiles() []common.Address {
+	if f := s.CustomPrecompilesFn; f != nil {
+		return f()
+	}
+	return nil
+}
+
+var _ params.SystemAddresser = Stub{}
+
+// SystemAddresses proxies to the s.SystemAddressesFn function if non-nil,
+// otherwise it returns nil.
+func (s Stub) SystemAddresses() params.SystemAddresses {
+	if f := s.SystemAddressesFn; f != nil {
+		return f()
+	}
+	return nil
+}
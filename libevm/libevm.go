@@ -17,6 +17,8 @@
 package libevm
 
 import (
+	"math/big"
+
 	"github.com/holiman/uint256"
 
 	"github.com/ava-labs/libevm/common"
@@ -42,6 +44,17 @@ type AccessTuple struct {
 	StorageKeys []common.Hash
 }
 
+// BlockHeader provides read-only access to the header of the block in whose
+// context a hook is called, for packages that cannot import core/types without
+// causing a circular dependency. It is implemented by *core/types.Header, to
+// which it MAY be type-asserted for access to all fields.
+type BlockHeader interface {
+	BlockNumber() *big.Int
+	BlockTime() uint64
+	BlockCoinbase() common.Address
+	BlockBaseFee() *big.Int // nil before London
+}
+
 // StateReader is a subset of vm.StateDB, exposing only methods that read from
 // but do not modify state. See method comments in vm.StateDB, which aren't
 // copied here as they risk becoming outdated.
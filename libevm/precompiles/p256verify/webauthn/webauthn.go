@@ -0,0 +1,259 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package webauthn provides helpers for converting WebAuthn (passkey)
+// assertions into input for the P256VERIFY precompile, as defined by
+// [RIP-7212].
+//
+// A WebAuthn authenticator signs SHA-256(authenticatorData ||
+// SHA-256(clientDataJSON)) and returns an ASN.1 DER-encoded signature, whereas
+// the precompile expects a 160-byte input of the message hash, the signature's
+// r and s values, and the public key's x and y coordinates, each as a 32-byte,
+// big-endian word.
+//
+// [RIP-7212]: https://github.com/ethereum/RIPs/blob/master/RIPS/rip-7212.md
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+
+	"github.com/ava-labs/libevm/libevm/options"
+)
+
+// PrecompileInputLength is the length of valid input to the P256VERIFY
+// precompile.
+const PrecompileInputLength = 160
+
+// MinAuthenticatorDataLength is the length of authenticator data without
+// attested credentials or extensions: a 32-byte RP ID hash, 1 byte of flags,
+// and a 4-byte signature counter.
+const MinAuthenticatorDataLength = 37
+
+// Authenticator-data flags.
+const (
+	FlagUserPresent  byte = 1 << 0
+	FlagUserVerified byte = 1 << 2
+)
+
+// ClientDataTypeGet is the type of [ClientData] for assertions.
+const ClientDataTypeGet = "webauthn.get"
+
+var (
+	// ErrAuthenticatorData is returned when authenticator data is malformed.
+	ErrAuthenticatorData = errors.New("invalid authenticator data")
+	// ErrClientData is returned when client data is malformed or fails
+	// validation.
+	ErrClientData = errors.New("invalid client data")
+	// ErrSignature is returned when a DER signature is malformed or its values
+	// are out of range.
+	ErrSignature = errors.New("invalid signature")
+	// ErrPublicKey is returned when a public key is not on the P-256 curve.
+	ErrPublicKey = errors.New("invalid public key")
+)
+
+// An Assertion carries the fields of a WebAuthn authenticator assertion
+// response that are required for signature verification.
+type Assertion struct {
+	AuthenticatorData []byte
+	ClientDataJSON    []byte
+	Signature         []byte // ASN.1 DER
+}
+
+// ClientData is the subset of the fields of the CollectedClientData
+// dictionary, serialised as clientDataJSON, that are relevant to assertion
+// verification.
+type ClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"` // base64url, without padding
+	Origin    string `json:"origin"`
+}
+
+// ParseClientData parses `clientDataJSON` and returns an error if it isn't of
+// type [ClientDataTypeGet] or if its challenge doesn't match `challenge`.
+func ParseClientData(clientDataJSON, challenge []byte) (*ClientData, error) {
+	cd := new(ClientData)
+	if err := json.Unmarshal(clientDataJSON, cd); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrClientData, err)
+	}
+	if cd.Type != ClientDataTypeGet {
+		return nil, fmt.Errorf("%w: type %q; want %q", ErrClientData, cd.Type, ClientDataTypeGet)
+	}
+	got, err := base64.RawURLEncoding.DecodeString(cd.Challenge)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decoding challenge: %v", ErrClientData, err)
+	}
+	if !bytes.Equal(got, challenge) {
+		return nil, fmt.Errorf("%w: challenge %#x; want %#x", ErrClientData, got, challenge)
+	}
+	return cd, nil
+}
+
+// CheckAuthenticatorData returns an error if `authData` is too short or if
+// any of the `required` flag bits are unset.
+func CheckAuthenticatorData(authData []byte, required byte) error {
+	if n := len(authData); n < MinAuthenticatorDataLength {
+		return fmt.Errorf("%w: %d bytes; want >= %d", ErrAuthenticatorData, n, MinAuthenticatorDataLength)
+	}
+	if flags := authData[32]; flags&required != required {
+		return fmt.Errorf("%w: flags %#08b missing required %#08b", ErrAuthenticatorData, flags, required)
+	}
+	return nil
+}
+
+// MessageHash returns SHA-256(authData || SHA-256(clientDataJSON)), the hash
+// signed by the authenticator.
+func MessageHash(authData, clientDataJSON []byte) [32]byte {
+	cd := sha256.Sum256(clientDataJSON)
+	h := sha256.New()
+	h.Write(authData)
+	h.Write(cd[:])
+	var out [32]byte
+	h.Sum(out[:0])
+	return out
+}
+
+var (
+	curveOrder     = elliptic.P256().Params().N
+	halfCurveOrder = new(big.Int).Rsh(curveOrder, 1)
+)
+
+// ParseDERSignature parses a strict ASN.1 DER-encoded ECDSA signature, as
+// returned by WebAuthn authenticators, and returns its r and s values. Both
+// values MUST be in [1, N-1], where N is the order of the P-256 curve.
+func ParseDERSignature(der []byte) (r, s *big.Int, _ error) {
+	var (
+		in    = cryptobyte.String(der)
+		inner cryptobyte.String
+	)
+	r, s = new(big.Int), new(big.Int)
+	if !in.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!in.Empty() ||
+		!inner.ReadASN1Integer(r) ||
+		!inner.ReadASN1Integer(s) ||
+		!inner.Empty() {
+		return nil, nil, fmt.Errorf("%w: malformed DER", ErrSignature)
+	}
+	for _, v := range []*big.Int{r, s} {
+		if v.Sign() <= 0 || v.Cmp(curveOrder) >= 0 {
+			return nil, nil, fmt.Errorf("%w: value out of range", ErrSignature)
+		}
+	}
+	return r, s, nil
+}
+
+// IsLowS reports whether `s` is at most half of the order of the P-256 curve.
+func IsLowS(s *big.Int) bool {
+	return s.Cmp(halfCurveOrder) <= 0
+}
+
+// NormalizeS returns `s` if [IsLowS] is true, otherwise it returns N-s, where
+// N is the order of the P-256 curve. Both values are valid for the same
+// signature, but some verifiers only accept the lower of the two. The
+// argument is never modified.
+func NormalizeS(s *big.Int) *big.Int {
+	if IsLowS(s) {
+		return s
+	}
+	return new(big.Int).Sub(curveOrder, s)
+}
+
+// PrecompileInput returns the P256VERIFY input for the arguments, which MUST
+// each fit in 32 bytes. The public key MUST be on the P-256 curve.
+func PrecompileInput(hash [32]byte, r, s *big.Int, pub *ecdsa.PublicKey) ([]byte, error) {
+	if pub == nil || pub.X == nil || pub.Y == nil || !elliptic.P256().IsOnCurve(pub.X, pub.Y) {
+		return nil, ErrPublicKey
+	}
+	for _, v := range []*big.Int{r, s} {
+		if v == nil || v.Sign() < 0 || v.BitLen() > 256 {
+			return nil, fmt.Errorf("%w: value out of range", ErrSignature)
+		}
+	}
+
+	in := make([]byte, PrecompileInputLength)
+	copy(in, hash[:])
+	r.FillBytes(in[32:64])
+	s.FillBytes(in[64:96])
+	pub.X.FillBytes(in[96:128])
+	pub.Y.FillBytes(in[128:160])
+	return in, nil
+}
+
+type config struct {
+	challenge  []byte
+	flags      byte
+	normaliseS bool
+}
+
+// An Option configures [Assertion.PrecompileInput].
+type Option = options.Option[config]
+
+// WithChallenge requires that the client data's challenge matches `c`.
+func WithChallenge(c []byte) Option {
+	return options.Func[config](func(cfg *config) {
+		cfg.challenge = c
+	})
+}
+
+// WithRequiredFlags requires that all of the `flags` bits are set in the
+// authenticator data. The default is [FlagUserPresent].
+func WithRequiredFlags(flags byte) Option {
+	return options.Func[config](func(cfg *config) {
+		cfg.flags = flags
+	})
+}
+
+// WithNormalizedS replaces high s values with their low equivalent; see
+// [NormalizeS].
+func WithNormalizedS() Option {
+	return options.Func[config](func(cfg *config) {
+		cfg.normaliseS = true
+	})
+}
+
+// PrecompileInput validates the assertion and returns the P256VERIFY input for
+// verifying it against `pub`. The signature itself is not verified; that is
+// the responsibility of the precompile.
+func (a *Assertion) PrecompileInput(pub *ecdsa.PublicKey, opts ...Option) ([]byte, error) {
+	cfg := options.ApplyTo(&config{flags: FlagUserPresent}, opts...)
+
+	if err := CheckAuthenticatorData(a.AuthenticatorData, cfg.flags); err != nil {
+		return nil, err
+	}
+	if cfg.challenge != nil {
+		if _, err := ParseClientData(a.ClientDataJSON, cfg.challenge); err != nil {
+			return nil, err
+		}
+	}
+	r, s, err := ParseDERSignature(a.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.normaliseS {
+		s = NormalizeS(s)
+	}
+	return PrecompileInput(MessageHash(a.AuthenticatorData, a.ClientDataJSON), r, s, pub)
+}
@@ -0,0 +1,248 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/core/vm"
+)
+
+func p256Verify(t *testing.T, input []byte) bool {
+	t.Helper()
+	p := vm.PrecompiledContractsP256Verify[common.BytesToAddress([]byte{0x01, 0x00})]
+	out, err := p.Run(input)
+	require.NoError(t, err, "P256VERIFY")
+	return len(out) == 32 && out[31] == 1
+}
+
+func bigFromHex(t *testing.T, s string) *big.Int {
+	t.Helper()
+	b, ok := new(big.Int).SetString(s, 16)
+	require.Truef(t, ok, "%T.SetString(%q, 16)", b, s)
+	return b
+}
+
+const (
+	vectorAuthData   = "0xa379a6f6eeafb9a55e378c118034e2751e682fab9f2d30ab13d2125586ce19470500000007"
+	vectorClientData = `{"type":"webauthn.get","challenge":"bGliZXZtLXdlYmF1dGhu","origin":"https://example.com","crossOrigin":false}`
+	vectorChallenge  = "libevm-webauthn"
+	vectorHash       = "0x4cce81c17e331de4d7e1e0a9cf18392fcc0cfdda36354e93dbc35677a100576e"
+	vectorPubX       = "60fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6"
+	vectorPubY       = "7903fe1008b8bc99a41ae9e95628bc64f2f1b20c2d7e9f5177a3c294d4462299"
+)
+
+func vectorPubKey(t *testing.T) *ecdsa.PublicKey {
+	t.Helper()
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     bigFromHex(t, vectorPubX),
+		Y:     bigFromHex(t, vectorPubY),
+	}
+}
+
+func TestVectors(t *testing.T) {
+	authData := hexutil.MustDecode(vectorAuthData)
+	require.Equal(t, vectorHash, hexutil.Encode(func() []byte {
+		h := MessageHash(authData, []byte(vectorClientData))
+		return h[:]
+	}()), "MessageHash()")
+
+	tests := []struct {
+		name     string
+		sig      string
+		wantR    string
+		wantS    string
+		wantLowS bool
+	}{
+		{
+			name:  "high_s",
+			sig:   "0x3046022100cac82ee29c1e7dee1df46e07407e0970113d0d79b5449ef1075cb0d0a75af8a1022100fcf5d1f5fc4091462fbd4ee8df510e25867ef1622f3bedebf7a8442a1983512c",
+			wantR: "cac82ee29c1e7dee1df46e07407e0970113d0d79b5449ef1075cb0d0a75af8a1",
+			wantS: "fcf5d1f5fc4091462fbd4ee8df510e25867ef1622f3bedebf7a8442a1983512c",
+		},
+		{
+			name:     "low_s",
+			sig:      "0x3045022100fe788dff909fb5d0b466b1bb362d9b774575bc17a30fcb0fe26467125466f2bf02202a93165330ccbfa429fe877dee65e566685b9252c4d5170b4fade315689cc54a",
+			wantR:    "fe788dff909fb5d0b466b1bb362d9b774575bc17a30fcb0fe26467125466f2bf",
+			wantS:    "2a93165330ccbfa429fe877dee65e566685b9252c4d5170b4fade315689cc54a",
+			wantLowS: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Assertion{
+				AuthenticatorData: authData,
+				ClientDataJSON:    []byte(vectorClientData),
+				Signature:         hexutil.MustDecode(tt.sig),
+			}
+			r, s, err := ParseDERSignature(a.Signature)
+			require.NoError(t, err, "ParseDERSignature()")
+			assert.Equal(t, bigFromHex(t, tt.wantR), r, "r")
+			assert.Equal(t, bigFromHex(t, tt.wantS), s, "s")
+			assert.Equal(t, tt.wantLowS, IsLowS(s), "IsLowS()")
+
+			opts := []Option{
+				WithChallenge([]byte(vectorChallenge)),
+				WithRequiredFlags(FlagUserPresent | FlagUserVerified),
+			}
+			for _, normalise := range []bool{false, true} {
+				t.Run(fmt.Sprintf("normalise_s=%t", normalise), func(t *testing.T) {
+					opts := opts
+					if normalise {
+						opts = append(opts, WithNormalizedS())
+					}
+					in, err := a.PrecompileInput(vectorPubKey(t), opts...)
+					require.NoError(t, err, "PrecompileInput()")
+					require.Len(t, in, PrecompileInputLength)
+
+					assert.Equal(t, vectorHash, hexutil.Encode(in[:32]), "hash")
+					gotS := new(big.Int).SetBytes(in[64:96])
+					assert.Equal(t, normalise || tt.wantLowS, IsLowS(gotS), "IsLowS(<s in input>)")
+					assert.Truef(t, p256Verify(t, in), "P256VERIFY(%#x)", in)
+
+					in[0] ^= 1
+					assert.False(t, p256Verify(t, in), "P256VERIFY() with modified hash")
+				})
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "ecdsa.GenerateKey()")
+
+	challenge := []byte("random challenge")
+	authData := make([]byte, MinAuthenticatorDataLength)
+	authData[32] = FlagUserPresent
+	clientData := fmt.Sprintf(
+		`{"type":%q,"challenge":%q,"origin":"https://example.org"}`,
+		ClientDataTypeGet, base64.RawURLEncoding.EncodeToString(challenge),
+	)
+
+	for i := 0; i < 10; i++ {
+		hash := MessageHash(authData, []byte(clientData))
+		sig, err := ecdsa.SignASN1(rand.Reader, priv, hash[:])
+		require.NoError(t, err, "ecdsa.SignASN1()")
+
+		a := &Assertion{
+			AuthenticatorData: authData,
+			ClientDataJSON:    []byte(clientData),
+			Signature:         sig,
+		}
+		in, err := a.PrecompileInput(&priv.PublicKey, WithChallenge(challenge), WithNormalizedS())
+		require.NoError(t, err, "PrecompileInput()")
+		require.True(t, p256Verify(t, in), "P256VERIFY()")
+	}
+}
+
+func TestParseDERSignatureErrors(t *testing.T) {
+	n := elliptic.P256().Params().N
+	der := func(r, s []byte) []byte {
+		out := []byte{0x30, byte(4 + len(r) + len(s)), 0x02, byte(len(r))}
+		out = append(out, r...)
+		out = append(out, 0x02, byte(len(s)))
+		return append(out, s...)
+	}
+
+	tests := map[string][]byte{
+		"empty":              nil,
+		"not_sequence":       {0x31, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01},
+		"trailing_data":      append(der([]byte{1}, []byte{1}), 0),
+		"extra_integer":      {0x30, 0x09, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01},
+		"missing_s":          {0x30, 0x03, 0x02, 0x01, 0x01},
+		"non_minimal_r":      der([]byte{0x00, 0x01}, []byte{1}),
+		"negative_s":         der([]byte{1}, []byte{0xff}),
+		"zero_r":             der([]byte{0}, []byte{1}),
+		"s_equal_to_order":   der([]byte{1}, append([]byte{0}, n.Bytes()...)),
+		"truncated_sequence": der([]byte{1}, []byte{1})[:7],
+	}
+
+	for name, sig := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := ParseDERSignature(sig)
+			require.ErrorIs(t, err, ErrSignature)
+		})
+	}
+}
+
+func TestNormalizeS(t *testing.T) {
+	n := elliptic.P256().Params().N
+	half := new(big.Int).Rsh(n, 1)
+
+	tests := []struct {
+		s, want *big.Int
+	}{
+		{big.NewInt(1), big.NewInt(1)},
+		{half, half},
+		{new(big.Int).Add(half, big.NewInt(1)), half},
+		{new(big.Int).Sub(n, big.NewInt(1)), big.NewInt(1)},
+	}
+	for _, tt := range tests {
+		orig := new(big.Int).Set(tt.s)
+		assert.Equalf(t, tt.want, NormalizeS(tt.s), "NormalizeS(%v)", tt.s)
+		assert.Equal(t, orig, tt.s, "argument not modified")
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	authData := hexutil.MustDecode(vectorAuthData)
+
+	t.Run("client_data", func(t *testing.T) {
+		tests := map[string]string{
+			"bad_json":         `{"type":`,
+			"wrong_type":       `{"type":"webauthn.create","challenge":"bGliZXZtLXdlYmF1dGhu"}`,
+			"wrong_challenge":  `{"type":"webauthn.get","challenge":"b3RoZXI"}`,
+			"padded_challenge": `{"type":"webauthn.get","challenge":"bGliZXZtLXdlYmF1dGhu="}`,
+		}
+		for name, cd := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := ParseClientData([]byte(cd), []byte(vectorChallenge))
+				require.ErrorIs(t, err, ErrClientData)
+			})
+		}
+	})
+
+	t.Run("authenticator_data", func(t *testing.T) {
+		require.ErrorIs(t, CheckAuthenticatorData(authData[:MinAuthenticatorDataLength-1], 0), ErrAuthenticatorData, "short")
+
+		noUV := append([]byte{}, authData...)
+		noUV[32] = FlagUserPresent
+		require.NoError(t, CheckAuthenticatorData(noUV, FlagUserPresent), "user present")
+		require.ErrorIs(t, CheckAuthenticatorData(noUV, FlagUserVerified), ErrAuthenticatorData, "user verified")
+	})
+
+	t.Run("public_key", func(t *testing.T) {
+		pub := vectorPubKey(t)
+		pub.Y.Add(pub.Y, big.NewInt(1))
+		_, err := PrecompileInput([32]byte{}, big.NewInt(1), big.NewInt(1), pub)
+		require.ErrorIs(t, err, ErrPublicKey)
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package parallel
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// CommitmentVersion is the version of the serialization format used by
+// [Processor.Commitment], which is included in the hashed preimage. It will
+// be incremented if the format changes.
+const CommitmentVersion uint64 = 1
+
+// An AggregatedCodec serializes the aggregated output of a [Handler] for
+// inclusion in a [Processor.Commitment]. Handlers that don't implement it have
+// their output RLP encoded. The encoding MUST be canonical; i.e. equal values
+// MUST have equal encodings.
+type AggregatedCodec[A any] interface {
+	EncodeAggregated(A) ([]byte, error)
+}
+
+// ExcludeFromCommitment returns a [HandlerOption] that excludes the
+// [Handler]'s aggregated output from [Processor.Commitment], typically because
+// it doesn't affect state.
+func ExcludeFromCommitment() HandlerOption {
+	return options.Func[handlerConfig](func(c *handlerConfig) {
+		c.uncommitted = true
+	})
+}
+
+// A commitmentEntry is the serialized, aggregated output of a single
+// [Handler], as included in a [Processor.Commitment].
+type commitmentEntry struct {
+	Handler    string
+	Aggregated []byte
+}
+
+// commitmentPreimage is the RLP-encoded structure hashed by [commit].
+type commitmentPreimage struct {
+	Version uint64
+	Entries []commitmentEntry
+}
+
+// commit returns the Keccak256 hash of the versioned RLP encoding of the
+// entries, which MUST be in [Handler] registration order.
+func commit(entries []commitmentEntry) (common.Hash, error) {
+	buf, err := rlp.EncodeToBytes(&commitmentPreimage{
+		Version: CommitmentVersion,
+		Entries: entries,
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(buf), nil
+}
+
+// Commitment returns a hash committing to the aggregated outputs of every
+// [Handler] for the block, typically for inclusion in the block's header; e.g.
+// via header extras. Divergent outputs, between nodes, therefore become a
+// consensus failure instead of silently resulting in different state.
+//
+// The commitment is the Keccak256 hash of the RLP encoding of:
+//
+//	[CommitmentVersion, [[name_0, aggregated_0], [name_1, aggregated_1], ...]]
+//
+// where each entry is the name set with [WithName] and the output of
+// [Handler.PostProcess], encoded with the Handler's [AggregatedCodec] or, by
+// default, as RLP. Entries are in the order of calls to [AddHandler], and
+// Handlers registered with [ExcludeFromCommitment] are omitted. Every Handler
+// SHOULD therefore be registered [WithName], as with [WithResultStore].
+//
+// Commitment blocks until PostProcess has returned for every Handler. It MAY
+// be called at any time after [Processor.StartBlockCtx] until the return of
+// the respective call to [Processor.FinishBlock], including from
+// [Handler.AfterBlock]. The error is non-nil if the block isn't in progress or
+// if encoding fails.
+func (p *Processor) Commitment(block common.Hash) (common.Hash, error) {
+	run, ok := p.blockRun(block)
+	if !ok {
+		run, ok = p.afterBlockRun(block)
+	}
+	if !ok {
+		return common.Hash{}, fmt.Errorf("%w: %v", ErrBlockNotStarted, block)
+	}
+	return run.commitment()
+}
+
+// commit is the implementation of [blockRun.commitment].
+func (r *blockRun) commit() (common.Hash, error) {
+	var entries []commitmentEntry
+	for _, h := range r.handlers {
+		e, ok, err := h.commitmentEntry()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if ok {
+			entries = append(entries, e)
+		}
+	}
+	return commit(entries)
+}
+
+func (w *blockWrapper[CD, D, R, A]) commitmentEntry() (commitmentEntry, bool, error) {
+	if w.uncommitted {
+		return commitmentEntry{}, false, nil
+	}
+	agg := w.aggregated.Peek()
+
+	var (
+		buf []byte
+		err error
+	)
+	if c, ok := w.Handler.(AggregatedCodec[A]); ok {
+		buf, err = c.EncodeAggregated(agg)
+	} else {
+		buf, err = rlp.EncodeToBytes(agg)
+	}
+	if err != nil {
+		return commitmentEntry{}, false, fmt.Errorf("encoding aggregated output of handler %q: %v", w.name, err)
+	}
+	return commitmentEntry{
+		Handler:    w.name,
+		Aggregated: buf,
+	}, true, nil
+}
+
+// afterBlockRun returns the block with the specified hash if, having been
+// removed from those in progress by [Processor.FinishBlock], its
+// [Handler.AfterBlock] methods are being called.
+func (p *Processor) afterBlockRun(hash common.Hash) (*blockRun, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r, ok := p.afterBlock[hash]
+	return r, ok
+}
+
+// inAfterBlock makes the block available to [Processor.afterBlockRun] until
+// the returned function is called.
+func (p *Processor) inAfterBlock(r *blockRun) (done func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.afterBlock[r.hash] = r
+	return func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		delete(p.afterBlock, r.hash)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package parallel
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+func TestCommitFormat(t *testing.T) {
+	// Changes to this value are breaking changes of consensus-critical
+	// behaviour and MUST be accompanied by an increment of CommitmentVersion.
+	entries := []commitmentEntry{
+		{Handler: "a", Aggregated: []byte{1}},
+		{Handler: "b", Aggregated: nil},
+	}
+	preimage, err := rlp.EncodeToBytes(&commitmentPreimage{
+		Version: CommitmentVersion,
+		Entries: entries,
+	})
+	require.NoError(t, err, "rlp.EncodeToBytes(%T)", commitmentPreimage{})
+	// [1, [["a", 0x01], ["b", ""]]]
+	assert.Equal(t, "c801c6c26101c26280", common.Bytes2Hex(preimage), "RLP-encoded preimage")
+
+	got, err := commit(entries)
+	require.NoError(t, err, "commit()")
+	assert.Equal(t, common.HexToHash("0x2928d18c3b40d354e1de93dda2f19cdd2b1c65a2f8ca8acae4728b7406aa1326"), got, "commit()")
+}
+
+// summing aggregates the sum of its results, which are a function of the
+// transaction index and its own multiplier.
+type summing struct {
+	mul uint64
+
+	p         *Processor
+	gotCommit common.Hash
+	gotErr    error
+}
+
+func (*summing) BeforeBlock(libevm.StateReader, *types.Header) int    { return 0 }
+func (*summing) ShouldProcess(IndexedTx, int) (bool, uint64)          { return true, 0 }
+func (*summing) Prefetch(libevm.StateReader, IndexedTx, int) struct{} { return struct{}{} }
+
+func (s *summing) Process(_ libevm.StateReader, tx IndexedTx, _ int, _ struct{}) uint64 {
+	return s.mul * uint64(tx.Index+1) //nolint:gosec // Index is non-negative
+}
+
+func (*summing) PostProcess(_ int, res Results[uint64]) uint64 {
+	var sum uint64
+	for r := range res.ProcessOrder {
+		sum += r.Result
+	}
+	return sum
+}
+
+func (s *summing) AfterBlock(_ StateDB, _ uint64, b *types.Block, _ types.Receipts) {
+	s.gotCommit, s.gotErr = s.p.Commitment(b.Hash())
+}
+
+// encodedSum implements [AggregatedCodec] with a non-RLP encoding.
+type encodedSum struct {
+	summing
+}
+
+func (*encodedSum) EncodeAggregated(sum uint64) ([]byte, error) {
+	return []byte{'s', byte(sum)}, nil
+}
+
+func TestCommitment(t *testing.T) {
+	const numTxs = 4
+	txs := ethtest.NewTxGenerator(t, 0, params.MergedTestChainConfig).Txs(numTxs)
+	b := ethtest.NewBlock(&types.Header{Number: big.NewInt(0)}, txs)
+	rules := params.MergedTestChainConfig.Rules(big.NewInt(0), true, 0)
+	_, _, sdb := ethtest.NewEmptyStateDB(t)
+
+	// commitment runs the block through a new [Processor] with a [summing]
+	// handler with each multiplier, as well as one that is excluded.
+	commitment := func(t *testing.T, muls ...uint64) common.Hash {
+		t.Helper()
+		p := New(2, 2)
+		t.Cleanup(p.Close)
+
+		var hs []*summing
+		for i, m := range muls {
+			h := &summing{mul: m, p: p}
+			hs = append(hs, h)
+			AddHandler(p, h, WithName(string(rune('a'+i))))
+		}
+		AddHandler(p, &summing{mul: 1000, p: p}, ExcludeFromCommitment())
+
+		require.NoError(t, p.StartBlock(sdb, rules, b), "StartBlock()")
+		got, err := p.Commitment(b.Hash())
+		require.NoError(t, err, "Commitment() before FinishBlock()")
+		require.NoError(t, p.FinishBlock(sdb, b, nil), "FinishBlock()")
+
+		for _, h := range hs {
+			require.NoError(t, h.gotErr, "Commitment() from AfterBlock()")
+			assert.Equal(t, got, h.gotCommit, "Commitment() from AfterBlock()")
+		}
+		_, err = p.Commitment(b.Hash())
+		require.ErrorIs(t, err, ErrBlockNotStarted, "Commitment() after FinishBlock()")
+		return got
+	}
+
+	// Sum of (mul * (i+1)) for i in [0,numTxs) is 10*mul.
+	want := func(t *testing.T, sums ...uint64) common.Hash {
+		t.Helper()
+		var entries []commitmentEntry
+		for i, s := range sums {
+			buf, err := rlp.EncodeToBytes(s)
+			require.NoError(t, err, "rlp.EncodeToBytes()")
+			entries = append(entries, commitmentEntry{
+				Handler:    string(rune('a' + i)),
+				Aggregated: buf,
+			})
+		}
+		h, err := commit(entries)
+		require.NoError(t, err, "commit()")
+		return h
+	}
+
+	one := commitment(t, 1, 2)
+	assert.Equal(t, want(t, 10, 20), one, "Commitment()")
+	assert.Equal(t, one, commitment(t, 1, 2), "Commitment() with same handler outputs")
+	assert.NotEqual(t, one, commitment(t, 1, 3), "Commitment() with divergent handler output")
+	assert.NotEqual(t, one, commitment(t, 2, 1), "Commitment() with handlers in different order")
+
+	t.Run("AggregatedCodec", func(t *testing.T) {
+		p := New(1, 1)
+		t.Cleanup(p.Close)
+		h := &encodedSum{summing{mul: 1, p: p}}
+		AddHandler(p, h, WithName("codec"))
+
+		require.NoError(t, p.StartBlock(sdb, rules, b), "StartBlock()")
+		got, err := p.Commitment(b.Hash())
+		require.NoError(t, err, "Commitment()")
+		require.NoError(t, p.FinishBlock(sdb, b, nil), "FinishBlock()")
+
+		want, err := commit([]commitmentEntry{{Handler: "codec", Aggregated: []byte{'s', 10}}})
+		require.NoError(t, err, "commit()")
+		assert.Equal(t, want, got, "Commitment()")
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package parallel
+
+import (
+	"slices"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm/options"
+)
+
+// A TxFilter reports whether a transaction is relevant to a [Handler]. See
+// [WithTxFilter].
+type TxFilter func(IndexedTx) bool
+
+// WithTxFilter returns a [HandlerOption] that gates calls to
+// [Handler.ShouldProcess] on the [TxFilter]. Transactions that aren't matched
+// are treated as if ShouldProcess had returned false, allowing Handlers to
+// delegate inspection of transactions to helpers such as [MatchTo],
+// [MatchAccessList], and [MatchToOrAccessList]. A nil filter is equivalent to
+// not using the option.
+func WithTxFilter(f TxFilter) HandlerOption {
+	return options.Func[handlerConfig](func(c *handlerConfig) {
+		c.filter = f
+	})
+}
+
+// MatchTo returns a [TxFilter] that matches transactions sent to any of the
+// addresses; i.e. scenario (1) of the [Handler] documentation.
+func MatchTo(addrs ...common.Address) TxFilter {
+	return func(tx IndexedTx) bool {
+		to := tx.To()
+		return to != nil && slices.Contains(addrs, *to)
+	}
+}
+
+// MatchAccessList returns a [TxFilter] that matches transactions with at least
+// one [types.AccessTuple] referencing any of the addresses; i.e. scenario (2)
+// of the [Handler] documentation.
+func MatchAccessList(addrs ...common.Address) TxFilter {
+	return func(tx IndexedTx) bool {
+		for _, tuple := range tx.AccessList() {
+			if slices.Contains(addrs, tuple.Address) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchToOrAccessList returns a [TxFilter] that matches transactions matched
+// by either [MatchTo] or [MatchAccessList].
+func MatchToOrAccessList(addrs ...common.Address) TxFilter {
+	to, list := MatchTo(addrs...), MatchAccessList(addrs...)
+	return func(tx IndexedTx) bool {
+		return to(tx) || list(tx)
+	}
+}
@@ -17,13 +17,18 @@
 package parallel
 
 import (
+	"context"
+	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core/types"
 	"github.com/ava-labs/libevm/core/vm"
 	"github.com/ava-labs/libevm/libevm"
 	"github.com/ava-labs/libevm/libevm/eventual"
+	"github.com/ava-labs/libevm/libevm/options"
 	"github.com/ava-labs/libevm/libevm/stateconf"
 )
 
@@ -36,7 +41,9 @@ import (
 //  2. At least one [types.AccessTuple] references the precompile's address.
 //
 // Scenario (2) allows precompile access to be determined through inspection of
-// the [types.Transaction] alone, without the need for execution.
+// the [types.Transaction] alone, without the need for execution. Both scenarios
+// can be delegated to the [Processor] with [WithTxFilter] and either
+// [MatchTo], [MatchAccessList], or [MatchToOrAccessList].
 //
 // A [Processor] will orchestrate calling of Handler methods as follows:
 //
@@ -58,6 +65,12 @@ import (
 // are no intER-Handler guarantees except that AfterBlock() methods are called
 // sequentially, in the same order as they were registered with [AddHandler].
 //
+// The above guarantees are only within the scope of a single block. As blocks
+// MAY be pipelined (see [Processor.StartBlockCtx]), BeforeBlock() for one block
+// MAY precede AfterBlock() for an earlier one, and Process() calls for
+// different blocks MAY be concurrent. Calls to ShouldProcess() and AfterBlock()
+// are, however, never concurrent with others of the same method.
+//
 // All [libevm.StateReader] instances are opened to the state at the beginning
 // of the block. The [StateDB] is the same one used to execute the block, before
 // being committed, and MAY be written to.
@@ -75,7 +88,9 @@ type Handler[CommonData, Data, Result, Aggregated any] interface {
 	//
 	// Implementations MUST NOT perform any meaningful computation
 	// but MAY perform inter-transaction checks such as, for example,
-	// deduplication of work.
+	// deduplication of work. If the Handler was registered with
+	// [WithTxFilter] then ShouldProcess is only called for matching
+	// transactions.
 	ShouldProcess(IndexedTx, CommonData) (do bool, gas uint64)
 	// Prefetch is called before the respective call to Process() on this
 	// Handler. It MUST NOT perform any meaningful computation beyond what is
@@ -110,12 +125,25 @@ type Handler[CommonData, Data, Result, Aggregated any] interface {
 type IndexedTx struct {
 	Index int
 	*types.Transaction
+
+	ctx context.Context
+}
+
+// Context returns the context passed to [Processor.StartBlockCtx], which
+// Handler methods SHOULD respect for long-running operations. It is never nil.
+func (tx IndexedTx) Context() context.Context {
+	if tx.ctx == nil {
+		return context.Background()
+	}
+	return tx.ctx
 }
 
 // Results provides mechanisms for blocking on the output of [Handler.Process].
 type Results[R any] struct {
 	WaitForAll            func()
 	TxOrder, ProcessOrder <-chan TxResult[R]
+	// Context is the context passed to [Processor.StartBlockCtx].
+	Context context.Context
 }
 
 // A TxResult couples an [IndexedTx] with its respective result from
@@ -132,13 +160,35 @@ type StateDB interface {
 	SetState(_ common.Address, key, val common.Hash, _ ...stateconf.StateDBStateOption)
 }
 
-var _ handler = (*wrapper[any, any, any, any])(nil)
+var (
+	_ handler      = (*wrapper[any, any, any, any])(nil)
+	_ blockHandler = (*blockWrapper[any, any, any, any])(nil)
+)
 
 // A wrapper exposes the generic functionality of a [Handler] in a non-generic
-// manner, allowing [Processor] to be free of type parameters.
+// manner, allowing [Processor] to be free of type parameters. State that is
+// specific to a single block is held by a [blockWrapper], of which there is one
+// per block in progress.
 type wrapper[CD, D, R, A any] struct {
 	Handler[CD, D, R, A]
 
+	name        string
+	uncommitted bool // registered with [ExcludeFromCommitment]
+	metrics     *handlerMetrics
+	cache       *resultCache[R] // nil unless registered [WithResultStore]
+
+	mu     sync.RWMutex
+	blocks map[common.Hash]*blockWrapper[CD, D, R, A]
+}
+
+// A blockWrapper is the per-block equivalent of a [wrapper].
+type blockWrapper[CD, D, R, A any] struct {
+	*wrapper[CD, D, R, A]
+
+	ctx    context.Context
+	block  common.Hash
+	number uint64
+
 	totalTxsInBlock   int
 	txsBeingProcessed sync.WaitGroup
 
@@ -149,16 +199,22 @@ type wrapper[CD, D, R, A any] struct {
 	whenProcessed, txOrder chan TxResult[R]
 
 	aggregated eventual.Value[A]
+
+	aborted atomic.Int64
 }
 
 // AddHandler registers the [Handler] with the [Processor] and returns a
-// function to fetch the [TxResult] for the i'th transaction passed to
-// [Processor.StartBlock].
+// function to fetch the [TxResult] for the i'th transaction of the only block
+// passed to [Processor.StartBlock] for which [Processor.FinishBlock] is yet to
+// be called. If blocks overlap then the returned function can't determine
+// which of them is being executed, so [AddHandlerByBlock] or [AddAsPrecompile]
+// MUST be used instead.
 //
-// The returned function until the respective transaction has had its result
-// processed, and then returns the value returned by the [Handler]. The returned
-// boolean will be false if no processing occurred, either because the [Handler]
-// indicated as such or because the transaction supplied insufficient gas.
+// The returned function blocks until the respective transaction has had its
+// result processed, and then returns the value returned by the [Handler]. The
+// returned boolean will be false if no processing occurred, either because the
+// [Handler] indicated as such, because the transaction supplied insufficient
+// gas, or because there isn't exactly one block in progress.
 //
 // Multiple calls to Result with the same argument are allowed. Callers MUST NOT
 // charge the gas price for preprocessing as this is handled by
@@ -170,58 +226,169 @@ type wrapper[CD, D, R, A any] struct {
 // exclusive access to the [TxResult] so SHOULD NOT modify it, especially since
 // the result MAY also be accessed by [Handler.PostProcess], with no ordering
 // guarantees.
-func AddHandler[CD, D, R, A any](p *Processor, h Handler[CD, D, R, A]) func(txIndex int) (TxResult[R], bool) {
+//
+// By default, all Handlers share the workers allocated by [New], with jobs
+// dispatched to them in transaction order. See [WithDedicatedWorkers] and
+// [WithPriority] for alternative scheduling, and [WithName] for metrics.
+// AddHandler MUST NOT be called concurrently with any other [Processor]
+// methods.
+func AddHandler[CD, D, R, A any](p *Processor, h Handler[CD, D, R, A], opts ...HandlerOption) func(txIndex int) (TxResult[R], bool) {
+	byBlock := AddHandlerByBlock(p, h, opts...)
+	return func(txIndex int) (TxResult[R], bool) {
+		hash, ok := p.soleBlock()
+		if !ok {
+			return TxResult[R]{}, false
+		}
+		return byBlock(hash, txIndex)
+	}
+}
+
+// AddHandlerByBlock is equivalent to [AddHandler] except that the returned
+// function fetches results from the block with the specified hash, allowing
+// access to any block in progress. It returns false immediately if the block
+// isn't in progress.
+func AddHandlerByBlock[CD, D, R, A any](p *Processor, h Handler[CD, D, R, A], opts ...HandlerOption) func(block common.Hash, txIndex int) (TxResult[R], bool) {
+	cfg := options.ApplyTo(&handlerConfig{
+		name: strconv.Itoa(len(p.handlers)),
+	}, opts...)
 	w := &wrapper[CD, D, R, A]{
-		Handler:    h,
-		common:     eventual.New[CD](),
-		aggregated: eventual.New[A](),
+		Handler:     h,
+		name:        cfg.name,
+		uncommitted: cfg.uncommitted,
+		metrics:     newHandlerMetrics(cfg.name),
+		blocks:      make(map[common.Hash]*blockWrapper[CD, D, R, A]),
+	}
+	if cfg.store != nil {
+		codec, ok := h.(ResultCodec[R])
+		if !ok {
+			panic(fmt.Sprintf("%T registered WithResultStore() but doesn't implement ResultCodec", h))
+		}
+		w.cache = &resultCache[R]{
+			store: cfg.store,
+			codec: codec,
+			name:  cfg.name,
+		}
 	}
 	p.handlers = append(p.handlers, w)
+	p.schedules = append(p.schedules, p.newSchedule(cfg))
 	return w.result
 }
 
-func (w *wrapper[CD, D, R, A]) beforeBlock(sdb libevm.StateReader, b *types.Block) {
-	w.totalTxsInBlock = len(b.Transactions())
-	// We can reuse the channels already in the data and results slices because
-	// they're emptied by [wrapper.process] and [wrapper.finishBlock]
-	// respectively.
-	for i := len(w.results); i < w.totalTxsInBlock; i++ {
-		w.data = append(w.data, eventual.New[D]())
-		w.results = append(w.results, eventual.New[result[R]]())
+func (w *wrapper[CD, D, R, A]) beforeBlock(ctx context.Context, sdb libevm.StateReader, b *types.Block) blockHandler {
+	n := len(b.Transactions())
+	bw := &blockWrapper[CD, D, R, A]{
+		wrapper:         w,
+		ctx:             ctx,
+		block:           b.Hash(),
+		number:          b.NumberU64(),
+		totalTxsInBlock: n,
+		common:          eventual.New[CD](),
+		data:            make([]eventual.Value[D], n),
+		results:         make([]eventual.Value[result[R]], n),
+		aggregated:      eventual.New[A](),
+	}
+	for i := range n {
+		bw.data[i] = eventual.New[D]()
+		bw.results[i] = eventual.New[result[R]]()
 	}
 
+	w.mu.Lock()
+	w.blocks[bw.block] = bw
+	w.mu.Unlock()
+
 	go func() {
 		// goroutine guaranteed to have completed by the time a respective
-		// getter unblocks (i.e. in any call to [wrapper.prefetch]).
-		w.common.Put(w.BeforeBlock(sdb, types.CopyHeader(b.Header())))
+		// getter unblocks (i.e. in any call to [blockWrapper.prefetch]).
+		bw.common.Put(w.BeforeBlock(sdb, types.CopyHeader(b.Header())))
 	}()
+	return bw
+}
+
+func (w *wrapper[CD, D, R, A]) result(block common.Hash, i int) (TxResult[R], bool) {
+	w.mu.RLock()
+	bw, ok := w.blocks[block]
+	w.mu.RUnlock()
+	if !ok || i < 0 || i >= bw.totalTxsInBlock {
+		return TxResult[R]{}, false
+	}
+	return bw.result(i)
 }
 
-func (w *wrapper[CD, D, R, A]) shouldProcess(tx IndexedTx) (do bool, gas uint64) {
+func (w *blockWrapper[CD, D, R, A]) shouldProcess(tx IndexedTx) (do bool, gas uint64) {
 	return w.Handler.ShouldProcess(tx, w.common.Peek())
 }
 
-func (w *wrapper[CD, D, R, A]) beforeWork(jobs int) {
+func (w *blockWrapper[CD, D, R, A]) beforeWork(jobs int) {
+	w.metrics.queued.Inc(int64(jobs))
+	w.metrics.outstanding.Inc(int64(jobs))
 	w.txsBeingProcessed.Add(jobs)
 	w.whenProcessed = make(chan TxResult[R], jobs)
 	w.txOrder = make(chan TxResult[R], jobs)
 	go func() {
 		w.txsBeingProcessed.Wait()
-		// [wrapper.finishBlock] blocks until this is closed, guaranteeing
+		// [blockWrapper.finishBlock] blocks until this is closed, guaranteeing
 		// cleanup of this goroutine.
 		close(w.whenProcessed)
 	}()
 }
 
-func (w *wrapper[CD, D, R, A]) prefetch(sdb libevm.StateReader, job *prefetch) {
-	w.data[job.tx.Index].Put(w.Prefetch(sdb, job.tx, w.common.Peek()))
+// restore fulfils jobs from the [ResultStore], if any, returning those that
+// weren't cached. It MUST be called after [blockWrapper.beforeWork].
+func (w *blockWrapper[CD, D, R, A]) restore(jobs []*job) []*job {
+	if w.cache == nil {
+		return jobs
+	}
+	var remaining []*job
+	for _, j := range jobs {
+		val, ok := w.cache.read(w.number, w.block, j.tx.Index)
+		if !ok {
+			remaining = append(remaining, j)
+			continue
+		}
+		w.results[j.tx.Index].Put(result[R]{
+			tx:  j.tx,
+			val: &val,
+		})
+		w.whenProcessed <- TxResult[R]{
+			Tx:     j.tx,
+			Result: val,
+		}
+		w.metrics.queued.Dec(1)
+		w.metrics.outstanding.Dec(1)
+		w.txsBeingProcessed.Done()
+	}
+	return remaining
+}
+
+func (w *blockWrapper[CD, D, R, A]) prefetch(sdb libevm.StateReader, job *prefetch) {
+	w.metrics.queued.Dec(1)
+	var d D
+	if job.tx.Context().Err() == nil {
+		w.metrics.time(w.metrics.prefetch, "prefetch", job.tx, func() {
+			d = w.Prefetch(sdb, job.tx, w.common.Peek())
+		})
+	}
+	w.data[job.tx.Index].Put(d)
 }
 
-func (w *wrapper[CD, D, R, A]) process(sdb libevm.StateReader, job *process) {
+func (w *blockWrapper[CD, D, R, A]) process(sdb libevm.StateReader, job *process) {
 	defer w.txsBeingProcessed.Done()
+	defer w.metrics.outstanding.Dec(1)
 
 	idx := job.tx.Index
-	val := w.Process(sdb, job.tx, w.common.Peek(), w.data[idx].Take())
+	if job.tx.Context().Err() != nil {
+		w.data[idx].Take()
+		w.aborted.Add(1)
+		w.results[idx].Put(result[R]{tx: job.tx})
+		return
+	}
+	var val R
+	w.metrics.time(w.metrics.process, "process", job.tx, func() {
+		val = w.Process(sdb, job.tx, w.common.Peek(), w.data[idx].Take())
+	})
+	if w.cache != nil {
+		w.cache.write(w.number, w.block, idx, val)
+	}
 	r := result[R]{
 		tx:  job.tx,
 		val: &val,
@@ -233,14 +400,14 @@ func (w *wrapper[CD, D, R, A]) process(sdb libevm.StateReader, job *process) {
 	}
 }
 
-func (w *wrapper[CD, D, R, A]) nullResult(job *job) {
+func (w *blockWrapper[CD, D, R, A]) nullResult(job *job) {
 	w.results[job.tx.Index].Put(result[R]{
 		tx:  job.tx,
 		val: nil,
 	})
 }
 
-func (w *wrapper[CD, D, R, A]) result(i int) (TxResult[R], bool) {
+func (w *blockWrapper[CD, D, R, A]) result(i int) (TxResult[R], bool) {
 	r := w.results[i].Peek()
 
 	txr := TxResult[R]{
@@ -253,10 +420,10 @@ func (w *wrapper[CD, D, R, A]) result(i int) (TxResult[R], bool) {
 	return txr, true
 }
 
-func (w *wrapper[CD, D, R, A]) postProcess() {
+func (w *blockWrapper[CD, D, R, A]) postProcess() {
 	go func() {
-		// [wrapper.finishBlock] blocks until this is closed, guaranteeing
-		// cleanup of this goroutine.
+		// [blockWrapper.finishBlock] blocks until this is closed,
+		// guaranteeing cleanup of this goroutine.
 		defer close(w.txOrder)
 		for i := range w.totalTxsInBlock {
 			r, ok := w.result(i)
@@ -271,19 +438,34 @@ func (w *wrapper[CD, D, R, A]) postProcess() {
 		WaitForAll:   w.txsBeingProcessed.Wait,
 		TxOrder:      w.txOrder,
 		ProcessOrder: w.whenProcessed,
+		Context:      w.ctx,
 	}
 	w.aggregated.Put(w.PostProcess(w.common.Peek(), res))
 }
 
-func (w *wrapper[CD, D, R, A]) finishBlock(sdb vm.StateDB, b *types.Block, rs types.Receipts) {
-	w.AfterBlock(sdb, w.aggregated.Take(), b, rs)
+// ready blocks until all processing and post-processing is complete.
+func (w *blockWrapper[CD, D, R, A]) ready() {
+	w.txsBeingProcessed.Wait()
+	w.aggregated.Peek()
+}
+
+func (w *blockWrapper[CD, D, R, A]) abortedJobs() int64 {
+	return w.aborted.Load()
+}
 
-	// [wrapper.postProcess] is guaranteed to have finished because it sets
-	// [wrapper.aggregated], from which we have just read. However
+func (w *blockWrapper[CD, D, R, A]) finishBlock(sdb vm.StateDB, b *types.Block, rs types.Receipts, afterBlock bool) {
+	agg := w.aggregated.Peek()
+	if afterBlock {
+		w.AfterBlock(sdb, agg, b, rs)
+	}
+
+	// [blockWrapper.postProcess] is guaranteed to have finished because it
+	// sets [blockWrapper.aggregated], from which we have just read. However
 	// [Handler.PostProcess] is under no obligation to block on anything, and
-	// the goroutines filling [wrapper.txOrder] and [wrapper.whenProcessed]
-	// might still be reading results. We therefore guarantee their completion
-	// before "taking" all of [wrapper.results].
+	// the goroutines filling [blockWrapper.txOrder] and
+	// [blockWrapper.whenProcessed] might still be reading results. We
+	// therefore guarantee their completion before the block's state is
+	// released.
 	var wg sync.WaitGroup
 	wg.Add(2) // TODO(arr4n) update to Go 1.25 and use `wg.Go`
 	go func() {
@@ -297,12 +479,16 @@ func (w *wrapper[CD, D, R, A]) finishBlock(sdb vm.StateDB, b *types.Block, rs ty
 		wg.Done()
 	}()
 	wg.Wait()
+	w.release()
+}
 
-	w.common.Take()
-	for _, v := range w.results[:w.totalTxsInBlock] {
-		// Every result channel is guaranteed to have some value in its buffer
-		// because [Processor.BeforeBlock] either sends a nil *R or it
-		// dispatches a job, which will send a non-nil *R.
-		v.Take()
+// release stops the block being accessible via [wrapper.result].
+func (w *blockWrapper[CD, D, R, A]) release() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// A stalled block is cleaned up after it is no longer in progress, by which
+	// time the same block might have been restarted.
+	if w.blocks[w.block] == w {
+		delete(w.blocks, w.block)
 	}
 }
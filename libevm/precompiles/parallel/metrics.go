@@ -0,0 +1,77 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package parallel
+
+import (
+	"runtime/trace"
+	"time"
+
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/metrics"
+)
+
+// WithName returns a [HandlerOption] that sets the name under which the
+// [Handler]'s metrics are registered with [metrics.DefaultRegistry] and its
+// [runtime/trace] tasks are logged. It defaults to the Handler's index in the
+// order of calls to [AddHandler].
+//
+// The following metrics are recorded, all prefixed by
+// "parallel/handler/<name>/":
+//
+//   - "prefetch" and "process": timers of [Handler.Prefetch] and
+//     [Handler.Process] durations.
+//   - "jobs/queued": gauge of jobs awaiting a prefetching worker.
+//   - "jobs/outstanding": gauge of jobs yet to complete processing.
+//
+// Multiple Handlers with the same name share metrics.
+func WithName(name string) HandlerOption {
+	return options.Func[handlerConfig](func(c *handlerConfig) {
+		c.name = name
+	})
+}
+
+type handlerMetrics struct {
+	name                string
+	prefetch, process   metrics.Timer
+	queued, outstanding metrics.Gauge
+}
+
+func newHandlerMetrics(name string) *handlerMetrics {
+	prefix := "parallel/handler/" + name + "/"
+	return &handlerMetrics{
+		name:        name,
+		prefetch:    metrics.GetOrRegisterTimer(prefix+"prefetch", nil),
+		process:     metrics.GetOrRegisterTimer(prefix+"process", nil),
+		queued:      metrics.GetOrRegisterGauge(prefix+"jobs/queued", nil),
+		outstanding: metrics.GetOrRegisterGauge(prefix+"jobs/outstanding", nil),
+	}
+}
+
+// time calls `fn`, recording its duration with the [metrics.Timer] and, if
+// [trace.IsEnabled], as a [trace.Task] logged with the transaction hash.
+func (m *handlerMetrics) time(timer metrics.Timer, op string, tx IndexedTx, fn func()) {
+	defer timer.UpdateSince(time.Now())
+	if !trace.IsEnabled() {
+		fn()
+		return
+	}
+	ctx, task := trace.NewTask(tx.Context(), "parallel."+op)
+	defer task.End()
+	trace.Log(ctx, "handler", m.name)
+	trace.Log(ctx, "tx", tx.Hash().Hex())
+	trace.WithRegion(ctx, op, fn)
+}
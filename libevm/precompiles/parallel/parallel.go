@@ -19,10 +19,14 @@
 package parallel
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core"
@@ -34,28 +38,58 @@ import (
 )
 
 // A handler is the non-generic equivalent of a [Handler], exposed by [wrapper].
+// Per-block functionality is exposed by the [blockHandler] that it returns for
+// each block.
 type handler interface {
-	beforeBlock(libevm.StateReader, *types.Block)
+	beforeBlock(context.Context, libevm.StateReader, *types.Block) blockHandler
+}
+
+// A blockHandler is the non-generic equivalent of a [Handler], scoped to a
+// single block, exposed by [blockWrapper].
+type blockHandler interface {
 	shouldProcess(IndexedTx) (do bool, gas uint64)
 	beforeWork(jobs int)
+	restore([]*job) (remaining []*job)
 	prefetch(libevm.StateReader, *prefetch)
 	nullResult(*job)
 	process(libevm.StateReader, *process)
 	postProcess()
-	finishBlock(vm.StateDB, *types.Block, types.Receipts)
+	ready()
+	abortedJobs() int64
+	finishBlock(_ vm.StateDB, _ *types.Block, _ types.Receipts, afterBlock bool)
+	commitmentEntry() (_ commitmentEntry, include bool, _ error)
+	release()
 }
 
 // A Processor orchestrates dispatch and collection of results from one or more
 // [Handler] instances.
 type Processor struct {
-	handlers []handler
-
-	workers    sync.WaitGroup
-	stateShare stateDBSharer
-	prefetch   chan *prefetch
-	process    chan *process
+	handlers  []handler
+	schedules []*schedule // parallel to handlers
+
+	workers     sync.WaitGroup
+	dispatchers sync.WaitGroup
+	quit        chan struct{} // closed by [Processor.Close] to stop dispatchers
+	prefetch    chan *prefetch
+	process     chan *process
+
+	mu         sync.Mutex
+	blocks     []*blockRun               // in progress, in order of calls to StartBlockCtx
+	afterBlock map[common.Hash]*blockRun // see [Processor.afterBlockRun]
+	stalled    atomic.Int64              // blocks with outstanding jobs; see [ErrStalled]
+}
 
-	txGas map[common.Hash]uint64
+// A blockRun is the state of a single block, from the call to
+// [Processor.StartBlockCtx] until that to [Processor.FinishBlock].
+type blockRun struct {
+	hash     common.Hash
+	header   *types.Header
+	ctx      context.Context
+	handlers []blockHandler // parallel to [Processor.handlers]
+	states   *statePool
+	txGas    map[common.Hash]uint64
+
+	commitment func() (common.Hash, error) // memoised [blockRun.commit]
 }
 
 type (
@@ -63,8 +97,9 @@ type (
 	// generic type parameters, while prefetch and process are explicitly *not*
 	// aliases, to guarantee that they aren't considered equivalent.
 	job = struct {
-		handler handler
+		handler blockHandler
 		tx      IndexedTx
+		states  *statePool
 	}
 	prefetch job
 	process  job
@@ -83,187 +118,360 @@ type result[T any] struct {
 // long-running processing; see the respective methods on [Handler] for more
 // context.
 //
+// These workers are shared by all [Handler] instances unless [AddHandler]
+// receives [WithDedicatedWorkers].
+//
 // [Processor.Close] MUST be called after the final call to
 // [Processor.FinishBlock] to avoid leaking goroutines.
 func New(prefetchers, processors int) *Processor {
-	prefetchers = max(prefetchers, 1)
-	processors = max(processors, 1)
-	workers := prefetchers + processors
-
 	p := &Processor{
-		stateShare: stateDBSharer{
-			workers:   workers,
-			available: make(chan struct{}),
-			sdb:       make(chan *state.StateDB, 1),
-		},
-		prefetch: make(chan *prefetch),
-		process:  make(chan *process),
-		txGas:    make(map[common.Hash]uint64),
+		quit:       make(chan struct{}),
+		prefetch:   make(chan *prefetch),
+		process:    make(chan *process),
+		afterBlock: make(map[common.Hash]*blockRun),
 	}
-
-	p.workers.Add(workers)       // for shutdown via [Processor.Close]
-	p.stateShare.wg.Add(workers) // for readiness of [Processor.worker] loops
-	for range prefetchers {
-		go worker(p, p.prefetch, func(sdb libevm.StateReader, job *prefetch) {
-			job.handler.prefetch(sdb, job)
-		})
-	}
-	for range processors {
-		go worker(p, p.process, func(sdb libevm.StateReader, job *process) {
-			job.handler.process(sdb, job)
-		})
-	}
-	p.stateShare.wg.Wait()
-
+	p.startWorkers(max(prefetchers, 1), max(processors, 1), p.prefetch, p.process)
 	return p
 }
 
-// A stateDBSharer allows concurrent workers to make copies of a primary
-// database. When the `available` channel is closed, all workers call
-// [state.StateDB.Copy] then signal completion on the [sync.WaitGroup]. The
-// channel is replaced for each round of distribution.
-type stateDBSharer struct {
-	available chan struct{}
-	sdb       chan *state.StateDB
-	workers   int
-	wg        sync.WaitGroup
+// A statePool provides workers with read-only copies of a block's
+// [state.StateDB], reusing them between jobs.
+type statePool struct {
+	mu   sync.Mutex
+	base *state.StateDB
+	free []*state.StateDB
 }
 
-func (s *stateDBSharer) distribute(sdb *state.StateDB) {
-	ch := s.available                 // already copied by [Processor.worker], which is waiting for it to close
-	s.available = make(chan struct{}) // will be copied, ready for the next distribution
+func newStatePool(sdb *state.StateDB) *statePool {
+	// The copy is taken now because the original will be modified as soon as
+	// the block is executed.
+	return &statePool{base: sdb.CopyForRead()}
+}
+
+func (s *statePool) get() *state.StateDB {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.free); n > 0 {
+		sdb := s.free[n-1]
+		s.free = s.free[:n-1]
+		return sdb
+	}
+	// [state.StateDB.CopyForRead] is cheaper than a full copy as workers only
+	// read, but it too isn't documented as threadsafe.
+	return s.base.CopyForRead()
+}
 
-	s.sdb <- sdb
-	s.wg.Add(s.workers)
-	close(ch) // Take a moment to enjoy the symmetry :)
-	s.wg.Wait()
-	<-s.sdb
+func (s *statePool) put(sdb *state.StateDB) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.free = append(s.free, sdb)
 }
 
 func worker[J ~job](p *Processor, work <-chan *J, do func(libevm.StateReader, *J)) {
 	defer p.workers.Done()
-
-	var sdb *state.StateDB
-	share := &p.stateShare
-	stateAvailable := share.available
-	// Without this signal of readiness, a premature call to
-	// [Processor.StartBlock] could replace `share.nextAvailable` before we've
-	// copied it.
-	share.wg.Done()
-
-	for {
-		select {
-		case <-stateAvailable: // guaranteed at the beginning of each block
-			// [state.StateDB.Copy] is a complex method that isn't explicitly
-			// documented as being threadsafe.
-			sdb = (<-share.sdb).Copy()
-			share.sdb <- sdb // no need to return the original as each worker copies
-
-			stateAvailable = share.available
-			share.wg.Done()
-
-		case w, ok := <-work:
-			if !ok {
-				return
-			}
-			do(sdb, w)
-		}
+	for j := range work {
+		states := job(*j).states
+		sdb := states.get()
+		do(sdb, j)
+		states.put(sdb)
 	}
 }
 
 // Close shuts down the [Processor], after which it can no longer be used.
+//
+// If a block stalled (see [ErrStalled]) and its outstanding jobs are yet to
+// return, Close doesn't wait for them, and their goroutines are leaked. Jobs
+// of blocks in progress that are yet to be dispatched to workers are aborted.
 func (p *Processor) Close() {
+	// Dispatchers MUST have returned before the channels on which they send
+	// are closed.
+	close(p.quit)
+	p.dispatchers.Wait()
+
 	close(p.prefetch)
 	close(p.process)
-	p.workers.Wait()
+	for _, s := range p.schedules {
+		if s.dedicated() {
+			close(s.prefetch)
+			close(s.process)
+		}
+	}
+	if !p.isStalled() {
+		p.workers.Wait()
+	}
 }
 
-// StartBlock dispatches transactions to every [Handler] but returns immediately
-// after performing preliminary setup. It MUST be paired with a call to
-// [Processor.FinishBlock], without overlap of blocks.
+// StartBlock is equivalent to [Processor.StartBlockCtx] with
+// [context.Background].
 func (p *Processor) StartBlock(sdb *state.StateDB, rules params.Rules, b *types.Block) error {
-	// The distribution mechanism copies the StateDB so we don't need to do it
-	// here, but [wrapper.beforeBlock] doesn't make its own copy. Note that even
-	// reading from a [state.StateDB] is not threadsafe.
-	p.stateShare.distribute(sdb)
+	return p.StartBlockCtx(context.Background(), sdb, rules, b)
+}
+
+var (
+	// ErrJobAborted is returned by [Processor.FinishBlock] if any job wasn't
+	// started because the block's context was done.
+	ErrJobAborted = errors.New("parallel job aborted")
+	// ErrStalled is wrapped, along with [ErrJobAborted], by errors returned by
+	// [Processor.FinishBlock] if the block's context was done while jobs were
+	// still running. The block's cleanup is deferred until said jobs return.
+	ErrStalled = errors.New("parallel processor stalled by outstanding jobs")
+	// ErrBlockInProgress is returned by [Processor.StartBlockCtx] if the block
+	// was already started and is yet to be finished.
+	ErrBlockInProgress = errors.New("block already in progress")
+	// ErrBlockNotStarted is returned by [Processor.FinishBlock] if the block
+	// isn't in progress.
+	ErrBlockNotStarted = errors.New("block not in progress")
+)
+
+// StartBlockCtx dispatches transactions to every [Handler] but returns
+// immediately after performing preliminary setup. It MUST be paired with a call
+// to [Processor.FinishBlock] with the same block.
+//
+// Blocks MAY overlap, allowing, for example, speculative processing of the next
+// block while the current one is executed. The [state.StateDB] is copied
+// before StartBlockCtx returns so the caller MAY continue to use it. Calls to
+// StartBlockCtx MUST NOT be concurrent with each other, nor with calls to
+// [Processor.FinishBlock].
+//
+// The context is available to Handlers via [IndexedTx.Context] and
+// [Results.Context]. Once it is done, jobs that have yet to start are aborted,
+// without calling [Handler.Prefetch] or [Handler.Process], and their results
+// are reported as not processed. [Processor.FinishBlock] then returns an error
+// instead of blocking on jobs that are still running, so the block MUST be
+// considered invalid by the caller.
+func (p *Processor) StartBlockCtx(ctx context.Context, sdb *state.StateDB, rules params.Rules, b *types.Block) error {
+	run := &blockRun{
+		hash:   b.Hash(),
+		header: b.Header(),
+		ctx:    ctx,
+		states: newStatePool(sdb),
+		txGas:  make(map[common.Hash]uint64),
+	}
+	run.commitment = sync.OnceValues(run.commit)
+	if _, ok := p.blockRun(run.hash); ok {
+		return fmt.Errorf("%w: %v", ErrBlockInProgress, run.hash)
+	}
+
+	// [wrapper.beforeBlock] doesn't make its own copy. Note that even reading
+	// from a [state.StateDB] is not threadsafe.
 	for _, h := range p.handlers {
-		h.beforeBlock(sdb.Copy(), b)
+		run.handlers = append(run.handlers, h.beforeBlock(ctx, sdb.Copy(), b))
 	}
 
 	txs := b.Transactions()
-	jobs := make([]*job, 0, len(p.handlers)*len(txs))
-	workloads := make([]int, len(p.handlers))
+	queues := make([][]*job, len(p.handlers))
 
 	for txIdx, rawTx := range txs {
 		tx := IndexedTx{
 			Index:       txIdx,
 			Transaction: rawTx,
+			ctx:         ctx,
 		}
 
-		do, err := p.shouldProcess(tx, rules) // MUST NOT be concurrent within a Handler
+		do, err := p.shouldProcess(run, tx, rules) // MUST NOT be concurrent within a Handler
 		if err != nil {
+			for _, h := range run.handlers {
+				h.release()
+			}
 			return err
 		}
-		for i, h := range p.handlers {
+		for i, h := range run.handlers {
 			j := &job{
 				tx:      tx,
 				handler: h,
+				states:  run.states,
 			}
 			if !do[i] {
 				h.nullResult(j)
 				continue
 			}
-			workloads[i]++
-			jobs = append(jobs, j)
+			queues[i] = append(queues[i], j)
 		}
 	}
 
-	for i, w := range workloads {
-		p.handlers[i].beforeWork(w)
+	p.mu.Lock()
+	p.blocks = append(p.blocks, run)
+	p.mu.Unlock()
+
+	for i, q := range queues {
+		run.handlers[i].beforeWork(len(q))
+		queues[i] = run.handlers[i].restore(q)
 	}
 	// All of the following goroutines are dependent on the one(s) preceding
-	// them, while [wrapper.finishBlock] is dependent on [wrapper.postProcess].
-	// The return of [Processor.FinishBlock] is therefore a guarantee of the end
-	// of the lifespans of all of these goroutines.
-	go func() {
-		for _, j := range jobs {
-			p.prefetch <- (*prefetch)(j)
+	// them, while [blockWrapper.finishBlock] is dependent on
+	// [blockWrapper.postProcess]. The return of [Processor.FinishBlock] is
+	// therefore a guarantee of the end of the lifespans of all of these
+	// goroutines.
+	var (
+		shared  [][]*job
+		weights []uint
+	)
+	for i, s := range p.schedules {
+		if !s.dedicated() {
+			shared = append(shared, queues[i])
+			weights = append(weights, s.weight)
+			continue
 		}
-	}()
+		p.dispatchTo(s.prefetch, s.process, [][]*job{queues[i]}, []uint{s.weight})
+	}
+	p.dispatchTo(p.prefetch, p.process, shared, weights)
+	for _, h := range run.handlers {
+		go h.postProcess()
+	}
+	return nil
+}
+
+// FinishBlock propagates its arguments to every [Handler] and releases the
+// block's state. A nil error guarantees that all dispatched work from the
+// respective call to [Processor.StartBlockCtx] has been completed. Blocks MAY
+// be finished in any order, but calls to [Handler.AfterBlock] are sequential
+// and in the order of calls to FinishBlock.
+//
+// If the block's context was done before all jobs completed, FinishBlock
+// returns an error wrapping [ErrJobAborted] and [context.Cause], and
+// [Handler.AfterBlock] is not called. See [ErrStalled] re jobs that are still
+// running.
+func (p *Processor) FinishBlock(sdb vm.StateDB, b *types.Block, rs types.Receipts) error {
+	run, ok := p.removeBlockRun(b.Hash())
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrBlockNotStarted, b.Hash())
+	}
+
+	ready := make(chan struct{})
 	go func() {
-		for _, j := range jobs {
-			p.process <- (*process)(j)
+		defer close(ready)
+		for _, h := range run.handlers {
+			h.ready()
 		}
 	}()
-	for _, h := range p.handlers {
-		go h.postProcess()
+
+	select {
+	case <-ready:
+	case <-run.ctx.Done():
+		select {
+		case <-ready:
+		default:
+			// Cleanup requires all jobs to have returned.
+			p.stalled.Add(1)
+			go func() {
+				defer p.stalled.Add(-1)
+				<-ready
+				p.cleanup(run, nil, b, nil, false)
+			}()
+			return fmt.Errorf("%w: %w: %w", ErrJobAborted, ErrStalled, context.Cause(run.ctx))
+		}
 	}
+
+	var aborted int64
+	for _, h := range run.handlers {
+		aborted += h.abortedJobs()
+	}
+	if aborted > 0 {
+		p.cleanup(run, sdb, b, rs, false)
+		return fmt.Errorf("%w: %d job(s): %w", ErrJobAborted, aborted, context.Cause(run.ctx))
+	}
+	defer p.inAfterBlock(run)()
+	p.cleanup(run, sdb, b, rs, true)
 	return nil
 }
 
-// FinishBlock propagates its arguments to every [Handler] and resets the
-// [Processor] to a state ready for the next block. A return from FinishBlock
-// guarantees that all dispatched work from the respective call to
-// [Processor.StartBlock] has been completed.
-func (p *Processor) FinishBlock(sdb vm.StateDB, b *types.Block, rs types.Receipts) {
+func (p *Processor) cleanup(run *blockRun, sdb vm.StateDB, b *types.Block, rs types.Receipts, afterBlock bool) {
 	// [Handler.FinishBlock] is allowed to write to state, so these MUST NOT be
 	// concurrent.
-	for _, h := range p.handlers {
-		h.finishBlock(sdb, b, rs)
+	for _, h := range run.handlers {
+		h.finishBlock(sdb, b, rs, afterBlock)
+	}
+}
+
+// isStalled reports whether any block stalled and its outstanding jobs are
+// yet to return.
+func (p *Processor) isStalled() bool {
+	return p.stalled.Load() > 0
+}
+
+// blockRun returns the in-progress block with the specified hash.
+func (p *Processor) blockRun(hash common.Hash) (*blockRun, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, r := range p.blocks {
+		if r.hash == hash {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// removeBlockRun is equivalent to [Processor.blockRun] except that it also
+// removes the block from those in progress.
+func (p *Processor) removeBlockRun(hash common.Hash) (*blockRun, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, r := range p.blocks {
+		if r.hash == hash {
+			p.blocks = slices.Delete(p.blocks, i, i+1)
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// soleBlock returns the hash of the only block in progress, returning false if
+// there are none or more than one.
+func (p *Processor) soleBlock() (common.Hash, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.blocks) != 1 {
+		return common.Hash{}, false
+	}
+	return p.blocks[0].hash, true
+}
+
+// executingBlockRun returns the in-progress block that matches the number and,
+// if non-nil, the header of a block being executed. The header is not required
+// to be identical to that passed to [Processor.StartBlockCtx] as some fields
+// (e.g. gas used) are only known after execution, so blocks are matched by
+// number and parent hash, with the header hash only used to disambiguate
+// siblings. It returns false if there is no unique match.
+func (p *Processor) executingBlockRun(num *big.Int, hdr *types.Header) (*blockRun, bool) {
+	if num == nil {
+		return nil, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var matches []*blockRun
+	for _, r := range p.blocks {
+		if r.header.Number.Cmp(num) != 0 {
+			continue
+		}
+		if hdr != nil && r.header.ParentHash != hdr.ParentHash {
+			continue
+		}
+		matches = append(matches, r)
+	}
+	if len(matches) > 1 && hdr != nil {
+		h := hdr.Hash()
+		matches = slices.DeleteFunc(matches, func(r *blockRun) bool {
+			return r.hash != h
+		})
 	}
-	for tx := range p.txGas {
-		delete(p.txGas, tx)
+	if len(matches) != 1 {
+		return nil, false
 	}
+	return matches[0], true
 }
 
-func (p *Processor) shouldProcess(tx IndexedTx, rules params.Rules) (process []bool, retErr error) {
+func (p *Processor) shouldProcess(run *blockRun, tx IndexedTx, rules params.Rules) (process []bool, retErr error) {
 	// An explicit 0 is necessary to avoid [Processor.PreprocessingGasCharge]
 	// returning [ErrTxUnknown].
-	p.txGas[tx.Hash()] = 0
+	run.txGas[tx.Hash()] = 0
 
 	process = make([]bool, len(p.handlers))
 	var totalCost uint64
-	for i, h := range p.handlers {
+	for i, h := range run.handlers {
+		if f := p.schedules[i].filter; f != nil && !f(tx) {
+			continue
+		}
 		do, cost := h.shouldProcess(tx)
 		if !do {
 			continue
@@ -277,7 +485,7 @@ func (p *Processor) shouldProcess(tx IndexedTx, rules params.Rules) (process []b
 
 	defer func() {
 		if retErr == nil {
-			p.txGas[tx.Hash()] = totalCost
+			run.txGas[tx.Hash()] = totalCost
 		}
 	}()
 
@@ -314,14 +522,25 @@ func intrinsicGas(data []byte, access types.AccessList, txTo *common.Address, ru
 }
 
 // ErrTxUnknown is returned by [Processor.PreprocessingGasCharge] if it is
-// called with a transaction hash that wasn't in the last block passed to
-// [Processor.StartBlock].
+// called with a transaction hash that isn't in the block being executed, or if
+// said block can't be uniquely identified among those in progress.
 var ErrTxUnknown = errors.New("transaction unknown by parallel preprocessor")
 
 // PreprocessingGasCharge implements the [vm.Preprocessor] interface and MUST be
-// registered via [vm.RegisterHooks] to ensure proper gas accounting.
-func (p *Processor) PreprocessingGasCharge(tx common.Hash) (uint64, error) {
-	g, ok := p.txGas[tx]
+// registered via [vm.RegisterHooks] to ensure proper gas accounting; see
+// [vm.NewPreprocessorHooks] for registering multiple Processors.
+//
+// The transaction is looked up in the in-progress block that matches the
+// [vm.BlockContext], allowing blocks to overlap; i.e. the block being executed
+// need not be the earliest started.
+func (p *Processor) PreprocessingGasCharge(block vm.BlockContext, tx common.Hash) (uint64, error) {
+	var (
+		g  uint64
+		ok bool
+	)
+	if run, inProgress := p.executingBlockRun(block.BlockNumber, block.Header); inProgress {
+		g, ok = run.txGas[tx]
+	}
 	if !ok {
 		return 0, fmt.Errorf("%w: %v", ErrTxUnknown, tx)
 	}
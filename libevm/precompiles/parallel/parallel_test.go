@@ -17,13 +17,16 @@
 package parallel
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"math"
 	"math/big"
 	"math/rand/v2"
 	"slices"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -33,12 +36,14 @@ import (
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
 	"github.com/ava-labs/libevm/core/types"
 	"github.com/ava-labs/libevm/core/vm"
 	"github.com/ava-labs/libevm/crypto"
 	"github.com/ava-labs/libevm/libevm"
 	"github.com/ava-labs/libevm/libevm/ethtest"
 	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/metrics"
 	"github.com/ava-labs/libevm/params"
 	"github.com/ava-labs/libevm/trie"
 )
@@ -269,7 +274,7 @@ func TestProcessor(t *testing.T) {
 			}
 
 			extra := []byte("extra")
-			block := types.NewBlock(&types.Header{Extra: extra}, txs, nil, nil, trie.NewStackTrie(nil))
+			block := ethtest.NewBlock(&types.Header{Extra: extra}, txs)
 			require.NoError(t, p.StartBlock(sdb, rules, block), "StartBlock()")
 
 			var wantPerTx []TxResult[recorded]
@@ -306,7 +311,7 @@ func TestProcessor(t *testing.T) {
 				}
 			}
 
-			p.FinishBlock(sdb, block, nil)
+			require.NoError(t, p.FinishBlock(sdb, block, nil), "FinishBlock()")
 			tests := []struct {
 				name string
 				got  []TxResult[recorded]
@@ -328,6 +333,7 @@ func TestProcessor(t *testing.T) {
 				t.Run(tt.name, func(t *testing.T) {
 					opts := cmp.Options{
 						tt.opt,
+						cmpopts.IgnoreUnexported(IndexedTx{}),
 						cmp.Comparer(func(a, b *types.Transaction) bool {
 							return a.Hash() == b.Hash()
 						}),
@@ -350,8 +356,8 @@ type vmHooks struct {
 	vm.NOOPHooks
 }
 
-func (h *vmHooks) PreprocessingGasCharge(tx common.Hash) (uint64, error) {
-	return h.Preprocessor.PreprocessingGasCharge(tx)
+func (h *vmHooks) PreprocessingGasCharge(block vm.BlockContext, tx common.Hash) (uint64, error) {
+	return h.Preprocessor.PreprocessingGasCharge(block, tx)
 }
 
 func TestIntegration(t *testing.T) {
@@ -400,6 +406,9 @@ func TestIntegration(t *testing.T) {
 		Number:  big.NewInt(0),
 		BaseFee: big.NewInt(0),
 	}
+	// Required by [Processor.PreprocessingGasCharge] to identify the block.
+	evm.Context.BlockNumber = header.Number
+	evm.Context.Header = header
 	config := evm.ChainConfig()
 	rules := config.Rules(header.Number, true, header.Time)
 	signer := types.MakeSigner(config, header.Number, header.Time)
@@ -453,7 +462,7 @@ func TestIntegration(t *testing.T) {
 		wantReceipts = append(wantReceipts, wantR)
 	}
 
-	block := types.NewBlock(header, txs, nil, nil, trie.NewStackTrie(nil))
+	block := ethtest.NewBlock(header, txs)
 	require.NoError(t, sut.StartBlock(state, rules, block), "StartBlock()")
 
 	pool := core.GasPool(math.MaxUint64)
@@ -496,7 +505,7 @@ func TestIntegration(t *testing.T) {
 		require.NoError(t, err, "ApplyTransaction([%d])", i)
 		receipts = append(receipts, receipt)
 	}
-	sut.FinishBlock(state, block, receipts)
+	require.NoError(t, sut.FinishBlock(state, block, receipts), "FinishBlock()")
 
 	if diff := cmp.Diff(wantReceipts, handler.gotReceipts, ignore); diff != "" {
 		t.Errorf("%T diff (-want +got):\n%s", receipts, diff)
@@ -565,7 +574,7 @@ func TestTotalCost(t *testing.T) {
 			require.NoError(t, p.StartBlock(sdb, rules, b), "StartBlock()")
 			t.Cleanup(func() { p.FinishBlock(sdb, b, nil) })
 
-			got, err := p.PreprocessingGasCharge(tx.Hash())
+			got, err := p.PreprocessingGasCharge(vm.BlockContext{BlockNumber: b.Number()}, tx.Hash())
 			if err != nil || got != tt.want {
 				t.Errorf("PreprocessingGasCharge() got (%d, %v); want (%d, nil)", got, err, tt.want)
 			}
@@ -573,4 +582,498 @@ func TestTotalCost(t *testing.T) {
 	}
 }
 
+type blocking struct {
+	expensive
+	release <-chan struct{}
+}
+
+func (b blocking) Process(libevm.StateReader, IndexedTx, int, int) int {
+	<-b.release
+	return 0
+}
+
+func TestDedicatedWorkers(t *testing.T) {
+	txs := ethtest.NewTxGenerator(t, 0, params.MergedTestChainConfig).Txs(4)
+	b := ethtest.NewBlock(&types.Header{Number: big.NewInt(0)}, txs)
+	rules := params.MergedTestChainConfig.Rules(big.NewInt(0), true, 0)
+	_, _, sdb := ethtest.NewEmptyStateDB(t)
+
+	// With a single shared processor, the fast Handler would be starved by the
+	// blocking one if they weren't isolated from each other.
+	p := New(1, 1)
+	t.Cleanup(p.Close)
+	release := make(chan struct{})
+	AddHandler(p, blocking{release: release}, WithDedicatedWorkers(1))
+	fast := AddHandler(p, expensive{})
+
+	require.NoError(t, p.StartBlock(sdb, rules, b), "StartBlock()")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range txs {
+			fast(i)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Error("Handler with shared workers starved by Handler with dedicated workers")
+	}
+	close(release)
+	<-done
+	require.NoError(t, p.FinishBlock(sdb, b, nil), "FinishBlock()")
+}
+
+func TestDispatchPriority(t *testing.T) {
+	const (
+		lenA, lenB       = 30, 30
+		weightA, weightB = 3, 1
+	)
+	queue := func(id, n int) []*job {
+		var q []*job
+		for i := range n {
+			q = append(q, &job{tx: IndexedTx{Index: 1000*id + i}})
+		}
+		return q
+	}
+
+	var got []int
+	dispatch(
+		[][]*job{queue(0, lenA), queue(1, lenB), nil},
+		[]uint{weightA, weightB, 1},
+		func(j *job) { got = append(got, j.tx.Index) },
+	)
+	require.Len(t, got, lenA+lenB, "number of dispatched jobs")
+
+	var fromA, fromB []int
+	for i, idx := range got {
+		if idx < 1000 {
+			fromA = append(fromA, idx)
+		} else {
+			fromB = append(fromB, idx-1000)
+		}
+		// While both queues are non-empty, dispatch is proportional to weight.
+		if n := i + 1; len(fromA) < lenA && n%(weightA+weightB) == 0 {
+			require.Equalf(t, n*weightA/(weightA+weightB), len(fromA), "jobs from higher-priority queue after %d dispatched", n)
+		}
+	}
+	require.True(t, slices.IsSorted(fromA), "order within queue preserved")
+	require.True(t, slices.IsSorted(fromB), "order within queue preserved")
+}
+
 // TODO(arr4n) unit test for [AddPrecompile] unhappy paths.
+
+func TestBlockContext(t *testing.T) {
+	txs := ethtest.NewTxGenerator(t, 0, params.MergedTestChainConfig).Txs(4)
+	b := ethtest.NewBlock(&types.Header{Number: big.NewInt(0)}, txs)
+	rules := params.MergedTestChainConfig.Rules(big.NewInt(0), true, 0)
+	_, _, sdb := ethtest.NewEmptyStateDB(t)
+
+	t.Run("cancelled", func(t *testing.T) {
+		p := New(2, 2)
+		t.Cleanup(p.Close)
+		results := AddHandler(p, expensive{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.NoError(t, p.StartBlockCtx(ctx, sdb, rules, b), "StartBlockCtx()")
+		for i := range txs {
+			_, ok := results(i)
+			require.Falsef(t, ok, "result %d of aborted job reported as processed", i)
+		}
+		err := p.FinishBlock(sdb, b, nil)
+		require.ErrorIs(t, err, ErrJobAborted, "FinishBlock()")
+		require.ErrorIs(t, err, context.Canceled, "FinishBlock()")
+
+		require.Eventually(t, func() bool { return !p.isStalled() }, 10*time.Second, time.Millisecond)
+		require.NoError(t, p.StartBlock(sdb, rules, b), "StartBlock() after aborted block")
+		require.NoError(t, p.FinishBlock(sdb, b, nil), "FinishBlock() after aborted block")
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		p := New(1, 1)
+		t.Cleanup(p.Close)
+		release := make(chan struct{})
+		AddHandler(p, blocking{release: release})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		require.NoError(t, p.StartBlockCtx(ctx, sdb, rules, b), "StartBlockCtx()")
+
+		err := p.FinishBlock(sdb, b, nil)
+		require.ErrorIs(t, err, ErrStalled, "FinishBlock() with hung job")
+		require.ErrorIs(t, err, context.DeadlineExceeded, "FinishBlock() with hung job")
+		require.True(t, p.isStalled(), "isStalled() before hung job returns")
+		require.NoError(t, p.StartBlock(sdb, rules, b), "StartBlock() before hung job returns")
+
+		close(release)
+		require.NoError(t, p.FinishBlock(sdb, b, nil), "FinishBlock() after hung job returns")
+		require.Eventually(t, func() bool { return !p.isStalled() }, 10*time.Second, time.Millisecond)
+	})
+}
+
+func TestCloseWithStalledHandler(t *testing.T) {
+	txs := ethtest.NewTxGenerator(t, 0, params.MergedTestChainConfig).Txs(4)
+	b := ethtest.NewBlock(&types.Header{Number: big.NewInt(0)}, txs)
+	rules := params.MergedTestChainConfig.Rules(big.NewInt(0), true, 0)
+	_, _, sdb := ethtest.NewEmptyStateDB(t)
+
+	// With a single worker of each kind, the dispatchers of both the shared
+	// and dedicated workers are blocked sending the second job while the
+	// first is being processed.
+	p := New(1, 1)
+	release := make(chan struct{})
+	AddHandler(p, blocking{release: release})
+	AddHandler(p, blocking{release: release}, WithDedicatedWorkers(1))
+	require.NoError(t, p.StartBlock(sdb, rules, b), "StartBlock()")
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		p.Close()
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close() returned while job in progress")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Close() didn't return after stalled job")
+	}
+}
+
+// blockNumbers returns results derived from the block number and
+// transaction index.
+type blockNumbers struct {
+	expensive
+}
+
+func (blockNumbers) BeforeBlock(_ libevm.StateReader, h *types.Header) int {
+	return int(h.Number.Int64())
+}
+
+func (blockNumbers) ShouldProcess(_ IndexedTx, num int) (bool, uint64) {
+	return true, uint64(num) //nolint:gosec // Test block numbers are small and positive
+}
+
+func (blockNumbers) Process(_ libevm.StateReader, tx IndexedTx, num, _ int) int {
+	return 1000*num + tx.Index
+}
+
+func TestPipelinedBlocks(t *testing.T) {
+	// Contract calls have gas in excess of the intrinsic amount, required for
+	// [blockNumbers.ShouldProcess] charges.
+	txs := ethtest.NewTxGenerator(
+		t, 0, params.MergedTestChainConfig,
+		ethtest.WithShapes(ethtest.ShapeContractCall),
+		ethtest.WithCallTargets(common.Address{'t', 'o'}),
+	).Txs(3)
+	newBlock := func(num int64, extra string) *types.Block {
+		return ethtest.NewBlock(&types.Header{Number: big.NewInt(num), Extra: []byte(extra)}, txs)
+	}
+	b1, b2 := newBlock(1, ""), newBlock(2, "")
+	rules := params.MergedTestChainConfig.Rules(big.NewInt(0), true, 0)
+	_, _, sdb := ethtest.NewEmptyStateDB(t)
+
+	p := New(2, 2)
+	t.Cleanup(p.Close)
+	byBlock := AddHandlerByBlock(p, blockNumbers{})
+	sole := AddHandler(p, blockNumbers{})
+
+	requireResults := func(t *testing.T, fn func(int) (TxResult[int], bool), num int) {
+		t.Helper()
+		for i := range txs {
+			got, ok := fn(i)
+			require.Truef(t, ok, "result %d processed", i)
+			require.Equalf(t, 1000*num+i, got.Result, "result %d", i)
+		}
+	}
+	fromBlock := func(b *types.Block) func(int) (TxResult[int], bool) {
+		return func(i int) (TxResult[int], bool) {
+			return byBlock(b.Hash(), i)
+		}
+	}
+	// Each of the two Handlers charges the block number, but the executed
+	// header is altered to demonstrate that it need not be identical to the
+	// one that was started.
+	requireCharge := func(t *testing.T, b *types.Block, withHeader bool) {
+		t.Helper()
+		bCtx := vm.BlockContext{BlockNumber: b.Number()}
+		if withHeader {
+			hdr := b.Header()
+			hdr.GasUsed++
+			bCtx.Header = hdr
+		}
+		got, err := p.PreprocessingGasCharge(bCtx, txs[0].Hash())
+		require.NoErrorf(t, err, "PreprocessingGasCharge(block %d)", b.NumberU64())
+		require.Equalf(t, 2*b.NumberU64(), got, "PreprocessingGasCharge(block %d)", b.NumberU64())
+	}
+
+	require.NoError(t, p.StartBlock(sdb, rules, b1), "StartBlock(1)")
+	require.NoError(t, p.StartBlock(sdb, rules, b2), "StartBlock(2) before FinishBlock(1)")
+	require.ErrorIs(t, p.StartBlock(sdb, rules, b1), ErrBlockInProgress, "StartBlock(1) while in progress")
+
+	requireResults(t, fromBlock(b1), 1)
+	requireResults(t, fromBlock(b2), 2)
+	_, ok := sole(0)
+	require.False(t, ok, "result without block identification while blocks overlap")
+	for _, withHeader := range []bool{false, true} {
+		requireCharge(t, b2, withHeader)
+		requireCharge(t, b1, withHeader)
+	}
+
+	t.Run("siblings", func(t *testing.T) {
+		sibling := newBlock(2, "sibling")
+		require.NoError(t, p.StartBlock(sdb, rules, sibling), "StartBlock(sibling of 2)")
+		defer func() {
+			require.NoError(t, p.FinishBlock(sdb, sibling, nil), "FinishBlock(sibling of 2)")
+		}()
+
+		_, err := p.PreprocessingGasCharge(vm.BlockContext{BlockNumber: b2.Number()}, txs[0].Hash())
+		require.ErrorIs(t, err, ErrTxUnknown, "PreprocessingGasCharge() of ambiguous block without header")
+
+		for _, b := range []*types.Block{b2, sibling} {
+			got, err := p.PreprocessingGasCharge(vm.BlockContext{BlockNumber: b.Number(), Header: b.Header()}, txs[0].Hash())
+			require.NoError(t, err, "PreprocessingGasCharge() with header")
+			require.Equal(t, 2*b.NumberU64(), got, "PreprocessingGasCharge() with header")
+		}
+	})
+
+	require.NoError(t, p.FinishBlock(sdb, b1, nil), "FinishBlock(1)")
+	requireResults(t, sole, 2)
+	_, ok = byBlock(b1.Hash(), 0)
+	require.False(t, ok, "result from finished block")
+	require.ErrorIs(t, p.FinishBlock(sdb, b1, nil), ErrBlockNotStarted, "FinishBlock(1) again")
+	_, err := p.PreprocessingGasCharge(vm.BlockContext{BlockNumber: b1.Number()}, txs[0].Hash())
+	require.ErrorIs(t, err, ErrTxUnknown, "PreprocessingGasCharge() of finished block")
+
+	require.NoError(t, p.FinishBlock(sdb, b2, nil), "FinishBlock(2)")
+	_, ok = sole(0)
+	require.False(t, ok, "result with no block in progress")
+}
+
+type recordShouldProcess struct {
+	expensive
+	got []int
+}
+
+func (r *recordShouldProcess) ShouldProcess(tx IndexedTx, _ int) (bool, uint64) {
+	r.got = append(r.got, tx.Index)
+	return true, 0
+}
+
+func TestTxFilter(t *testing.T) {
+	target := common.Address{'t', 'a', 'r', 'g', 'e', 't'}
+	other := common.Address{'o', 't', 'h', 'e', 'r'}
+
+	txs := types.Transactions{
+		types.NewTx(&types.LegacyTx{
+			Nonce: 0,
+			To:    &target,
+			Gas:   params.TxGas,
+		}),
+		types.NewTx(&types.LegacyTx{
+			Nonce: 1,
+			To:    &other,
+			Gas:   params.TxGas,
+		}),
+		types.NewTx(&types.AccessListTx{
+			Nonce:      2,
+			To:         &other,
+			Gas:        1e6,
+			AccessList: types.AccessList{{Address: other}, {Address: target}},
+		}),
+		types.NewTx(&types.AccessListTx{
+			Nonce:      3,
+			Gas:        1e6,
+			AccessList: types.AccessList{{Address: other}},
+		}),
+	}
+
+	tests := []struct {
+		name   string
+		filter TxFilter
+		want   []int
+	}{
+		{
+			name: "none",
+			want: []int{0, 1, 2, 3},
+		},
+		{
+			name:   "to",
+			filter: MatchTo(target),
+			want:   []int{0},
+		},
+		{
+			name:   "access_list",
+			filter: MatchAccessList(target),
+			want:   []int{2},
+		},
+		{
+			name:   "to_or_access_list",
+			filter: MatchToOrAccessList(target),
+			want:   []int{0, 2},
+		},
+		{
+			name:   "multiple_addresses",
+			filter: MatchToOrAccessList(target, other),
+			want:   []int{0, 1, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for i, tx := range txs {
+				if tt.filter == nil || tt.filter(IndexedTx{Index: i, Transaction: tx}) {
+					got = append(got, i)
+				}
+			}
+			require.Equal(t, tt.want, got, "TxFilter matches")
+
+			b := ethtest.NewBlock(&types.Header{Number: big.NewInt(0)}, txs)
+			rules := params.MergedTestChainConfig.Rules(big.NewInt(0), true, 0)
+			_, _, sdb := ethtest.NewEmptyStateDB(t)
+
+			p := New(1, 1)
+			t.Cleanup(p.Close)
+			h := &recordShouldProcess{}
+			results := AddHandler(p, h, WithTxFilter(tt.filter))
+
+			require.NoError(t, p.StartBlock(sdb, rules, b), "StartBlock()")
+			var processed []int
+			for i := range txs {
+				if _, ok := results(i); ok {
+					processed = append(processed, i)
+				}
+			}
+			require.NoError(t, p.FinishBlock(sdb, b, nil), "FinishBlock()")
+
+			require.Equal(t, tt.want, h.got, "Handler.ShouldProcess() calls")
+			require.Equal(t, tt.want, processed, "processed transactions")
+		})
+	}
+}
+
+func TestHandlerMetrics(t *testing.T) {
+	const numTxs = 5
+	txs := ethtest.NewTxGenerator(t, 0, params.MergedTestChainConfig).Txs(numTxs)
+	b := ethtest.NewBlock(&types.Header{Number: big.NewInt(0)}, txs)
+	rules := params.MergedTestChainConfig.Rules(big.NewInt(0), true, 0)
+	_, _, sdb := ethtest.NewEmptyStateDB(t)
+
+	const name = "test_handler_metrics"
+	p := New(2, 2)
+	t.Cleanup(p.Close)
+	AddHandler(p, expensive{}, WithName(name))
+
+	require.NoError(t, p.StartBlock(sdb, rules, b), "StartBlock()")
+	require.NoError(t, p.FinishBlock(sdb, b, nil), "FinishBlock()")
+
+	reg := metrics.DefaultRegistry
+	for _, m := range []string{"prefetch", "process"} {
+		timer, ok := reg.Get("parallel/handler/" + name + "/" + m).(metrics.Timer)
+		require.Truef(t, ok, "%q timer registered", m)
+		require.Equalf(t, int64(numTxs), timer.Snapshot().Count(), "%q timer count", m)
+	}
+	for _, m := range []string{"jobs/queued", "jobs/outstanding"} {
+		gauge, ok := reg.Get("parallel/handler/" + name + "/" + m).(metrics.Gauge)
+		require.Truef(t, ok, "%q gauge registered", m)
+		require.Zerof(t, gauge.Snapshot().Value(), "%q gauge after FinishBlock()", m)
+	}
+}
+
+type cachedResults struct {
+	expensive
+	processed atomic.Int64
+}
+
+func (c *cachedResults) Process(_ libevm.StateReader, tx IndexedTx, _, _ int) int {
+	c.processed.Add(1)
+	return 2*tx.Index + 1
+}
+
+func (*cachedResults) EncodeResult(r int) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, uint64(r)), nil //nolint:gosec // Test values are non-negative
+}
+
+func (*cachedResults) DecodeResult(b []byte) (int, error) {
+	if len(b) != 8 {
+		return 0, fmt.Errorf("invalid length %d", len(b))
+	}
+	return int(binary.BigEndian.Uint64(b)), nil //nolint:gosec // Test values are small
+}
+
+func TestResultStore(t *testing.T) {
+	const numTxs = 5
+	txs := ethtest.NewTxGenerator(t, 0, params.MergedTestChainConfig).Txs(numTxs)
+	b := ethtest.NewBlock(&types.Header{Number: big.NewInt(0)}, txs)
+	rules := params.MergedTestChainConfig.Rules(big.NewInt(0), true, 0)
+	_, _, sdb := ethtest.NewEmptyStateDB(t)
+
+	store := NewDatabaseResultStore(rawdb.NewMemoryDatabase())
+	// Each iteration simulates a restart, with a new [Processor] and
+	// [Handler], followed by re-execution of the same block.
+	for run, wantProcessed := range []int64{numTxs, 0} {
+		p := New(2, 2)
+		h := &cachedResults{}
+		results := AddHandler(p, h, WithName("cached"), WithResultStore(store))
+
+		require.NoErrorf(t, p.StartBlock(sdb, rules, b), "run %d: StartBlock()", run)
+		for i := range numTxs {
+			got, ok := results(i)
+			require.Truef(t, ok, "run %d: result %d processed", run, i)
+			require.Equalf(t, 2*i+1, got.Result, "run %d: result %d", run, i)
+		}
+		require.NoErrorf(t, p.FinishBlock(sdb, b, nil), "run %d: FinishBlock()", run)
+		p.Close()
+
+		require.Equalf(t, wantProcessed, h.processed.Load(), "run %d: calls to Handler.Process()", run)
+	}
+
+	t.Run("without_codec", func(t *testing.T) {
+		p := New(1, 1)
+		t.Cleanup(p.Close)
+		require.Panics(t, func() {
+			AddHandler(p, expensive{}, WithResultStore(store))
+		}, "AddHandler(WithResultStore()) with Handler not implementing ResultCodec")
+	})
+}
+
+func TestResultStoreRetention(t *testing.T) {
+	const retention = 2
+	store := NewDatabaseResultStore(rawdb.NewMemoryDatabase(), WithResultRetention(retention))
+
+	hash := func(n uint64) common.Hash {
+		return common.BigToHash(new(big.Int).SetUint64(n + 1))
+	}
+	write := func(t *testing.T, n uint64) {
+		t.Helper()
+		for _, h := range []string{"a", "b"} {
+			require.NoErrorf(t, store.WriteResult(n, hash(n), 0, h, []byte{byte(n)}), "WriteResult(%d, ..., %q)", n, h)
+		}
+	}
+	stored := func(n uint64) bool {
+		_, ok := store.ReadResult(n, hash(n), 0, "a")
+		return ok
+	}
+
+	const highest = 10
+	for n := uint64(0); n <= highest; n++ {
+		write(t, n)
+	}
+	for n := uint64(0); n <= highest; n++ {
+		require.Equalf(t, n >= highest-retention, stored(n), "ReadResult(%d) ok after writing up to block %d", n, highest)
+	}
+
+	// Writing a lower block, e.g. during a reorg, MUST NOT evict newer ones.
+	write(t, highest-1)
+	for n := uint64(highest - retention); n <= highest; n++ {
+		require.Truef(t, stored(n), "ReadResult(%d) ok after rewriting block %d", n, highest-1)
+	}
+}
@@ -16,7 +16,10 @@
 
 package parallel
 
-import "github.com/ava-labs/libevm/core/vm"
+import (
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+)
 
 // PrecompileResult is the interface required for a [Handler] to be converted
 // into a [vm.PrecompiledStatefulContract].
@@ -30,13 +33,22 @@ type PrecompileResult interface {
 
 // AddAsPrecompile is equivalent to [AddHandler] except that the returned
 // function is a [vm.PrecompiledStatefulContract] instead of a raw result
-// fetcher. If the function returned by [AddHandler] returns `false` then the
-// precompile returns [vm.ErrExecutionReverted].
+// fetcher. Results are fetched from the in-progress block being executed, as
+// identified by the [vm.PrecompileEnvironment], so blocks MAY overlap. If no
+// result is available then the precompile returns [vm.ErrExecutionReverted].
 func AddAsPrecompile[CD, D any, R PrecompileResult, A any](p *Processor, h Handler[CD, D, R, A]) vm.PrecompiledStatefulContract {
-	results := AddHandler(p, h)
+	results := AddHandlerByBlock(p, h)
 
 	return func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
-		res, ok := results(env.ReadOnlyState().TxIndex())
+		var hdr *types.Header
+		if header, err := env.BlockHeader(); err == nil {
+			hdr = &header
+		}
+		run, ok := p.executingBlockRun(env.BlockNumber(), hdr)
+		var res TxResult[R]
+		if ok {
+			res, ok = results(run.hash, env.ReadOnlyState().TxIndex())
+		}
 		if !ok {
 			// TODO(arr4n) add revert data to match a Solidity-style error
 			return nil, vm.ErrExecutionReverted
@@ -0,0 +1,183 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package parallel
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/options"
+)
+
+// A HandlerOption configures how a [Handler] passed to [AddHandler] is
+// scheduled.
+type HandlerOption = options.Option[handlerConfig]
+
+type handlerConfig struct {
+	dedicatedWorkers int
+	priority         uint
+	filter           TxFilter
+	name             string
+	store            ResultStore
+	uncommitted      bool
+}
+
+// WithDedicatedWorkers returns a [HandlerOption] that allocates `n` prefetching
+// and `n` processing workers for the exclusive use of the [Handler], instead of
+// it sharing the workers allocated by [New] with all other Handlers. A
+// non-positive `n` is equivalent to not using the option.
+func WithDedicatedWorkers(n int) HandlerOption {
+	return options.Func[handlerConfig](func(c *handlerConfig) {
+		c.dedicatedWorkers = n
+	})
+}
+
+// WithPriority returns a [HandlerOption] that sets the weight of the [Handler]
+// when jobs are dispatched to shared workers. Whenever multiple Handlers have
+// jobs waiting, each receives a share of dispatched jobs proportional to its
+// priority. The default priority is 1 and a priority of 0 is treated as 1.
+// Priority has no effect on Handlers with dedicated workers.
+func WithPriority(p uint) HandlerOption {
+	return options.Func[handlerConfig](func(c *handlerConfig) {
+		c.priority = p
+	})
+}
+
+// A schedule is the [Processor]'s dispatch configuration for a single
+// [handler].
+type schedule struct {
+	weight uint
+	filter TxFilter // nil matches all transactions
+	// Nil channels denote use of the shared workers.
+	prefetch chan *prefetch
+	process  chan *process
+}
+
+func (p *Processor) newSchedule(cfg *handlerConfig) *schedule {
+	s := &schedule{
+		weight: max(cfg.priority, 1),
+		filter: cfg.filter,
+	}
+
+	n := cfg.dedicatedWorkers
+	if n <= 0 {
+		return s
+	}
+	s.prefetch = make(chan *prefetch)
+	s.process = make(chan *process)
+	p.startWorkers(n, n, s.prefetch, s.process)
+	return s
+}
+
+func (s *schedule) dedicated() bool {
+	return s.prefetch != nil
+}
+
+// startWorkers starts the specified number of workers, reading from the
+// respective channels.
+func (p *Processor) startWorkers(prefetchers, processors int, pre chan *prefetch, proc chan *process) {
+	p.workers.Add(prefetchers + processors) // for shutdown via [Processor.Close]
+	for range prefetchers {
+		go worker(p, pre, func(sdb libevm.StateReader, job *prefetch) {
+			job.handler.prefetch(sdb, job)
+		})
+	}
+	for range processors {
+		go worker(p, proc, func(sdb libevm.StateReader, job *process) {
+			job.handler.process(sdb, job)
+		})
+	}
+}
+
+// dispatch calls `send` with all jobs, in order within each queue. Queues are
+// interleaved by smooth weighted round-robin such that, for as long as
+// multiple queues are non-empty, each receives a share of sends proportional
+// to its weight.
+func dispatch(queues [][]*job, weights []uint, send func(*job)) {
+	queues = append([][]*job(nil), queues...) // don't modify the caller's slice headers
+	current := make([]int64, len(queues))
+	for {
+		var (
+			total int64
+			next  = -1
+		)
+		for i, q := range queues {
+			if len(q) == 0 {
+				continue
+			}
+			w := int64(weights[i]) //nolint:gosec // Overflow would require an unreasonable priority
+			current[i] += w
+			total += w
+			if next == -1 || current[i] > current[next] {
+				next = i
+			}
+		}
+		if next == -1 {
+			return
+		}
+		current[next] -= total
+		send(queues[next][0])
+		queues[next] = queues[next][1:]
+	}
+}
+
+// dispatchTo starts goroutines that [dispatch] the queues to the prefetching
+// and processing channels. Once [Processor.Close] is called, jobs that are yet
+// to be sent are instead aborted, without being sent to a worker, so the
+// block's goroutines can return.
+func (p *Processor) dispatchTo(pre chan<- *prefetch, proc chan<- *process, queues [][]*job, weights []uint) {
+	p.dispatchers.Add(2) // for shutdown via [Processor.Close]
+	go func() {
+		defer p.dispatchers.Done()
+		dispatch(queues, weights, func(j *job) {
+			select {
+			case pre <- (*prefetch)(j):
+			case <-p.quit:
+				j.handler.prefetch(nil, (*prefetch)(abortedJob(j)))
+			}
+		})
+	}()
+	go func() {
+		defer p.dispatchers.Done()
+		dispatch(queues, weights, func(j *job) {
+			select {
+			case proc <- (*process)(j):
+			case <-p.quit:
+				j.handler.process(nil, (*process)(abortedJob(j)))
+			}
+		})
+	}()
+}
+
+// errClosed is the [context.Cause] of jobs aborted by [Processor.Close].
+var errClosed = errors.New("parallel.Processor closed")
+
+// closedCtx is a [context.Context] that is already done due to [errClosed].
+var closedCtx = func() context.Context {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errClosed)
+	return ctx
+}()
+
+// abortedJob returns a copy of `j` that Handlers treat as aborted because its
+// context is done.
+func abortedJob(j *job) *job {
+	cp := *j
+	cp.tx.ctx = closedCtx
+	return &cp
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package parallel
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/log"
+)
+
+// A ResultStore persists serialized [Handler] results, allowing them to be
+// reused when a block is re-executed; e.g. after a restart. Results are keyed
+// by block number and hash, transaction index, and the name set with
+// [WithName].
+type ResultStore interface {
+	ReadResult(number uint64, block common.Hash, txIndex int, handler string) (_ []byte, ok bool)
+	WriteResult(number uint64, block common.Hash, txIndex int, handler string, result []byte) error
+}
+
+// A ResultCodec serializes the results of a [Handler]. Handlers registered with
+// [WithResultStore] MUST implement it.
+type ResultCodec[R any] interface {
+	EncodeResult(R) ([]byte, error)
+	DecodeResult([]byte) (R, error)
+}
+
+// WithResultStore returns a [HandlerOption] that caches the [Handler]'s results
+// in the [ResultStore]. Before dispatching a transaction to the Handler, the
+// [Processor] attempts to read its result from the store and, if successful,
+// neither [Handler.Prefetch] nor [Handler.Process] is called. All results
+// returned by Process are written to the store.
+//
+// The Handler MUST implement [ResultCodec] and its Process method MUST be
+// deterministic. It SHOULD also be registered [WithName] as the default name,
+// being derived from registration order, is fragile across code changes. A nil
+// store is equivalent to not using the option.
+func WithResultStore(s ResultStore) HandlerOption {
+	return options.Func[handlerConfig](func(c *handlerConfig) {
+		c.store = s
+	})
+}
+
+// A ResultStoreOption configures the [ResultStore] returned by
+// [NewDatabaseResultStore].
+type ResultStoreOption = options.Option[resultStoreConfig]
+
+type resultStoreConfig struct {
+	retention uint64
+}
+
+// WithResultRetention returns a [ResultStoreOption] that evicts the results of
+// all blocks more than `blocks` below the highest block for which a result has
+// been written. Eviction occurs when a result is first written for a new
+// highest block. A value of zero, the default, disables eviction.
+func WithResultRetention(blocks uint64) ResultStoreOption {
+	return options.Func[resultStoreConfig](func(c *resultStoreConfig) {
+		c.retention = blocks
+	})
+}
+
+// NewDatabaseResultStore returns a [ResultStore] backed by the database.
+func NewDatabaseResultStore(db ethdb.KeyValueStore, opts ...ResultStoreOption) ResultStore {
+	return &dbResultStore{
+		db:     db,
+		config: options.ApplyTo(&resultStoreConfig{}, opts...),
+	}
+}
+
+type dbResultStore struct {
+	db     ethdb.KeyValueStore
+	config *resultStoreConfig
+
+	mu      sync.Mutex
+	highest uint64 // highest block number passed to WriteResult
+}
+
+var resultStorePrefix = []byte("libevm-parallel-result-")
+
+// resultKey returns resultStorePrefix + block number (uint64 big endian) +
+// block hash + tx index (uint64 big endian) + handler name. All but the last
+// component are of fixed length so keys are unique, and they are ordered by
+// block number to allow eviction.
+func resultKey(number uint64, block common.Hash, txIndex int, handler string) []byte {
+	key := make([]byte, 0, len(resultStorePrefix)+8+common.HashLength+8+len(handler))
+	key = append(key, resultStorePrefix...)
+	key = binary.BigEndian.AppendUint64(key, number)
+	key = append(key, block[:]...)
+	key = binary.BigEndian.AppendUint64(key, uint64(txIndex)) //nolint:gosec // Index is non-negative
+	return append(key, handler...)
+}
+
+func (s *dbResultStore) ReadResult(number uint64, block common.Hash, txIndex int, handler string) ([]byte, bool) {
+	val, err := s.db.Get(resultKey(number, block, txIndex, handler))
+	if err != nil {
+		return nil, false
+	}
+	return val, true
+}
+
+func (s *dbResultStore) WriteResult(number uint64, block common.Hash, txIndex int, handler string, result []byte) error {
+	if err := s.db.Put(resultKey(number, block, txIndex, handler), result); err != nil {
+		return err
+	}
+	if r := s.config.retention; r > 0 && s.raiseHighest(number) && number > r {
+		return s.evictBelow(number - r)
+	}
+	return nil
+}
+
+// raiseHighest reports whether `number` is greater than all previous values
+// passed to it, in which case it is recorded as the new highest.
+func (s *dbResultStore) raiseHighest(number uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if number <= s.highest {
+		return false
+	}
+	s.highest = number
+	return true
+}
+
+// evictBelow deletes the results of all blocks numbered below `number`.
+func (s *dbResultStore) evictBelow(number uint64) error {
+	it := s.db.NewIterator(resultStorePrefix, nil)
+	defer it.Release()
+
+	batch := s.db.NewBatch()
+	for it.Next() {
+		key := it.Key()[len(resultStorePrefix):]
+		if len(key) < 8 || binary.BigEndian.Uint64(key) >= number {
+			break
+		}
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// A resultCache couples a [ResultStore] with the [ResultCodec] of a
+// [wrapper]'s Handler.
+type resultCache[R any] struct {
+	store ResultStore
+	codec ResultCodec[R]
+	name  string
+}
+
+func (c *resultCache[R]) read(number uint64, block common.Hash, txIndex int) (R, bool) {
+	var zero R
+	buf, ok := c.store.ReadResult(number, block, txIndex, c.name)
+	if !ok {
+		return zero, false
+	}
+	r, err := c.codec.DecodeResult(buf)
+	if err != nil {
+		log.Warn("Decoding cached parallel result", "handler", c.name, "block", block, "tx", txIndex, "err", err)
+		return zero, false
+	}
+	return r, true
+}
+
+func (c *resultCache[R]) write(number uint64, block common.Hash, txIndex int, r R) {
+	buf, err := c.codec.EncodeResult(r)
+	if err == nil {
+		err = c.store.WriteResult(number, block, txIndex, c.name, buf)
+	}
+	if err != nil {
+		log.Warn("Caching parallel result", "handler", c.name, "block", block, "tx", txIndex, "err", err)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package pseudo provides typed, per-field access to extra payloads registered
+// with libevm (e.g. via [types.RegisterExtras] or [params.RegisterExtras]).
+//
+// The [Field] type in this package uses reflection and is intended for
+// prototyping. Production code SHOULD instead use equivalent helpers emitted
+// by the generator in the `gen` sub-directory, which are type-checked at
+// compile time and avoid reflection overhead.
+//
+// [types.RegisterExtras]: https://pkg.go.dev/github.com/ava-labs/libevm/core/types#RegisterExtras
+// [params.RegisterExtras]: https://pkg.go.dev/github.com/ava-labs/libevm/params#RegisterExtras
+package pseudo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A Field provides typed access to a single field, of type `F`, of an extra
+// payload carried by a container of type `C` (e.g. `*types.Header`).
+type Field[F, C any] struct {
+	get func(C) (reflect.Value, bool)
+	set func(C, func(reflect.Value))
+}
+
+// NewField constructs a [Field] for the struct field with the specified name.
+// The `get` and `set` functions are typically the respective methods of an
+// extras accessor; e.g. `extras.Header.Get` and `extras.Header.Set`, where
+// `extras` is returned by [types.RegisterExtras].
+//
+// The payload type `P` MUST be a struct or a pointer to a struct and the named
+// field MUST be exported and of type `F`.
+//
+// If `P` is a pointer then a nil payload is treated as the zero value of its
+// struct type by [Field.Get], and is replaced by a newly allocated payload by
+// [Field.Set].
+//
+// [types.RegisterExtras]: https://pkg.go.dev/github.com/ava-labs/libevm/core/types#RegisterExtras
+func NewField[F, C, P any](get func(C) P, set func(C, P), name string) (*Field[F, C], error) {
+	payloadT := reflect.TypeFor[P]()
+	structT := payloadT
+	isPtr := payloadT.Kind() == reflect.Pointer
+	if isPtr {
+		structT = payloadT.Elem()
+	}
+	if structT.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("payload type %v is neither a struct nor a pointer to one", payloadT)
+	}
+
+	sf, ok := structT.FieldByName(name)
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("%v has no field %q", structT, name)
+	case !sf.IsExported():
+		return nil, fmt.Errorf("field %v.%s is not exported", structT, name)
+	case sf.Type != reflect.TypeFor[F]():
+		return nil, fmt.Errorf("field %v.%s is of type %v, not %v", structT, name, sf.Type, reflect.TypeFor[F]())
+	}
+	idx := sf.Index
+
+	// structOf returns the addressable struct value carried by `p`, which is
+	// invalid i.f.f. `p` is a nil pointer.
+	structOf := func(p *P) reflect.Value {
+		v := reflect.ValueOf(p).Elem()
+		if !isPtr {
+			return v
+		}
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		return v.Elem()
+	}
+
+	return &Field[F, C]{
+		get: func(c C) (reflect.Value, bool) {
+			p := get(c)
+			s := structOf(&p)
+			if !s.IsValid() {
+				return reflect.Value{}, false
+			}
+			return s.FieldByIndex(idx), true
+		},
+		set: func(c C, fn func(reflect.Value)) {
+			p := get(c)
+			s := structOf(&p)
+			if !s.IsValid() {
+				reflect.ValueOf(&p).Elem().Set(reflect.New(structT))
+				s = structOf(&p)
+			}
+			fn(s.FieldByIndex(idx))
+			set(c, p)
+		},
+	}, nil
+}
+
+// MustNewField is equivalent to [NewField] except that it panics instead of
+// returning an error.
+func MustNewField[F, C, P any](get func(C) P, set func(C, P), name string) *Field[F, C] {
+	f, err := NewField[F](get, set, name)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Get returns the field's value in the payload carried by `c`. The value is
+// not copied so, if `F` is a pointer, slice, or map, modifications will be
+// reflected in the payload.
+func (f *Field[F, C]) Get(c C) F {
+	v, ok := f.get(c)
+	if !ok {
+		var zero F
+		return zero
+	}
+	return v.Interface().(F) //nolint:forcetypeassert // invariant guaranteed by NewField
+}
+
+// Set sets the field's value in the payload carried by `c`.
+func (f *Field[F, C]) Set(c C, val F) {
+	f.set(c, func(v reflect.Value) {
+		v.Set(reflect.ValueOf(&val).Elem())
+	})
+}
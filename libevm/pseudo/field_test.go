@@ -0,0 +1,132 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package pseudo_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm/pseudo"
+)
+
+type headerExtra struct {
+	types.NOOPHeaderHooks
+	FeeConfig *big.Int
+	Tags      []string
+	hidden    bool //nolint:unused // required for test of unexported fields
+}
+
+type accountExtra struct {
+	Flag bool
+}
+
+func TestField(t *testing.T) {
+	types.TestOnlyClearRegisteredExtras()
+	t.Cleanup(types.TestOnlyClearRegisteredExtras)
+	extras := types.RegisterExtras[
+		headerExtra, *headerExtra,
+		types.NOOPBlockBodyHooks, *types.NOOPBlockBodyHooks,
+		accountExtra,
+	]()
+
+	t.Run("pointer_payload", func(t *testing.T) {
+		fee := pseudo.MustNewField[*big.Int](extras.Header.Get, extras.Header.Set, "FeeConfig")
+		tags := pseudo.MustNewField[[]string](extras.Header.Get, extras.Header.Set, "Tags")
+
+		h := new(types.Header)
+		assert.Nil(t, fee.Get(h), "Get() before Set()")
+
+		want := big.NewInt(42)
+		fee.Set(h, want)
+		assert.Equal(t, want, fee.Get(h), "Get() after Set()")
+		assert.Equal(t, want, extras.Header.Get(h).FeeConfig, "accessor Get() after Field.Set()")
+
+		tags.Set(h, []string{"a", "b"})
+		assert.Equal(t, []string{"a", "b"}, tags.Get(h))
+		assert.Equal(t, want, fee.Get(h), "Set() on one field doesn't affect another")
+
+		extras.Header.Set(h, nil)
+		assert.Nil(t, fee.Get(h), "Get() with nil payload")
+		fee.Set(h, want)
+		assert.Equal(t, want, fee.Get(h), "Get() after Set() with nil payload")
+	})
+
+	t.Run("value_payload", func(t *testing.T) {
+		flag := pseudo.MustNewField[bool](extras.StateAccount.Get, extras.StateAccount.Set, "Flag")
+
+		acc := new(types.StateAccount)
+		assert.False(t, flag.Get(acc), "Get() before Set()")
+		flag.Set(acc, true)
+		assert.True(t, flag.Get(acc), "Get() after Set()")
+		assert.True(t, extras.StateAccount.Get(acc).Flag, "accessor Get() after Field.Set()")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			field string
+			new   func(string) error
+		}{
+			{
+				name:  "missing",
+				field: "Missing",
+				new: func(n string) error {
+					_, err := pseudo.NewField[bool](extras.Header.Get, extras.Header.Set, n)
+					return err
+				},
+			},
+			{
+				name:  "unexported",
+				field: "hidden",
+				new: func(n string) error {
+					_, err := pseudo.NewField[bool](extras.Header.Get, extras.Header.Set, n)
+					return err
+				},
+			},
+			{
+				name:  "wrong_type",
+				field: "FeeConfig",
+				new: func(n string) error {
+					_, err := pseudo.NewField[*big.Float](extras.Header.Get, extras.Header.Set, n)
+					return err
+				},
+			},
+			{
+				name:  "non_struct_payload",
+				field: "X",
+				new: func(n string) error {
+					_, err := pseudo.NewField[bool](
+						func(*types.Header) int { return 0 },
+						func(*types.Header, int) {},
+						n,
+					)
+					return err
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				require.Error(t, tt.new(tt.field))
+			})
+		}
+	})
+}
@@ -0,0 +1,242 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// genContext tracks state required while generating code for a single
+// accessor.
+type genContext struct {
+	inPackage *types.Package
+	imports   map[string]struct{}
+}
+
+func (ctx *genContext) addImport(path string) {
+	if path == ctx.inPackage.Path() {
+		return
+	}
+	ctx.imports[path] = struct{}{}
+}
+
+// qualify is the [types.Qualifier] used for printing types.
+func (ctx *genContext) qualify(pkg *types.Package) string {
+	if pkg == ctx.inPackage {
+		return ""
+	}
+	ctx.addImport(pkg.Path())
+	return pkg.Name()
+}
+
+func (ctx *genContext) typeString(t types.Type) string {
+	return types.TypeString(t, ctx.qualify)
+}
+
+// payloadSig is the signature of an accessor: `Get(C) P` and `Set(C, P)`.
+type payloadSig struct {
+	container, payload types.Type
+	structType         *types.Struct
+	isPtr              bool
+}
+
+func accessorSignature(acc types.Type) (*payloadSig, error) {
+	method := func(name string) (*types.Signature, error) {
+		obj, _, _ := types.LookupFieldOrMethod(acc, true, nil, name)
+		fn, ok := obj.(*types.Func)
+		if !ok {
+			return nil, fmt.Errorf("%v has no method %s()", acc, name)
+		}
+		return fn.Type().(*types.Signature), nil //nolint:forcetypeassert // invariant of *types.Func
+	}
+
+	get, err := method("Get")
+	if err != nil {
+		return nil, err
+	}
+	set, err := method("Set")
+	if err != nil {
+		return nil, err
+	}
+	if get.Params().Len() != 1 || get.Results().Len() != 1 {
+		return nil, fmt.Errorf("%v.Get() signature %v is not func(C) P", acc, get)
+	}
+	sig := &payloadSig{
+		container: get.Params().At(0).Type(),
+		payload:   get.Results().At(0).Type(),
+	}
+	if set.Params().Len() != 2 || set.Results().Len() != 0 ||
+		!types.Identical(set.Params().At(0).Type(), sig.container) ||
+		!types.Identical(set.Params().At(1).Type(), sig.payload) {
+		return nil, fmt.Errorf("%v.Set() signature %v is not func(%v, %v)", acc, set, sig.container, sig.payload)
+	}
+
+	under := sig.payload.Underlying()
+	if ptr, ok := under.(*types.Pointer); ok {
+		sig.isPtr = true
+		under = ptr.Elem().Underlying()
+	}
+	st, ok := under.(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("payload type %v is neither a struct nor a pointer to one", sig.payload)
+	}
+	sig.structType = st
+	return sig, nil
+}
+
+// generate returns the formatted Go code for all field accessors.
+func (cfg *Config) generate(fset *token.FileSet, pkg *types.Package) ([]byte, error) {
+	acc, err := cfg.accessorType(fset, pkg)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := accessorSignature(acc)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &genContext{
+		inPackage: pkg,
+		imports:   make(map[string]struct{}),
+	}
+	var body bytes.Buffer
+	for i := 0; i < sig.structType.NumFields(); i++ {
+		f := sig.structType.Field(i)
+		if !f.Exported() || f.Embedded() {
+			continue
+		}
+		cfg.writeField(&body, ctx, sig, f)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprint(&out, "// Code generated by github.com/ava-labs/libevm/libevm/pseudo/gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg.Name())
+	if len(ctx.imports) > 0 {
+		var std, other []string
+		for p := range ctx.imports {
+			if first, _, _ := strings.Cut(p, "/"); strings.Contains(first, ".") {
+				other = append(other, p)
+			} else {
+				std = append(std, p)
+			}
+		}
+		sort.Strings(std)
+		sort.Strings(other)
+
+		fmt.Fprintln(&out, "import (")
+		for _, p := range std {
+			fmt.Fprintf(&out, "\t%q\n", p)
+		}
+		if len(std) > 0 && len(other) > 0 {
+			fmt.Fprintln(&out)
+		}
+		for _, p := range other {
+			fmt.Fprintf(&out, "\t%q\n", p)
+		}
+		fmt.Fprint(&out, ")\n\n")
+	}
+	out.Write(body.Bytes())
+
+	code, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v\n%s", err, out.Bytes())
+	}
+	return code, nil
+}
+
+func (cfg *Config) writeField(w *bytes.Buffer, ctx *genContext, sig *payloadSig, f *types.Var) {
+	var (
+		container = ctx.typeString(sig.container)
+		payload   = ctx.typeString(sig.payload)
+		fieldType = ctx.typeString(f.Type())
+		name      = cfg.Prefix + f.Name()
+		acc       = cfg.Accessor
+	)
+	copyStmts := ""
+	if cfg.Copy {
+		copyStmts = ctx.copyStatements(f.Type(), "v")
+	}
+
+	fmt.Fprintf(w, "// Get%s returns the %s field of the %s payload carried by `c`", name, f.Name(), payload)
+	if sig.isPtr {
+		fmt.Fprint(w, ", or\n// its zero value if the payload is nil")
+	}
+	fmt.Fprint(w, ".\n")
+	if copyStmts != "" {
+		fmt.Fprint(w, "// The returned value is a copy.\n")
+	}
+	fmt.Fprintf(w, "func Get%s(c %s) (v %s) {\n", name, container, fieldType)
+	fmt.Fprintf(w, "\tp := %s.Get(c)\n", acc)
+	if sig.isPtr {
+		fmt.Fprint(w, "\tif p == nil {\n\t\treturn v\n\t}\n")
+	}
+	fmt.Fprintf(w, "\tv = p.%s\n%s\treturn v\n}\n\n", f.Name(), copyStmts)
+
+	fmt.Fprintf(w, "// Set%s sets the %s field of the %s payload carried by `c`", name, f.Name(), payload)
+	if sig.isPtr {
+		fmt.Fprint(w, ",\n// allocating a new payload if it is nil")
+	}
+	fmt.Fprint(w, ".\n")
+	if copyStmts != "" {
+		fmt.Fprint(w, "// The stored value is a copy of `v`.\n")
+	}
+	fmt.Fprintf(w, "func Set%s(c %s, v %s) {\n", name, container, fieldType)
+	fmt.Fprintf(w, "\tp := %s.Get(c)\n", acc)
+	if sig.isPtr {
+		elem := ctx.typeString(sig.payload.Underlying().(*types.Pointer).Elem()) //nolint:forcetypeassert // isPtr
+		fmt.Fprintf(w, "\tif p == nil {\n\t\tp = new(%s)\n\t}\n", elem)
+	}
+	fmt.Fprintf(w, "%s\tp.%s = v\n\t%s.Set(c, p)\n}\n\n", copyStmts, f.Name(), acc)
+}
+
+// copyStatements returns statements that replace the variable `v` of type `t`
+// with a copy, or the empty string if no copy is required.
+func (ctx *genContext) copyStatements(t types.Type, v string) string {
+	var b strings.Builder
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		ctx.addImport("slices")
+		fmt.Fprintf(&b, "\t%s = slices.Clone(%[1]s)\n", v)
+	case *types.Map:
+		ctx.addImport("maps")
+		fmt.Fprintf(&b, "\t%s = maps.Clone(%[1]s)\n", v)
+	case *types.Pointer:
+		fmt.Fprintf(&b, "\tif %s != nil {\n", v)
+		if isBigInt(u.Elem()) {
+			fmt.Fprintf(&b, "\t\t%s = new(%s).Set(%[1]s)\n", v, ctx.typeString(u.Elem()))
+		} else {
+			fmt.Fprintf(&b, "\t\tcp := *%s\n\t\t%[1]s = &cp\n", v)
+		}
+		fmt.Fprint(&b, "\t}\n")
+	}
+	return b.String()
+}
+
+func isBigInt(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := n.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "math/big" && obj.Name() == "Int"
+}
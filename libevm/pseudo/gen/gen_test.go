@@ -0,0 +1,129 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func typeCheck(t *testing.T, fset *token.FileSet, srcs ...[]byte) *types.Package {
+	t.Helper()
+	var files []*ast.File
+	for _, src := range srcs {
+		f, err := parser.ParseFile(fset, "", src, 0)
+		require.NoError(t, err, "parser.ParseFile()")
+		files = append(files, f)
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+	}
+	pkg, err := conf.Check("test", fset, files, nil)
+	require.NoError(t, err, "%T.Check()", conf)
+	return pkg
+}
+
+func TestOutput(t *testing.T) {
+	tests := []struct {
+		input, output string
+		cfg           Config
+	}{
+		{
+			input:  "pointer",
+			output: "pointer",
+			cfg:    Config{Accessor: "extras.Header"},
+		},
+		{
+			input:  "pointer",
+			output: "pointer_copy",
+			cfg:    Config{Accessor: "extras.Header", Copy: true},
+		},
+		{
+			input:  "pointer",
+			output: "pointer_prefix",
+			cfg:    Config{Accessor: "extras.Header", Prefix: "Header"},
+		},
+		{
+			input:  "value",
+			output: "value",
+			cfg:    Config{Accessor: "extras.StateAccount"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			src, err := os.ReadFile(filepath.Join("testdata", tt.input+".in.txt"))
+			require.NoError(t, err)
+
+			fset := token.NewFileSet()
+			got, err := tt.cfg.generate(fset, typeCheck(t, fset, src))
+			require.NoError(t, err, "generate()")
+
+			outputFile := filepath.Join("testdata", tt.output+".out.txt")
+			// Set this environment variable to regenerate the test outputs.
+			if os.Getenv("WRITE_TEST_FILES") != "" {
+				require.NoError(t, os.WriteFile(outputFile, got, 0600))
+			}
+			want, err := os.ReadFile(outputFile)
+			require.NoError(t, err)
+			require.Equal(t, string(want), string(got))
+
+			t.Run("compiles", func(t *testing.T) {
+				typeCheck(t, token.NewFileSet(), src, got)
+			})
+		})
+	}
+}
+
+func TestAccessorErrors(t *testing.T) {
+	const src = `package test
+
+type Accessor[C, P any] struct{}
+
+func (Accessor[C, P]) Get(C) P  { panic(0) }
+func (Accessor[C, P]) Set(C, P) {}
+
+type Bad struct{}
+
+func (Bad) Get(int) int { return 0 }
+func (Bad) Set(int, string) {}
+
+var (
+	notStruct Accessor[int, []int]
+	badSet    Bad
+	noMethods struct{}
+)
+`
+	fset := token.NewFileSet()
+	pkg := typeCheck(t, fset, []byte(src))
+
+	for _, expr := range []string{"notStruct", "badSet", "noMethods", "missing", "Accessor"} {
+		t.Run(expr, func(t *testing.T) {
+			cfg := &Config{Accessor: expr}
+			_, err := cfg.generate(fset, pkg)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// The gen command generates typed getter and setter functions for each
+// exported field of an extra payload, accessed via an extras accessor such as
+// those returned by `types.RegisterExtras()` or `params.RegisterExtras()`.
+//
+// Usage:
+//
+//	//go:generate go run github.com/ava-labs/libevm/libevm/pseudo/gen -accessor extras.Header -out gen_header_extras.go
+//
+// where `extras.Header` is a Go expression, evaluated in the scope of the
+// package in `-dir`, of a type with methods `Get(C) P` and `Set(C, P)`. The
+// payload type `P` MUST be a struct or a pointer to a struct. For every
+// exported, non-embedded field `Foo` of type `T`, functions with signatures
+// `GetFoo(C) T` and `SetFoo(C, T)` are generated.
+//
+// If `P` is a pointer then nil payloads are handled: getters return the zero
+// value and setters allocate a new payload. If the `-copy` flag is set then
+// slices, maps, and pointers are (shallow) copied when either getting or
+// setting; *big.Int values are deep copied.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"go/types"
+	"os"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	var (
+		pkgdir   = flag.String("dir", ".", "input package")
+		output   = flag.String("out", "-", "output file (default is stdout)")
+		accessor = flag.String("accessor", "", "Go expression of the accessor; e.g. extras.Header")
+		prefix   = flag.String("prefix", "", "string inserted between Get/Set and the field name")
+		copyVals = flag.Bool("copy", false, "copy slices, maps, and pointers in getters and setters")
+	)
+	flag.Parse()
+
+	cfg := Config{
+		Dir:      *pkgdir,
+		Accessor: *accessor,
+		Prefix:   *prefix,
+		Copy:     *copyVals,
+	}
+	code, err := cfg.process()
+	if err != nil {
+		fatal(err)
+	}
+	if *output == "-" {
+		os.Stdout.Write(code)
+	} else if err := os.WriteFile(*output, code, 0600); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(args ...any) {
+	fmt.Fprintln(os.Stderr, args...)
+	os.Exit(1)
+}
+
+// Config configures code generation.
+type Config struct {
+	Dir      string // input package directory
+	Accessor string // Go expression, evaluated in package scope
+	Prefix   string
+	Copy     bool
+}
+
+// process loads the package in `cfg.Dir` and generates the Go code.
+func (cfg *Config) process() ([]byte, error) {
+	if cfg.Accessor == "" {
+		return nil, fmt.Errorf("missing accessor expression")
+	}
+
+	pcfg := &packages.Config{
+		// Syntax is required for type-checking from source, without which
+		// unexported accessors would be unavailable.
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:  cfg.Dir,
+		Fset: token.NewFileSet(),
+	}
+	ps, err := packages.Load(pcfg, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(ps) != 1 {
+		return nil, fmt.Errorf("found %d Go packages in %s; expected 1", len(ps), cfg.Dir)
+	}
+	packages.PrintErrors(ps)
+	if len(ps[0].Errors) > 0 {
+		return nil, fmt.Errorf("package %s has errors", ps[0].PkgPath)
+	}
+	return cfg.generate(pcfg.Fset, ps[0].Types)
+}
+
+// accessorType evaluates `cfg.Accessor` in the scope of `pkg`.
+func (cfg *Config) accessorType(fset *token.FileSet, pkg *types.Package) (types.Type, error) {
+	tv, err := types.Eval(fset, pkg, token.NoPos, cfg.Accessor)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating accessor %q: %v", cfg.Accessor, err)
+	}
+	if !tv.IsValue() {
+		return nil, fmt.Errorf("accessor %q is not a value", cfg.Accessor)
+	}
+	return tv.Type, nil
+}
@@ -137,3 +137,29 @@ func SkipStateKeyTransformation() StateDBStateOption {
 func ShouldTransformStateKey(opts ...StateDBStateOption) bool {
 	return !options.As(opts...).skipKeyTransformation
 }
+
+// A StateDBTrieOption configures the opening of tries by state.Database
+// OpenTrie() and OpenStorageTrie() implementations, which forward them to
+// triedb.Database backends implementing triedb.OptionedReaderProvider.
+type StateDBTrieOption = options.Option[stateDBTrieConfig]
+
+type stateDBTrieConfig struct {
+	payload any
+}
+
+// WithTrieOpenPayload returns a StateDBTrieOption carrying an arbitrary
+// payload. It acts only as a carrier to exploit existing function plumbing and
+// the effect on behaviour is left to the implementation receiving it; e.g. to
+// distinguish speculative reads from canonical ones.
+func WithTrieOpenPayload(p any) StateDBTrieOption {
+	return options.Func[stateDBTrieConfig](func(c *stateDBTrieConfig) {
+		c.payload = p
+	})
+}
+
+// ExtractTrieOpenPayload returns the payload carried by a [WithTrieOpenPayload]
+// option. Only one such option can be used at once; behaviour is otherwise
+// undefined.
+func ExtractTrieOpenPayload(opts ...StateDBTrieOption) any {
+	return options.As(opts...).payload
+}
@@ -94,6 +94,8 @@ type environment struct {
 	receipts []*types.Receipt
 	sidecars []*types.BlobTxSidecar
 	blobs    int
+
+	invocations *vm.PrecompileInvocations // libevm: shared by all txs in the block
 }
 
 // copy creates a deep copy of environment.
@@ -105,6 +107,8 @@ func (env *environment) copy() *environment {
 		coinbase: env.coinbase,
 		header:   types.CopyHeader(env.header),
 		receipts: copyReceipts(env.receipts),
+
+		invocations: env.invocations.Copy(), // libevm
 	}
 	if env.gasPool != nil {
 		gasPool := *env.gasPool
@@ -697,6 +701,7 @@ func (w *worker) resultLoop() {
 				}
 				logs = append(logs, receipt.Logs...)
 			}
+			logs = append(logs, core.NonTransactionLogs(task.state, block.NumberU64(), hash)...) // libevm
 			// Commit block and state to database.
 			_, err := w.chain.WriteBlockAndSetHead(block, receipts, logs, task.state, true)
 			if err != nil {
@@ -731,6 +736,8 @@ func (w *worker) makeEnv(parent *types.Header, header *types.Header, coinbase co
 		state:    state,
 		coinbase: coinbase,
 		header:   header,
+
+		invocations: vm.NewPrecompileInvocations(), // libevm
 	}
 	// Keep track of transactions which return errors so they can be removed
 	env.tcount = 0
@@ -795,11 +802,13 @@ func (w *worker) applyTransaction(env *environment, tx *types.Transaction) (*typ
 	var (
 		snap = env.state.Snapshot()
 		gp   = env.gasPool.Gas()
+		inv  = env.invocations.Copy() // libevm
 	)
-	receipt, err := core.ApplyTransaction(w.chainConfig, w.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, *w.chain.GetVMConfig())
+	receipt, err := core.ApplyTransactionWithInvocations(w.chainConfig, w.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, *w.chain.GetVMConfig(), env.invocations) // libevm
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
 		env.gasPool.SetGas(gp)
+		env.invocations = inv // libevm
 	}
 	return receipt, err
 }
@@ -1015,6 +1024,7 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 		vmenv := vm.NewEVM(context, vm.TxContext{}, env.state, w.chainConfig, vm.Config{})
 		core.ProcessBeaconBlockRoot(*header.ParentBeaconRoot, vmenv, env.state)
 	}
+	core.ProcessBeforeBlock(env.state, types.NewBlockWithHeader(header)) // libevm
 	return env, nil
 }
 
@@ -1096,6 +1106,7 @@ func (w *worker) generateWork(params *generateParams) *newPayloadResult {
 			log.Warn("Block building is interrupted", "allowance", common.PrettyDuration(w.newpayloadTimeout))
 		}
 	}
+	work.processAfterBlock(params.withdrawals) // libevm
 	block, err := w.engine.FinalizeAndAssemble(w.chain, work.header, work.state, work.txs, nil, work.receipts, params.withdrawals)
 	if err != nil {
 		return &newPayloadResult{err: err}
@@ -1185,6 +1196,7 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 		// https://github.com/ethereum/go-ethereum/issues/24299
 		env := env.copy()
 		// Withdrawals are set to nil here, because this is only called in PoW.
+		env.processAfterBlock(nil) // libevm
 		block, err := w.engine.FinalizeAndAssemble(w.chain, env.header, env.state, env.txs, nil, env.receipts, nil)
 		if err != nil {
 			return err
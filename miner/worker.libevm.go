@@ -0,0 +1,33 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+)
+
+// processAfterBlock calls [core.ProcessAfterBlock] with the block assembled so
+// far. It MUST be called exactly once, immediately before the consensus engine
+// finalizes the block.
+func (env *environment) processAfterBlock(withdrawals types.Withdrawals) {
+	block := types.NewBlockWithHeader(env.header).WithBody(types.Body{
+		Transactions: env.txs,
+		Withdrawals:  withdrawals,
+	})
+	core.ProcessAfterBlock(env.state, block, env.receipts)
+}
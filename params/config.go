@@ -671,7 +671,7 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
-	return c.Hooks().CheckConfigForkOrder()
+	return c.checkExtrasForkOrder() // libevm
 }
 
 func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError {
@@ -742,7 +742,7 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.VerkleTime, newcfg.VerkleTime, headTimestamp) {
 		return newTimestampCompatError("Verkle fork timestamp", c.VerkleTime, newcfg.VerkleTime)
 	}
-	return c.Hooks().CheckConfigCompatible(newcfg, headNumber, headTimestamp)
+	return c.checkExtrasCompatible(newcfg, headNumber, headTimestamp) // libevm
 }
 
 // BaseFeeChangeDenominator bounds the amount the base fee can change between blocks.
@@ -914,7 +914,9 @@ type Rules struct {
 	IsMerge, IsShanghai, IsCancun, IsPrague                 bool
 	IsVerkle                                                bool
 
-	extra *pseudo.Type // See RegisterExtras()
+	extra     *pseudo.Type // See RegisterExtras()
+	timestamp uint64       // libevm: see [Rules.Timestamp]
+	config    *ChainConfig // libevm: see [Rules.SystemAddress]
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -48,6 +48,13 @@ type Extras[C ChainConfigHooks, R RulesHooks] struct {
 	// TODO(arr4n): add the [Rules] to the return signature to make it clearer
 	// that the caller can modify the generated Rules.
 	NewRules func(_ *ChainConfig, _ *Rules, _ C, blockNum *big.Int, isMerge bool, timestamp uint64) R
+	// CheckConfigCompatible, if non-nil, is called by
+	// [ChainConfig.CheckCompatible] with the extra payloads of the stored and
+	// new configs, after all other checks, including
+	// [ChainConfigHooks.CheckConfigCompatible], have passed. It allows changes
+	// to the extras across restarts to be detected without having to access
+	// the new payload via the [ChainConfig].
+	CheckConfigCompatible func(stored, new C, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError
 }
 
 // RegisterExtras registers the types `C` and `R` such that they are carried as
@@ -87,11 +94,12 @@ func RegisterExtras[C ChainConfigHooks, R RulesHooks](e Extras[C, R]) ExtraPaylo
 func payloadsAndConstructors[C ChainConfigHooks, R RulesHooks](e Extras[C, R]) (ExtraPayloads[C, R], *extraConstructors) {
 	payloads := e.payloads()
 	return payloads, &extraConstructors{
-		newChainConfig: pseudo.NewConstructor[C]().Zero,
-		newRules:       pseudo.NewConstructor[R]().Zero,
-		reuseJSONRoot:  e.ReuseJSONRoot,
-		newForRules:    e.newForRules,
-		payloads:       payloads,
+		newChainConfig:  pseudo.NewConstructor[C]().Zero,
+		newRules:        pseudo.NewConstructor[R]().Zero,
+		reuseJSONRoot:   e.ReuseJSONRoot,
+		newForRules:     e.newForRules,
+		checkCompatible: e.checkCompatible,
+		payloads:        payloads,
 	}
 }
 
@@ -137,6 +145,7 @@ type extraConstructors struct {
 	newChainConfig, newRules func() *pseudo.Type
 	reuseJSONRoot            bool
 	newForRules              func(_ *ChainConfig, _ *Rules, blockNum *big.Int, isMerge bool, timestamp uint64) *pseudo.Type
+	checkCompatible          func(stored, newcfg *ChainConfig, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError
 	// use top-level hooksFrom<X>() functions instead of these as they handle
 	// instances where no [Extras] were registered.
 	payloads interface {
@@ -153,6 +162,14 @@ func (e *Extras[C, R]) newForRules(c *ChainConfig, r *Rules, blockNum *big.Int,
 	return pseudo.From(rExtra).Type
 }
 
+func (e *Extras[C, R]) checkCompatible(stored, newcfg *ChainConfig, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError {
+	if e.CheckConfigCompatible == nil {
+		return nil
+	}
+	p := e.payloads().ChainConfig
+	return e.CheckConfigCompatible(p.Get(stored), p.Get(newcfg), headNumber, headTimestamp)
+}
+
 func (*Extras[C, R]) payloads() ExtraPayloads[C, R] {
 	return ExtraPayloads[C, R]{
 		ChainConfig: pseudo.NewAccessor[*ChainConfig, C](
@@ -211,12 +228,20 @@ func (e ExtraPayloads[C, R]) hooksFromRules(r *Rules) RulesHooks {
 // addRulesExtra is called at the end of [ChainConfig.Rules]; it exists to
 // abstract the libevm-specific behaviour outside of original geth code.
 func (c *ChainConfig) addRulesExtra(r *Rules, blockNum *big.Int, isMerge bool, timestamp uint64) {
+	r.timestamp = timestamp
+	r.config = c
 	r.extra = nil
 	if registeredExtras.Registered() {
 		r.extra = registeredExtras.Get().newForRules(c, r, blockNum, isMerge, timestamp)
 	}
 }
 
+// Timestamp returns the timestamp passed to [ChainConfig.Rules] when
+// constructing r, or zero if r was constructed by other means.
+func (r *Rules) Timestamp() uint64 {
+	return r.timestamp
+}
+
 // extraPayload returns the ChainConfig's extra payload iff [RegisterExtras] has
 // already been called. If the payload hasn't been populated (typically via
 // unmarshalling of JSON), a nil value is constructed and returned.
@@ -368,3 +368,69 @@ func assertRulesCopiedFromChainConfig[C ChainConfigHooks, R RulesHooks, Payload
 
 	assert.Equalf(t, val, getX(&rulesExtra), "%T.X copied from %T.X", rulesExtra, ccExtra)
 }
+
+func TestExtrasCheckConfigCompatible(t *testing.T) {
+	type ccExtra struct {
+		NOOPHooks
+		Upgrades ForkExtras[struct{}]
+	}
+	const head = 100
+
+	tests := []struct {
+		name       string
+		check      bool
+		storedTime uint64
+		newTime    uint64
+		want       *ConfigCompatError
+	}{
+		{
+			name:       "unchanged",
+			check:      true,
+			storedTime: 50,
+			newTime:    50,
+		},
+		{
+			name:       "changed_after_head",
+			check:      true,
+			storedTime: 200,
+			newTime:    300,
+		},
+		{
+			name:       "changed_before_head",
+			check:      true,
+			storedTime: 50,
+			newTime:    60,
+			want:       newTimestampCompatError("Alpha fork timestamp", newUint64(50), newUint64(60)),
+		},
+		{
+			name:       "no_check_registered",
+			storedTime: 50,
+			newTime:    60,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			TestOnlyClearRegisteredExtras()
+			t.Cleanup(TestOnlyClearRegisteredExtras)
+
+			e := Extras[ccExtra, NOOPHooks]{}
+			if tt.check {
+				e.CheckConfigCompatible = func(stored, new ccExtra, _ *big.Int, headTimestamp uint64) *ConfigCompatError {
+					return stored.Upgrades.CheckCompatible(new.Upgrades, headTimestamp)
+				}
+			}
+			extras := RegisterExtras(e)
+
+			newConfig := func(time uint64) *ChainConfig {
+				c := &ChainConfig{ChainID: big.NewInt(1)}
+				extras.ChainConfig.Set(c, ccExtra{
+					Upgrades: ForkExtras[struct{}]{{Name: "Alpha", Time: time}},
+				})
+				return c
+			}
+			stored, updated := newConfig(tt.storedTime), newConfig(tt.newTime)
+			assert.Equal(t, tt.want, stored.CheckCompatible(updated, 0, head))
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// A NetworkUpgrade is a named section of a [ForkExtras] schedule, activated
+// at a block timestamp.
+type NetworkUpgrade[T any] struct {
+	Name   string `json:"name"`
+	Time   uint64 `json:"timestamp"`
+	Config T      `json:"config"`
+}
+
+// ForkExtras is a schedule of timestamp-activated network upgrades, each
+// carrying a configuration section of type `T`, in order of activation. It is
+// intended to be a field of the [ChainConfig] extra payload registered with
+// [RegisterExtras], with the active section resolved by [Extras.NewRules]
+// via [ForkExtras.ActiveAt], and the schedule checked via [ForkExtras.Verify]
+// and, typically from [Extras.CheckConfigCompatible], [ForkExtras.CheckCompatible].
+type ForkExtras[T any] []NetworkUpgrade[T]
+
+// Verify returns an error if any upgrade is unnamed, if names are repeated,
+// or if activation times are decreasing. Upgrades MAY share an activation
+// time, in which case the later one in the schedule takes precedence.
+func (f ForkExtras[T]) Verify() error {
+	seen := make(map[string]struct{}, len(f))
+	for i, u := range f {
+		if u.Name == "" {
+			return fmt.Errorf("network upgrade %d is unnamed", i)
+		}
+		if _, ok := seen[u.Name]; ok {
+			return fmt.Errorf("network upgrade %q is repeated", u.Name)
+		}
+		seen[u.Name] = struct{}{}
+		if i > 0 && u.Time < f[i-1].Time {
+			return fmt.Errorf("network upgrade %q (timestamp %d) activates before preceding %q (timestamp %d)", u.Name, u.Time, f[i-1].Name, f[i-1].Time)
+		}
+	}
+	return nil
+}
+
+// ActivationTimes returns the activation time of each upgrade, in order.
+func (f ForkExtras[T]) ActivationTimes() []uint64 {
+	ts := make([]uint64, len(f))
+	for i, u := range f {
+		ts[i] = u.Time
+	}
+	return ts
+}
+
+// ActiveAt returns the most recent upgrade activated at or before the block
+// timestamp, and true, or false if there is none. The schedule MUST be
+// [ForkExtras.Verify]-ed.
+func (f ForkExtras[T]) ActiveAt(time uint64) (NetworkUpgrade[T], bool) {
+	// Index of the first upgrade that is not yet active.
+	i := sort.Search(len(f), func(i int) bool { return f[i].Time > time })
+	if i == 0 {
+		return NetworkUpgrade[T]{}, false
+	}
+	return f[i-1], true
+}
+
+// IsActive reports whether the named upgrade exists and is active at the
+// block timestamp.
+func (f ForkExtras[T]) IsActive(name string, time uint64) bool {
+	for _, u := range f {
+		if u.Name == name {
+			return u.Time <= time
+		}
+	}
+	return false
+}
+
+// CheckCompatible is the [ForkExtras] equivalent of
+// [ChainConfig.CheckCompatible], comparing the receiver, as the stored
+// schedule, against a new one. The activation time of an upgrade MUST NOT
+// change, nor may an upgrade be added or removed, if it is active at the head
+// timestamp under either schedule. Similarly, the configuration of an upgrade
+// active at the head timestamp MUST NOT change, as determined by
+// [reflect.DeepEqual].
+func (f ForkExtras[T]) CheckCompatible(newcfg ForkExtras[T], headTimestamp uint64) *ConfigCompatError {
+	upgrades := func(s ForkExtras[T]) map[string]NetworkUpgrade[T] {
+		m := make(map[string]NetworkUpgrade[T], len(s))
+		for _, u := range s {
+			m[u.Name] = u
+		}
+		return m
+	}
+	stored, updated := upgrades(f), upgrades(newcfg)
+	time := func(m map[string]NetworkUpgrade[T], name string) *uint64 {
+		if u, ok := m[name]; ok {
+			return &u.Time
+		}
+		return nil
+	}
+
+	var names []string
+	for _, u := range append(slices.Clone(f), newcfg...) {
+		if !slices.Contains(names, u.Name) {
+			names = append(names, u.Name)
+		}
+	}
+
+	var earliest *ConfigCompatError
+	for _, name := range names {
+		s, n := time(stored, name), time(updated, name)
+		var err *ConfigCompatError
+		switch {
+		case isForkTimestampIncompatible(s, n, headTimestamp):
+			err = newTimestampCompatError(fmt.Sprintf("%s fork timestamp", name), s, n)
+		case s != nil && n != nil && isTimestampForked(s, headTimestamp) && !reflect.DeepEqual(stored[name].Config, updated[name].Config):
+			// Given the previous case, the timestamps are necessarily equal.
+			err = newTimestampCompatError(fmt.Sprintf("%s fork config", name), s, n)
+		default:
+			continue
+		}
+		if earliest == nil || err.RewindToTime < earliest.RewindToTime {
+			earliest = err
+		}
+	}
+	return earliest
+}
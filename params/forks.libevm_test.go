@@ -0,0 +1,217 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"encoding/json"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type feeConfig struct {
+	MinBaseFee uint64 `json:"minBaseFee"`
+}
+
+type upgradesExtra struct {
+	NOOPHooks
+	Upgrades ForkExtras[feeConfig] `json:"upgrades"`
+}
+
+type upgradesRulesExtra struct {
+	NOOPHooks
+	Upgrade   string
+	FeeConfig feeConfig
+}
+
+func TestForkExtras(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+	extras := RegisterExtras(Extras[upgradesExtra, upgradesRulesExtra]{
+		NewRules: func(_ *ChainConfig, _ *Rules, c upgradesExtra, _ *big.Int, _ bool, timestamp uint64) upgradesRulesExtra {
+			u, _ := c.Upgrades.ActiveAt(timestamp)
+			return upgradesRulesExtra{
+				Upgrade:   u.Name,
+				FeeConfig: u.Config,
+			}
+		},
+	})
+
+	const configJSON = `{
+		"chainId": 1,
+		"extra": {
+			"upgrades": [
+				{"name": "first", "timestamp": 10, "config": {"minBaseFee": 1}},
+				{"name": "second", "timestamp": 20, "config": {"minBaseFee": 2}},
+				{"name": "third", "timestamp": 20, "config": {"minBaseFee": 3}}
+			]
+		}
+	}`
+	config := new(ChainConfig)
+	require.NoError(t, json.Unmarshal([]byte(configJSON), config), "json.Unmarshal(%T)", config)
+	upgrades := extras.ChainConfig.Get(config).Upgrades
+	require.NoError(t, upgrades.Verify(), "Verify()")
+	assert.Equal(t, []uint64{10, 20, 20}, upgrades.ActivationTimes(), "ActivationTimes()")
+
+	tests := []struct {
+		time        uint64
+		wantUpgrade string
+		wantMinFee  uint64
+	}{
+		{time: 0},
+		{time: 9},
+		{time: 10, wantUpgrade: "first", wantMinFee: 1},
+		{time: 19, wantUpgrade: "first", wantMinFee: 1},
+		{time: 20, wantUpgrade: "third", wantMinFee: 3},
+		{time: 1000, wantUpgrade: "third", wantMinFee: 3},
+	}
+	for _, tt := range tests {
+		rules := config.Rules(big.NewInt(0), false, tt.time)
+		got := extras.Rules.Get(&rules)
+		assert.Equalf(t, tt.wantUpgrade, got.Upgrade, "Rules(time=%d) active upgrade", tt.time)
+		assert.Equalf(t, tt.wantMinFee, got.FeeConfig.MinBaseFee, "Rules(time=%d) fee config", tt.time)
+	}
+
+	assert.True(t, upgrades.IsActive("second", 20), "IsActive(second, 20)")
+	assert.False(t, upgrades.IsActive("second", 19), "IsActive(second, 19)")
+	assert.False(t, upgrades.IsActive("unknown", 1000), "IsActive(unknown, 1000)")
+}
+
+func TestForkExtrasVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		f       ForkExtras[struct{}]
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			f:    ForkExtras[struct{}]{{Name: "a", Time: 1}, {Name: "b", Time: 1}, {Name: "c", Time: 2}},
+		},
+		{
+			name:    "unnamed",
+			f:       ForkExtras[struct{}]{{Time: 1}},
+			wantErr: true,
+		},
+		{
+			name:    "repeated_name",
+			f:       ForkExtras[struct{}]{{Name: "a", Time: 1}, {Name: "a", Time: 2}},
+			wantErr: true,
+		},
+		{
+			name:    "decreasing_time",
+			f:       ForkExtras[struct{}]{{Name: "a", Time: 2}, {Name: "b", Time: 1}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.f.Verify()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestForkExtrasCheckCompatible(t *testing.T) {
+	stored := ForkExtras[feeConfig]{
+		{Name: "a", Time: 10, Config: feeConfig{MinBaseFee: 1}},
+		{Name: "b", Time: 20, Config: feeConfig{MinBaseFee: 2}},
+	}
+	withConfig := func(i int, c feeConfig) ForkExtras[feeConfig] {
+		s := slices.Clone(stored)
+		s[i].Config = c
+		return s
+	}
+
+	tests := []struct {
+		name         string
+		newcfg       ForkExtras[feeConfig]
+		head         uint64
+		wantWhat     string // empty if compatible
+		wantRewindTo uint64
+	}{
+		{
+			name:   "identical",
+			newcfg: stored,
+			head:   100,
+		},
+		{
+			name:   "reschedule_future",
+			newcfg: ForkExtras[feeConfig]{stored[0], {Name: "b", Time: 30, Config: stored[1].Config}},
+			head:   15,
+		},
+		{
+			name:   "add_future",
+			newcfg: append(stored, NetworkUpgrade[feeConfig]{Name: "c", Time: 50}),
+			head:   25,
+		},
+		{
+			name:         "reschedule_active",
+			newcfg:       ForkExtras[feeConfig]{stored[0], {Name: "b", Time: 30, Config: stored[1].Config}},
+			head:         25,
+			wantWhat:     "b fork timestamp",
+			wantRewindTo: 19,
+		},
+		{
+			name:         "remove_active",
+			newcfg:       stored[:1],
+			head:         25,
+			wantWhat:     "b fork timestamp",
+			wantRewindTo: 19,
+		},
+		{
+			name:   "reconfigure_future",
+			newcfg: withConfig(1, feeConfig{MinBaseFee: 3}),
+			head:   15,
+		},
+		{
+			name:         "reconfigure_active",
+			newcfg:       withConfig(1, feeConfig{MinBaseFee: 3}),
+			head:         25,
+			wantWhat:     "b fork config",
+			wantRewindTo: 19,
+		},
+		{
+			name:         "earliest_reported",
+			newcfg:       ForkExtras[feeConfig]{{Name: "a", Time: 5}, {Name: "b", Time: 15}},
+			head:         25,
+			wantWhat:     "a fork timestamp",
+			wantRewindTo: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := stored.CheckCompatible(tt.newcfg, tt.head)
+			if tt.wantWhat == "" {
+				require.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, tt.wantWhat, err.What, "What")
+			assert.Equal(t, tt.wantRewindTo, err.RewindToTime, "RewindToTime")
+		})
+	}
+}
@@ -17,8 +17,11 @@
 package params
 
 import (
+	"math"
 	"math/big"
 
+	"github.com/holiman/uint256"
+
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/libevm"
 )
@@ -29,6 +32,11 @@ type ChainConfigHooks interface {
 	CheckConfigForkOrder() error
 	CheckConfigCompatible(newcfg *ChainConfig, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError
 	Description() string
+	// Validate is called by [ChainConfig.CheckConfigForkOrder], after all
+	// other checks have passed, and hence during genesis setup. It is
+	// intended for enforcing invariants on the extra payload, such as bounds
+	// or ordering of values, so invalid configs fail fast.
+	Validate(*ChainConfig) error
 }
 
 // TODO(arr4n): given the choice of whether a hook should be defined on a
@@ -62,6 +70,14 @@ type RulesHooks interface {
 	// called if the access list is nil. The hook MAY return an error (e.g., for
 	// gas overflow).
 	AccessListGas(accessList libevm.AccessList) (gas uint64, override bool, err error)
+	// DataGas receives the non-empty data of a transaction, as well as the gas
+	// charged for it under the default per-byte rules, and returns the
+	// intrinsic gas to be charged instead; e.g. one based on an estimate of the
+	// compressed size. EIP-3860 init-code gas is charged separately and is
+	// unaffected. As the hook is consulted by [core.IntrinsicGas], it applies
+	// equally to transaction execution, the transaction pool, and gas
+	// estimation.
+	DataGas(data []byte, defaultGas uint64) uint64
 	// ShouldRefundGas returns whether or not to honour gas refunds, which is
 	// otherwise the default behaviour.
 	ShouldRefundGas() bool
@@ -71,6 +87,44 @@ type RulesHooks interface {
 	// will be capped at the limit. The minimum spend will be applied _after_
 	// refunds, if any.
 	MinimumGasConsumption(txGasLimit uint64) (gas uint64)
+	// GasPayer is called once per transaction, after CanExecuteTransaction,
+	// and MAY return an account, other than the sender, that pays for gas. If
+	// `sponsored` is true then the payer's balance is checked for and debited
+	// the cost of gas (including blob gas), and is credited with refunds. The
+	// sender remains responsible for the nonce and the transferred value.
+	GasPayer(from common.Address, to *common.Address, _ libevm.StateReader) (payer common.Address, sponsored bool)
+	// MaxInitCodeSize returns the maximum length of init code, enforced as per
+	// EIP-3860 when Shanghai is active; i.e. for contract-creation
+	// transactions, CREATE, CREATE2, the transaction pool, and RPC input. The
+	// returned value MUST NOT exceed [math.MaxUint32] to avoid overflow of gas
+	// calculations.
+	MaxInitCodeSize() int
+	// MaxTxDataBytes returns the maximum length of the data of a transaction
+	// accepted by the transaction pool and as RPC input. It is not a consensus
+	// rule.
+	MaxTxDataBytes() int
+	// PrecompileGas receives the address and input of a call to a precompiled
+	// contract, as well as the gas that the contract itself requires, and
+	// returns the gas to be charged before the contract is run. Stateful
+	// precompiles require zero gas by default, charging for themselves while
+	// running, which is unaffected by this hook.
+	PrecompileGas(addr common.Address, input []byte, defaultGas uint64) uint64
+	// FeeRecipients receives the header of the executing block and the fees
+	// paid by a transaction, and returns the accounts to be credited with
+	// them. The base-fee portion of fees is credited to `baseFeeTo` if non-nil,
+	// otherwise it is burnt, and the priority-fee portion (tip) is credited to
+	// `tipTo`. Fees are never credited for transactions that don't pay them,
+	// e.g. gas-free calls, and the base fee is zero, and never credited, if
+	// the EVM is configured with NoBaseFee. The amounts MUST NOT be modified.
+	FeeRecipients(_ libevm.BlockHeader, baseFee, tip *uint256.Int) (baseFeeTo *common.Address, tipTo common.Address)
+	// VerifyPredicates receives the access list of a transaction, which MAY
+	// encode predicates (e.g. signed messages) addressed to precompiles, and
+	// returns a non-nil error iff they are invalid, in which case so too is
+	// the transaction. It is called before execution, with the header of the
+	// executing block, and during transaction pool validation, with the header
+	// of the current chain head. It is never called for transactions with
+	// empty access lists.
+	VerifyPredicates(_ libevm.BlockHeader, from common.Address, to *common.Address, _ libevm.AccessList) error
 }
 
 // RulesAllowlistHooks are a subset of [RulesHooks] that gate actions, signalled
@@ -83,6 +137,26 @@ type RulesAllowlistHooks interface {
 	CanExecuteTransaction(from common.Address, to *common.Address, _ libevm.StateReader) error
 }
 
+// A CustomPrecompiler MAY be implemented by a type registered with
+// [RegisterExtras] for [Rules] payloads, in which case [vm.ActivePrecompiles]
+// appends the addresses returned by its CustomPrecompiles method, unless
+// already present, before passing them to [RulesHooks.ActivePrecompiles]. This
+// allows custom precompiles to be declared without overriding the entire list.
+// The returned addresses MUST be consistent with the behaviour of the
+// PrecompileOverride hook.
+type CustomPrecompiler interface {
+	CustomPrecompiles() []common.Address
+}
+
+// CustomPrecompiles returns the addresses returned by the [CustomPrecompiler]
+// registered for the [Rules], or nil if there is none.
+func (r *Rules) CustomPrecompiles() []common.Address {
+	if c, ok := r.Hooks().(CustomPrecompiler); ok {
+		return c.CustomPrecompiles()
+	}
+	return nil
+}
+
 // Hooks returns the hooks registered with [RegisterExtras], or [NOOPHooks] if
 // none were registered.
 func (c *ChainConfig) Hooks() ChainConfigHooks {
@@ -92,6 +166,31 @@ func (c *ChainConfig) Hooks() ChainConfigHooks {
 	return NOOPHooks{}
 }
 
+// checkExtrasForkOrder is called at the end of [ChainConfig.CheckConfigForkOrder].
+func (c *ChainConfig) checkExtrasForkOrder() error {
+	hooks := c.Hooks()
+	if err := hooks.CheckConfigForkOrder(); err != nil {
+		return err
+	}
+	if err := c.validateSystemAddresses(); err != nil {
+		return err
+	}
+	return hooks.Validate(c)
+}
+
+// checkExtrasCompatible is called at the end of [ChainConfig.CheckCompatible],
+// running both the [ChainConfigHooks] and [Extras.CheckConfigCompatible]
+// checks.
+func (c *ChainConfig) checkExtrasCompatible(newcfg *ChainConfig, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError {
+	if err := c.Hooks().CheckConfigCompatible(newcfg, headNumber, headTimestamp); err != nil {
+		return err
+	}
+	if e := registeredExtras; e.Registered() {
+		return e.Get().checkCompatible(c, newcfg, headNumber, headTimestamp)
+	}
+	return nil
+}
+
 // Hooks returns the hooks registered with [RegisterExtras], or [NOOPHooks] if
 // none were registered.
 func (r *Rules) Hooks() RulesHooks {
@@ -118,6 +217,11 @@ func (NOOPHooks) CheckConfigForkOrder() error {
 	return nil
 }
 
+// Validate accepts all (otherwise valid) configs.
+func (NOOPHooks) Validate(*ChainConfig) error {
+	return nil
+}
+
 // CheckConfigCompatible verifies all (otherwise valid) new configs.
 func (NOOPHooks) CheckConfigCompatible(*ChainConfig, *big.Int, uint64) *ConfigCompatError {
 	return nil
@@ -155,6 +259,11 @@ func (NOOPHooks) AccessListGas(_ libevm.AccessList) (uint64, bool, error) {
 	return 0, false, nil
 }
 
+// DataGas returns `defaultGas` unchanged.
+func (NOOPHooks) DataGas(_ []byte, defaultGas uint64) uint64 {
+	return defaultGas
+}
+
 // ShouldRefundGas always returns true.
 func (NOOPHooks) ShouldRefundGas() bool {
 	return true
@@ -164,3 +273,34 @@ func (NOOPHooks) ShouldRefundGas() bool {
 func (NOOPHooks) MinimumGasConsumption(uint64) uint64 {
 	return 0
 }
+
+// GasPayer always returns sponsored=false, signalling that the sender pays
+// for gas.
+func (NOOPHooks) GasPayer(common.Address, *common.Address, libevm.StateReader) (common.Address, bool) {
+	return common.Address{}, false
+}
+
+// MaxInitCodeSize returns [MaxInitCodeSize].
+func (NOOPHooks) MaxInitCodeSize() int {
+	return MaxInitCodeSize
+}
+
+// MaxTxDataBytes returns [math.MaxInt], signalling no limit.
+func (NOOPHooks) MaxTxDataBytes() int {
+	return math.MaxInt
+}
+
+// PrecompileGas returns `defaultGas` unchanged.
+func (NOOPHooks) PrecompileGas(_ common.Address, _ []byte, defaultGas uint64) uint64 {
+	return defaultGas
+}
+
+// FeeRecipients burns the base fee and credits the tip to the coinbase.
+func (NOOPHooks) FeeRecipients(header libevm.BlockHeader, _, _ *uint256.Int) (*common.Address, common.Address) {
+	return nil, header.BlockCoinbase()
+}
+
+// VerifyPredicates accepts all predicates.
+func (NOOPHooks) VerifyPredicates(libevm.BlockHeader, common.Address, *common.Address, libevm.AccessList) error {
+	return nil
+}
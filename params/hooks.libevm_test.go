@@ -23,6 +23,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/libevm/ethtest"
 	"github.com/ava-labs/libevm/libevm/hookstest"
 	"github.com/ava-labs/libevm/params"
@@ -53,6 +54,31 @@ func TestChainConfigHooks_CheckConfigForkOrder(t *testing.T) {
 	require.Equal(t, err, c.CheckConfigForkOrder(), "CheckConfigForkOrder() with error-producing hook")
 }
 
+func TestChainConfigHooks_Validate(t *testing.T) {
+	err := errors.New("invalid extras")
+
+	c := new(params.ChainConfig)
+	require.NoError(t, c.CheckConfigForkOrder(), "CheckConfigForkOrder() with no hooks")
+
+	var got *params.ChainConfig
+	hooks := &hookstest.Stub{
+		ValidateFn: func(c *params.ChainConfig) error {
+			got = c
+			return err
+		},
+	}
+	hooks.Register(t).ChainConfig.Set(c, hooks)
+	require.Equal(t, err, c.CheckConfigForkOrder(), "CheckConfigForkOrder() with error-producing Validate() hook")
+	require.Same(t, c, got, "ChainConfig passed to Validate()")
+
+	hooks.CheckConfigForkOrderFn = func() error { return errors.New("fork order") }
+	hooks.ValidateFn = func(*params.ChainConfig) error {
+		t.Error("Validate() called after CheckConfigForkOrder() hook error")
+		return nil
+	}
+	require.Error(t, c.CheckConfigForkOrder(), "CheckConfigForkOrder() with error-producing fork-order hook")
+}
+
 func TestChainConfigHooks_CheckConfigCompatible(t *testing.T) {
 	rng := ethtest.NewPseudoRand(1234567890)
 	newcfg := &params.ChainConfig{
@@ -76,3 +102,98 @@ func TestChainConfigHooks_CheckConfigCompatible(t *testing.T) {
 	want := makeCompatErr(newcfg, new(big.Int).SetUint64(headNumber), headTimestamp)
 	require.Equal(t, want, c.CheckCompatible(newcfg, headNumber, headTimestamp), "CheckCompatible() with error-producing hook")
 }
+
+func TestSystemAddresses(t *testing.T) {
+	feeCollector := common.Address{'f', 'e', 'e'}
+	table := params.SystemAddresses{
+		"feeCollector": {Address: feeCollector},
+	}
+
+	c := new(params.ChainConfig)
+	_, ok := c.SystemAddress("feeCollector")
+	require.False(t, ok, "SystemAddress() with no hooks")
+
+	hooks := &hookstest.Stub{
+		SystemAddressesFn: func() params.SystemAddresses { return table },
+	}
+	extras := hooks.Register(t)
+	extras.ChainConfig.Set(c, hooks)
+	require.NoError(t, c.CheckConfigForkOrder(), "CheckConfigForkOrder() with valid system addresses")
+
+	got, ok := c.SystemAddress("feeCollector")
+	require.True(t, ok, "ChainConfig.SystemAddress() ok")
+	require.Equal(t, feeCollector, got, "ChainConfig.SystemAddress()")
+	_, ok = c.SystemAddress("bridge")
+	require.False(t, ok, "ChainConfig.SystemAddress() of unknown name")
+
+	rules := c.Rules(big.NewInt(0), false, 0)
+	got, ok = rules.SystemAddress("feeCollector")
+	require.True(t, ok, "Rules.SystemAddress() ok")
+	require.Equal(t, feeCollector, got, "Rules.SystemAddress()")
+
+	precompileAddr := common.BytesToAddress([]byte{0x02})
+	table["admin"] = params.SystemAddressEntry{Address: precompileAddr}
+	require.ErrorContains(t, c.CheckConfigForkOrder(), `"admin"`, "CheckConfigForkOrder() with system address in precompile range")
+	table["admin"] = params.SystemAddressEntry{Address: precompileAddr, AllowPrecompileRange: true}
+	require.NoError(t, c.CheckConfigForkOrder(), "CheckConfigForkOrder() with explicitly allowed precompile-range address")
+}
+
+func TestRulesSystemAddressFromChainConfig(t *testing.T) {
+	var (
+		feeCollector = common.Address{'f', 'e', 'e'}
+		bridge       = common.Address{'b', 'r', 'i', 'd', 'g', 'e'}
+		override     = common.Address{'o', 'v', 'e', 'r'}
+	)
+	config := &hookstest.Stub{
+		SystemAddressesFn: func() params.SystemAddresses {
+			return params.SystemAddresses{
+				"feeCollector": {Address: feeCollector},
+				"bridge":       {Address: bridge},
+			}
+		},
+	}
+	rules := &hookstest.Stub{
+		SystemAddressesFn: func() params.SystemAddresses {
+			return params.SystemAddresses{
+				"bridge": {Address: override},
+			}
+		},
+	}
+	extras := hookstest.Register(t, params.Extras[*hookstest.Stub, *hookstest.Stub]{
+		NewRules: func(*params.ChainConfig, *params.Rules, *hookstest.Stub, *big.Int, bool, uint64) *hookstest.Stub {
+			return rules
+		},
+	})
+	c := new(params.ChainConfig)
+	extras.ChainConfig.Set(c, config)
+	r := c.Rules(big.NewInt(0), false, 0)
+
+	for _, tt := range []struct {
+		name   string
+		want   common.Address
+		wantOK bool
+	}{
+		{"feeCollector", feeCollector, true}, // config only
+		{"bridge", override, true},
+		{"unknown", common.Address{}, false},
+	} {
+		got, ok := r.SystemAddress(tt.name)
+		require.Equalf(t, tt.wantOK, ok, "Rules.SystemAddress(%q) ok", tt.name)
+		require.Equalf(t, tt.want, got, "Rules.SystemAddress(%q)", tt.name)
+	}
+}
+
+func TestIsUpstreamPrecompileRange(t *testing.T) {
+	for _, tt := range []struct {
+		addr common.Address
+		want bool
+	}{
+		{common.Address{}, false},
+		{common.BytesToAddress([]byte{0x01}), true},
+		{common.BytesToAddress([]byte{0xff}), true},
+		{common.BytesToAddress([]byte{0x01, 0x00}), false},
+		{common.Address{0x01}, false},
+	} {
+		require.Equalf(t, tt.want, params.IsUpstreamPrecompileRange(tt.addr), "IsUpstreamPrecompileRange(%v)", tt.addr)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+// A SystemAddressEntry is a named address with chain-specific meaning, such as
+// a fee collector or bridge. It is unrelated to the [SystemAddress] used by
+// EIP-4788.
+type SystemAddressEntry struct {
+	Address common.Address `json:"address"`
+	// AllowPrecompileRange permits Address to be in the range reserved by
+	// upstream for precompiles; see [IsUpstreamPrecompileRange].
+	AllowPrecompileRange bool `json:"allowPrecompileRange,omitempty"`
+}
+
+// SystemAddresses is a table of [SystemAddressEntry] values, keyed by name.
+type SystemAddresses map[string]SystemAddressEntry
+
+// A SystemAddresser MAY be implemented by a type registered with
+// [RegisterExtras] for [ChainConfig] and/or [Rules] payloads, in which case
+// [ChainConfig.SystemAddress] and [Rules.SystemAddress] look up addresses in
+// the returned tables. A [ChainConfig] table is checked by
+// [ChainConfig.CheckConfigForkOrder]; see [SystemAddresses.Validate].
+type SystemAddresser interface {
+	SystemAddresses() SystemAddresses
+}
+
+// SystemAddress returns the named address from the [SystemAddresser]
+// registered for the [ChainConfig], and true, or false if there is no such
+// address.
+func (c *ChainConfig) SystemAddress(name string) (common.Address, bool) {
+	return systemAddress(c.Hooks(), name)
+}
+
+// SystemAddress returns the named address, and true, or false if there is no
+// such address. An entry in the table of the [SystemAddresser] registered for
+// the [Rules] takes precedence over one in that of the [ChainConfig] from which
+// the Rules were constructed.
+func (r *Rules) SystemAddress(name string) (common.Address, bool) {
+	if a, ok := systemAddress(r.Hooks(), name); ok {
+		return a, true
+	}
+	if r.config == nil {
+		return common.Address{}, false
+	}
+	return r.config.SystemAddress(name)
+}
+
+func systemAddress(hooks any, name string) (common.Address, bool) {
+	s, ok := hooks.(SystemAddresser)
+	if !ok {
+		return common.Address{}, false
+	}
+	a, ok := s.SystemAddresses()[name]
+	return a.Address, ok
+}
+
+// IsUpstreamPrecompileRange reports whether the address is in the range
+// [0x01, 0xff], which upstream geth reserves for precompiles.
+func IsUpstreamPrecompileRange(addr common.Address) bool {
+	for _, b := range addr[:common.AddressLength-1] {
+		if b != 0 {
+			return false
+		}
+	}
+	return addr[common.AddressLength-1] != 0
+}
+
+// Validate returns an error if any entry has an empty name or, unless it sets
+// AllowPrecompileRange, has an address in the upstream precompile range.
+func (s SystemAddresses) Validate() error {
+	names := make([]string, 0, len(s))
+	for n := range s {
+		names = append(names, n)
+	}
+	slices.Sort(names) // deterministic errors
+
+	for _, n := range names {
+		a := s[n]
+		if n == "" {
+			return fmt.Errorf("system address %v with empty name", a.Address)
+		}
+		if IsUpstreamPrecompileRange(a.Address) && !a.AllowPrecompileRange {
+			return fmt.Errorf("system address %q (%v) in upstream precompile range", n, a.Address)
+		}
+	}
+	return nil
+}
+
+func (c *ChainConfig) validateSystemAddresses() error {
+	if s, ok := c.Hooks().(SystemAddresser); ok {
+		return s.SystemAddresses().Validate()
+	}
+	return nil
+}
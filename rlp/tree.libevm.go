@@ -0,0 +1,127 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// An ItemNode is a single item in a parsed RLP document. If Kind is [List]
+// then the item's Children are populated, otherwise its Content holds the
+// (already unwrapped) bytes of the [String] or [Byte].
+//
+// ItemNodes MAY be modified, added, or removed before re-encoding a tree with
+// [ItemNode.EncodeRLP] or [EncodeTree]. Length prefixes are recomputed upon
+// encoding so need not be accounted for.
+type ItemNode struct {
+	Kind     Kind
+	Content  []byte
+	Children []*ItemNode
+}
+
+// ParseTree parses RLP-encoded `b`, which MUST contain exactly one item, into
+// a tree of [ItemNode]s. Content slices reference `b`, which MUST NOT be
+// modified while the tree is in use.
+func ParseTree(b []byte) (*ItemNode, error) {
+	n, rest, err := parseItem(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, ErrMoreThanOneValue
+	}
+	return n, nil
+}
+
+func parseItem(b []byte) (*ItemNode, []byte, error) {
+	kind, content, rest, err := Split(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := &ItemNode{Kind: kind}
+	if kind != List {
+		n.Content = content
+		return n, rest, nil
+	}
+
+	n.Children = []*ItemNode{}
+	for len(content) > 0 {
+		var child *ItemNode
+		child, content, err = parseItem(content)
+		if err != nil {
+			return nil, nil, err
+		}
+		n.Children = append(n.Children, child)
+	}
+	return n, rest, nil
+}
+
+// EncodeTree returns the RLP encoding of the tree rooted at `n`.
+func EncodeTree(n *ItemNode) ([]byte, error) {
+	// Not using [EncodeToBytes] as it would encode a nil pointer as an empty
+	// item instead of returning an error.
+	buf := getEncBuffer()
+	defer encBufferPool.Put(buf)
+
+	if err := n.encode(EncoderBuffer{buf: buf}); err != nil {
+		return nil, err
+	}
+	return buf.makeBytes(), nil
+}
+
+var errNilItemNode = errors.New("nil *rlp.ItemNode")
+
+// EncodeRLP implements the [Encoder] interface.
+func (n *ItemNode) EncodeRLP(w io.Writer) error {
+	buf := NewEncoderBuffer(w)
+	if err := n.encode(buf); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+func (n *ItemNode) encode(buf EncoderBuffer) error {
+	if n == nil {
+		return errNilItemNode
+	}
+
+	switch n.Kind {
+	case Byte:
+		if len(n.Content) != 1 {
+			return fmt.Errorf("%T of kind %v with %d bytes of content", n, n.Kind, len(n.Content))
+		}
+		fallthrough
+	case String:
+		buf.WriteBytes(n.Content)
+		return nil
+
+	case List:
+		return buf.InList(func() error {
+			for _, c := range n.Children {
+				if err := c.encode(buf); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	default:
+		return fmt.Errorf("%T of unknown kind %v", n, n.Kind)
+	}
+}
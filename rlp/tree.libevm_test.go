@@ -0,0 +1,142 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rlp
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTreeRoundTrip(t *testing.T) {
+	type inner struct {
+		A uint64
+		B []byte
+	}
+	type outer struct {
+		X     string
+		Empty []uint
+		In    inner
+		Ys    []uint
+	}
+
+	tests := []any{
+		uint(0),
+		uint(42),
+		uint(1 << 40),
+		"",
+		"hello world",
+		make([]byte, 100),
+		[]uint{},
+		outer{
+			X:     "x",
+			Empty: []uint{},
+			In:    inner{A: 1, B: make([]byte, 60)},
+			Ys:    []uint{1, 2, 300},
+		},
+	}
+
+	for _, val := range tests {
+		enc, err := EncodeToBytes(val)
+		require.NoErrorf(t, err, "EncodeToBytes(%T)", val)
+
+		tree, err := ParseTree(enc)
+		require.NoErrorf(t, err, "ParseTree(EncodeToBytes(%T))", val)
+		got, err := EncodeTree(tree)
+		require.NoError(t, err, "EncodeTree(ParseTree(...))")
+		assert.Equalf(t, enc, got, "EncodeTree(ParseTree(EncodeToBytes(%T)))", val)
+	}
+}
+
+func TestModifyTree(t *testing.T) {
+	type before struct {
+		A uint
+		B []string
+	}
+	type after struct {
+		A uint
+		B []string
+		C string
+	}
+
+	enc, err := EncodeToBytes(before{A: 1, B: []string{"x", "y"}})
+	require.NoError(t, err, "EncodeToBytes(before)")
+	tree, err := ParseTree(enc)
+	require.NoError(t, err, "ParseTree()")
+
+	require.Len(t, tree.Children, 2)
+	list := tree.Children[1]
+	require.Equal(t, List, list.Kind)
+	list.Children = append(list.Children[:1], &ItemNode{Kind: String, Content: []byte("z")})
+	tree.Children = append(tree.Children, &ItemNode{Kind: String, Content: make([]byte, 64)})
+
+	want, err := EncodeToBytes(after{A: 1, B: []string{"x", "z"}, C: string(make([]byte, 64))})
+	require.NoError(t, err, "EncodeToBytes(after)")
+	got, err := EncodeTree(tree)
+	require.NoError(t, err, "EncodeTree()")
+	assert.Equal(t, want, got)
+}
+
+func TestParseTreeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rlp  []byte
+		want error
+	}{
+		{
+			name: "empty",
+			rlp:  nil,
+			want: io.ErrUnexpectedEOF,
+		},
+		{
+			name: "trailing_data",
+			rlp:  []byte{0x01, 0x02},
+			want: ErrMoreThanOneValue,
+		},
+		{
+			name: "non_canonical_in_list",
+			rlp:  []byte{0xc2, 0x81, 0x01},
+			want: ErrCanonSize,
+		},
+		{
+			name: "list_content_too_short",
+			rlp:  []byte{0xc2, 0x82, 0x01},
+			want: ErrValueTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTree(tt.rlp)
+			require.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestEncodeTreeErrors(t *testing.T) {
+	for _, n := range []*ItemNode{
+		nil,
+		{Kind: Byte, Content: []byte{1, 2}},
+		{Kind: Kind(-1)},
+		{Kind: List, Children: []*ItemNode{nil}},
+	} {
+		_, err := EncodeTree(n)
+		assert.Errorf(t, err, "EncodeTree(%+v)", n)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package rpc
+
+// CloseConnection closes the connection over which notifications are sent,
+// after which [Notifier.Closed] is signalled. It is intended for disconnecting
+// clients that aren't keeping up with notifications. CloseConnection is a no-op
+// if the underlying connection can't be closed.
+func (n *Notifier) CloseConnection() {
+	if c, ok := n.h.conn.(interface{ close() }); ok {
+		c.close()
+	}
+}
@@ -153,6 +153,9 @@ func (db *Database) Update(root common.Hash, parent common.Hash, block uint64, n
 	if db.preimages != nil {
 		db.preimages.commit(false)
 	}
+	if ok, err := db.updateOrdered(root, parent, block, nodes, states, opts...); ok {
+		return err
+	}
 	return db.backend.Update(root, parent, block, nodes, states, opts...)
 }
 
@@ -17,9 +17,15 @@
 package triedb
 
 import (
+	"maps"
+	"slices"
+	"strings"
+
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/stateconf"
 	"github.com/ava-labs/libevm/log"
+	"github.com/ava-labs/libevm/trie/trienode"
 	"github.com/ava-labs/libevm/trie/triestate"
 	"github.com/ava-labs/libevm/triedb/database"
 	"github.com/ava-labs/libevm/triedb/hashdb"
@@ -74,6 +80,111 @@ func (db *Database) overrideBackend(diskdb ethdb.Database, config *Config) bool
 	return true
 }
 
+// An OrderedUpdater is an optional interface that MAY be implemented by a
+// [DBOverride]. If implemented, [Database.Update] calls UpdateOrdered instead
+// of the backend's Update method, allowing the backend to consume trie-node
+// changes in a deterministic order without having to sort them itself.
+type OrderedUpdater interface {
+	UpdateOrdered(*UpdateBatch, ...stateconf.TrieDBUpdateOption) error
+}
+
+// An UpdateBatch is the set of arguments passed to [Database.Update], with
+// trie-node changes ordered deterministically.
+type UpdateBatch struct {
+	Root, Parent common.Hash
+	Block        uint64
+	// Nodes are sorted by owner and then by path, both ascending. As the owner
+	// of the account trie is the zero hash, its nodes are always first.
+	Nodes []NodeUpdate
+	// States is carried as received; see [triestate.Set].
+	States *triestate.Set
+}
+
+// A NodeUpdate is a single trie-node change in an [UpdateBatch]. A deleted
+// node is represented by a [trienode.Node] for which IsDeleted() is true.
+type NodeUpdate struct {
+	Owner common.Hash // zero for the account trie
+	Path  string
+	Node  *trienode.Node
+}
+
+// NewUpdateBatch returns an [UpdateBatch] with all nodes in `nodes` sorted as
+// described on the type. A nil `nodes` results in an empty batch.
+func NewUpdateBatch(root, parent common.Hash, block uint64, nodes *trienode.MergedNodeSet, states *triestate.Set) *UpdateBatch {
+	b := &UpdateBatch{
+		Root:   root,
+		Parent: parent,
+		Block:  block,
+		States: states,
+	}
+	if nodes == nil {
+		return b
+	}
+	owners := slices.SortedFunc(maps.Keys(nodes.Sets), func(a, b common.Hash) int {
+		return a.Cmp(b)
+	})
+	for _, owner := range owners {
+		set := nodes.Sets[owner]
+		for _, path := range slices.SortedFunc(maps.Keys(set.Nodes), strings.Compare) {
+			b.Nodes = append(b.Nodes, NodeUpdate{
+				Owner: owner,
+				Path:  path,
+				Node:  set.Nodes[path],
+			})
+		}
+	}
+	return b
+}
+
+// updateOrdered calls [OrderedUpdater.UpdateOrdered] on the backend if it
+// implements said interface, and returns true to signal that this occurred.
+func (db *Database) updateOrdered(root, parent common.Hash, block uint64, nodes *trienode.MergedNodeSet, states *triestate.Set, opts ...stateconf.TrieDBUpdateOption) (bool, error) {
+	u, ok := db.backend.(OrderedUpdater)
+	if !ok {
+		return false, nil
+	}
+	return true, u.UpdateOrdered(NewUpdateBatch(root, parent, block, nodes, states), opts...)
+}
+
+// An OptionedReaderProvider is an optional interface that MAY be implemented
+// by a [DBOverride]. If implemented, [Database.ReaderWithOptions] calls
+// ReaderWithOptions instead of the backend's Reader method whenever options
+// are provided, allowing the backend to distinguish between reasons for
+// opening a trie (e.g. speculative vs canonical reads).
+type OptionedReaderProvider interface {
+	ReaderWithOptions(common.Hash, ...stateconf.StateDBTrieOption) (database.Reader, error)
+}
+
+// ReaderWithOptions is equivalent to [Database.Reader] except that, if the
+// backend implements [OptionedReaderProvider] and at least one option is
+// provided, the options are propagated to the backend.
+func (db *Database) ReaderWithOptions(root common.Hash, opts ...stateconf.StateDBTrieOption) (database.Reader, error) {
+	if p, ok := db.backend.(OptionedReaderProvider); ok && len(opts) > 0 {
+		return p.ReaderWithOptions(root, opts...)
+	}
+	return db.Reader(root)
+}
+
+// WithReaderOptions returns a [database.Database], suitable for opening tries,
+// that is identical to `db` except that its Reader method is equivalent to
+// [Database.ReaderWithOptions] with the provided options. If no options are
+// provided then `db` itself is returned.
+func (db *Database) WithReaderOptions(opts ...stateconf.StateDBTrieOption) database.Database {
+	if len(opts) == 0 {
+		return db
+	}
+	return &optionedReaderDB{db, opts}
+}
+
+type optionedReaderDB struct {
+	*Database
+	opts []stateconf.StateDBTrieOption
+}
+
+func (db *optionedReaderDB) Reader(root common.Hash) (database.Reader, error) {
+	return db.ReaderWithOptions(root, db.opts...)
+}
+
 var (
 	// If either of these break then the respective interface SHOULD be updated.
 	_ HashDB = (*hashdb.Database)(nil)
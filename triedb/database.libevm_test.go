@@ -19,10 +19,14 @@ package triedb
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/stateconf"
+	"github.com/ava-labs/libevm/trie/trienode"
+	"github.com/ava-labs/libevm/trie/triestate"
 	"github.com/ava-labs/libevm/triedb/database"
 )
 
@@ -52,3 +56,61 @@ type reader struct {
 func (override) Reader(common.Hash) (database.Reader, error) {
 	return reader{}, nil
 }
+
+type orderedOverride struct {
+	override
+	got *UpdateBatch
+}
+
+func (o *orderedOverride) UpdateOrdered(b *UpdateBatch, _ ...stateconf.TrieDBUpdateOption) error {
+	o.got = b
+	return nil
+}
+
+func TestOrderedUpdater(t *testing.T) {
+	backend := new(orderedOverride)
+	db := NewDatabase(nil, &Config{
+		DBOverride: func(ethdb.Database) DBOverride {
+			return backend
+		},
+	})
+
+	node := func(b byte) *trienode.Node {
+		return trienode.New(common.Hash{b}, []byte{b})
+	}
+	nodes := trienode.NewMergedNodeSet()
+	for _, owner := range []common.Hash{{2}, {}, {1}} {
+		set := trienode.NewNodeSet(owner)
+		for _, path := range []string{"\x01\x02", "", "\x01", "\x00\x0f"} {
+			set.AddNode([]byte(path), node(owner[0]))
+		}
+		require.NoError(t, nodes.Merge(set), "%T.Merge()", nodes)
+	}
+	states := triestate.New(nil, nil, nil)
+
+	var (
+		root   = common.Hash{'r'}
+		parent = common.Hash{'p'}
+	)
+	const block = 42
+	require.NoErrorf(t, db.Update(root, parent, block, nodes, states), "%T.Update()", db)
+	require.NotNilf(t, backend.got, "%T.UpdateOrdered() called by %T.Update()", backend, db)
+
+	var want []NodeUpdate
+	for _, owner := range []common.Hash{{}, {1}, {2}} {
+		for _, path := range []string{"", "\x00\x0f", "\x01", "\x01\x02"} {
+			want = append(want, NodeUpdate{
+				Owner: owner,
+				Path:  path,
+				Node:  node(owner[0]),
+			})
+		}
+	}
+	assert.Equal(t, &UpdateBatch{
+		Root:   root,
+		Parent: parent,
+		Block:  block,
+		Nodes:  want,
+		States: states,
+	}, backend.got)
+}
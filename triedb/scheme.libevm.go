@@ -0,0 +1,75 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package triedb
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/libevm/testonly"
+)
+
+var registeredSchemes = make(map[string]DBConstructor)
+
+// A SchemeOption configures a scheme registered with [RegisterScheme].
+type SchemeOption = options.Option[schemeConfig]
+
+type schemeConfig struct {
+	isStored func(ethdb.Reader) bool
+}
+
+// WithStoredSchemeDetector returns a [SchemeOption] that allows
+// [rawdb.ReadStateScheme] to detect persistent state of the registered scheme.
+// See [rawdb.RegisterStateScheme] for details.
+func WithStoredSchemeDetector(fn func(ethdb.Reader) bool) SchemeOption {
+	return options.Func[schemeConfig](func(c *schemeConfig) {
+		c.isStored = fn
+	})
+}
+
+// RegisterScheme registers a named backend-database implementation, allowing it
+// to be selected declaratively (e.g. by a node's configured state scheme)
+// instead of having to set [Config.DBOverride] in code. The name is also
+// registered with [rawdb.RegisterStateScheme] and the same constraints apply.
+func RegisterScheme(name string, ctor DBConstructor, opts ...SchemeOption) {
+	if ctor == nil {
+		panic(fmt.Sprintf("nil %T for state scheme %q", ctor, name))
+	}
+	cfg := options.ApplyTo(&schemeConfig{}, opts...)
+	rawdb.RegisterStateScheme(name, cfg.isStored)
+	registeredSchemes[name] = ctor
+}
+
+// RegisteredScheme returns the constructor passed to [RegisterScheme] for the
+// named scheme, and true, or false if no such scheme was registered.
+func RegisteredScheme(name string) (DBConstructor, bool) {
+	c, ok := registeredSchemes[name]
+	return c, ok
+}
+
+// TestOnlyClearRegisteredSchemes clears all schemes previously registered with
+// [RegisterScheme], including their registration with
+// [rawdb.RegisterStateScheme]. It panics if called from a non-testing call
+// stack.
+func TestOnlyClearRegisteredSchemes() {
+	testonly.OrPanic(func() {
+		clear(registeredSchemes)
+		rawdb.TestOnlyClearRegisteredStateSchemes()
+	})
+}
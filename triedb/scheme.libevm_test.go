@@ -0,0 +1,78 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package triedb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/ethdb"
+)
+
+func TestRegisterScheme(t *testing.T) {
+	TestOnlyClearRegisteredSchemes()
+	t.Cleanup(TestOnlyClearRegisteredSchemes)
+
+	const name = "custom"
+	marker := []byte("custom-scheme-marker")
+	RegisterScheme(
+		name,
+		func(ethdb.Database) DBOverride { return override{} },
+		WithStoredSchemeDetector(func(db ethdb.Reader) bool {
+			ok, _ := db.Has(marker)
+			return ok
+		}),
+	)
+
+	t.Run("duplicate_and_reserved", func(t *testing.T) {
+		ctor := func(ethdb.Database) DBOverride { return nil }
+		for _, n := range []string{name, rawdb.HashScheme, rawdb.PathScheme, ""} {
+			assert.Panicsf(t, func() { RegisterScheme(n, ctor) }, "RegisterScheme(%q)", n)
+		}
+	})
+
+	ctor, ok := RegisteredScheme(name)
+	require.True(t, ok, "RegisteredScheme(%q)", name)
+	assert.True(t, rawdb.IsRegisteredStateScheme(name), "rawdb.IsRegisteredStateScheme(%q)", name)
+	_, ok = RegisteredScheme("unknown")
+	assert.False(t, ok, "RegisteredScheme([unregistered])")
+
+	db := NewDatabase(nil, &Config{DBOverride: ctor})
+	got, err := db.Reader(common.Hash{})
+	require.NoError(t, err)
+	assert.IsType(t, reader{}, got, "%T.Reader() with registered scheme", db)
+
+	disk := rawdb.NewMemoryDatabase()
+	assert.Empty(t, rawdb.ReadStateScheme(disk), "rawdb.ReadStateScheme() before marker written")
+	parsed, err := rawdb.ParseStateScheme(name, disk)
+	require.NoError(t, err, "rawdb.ParseStateScheme(%q, [empty db])", name)
+	assert.Equal(t, name, parsed)
+
+	require.NoError(t, disk.Put(marker, []byte{1}))
+	assert.Equal(t, name, rawdb.ReadStateScheme(disk), "rawdb.ReadStateScheme() after marker written")
+
+	parsed, err = rawdb.ParseStateScheme("", disk)
+	require.NoError(t, err, "rawdb.ParseStateScheme(\"\", [db with marker])")
+	assert.Equal(t, name, parsed)
+
+	_, err = rawdb.ParseStateScheme(rawdb.HashScheme, disk)
+	assert.Error(t, err, "rawdb.ParseStateScheme(%q, [db with %q marker])", rawdb.HashScheme, name)
+}